@@ -19,6 +19,9 @@
 package polaris
 
 import (
+	"context"
+	"time"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -53,6 +56,18 @@ type WatchAllInstancesRequest api.WatchAllInstancesRequest
 // WatchAllServicesRequest is the request to watch services
 type WatchAllServicesRequest api.WatchAllServicesRequest
 
+// WaitForPeersRequest is the request to wait for minimum healthy peers
+type WaitForPeersRequest api.WaitForPeersRequest
+
+// QueryInstancesRequest is the request to query instances from server directly
+type QueryInstancesRequest api.QueryInstancesRequest
+
+// ValidateContractRequest is the request to validate an outgoing request against the callee's contract
+type ValidateContractRequest api.ValidateContractRequest
+
+// ReportShadowComparisonRequest is the request to report the comparison of primary and shadow responses
+type ReportShadowComparisonRequest api.ReportShadowComparisonRequest
+
 // ConsumerAPI 主调端API方法.
 type ConsumerAPI interface {
 	api.SDKOwner
@@ -62,6 +77,12 @@ type ConsumerAPI interface {
 	GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error)
 	// GetAllInstances 同步获取完整的服务列表
 	GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// GetOneInstanceWithContext 使用调用方上下文同步获取单个服务
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 使用调用方上下文同步获取可用的服务列表
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 使用调用方上下文同步获取完整的服务列表
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
 	// GetRouteRule 同步获取服务路由规则
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
@@ -76,6 +97,24 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件
+	ExportSnapshot(path string) error
+	// ImportSnapshot 从快照文件导入服务、实例及规则
+	ImportSnapshot(path string) error
+	// WaitForPeers 等待服务的健康实例数达到下限
+	WaitForPeers(ctx context.Context, req *WaitForPeersRequest) error
+	// GetInstancesDiff 获取服务实例自 sinceRevision 以来的增量变更
+	GetInstancesDiff(svcKey model.ServiceKey, sinceRevision string) (*model.InstancesDiffResponse, error)
+	// QueryInstances 直接向服务端查询实例，支持过滤及分页
+	QueryInstances(req *QueryInstancesRequest) (*model.QueryInstancesResponse, error)
+	// ValidateContract 按照被调服务契约校验请求的方法及路径
+	ValidateContract(req *ValidateContractRequest) (*model.ContractValidationResult, error)
+	// ReportShadowComparison 上报影子请求与主请求应答的对比结果
+	ReportShadowComparison(req *ReportShadowComparisonRequest) error
+	// OverrideInstanceWeight 在本地覆盖实例参与负载均衡的权重，到期后自动失效
+	OverrideInstanceWeight(svcKey model.ServiceKey, instanceID string, weight int, ttl time.Duration) error
+	// ClearInstanceWeightOverride 清除实例的本地权重覆盖
+	ClearInstanceWeightOverride(svcKey model.ServiceKey, instanceID string) error
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -89,6 +128,9 @@ type InstanceDeRegisterRequest api.InstanceDeRegisterRequest
 // InstanceHeartbeatRequest 实例心跳请求.
 type InstanceHeartbeatRequest api.InstanceHeartbeatRequest
 
+// ImportInstancesRequest 外部实例批量导入请求.
+type ImportInstancesRequest api.ImportInstancesRequest
+
 // ProviderAPI CL5服务端API的主接口.
 type ProviderAPI interface {
 	api.SDKOwner
@@ -106,6 +148,28 @@ type ProviderAPI interface {
 	// Heartbeat
 	// 心跳上报
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// RegisterInstanceWithContext
+	// 使用调用方上下文注册服务实例，注册成功后的后台心跳不受该上下文影响
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext
+	// 使用调用方上下文反注册服务实例
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext
+	// 使用调用方上下文上报心跳
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
+	// ImportInstances
+	// 将外部系统的实例全集同步到北极星，对同步标签下的实例进行对账
+	ImportInstances(req *ImportInstancesRequest) (*model.ImportInstancesResponse, error)
+	// EnterLameduck
+	// 进入lameduck状态，将自动心跳实例的TTL缩短为 provider.lameduckTTL，用于容器preStop钩子
+	EnterLameduck() error
+	// TrackRequest
+	// 记录一个开始处理的服务端请求，请求处理完成时调用返回的函数
+	TrackRequest() (done func())
+	// GracefulShutdown
+	// 隔离实例、等待在途请求处理完成后反注册实例，用于容器preStop钩子
+	GracefulShutdown(ctx context.Context) error
 	// Destroy
 	// 销毁API，销毁后无法再进行调用
 	Destroy()
@@ -145,12 +209,14 @@ type ConfigAPI interface {
 	GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error)
 	// FetchConfigFile 获取配置文件
 	FetchConfigFile(*GetConfigFileRequest) (model.ConfigFile, error)
+	// GetConfigFiles 批量获取并订阅同一分组下的多个配置文件，返回文件名到配置文件的映射
+	GetConfigFiles(namespace, fileGroup string, fileNames []string) (map[string]model.ConfigFile, error)
 	// CreateConfigFile create configuration file
-	CreateConfigFile(namespace, fileGroup, fileName, content string) error
+	CreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error
 	// UpdateConfigFile update configuration file
-	UpdateConfigFile(namespace, fileGroup, fileName, content string) error
+	UpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error
 	// PublishConfigFile publish configuration file
-	PublishConfigFile(namespace, fileGroup, fileName string) error
+	PublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error
 }
 
 // ConfigGroupAPI .
@@ -169,6 +235,10 @@ type CircuitBreakerAPI interface {
 	Check(model.Resource) (*model.CheckResult, error)
 	// Report
 	Report(*model.ResourceStat) error
+	// ReportExternalHealth 上报应用自行判定的实例健康状态
+	ReportExternalHealth(instanceKey model.InstanceKey, healthy bool, reason string) error
+	// ClearQuarantine 清除熔断实例隔离名单，不指定实例时清除全部
+	ClearQuarantine(instanceKeys ...model.InstanceKey) int
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(model.CustomerFunction, *api.RequestContext) model.DecoratorFunction
 	// MakeInvokeHandler
@@ -177,6 +247,12 @@ type CircuitBreakerAPI interface {
 	Destroy()
 }
 
+// DoRequest 聚合调用请求，一次调用完成限流、实例选择、熔断、结果上报以及重试降级
+type DoRequest api.DoRequest
+
+// DoFunc 聚合调用中的用户调用逻辑
+type DoFunc = api.DoFunc
+
 // RouterAPI routing api methods
 type RouterAPI interface {
 	api.SDKOwner
@@ -184,6 +260,11 @@ type RouterAPI interface {
 	ProcessRouters(*ProcessRoutersRequest) (*model.InstancesResponse, error)
 	// ProcessLoadBalance process load balancer to get the target instances
 	ProcessLoadBalance(*ProcessLoadBalanceRequest) (*model.OneInstanceResponse, error)
+	// SetSplit set the local traffic split of a service by version, it takes precedence over server rules
+	// and expires automatically, for canary analysis controllers to drive progressive delivery
+	SetSplit(*TrafficSplitRequest) error
+	// ClearSplit clear the local traffic split of a service
+	ClearSplit(namespace, service string) error
 }
 
 // ProcessRoutersRequest process routers to filter instances
@@ -210,3 +291,8 @@ func (r *ProcessRoutersRequest) convert() {
 type ProcessLoadBalanceRequest struct {
 	model.ProcessLoadBalanceRequest
 }
+
+// TrafficSplitRequest set the local traffic split of a service
+type TrafficSplitRequest struct {
+	model.TrafficSplitRequest
+}
@@ -25,10 +25,16 @@ import (
 // CircuitBreakerAPI .
 type CircuitBreakerAPI interface {
 	SDKOwner
-	// Check
+	// Check 检查资源是否可以调用，资源配置了舱壁时通过检查会占用 CheckResult.Permit 并发许可
 	Check(model.Resource) (*model.CheckResult, error)
-	// Report
+	// Report 上报调用结果，ResourceStat.Permit 携带 Check 返回的许可时一并归还
 	Report(*model.ResourceStat) error
+	// ReportExternalHealth 上报应用自行判定的实例健康状态（例如复制延迟过大），与调用统计、主动探测一起参与熔断判定，
+	// 上报不健康后实例会被熔断，直到再次上报健康
+	ReportExternalHealth(instanceKey model.InstanceKey, healthy bool, reason string) error
+	// ClearQuarantine 清除熔断实例隔离名单（consumer.circuitBreaker.quarantineTTL），被隔离的实例立即恢复，
+	// 不指定实例时清除全部，返回清除的实例数
+	ClearQuarantine(instanceKeys ...model.InstanceKey) int
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(model.CustomerFunction, *RequestContext) model.DecoratorFunction
 	// MakeInvokeHandler
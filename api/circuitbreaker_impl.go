@@ -39,6 +39,24 @@ func (c *circuitBreakerAPI) Report(reportStat *model.ResourceStat) error {
 	return c.context.GetEngine().Report(reportStat)
 }
 
+// ReportExternalHealth 上报应用自行判定的实例健康状态
+func (c *circuitBreakerAPI) ReportExternalHealth(instanceKey model.InstanceKey, healthy bool, reason string) error {
+	report := &model.ExternalHealthReport{
+		InstanceKey: instanceKey,
+		Healthy:     healthy,
+		Reason:      reason,
+	}
+	if err := report.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().ReportExternalHealth(report)
+}
+
+// ClearQuarantine 清除熔断实例隔离名单
+func (c *circuitBreakerAPI) ClearQuarantine(instanceKeys ...model.InstanceKey) int {
+	return c.context.GetEngine().ClearQuarantine(instanceKeys...)
+}
+
 func (c *circuitBreakerAPI) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *RequestContext) model.DecoratorFunction {
 	return c.context.GetEngine().MakeFunctionDecorator(f, &reqCtx.RequestContext)
 }
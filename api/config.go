@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -82,6 +83,39 @@ type SDKContext interface {
 	// GetValueContext
 	// @brief 获取值上下文
 	GetValueContext() model.ValueContext
+
+	// WaitForReady
+	// @brief 等待预热清单中的服务加载到缓存，超时或者加载失败时返回错误
+	WaitForReady(timeout time.Duration) error
+
+	// AddPreLoadBalanceHook
+	// @brief 添加负载均衡前执行的实例过滤钩子，对本上下文的所有GetOneInstance及ProcessLoadBalance生效
+	AddPreLoadBalanceHook(hook model.PreLoadBalanceHook)
+
+	// AddPostLoadBalanceHook
+	// @brief 添加负载均衡后执行的实例选择钩子，可替换选中的实例
+	AddPostLoadBalanceHook(hook model.PostLoadBalanceHook)
+
+	// AddInstancesResultHook
+	// @brief 添加GetInstances结果的后处理钩子，在路由之后、返回应答之前执行，可对实例排序或附加信息
+	AddInstancesResultHook(hook model.InstancesResultHook)
+
+	// AddAddressTranslator
+	// @brief 添加实例地址转换器，在实例返回给调用方之前将注册地址转换为本进程可达的地址，先于配置的转换规则执行
+	AddAddressTranslator(translator model.AddressTranslator)
+
+	// AddServiceNameMapper
+	// @brief 添加服务名映射器，在服务发现之前将旧的服务名映射为实际的服务名，应答中还原为旧的服务名，先于配置的映射规则执行
+	AddServiceNameMapper(mapper model.ServiceNameMapper)
+
+	// Drain
+	// @brief 下线前有序排空：停止心跳并反注册自动心跳的实例、停止分配配额、刷新缓存的统计数据，最后销毁上下文，
+	// ctx 到期时跳过剩余的反注册。配置中心使用长轮询，没有待确认的消息需要刷新，与服务端的流在销毁上下文时关闭
+	Drain(ctx context.Context) error
+	// RecentCalls
+	// @brief 获取内存中保留的最近API调用记录（接口、服务、返回码、耗时），按时间先后排列，
+	// 用于故障排查，记录数由 global.api.recentCallsSize 控制
+	RecentCalls() []model.APICallRecord
 }
 
 // SDKOwner 获取SDK上下文接口
@@ -156,6 +190,51 @@ func (s *sdkContext) GetValueContext() model.ValueContext {
 	return s.valueContext
 }
 
+// WaitForReady 等待缓存预热完成
+func (s *sdkContext) WaitForReady(timeout time.Duration) error {
+	return s.engine.WaitForReady(timeout)
+}
+
+// AddPreLoadBalanceHook 添加负载均衡前执行的实例过滤钩子
+func (s *sdkContext) AddPreLoadBalanceHook(hook model.PreLoadBalanceHook) {
+	s.engine.AddPreLoadBalanceHook(hook)
+}
+
+// AddPostLoadBalanceHook 添加负载均衡后执行的实例选择钩子
+func (s *sdkContext) AddPostLoadBalanceHook(hook model.PostLoadBalanceHook) {
+	s.engine.AddPostLoadBalanceHook(hook)
+}
+
+// AddInstancesResultHook 添加GetInstances结果的后处理钩子
+func (s *sdkContext) AddInstancesResultHook(hook model.InstancesResultHook) {
+	s.engine.AddInstancesResultHook(hook)
+}
+
+// AddAddressTranslator 添加实例地址转换器
+func (s *sdkContext) AddAddressTranslator(translator model.AddressTranslator) {
+	s.engine.AddAddressTranslator(translator)
+}
+
+// AddServiceNameMapper 添加服务名映射器
+func (s *sdkContext) AddServiceNameMapper(mapper model.ServiceNameMapper) {
+	s.engine.AddServiceNameMapper(mapper)
+}
+
+// Drain 下线前有序排空后销毁上下文
+func (s *sdkContext) Drain(ctx context.Context) error {
+	if s.IsDestroyed() {
+		return nil
+	}
+	err := s.engine.Drain(ctx)
+	s.Destroy()
+	return err
+}
+
+// RecentCalls 获取最近的API调用记录
+func (s *sdkContext) RecentCalls() []model.APICallRecord {
+	return s.engine.RecentCalls()
+}
+
 // InitContextByFile 通过配置文件新建服务消费者配置
 func InitContextByFile(path string) (SDKContext, error) {
 	if !model.IsFile(path) {
@@ -261,6 +340,7 @@ func InitContextByConfig(cfg config.Configuration) (SDKContext, error) {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to verify input config")
 	}
 	initSelfIP(cfg)
+	initSidecar(cfg)
 	token := &model.SDKToken{
 		IP:       cfg.GetGlobal().GetAPI().GetBindIP(),
 		PID:      int32(os.Getpid()),
@@ -329,13 +409,14 @@ func initSelfIP(cfg config.Configuration) {
 		return
 	}
 
-	address := cfg.GetGlobal().GetServerConnector().GetAddresses()
-	if len(address) == 0 {
+	endpoints := config.MergeServerEndpoints(cfg.GetGlobal().GetServerConnector().GetAddresses(),
+		cfg.GetGlobal().GetServerConnector().GetEndpoints())
+	if len(endpoints) == 0 {
 		return
 	}
 
 	timeout := cfg.GetGlobal().GetServerConnector().GetConnectTimeout()
-	conn, _ := net.DialTimeout("tcp", address[0], timeout)
+	conn, _ := net.DialTimeout("tcp", endpoints[0].Address, timeout)
 	if conn != nil {
 		localAddr := conn.LocalAddr().String()
 		colonIdx := strings.LastIndex(localAddr, ":")
@@ -347,6 +428,38 @@ func initSelfIP(cfg config.Configuration) {
 	}
 }
 
+// initSidecar 检测本机polaris-sidecar，检测成功时服务发现及配置拉取优先经由sidecar进行
+func initSidecar(cfg config.Configuration) {
+	sidecarCfg := cfg.GetGlobal().GetSidecar()
+	mode := sidecarCfg.GetMode()
+	if mode == config.SidecarModeNever {
+		return
+	}
+	fallback := mode == config.SidecarModeAuto
+	if fallback && !detectSidecar(sidecarCfg.GetAddress(), sidecarCfg.GetDetectTimeout()) {
+		log.GetBaseLogger().Infof("sidecar %s not detected, connect to remote servers directly",
+			sidecarCfg.GetAddress())
+		return
+	}
+	config.DelegateToSidecar(cfg.GetGlobal().GetServerConnector(), sidecarCfg.GetAddress(), fallback)
+	if cfg.GetConfigFile().IsEnable() {
+		config.DelegateToSidecar(cfg.GetConfigFile().GetConfigConnectorConfig(),
+			sidecarCfg.GetConfigAddress(), fallback)
+	}
+	log.GetBaseLogger().Infof("delegate discovery to sidecar %s, config to sidecar %s, mode %s",
+		sidecarCfg.GetAddress(), sidecarCfg.GetConfigAddress(), mode)
+}
+
+// detectSidecar 探测本机sidecar是否在监听
+func detectSidecar(address string, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
 func initSelfLabels(cfg config.Configuration, sdkToken *model.SDKToken) {
 	clientCfg := cfg.GetGlobal().GetClient().(*config.ClientConfigImpl)
 	if clientCfg.GetId() == "" {
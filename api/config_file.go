@@ -35,12 +35,15 @@ type ConfigFileAPI interface {
 	GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error)
 	// FetchConfigFile 获取配置文件
 	FetchConfigFile(*GetConfigFileRequest) (model.ConfigFile, error)
+	// GetConfigFiles 批量获取并订阅同一分组下的多个配置文件，返回文件名到配置文件的映射，
+	// 部分文件获取失败时返回获取成功的文件以及汇总的错误
+	GetConfigFiles(namespace, fileGroup string, fileNames []string) (map[string]model.ConfigFile, error)
 	// CreateConfigFile 创建配置文件
-	CreateConfigFile(namespace, fileGroup, fileName, content string) error
+	CreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error
 	// UpdateConfigFile 更新配置文件
-	UpdateConfigFile(namespace, fileGroup, fileName, content string) error
+	UpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error
 	// PublishConfigFile 发布配置文件
-	PublishConfigFile(namespace, fileGroup, fileName string) error
+	PublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error
 }
 
 type ConfigGroupAPI interface {
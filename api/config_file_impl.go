@@ -67,19 +67,28 @@ func (c *configFileAPI) FetchConfigFile(req *GetConfigFileRequest) (model.Config
 	return c.context.GetEngine().SyncGetConfigFile(req.GetConfigFileRequest)
 }
 
+// GetConfigFiles 批量获取配置文件
+func (c *configFileAPI) GetConfigFiles(namespace, fileGroup string, fileNames []string) (map[string]model.ConfigFile, error) {
+	return c.context.GetEngine().SyncGetConfigFiles(&model.GetConfigFilesRequest{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		FileNames: fileNames,
+	})
+}
+
 // CreateConfigFile 创建配置文件
-func (c *configFileAPI) CreateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return c.context.GetEngine().SyncCreateConfigFile(namespace, fileGroup, fileName, content)
+func (c *configFileAPI) CreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	return c.context.GetEngine().SyncCreateConfigFile(namespace, fileGroup, fileName, content, opts...)
 }
 
 // UpdateConfigFile 更新配置文件
-func (c *configFileAPI) UpdateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return c.context.GetEngine().SyncUpdateConfigFile(namespace, fileGroup, fileName, content)
+func (c *configFileAPI) UpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	return c.context.GetEngine().SyncUpdateConfigFile(namespace, fileGroup, fileName, content, opts...)
 }
 
 // PublishConfigFile 发布配置文件
-func (c *configFileAPI) PublishConfigFile(namespace, fileGroup, fileName string) error {
-	return c.context.GetEngine().SyncPublishConfigFile(namespace, fileGroup, fileName)
+func (c *configFileAPI) PublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error {
+	return c.context.GetEngine().SyncPublishConfigFile(namespace, fileGroup, fileName, opts...)
 }
 
 // SDKContext 获取SDK上下文
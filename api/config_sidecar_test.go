@@ -0,0 +1,60 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"net"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// TestInitSidecar 测试自动模式下sidecar的探测及容灾地址降级
+func TestInitSidecar(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	cfg := config.NewDefaultConfiguration([]string{"10.0.0.1:8091"})
+	cfg.GetGlobal().GetSidecar().SetMode(config.SidecarModeAuto)
+	cfg.GetGlobal().GetSidecar().SetAddress(ln.Addr().String())
+	initSidecar(cfg)
+	endpoints := cfg.GetGlobal().GetServerConnector().GetEndpoints()
+	if len(endpoints) != 2 || len(cfg.GetGlobal().GetServerConnector().GetAddresses()) != 0 {
+		t.Fatalf("unexpected endpoints %v", endpoints)
+	}
+	if endpoints[0].Address != ln.Addr().String() || endpoints[0].Priority != 0 {
+		t.Fatalf("sidecar should be the preferred endpoint, got %s/%d", endpoints[0].Address, endpoints[0].Priority)
+	}
+	if endpoints[1].Address != "10.0.0.1:8091" || endpoints[1].Priority != 1 {
+		t.Fatalf("remote server should be the fallback endpoint, got %s/%d",
+			endpoints[1].Address, endpoints[1].Priority)
+	}
+
+	// sidecar不存在时保持直连远端服务端
+	cfg = config.NewDefaultConfiguration([]string{"10.0.0.1:8091"})
+	cfg.GetGlobal().GetSidecar().SetMode(config.SidecarModeAuto)
+	cfg.GetGlobal().GetSidecar().SetAddress("127.0.0.1:1")
+	initSidecar(cfg)
+	if addresses := cfg.GetGlobal().GetServerConnector().GetAddresses(); len(addresses) != 1 ||
+		len(cfg.GetGlobal().GetServerConnector().GetEndpoints()) != 0 {
+		t.Fatalf("remote servers should be kept when sidecar is absent, got %v", addresses)
+	}
+}
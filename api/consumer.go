@@ -18,6 +18,9 @@
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -128,6 +131,26 @@ type WatchAllServicesRequest struct {
 	model.WatchAllServicesRequest
 }
 
+// WaitForPeersRequest 等待健康实例数达到下限的请求
+type WaitForPeersRequest struct {
+	model.WaitForPeersRequest
+}
+
+// QueryInstancesRequest 直接向服务端查询实例的请求
+type QueryInstancesRequest struct {
+	model.QueryInstancesRequest
+}
+
+// ValidateContractRequest 按照被调服务契约校验请求的请求
+type ValidateContractRequest struct {
+	model.ValidateContractRequest
+}
+
+// ReportShadowComparisonRequest 上报影子请求对比结果的请求
+type ReportShadowComparisonRequest struct {
+	model.ReportShadowComparisonRequest
+}
+
 // ConsumerAPI 主调端API方法
 type ConsumerAPI interface {
 	SDKOwner
@@ -137,6 +160,12 @@ type ConsumerAPI interface {
 	GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error)
 	// GetAllInstances 获取完整的服务列表（包括隔离及不健康的服务实例）
 	GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// GetOneInstanceWithContext 同GetOneInstance，调用方上下文的截止时间及取消信号会传递到缓存等待、重试及与服务端的交互中
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 同GetInstances，使用调用方上下文控制截止时间及取消
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 同GetAllInstances，使用调用方上下文控制截止时间及取消
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
 	// GetRouteRule 同步获取服务路由规则
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
@@ -154,6 +183,29 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件，用于灾备单元的预热
+	ExportSnapshot(path string) error
+	// ImportSnapshot 从快照文件导入服务、实例及规则，本地已存在的缓存不会被覆盖
+	ImportSnapshot(path string) error
+	// WaitForPeers 等待服务的健康实例数达到下限，用于依赖法定人数的服务在对外提供服务前进行就绪检查
+	WaitForPeers(ctx context.Context, req *WaitForPeersRequest) error
+	// GetInstancesDiff 获取服务实例自 sinceRevision 以来新增、删除及变更的实例，返回的 Revision 用于下一次增量查询，
+	// sinceRevision 为空或已过期时返回全量实例，便于同步拓扑的批处理系统进行增量更新
+	GetInstancesDiff(svcKey model.ServiceKey, sinceRevision string) (*model.InstancesDiffResponse, error)
+	// QueryInstances 直接向服务端查询实例，支持按元数据、健康状态及地域过滤并分页返回，不经过本地缓存也不订阅服务，
+	// 便于盘点类任务进行一次性查询
+	QueryInstances(req *QueryInstancesRequest) (*model.QueryInstancesResponse, error)
+	// ValidateContract 按照被调服务上报的服务契约校验即将发出的请求的方法及路径，请求未在契约中声明或者契约已下线时
+	// 输出告警日志及指标，需要开启consumer.contractValidation，用于尽早发现主调与被调之间的接口漂移
+	ValidateContract(req *ValidateContractRequest) (*model.ContractValidationResult, error)
+	// ReportShadowComparison 上报同一请求发往主服务与影子服务后应答的对比结果，按主服务、影子服务、方法及结果统计，
+	// 用于服务迁移时观察影子服务的不一致率，可直接使用 integrations/shadow 完成影子请求的发送及对比
+	ReportShadowComparison(req *ReportShadowComparisonRequest) error
+	// OverrideInstanceWeight 在本地覆盖实例参与负载均衡的权重，不修改服务端数据，ttl 到期后恢复服务端权重，
+	// ttl 为0时使用默认的5分钟，最长24小时；便于与客户端部署在一起的弹性伸缩、压测及降级演练等外部控制器实时调整流量
+	OverrideInstanceWeight(svcKey model.ServiceKey, instanceID string, weight int, ttl time.Duration) error
+	// ClearInstanceWeightOverride 清除实例的本地权重覆盖，立即恢复服务端权重
+	ClearInstanceWeightOverride(svcKey model.ServiceKey, instanceID string) error
 }
 
 var (
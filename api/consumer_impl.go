@@ -18,7 +18,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/modern-go/reflect2"
@@ -69,6 +71,31 @@ func (c *consumerAPI) GetAllInstances(req *GetAllInstancesRequest) (*model.Insta
 	return c.context.GetEngine().SyncGetAllInstances(&req.GetAllInstancesRequest)
 }
 
+// GetOneInstanceWithContext 使用调用方上下文获取负载均衡后的单个实例
+func (c *consumerAPI) GetOneInstanceWithContext(
+	ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	// 使用请求的副本携带上下文，避免复用请求时残留已取消的上下文，也避免并发复用请求时的数据竞争
+	withCtx := *req
+	withCtx.SetContext(ctx)
+	return c.GetOneInstance(&withCtx)
+}
+
+// GetInstancesWithContext 使用调用方上下文获取路由后的实例
+func (c *consumerAPI) GetInstancesWithContext(
+	ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	withCtx := *req
+	withCtx.SetContext(ctx)
+	return c.GetInstances(&withCtx)
+}
+
+// GetAllInstancesWithContext 使用调用方上下文获取完整的服务实例
+func (c *consumerAPI) GetAllInstancesWithContext(
+	ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	withCtx := *req
+	withCtx.SetContext(ctx)
+	return c.GetAllInstances(&withCtx)
+}
+
 // UpdateServiceCallResult update the service call error code and delay
 func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
 	if err := checkAvailable(c); err != nil {
@@ -134,6 +161,101 @@ func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.Wat
 	return c.context.GetEngine().WatchAllServices(&req.WatchAllServicesRequest)
 }
 
+// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件
+func (c *consumerAPI) ExportSnapshot(path string) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if path == "" {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "snapshot path can not be empty")
+	}
+	return c.context.GetEngine().ExportSnapshot(path)
+}
+
+// ImportSnapshot 从快照文件导入服务、实例及规则
+func (c *consumerAPI) ImportSnapshot(path string) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if !model.IsFile(path) {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "snapshot file %s not exists", path)
+	}
+	return c.context.GetEngine().ImportSnapshot(path)
+}
+
+// WaitForPeers 等待服务的健康实例数达到下限
+func (c *consumerAPI) WaitForPeers(ctx context.Context, req *WaitForPeersRequest) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().WaitForPeers(ctx, &req.WaitForPeersRequest)
+}
+
+// GetInstancesDiff 获取服务实例的增量变更
+func (c *consumerAPI) GetInstancesDiff(svcKey model.ServiceKey, sinceRevision string) (*model.InstancesDiffResponse, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if len(svcKey.Namespace) == 0 || len(svcKey.Service) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "namespace and service are required")
+	}
+	return c.context.GetEngine().GetInstancesDiff(svcKey, sinceRevision)
+}
+
+// QueryInstances 直接向服务端查询实例
+func (c *consumerAPI) QueryInstances(req *QueryInstancesRequest) (*model.QueryInstancesResponse, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncQueryInstances(&req.QueryInstancesRequest)
+}
+
+// ValidateContract 按照被调服务契约校验请求
+func (c *consumerAPI) ValidateContract(req *ValidateContractRequest) (*model.ContractValidationResult, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncValidateContract(&req.ValidateContractRequest)
+}
+
+// ReportShadowComparison 上报影子请求对比结果
+func (c *consumerAPI) ReportShadowComparison(req *ReportShadowComparisonRequest) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncReportShadowComparison(&req.ReportShadowComparisonRequest)
+}
+
+// OverrideInstanceWeight 在本地覆盖实例的权重
+func (c *consumerAPI) OverrideInstanceWeight(svcKey model.ServiceKey, instanceID string, weight int,
+	ttl time.Duration) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	return c.context.GetEngine().OverrideInstanceWeight(svcKey, instanceID, weight, ttl)
+}
+
+// ClearInstanceWeightOverride 清除实例的本地权重覆盖
+func (c *consumerAPI) ClearInstanceWeightOverride(svcKey model.ServiceKey, instanceID string) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	c.context.GetEngine().ClearInstanceWeightOverride(svcKey, instanceID)
+	return nil
+}
+
 // SDKContext 获取SDK上下文
 func (c *consumerAPI) SDKContext() SDKContext {
 	return c.context
@@ -18,10 +18,12 @@
 package api
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -75,3 +77,35 @@ func TestGetInstancesRequest_convert(t *testing.T) {
 		})
 	}
 }
+
+type ctxEngine struct {
+	model.Engine
+	ctx context.Context
+}
+
+func (e *ctxEngine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	e.ctx = req.Context()
+	return &model.OneInstanceResponse{}, nil
+}
+
+// TestGetOneInstanceWithContext 测试调用方上下文只作用于本次调用，不会残留在复用的请求上
+func TestGetOneInstanceWithContext(t *testing.T) {
+	engine := &ctxEngine{}
+	consumer := &consumerAPI{context: &electionContext{
+		engine: engine,
+		cfg:    config.NewDefaultConfiguration(nil),
+	}}
+	req := &GetOneInstanceRequest{}
+	req.Namespace = "Test"
+	req.Service = "echo"
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := consumer.GetOneInstanceWithContext(ctx, req)
+	cancel()
+	assert.Nil(t, err)
+	assert.Equal(t, ctx, engine.ctx)
+	assert.Equal(t, context.Background(), req.Context())
+
+	_, err = consumer.GetOneInstance(req)
+	assert.Nil(t, err)
+	assert.Nil(t, engine.ctx.Err())
+}
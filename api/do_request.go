@@ -0,0 +1,163 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DoRequest 聚合调用请求，一次调用完成限流、实例选择、熔断、结果上报以及重试降级
+type DoRequest struct {
+	// 必选，命名空间
+	Namespace string
+	// 必选，被调服务名
+	Service string
+	// 可选，调用的接口方法，用于接口级限流及熔断
+	Method string
+	// 可选，请求标签，同时用于限流规则及路由规则的匹配，key的格式与 model.BuildArgumentFromLabel 一致
+	Labels map[string]string
+	// 可选，主调方服务信息
+	SourceService *model.ServiceInfo
+	// 可选，尝试预算，为空时按照consumer.requestBudget配置创建
+	Budget *model.RequestBudget
+	// 可选，降级函数，请求被限流、被熔断或者尝试预算内全部失败时调用，返回值作为Do的返回值
+	Fallback func(ctx context.Context, err error) error
+}
+
+// DoFunc 用户调用逻辑，instance为本次尝试选中的实例，ctx在尝试预算超时后取消
+type DoFunc func(ctx context.Context, instance model.Instance) error
+
+// Validate 校验聚合调用请求
+func (r *DoRequest) Validate() error {
+	if nil == r {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "DoRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.Service) == 0 {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"DoRequest: namespace and service can not be empty")
+	}
+	return nil
+}
+
+// Do 对服务发起一次带完整治理能力的逻辑请求：先获取限流配额，随后在尝试预算内重试，
+// 每次尝试都经过接口级熔断检查、选择可用实例并执行fn，调用结果上报给熔断及监控。
+// 请求被限流时返回的错误可通过 errors.Is(err, model.ErrQuotaLimited) 判断，
+// 设置了Fallback时，被限流、被熔断及最终失败的错误都交由Fallback处理
+func Do(ctx context.Context, sdkCtx SDKContext, req *DoRequest, fn DoFunc) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if sdkCtx.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "sdk context has been destroyed")
+	}
+	arguments := make([]model.Argument, 0, len(req.Labels))
+	for key, value := range req.Labels {
+		arguments = append(arguments, model.BuildArgumentFromLabel(key, value))
+	}
+	future, err := acquireDoQuota(ctx, sdkCtx, req, arguments)
+	if future != nil {
+		// 用户逻辑及降级函数执行结束后归还配额，并发数限流依赖该调用
+		defer future.Release()
+	}
+	if err != nil {
+		return doFallback(ctx, req, err)
+	}
+	reqCtx := &RequestContext{RequestContext: model.RequestContext{
+		Callee: &model.ServiceKey{Namespace: req.Namespace, Service: req.Service},
+		Method: req.Method,
+		Budget: req.Budget,
+	}}
+	if req.SourceService != nil {
+		reqCtx.Caller = &model.ServiceKey{Namespace: req.SourceService.Namespace, Service: req.SourceService.Service}
+	}
+	_, err = DoWithRetry(ctx, sdkCtx, reqCtx, func(attemptCtx context.Context, _ interface{}) (interface{}, error) {
+		return nil, doAttempt(attemptCtx, sdkCtx, req, arguments, fn)
+	})
+	if err != nil {
+		return doFallback(ctx, req, err)
+	}
+	return nil
+}
+
+// acquireDoQuota 获取限流配额，需要排队时在调用方上下文内等待，获取到配额分配结果时返回需要归还的future
+func acquireDoQuota(ctx context.Context, sdkCtx SDKContext, req *DoRequest,
+	arguments []model.Argument) (*model.QuotaFutureImpl, error) {
+	quotaReq := &model.QuotaRequestImpl{}
+	quotaReq.SetNamespace(req.Namespace)
+	quotaReq.SetService(req.Service)
+	quotaReq.SetMethod(req.Method)
+	for _, argument := range arguments {
+		quotaReq.AddArgument(argument)
+	}
+	future, err := sdkCtx.GetEngine().AsyncGetQuota(quotaReq)
+	if err != nil {
+		return nil, err
+	}
+	resp := future.GetImmediately()
+	if resp.Code == model.QuotaResultLimited {
+		return future, fmt.Errorf("%w: %s", model.ErrQuotaLimited, resp.Info)
+	}
+	if wait := future.GetExpectedWait(); wait > 0 && !data.SleepWithContext(ctx, wait) {
+		return future, model.ContextError(ctx)
+	}
+	return future, nil
+}
+
+// doAttempt 单次尝试：选择实例，执行用户逻辑并上报实例调用结果
+func doAttempt(ctx context.Context, sdkCtx SDKContext, req *DoRequest, arguments []model.Argument, fn DoFunc) error {
+	instanceReq := &model.GetOneInstanceRequest{
+		Namespace:     req.Namespace,
+		Service:       req.Service,
+		SourceService: req.SourceService,
+		Arguments:     arguments,
+	}
+	instanceReq.SetContext(ctx)
+	resp, err := sdkCtx.GetEngine().SyncGetOneInstance(instanceReq)
+	if err != nil {
+		return err
+	}
+	instance := resp.GetInstance()
+	start := time.Now()
+	err = fn(ctx, instance)
+	result := &model.ServiceCallResult{SourceService: req.SourceService}
+	result.SetCalledInstance(instance).SetDelay(time.Since(start))
+	result.SetMethod(req.Method)
+	if err != nil {
+		result.SetRetStatus(model.RetFail).SetRetCode(-1)
+	} else {
+		result.SetRetStatus(model.RetSuccess).SetRetCode(0)
+	}
+	if reportErr := sdkCtx.GetEngine().SyncUpdateServiceCallResult(result); reportErr != nil {
+		log.GetBaseLogger().Errorf("[Do] fail to report call result of %s: %v", instance.GetId(), reportErr)
+	}
+	return err
+}
+
+// doFallback 存在降级函数时交由降级函数处理错误
+func doFallback(ctx context.Context, req *DoRequest, err error) error {
+	if req.Fallback == nil {
+		return err
+	}
+	return req.Fallback(ctx, err)
+}
@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type doInstance struct {
+	model.Instance
+	id string
+}
+
+func (i *doInstance) GetId() string {
+	return i.id
+}
+
+// doEngine 模拟限流、熔断及实例选择，熔断器打开时装饰后的函数直接返回熔断错误
+type doEngine struct {
+	model.Engine
+	limited     bool
+	breakerOpen bool
+	instance    model.Instance
+	results     []*model.ServiceCallResult
+}
+
+func (e *doEngine) AsyncGetQuota(*model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
+	resp := &model.QuotaResponse{Code: model.QuotaResultOk}
+	if e.limited {
+		resp = &model.QuotaResponse{Code: model.QuotaResultLimited, Info: "qps exceeded"}
+	}
+	return model.QuotaFutureWithResponse(resp), nil
+}
+
+func (e *doEngine) SyncGetOneInstance(*model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = []model.Instance{e.instance}
+	return resp, nil
+}
+
+func (e *doEngine) SyncUpdateServiceCallResult(result *model.ServiceCallResult) error {
+	e.results = append(e.results, result)
+	return nil
+}
+
+func (e *doEngine) SyncReportStat(model.MetricType, model.InstanceGauge) error {
+	return nil
+}
+
+func (e *doEngine) MakeFunctionDecorator(f model.CustomerFunction,
+	reqCtx *model.RequestContext) model.DecoratorFunction {
+	return func(ctx context.Context, args interface{}) (interface{}, *model.CallAborted, error) {
+		if e.breakerOpen {
+			err := errors.New("circuit breaker open")
+			return nil, model.NewCallAborted(err, "method-rule", nil), err
+		}
+		if !reqCtx.Budget.TryAcquire() {
+			return nil, model.NewCallAborted(model.ErrRequestBudgetExhausted, "", nil), model.ErrRequestBudgetExhausted
+		}
+		ret, err := f(ctx, args)
+		return ret, nil, err
+	}
+}
+
+func newDoContext(engine model.Engine) SDKContext {
+	return &electionContext{engine: engine, cfg: config.NewDefaultConfiguration(nil)}
+}
+
+func newDoRequest() *DoRequest {
+	return &DoRequest{Namespace: "Test", Service: "echo", Method: "/echo", Budget: model.NewRequestBudget(3, 0)}
+}
+
+// TestDoRetry 测试失败后在尝试预算内重试，每次尝试都上报调用结果
+func TestDoRetry(t *testing.T) {
+	engine := &doEngine{instance: &doInstance{id: "a"}}
+	var attempts int
+	err := Do(context.Background(), newDoContext(engine), newDoRequest(),
+		func(ctx context.Context, instance model.Instance) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("unavailable")
+			}
+			return nil
+		})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expect success on third attempt, got %v after %d attempts", err, attempts)
+	}
+	if len(engine.results) != 3 || engine.results[0].GetRetStatus() != model.RetFail ||
+		engine.results[2].GetRetStatus() != model.RetSuccess {
+		t.Fatalf("expect every attempt reported, got %d results", len(engine.results))
+	}
+
+	attempts = 0
+	err = Do(context.Background(), newDoContext(engine), newDoRequest(),
+		func(ctx context.Context, instance model.Instance) error {
+			attempts++
+			return errors.New("unavailable")
+		})
+	if !errors.Is(err, model.ErrRequestBudgetExhausted) || attempts != 3 {
+		t.Fatalf("expect budget exhausted after 3 attempts, got %v after %d attempts", err, attempts)
+	}
+}
+
+// TestDoLimitedAndBroken 测试被限流及被熔断时不执行用户逻辑，错误交由Fallback处理
+func TestDoLimitedAndBroken(t *testing.T) {
+	engine := &doEngine{instance: &doInstance{id: "a"}, limited: true}
+	fn := func(ctx context.Context, instance model.Instance) error {
+		t.Fatal("fn should not be called")
+		return nil
+	}
+	if err := Do(context.Background(), newDoContext(engine), newDoRequest(), fn); !errors.Is(err, model.ErrQuotaLimited) {
+		t.Fatalf("expect quota limited, got %v", err)
+	}
+
+	engine.limited = false
+	engine.breakerOpen = true
+	var fallbackErr error
+	req := newDoRequest()
+	req.Fallback = func(ctx context.Context, err error) error {
+		fallbackErr = err
+		return nil
+	}
+	if err := Do(context.Background(), newDoContext(engine), req, fn); err != nil {
+		t.Fatalf("expect error handled by fallback, got %v", err)
+	}
+	if fallbackErr == nil || fallbackErr.Error() != "circuit breaker open" {
+		t.Fatalf("expect circuit breaker error passed to fallback, got %v", fallbackErr)
+	}
+	if len(engine.results) != 0 {
+		t.Fatal("expect no call result reported when call not made")
+	}
+}
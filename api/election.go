@@ -0,0 +1,48 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+// LeaderElection 基于北极星服务实例注册及心跳实现的选主
+// 每个候选者以实例的形式注册到选举服务中，健康且最早加入的候选者当选为主节点，
+// 主节点加入选举时的epoch作为fencing token；新的epoch总是大于已知候选者的epoch，
+// 心跳超时的候选者恢复后以新的epoch重新加入，因此后续任期的token单调递增
+type LeaderElection interface {
+	// SetNamespace 设置选举服务所在的命名空间，需要在Start之前调用，默认为default
+	SetNamespace(namespace string)
+	// OnElected 设置当选为主节点时的回调，token为本任期的fencing token
+	OnElected(handler func(token int64))
+	// OnRevoked 设置失去主节点身份时的回调
+	OnRevoked(handler func())
+	// Start 注册候选者并参与选举
+	Start() error
+	// Stop 退出选举，如果当前为主节点则释放主节点身份
+	Stop()
+	// IsLeader 当前候选者是否为主节点
+	IsLeader() bool
+	// GetLeader 获取当前主节点的候选者标识，未知时返回空
+	GetLeader() string
+	// GetFencingToken 获取本任期的fencing token，非主节点时返回0
+	GetFencingToken() int64
+}
+
+var (
+	// NewLeaderElection 通过默认配置创建选主对象，Stop时会销毁内部创建的SDK上下文
+	NewLeaderElection = newLeaderElection
+	// NewLeaderElectionByContext 通过上下文创建选主对象
+	NewLeaderElectionByContext = newLeaderElectionByContext
+)
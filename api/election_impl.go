@@ -0,0 +1,438 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+)
+
+const (
+	// 选举服务默认命名空间
+	defaultElectionNamespace = "default"
+	// 候选者实例上标识选举键的元数据
+	electionKeyMetadata = "polaris.election.key"
+	// 候选者实例上标识加入选举时间的元数据，同时作为fencing token
+	electionEpochMetadata = "polaris.election.epoch"
+	// 候选者实例上标识候选者的元数据，与实例ID相同
+	electionCandidateMetadata = "polaris.election.candidate"
+	// 候选者实例不对外提供服务，端口只用于通过注册校验，候选者之间通过实例ID区分
+	electionPort = 1
+)
+
+// leaderElection 选主实现
+type leaderElection struct {
+	context    SDKContext
+	ownContext bool
+	namespace  string
+	service    string
+	key        string
+	ttl        time.Duration
+	onElected  func(token int64)
+	onRevoked  func()
+
+	mutex   sync.RWMutex
+	started bool
+	host    string
+	// 候选者标识，同时作为注册实例的ID
+	candidateID string
+	epoch       int64
+	// 最近一次心跳成功的时间，超过ttl未成功则主动放弃主节点身份
+	lastBeatTime time.Time
+	// 心跳超时后需要以新的epoch重新加入选举
+	stale    bool
+	leader   string
+	token    int64
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// SetNamespace 设置选举服务所在的命名空间
+func (l *leaderElection) SetNamespace(namespace string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.namespace = namespace
+}
+
+// OnElected 设置当选为主节点时的回调
+func (l *leaderElection) OnElected(handler func(token int64)) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.onElected = handler
+}
+
+// OnRevoked 设置失去主节点身份时的回调
+func (l *leaderElection) OnRevoked(handler func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.onRevoked = handler
+}
+
+// Start 注册候选者并参与选举
+func (l *leaderElection) Start() error {
+	if err := checkAvailable(l); err != nil {
+		return err
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.started {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "leader election has been started")
+	}
+	l.host = l.context.GetConfig().GetGlobal().GetAPI().GetBindIP()
+	if len(l.host) == 0 {
+		l.host = "127.0.0.1"
+	}
+	l.candidateID = l.host + "#" + uuid.New().String()
+	epoch := l.nextEpoch()
+	if err := l.register(epoch); err != nil {
+		return err
+	}
+	l.epoch = epoch
+	l.lastBeatTime = time.Now()
+	l.started = true
+	l.stopChan = make(chan struct{})
+	l.wg.Add(1)
+	stopChan := l.stopChan
+	profiling.Go("leaderElection", "campaign", func() {
+		l.campaign(stopChan)
+	})
+	log.GetBaseLogger().Infof("[LeaderElection] candidate %s joined election %s/%s/%s, epoch %d",
+		l.candidate(), l.namespace, l.service, l.key, l.epoch)
+	return nil
+}
+
+// Stop 退出选举
+func (l *leaderElection) Stop() {
+	l.mutex.Lock()
+	if !l.started {
+		l.mutex.Unlock()
+		return
+	}
+	l.started = false
+	close(l.stopChan)
+	l.mutex.Unlock()
+	l.wg.Wait()
+	l.revoke()
+	err := l.context.GetEngine().SyncDeregister(&model.InstanceDeRegisterRequest{
+		Namespace:  l.namespace,
+		Service:    l.service,
+		InstanceID: l.candidateID,
+		Host:       l.host,
+		Port:       electionPort,
+	})
+	if err != nil {
+		log.GetBaseLogger().Warnf("[LeaderElection] fail to deregister candidate %s: %v", l.candidate(), err)
+	}
+	if l.ownContext {
+		l.context.Destroy()
+	}
+}
+
+// IsLeader 当前候选者是否为主节点
+func (l *leaderElection) IsLeader() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.token > 0
+}
+
+// GetLeader 获取当前主节点的候选者标识
+func (l *leaderElection) GetLeader() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.leader
+}
+
+// GetFencingToken 获取本任期的fencing token
+func (l *leaderElection) GetFencingToken() int64 {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.token
+}
+
+// SDKContext 获取SDK上下文
+func (l *leaderElection) SDKContext() SDKContext {
+	return l.context
+}
+
+// candidate 候选者标识
+func (l *leaderElection) candidate() string {
+	return l.candidateID
+}
+
+// register 以指定的epoch注册候选者实例，重复注册时更新实例的epoch
+func (l *leaderElection) register(epoch int64) error {
+	ttlSeconds := int(l.ttl / time.Second)
+	req := &model.InstanceRegisterRequest{
+		Namespace:  l.namespace,
+		Service:    l.service,
+		Host:       l.host,
+		Port:       electionPort,
+		TTL:        &ttlSeconds,
+		InstanceId: l.candidateID,
+		Metadata: map[string]string{
+			electionKeyMetadata:       l.key,
+			electionCandidateMetadata: l.candidateID,
+			electionEpochMetadata:     strconv.FormatInt(epoch, 10),
+		},
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	_, err := l.context.GetEngine().SyncRegister(req)
+	return err
+}
+
+// nextEpoch 计算加入选举的epoch，大于当前时间戳以及已知候选者的最大epoch，
+// 保证新加入或者重新加入的候选者排在现有主节点之后，主机间存在时钟偏差时fencing token也不会回退
+func (l *leaderElection) nextEpoch() int64 {
+	epoch := time.Now().UnixNano()
+	candidates, err := l.getCandidates()
+	if err != nil {
+		// 选举服务尚未创建时查询失败，此时没有其他候选者
+		log.GetBaseLogger().Debugf("[LeaderElection] fail to get candidates of %s/%s: %v",
+			l.namespace, l.service, err)
+		return epoch
+	}
+	for _, c := range candidates {
+		if c.epoch >= epoch {
+			epoch = c.epoch + 1
+		}
+	}
+	return epoch
+}
+
+// electionCandidate 选举服务中的候选者
+type electionCandidate struct {
+	id      string
+	epoch   int64
+	healthy bool
+}
+
+// getCandidates 获取同一选举键下的全部候选者
+func (l *leaderElection) getCandidates() ([]electionCandidate, error) {
+	resp, err := l.context.GetEngine().SyncGetAllInstances(&model.GetAllInstancesRequest{
+		Namespace: l.namespace,
+		Service:   l.service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var candidates []electionCandidate
+	for _, instance := range resp.GetInstances() {
+		metadata := instance.GetMetadata()
+		if metadata[electionKeyMetadata] != l.key {
+			continue
+		}
+		epoch, err := strconv.ParseInt(metadata[electionEpochMetadata], 10, 64)
+		if err != nil {
+			continue
+		}
+		id := metadata[electionCandidateMetadata]
+		if len(id) == 0 {
+			id = instance.GetId()
+		}
+		candidates = append(candidates, electionCandidate{
+			id:      id,
+			epoch:   epoch,
+			healthy: instance.IsHealthy() && !instance.IsIsolated(),
+		})
+	}
+	return candidates, nil
+}
+
+// campaign 周期性上报心跳并根据实例列表计算主节点
+func (l *leaderElection) campaign(stopChan chan struct{}) {
+	defer l.wg.Done()
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			l.tick()
+		}
+	}
+}
+
+// tick 一次选举周期：上报心跳，心跳超时则放弃主节点身份，恢复后以新的epoch重新加入，再计算主节点
+func (l *leaderElection) tick() {
+	l.heartbeat()
+	l.mutex.Lock()
+	expired := time.Since(l.lastBeatTime) > l.ttl
+	if expired {
+		l.stale = true
+	}
+	stale := l.stale
+	l.mutex.Unlock()
+	if expired {
+		// 心跳超时，服务端很快会将本候选者置为不健康，需要先于其他候选者当选前放弃身份
+		l.revoke()
+		return
+	}
+	if stale && !l.rejoin() {
+		return
+	}
+	l.elect()
+}
+
+// rejoin 心跳超时期间其他候选者可能已经当选，以新的epoch重新注册，避免恢复后凭借旧的epoch抢回主节点导致fencing token回退
+func (l *leaderElection) rejoin() bool {
+	epoch := l.nextEpoch()
+	if err := l.register(epoch); err != nil {
+		log.GetBaseLogger().Warnf("[LeaderElection] candidate %s fail to rejoin election: %v", l.candidate(), err)
+		return false
+	}
+	l.mutex.Lock()
+	l.epoch = epoch
+	l.stale = false
+	l.mutex.Unlock()
+	log.GetBaseLogger().Infof("[LeaderElection] candidate %s rejoined election %s/%s/%s, epoch %d",
+		l.candidate(), l.namespace, l.service, l.key, epoch)
+	return true
+}
+
+func (l *leaderElection) heartbeat() {
+	err := l.context.GetEngine().SyncHeartbeat(&model.InstanceHeartbeatRequest{
+		Namespace:  l.namespace,
+		Service:    l.service,
+		InstanceID: l.candidateID,
+		Host:       l.host,
+		Port:       electionPort,
+	})
+	if err != nil {
+		log.GetBaseLogger().Warnf("[LeaderElection] candidate %s fail to heartbeat: %v", l.candidate(), err)
+		return
+	}
+	l.mutex.Lock()
+	l.lastBeatTime = time.Now()
+	l.mutex.Unlock()
+}
+
+// elect 健康且未隔离的候选者中，epoch最小的当选，epoch相同时按候选者标识排序
+func (l *leaderElection) elect() {
+	candidates, err := l.getCandidates()
+	if err != nil {
+		log.GetBaseLogger().Warnf("[LeaderElection] fail to get candidates of %s/%s: %v",
+			l.namespace, l.service, err)
+		return
+	}
+	var (
+		leader      string
+		leaderEpoch int64
+	)
+	for _, c := range candidates {
+		if !c.healthy {
+			continue
+		}
+		if len(leader) == 0 || c.epoch < leaderEpoch || (c.epoch == leaderEpoch && c.id < leader) {
+			leader, leaderEpoch = c.id, c.epoch
+		}
+	}
+	l.mutex.RLock()
+	epoch := l.epoch
+	l.mutex.RUnlock()
+	if leader == l.candidate() && leaderEpoch == epoch {
+		l.elected(leader)
+		return
+	}
+	l.mutex.Lock()
+	l.leader = leader
+	l.mutex.Unlock()
+	l.revoke()
+}
+
+func (l *leaderElection) elected(leader string) {
+	l.mutex.Lock()
+	l.leader = leader
+	if l.token > 0 {
+		l.mutex.Unlock()
+		return
+	}
+	token := l.epoch
+	l.token = token
+	handler := l.onElected
+	l.mutex.Unlock()
+	log.GetBaseLogger().Infof("[LeaderElection] candidate %s elected as leader of %s/%s/%s, token %d",
+		leader, l.namespace, l.service, l.key, token)
+	if handler != nil {
+		handler(token)
+	}
+}
+
+func (l *leaderElection) revoke() {
+	l.mutex.Lock()
+	if l.token == 0 {
+		l.mutex.Unlock()
+		return
+	}
+	l.token = 0
+	if l.leader == l.candidate() {
+		l.leader = ""
+	}
+	handler := l.onRevoked
+	l.mutex.Unlock()
+	log.GetBaseLogger().Infof("[LeaderElection] candidate %s revoked from leader of %s/%s/%s",
+		l.candidate(), l.namespace, l.service, l.key)
+	if handler != nil {
+		handler()
+	}
+}
+
+// newLeaderElection 通过默认配置创建选主对象
+func newLeaderElection(service, key string, ttl time.Duration) (LeaderElection, error) {
+	context, err := InitContextByConfig(config.NewDefaultConfigurationWithDomain())
+	if err != nil {
+		return nil, err
+	}
+	election, err := newLeaderElectionByContext(context, service, key, ttl)
+	if err != nil {
+		context.Destroy()
+		return nil, err
+	}
+	election.(*leaderElection).ownContext = true
+	return election, nil
+}
+
+// newLeaderElectionByContext 通过上下文创建选主对象
+func newLeaderElectionByContext(
+	context SDKContext, service, key string, ttl time.Duration) (LeaderElection, error) {
+	if len(service) == 0 || len(key) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"leader election: service and key should not be empty")
+	}
+	if ttl < time.Second {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"leader election: ttl should not be less than 1s")
+	}
+	return &leaderElection{
+		context:   context,
+		namespace: defaultElectionNamespace,
+		service:   service,
+		key:       key,
+		ttl:       ttl,
+	}, nil
+}
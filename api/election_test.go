@@ -0,0 +1,222 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// fakeRegistry 选举测试使用的内存注册中心，按实例ID保存候选者
+type fakeRegistry struct {
+	mutex     sync.Mutex
+	instances map[string]*apiservice.Instance
+	// 心跳失败的实例，模拟网络分区
+	partitioned map[string]bool
+}
+
+func (r *fakeRegistry) setHealthy(id string, healthy bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.instances[id].Healthy = wrapperspb.Bool(healthy)
+}
+
+type electionEngine struct {
+	model.Engine
+	registry *fakeRegistry
+}
+
+func (e *electionEngine) SyncRegister(req *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	e.registry.mutex.Lock()
+	defer e.registry.mutex.Unlock()
+	e.registry.instances[req.InstanceId] = &apiservice.Instance{
+		Id:       wrapperspb.String(req.InstanceId),
+		Host:     wrapperspb.String(req.Host),
+		Port:     wrapperspb.UInt32(uint32(req.Port)),
+		Healthy:  wrapperspb.Bool(true),
+		Metadata: req.Metadata,
+	}
+	return &model.InstanceRegisterResponse{InstanceID: req.InstanceId}, nil
+}
+
+func (e *electionEngine) SyncHeartbeat(req *model.InstanceHeartbeatRequest) error {
+	e.registry.mutex.Lock()
+	defer e.registry.mutex.Unlock()
+	if e.registry.partitioned[req.InstanceID] {
+		return errors.New("network unreachable")
+	}
+	return nil
+}
+
+func (e *electionEngine) SyncDeregister(req *model.InstanceDeRegisterRequest) error {
+	e.registry.mutex.Lock()
+	defer e.registry.mutex.Unlock()
+	delete(e.registry.instances, req.InstanceID)
+	return nil
+}
+
+func (e *electionEngine) SyncGetAllInstances(req *model.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	e.registry.mutex.Lock()
+	defer e.registry.mutex.Unlock()
+	svcKey := &model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	resp := &model.InstancesResponse{}
+	for _, instance := range e.registry.instances {
+		resp.Instances = append(resp.Instances, pb.NewInstanceInProto(instance, svcKey, nil))
+	}
+	return resp, nil
+}
+
+type electionContext struct {
+	SDKContext
+	engine model.Engine
+	cfg    config.Configuration
+}
+
+func (c *electionContext) GetEngine() model.Engine {
+	return c.engine
+}
+
+func (c *electionContext) GetConfig() config.Configuration {
+	return c.cfg
+}
+
+func (c *electionContext) IsDestroyed() bool {
+	return false
+}
+
+// newTestCandidate 创建候选者，ttl足够长，由测试调用tick驱动选举
+func newTestCandidate(t *testing.T, registry *fakeRegistry, tokens *[]int64) *leaderElection {
+	ctx := &electionContext{
+		engine: &electionEngine{registry: registry},
+		cfg:    config.NewDefaultConfiguration(nil),
+	}
+	election, err := newLeaderElectionByContext(ctx, "election", "job", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := election.(*leaderElection)
+	l.OnElected(func(token int64) {
+		*tokens = append(*tokens, token)
+	})
+	if err = l.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(l.Stop)
+	return l
+}
+
+// TestLeaderElectionFailover 测试选主、主节点失联后的切换以及fencing token单调递增
+func TestLeaderElectionFailover(t *testing.T) {
+	registry := &fakeRegistry{instances: map[string]*apiservice.Instance{}, partitioned: map[string]bool{}}
+	var tokens []int64
+	a := newTestCandidate(t, registry, &tokens)
+	b := newTestCandidate(t, registry, &tokens)
+	if a.candidate() == b.candidate() {
+		t.Fatal("candidates on the same host should have different identities")
+	}
+	a.tick()
+	b.tick()
+	if !a.IsLeader() || b.IsLeader() || b.GetLeader() != a.candidate() {
+		t.Fatalf("expect the earliest candidate elected, leader %s", b.GetLeader())
+	}
+
+	// a 心跳失败超过ttl，主动放弃主节点，服务端随后将其置为不健康，b 当选
+	registry.partitioned[a.candidate()] = true
+	a.mutex.Lock()
+	a.lastBeatTime = time.Now().Add(-2 * a.ttl)
+	a.mutex.Unlock()
+	a.tick()
+	if a.IsLeader() {
+		t.Fatal("expect leader revoked after heartbeat expired")
+	}
+	registry.setHealthy(a.candidate(), false)
+	b.tick()
+	if !b.IsLeader() {
+		t.Fatal("expect b elected after a is unhealthy")
+	}
+
+	// a 恢复后以新的epoch重新加入，不能凭借旧的epoch抢回主节点
+	registry.partitioned[a.candidate()] = false
+	registry.setHealthy(a.candidate(), true)
+	a.tick()
+	b.tick()
+	if a.IsLeader() || !b.IsLeader() {
+		t.Fatal("expect recovered candidate to rejoin behind the current leader")
+	}
+
+	// b 退出后 a 当选，token 继续递增
+	b.Stop()
+	a.tick()
+	if !a.IsLeader() {
+		t.Fatal("expect a elected after b stopped")
+	}
+	if len(tokens) != 3 {
+		t.Fatalf("expect 3 terms, got %v", tokens)
+	}
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] <= tokens[i-1] {
+			t.Fatalf("expect fencing tokens increasing, got %v", tokens)
+		}
+	}
+	if a.GetFencingToken() != tokens[2] {
+		t.Fatalf("expect current token %d, got %d", tokens[2], a.GetFencingToken())
+	}
+}
+
+// TestLeaderElectionEpochAfterSkew 测试时钟落后的候选者加入时epoch仍大于已有候选者
+func TestLeaderElectionEpochAfterSkew(t *testing.T) {
+	registry := &fakeRegistry{instances: map[string]*apiservice.Instance{}, partitioned: map[string]bool{}}
+	future := time.Now().Add(time.Hour).UnixNano()
+	registry.instances["skewed"] = &apiservice.Instance{
+		Id:      wrapperspb.String("skewed"),
+		Host:    wrapperspb.String("127.0.0.2"),
+		Port:    wrapperspb.UInt32(electionPort),
+		Healthy: wrapperspb.Bool(true),
+		Metadata: map[string]string{
+			electionKeyMetadata:       "job",
+			electionCandidateMetadata: "skewed",
+			electionEpochMetadata:     "0",
+		},
+	}
+	registry.instances["ahead"] = &apiservice.Instance{
+		Id:      wrapperspb.String("ahead"),
+		Host:    wrapperspb.String("127.0.0.3"),
+		Port:    wrapperspb.UInt32(electionPort),
+		Healthy: wrapperspb.Bool(false),
+		Metadata: map[string]string{
+			electionKeyMetadata:       "job",
+			electionCandidateMetadata: "ahead",
+			electionEpochMetadata:     strconv.FormatInt(future, 10),
+		},
+	}
+	var tokens []int64
+	l := newTestCandidate(t, registry, &tokens)
+	if l.epoch <= future {
+		t.Fatalf("expect epoch after known candidates, got %d <= %d", l.epoch, future)
+	}
+}
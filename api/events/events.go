@@ -0,0 +1,205 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package events 提供类型化的SDK事件订阅入口，事件通过channel投递，ctx取消或者SDK销毁时channel被关闭
+package events
+
+import (
+	"context"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SubscribeInstances 订阅服务实例变更事件
+func SubscribeInstances(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.InstancesChangeEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.InstancesChangeEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindInstancesChange, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.InstancesChangeEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeCircuitBreaker 订阅熔断状态变更事件
+func SubscribeCircuitBreaker(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.CircuitBreakerEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.CircuitBreakerEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindCircuitBreaker, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.CircuitBreakerEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeConfigChange 订阅已订阅配置文件的变更事件，只有通过 GetConfigFile 订阅过的配置文件才会产生事件
+func SubscribeConfigChange(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.ConfigFileChangeEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.ConfigFileChangeEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindConfigChange, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.ConfigFileChangeEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeRateLimitRule 订阅限流规则变更事件
+func SubscribeRateLimitRule(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.RateLimitRuleEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.RateLimitRuleEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindRateLimitRule, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.RateLimitRuleEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeCachePreload 订阅持久化缓存加载进度事件，启动等待超时后仍在后台加载的缓存也会继续产生进度事件
+func SubscribeCachePreload(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.CachePreloadEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.CachePreloadEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindCachePreload, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.CachePreloadEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// SubscribeMemoryShed 订阅缓存内存降载模式变更事件，缓存内存超出预算进入降载模式以及恢复时产生事件
+func SubscribeMemoryShed(ctx context.Context, sdkCtx api.SDKContext,
+	opts ...model.EventSubscribeOption) (<-chan model.MemoryShedEvent, error) {
+	options := model.BuildEventSubscribeOptions(opts)
+	ch := make(chan model.MemoryShedEvent, options.BufferSize)
+	err := subscribe(ctx, sdkCtx, model.EventKindMemoryShed, options, func(event interface{}) bool {
+		select {
+		case ch <- event.(model.MemoryShedEvent):
+			return true
+		default:
+			return false
+		}
+	}, func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}, func() { close(ch) })
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// subscribe 注册订阅者，send 将事件非阻塞地写入类型化的channel，缓冲区已满时返回false，
+// dropOldest 非阻塞地丢弃缓冲区中最早的事件，closeCh 关闭channel
+func subscribe(ctx context.Context, sdkCtx api.SDKContext, kind model.EventKind, options *model.EventSubscribeOptions,
+	send func(event interface{}) bool, dropOldest func(), closeCh func()) error {
+	if ctx == nil || sdkCtx == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "ctx and sdkCtx can not be nil")
+	}
+	if sdkCtx.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "api instance has been destroyed")
+	}
+	return sdkCtx.GetEngine().SubscribeEvents(ctx, &model.EventSubscriber{
+		Kind: kind,
+		Deliver: func(event interface{}) {
+			offer(kind, options.BufferPolicy, event, send, dropOldest)
+		},
+		Close: closeCh,
+	})
+}
+
+// offer 按缓冲策略投递事件，在事件分发方持有锁时调用，只做非阻塞的写入和丢弃
+func offer(kind model.EventKind, policy model.EventBufferPolicy, event interface{},
+	send func(event interface{}) bool, dropOldest func()) {
+	if send(event) {
+		return
+	}
+	if policy == model.DropOldestEvent {
+		dropOldest()
+		if send(event) {
+			return
+		}
+	}
+	log.GetBaseLogger().Warnf("[Events] subscriber buffer of %s events is full, event dropped", kind)
+}
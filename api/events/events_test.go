@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package events
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestOfferDropOldest 测试缓冲区满时按丢弃最早事件的策略保留最新的事件
+func TestOfferDropOldest(t *testing.T) {
+	ch := make(chan int, 2)
+	send := func(event interface{}) bool {
+		select {
+		case ch <- event.(int):
+			return true
+		default:
+			return false
+		}
+	}
+	dropOldest := func() {
+		select {
+		case <-ch:
+		default:
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		offer(model.EventKindInstancesChange, model.DropOldestEvent, i, send, dropOldest)
+	}
+	if first, second := <-ch, <-ch; first != 2 || second != 3 {
+		t.Fatalf("expect latest events kept, got %d, %d", first, second)
+	}
+}
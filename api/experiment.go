@@ -0,0 +1,40 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import "github.com/polarismesh/polaris-go/pkg/model"
+
+// ExperimentAPI 基于配置中心下发的实验定义进行A/B实验分组
+// 实验定义文件为yaml或json格式，文件变更后自动生效；分组使用与灰度分桶路由相同的哈希算法，
+// 实验与灰度规则配置相同盐值时，第一个分组的单元与灰度百分比内的单元一致
+type ExperimentAPI interface {
+	SDKOwner
+	// GetVariant 获取实验单元所在的分组，单元未进入实验时返回空，实验不存在时返回错误
+	GetVariant(experimentKey, unitID string) (string, error)
+	// GetAssignment 携带请求标签获取分组结果，标签用于定向规则求值
+	GetAssignment(experimentKey, unitID string, labels map[string]string) (*model.ExperimentAssignment, error)
+	// Destroy 停止监听实验定义文件，通过默认配置创建时会销毁内部创建的SDK上下文
+	Destroy()
+}
+
+var (
+	// NewExperimentAPI 通过默认配置创建实验API，实验定义位于指定的配置文件
+	NewExperimentAPI = newExperimentAPI
+	// NewExperimentAPIByContext 通过上下文创建实验API
+	NewExperimentAPIByContext = newExperimentAPIByContext
+)
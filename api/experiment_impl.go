@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/experiment"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// experimentAPI 实验分组实现
+type experimentAPI struct {
+	context    SDKContext
+	ownContext bool
+	file       model.ConfigFile
+	assigner   *experiment.Assigner
+	destroyed  uint32
+}
+
+// newExperimentAPI 通过默认配置创建实验API
+func newExperimentAPI(namespace, fileGroup, fileName string) (ExperimentAPI, error) {
+	context, err := InitContextByConfig(config.NewDefaultConfigurationWithDomain())
+	if err != nil {
+		return nil, err
+	}
+	api, err := newExperimentAPIByContext(context, namespace, fileGroup, fileName)
+	if err != nil {
+		context.Destroy()
+		return nil, err
+	}
+	api.(*experimentAPI).ownContext = true
+	return api, nil
+}
+
+// newExperimentAPIByContext 通过上下文创建实验API
+func newExperimentAPIByContext(
+	context SDKContext, namespace, fileGroup, fileName string) (ExperimentAPI, error) {
+	if len(namespace) == 0 || len(fileGroup) == 0 || len(fileName) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"experiment: namespace, fileGroup and fileName should not be empty")
+	}
+	file, err := context.GetEngine().SyncGetConfigFile(&model.GetConfigFileRequest{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		FileName:  fileName,
+		Subscribe: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e := &experimentAPI{
+		context:  context,
+		file:     file,
+		assigner: experiment.NewAssigner(),
+	}
+	if err = e.assigner.Update(file.GetContent()); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"experiment: invalid definitions in %s/%s/%s", namespace, fileGroup, fileName)
+	}
+	file.AddChangeListener(e.onChange)
+	return e, nil
+}
+
+// onChange 实验定义文件变更，定义不合法时保留原有定义
+func (e *experimentAPI) onChange(event model.ConfigFileChangeEvent) {
+	if atomic.LoadUint32(&e.destroyed) == 1 {
+		return
+	}
+	if err := e.assigner.Update(event.NewValue); err != nil {
+		log.GetBaseLogger().Errorf("[Experiment] fail to update definitions from %s/%s/%s, keep previous: %v",
+			e.file.GetNamespace(), e.file.GetFileGroup(), e.file.GetFileName(), err)
+		return
+	}
+	log.GetBaseLogger().Infof("[Experiment] definitions updated from %s/%s/%s, experiments %v",
+		e.file.GetNamespace(), e.file.GetFileGroup(), e.file.GetFileName(), e.assigner.Keys())
+}
+
+// GetVariant 获取实验单元所在的分组
+func (e *experimentAPI) GetVariant(experimentKey, unitID string) (string, error) {
+	assignment, err := e.GetAssignment(experimentKey, unitID, nil)
+	if err != nil {
+		return "", err
+	}
+	return assignment.Variant, nil
+}
+
+// GetAssignment 携带请求标签获取分组结果
+func (e *experimentAPI) GetAssignment(
+	experimentKey, unitID string, labels map[string]string) (*model.ExperimentAssignment, error) {
+	if err := checkAvailable(e); err != nil {
+		return nil, err
+	}
+	if len(experimentKey) == 0 || len(unitID) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"experiment: experimentKey and unitID should not be empty")
+	}
+	assignment, err := e.assigner.Assign(experimentKey, unitID, labels)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "experiment: %v", err)
+	}
+	return assignment, nil
+}
+
+// Destroy 停止监听实验定义文件
+func (e *experimentAPI) Destroy() {
+	if !atomic.CompareAndSwapUint32(&e.destroyed, 0, 1) {
+		return
+	}
+	if e.ownContext {
+		e.context.Destroy()
+	}
+}
+
+// SDKContext 获取SDK上下文
+func (e *experimentAPI) SDKContext() SDKContext {
+	return e.context
+}
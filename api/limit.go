@@ -65,6 +65,10 @@ type QuotaFuture interface {
 	Get() *model.QuotaResponse
 	// GetImmediately 立刻获取分配结果，不等待
 	GetImmediately() *model.QuotaResponse
+	// GetExpectedWait 获取剩余的预期排队等待时间，可用于在超出延迟预算时快速失败
+	GetExpectedWait() time.Duration
+	// GetQueueDepth 获取排队时位于当前请求前面的请求数
+	GetQueueDepth() int
 	// Release 释放资源，仅用于并发数限流的场景
 	Release()
 }
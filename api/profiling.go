@@ -0,0 +1,37 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+)
+
+const (
+	// ProfileLabelPlugin SDK常驻协程pprof标签中的插件名，可通过go tool pprof -tagfocus过滤
+	ProfileLabelPlugin = profiling.LabelPlugin
+	// ProfileLabelTask SDK常驻协程pprof标签中的任务类型
+	ProfileLabelTask = profiling.LabelTask
+)
+
+// RegisterProfileHandlers 将SDK专属的profile注册到应用已有的pprof mux上，
+// 路径为/debug/pprof/polaris-go.goroutines，记录存活的SDK常驻协程及其启动位置
+func RegisterProfileHandlers(mux *http.ServeMux) {
+	profiling.RegisterHandlers(mux)
+}
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -36,10 +38,17 @@ type InstanceRegisterRequest struct {
 	model.InstanceRegisterRequest
 }
 
+// ImportInstancesRequest 外部实例批量导入请求
+type ImportInstancesRequest struct {
+	model.ImportInstancesRequest
+}
+
 // ProviderAPI CL5服务端API的主接口
 type ProviderAPI interface {
 	SDKOwner
-	// RegisterInstance
+	// RegisterInstance 注册服务实例并由SDK维护心跳，调用方无需自行上报心跳：
+	// 按照实例TTL（默认5秒）定时上报心跳；服务端丢失实例或者心跳连续失败时使用原始请求自动重新注册，
+	// 重新注册失败时按指数退避，结果通过 ReRegisterHandler 回调；Deregister 或者 Destroy 时停止心跳。
 	// minimum supported version of polaris-server is v1.10.0
 	RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
 	// Register
@@ -52,6 +61,25 @@ type ProviderAPI interface {
 	// Heartbeat the heartbeat report
 	// Deprecated: Use RegisterInstance instead.
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// RegisterInstanceWithContext 同RegisterInstance，调用方上下文的截止时间及取消信号会传递到重试及与服务端的交互中，
+	// 注册成功后的后台心跳不受该上下文影响
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext 同Deregister，使用调用方上下文控制截止时间及取消
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext 同Heartbeat，使用调用方上下文控制截止时间及取消
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
+	// ImportInstances 将外部系统（VIP 池、云负载均衡后端等）的实例全集同步到北极星，
+	// 对同步标签下的实例进行对账：注册新增及变更的实例，移除不在列表中的实例，DryRun 模式下仅返回对账结果
+	ImportInstances(req *ImportInstancesRequest) (*model.ImportInstancesResponse, error)
+	// EnterLameduck 进入lameduck状态，将自动心跳实例的TTL缩短为 provider.lameduckTTL，
+	// 可在容器preStop钩子中调用，即使进程随后被SIGKILL来不及反注册，服务端也能在数秒内摘除实例
+	EnterLameduck() error
+	// TrackRequest 记录一个开始处理的服务端请求，请求处理完成时调用返回的函数，GracefulShutdown 会等待在途请求处理完成
+	TrackRequest() (done func())
+	// GracefulShutdown 优雅下线，适用于容器preStop钩子：将自动心跳的实例隔离使主调方不再分配新请求，
+	// 等待 TrackRequest 记录的在途请求处理完成（最长 provider.shutdownDrainPeriod），再反注册实例并刷新统计上报
+	GracefulShutdown(ctx context.Context) error
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/register"
@@ -76,6 +78,63 @@ func (c *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
 	return c.context.GetEngine().SyncHeartbeat(&instance.InstanceHeartbeatRequest)
 }
 
+// RegisterInstanceWithContext 使用调用方上下文注册服务实例，并开启后台心跳
+func (c *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	withCtx := *instance
+	withCtx.SetContext(ctx)
+	return c.RegisterInstance(&withCtx)
+}
+
+// DeregisterWithContext 使用调用方上下文反注册服务实例
+func (c *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	withCtx := *instance
+	withCtx.SetContext(ctx)
+	return c.Deregister(&withCtx)
+}
+
+// HeartbeatWithContext 使用调用方上下文上报心跳
+func (c *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	withCtx := *instance
+	withCtx.SetContext(ctx)
+	return c.Heartbeat(&withCtx)
+}
+
+// ImportInstances 外部实例批量导入
+func (c *providerAPI) ImportInstances(req *ImportInstancesRequest) (*model.ImportInstancesResponse, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.context.GetEngine().SyncImportInstances(&req.ImportInstancesRequest)
+}
+
+// EnterLameduck 进入lameduck状态，缩短自动心跳实例的TTL
+func (c *providerAPI) EnterLameduck() error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	return c.context.GetEngine().EnterLameduck()
+}
+
+// TrackRequest 记录一个开始处理的服务端请求，API销毁后不再记录
+func (c *providerAPI) TrackRequest() func() {
+	if err := checkAvailable(c); err != nil {
+		return func() {}
+	}
+	return c.context.GetEngine().TrackServerRequest()
+}
+
+// GracefulShutdown 隔离实例、等待在途请求处理完成后反注册实例
+func (c *providerAPI) GracefulShutdown(ctx context.Context) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	return c.context.GetEngine().GracefulShutdown(ctx)
+}
+
 // SDKContext 获取SDK上下文
 func (c *providerAPI) SDKContext() SDKContext {
 	return c.context
@@ -0,0 +1,180 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// NewRequestBudget 按照consumer.requestBudget配置创建单次逻辑请求的尝试预算，
+// 总耗时上限为单次请求超时时间baseTimeout乘以配置的倍数，baseTimeout为0时不限制总耗时
+func NewRequestBudget(sdkCtx SDKContext, baseTimeout time.Duration) *model.RequestBudget {
+	budgetCfg := sdkCtx.GetConfig().GetConsumer().GetRequestBudget()
+	maxDuration := time.Duration(float64(baseTimeout) * budgetCfg.GetTimeoutMultiplier())
+	return model.NewRequestBudget(budgetCfg.GetMaxAttempts(), maxDuration)
+}
+
+// DoWithRetry 在尝试预算内顺序重试调用f直到成功，每次尝试都经过熔断检查，f的args参数为从0开始的尝试序号。
+// reqCtx.Budget为空时按照配置创建不限制总耗时的预算；预算耗尽时返回的错误可通过
+// errors.Is(err, model.ErrRequestBudgetExhausted) 判断
+func DoWithRetry(ctx context.Context, sdkCtx SDKContext, reqCtx *RequestContext,
+	f model.CustomerFunction) (interface{}, error) {
+	budget := ensureRequestBudget(sdkCtx, reqCtx)
+	decorator := sdkCtx.GetEngine().MakeFunctionDecorator(f, &reqCtx.RequestContext)
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := withBudgetDeadline(ctx, budget)
+		ret, aborted, err := decorator(attemptCtx, attempt)
+		cancel()
+		if aborted == nil && err == nil {
+			reportRequestAttempts(sdkCtx, reqCtx, budget, nil)
+			return ret, nil
+		}
+		if aborted != nil {
+			err = budgetAbortedError(aborted, lastErr)
+			reportRequestAttempts(sdkCtx, reqCtx, budget, err)
+			return nil, err
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			reportRequestAttempts(sdkCtx, reqCtx, budget, lastErr)
+			return nil, lastErr
+		}
+	}
+}
+
+// DoWithHedge 在尝试预算内发起对冲请求：已发出的请求在hedgeDelay内均未返回时再发起一次请求，
+// 请求失败时立即发起下一次请求，返回最先成功的结果并取消其余请求。f的args参数为从0开始的尝试序号
+func DoWithHedge(ctx context.Context, sdkCtx SDKContext, reqCtx *RequestContext, hedgeDelay time.Duration,
+	f model.CustomerFunction) (interface{}, error) {
+	budget := ensureRequestBudget(sdkCtx, reqCtx)
+	decorator := sdkCtx.GetEngine().MakeFunctionDecorator(f, &reqCtx.RequestContext)
+	hedgeCtx, cancel := withBudgetDeadline(ctx, budget)
+	defer cancel()
+
+	type attemptResult struct {
+		ret     interface{}
+		aborted *model.CallAborted
+		err     error
+	}
+	results := make(chan *attemptResult)
+	var attempt, inflight int
+	launch := func() {
+		go func(attempt int) {
+			ret, aborted, err := decorator(hedgeCtx, attempt)
+			select {
+			case results <- &attemptResult{ret: ret, aborted: aborted, err: err}:
+			case <-hedgeCtx.Done():
+			}
+		}(attempt)
+		attempt++
+		inflight++
+	}
+	launch()
+	timer := time.NewTimer(hedgeDelay)
+	defer timer.Stop()
+	var (
+		lastErr   error
+		launching = true
+	)
+	for inflight > 0 {
+		select {
+		case result := <-results:
+			inflight--
+			if result.aborted == nil && result.err == nil {
+				reportRequestAttempts(sdkCtx, reqCtx, budget, nil)
+				return result.ret, nil
+			}
+			if result.aborted != nil {
+				// 熔断或预算耗尽，不再发起新的请求，等待已发出的请求返回
+				launching = false
+				lastErr = budgetAbortedError(result.aborted, lastErr)
+				continue
+			}
+			lastErr = result.err
+			if launching && inflight == 0 && hedgeCtx.Err() == nil {
+				launch()
+			}
+		case <-timer.C:
+			if launching && !budget.Exhausted() {
+				launch()
+				timer.Reset(hedgeDelay)
+			}
+		case <-hedgeCtx.Done():
+			if lastErr == nil {
+				lastErr = hedgeCtx.Err()
+			}
+			reportRequestAttempts(sdkCtx, reqCtx, budget, lastErr)
+			return nil, lastErr
+		}
+	}
+	reportRequestAttempts(sdkCtx, reqCtx, budget, lastErr)
+	return nil, lastErr
+}
+
+// ensureRequestBudget 请求未指定预算时按照配置创建
+func ensureRequestBudget(sdkCtx SDKContext, reqCtx *RequestContext) *model.RequestBudget {
+	if reqCtx.Budget == nil {
+		reqCtx.Budget = NewRequestBudget(sdkCtx, 0)
+	}
+	return reqCtx.Budget
+}
+
+// withBudgetDeadline 将预算的截止时间设置到请求上下文中
+func withBudgetDeadline(ctx context.Context, budget *model.RequestBudget) (context.Context, context.CancelFunc) {
+	if deadline, ok := budget.Deadline(); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// budgetAbortedError 预算耗尽时附带最后一次尝试的错误
+func budgetAbortedError(aborted *model.CallAborted, lastErr error) error {
+	err := aborted.GetError()
+	if errors.Is(err, model.ErrRequestBudgetExhausted) && lastErr != nil {
+		return fmt.Errorf("%w, last error: %v", err, lastErr)
+	}
+	return err
+}
+
+// reportRequestAttempts 逻辑请求结束后记录访问日志并上报尝试次数统计
+func reportRequestAttempts(sdkCtx SDKContext, reqCtx *RequestContext, budget *model.RequestBudget, err error) {
+	gauge := &model.RequestAttemptsGauge{
+		Method:    reqCtx.Method,
+		Attempts:  budget.Attempts(),
+		Success:   err == nil,
+		Exhausted: errors.Is(err, model.ErrRequestBudgetExhausted),
+	}
+	if reqCtx.Callee != nil {
+		gauge.Namespace = reqCtx.Callee.Namespace
+		gauge.Service = reqCtx.Callee.Service
+	}
+	log.GetStatLogger().Infof("[RequestBudget] callee %s::%s, method %s, attempts_total %d, max_attempts %d, "+
+		"elapsed %v, success %v, exhausted %v", gauge.Namespace, gauge.Service, gauge.Method, gauge.Attempts,
+		budget.MaxAttempts(), budget.Elapsed(), gauge.Success, gauge.Exhausted)
+	if reportErr := sdkCtx.GetEngine().SyncReportStat(model.RequestAttemptsStat, gauge); reportErr != nil {
+		log.GetBaseLogger().Errorf("fail to report request attempts of %s::%s: %v",
+			gauge.Namespace, gauge.Service, reportErr)
+	}
+}
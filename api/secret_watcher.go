@@ -0,0 +1,40 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import "github.com/polarismesh/polaris-go/pkg/model"
+
+// SecretWatcher 监听配置中心中的证书及私钥文件，校验通过后原子地下发给注册的回调，用于mTLS证书轮转
+// 证书与私钥不匹配、证书过期或者尚未生效时拒绝下发，继续使用上一份通过校验的证书；
+// 证书与私钥分别发布时，中间状态会被拒绝，两者都更新后再下发
+type SecretWatcher interface {
+	SDKOwner
+	// GetSecret 获取当前生效的证书
+	GetSecret() *model.TLSSecret
+	// AddReloader 注册证书变更回调，注册时立即以当前生效的证书回调一次
+	AddReloader(reloader model.TLSReloader)
+	// Destroy 停止下发证书，通过默认配置创建时会销毁内部创建的SDK上下文
+	Destroy()
+}
+
+var (
+	// NewSecretWatcher 通过默认配置创建证书监听器
+	NewSecretWatcher = newSecretWatcher
+	// NewSecretWatcherByContext 通过上下文创建证书监听器
+	NewSecretWatcherByContext = newSecretWatcherByContext
+)
@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/secret"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// secretWatcher 证书监听器实现
+type secretWatcher struct {
+	context    SDKContext
+	ownContext bool
+	files      model.SecretFiles
+	certFile   model.ConfigFile
+	keyFile    model.ConfigFile
+	caFile     model.ConfigFile
+
+	// 串行化证书校验及回调下发
+	lock      sync.Mutex
+	current   atomic.Value
+	delivered [3]string
+	reloaders []model.TLSReloader
+	destroyed uint32
+}
+
+// newSecretWatcher 通过默认配置创建证书监听器
+func newSecretWatcher(files model.SecretFiles) (SecretWatcher, error) {
+	context, err := InitContextByConfig(config.NewDefaultConfigurationWithDomain())
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := newSecretWatcherByContext(context, files)
+	if err != nil {
+		context.Destroy()
+		return nil, err
+	}
+	watcher.(*secretWatcher).ownContext = true
+	return watcher, nil
+}
+
+// newSecretWatcherByContext 通过上下文创建证书监听器，初始证书不合法时返回错误
+func newSecretWatcherByContext(context SDKContext, files model.SecretFiles) (SecretWatcher, error) {
+	if len(files.Namespace) == 0 || len(files.FileGroup) == 0 ||
+		len(files.CertFile) == 0 || len(files.KeyFile) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"secret: namespace, fileGroup, certFile and keyFile should not be empty")
+	}
+	w := &secretWatcher{
+		context: context,
+		files:   files,
+	}
+	var err error
+	if w.certFile, err = w.subscribe(files.CertFile); err != nil {
+		return nil, err
+	}
+	if w.keyFile, err = w.subscribe(files.KeyFile); err != nil {
+		return nil, err
+	}
+	if len(files.CAFile) > 0 {
+		if w.caFile, err = w.subscribe(files.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	contents := w.contents()
+	current, err := secret.Parse(contents[0], contents[1], contents[2], time.Now())
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"secret: invalid certificate in %s/%s: %v", files.Namespace, files.FileGroup, err)
+	}
+	w.current.Store(current)
+	w.delivered = contents
+	for _, file := range []model.ConfigFile{w.certFile, w.keyFile, w.caFile} {
+		if file != nil {
+			file.AddChangeListener(w.onChange)
+		}
+	}
+	return w, nil
+}
+
+func (w *secretWatcher) subscribe(fileName string) (model.ConfigFile, error) {
+	return w.context.GetEngine().SyncGetConfigFile(&model.GetConfigFileRequest{
+		Namespace: w.files.Namespace,
+		FileGroup: w.files.FileGroup,
+		FileName:  fileName,
+		Subscribe: true,
+	})
+}
+
+// contents 获取证书、私钥及CA文件的当前内容
+func (w *secretWatcher) contents() [3]string {
+	var contents [3]string
+	contents[0] = w.certFile.GetContent()
+	contents[1] = w.keyFile.GetContent()
+	if w.caFile != nil {
+		contents[2] = w.caFile.GetContent()
+	}
+	return contents
+}
+
+// onChange 任一文件变更后重新校验整套证书，通过校验且内容变化时下发给回调
+func (w *secretWatcher) onChange(event model.ConfigFileChangeEvent) {
+	if atomic.LoadUint32(&w.destroyed) == 1 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	contents := w.contents()
+	if contents == w.delivered {
+		return
+	}
+	next, err := secret.Parse(contents[0], contents[1], contents[2], time.Now())
+	if err != nil {
+		log.GetBaseLogger().Warnf("[Secret] refuse to deliver certificate from %s/%s after %s changed, keep previous: %v",
+			w.files.Namespace, w.files.FileGroup, event.ConfigFileMetadata.GetFileName(), err)
+		return
+	}
+	w.current.Store(next)
+	w.delivered = contents
+	log.GetBaseLogger().Infof("[Secret] deliver certificate %s from %s/%s, not after %v",
+		next.Leaf.Subject, w.files.Namespace, w.files.FileGroup, next.NotAfter)
+	for _, reloader := range w.reloaders {
+		w.invoke(reloader, next)
+	}
+}
+
+func (w *secretWatcher) invoke(reloader model.TLSReloader, current *model.TLSSecret) {
+	if err := reloader(current); err != nil {
+		log.GetBaseLogger().Errorf("[Secret] fail to reload certificate from %s/%s: %v",
+			w.files.Namespace, w.files.FileGroup, err)
+	}
+}
+
+// GetSecret 获取当前生效的证书
+func (w *secretWatcher) GetSecret() *model.TLSSecret {
+	return w.current.Load().(*model.TLSSecret)
+}
+
+// AddReloader 注册证书变更回调
+func (w *secretWatcher) AddReloader(reloader model.TLSReloader) {
+	if reloader == nil {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.reloaders = append(w.reloaders, reloader)
+	w.invoke(reloader, w.GetSecret())
+}
+
+// Destroy 停止下发证书
+func (w *secretWatcher) Destroy() {
+	if !atomic.CompareAndSwapUint32(&w.destroyed, 0, 1) {
+		return
+	}
+	if w.ownContext {
+		w.context.Destroy()
+	}
+}
+
+// SDKContext 获取SDK上下文
+func (w *secretWatcher) SDKContext() SDKContext {
+	return w.context
+}
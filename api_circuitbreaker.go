@@ -78,6 +78,16 @@ func (c *circuitBreakerAPI) Report(stat *model.ResourceStat) error {
 	return c.rawAPI.Report(stat)
 }
 
+// ReportExternalHealth 上报应用自行判定的实例健康状态
+func (c *circuitBreakerAPI) ReportExternalHealth(instanceKey model.InstanceKey, healthy bool, reason string) error {
+	return c.rawAPI.ReportExternalHealth(instanceKey, healthy, reason)
+}
+
+// ClearQuarantine 清除熔断实例隔离名单
+func (c *circuitBreakerAPI) ClearQuarantine(instanceKeys ...model.InstanceKey) int {
+	return c.rawAPI.ClearQuarantine(instanceKeys...)
+}
+
 // MakeFunctionDecorator
 func (c *circuitBreakerAPI) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *api.RequestContext) model.DecoratorFunction {
 	return c.rawAPI.MakeFunctionDecorator(f, reqCtx)
@@ -78,19 +78,24 @@ func (c *configAPI) FetchConfigFile(req *GetConfigFileRequest) (model.ConfigFile
 	return c.rawAPI.FetchConfigFile((*api.GetConfigFileRequest)(req))
 }
 
+// GetConfigFiles 批量获取配置文件
+func (c *configAPI) GetConfigFiles(namespace, fileGroup string, fileNames []string) (map[string]model.ConfigFile, error) {
+	return c.rawAPI.GetConfigFiles(namespace, fileGroup, fileNames)
+}
+
 // CreateConfigFile 创建配置文件
-func (c *configAPI) CreateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return c.rawAPI.CreateConfigFile(namespace, fileGroup, fileName, content)
+func (c *configAPI) CreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	return c.rawAPI.CreateConfigFile(namespace, fileGroup, fileName, content, opts...)
 }
 
 // UpdateConfigFile 更新配置文件
-func (c *configAPI) UpdateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return c.rawAPI.UpdateConfigFile(namespace, fileGroup, fileName, content)
+func (c *configAPI) UpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	return c.rawAPI.UpdateConfigFile(namespace, fileGroup, fileName, content, opts...)
 }
 
 // PublishConfigFile 发布配置文件
-func (c *configAPI) PublishConfigFile(namespace, fileGroup, fileName string) error {
-	return c.rawAPI.PublishConfigFile(namespace, fileGroup, fileName)
+func (c *configAPI) PublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error {
+	return c.rawAPI.PublishConfigFile(namespace, fileGroup, fileName, opts...)
 }
 
 // SDKContext 获取SDK上下文
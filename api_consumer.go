@@ -18,6 +18,9 @@
 package polaris
 
 import (
+	"context"
+	"time"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -48,6 +51,24 @@ func (c *consumerAPI) GetAllInstances(req *GetAllInstancesRequest) (*model.Insta
 	return c.rawAPI.GetAllInstances((*api.GetAllInstancesRequest)(req))
 }
 
+// GetOneInstanceWithContext 使用调用方上下文同步获取单个服务
+func (c *consumerAPI) GetOneInstanceWithContext(
+	ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	return c.rawAPI.GetOneInstanceWithContext(ctx, (*api.GetOneInstanceRequest)(req))
+}
+
+// GetInstancesWithContext 使用调用方上下文同步获取可用的服务列表
+func (c *consumerAPI) GetInstancesWithContext(
+	ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetInstancesWithContext(ctx, (*api.GetInstancesRequest)(req))
+}
+
+// GetAllInstancesWithContext 使用调用方上下文同步获取完整的服务列表
+func (c *consumerAPI) GetAllInstancesWithContext(
+	ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetAllInstancesWithContext(ctx, (*api.GetAllInstancesRequest)(req))
+}
+
 // GetRouteRule 同步获取服务路由规则
 func (c *consumerAPI) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
 	return c.rawAPI.GetRouteRule((*api.GetServiceRuleRequest)(req))
@@ -83,6 +104,52 @@ func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.Wat
 	return c.rawAPI.WatchAllServices((*api.WatchAllServicesRequest)(req))
 }
 
+// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件
+func (c *consumerAPI) ExportSnapshot(path string) error {
+	return c.rawAPI.ExportSnapshot(path)
+}
+
+// ImportSnapshot 从快照文件导入服务、实例及规则
+func (c *consumerAPI) ImportSnapshot(path string) error {
+	return c.rawAPI.ImportSnapshot(path)
+}
+
+// WaitForPeers 等待服务的健康实例数达到下限
+func (c *consumerAPI) WaitForPeers(ctx context.Context, req *WaitForPeersRequest) error {
+	return c.rawAPI.WaitForPeers(ctx, (*api.WaitForPeersRequest)(req))
+}
+
+// GetInstancesDiff 获取服务实例自 sinceRevision 以来的增量变更
+func (c *consumerAPI) GetInstancesDiff(svcKey model.ServiceKey, sinceRevision string) (*model.InstancesDiffResponse, error) {
+	return c.rawAPI.GetInstancesDiff(svcKey, sinceRevision)
+}
+
+// QueryInstances 直接向服务端查询实例，支持过滤及分页
+func (c *consumerAPI) QueryInstances(req *QueryInstancesRequest) (*model.QueryInstancesResponse, error) {
+	return c.rawAPI.QueryInstances((*api.QueryInstancesRequest)(req))
+}
+
+// ValidateContract 按照被调服务契约校验请求的方法及路径
+func (c *consumerAPI) ValidateContract(req *ValidateContractRequest) (*model.ContractValidationResult, error) {
+	return c.rawAPI.ValidateContract((*api.ValidateContractRequest)(req))
+}
+
+// ReportShadowComparison 上报影子请求与主请求应答的对比结果
+func (c *consumerAPI) ReportShadowComparison(req *ReportShadowComparisonRequest) error {
+	return c.rawAPI.ReportShadowComparison((*api.ReportShadowComparisonRequest)(req))
+}
+
+// OverrideInstanceWeight 在本地覆盖实例参与负载均衡的权重，到期后自动失效
+func (c *consumerAPI) OverrideInstanceWeight(svcKey model.ServiceKey, instanceID string, weight int,
+	ttl time.Duration) error {
+	return c.rawAPI.OverrideInstanceWeight(svcKey, instanceID, weight, ttl)
+}
+
+// ClearInstanceWeightOverride 清除实例的本地权重覆盖
+func (c *consumerAPI) ClearInstanceWeightOverride(svcKey model.ServiceKey, instanceID string) error {
+	return c.rawAPI.ClearInstanceWeightOverride(svcKey, instanceID)
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *consumerAPI) Destroy() {
 	c.rawAPI.Destroy()
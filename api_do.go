@@ -0,0 +1,31 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"context"
+
+	"github.com/polarismesh/polaris-go/api"
+)
+
+// Do 对服务发起一次带完整治理能力的逻辑请求，依次完成限流、接口熔断检查、实例选择、
+// 执行fn以及调用结果上报，失败时在尝试预算内重试，最终失败时调用降级函数。
+// sdkCtx可通过任意API对象的SDKContext()获取
+func Do(ctx context.Context, sdkCtx api.SDKContext, req *DoRequest, fn DoFunc) error {
+	return api.Do(ctx, sdkCtx, (*api.DoRequest)(req), fn)
+}
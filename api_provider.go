@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -57,6 +59,42 @@ func (p *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
 	return p.rawAPI.Heartbeat((*api.InstanceHeartbeatRequest)(instance))
 }
 
+// RegisterInstanceWithContext 使用调用方上下文注册服务实例，并开启后台心跳
+func (p *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.rawAPI.RegisterInstanceWithContext(ctx, (*api.InstanceRegisterRequest)(instance))
+}
+
+// DeregisterWithContext 使用调用方上下文反注册服务实例
+func (p *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	return p.rawAPI.DeregisterWithContext(ctx, (*api.InstanceDeRegisterRequest)(instance))
+}
+
+// HeartbeatWithContext 使用调用方上下文上报心跳
+func (p *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	return p.rawAPI.HeartbeatWithContext(ctx, (*api.InstanceHeartbeatRequest)(instance))
+}
+
+// ImportInstances 将外部系统的实例全集同步到北极星
+func (p *providerAPI) ImportInstances(req *ImportInstancesRequest) (*model.ImportInstancesResponse, error) {
+	return p.rawAPI.ImportInstances((*api.ImportInstancesRequest)(req))
+}
+
+// EnterLameduck 进入lameduck状态，缩短自动心跳实例的TTL
+func (p *providerAPI) EnterLameduck() error {
+	return p.rawAPI.EnterLameduck()
+}
+
+// TrackRequest 记录一个开始处理的服务端请求，请求处理完成时调用返回的函数
+func (p *providerAPI) TrackRequest() func() {
+	return p.rawAPI.TrackRequest()
+}
+
+// GracefulShutdown 隔离实例、等待在途请求处理完成后反注册实例
+func (p *providerAPI) GracefulShutdown(ctx context.Context) error {
+	return p.rawAPI.GracefulShutdown(ctx)
+}
+
 // Destroy the api is destroyed and cannot be called again
 func (p *providerAPI) Destroy() {
 	p.rawAPI.Destroy()
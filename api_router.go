@@ -50,6 +50,26 @@ func (r *routerAPI) ProcessLoadBalance(request *ProcessLoadBalanceRequest) (*mod
 	return r.sdkCtx.GetEngine().ProcessLoadBalance(&request.ProcessLoadBalanceRequest)
 }
 
+// SetSplit set the local traffic split of a service
+func (r *routerAPI) SetSplit(request *TrafficSplitRequest) error {
+	if err := api.CheckAvailable(r); err != nil {
+		return err
+	}
+	if request == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "TrafficSplitRequest can not be nil")
+	}
+	return r.sdkCtx.GetEngine().SetTrafficSplit(&request.TrafficSplitRequest)
+}
+
+// ClearSplit clear the local traffic split of a service
+func (r *routerAPI) ClearSplit(namespace, service string) error {
+	if err := api.CheckAvailable(r); err != nil {
+		return err
+	}
+	r.sdkCtx.GetEngine().ClearTrafficSplit(model.ServiceKey{Namespace: namespace, Service: service})
+	return nil
+}
+
 // SDKContext getting the sdk context
 func (r *routerAPI) SDKContext() api.SDKContext {
 	return r.sdkCtx
@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package compat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type stubCtxKey struct{}
+
+type stubConsumerAPI struct {
+	api.ConsumerAPI
+	ctx context.Context
+}
+
+func (s *stubConsumerAPI) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	s.ctx = req.Context()
+	return &model.OneInstanceResponse{}, nil
+}
+
+func TestConsumerShim(t *testing.T) {
+	stub := &stubConsumerAPI{}
+	consumer := WrapConsumerAPI(stub)
+	req := &GetOneInstanceRequest{}
+	req.Namespace = "Test"
+	req.Service = "echo"
+	ctx := context.WithValue(context.Background(), stubCtxKey{}, "caller")
+	req.SetContext(ctx)
+	if _, err := consumer.GetOneInstance(req); err != nil {
+		t.Fatal(err)
+	}
+	if stub.ctx != ctx {
+		t.Fatal("expect caller context kept when delegated")
+	}
+	if consumer.Unwrap() != stub {
+		t.Fatal("expect underlying api returned")
+	}
+}
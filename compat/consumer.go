@@ -0,0 +1,150 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package compat 固定 v1 风格的公开 API 签名（不携带 context 的同步调用以及原有的请求结构体），
+// 实现上委托给 api 包中对应的接口，请求中已设置的上下文会原样透传，业务可以在升级 SDK 大版本时先切换到本包，再逐个调用点迁移到新接口。
+// 目前新旧请求模型一致，请求结构体直接使用类型别名，模型出现差异时在本包内完成转换
+package compat
+
+import (
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SchemaVersion 本包固定的公开 API 版本
+const SchemaVersion = "v1"
+
+type (
+	// GetOneInstanceRequest v1 获取单个服务实例请求
+	GetOneInstanceRequest = api.GetOneInstanceRequest
+	// GetInstancesRequest v1 获取可用服务实例请求
+	GetInstancesRequest = api.GetInstancesRequest
+	// GetAllInstancesRequest v1 获取全部服务实例请求
+	GetAllInstancesRequest = api.GetAllInstancesRequest
+	// GetServiceRuleRequest v1 获取服务规则请求
+	GetServiceRuleRequest = api.GetServiceRuleRequest
+	// ServiceCallResult v1 服务调用结果
+	ServiceCallResult = api.ServiceCallResult
+	// GetServicesRequest v1 批量获取服务请求
+	GetServicesRequest = api.GetServicesRequest
+	// InitCalleeServiceRequest v1 初始化被调服务请求
+	InitCalleeServiceRequest = api.InitCalleeServiceRequest
+)
+
+// ConsumerAPI v1 风格的主调端接口
+type ConsumerAPI interface {
+	// GetOneInstance 获取单个服务（会执行路由链与负载均衡，获取负载均衡后的服务实例）
+	GetOneInstance(req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstances 获取可用的服务列表（会执行路由链，默认去掉隔离以及不健康的服务实例）
+	GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstances 获取完整的服务列表（包括隔离及不健康的服务实例）
+	GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// GetRouteRule 同步获取服务路由规则
+	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// UpdateServiceCallResult 上报服务调用结果
+	UpdateServiceCallResult(req *ServiceCallResult) error
+	// GetServices 根据业务同步获取批量服务
+	GetServices(req *GetServicesRequest) (*model.ServicesResponse, error)
+	// InitCalleeService 初始化服务运行中需要的被调服务
+	InitCalleeService(req *InitCalleeServiceRequest) error
+	// Destroy 销毁API，销毁后无法再进行调用
+	Destroy()
+	// Unwrap 获取底层的 api.ConsumerAPI，用于逐步迁移到新接口
+	Unwrap() api.ConsumerAPI
+}
+
+// NewConsumerAPI 通过以默认域名为埋点server的默认配置创建ConsumerAPI
+func NewConsumerAPI() (ConsumerAPI, error) {
+	return wrapConsumer(api.NewConsumerAPI())
+}
+
+// NewConsumerAPIByFile 通过配置文件创建ConsumerAPI
+func NewConsumerAPIByFile(path string) (ConsumerAPI, error) {
+	return wrapConsumer(api.NewConsumerAPIByFile(path))
+}
+
+// NewConsumerAPIByConfig 通过配置对象创建ConsumerAPI
+func NewConsumerAPIByConfig(cfg config.Configuration) (ConsumerAPI, error) {
+	return wrapConsumer(api.NewConsumerAPIByConfig(cfg))
+}
+
+// NewConsumerAPIByContext 通过上下文创建ConsumerAPI
+func NewConsumerAPIByContext(sdkCtx api.SDKContext) ConsumerAPI {
+	return WrapConsumerAPI(api.NewConsumerAPIByContext(sdkCtx))
+}
+
+// WrapConsumerAPI 将 api.ConsumerAPI 包装为 v1 风格的接口
+func WrapConsumerAPI(consumer api.ConsumerAPI) ConsumerAPI {
+	return &consumerShim{consumer: consumer}
+}
+
+func wrapConsumer(consumer api.ConsumerAPI, err error) (ConsumerAPI, error) {
+	if err != nil {
+		return nil, err
+	}
+	return WrapConsumerAPI(consumer), nil
+}
+
+type consumerShim struct {
+	consumer api.ConsumerAPI
+}
+
+// GetOneInstance 获取单个服务实例
+func (c *consumerShim) GetOneInstance(req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	return c.consumer.GetOneInstance(req)
+}
+
+// GetInstances 获取可用的服务实例
+func (c *consumerShim) GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	return c.consumer.GetInstances(req)
+}
+
+// GetAllInstances 获取完整的服务实例
+func (c *consumerShim) GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.consumer.GetAllInstances(req)
+}
+
+// GetRouteRule 同步获取服务路由规则
+func (c *consumerShim) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return c.consumer.GetRouteRule(req)
+}
+
+// UpdateServiceCallResult 上报服务调用结果
+func (c *consumerShim) UpdateServiceCallResult(req *ServiceCallResult) error {
+	return c.consumer.UpdateServiceCallResult(req)
+}
+
+// GetServices 根据业务同步获取批量服务
+func (c *consumerShim) GetServices(req *GetServicesRequest) (*model.ServicesResponse, error) {
+	return c.consumer.GetServices(req)
+}
+
+// InitCalleeService 初始化服务运行中需要的被调服务
+func (c *consumerShim) InitCalleeService(req *InitCalleeServiceRequest) error {
+	return c.consumer.InitCalleeService(req)
+}
+
+// Destroy 销毁API
+func (c *consumerShim) Destroy() {
+	c.consumer.Destroy()
+}
+
+// Unwrap 获取底层的 api.ConsumerAPI
+func (c *consumerShim) Unwrap() api.ConsumerAPI {
+	return c.consumer
+}
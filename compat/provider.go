@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package compat
+
+import (
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type (
+	// InstanceRegisterRequest v1 注册服务实例请求
+	InstanceRegisterRequest = api.InstanceRegisterRequest
+	// InstanceDeRegisterRequest v1 反注册服务实例请求
+	InstanceDeRegisterRequest = api.InstanceDeRegisterRequest
+	// InstanceHeartbeatRequest v1 心跳上报请求
+	InstanceHeartbeatRequest = api.InstanceHeartbeatRequest
+)
+
+// ProviderAPI v1 风格的被调端接口
+type ProviderAPI interface {
+	// RegisterInstance 注册服务实例并开启后台心跳
+	RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// Register 同步注册服务实例，不开启后台心跳
+	Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// Deregister 同步反注册服务实例
+	Deregister(instance *InstanceDeRegisterRequest) error
+	// Heartbeat 上报心跳
+	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// Destroy 销毁API，销毁后无法再进行调用
+	Destroy()
+	// Unwrap 获取底层的 api.ProviderAPI，用于逐步迁移到新接口
+	Unwrap() api.ProviderAPI
+}
+
+// NewProviderAPI 通过以默认域名为埋点server的默认配置创建ProviderAPI
+func NewProviderAPI() (ProviderAPI, error) {
+	return wrapProvider(api.NewProviderAPI())
+}
+
+// NewProviderAPIByFile 通过配置文件创建ProviderAPI
+func NewProviderAPIByFile(path string) (ProviderAPI, error) {
+	return wrapProvider(api.NewProviderAPIByFile(path))
+}
+
+// NewProviderAPIByConfig 通过配置对象创建ProviderAPI
+func NewProviderAPIByConfig(cfg config.Configuration) (ProviderAPI, error) {
+	return wrapProvider(api.NewProviderAPIByConfig(cfg))
+}
+
+// NewProviderAPIByContext 通过上下文创建ProviderAPI
+func NewProviderAPIByContext(sdkCtx api.SDKContext) ProviderAPI {
+	return WrapProviderAPI(api.NewProviderAPIByContext(sdkCtx))
+}
+
+// WrapProviderAPI 将 api.ProviderAPI 包装为 v1 风格的接口
+func WrapProviderAPI(provider api.ProviderAPI) ProviderAPI {
+	return &providerShim{provider: provider}
+}
+
+func wrapProvider(provider api.ProviderAPI, err error) (ProviderAPI, error) {
+	if err != nil {
+		return nil, err
+	}
+	return WrapProviderAPI(provider), nil
+}
+
+type providerShim struct {
+	provider api.ProviderAPI
+}
+
+// RegisterInstance 注册服务实例并开启后台心跳
+func (p *providerShim) RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.provider.RegisterInstance(instance)
+}
+
+// Register 同步注册服务实例
+func (p *providerShim) Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.provider.Register(instance)
+}
+
+// Deregister 同步反注册服务实例
+func (p *providerShim) Deregister(instance *InstanceDeRegisterRequest) error {
+	return p.provider.Deregister(instance)
+}
+
+// Heartbeat 上报心跳
+func (p *providerShim) Heartbeat(instance *InstanceHeartbeatRequest) error {
+	return p.provider.Heartbeat(instance)
+}
+
+// Destroy 销毁API
+func (p *providerShim) Destroy() {
+	p.provider.Destroy()
+}
+
+// Unwrap 获取底层的 api.ProviderAPI
+func (p *providerShim) Unwrap() api.ProviderAPI {
+	return p.provider
+}
@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// BalancerName 北极星负载均衡器名称，由 NewResolverBuilder 创建的resolver自动选用
+const BalancerName = "polaris"
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(BalancerName, &pickerBuilder{}, base.Config{HealthCheck: false}))
+}
+
+// DialOptions 使用北极星进行服务发现、路由、负载均衡、熔断及连接数限制的grpc拨号选项，
+// 通过 grpc.Dial("polaris://namespace/service", polarisgrpc.DialOptions(consumer)...) 使用
+func DialOptions(consumer api.ConsumerAPI) []grpc.DialOption {
+	limiter := newConnectionLimiter(consumer)
+	return []grpc.DialOption{
+		grpc.WithResolvers(&resolverBuilder{consumer: consumer, limiter: limiter}),
+		grpc.WithContextDialer(limiter.DialContext),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(consumer)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(consumer)),
+	}
+}
+
+type pickerBuilder struct{}
+
+// Build 按照就绪的SubConn创建picker
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	p := &picker{subConns: make(map[string]balancer.SubConn, len(info.ReadySCs))}
+	for subConn, subConnInfo := range info.ReadySCs {
+		if attribute, ok := subConnInfo.Address.Attributes.Value(addressAttributeKey{}).(*addressAttribute); ok {
+			p.attribute = attribute
+		}
+		p.subConns[subConnInfo.Address.Addr] = subConn
+	}
+	if p.attribute == nil {
+		return base.NewErrPicker(status.Error(codes.Unavailable, "polaris balancer requires polaris resolver"))
+	}
+	return p
+}
+
+// picker 每次调用通过 GetOneInstance 完成路由、熔断过滤及负载均衡，再映射到对应的SubConn
+type picker struct {
+	attribute *addressAttribute
+	subConns  map[string]balancer.SubConn
+}
+
+// Pick 选择本次调用的实例，选中的实例尚未就绪时返回 ErrNoSubConnAvailable，由grpc等待连接就绪后使用新的picker重新选择
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	req := &api.GetOneInstanceRequest{}
+	req.Namespace = p.attribute.svcKey.Namespace
+	req.Service = p.attribute.svcKey.Service
+	req.Arguments = []model.Argument{model.BuildMethodArgument(info.FullMethodName)}
+	req.SetContext(info.Ctx)
+	resp, err := p.attribute.consumer.GetOneInstance(req)
+	if err != nil {
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable, "fail to pick instance of %s: %v",
+			p.attribute.svcKey, err)
+	}
+	instance := resp.GetInstance()
+	subConn, ok := p.subConns[instanceAddr(instance)]
+	if !ok {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	if _, ok := info.Ctx.Value(instanceHolderKey{}).(*instanceHolder); ok {
+		// 拦截器负责上报调用结果
+		SetPickedInstance(info.Ctx, instance)
+		return balancer.PickResult{SubConn: subConn}, nil
+	}
+	start := time.Now()
+	return balancer.PickResult{SubConn: subConn, Done: func(doneInfo balancer.DoneInfo) {
+		reportCallResult(p.attribute.consumer, instance, info.FullMethodName, time.Since(start), doneInfo.Err)
+	}}, nil
+}
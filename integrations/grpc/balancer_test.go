@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+type stubConsumerAPI struct {
+	api.ConsumerAPI
+	instance     model.Instance
+	allInstances []model.Instance
+	allCalls     int
+	mutex        sync.Mutex
+	results      []*api.ServiceCallResult
+}
+
+func (s *stubConsumerAPI) GetOneInstance(*api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = []model.Instance{s.instance}
+	return resp, nil
+}
+
+func (s *stubConsumerAPI) GetAllInstances(*api.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.allCalls++
+	return &model.InstancesResponse{Instances: s.allInstances}, nil
+}
+
+func (s *stubConsumerAPI) UpdateServiceCallResult(result *api.ServiceCallResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *stubConsumerAPI) reported() []*api.ServiceCallResult {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*api.ServiceCallResult(nil), s.results...)
+}
+
+type stubSubConn struct {
+	balancer.SubConn
+}
+
+func newTestInstance(host string, port uint32) model.Instance {
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Host: wrapperspb.String(host),
+		Port: wrapperspb.UInt32(port),
+	}, &model.ServiceKey{Namespace: "Test", Service: "echo"}, nil)
+}
+
+func TestPickerPick(t *testing.T) {
+	consumer := &stubConsumerAPI{instance: newTestInstance("127.0.0.1", 8080)}
+	attribute := &addressAttribute{consumer: consumer, svcKey: model.ServiceKey{Namespace: "Test", Service: "echo"}}
+	subConn := &stubSubConn{}
+	p := (&pickerBuilder{}).Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+		subConn: {Address: resolver.Address{
+			Addr:       "127.0.0.1:8080",
+			Attributes: attributes.New(addressAttributeKey{}, attribute),
+		}},
+	}})
+
+	result, err := p.Pick(balancer.PickInfo{FullMethodName: "/echo.Echo/Say", Ctx: context.Background()})
+	if err != nil || result.SubConn != subConn {
+		t.Fatalf("expect ready sub conn picked, got %v, %v", result.SubConn, err)
+	}
+	result.Done(balancer.DoneInfo{Err: status.Error(codes.Unavailable, "down")})
+	if len(consumer.results) != 1 || consumer.results[0].GetRetStatus() != model.RetFail {
+		t.Fatalf("expect failed call reported without interceptor, got %v", consumer.results)
+	}
+
+	ctx, holder := withInstanceHolder(context.Background())
+	if result, err = p.Pick(balancer.PickInfo{FullMethodName: "/echo.Echo/Say", Ctx: ctx}); err != nil ||
+		result.Done != nil || holder.get() != consumer.instance {
+		t.Fatalf("expect picked instance handed to interceptor, got %v", err)
+	}
+
+	consumer.instance = newTestInstance("127.0.0.2", 8080)
+	if _, err = p.Pick(balancer.PickInfo{Ctx: context.Background()}); err != balancer.ErrNoSubConnAvailable {
+		t.Fatalf("expect no sub conn available for instance not ready, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/integrations/respcache"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// CacheKeyExtractor 从调用中提取应答缓存键的请求标识，返回false时该调用不读写应答缓存。
+// 需要按用户缓存时，可在请求标识中加入经过鉴权的用户身份
+type CacheKeyExtractor func(ctx context.Context, method string, req proto.Message) (string, bool)
+
+// WithCacheKeyExtractor 设置应答缓存的请求标识提取方法，默认使用 DefaultCacheKeyExtractor
+func WithCacheKeyExtractor(extractor CacheKeyExtractor) Option {
+	return func(o *options) {
+		o.cacheKey = extractor
+	}
+}
+
+// DefaultCacheKeyExtractor 默认的应答缓存请求标识提取方法，使用请求序列化后的内容作为请求标识，
+// 出站元数据中带有 authorization 或者 cookie 的调用属于特定用户，不使用缓存
+func DefaultCacheKeyExtractor(ctx context.Context, method string, req proto.Message) (string, bool) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if len(md.Get("authorization")) > 0 || len(md.Get("cookie")) > 0 {
+			return "", false
+		}
+	}
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(req); err != nil {
+		return "", false
+	}
+	return string(buf.Bytes()), true
+}
+
+// UnaryClientCacheInterceptor 创建带应答微缓存的一元调用拦截器，只对 cache 中显式开启的方法生效：
+// 调用成功时缓存应答，方法熔断打开或者被调方返回 Unavailable、ResourceExhausted 时返回缓存中未过期的应答。
+// 使用 grpc.PerRPCCredentials 调用选项的调用属于特定用户，不使用缓存；
+// 连接级别的凭据对该连接上的所有调用相同，不影响缓存。
+// breakerAPI 不为空时同时进行方法级熔断检查及上报，方法名为完整的 /package.Service/Method
+func UnaryClientCacheInterceptor(cache *respcache.Cache, breakerAPI api.CircuitBreakerAPI,
+	opts ...Option) grpc.UnaryClientInterceptor {
+	o := options{cacheKey: DefaultCacheKeyExtractor}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if !cache.Cacheable(method) || hasPerRPCCredentials(callOpts) {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		reqMsg, reqOk := req.(proto.Message)
+		replyMsg, replyOk := reply.(proto.Message)
+		if !reqOk || !replyOk {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		namespace, service := o.namespace, o.service
+		if len(service) == 0 {
+			namespace, service = parseTarget(cc.Target())
+		}
+		request, ok := o.cacheKey(ctx, method, reqMsg)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		key := respcache.Key{Namespace: namespace, Service: service, Method: method, Request: request}
+
+		var resource model.Resource
+		var permit *model.BulkheadPermit
+		if breakerAPI != nil && len(service) > 0 {
+			var open bool
+			if resource, permit, open = checkMethodBreaker(breakerAPI, namespace, service, method); open {
+				if serveCachedReply(cache, key, replyMsg) {
+					return nil
+				}
+				return status.Errorf(codes.Unavailable, "circuit breaker open for %s", method)
+			}
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		if resource != nil {
+			code := status.Code(err)
+			stat := &model.ResourceStat{
+				Resource:  resource,
+				RetCode:   code.String(),
+				Delay:     time.Since(start),
+				RetStatus: RetStatusFromCode(code),
+				Permit:    permit,
+			}
+			if reportErr := breakerAPI.Report(stat); reportErr != nil {
+				log.GetBaseLogger().Warnf("[grpc] fail to report breaker stat for %s: %v", method, reportErr)
+			}
+		}
+		if err == nil {
+			cache.Put(key, proto.Clone(replyMsg), proto.Size(replyMsg))
+			return nil
+		}
+		if code := status.Code(err); code == codes.Unavailable || code == codes.ResourceExhausted {
+			if serveCachedReply(cache, key, replyMsg) {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// hasPerRPCCredentials 调用选项中是否带有单次调用的凭据
+func hasPerRPCCredentials(callOpts []grpc.CallOption) bool {
+	for _, callOpt := range callOpts {
+		if _, ok := callOpt.(grpc.PerRPCCredsCallOption); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMethodBreaker 检查方法级熔断，返回熔断是否打开，放通时同时返回需要上报调用结果的资源及占用的舱壁并发许可
+func checkMethodBreaker(breakerAPI api.CircuitBreakerAPI,
+	namespace, service, method string) (model.Resource, *model.BulkheadPermit, bool) {
+	resource, err := model.NewMethodResource(&model.ServiceKey{Namespace: namespace, Service: service}, nil, method)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[grpc] fail to build breaker resource for %s: %v", method, err)
+		return nil, nil, false
+	}
+	result, err := breakerAPI.Check(resource)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[grpc] fail to check breaker for %s: %v", method, err)
+		return nil, nil, false
+	}
+	if !result.Pass {
+		return nil, nil, true
+	}
+	return resource, result.Permit, false
+}
+
+// serveCachedReply 将缓存中未过期的应答写入reply
+func serveCachedReply(cache *respcache.Cache, key respcache.Key, reply proto.Message) bool {
+	cached, ok := cache.Get(key)
+	if !ok {
+		return false
+	}
+	reply.Reset()
+	proto.Merge(reply, cached.(proto.Message))
+	log.GetBaseLogger().Debugf("[grpc] serve cached reply for %s::%s %s", key.Namespace, key.Service, key.Method)
+	return true
+}
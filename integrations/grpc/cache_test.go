@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/integrations/respcache"
+)
+
+type userCredentials string
+
+func (u userCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": string(u)}, nil
+}
+
+func (u userCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// TestUnaryClientCacheIsolatesUsers 测试带有用户凭据的调用不会共享缓存的应答
+func TestUnaryClientCacheIsolatesUsers(t *testing.T) {
+	const method = "/echo.Echo/Get"
+	cache := respcache.New(respcache.WithMethods(method))
+	interceptor := UnaryClientCacheInterceptor(cache, nil, WithService("Test", "echo"))
+	var unavailable bool
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		if unavailable {
+			return status.Error(codes.Unavailable, "unavailable")
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		reply.(*wrapperspb.StringValue).Value = strings.Join(md.Get("authorization"), ",")
+		return nil
+	}
+	call := func(user string, opts ...grpc.CallOption) (string, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", user)
+		reply := &wrapperspb.StringValue{}
+		err := interceptor(ctx, method, wrapperspb.String("profile"), reply, nil, invoker, opts...)
+		return reply.Value, err
+	}
+	if _, err := call("alice"); err != nil {
+		t.Fatal(err)
+	}
+	creds := grpc.PerRPCCredentials(userCredentials("alice"))
+	if _, err := call("alice", creds); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expect calls with credentials not cached, got %d entries", cache.Len())
+	}
+	anonymous := &wrapperspb.StringValue{}
+	if err := interceptor(context.Background(), method, wrapperspb.String("profile"), anonymous, nil,
+		invoker); err != nil || cache.Len() != 1 {
+		t.Fatalf("expect anonymous call cached, got %d entries, %v", cache.Len(), err)
+	}
+
+	unavailable = true
+	if reply, err := call("bob"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expect bob not served alice's reply, got %q %v", reply, err)
+	}
+}
+
+// TestUnaryClientCacheKeyExtractor 测试自定义请求标识提取方法按用户区分缓存
+func TestUnaryClientCacheKeyExtractor(t *testing.T) {
+	const method = "/echo.Echo/Get"
+	cache := respcache.New(respcache.WithMethods(method))
+	interceptor := UnaryClientCacheInterceptor(cache, nil, WithService("Test", "echo"),
+		WithCacheKeyExtractor(func(ctx context.Context, method string, req proto.Message) (string, bool) {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			return md.Get("x-user")[0] + " " + req.(*wrapperspb.StringValue).Value, true
+		}))
+	var unavailable bool
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		if unavailable {
+			return status.Error(codes.Unavailable, "unavailable")
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		reply.(*wrapperspb.StringValue).Value = md.Get("x-user")[0]
+		return nil
+	}
+	call := func(user string) (string, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-user", user)
+		reply := &wrapperspb.StringValue{}
+		err := interceptor(ctx, method, wrapperspb.String("profile"), reply, nil, invoker)
+		return reply.Value, err
+	}
+	if _, err := call("alice"); err != nil {
+		t.Fatal(err)
+	}
+	unavailable = true
+	if reply, err := call("alice"); err != nil || reply != "alice" {
+		t.Fatalf("expect alice served from cache, got %q %v", reply, err)
+	}
+	if reply, err := call("bob"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expect bob not served alice's reply, got %q %v", reply, err)
+	}
+}
@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// ContextDialer 支持 unix domain socket 实例的拨号方法，可通过 grpc.WithContextDialer 使用，
+// 地址为 unix:///path/to/sock 时通过 unix domain socket 拨号，否则按 tcp 拨号
+func ContextDialer(ctx context.Context, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	if model.IsUnixSocketHost(addr) {
+		return dialer.DialContext(ctx, model.NetworkUnix, model.UnixSocketPath(addr))
+	}
+	return dialer.DialContext(ctx, model.NetworkTCP, addr)
+}
+
+// connectionLimiter 记录 resolver 解析出的地址所属的被调服务，拨号时按服务执行连接数限制
+// （consumer.servicesSpecific[].connectionLimit），连接关闭时归还许可
+type connectionLimiter struct {
+	consumer api.ConsumerAPI
+	mutex    sync.RWMutex
+	services map[string]model.ServiceKey
+}
+
+func newConnectionLimiter(consumer api.ConsumerAPI) *connectionLimiter {
+	return &connectionLimiter{consumer: consumer, services: make(map[string]model.ServiceKey)}
+}
+
+// update 使用服务最新的地址列表替换该服务原有的地址
+func (l *connectionLimiter) update(svcKey model.ServiceKey, addrs []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for addr, key := range l.services {
+		if key == svcKey {
+			delete(l.services, addr)
+		}
+	}
+	for _, addr := range addrs {
+		l.services[addr] = svcKey
+	}
+}
+
+// DialContext 获取连接许可后拨号，超过限制时返回 model.ErrConnectionLimited，未知地址不受限制
+func (l *connectionLimiter) DialContext(ctx context.Context, addr string) (net.Conn, error) {
+	l.mutex.RLock()
+	svcKey, ok := l.services[addr]
+	l.mutex.RUnlock()
+	if !ok {
+		return ContextDialer(ctx, addr)
+	}
+	release, err := l.consumer.SDKContext().GetEngine().AcquireConnection(svcKey)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := ContextDialer(ctx, addr)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &limitedConn{Conn: conn, release: release}, nil
+}
+
+// limitedConn 关闭时归还连接许可
+type limitedConn struct {
+	net.Conn
+	release func()
+}
+
+// Close 关闭连接并归还许可
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
@@ -0,0 +1,309 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package polarisgrpc 提供 grpc-go 与北极星的集成能力，导入路径为 github.com/polarismesh/polaris-go/integrations/grpc，
+// 包名使用 polarisgrpc 以避免与 google.golang.org/grpc 冲突
+package polarisgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Scheme 北极星的grpc target scheme，格式为 polaris://namespace/service 或者 polaris://service?namespace=ns
+	Scheme = "polaris"
+	// DefaultNamespace 未在target中指定命名空间时使用的默认命名空间
+	DefaultNamespace = "default"
+	// instanceIndexTTL 未使用北极星负载均衡器时，按对端地址查找实例所用索引的有效期
+	instanceIndexTTL = 5 * time.Second
+)
+
+// Option 拦截器选项
+type Option func(*options)
+
+type options struct {
+	namespace string
+	service   string
+	cacheKey  CacheKeyExtractor
+}
+
+// WithService 指定被调服务，不指定时从 polaris:// 格式的target中解析
+func WithService(namespace, service string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+		o.service = service
+	}
+}
+
+// UnaryClientInterceptor 创建一元调用拦截器，自动统计调用时延及状态码并上报调用结果.
+// 使用根目录 polaris.ConsumerAPI 时，可通过 api.NewConsumerAPIByContext(consumer.SDKContext()) 获取 api.ConsumerAPI
+func UnaryClientInterceptor(consumer api.ConsumerAPI, opts ...Option) grpc.UnaryClientInterceptor {
+	r := newCallReporter(consumer, opts...)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		ctx, holder := withInstanceHolder(ctx)
+		p := &peer.Peer{}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(p))...)
+		r.report(cc.Target(), method, holder, p, time.Since(start), err)
+		return err
+	}
+}
+
+// StreamClientInterceptor 创建流式调用拦截器，流结束时上报调用结果
+func StreamClientInterceptor(consumer api.ConsumerAPI, opts ...Option) grpc.StreamClientInterceptor {
+	r := newCallReporter(consumer, opts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, holder := withInstanceHolder(ctx)
+		p := &peer.Peer{}
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			r.report(cc.Target(), method, holder, p, time.Since(start), err)
+			return nil, err
+		}
+		s := &reportingStream{ClientStream: stream, desc: desc, done: make(chan struct{}), finish: func(err error) {
+			r.report(cc.Target(), method, holder, p, time.Since(start), err)
+		}}
+		go s.watch(ctx)
+		return s, nil
+	}
+}
+
+// SetPickedInstance 由负载均衡器调用，北极星负载均衡器（BalancerName）会自动调用，记录本次调用选中的实例，拦截器优先使用该实例上报调用结果，
+// 未记录时按照对端地址在服务实例中查找
+func SetPickedInstance(ctx context.Context, instance model.Instance) {
+	if holder, ok := ctx.Value(instanceHolderKey{}).(*instanceHolder); ok {
+		holder.mutex.Lock()
+		holder.instance = instance
+		holder.mutex.Unlock()
+	}
+}
+
+// RetStatusFromCode 将grpc状态码转换为调用结果状态，只有表示被调实例异常的状态码才视为失败
+func RetStatusFromCode(code codes.Code) model.RetStatus {
+	switch code {
+	case codes.OK:
+		return model.RetSuccess
+	case codes.DeadlineExceeded:
+		return model.RetTimeout
+	case codes.ResourceExhausted:
+		return model.RetFlowControl
+	case codes.Unavailable, codes.Internal, codes.Unknown, codes.DataLoss:
+		return model.RetFail
+	case codes.Canceled:
+		return model.RetUnknown
+	default:
+		// 参数错误、权限错误等业务错误不代表被调实例异常
+		return model.RetSuccess
+	}
+}
+
+type instanceHolderKey struct{}
+
+type instanceHolder struct {
+	mutex    sync.Mutex
+	instance model.Instance
+}
+
+func withInstanceHolder(ctx context.Context) (context.Context, *instanceHolder) {
+	holder := &instanceHolder{}
+	return context.WithValue(ctx, instanceHolderKey{}, holder), holder
+}
+
+func (h *instanceHolder) get() model.Instance {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.instance
+}
+
+// reportingStream 流结束时触发上报，每个流只上报一次
+type reportingStream struct {
+	grpc.ClientStream
+	desc   *grpc.StreamDesc
+	once   sync.Once
+	done   chan struct{}
+	finish func(err error)
+}
+
+// RecvMsg 接收消息，读到流结束或者出错时上报，服务端非流式的调用收到唯一的应答即结束
+func (s *reportingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == io.EOF:
+		s.finishOnce(nil)
+	case err != nil:
+		s.finishOnce(err)
+	case !s.desc.ServerStreams:
+		s.finishOnce(nil)
+	}
+	return err
+}
+
+// watch 调用方取消或者超时的时候流不再被读取，按照上下文的错误上报
+func (s *reportingStream) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		s.finishOnce(status.FromContextError(ctx.Err()).Err())
+	case <-s.done:
+	}
+}
+
+func (s *reportingStream) finishOnce(err error) {
+	s.once.Do(func() {
+		close(s.done)
+		s.finish(err)
+	})
+}
+
+// callReporter 调用结果上报
+type callReporter struct {
+	consumer api.ConsumerAPI
+	opts     options
+	mutex    sync.Mutex
+	// indexes 被调服务按地址索引的实例，避免每次调用都查询全部实例
+	indexes map[model.ServiceKey]*instanceIndex
+}
+
+// instanceIndex 服务下按照grpc拨号地址索引的实例
+type instanceIndex struct {
+	expireTime time.Time
+	instances  map[string]model.Instance
+}
+
+func newCallReporter(consumer api.ConsumerAPI, opts ...Option) *callReporter {
+	r := &callReporter{consumer: consumer, indexes: make(map[model.ServiceKey]*instanceIndex)}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+	return r
+}
+
+func (r *callReporter) report(target, method string, holder *instanceHolder, p *peer.Peer,
+	delay time.Duration, err error) {
+	instance := holder.get()
+	if instance == nil {
+		instance = r.lookupInstance(target, p)
+	}
+	if instance == nil {
+		return
+	}
+	reportCallResult(r.consumer, instance, method, delay, err)
+}
+
+// reportCallResult 按照grpc状态码上报实例调用结果
+func reportCallResult(consumer api.ConsumerAPI, instance model.Instance, method string,
+	delay time.Duration, err error) {
+	code := status.Code(err)
+	result := &api.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetMethod(method)
+	result.SetRetStatus(RetStatusFromCode(code))
+	result.SetRetCode(int32(code))
+	result.SetDelay(delay)
+	if reportErr := consumer.UpdateServiceCallResult(result); reportErr != nil {
+		log.GetBaseLogger().Warnf("[grpc] fail to report call result of %s: %v", method, reportErr)
+	}
+}
+
+// lookupInstance 按照对端地址在被调服务的实例中查找，北极星负载均衡器已经记录选中实例时不会走到这里
+func (r *callReporter) lookupInstance(target string, p *peer.Peer) model.Instance {
+	if p.Addr == nil {
+		return nil
+	}
+	namespace, service := r.opts.namespace, r.opts.service
+	if len(service) == 0 {
+		namespace, service = parseTarget(target)
+	}
+	if len(service) == 0 {
+		return nil
+	}
+	var addr string
+	if p.Addr.Network() == model.NetworkUnix {
+		// unix domain socket 实例只按照文件路径匹配
+		addr = model.UnixSocketScheme + p.Addr.String()
+	} else {
+		host, port, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return nil
+		}
+		addr = net.JoinHostPort(host, port)
+	}
+	index := r.getInstanceIndex(model.ServiceKey{Namespace: namespace, Service: service})
+	if index == nil {
+		return nil
+	}
+	return index.instances[addr]
+}
+
+// getInstanceIndex 获取服务的实例地址索引，过期后重新查询服务实例
+func (r *callReporter) getInstanceIndex(svcKey model.ServiceKey) *instanceIndex {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	if index, ok := r.indexes[svcKey]; ok && now.Before(index.expireTime) {
+		return index
+	}
+	req := &api.GetAllInstancesRequest{}
+	req.Namespace = svcKey.Namespace
+	req.Service = svcKey.Service
+	resp, err := r.consumer.GetAllInstances(req)
+	if err != nil {
+		return nil
+	}
+	index := &instanceIndex{
+		expireTime: now.Add(instanceIndexTTL),
+		instances:  make(map[string]model.Instance, len(resp.GetInstances())),
+	}
+	for _, instance := range resp.GetInstances() {
+		index.instances[instanceAddr(instance)] = instance
+	}
+	r.indexes[svcKey] = index
+	return index
+}
+
+// parseTarget 解析 polaris://namespace/service 或者 polaris://service?namespace=ns 格式的target
+func parseTarget(target string) (string, string) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != Scheme {
+		return "", ""
+	}
+	if service := strings.Trim(u.Path, "/"); len(service) > 0 {
+		return u.Host, service
+	}
+	namespace := u.Query().Get("namespace")
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	return namespace, u.Host
+}
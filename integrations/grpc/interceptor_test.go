@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestRetStatusFromCode(t *testing.T) {
+	expects := map[codes.Code]model.RetStatus{
+		codes.OK:                model.RetSuccess,
+		codes.InvalidArgument:   model.RetSuccess,
+		codes.DeadlineExceeded:  model.RetTimeout,
+		codes.Unavailable:       model.RetFail,
+		codes.ResourceExhausted: model.RetFlowControl,
+		codes.Canceled:          model.RetUnknown,
+	}
+	for code, expect := range expects {
+		if status := RetStatusFromCode(code); status != expect {
+			t.Errorf("code %s: expect %s, got %s", code, expect, status)
+		}
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	namespace, service := parseTarget("polaris://echo?namespace=Test")
+	if namespace != "Test" || service != "echo" {
+		t.Fatalf("unexpected target parse result %s/%s", namespace, service)
+	}
+	namespace, service = parseTarget("polaris://Test/echo")
+	if namespace != "Test" || service != "echo" {
+		t.Fatalf("unexpected target parse result %s/%s", namespace, service)
+	}
+	namespace, service = parseTarget("polaris://echo")
+	if namespace != DefaultNamespace || service != "echo" {
+		t.Fatalf("unexpected target parse result %s/%s", namespace, service)
+	}
+	if _, service = parseTarget("dns:///echo:8080"); service != "" {
+		t.Fatalf("expect non polaris target ignored, got %s", service)
+	}
+}
+
+type stubClientStream struct {
+	grpc.ClientStream
+	recvErrs []error
+}
+
+func (s *stubClientStream) RecvMsg(interface{}) error {
+	if len(s.recvErrs) == 0 {
+		return io.EOF
+	}
+	err := s.recvErrs[0]
+	s.recvErrs = s.recvErrs[1:]
+	return err
+}
+
+// TestStreamClientInterceptorReport 测试流式调用在收到最终应答、读到流结束或者调用方取消时上报且只上报一次，
+// 按对端地址查找实例时复用实例索引
+func TestStreamClientInterceptorReport(t *testing.T) {
+	consumer := &stubConsumerAPI{allInstances: []model.Instance{newTestInstance("127.0.0.1", 8080)}}
+	interceptor := StreamClientInterceptor(consumer, WithService("Test", "echo"))
+	cc, err := grpc.Dial("passthrough:///127.0.0.1:8080", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+	newStream := func(ctx context.Context, desc *grpc.StreamDesc, recvErrs ...error) grpc.ClientStream {
+		streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+			opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			for _, opt := range opts {
+				if peerOpt, ok := opt.(grpc.PeerCallOption); ok {
+					peerOpt.PeerAddr.Addr = &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080}
+				}
+			}
+			return &stubClientStream{recvErrs: recvErrs}, nil
+		}
+		stream, err := interceptor(ctx, desc, cc, "/echo.Echo/Say", streamer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stream
+	}
+	expectReported := func(count int, retStatus model.RetStatus) {
+		deadline := time.Now().Add(time.Second)
+		for len(consumer.reported()) < count && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		results := consumer.reported()
+		if len(results) != count {
+			t.Fatalf("expect %d reports, got %d", count, len(results))
+		}
+		if count > 0 && results[count-1].GetRetStatus() != retStatus {
+			t.Fatalf("expect %s reported, got %s", retStatus, results[count-1].GetRetStatus())
+		}
+	}
+
+	// 客户端流式调用 CloseAndRecv 收到应答时返回nil
+	stream := newStream(context.Background(), &grpc.StreamDesc{ClientStreams: true}, nil)
+	_ = stream.RecvMsg(nil)
+	expectReported(1, model.RetSuccess)
+	_ = stream.RecvMsg(nil)
+	expectReported(1, model.RetSuccess)
+
+	// 服务端流式调用读到流结束才上报
+	stream = newStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, io.EOF)
+	_ = stream.RecvMsg(nil)
+	expectReported(1, model.RetSuccess)
+	_ = stream.RecvMsg(nil)
+	expectReported(2, model.RetSuccess)
+
+	// 调用方取消后不再读取流，按取消上报
+	ctx, cancel := context.WithCancel(context.Background())
+	stream = newStream(ctx, &grpc.StreamDesc{ServerStreams: true}, nil)
+	cancel()
+	expectReported(3, model.RetUnknown)
+	_ = stream.RecvMsg(nil)
+	expectReported(3, model.RetUnknown)
+
+	consumer.mutex.Lock()
+	defer consumer.mutex.Unlock()
+	if consumer.allCalls != 1 {
+		t.Fatalf("expect instances queried once, got %d", consumer.allCalls)
+	}
+	if results := consumer.results; results[0].GetCalledInstance() != consumer.allInstances[0] {
+		t.Fatal("expect instance resolved by peer address")
+	}
+}
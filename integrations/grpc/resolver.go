@@ -0,0 +1,143 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polarisgrpc
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// addressAttributeKey 地址属性中保存北极星被调服务及ConsumerAPI的key
+type addressAttributeKey struct{}
+
+// addressAttribute 负载均衡器通过地址属性获取被调服务及ConsumerAPI，值在整个连接期间保持不变，避免SubConn被重建
+type addressAttribute struct {
+	consumer api.ConsumerAPI
+	svcKey   model.ServiceKey
+}
+
+// NewResolverBuilder 创建北极星的grpc resolver，通过 grpc.WithResolvers 使用，
+// 解析 polaris://namespace/service 格式的target，监听服务实例变更并使用北极星负载均衡器（BalancerName）
+func NewResolverBuilder(consumer api.ConsumerAPI) resolver.Builder {
+	return &resolverBuilder{consumer: consumer}
+}
+
+type resolverBuilder struct {
+	consumer api.ConsumerAPI
+	limiter  *connectionLimiter
+}
+
+// Build 创建resolver并开始监听服务实例
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn,
+	_ resolver.BuildOptions) (resolver.Resolver, error) {
+	namespace, service := parseTarget(target.URL.String())
+	if len(service) == 0 {
+		return nil, fmt.Errorf("invalid polaris target %s, expect %s://namespace/service",
+			target.URL.String(), Scheme)
+	}
+	r := &polarisResolver{
+		attribute: &addressAttribute{
+			consumer: b.consumer,
+			svcKey:   model.ServiceKey{Namespace: namespace, Service: service},
+		},
+		cc:            cc,
+		limiter:       b.limiter,
+		serviceConfig: cc.ParseServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{"%s":{}}]}`, BalancerName)),
+	}
+	req := &api.WatchAllInstancesRequest{}
+	req.Namespace = namespace
+	req.Service = service
+	req.WatchMode = api.WatchModeNotify
+	req.InstancesListener = r
+	resp, err := b.consumer.WatchAllInstances(req)
+	if err != nil {
+		return nil, err
+	}
+	r.watch = resp
+	r.OnInstancesUpdate(resp.InstancesResponse())
+	return r, nil
+}
+
+// Scheme 返回 Scheme
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+type polarisResolver struct {
+	attribute     *addressAttribute
+	cc            resolver.ClientConn
+	limiter       *connectionLimiter
+	serviceConfig *serviceconfig.ParseResult
+	watch         *model.WatchAllInstancesResponse
+}
+
+// OnInstancesUpdate 服务实例变更时更新grpc地址列表，隔离的实例不建立连接
+func (r *polarisResolver) OnInstancesUpdate(resp *model.InstancesResponse) {
+	if resp == nil {
+		return
+	}
+	instances := resp.GetInstances()
+	addresses := make([]resolver.Address, 0, len(instances))
+	addrs := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if instance.IsIsolated() {
+			continue
+		}
+		addr := instanceAddr(instance)
+		addresses = append(addresses, resolver.Address{
+			Addr:       addr,
+			Attributes: attributes.New(addressAttributeKey{}, r.attribute),
+		})
+		addrs = append(addrs, addr)
+	}
+	if r.limiter != nil {
+		r.limiter.update(r.attribute.svcKey, addrs)
+	}
+	state := resolver.State{Addresses: addresses, ServiceConfig: r.serviceConfig}
+	if err := r.cc.UpdateState(state); err != nil {
+		log.GetBaseLogger().Warnf("[grpc] fail to update resolver state of %s: %v", r.attribute.svcKey, err)
+	}
+}
+
+// ResolveNow 实例由监听推送，无需主动解析
+func (r *polarisResolver) ResolveNow(resolver.ResolveNowOptions) {
+}
+
+// Close 取消实例监听
+func (r *polarisResolver) Close() {
+	if r.watch != nil {
+		r.watch.CancelWatch()
+	}
+}
+
+// instanceAddr 实例的grpc拨号地址，unix domain socket 实例使用 unix:// 地址
+func instanceAddr(instance model.Instance) string {
+	if model.IsUnixSocketHost(instance.GetHost()) {
+		return instance.GetHost()
+	}
+	return net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+}
@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Response 响应分类器的输入，包含状态码、应答头以及应答体大小
+type Response struct {
+	// StatusCode 状态码
+	StatusCode int
+	// Header 应答头
+	Header http.Header
+	// BodySize 应答体大小，单位字节
+	BodySize int64
+}
+
+// ResponseClassifier 将 HTTP 应答归类为调用结果，返回上报熔断的 RetStatus 以及 RetCode，
+// 例如 404 可以归类为成功，避免被当作被调方故障；429 可以归类为 model.RetFlowControl，不计入熔断统计
+type ResponseClassifier func(resp *Response) (model.RetStatus, string)
+
+var (
+	classifierMutex   sync.RWMutex
+	defaultClassifier ResponseClassifier = DefaultResponseClassifier
+)
+
+// DefaultResponseClassifier 默认的响应分类器，5xx 为失败，其余为成功，RetCode 为状态码
+func DefaultResponseClassifier(resp *Response) (model.RetStatus, string) {
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return model.RetFail, strconv.Itoa(resp.StatusCode)
+	}
+	return model.RetSuccess, strconv.Itoa(resp.StatusCode)
+}
+
+// RegisterResponseClassifier 注册进程级别的默认响应分类器，对之后创建且没有通过 WithResponseClassifier
+// 单独指定分类器的中间件生效，用于在团队间统一状态码的语义，传入nil时恢复为 DefaultResponseClassifier
+func RegisterResponseClassifier(classifier ResponseClassifier) {
+	classifierMutex.Lock()
+	defer classifierMutex.Unlock()
+	if classifier == nil {
+		classifier = DefaultResponseClassifier
+	}
+	defaultClassifier = classifier
+}
+
+// getResponseClassifier 获取进程级别的默认响应分类器
+func getResponseClassifier() ResponseClassifier {
+	classifierMutex.RLock()
+	defer classifierMutex.RUnlock()
+	return defaultClassifier
+}
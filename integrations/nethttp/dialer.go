@@ -0,0 +1,32 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"context"
+	"net"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DialInstance 拨号负载均衡选中的实例，支持 unix domain socket 实例，可用于 http.Transport 的 DialContext
+func DialInstance(ctx context.Context, instance model.Instance) (net.Conn, error) {
+	network, address := model.InstanceDialAddress(instance)
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,455 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package nethttp 提供标准库 net/http 与北极星的集成能力，包括服务端的限流、熔断中间件、监听地址自动注册，
+// 以及客户端基于服务发现的 RoundTripper
+package nethttp
+
+import (
+	"bytes"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/integrations/respcache"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// HeaderRetryAfter 限流或熔断时返回的标准重试等待头，单位秒
+	HeaderRetryAfter = "Retry-After"
+	// HeaderLimitedReason 限流或熔断时返回的原因提示头
+	HeaderLimitedReason = "X-Polaris-Limited-Reason"
+	// HeaderDegraded 限流或熔断时返回缓存应答的降级标识头，值为 HeaderDegradedCache
+	HeaderDegraded = "X-Polaris-Degraded"
+	// HeaderDegradedCache 降级标识头的取值，表示应答来自缓存
+	HeaderDegradedCache = "cache"
+	// DefaultRetryAfter 无法从限流结果中得到等待时间时，Retry-After 的默认值
+	DefaultRetryAfter = time.Second
+)
+
+// RouteExtractor 从请求中提取路由，作为限流规则的 method 以及熔断的方法级资源
+type RouteExtractor func(r *http.Request) string
+
+// LabelExtractor 从请求中提取限流规则的匹配参数
+type LabelExtractor func(r *http.Request) []model.Argument
+
+// CacheKeyExtractor 从请求中提取应答缓存键的请求标识，返回false时该请求不读写应答缓存。
+// 需要按用户缓存时，可在请求标识中加入经过鉴权的用户身份
+type CacheKeyExtractor func(r *http.Request) (string, bool)
+
+// Option 中间件选项
+type Option func(*options)
+
+type options struct {
+	limitAPI       api.LimitAPI
+	breakerAPI     api.CircuitBreakerAPI
+	providerAPI    api.ProviderAPI
+	routeExtractor RouteExtractor
+	labelExtractor LabelExtractor
+	retryAfter     time.Duration
+	classifier     ResponseClassifier
+	cache          *respcache.Cache
+	cacheKey       CacheKeyExtractor
+}
+
+// WithLimitAPI 开启限流，每个请求按照路由获取一次配额，被限流时返回 429
+func WithLimitAPI(limitAPI api.LimitAPI) Option {
+	return func(o *options) {
+		o.limitAPI = limitAPI
+	}
+}
+
+// WithProviderAPI 统计处理中的请求数，供 ProviderAPI.GracefulShutdown 在退出前等待请求处理完成
+func WithProviderAPI(providerAPI api.ProviderAPI) Option {
+	return func(o *options) {
+		o.providerAPI = providerAPI
+	}
+}
+
+// WithCircuitBreakerAPI 开启方法级熔断，熔断打开时返回 503，请求结束后按照状态码上报调用结果
+func WithCircuitBreakerAPI(breakerAPI api.CircuitBreakerAPI) Option {
+	return func(o *options) {
+		o.breakerAPI = breakerAPI
+	}
+}
+
+// WithRouteExtractor 设置路由提取方法，默认使用 URL.Path
+func WithRouteExtractor(extractor RouteExtractor) Option {
+	return func(o *options) {
+		o.routeExtractor = extractor
+	}
+}
+
+// WithLabelExtractor 设置限流参数提取方法，默认使用 DefaultLabelExtractor
+func WithLabelExtractor(extractor LabelExtractor) Option {
+	return func(o *options) {
+		o.labelExtractor = extractor
+	}
+}
+
+// WithRetryAfter 设置熔断时返回的 Retry-After，默认 DefaultRetryAfter
+func WithRetryAfter(retryAfter time.Duration) Option {
+	return func(o *options) {
+		o.retryAfter = retryAfter
+	}
+}
+
+// WithFailureClassifier 设置哪些状态码上报为失败，默认 5xx 为失败
+func WithFailureClassifier(isFailure func(statusCode int) bool) Option {
+	return func(o *options) {
+		o.classifier = func(resp *Response) (model.RetStatus, string) {
+			if isFailure(resp.StatusCode) {
+				return model.RetFail, strconv.Itoa(resp.StatusCode)
+			}
+			return model.RetSuccess, strconv.Itoa(resp.StatusCode)
+		}
+	}
+}
+
+// WithResponseClassifier 设置响应分类器，默认使用 RegisterResponseClassifier 注册的进程级分类器
+func WithResponseClassifier(classifier ResponseClassifier) Option {
+	return func(o *options) {
+		o.classifier = classifier
+	}
+}
+
+// WithResponseCache 开启应答微缓存，缓存按照路由显式开启，只缓存 GET 请求的 2xx 应答，
+// 请求被熔断或者被限流时返回缓存中未过期的应答，并设置 HeaderDegraded 头。
+// 带有 Set-Cookie、Cache-Control: private/no-store 或者 Vary: * 的应答不缓存，
+// 应答的 Vary 头列出的请求头取值不同时不会命中缓存
+func WithResponseCache(cache *respcache.Cache) Option {
+	return func(o *options) {
+		o.cache = cache
+	}
+}
+
+// WithCacheKeyExtractor 设置应答缓存的请求标识提取方法，默认使用 DefaultCacheKeyExtractor
+func WithCacheKeyExtractor(extractor CacheKeyExtractor) Option {
+	return func(o *options) {
+		o.cacheKey = extractor
+	}
+}
+
+// DefaultCacheKeyExtractor 默认的应答缓存请求标识提取方法，使用 RequestURI 作为请求标识，
+// 带有 Authorization 或者 Cookie 头的请求属于特定用户，不使用缓存
+func DefaultCacheKeyExtractor(r *http.Request) (string, bool) {
+	if len(r.Header.Get("Authorization")) > 0 || len(r.Header.Get("Cookie")) > 0 {
+		return "", false
+	}
+	return r.URL.RequestURI(), true
+}
+
+// DefaultLabelExtractor 默认的限流参数提取方法，提取主调IP、路径、请求头以及查询参数（多值时取第一个）
+func DefaultLabelExtractor(r *http.Request) []model.Argument {
+	arguments := make([]model.Argument, 0, 2+len(r.Header)+len(r.URL.Query()))
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		arguments = append(arguments, model.BuildCallerIPArgument(host))
+	}
+	arguments = append(arguments, model.BuildPathArgument(r.URL.Path))
+	for key := range r.Header {
+		arguments = append(arguments, model.BuildHeaderArgument(key, r.Header.Get(key)))
+	}
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			arguments = append(arguments, model.BuildQueryArgument(key, values[0]))
+		}
+	}
+	return arguments
+}
+
+// Middleware 创建 net/http 中间件，对被调服务 namespace/service 按路由进行限流以及熔断判断
+func Middleware(namespace, service string, opts ...Option) func(http.Handler) http.Handler {
+	m := newMiddleware(namespace, service, opts...)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m.serveHTTP(w, r, next)
+		})
+	}
+}
+
+type middleware struct {
+	svcKey model.ServiceKey
+	opts   options
+}
+
+func newMiddleware(namespace, service string, opts ...Option) *middleware {
+	m := &middleware{
+		svcKey: model.ServiceKey{Namespace: namespace, Service: service},
+		opts: options{
+			routeExtractor: func(r *http.Request) string { return r.URL.Path },
+			labelExtractor: DefaultLabelExtractor,
+			retryAfter:     DefaultRetryAfter,
+			classifier:     getResponseClassifier(),
+			cacheKey:       DefaultCacheKeyExtractor,
+		},
+	}
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
+	return m
+}
+
+func (m *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	if m.opts.providerAPI != nil {
+		done := m.opts.providerAPI.TrackRequest()
+		defer done()
+	}
+	route := m.opts.routeExtractor(r)
+	var resource model.Resource
+	var permit *model.BulkheadPermit
+	if m.opts.breakerAPI != nil && len(route) > 0 {
+		var err error
+		if resource, err = model.NewMethodResource(&m.svcKey, nil, route); err != nil {
+			log.GetBaseLogger().Warnf("[nethttp] fail to build breaker resource for %s: %v", route, err)
+		}
+	}
+	if resource != nil {
+		result, err := m.opts.breakerAPI.Check(resource)
+		if err != nil {
+			log.GetBaseLogger().Warnf("[nethttp] fail to check breaker for %s: %v", route, err)
+		} else if !result.Pass {
+			if m.serveCached(w, r, route) {
+				return
+			}
+			reject(w, http.StatusServiceUnavailable, m.opts.retryAfter, "circuit breaker open: "+result.RuleName)
+			return
+		}
+		// 被限流等未上报调用结果的请求同样归还舱壁并发许可
+		permit = result.Permit
+		defer permit.Release()
+	}
+	if m.opts.limitAPI != nil {
+		future, err := m.getQuota(r, route)
+		if err != nil {
+			log.GetBaseLogger().Warnf("[nethttp] fail to get quota for %s: %v", route, err)
+		} else {
+			defer future.Release()
+			if resp := future.GetImmediately(); resp.Code == model.QuotaResultLimited {
+				if m.serveCached(w, r, route) {
+					return
+				}
+				reject(w, http.StatusTooManyRequests, time.Duration(resp.WaitMs)*time.Millisecond,
+					"rate limited: "+resp.Info)
+				return
+			}
+			// 匀速排队场景下等待至分配时间
+			future.Get()
+		}
+	}
+	cacheKey, cacheable := m.cacheKey(r, route)
+	if resource == nil && !cacheable {
+		next.ServeHTTP(w, r)
+		return
+	}
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	if cacheable {
+		recorder.capture = &bytes.Buffer{}
+		recorder.captureLimit = m.opts.cache.MaxEntryBytes()
+	}
+	start := time.Now()
+	next.ServeHTTP(recorder, r)
+	if cacheable {
+		m.storeCached(r, cacheKey, recorder)
+	}
+	if resource == nil {
+		return
+	}
+	retStatus, retCode := m.opts.classifier(&Response{
+		StatusCode: recorder.statusCode,
+		Header:     recorder.Header(),
+		BodySize:   recorder.bodySize,
+	})
+	stat := &model.ResourceStat{
+		Resource:  resource,
+		RetCode:   retCode,
+		Delay:     time.Since(start),
+		RetStatus: retStatus,
+		Permit:    permit,
+	}
+	if err := m.opts.breakerAPI.Report(stat); err != nil {
+		log.GetBaseLogger().Warnf("[nethttp] fail to report breaker stat for %s: %v", route, err)
+	}
+}
+
+func (m *middleware) getQuota(r *http.Request, route string) (api.QuotaFuture, error) {
+	req := api.NewQuotaRequest()
+	req.SetNamespace(m.svcKey.Namespace)
+	req.SetService(m.svcKey.Service)
+	req.SetMethod(route)
+	for _, argument := range m.opts.labelExtractor(r) {
+		req.AddArgument(argument)
+	}
+	return m.opts.limitAPI.GetQuota(req)
+}
+
+// cachedResponse 缓存的 HTTP 应答
+type cachedResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	// 应答 Vary 头列出的请求头，以及缓存时这些请求头的取值
+	vary map[string]string
+}
+
+// matchVary 请求中 Vary 头列出的请求头取值是否与缓存时一致
+func (c *cachedResponse) matchVary(r *http.Request) bool {
+	for name, value := range c.vary {
+		if strings.Join(r.Header.Values(name), ",") != value {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey 计算请求的应答缓存键，返回false时请求不使用应答缓存
+func (m *middleware) cacheKey(r *http.Request, route string) (respcache.Key, bool) {
+	if r.Method != http.MethodGet || !m.opts.cache.Cacheable(route) {
+		return respcache.Key{}, false
+	}
+	request, ok := m.opts.cacheKey(r)
+	if !ok {
+		return respcache.Key{}, false
+	}
+	return respcache.Key{
+		Namespace: m.svcKey.Namespace,
+		Service:   m.svcKey.Service,
+		Method:    route,
+		Request:   request,
+	}, true
+}
+
+// storeCached 缓存成功、完整收集且不属于特定用户的应答
+func (m *middleware) storeCached(r *http.Request, key respcache.Key, recorder *statusRecorder) {
+	if recorder.statusCode < http.StatusOK || recorder.statusCode >= http.StatusMultipleChoices ||
+		recorder.captureOverflow {
+		return
+	}
+	header := recorder.Header()
+	if len(header.Values("Set-Cookie")) > 0 {
+		return
+	}
+	for _, directive := range headerTokens(header, "Cache-Control") {
+		if directive == "private" || directive == "no-store" || strings.HasPrefix(directive, "private=") {
+			return
+		}
+	}
+	vary := make(map[string]string)
+	for _, name := range headerTokens(header, "Vary") {
+		if name == "*" {
+			return
+		}
+		vary[http.CanonicalHeaderKey(name)] = strings.Join(r.Header.Values(name), ",")
+	}
+	body := recorder.capture.Bytes()
+	m.opts.cache.Put(key, &cachedResponse{
+		statusCode: recorder.statusCode,
+		header:     header.Clone(),
+		body:       body,
+		vary:       vary,
+	}, len(body))
+}
+
+// headerTokens 获取逗号分隔的头部取值，统一转为小写
+func headerTokens(header http.Header, name string) []string {
+	var tokens []string
+	for _, value := range header.Values(name) {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.ToLower(strings.TrimSpace(token)); len(token) > 0 {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// serveCached 返回缓存中未过期且 Vary 匹配的应答
+func (m *middleware) serveCached(w http.ResponseWriter, r *http.Request, route string) bool {
+	key, ok := m.cacheKey(r, route)
+	if !ok {
+		return false
+	}
+	value, ok := m.opts.cache.Get(key)
+	if !ok {
+		return false
+	}
+	cached := value.(*cachedResponse)
+	if !cached.matchVary(r) {
+		return false
+	}
+	for key, values := range cached.header {
+		w.Header()[key] = values
+	}
+	w.Header().Set(HeaderDegraded, HeaderDegradedCache)
+	w.WriteHeader(cached.statusCode)
+	_, _ = w.Write(cached.body)
+	return true
+}
+
+// reject 返回限流或熔断应答，Retry-After 向上取整到秒
+func reject(w http.ResponseWriter, statusCode int, retryAfter time.Duration, reason string) {
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+	w.Header().Set(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	w.Header().Set(HeaderLimitedReason, reason)
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+// statusRecorder 记录业务处理返回的状态码以及应答体大小，开启应答缓存时同时收集应答体
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	bodySize   int64
+	// 收集的应答体，超过captureLimit时停止收集
+	capture         *bytes.Buffer
+	captureLimit    int
+	captureOverflow bool
+}
+
+// Write 记录应答体大小
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	n, err := s.ResponseWriter.Write(b)
+	s.bodySize += int64(n)
+	if s.capture != nil && !s.captureOverflow {
+		if s.capture.Len()+n > s.captureLimit {
+			s.captureOverflow = true
+			s.capture.Reset()
+		} else {
+			s.capture.Write(b[:n])
+		}
+	}
+	return n, err
+}
+
+// WriteHeader 记录状态码
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush 透传流式输出
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
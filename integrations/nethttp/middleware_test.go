@@ -0,0 +1,260 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/integrations/respcache"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type stubLimitAPI struct {
+	api.LimitAPI
+	code    model.QuotaResultCode
+	methods []string
+}
+
+func (s *stubLimitAPI) GetQuota(request api.QuotaRequest) (api.QuotaFuture, error) {
+	s.methods = append(s.methods, request.(*model.QuotaRequestImpl).GetMethod())
+	return model.QuotaFutureWithResponse(&model.QuotaResponse{Code: s.code, WaitMs: 1500}), nil
+}
+
+type stubBreakerAPI struct {
+	api.CircuitBreakerAPI
+	pass  bool
+	stats []*model.ResourceStat
+}
+
+func (s *stubBreakerAPI) Check(model.Resource) (*model.CheckResult, error) {
+	return &model.CheckResult{Pass: s.pass, RuleName: "rule"}, nil
+}
+
+func (s *stubBreakerAPI) Report(stat *model.ResourceStat) error {
+	s.stats = append(s.stats, stat)
+	return nil
+}
+
+func serve(handler http.Handler, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestMiddlewareRateLimit(t *testing.T) {
+	limitAPI := &stubLimitAPI{code: model.QuotaResultLimited}
+	handler := Middleware("Test", "echo", WithLimitAPI(limitAPI))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("limited request should not reach handler")
+		}))
+	recorder := serve(handler, "/echo?uid=1")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expect 429, got %d", recorder.Code)
+	}
+	if recorder.Header().Get(HeaderRetryAfter) != "2" {
+		t.Fatalf("expect Retry-After 2, got %s", recorder.Header().Get(HeaderRetryAfter))
+	}
+	if len(limitAPI.methods) != 1 || limitAPI.methods[0] != "/echo" {
+		t.Fatalf("unexpected quota methods %v", limitAPI.methods)
+	}
+}
+
+func TestMiddlewareCircuitBreaker(t *testing.T) {
+	breakerAPI := &stubBreakerAPI{pass: true}
+	handler := Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+	if recorder := serve(handler, "/echo"); recorder.Code != http.StatusBadGateway {
+		t.Fatalf("expect 502, got %d", recorder.Code)
+	}
+	if len(breakerAPI.stats) != 1 || breakerAPI.stats[0].RetStatus != model.RetFail ||
+		breakerAPI.stats[0].RetCode != "502" {
+		t.Fatalf("unexpected breaker stats %v", breakerAPI.stats)
+	}
+
+	breakerAPI.pass = false
+	recorder := serve(handler, "/echo")
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect 503, got %d", recorder.Code)
+	}
+	if recorder.Header().Get(HeaderRetryAfter) != "1" {
+		t.Fatalf("expect Retry-After 1, got %s", recorder.Header().Get(HeaderRetryAfter))
+	}
+	if len(breakerAPI.stats) != 1 {
+		t.Fatalf("rejected request should not be reported, got %d stats", len(breakerAPI.stats))
+	}
+}
+
+func TestMiddlewareResponseClassifier(t *testing.T) {
+	RegisterResponseClassifier(func(resp *Response) (model.RetStatus, string) {
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return model.RetFlowControl, "429"
+		case resp.StatusCode == http.StatusNotFound || resp.StatusCode < http.StatusInternalServerError:
+			return model.RetSuccess, strconv.Itoa(resp.StatusCode)
+		}
+		return model.RetFail, strconv.Itoa(resp.StatusCode)
+	})
+	defer RegisterResponseClassifier(nil)
+
+	breakerAPI := &stubBreakerAPI{pass: true}
+	var bodySize int64
+	handler := Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/missing":
+				http.NotFound(w, r)
+			case "/busy":
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		}))
+	serve(handler, "/missing")
+	serve(handler, "/busy")
+	if len(breakerAPI.stats) != 2 || breakerAPI.stats[0].RetStatus != model.RetSuccess ||
+		breakerAPI.stats[1].RetStatus != model.RetFlowControl {
+		t.Fatalf("unexpected breaker stats %v", breakerAPI.stats)
+	}
+
+	handler = Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI),
+		WithResponseClassifier(func(resp *Response) (model.RetStatus, string) {
+			bodySize = resp.BodySize
+			return model.RetSuccess, "ok"
+		}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	serve(handler, "/echo")
+	if bodySize != 5 || breakerAPI.stats[2].RetCode != "ok" {
+		t.Fatalf("expect option classifier with body size 5, got %d %v", bodySize, breakerAPI.stats[2])
+	}
+}
+
+func TestMiddlewareResponseCache(t *testing.T) {
+	breakerAPI := &stubBreakerAPI{pass: true}
+	cache := respcache.New(respcache.WithMethods("/echo"))
+	var calls int
+	handler := Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI), WithResponseCache(cache))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("hello"))
+		}))
+	if recorder := serve(handler, "/echo?uid=1"); recorder.Body.String() != "hello" {
+		t.Fatalf("unexpected body %q", recorder.Body.String())
+	}
+
+	breakerAPI.pass = false
+	recorder := serve(handler, "/echo?uid=1")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hello" ||
+		recorder.Header().Get(HeaderDegraded) != HeaderDegradedCache ||
+		recorder.Header().Get("Content-Type") != "text/plain" {
+		t.Fatalf("expect cached response, got %d %q %v", recorder.Code, recorder.Body.String(), recorder.Header())
+	}
+	if recorder = serve(handler, "/echo?uid=2"); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect 503 for uncached request, got %d", recorder.Code)
+	}
+	if calls != 1 {
+		t.Fatalf("expect handler called once, got %d", calls)
+	}
+}
+
+// TestMiddlewareResponseCacheIsolatesUsers 测试不同用户的请求不会共享缓存的应答
+func TestMiddlewareResponseCacheIsolatesUsers(t *testing.T) {
+	breakerAPI := &stubBreakerAPI{pass: true}
+	cache := respcache.New(respcache.WithMethods("/profile", "/private", "/vary"))
+	handler := Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI), WithResponseCache(cache))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := r.Header.Get("X-User")
+			switch r.URL.Path {
+			case "/private":
+				w.Header().Set("Cache-Control", "max-age=10, Private")
+			case "/vary":
+				w.Header().Set("Vary", "Accept-Encoding, X-User")
+			default:
+				w.Header().Set("Set-Cookie", "session="+user)
+			}
+			_, _ = w.Write([]byte(user))
+		}))
+	request := func(path, user string, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-User", user)
+		if len(header) > 0 {
+			req.Header.Set(header, user)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+	for _, header := range []string{"Authorization", "Cookie"} {
+		request("/vary", "alice", header)
+	}
+	request("/profile", "alice", "")
+	request("/private", "alice", "")
+	request("/vary", "alice", "")
+	if cache.Len() != 1 {
+		t.Fatalf("expect only the response with Vary cached, got %d entries", cache.Len())
+	}
+
+	breakerAPI.pass = false
+	for _, path := range []string{"/profile", "/private"} {
+		if recorder := request(path, "bob", ""); recorder.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expect 503 for %s, got %d %q", path, recorder.Code, recorder.Body.String())
+		}
+	}
+	if recorder := request("/vary", "bob", ""); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect bob not served alice's response, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	if recorder := request("/vary", "bob", "Authorization"); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect authorized request not served from cache, got %d", recorder.Code)
+	}
+	if recorder := request("/vary", "alice", ""); recorder.Body.String() != "alice" ||
+		recorder.Header().Get(HeaderDegraded) != HeaderDegradedCache {
+		t.Fatalf("expect alice served her cached response, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+// TestMiddlewareCacheKeyExtractor 测试自定义请求标识提取方法按用户区分缓存
+func TestMiddlewareCacheKeyExtractor(t *testing.T) {
+	breakerAPI := &stubBreakerAPI{pass: true}
+	cache := respcache.New(respcache.WithMethods("/echo"))
+	handler := Middleware("Test", "echo", WithCircuitBreakerAPI(breakerAPI), WithResponseCache(cache),
+		WithCacheKeyExtractor(func(r *http.Request) (string, bool) {
+			return r.Header.Get("Authorization") + " " + r.URL.RequestURI(), true
+		}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/echo", nil)
+		req.Header.Set("Authorization", token)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+	request("alice")
+	breakerAPI.pass = false
+	if recorder := request("alice"); recorder.Body.String() != "alice" {
+		t.Fatalf("expect alice served from cache, got %d %q", recorder.Code, recorder.Body.String())
+	}
+	if recorder := request("bob"); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expect bob not served alice's response, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
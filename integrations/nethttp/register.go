@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"errors"
+	"net"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RegisterListener 将监听地址注册到北极星并由 SDK 自动上报心跳，返回用于反注册的函数，
+// req 中未填写 Host、Port 时从监听地址中获取，监听在通配地址上时使用本机首个非回环IP，
+// unix domain socket 监听注册为 unix:///path 格式的地址
+func RegisterListener(provider api.ProviderAPI, ln net.Listener, req *api.InstanceRegisterRequest) (func() error, error) {
+	switch addr := ln.Addr().(type) {
+	case *net.TCPAddr:
+		if req.Port == 0 {
+			req.Port = addr.Port
+		}
+		if len(req.Host) == 0 {
+			host, err := advertiseHost(addr.IP)
+			if err != nil {
+				return nil, err
+			}
+			req.Host = host
+		}
+	case *net.UnixAddr:
+		if len(req.Host) == 0 {
+			req.Host = model.UnixSocketScheme + addr.Name
+		}
+	default:
+		return nil, errors.New("nethttp: only tcp or unix listener can be registered")
+	}
+	resp, err := provider.RegisterInstance(req)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		deregisterReq := &api.InstanceDeRegisterRequest{}
+		deregisterReq.Namespace = req.Namespace
+		deregisterReq.Service = req.Service
+		deregisterReq.ServiceToken = req.ServiceToken
+		deregisterReq.InstanceID = resp.InstanceID
+		deregisterReq.Host = req.Host
+		deregisterReq.Port = req.Port
+		return provider.Deregister(deregisterReq)
+	}, nil
+}
+
+// advertiseHost 计算注册使用的地址
+func advertiseHost(ip net.IP) (string, error) {
+	if len(ip) > 0 && !ip.IsUnspecified() {
+		return ip.String(), nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+		return ipNet.IP.String(), nil
+	}
+	return "", errors.New("nethttp: no available address to advertise")
+}
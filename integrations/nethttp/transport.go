@@ -0,0 +1,211 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DefaultNamespace 未通过 WithNamespace 指定时被调服务所在的命名空间
+const DefaultNamespace = "default"
+
+// TransportOption 客户端 RoundTripper 选项
+type TransportOption func(*transportOptions)
+
+type transportOptions struct {
+	namespace      string
+	base           http.RoundTripper
+	labelExtractor LabelExtractor
+	classifier     ResponseClassifier
+}
+
+// WithNamespace 设置被调服务所在的命名空间，默认 DefaultNamespace
+func WithNamespace(namespace string) TransportOption {
+	return func(o *transportOptions) {
+		o.namespace = namespace
+	}
+}
+
+// WithBaseTransport 设置实际发送请求的 RoundTripper，默认在 http.DefaultTransport 的基础上通过 LimitedDialContext
+// 执行被调服务的连接数限制（consumer.servicesSpecific[].connectionLimit），自定义时可同样使用 LimitedDialContext
+func WithBaseTransport(base http.RoundTripper) TransportOption {
+	return func(o *transportOptions) {
+		o.base = base
+	}
+}
+
+// WithRoutingLabelExtractor 设置从请求中提取路由参数的方法，默认使用 DefaultLabelExtractor
+func WithRoutingLabelExtractor(extractor LabelExtractor) TransportOption {
+	return func(o *transportOptions) {
+		o.labelExtractor = extractor
+	}
+}
+
+// WithTransportClassifier 设置应答的调用结果分类器，默认使用 RegisterResponseClassifier 注册的进程级分类器
+func WithTransportClassifier(classifier ResponseClassifier) TransportOption {
+	return func(o *transportOptions) {
+		o.classifier = classifier
+	}
+}
+
+// NewTransport 创建基于北极星服务发现的 http.RoundTripper，请求 URL 的主机名为被调服务名，
+// 每次请求通过 ConsumerAPI 完成路由及负载均衡，将请求发往选中的实例，并按照应答或者错误上报调用结果。
+// 例如 &http.Client{Transport: nethttp.NewTransport(consumer)} 后请求 http://echo/hello。
+// 使用根目录 polaris.ConsumerAPI 时，可通过 api.NewConsumerAPIByContext(consumer.SDKContext()) 获取 api.ConsumerAPI
+func NewTransport(consumer api.ConsumerAPI, opts ...TransportOption) http.RoundTripper {
+	t := &transport{
+		consumer: consumer,
+		opts: transportOptions{
+			namespace:      DefaultNamespace,
+			labelExtractor: DefaultLabelExtractor,
+			classifier:     getResponseClassifier(),
+		},
+	}
+	for _, opt := range opts {
+		opt(&t.opts)
+	}
+	if t.opts.base == nil {
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.DialContext = LimitedDialContext(consumer, base.DialContext)
+		t.opts.base = base
+	}
+	return t
+}
+
+// DialContextFunc http.Transport 的拨号方法
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+type serviceKeyContextKey struct{}
+
+// LimitedDialContext 包装拨号方法，按照 NewTransport 写入请求上下文的被调服务执行连接数限制，
+// 超过限制时排队或者返回 model.ErrConnectionLimited，连接关闭时归还许可；没有被调服务信息的连接不受限制
+func LimitedDialContext(consumer api.ConsumerAPI, dial DialContextFunc) DialContextFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		svcKey, ok := ctx.Value(serviceKeyContextKey{}).(model.ServiceKey)
+		if !ok {
+			return dial(ctx, network, addr)
+		}
+		release, err := consumer.SDKContext().GetEngine().AcquireConnection(svcKey)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		return &limitedConn{Conn: conn, release: release}, nil
+	}
+}
+
+// limitedConn 关闭时归还连接许可
+type limitedConn struct {
+	net.Conn
+	release func()
+}
+
+// Close 关闭连接并归还许可
+func (c *limitedConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
+
+type transport struct {
+	consumer api.ConsumerAPI
+	opts     transportOptions
+}
+
+// RoundTrip 选择实例并发送请求，不修改传入的请求
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	service := r.URL.Hostname()
+	req := &api.GetOneInstanceRequest{}
+	req.Namespace = t.opts.namespace
+	req.Service = service
+	req.Arguments = t.opts.labelExtractor(r)
+	req.SetContext(r.Context())
+	resp, err := t.consumer.GetOneInstance(req)
+	if err != nil {
+		return nil, err
+	}
+	instance := resp.GetInstance()
+	if model.IsUnixSocketHost(instance.GetHost()) {
+		return nil, fmt.Errorf("instance %s of %s::%s is a unix domain socket, use DialInstance instead",
+			instance.GetHost(), t.opts.namespace, service)
+	}
+	svcKey := model.ServiceKey{Namespace: t.opts.namespace, Service: service}
+	outReq := r.Clone(context.WithValue(r.Context(), serviceKeyContextKey{}, svcKey))
+	outReq.URL.Host = net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+	if len(outReq.Host) == 0 {
+		outReq.Host = r.URL.Host
+	}
+	start := time.Now()
+	httpResp, err := t.opts.base.RoundTrip(outReq)
+	t.report(r.Context(), instance, r.URL.Path, time.Since(start), httpResp, err)
+	return httpResp, err
+}
+
+// report 上报调用结果，请求出错时按照是否超时上报，否则按照应答分类器的结果上报，RetCode 为状态码.
+// 调用方主动取消或者自身超时导致的失败与被调实例无关，不进行上报
+func (t *transport) report(ctx context.Context, instance model.Instance, method string, delay time.Duration,
+	httpResp *http.Response, err error) {
+	if err != nil && (errors.Is(err, context.Canceled) || ctx.Err() != nil) {
+		return
+	}
+	result := &api.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetMethod(method)
+	result.SetDelay(delay)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			result.SetRetStatus(model.RetTimeout)
+		} else {
+			result.SetRetStatus(model.RetFail)
+		}
+		result.SetRetCode(-1)
+	} else {
+		bodySize := httpResp.ContentLength
+		if bodySize < 0 {
+			bodySize = 0
+		}
+		retStatus, _ := t.opts.classifier(&Response{
+			StatusCode: httpResp.StatusCode,
+			Header:     httpResp.Header,
+			BodySize:   bodySize,
+		})
+		result.SetRetStatus(retStatus)
+		result.SetRetCode(int32(httpResp.StatusCode))
+	}
+	if reportErr := t.consumer.UpdateServiceCallResult(result); reportErr != nil {
+		log.GetBaseLogger().Warnf("[nethttp] fail to report call result of %s: %v", method, reportErr)
+	}
+}
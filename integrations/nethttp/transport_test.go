@@ -0,0 +1,179 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+type stubConsumerAPI struct {
+	api.ConsumerAPI
+	instance model.Instance
+	requests []*api.GetOneInstanceRequest
+	results  []*api.ServiceCallResult
+	engine   stubEngine
+}
+
+type stubSDKContext struct {
+	api.SDKContext
+	engine model.Engine
+}
+
+func (s *stubSDKContext) GetEngine() model.Engine {
+	return s.engine
+}
+
+type stubEngine struct {
+	model.Engine
+	mutex          sync.Mutex
+	maxConnections int
+	connections    int
+}
+
+func (s *stubEngine) AcquireConnection(model.ServiceKey) (func(), error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.maxConnections > 0 && s.connections >= s.maxConnections {
+		return nil, model.ErrConnectionLimited
+	}
+	s.connections++
+	return func() {
+		s.mutex.Lock()
+		s.connections--
+		s.mutex.Unlock()
+	}, nil
+}
+
+func (s *stubConsumerAPI) SDKContext() api.SDKContext {
+	return &stubSDKContext{engine: &s.engine}
+}
+
+func (s *stubConsumerAPI) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	s.requests = append(s.requests, req)
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = []model.Instance{s.instance}
+	return resp, nil
+}
+
+func (s *stubConsumerAPI) UpdateServiceCallResult(result *api.ServiceCallResult) error {
+	s.results = append(s.results, result)
+	return nil
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "echo" {
+			t.Errorf("expect logical host kept, got %s", r.Host)
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	consumer := &stubConsumerAPI{instance: pb.NewInstanceInProto(&apiservice.Instance{
+		Host: wrapperspb.String(host),
+		Port: wrapperspb.UInt32(uint32(port)),
+	}, &model.ServiceKey{Namespace: "Test", Service: "echo"}, nil)}
+	client := &http.Client{Transport: NewTransport(consumer, WithNamespace("Test"))}
+
+	for _, path := range []string{"/ok", "/fail"} {
+		resp, err := client.Get("http://echo" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if len(consumer.requests) != 2 || consumer.requests[0].Namespace != "Test" ||
+		consumer.requests[0].Service != "echo" {
+		t.Fatalf("unexpected instance requests %v", consumer.requests)
+	}
+	if len(consumer.results) != 2 || consumer.results[0].GetRetStatus() != model.RetSuccess ||
+		consumer.results[1].GetRetStatus() != model.RetFail || consumer.results[1].GetRetCodeValue() != 502 ||
+		consumer.results[1].GetMethod() != "/fail" {
+		t.Fatalf("unexpected call results %v", consumer.results)
+	}
+
+	// 调用方取消的请求不上报
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://echo/ok", nil)
+	if _, err := client.Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect canceled request, got %v", err)
+	}
+	if len(consumer.results) != 2 {
+		t.Fatalf("expect canceled request not reported, got %v", consumer.results[2:])
+	}
+}
+
+func TestTransportConnectionLimit(t *testing.T) {
+	entered := make(chan struct{}, 1)
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-block
+	}))
+	defer server.Close()
+	defer func() {
+		select {
+		case <-block:
+		default:
+			close(block)
+		}
+	}()
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	consumer := &stubConsumerAPI{instance: pb.NewInstanceInProto(&apiservice.Instance{
+		Host: wrapperspb.String(host),
+		Port: wrapperspb.UInt32(uint32(port)),
+	}, &model.ServiceKey{Namespace: "Test", Service: "echo"}, nil), engine: stubEngine{maxConnections: 1}}
+	transport := NewTransport(consumer, WithNamespace("Test"))
+	client := &http.Client{Transport: transport}
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := client.Get("http://echo/slow")
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		done <- err
+	}()
+	<-entered
+	if _, err := client.Get("http://echo/second"); !errors.Is(err, model.ErrConnectionLimited) {
+		t.Fatalf("expect connection limited error, got %v", err)
+	}
+	close(block)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
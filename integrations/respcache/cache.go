@@ -0,0 +1,185 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package respcache 提供集成层使用的应答微缓存：对显式开启的幂等读方法缓存最近一次成功的应答，
+// 在被调服务熔断或者被限流时返回缓存的应答，以可用但可能过期的数据降级
+package respcache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL 缓存应答的默认有效期
+	DefaultTTL = 10 * time.Second
+	// DefaultMaxEntries 默认的最大缓存条数
+	DefaultMaxEntries = 1024
+	// DefaultMaxEntryBytes 默认的单条应答大小上限，超过上限的应答不缓存
+	DefaultMaxEntryBytes = 64 * 1024
+)
+
+// Key 缓存键，由被调服务、方法以及请求内容组成
+type Key struct {
+	Namespace string
+	Service   string
+	Method    string
+	// Request 请求内容的标识，例如 HTTP 的 RequestURI 或者 grpc 请求序列化后的内容
+	Request string
+}
+
+// Option 缓存选项
+type Option func(*options)
+
+type options struct {
+	ttl           time.Duration
+	maxEntries    int
+	maxEntryBytes int
+	methods       map[string]struct{}
+}
+
+// WithTTL 设置缓存应答的有效期，默认 DefaultTTL
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithMaxEntries 设置最大缓存条数，超过时淘汰最久未使用的应答，默认 DefaultMaxEntries
+func WithMaxEntries(maxEntries int) Option {
+	return func(o *options) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// WithMaxEntryBytes 设置单条应答的大小上限，默认 DefaultMaxEntryBytes
+func WithMaxEntryBytes(maxEntryBytes int) Option {
+	return func(o *options) {
+		o.maxEntryBytes = maxEntryBytes
+	}
+}
+
+// WithMethods 开启缓存的方法，只有显式开启的方法才会缓存，方法必须是幂等的读操作
+func WithMethods(methods ...string) Option {
+	return func(o *options) {
+		for _, method := range methods {
+			o.methods[method] = struct{}{}
+		}
+	}
+}
+
+// Cache 按照服务及方法缓存成功应答的LRU缓存，并发安全
+type Cache struct {
+	opts    options
+	mutex   sync.Mutex
+	entries map[Key]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+type entry struct {
+	key      Key
+	value    interface{}
+	expireAt time.Time
+}
+
+// New 创建应答缓存
+func New(opts ...Option) *Cache {
+	c := &Cache{
+		opts: options{
+			ttl:           DefaultTTL,
+			maxEntries:    DefaultMaxEntries,
+			maxEntryBytes: DefaultMaxEntryBytes,
+			methods:       map[string]struct{}{},
+		},
+		entries: map[Key]*list.Element{},
+		lru:     list.New(),
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	return c
+}
+
+// Cacheable 方法是否开启了缓存
+func (c *Cache) Cacheable(method string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.opts.methods[method]
+	return ok
+}
+
+// MaxEntryBytes 单条应答的大小上限，集成层可据此限制收集应答的内存
+func (c *Cache) MaxEntryBytes() int {
+	return c.opts.maxEntryBytes
+}
+
+// Put 缓存成功的应答，size为应答大小，方法未开启缓存或者应答超过大小上限时忽略
+func (c *Cache) Put(key Key, value interface{}, size int) {
+	if !c.Cacheable(key.Method) || c.opts.maxEntries <= 0 || size > c.opts.maxEntryBytes {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	expireAt := c.now().Add(c.opts.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expireAt = expireAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, expireAt: expireAt})
+	for c.lru.Len() > c.opts.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// Get 获取未过期的缓存应答，过期的应答会被删除
+func (c *Cache) Get(key Key) (interface{}, bool) {
+	if !c.Cacheable(key.Method) {
+		return nil, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expireAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return e.value, true
+}
+
+// Len 当前缓存条数，包含尚未清理的过期应答
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
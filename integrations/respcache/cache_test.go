@@ -0,0 +1,65 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package respcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheOptInAndBounds(t *testing.T) {
+	c := New(WithMethods("get"), WithMaxEntries(2), WithMaxEntryBytes(8))
+	key := func(req string) Key {
+		return Key{Namespace: "Test", Service: "svc", Method: "get", Request: req}
+	}
+	c.Put(Key{Namespace: "Test", Service: "svc", Method: "set", Request: "a"}, "v", 1)
+	if c.Len() != 0 {
+		t.Fatal("expect method without opt-in not cached")
+	}
+	c.Put(key("big"), "v", 9)
+	if c.Len() != 0 {
+		t.Fatal("expect entry over size bound not cached")
+	}
+	c.Put(key("a"), "a", 1)
+	c.Put(key("b"), "b", 1)
+	if _, ok := c.Get(key("a")); !ok {
+		t.Fatal("expect entry a cached")
+	}
+	c.Put(key("c"), "c", 1)
+	if _, ok := c.Get(key("b")); ok {
+		t.Fatal("expect least recently used entry b evicted")
+	}
+	if value, ok := c.Get(key("a")); !ok || value != "a" {
+		t.Fatalf("expect entry a kept, got %v", value)
+	}
+}
+
+func TestCacheExpire(t *testing.T) {
+	now := time.Now()
+	c := New(WithMethods("get"), WithTTL(time.Second))
+	c.now = func() time.Time { return now }
+	key := Key{Namespace: "Test", Service: "svc", Method: "get"}
+	c.Put(key, "v", 1)
+	if _, ok := c.Get(key); !ok {
+		t.Fatal("expect entry cached before ttl")
+	}
+	now = now.Add(time.Second)
+	if _, ok := c.Get(key); ok || c.Len() != 0 {
+		t.Fatal("expect expired entry removed")
+	}
+}
@@ -0,0 +1,213 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package shadow 提供服务迁移时的影子请求对比：同一请求同时发往主服务与影子服务，调用方只拿到主服务的应答，
+// 影子服务的应答在后台与主服务的应答对比，对比结果通过 ConsumerAPI.ReportShadowComparison 上报，
+// 通过 shadow_comparison_total 指标观察影子服务的不一致率
+package shadow
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultTimeout 影子请求的默认超时时间，包含选择影子服务实例及发送请求
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxConcurrency 默认的影子请求最大并发数
+	DefaultMaxConcurrency = 64
+)
+
+// Call 向选中的实例发送请求，主服务与影子服务使用同一个 Call 发送请求，因此请求内容必须可以重复发送，
+// 并且只有幂等的读请求才适合发往影子服务
+type Call func(ctx context.Context, instance model.Instance) (interface{}, error)
+
+// Comparator 对比主服务与影子服务的应答，不一致时返回的描述只输出到日志
+type Comparator func(primary, shadow interface{}) (equal bool, detail string)
+
+// DefaultComparator 使用 reflect.DeepEqual 对比应答
+func DefaultComparator(primary, shadow interface{}) (bool, string) {
+	if reflect.DeepEqual(primary, shadow) {
+		return true, ""
+	}
+	return false, "responses are not deeply equal"
+}
+
+// Option 影子对比选项
+type Option func(*options)
+
+type options struct {
+	comparator     Comparator
+	sampleRate     float64
+	timeout        time.Duration
+	maxConcurrency int
+}
+
+// WithComparator 设置应答的对比方法，默认使用 DefaultComparator，
+// 应答中带有时间戳、请求ID等每次都不同的字段时需要自定义对比方法忽略这些字段
+func WithComparator(comparator Comparator) Option {
+	return func(o *options) {
+		o.comparator = comparator
+	}
+}
+
+// WithSampleRate 设置发送影子请求的比例，取值[0, 1]，默认为1，即每个请求都发送影子请求
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithTimeout 设置影子请求的超时时间，默认 DefaultTimeout；影子请求不使用调用方的上下文，
+// 主请求返回后影子请求仍会继续，直到完成或者超时
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxConcurrency 设置影子请求的最大并发数，默认 DefaultMaxConcurrency，超过时不发送影子请求，
+// 避免影子服务变慢时堆积协程
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(o *options) {
+		o.maxConcurrency = maxConcurrency
+	}
+}
+
+// Harness 影子请求对比器，并发安全
+type Harness struct {
+	consumer api.ConsumerAPI
+	primary  model.ServiceKey
+	shadow   model.ServiceKey
+	opts     options
+	permits  chan struct{}
+}
+
+// New 创建影子请求对比器，请求通过 ConsumerAPI 分别在主服务与影子服务中完成路由及负载均衡。
+// 使用根目录 polaris.ConsumerAPI 时，可通过 api.NewConsumerAPIByContext(consumer.SDKContext()) 获取 api.ConsumerAPI
+func New(consumer api.ConsumerAPI, primary, shadow model.ServiceKey, opts ...Option) *Harness {
+	h := &Harness{
+		consumer: consumer,
+		primary:  primary,
+		shadow:   shadow,
+		opts: options{
+			comparator:     DefaultComparator,
+			sampleRate:     1,
+			timeout:        DefaultTimeout,
+			maxConcurrency: DefaultMaxConcurrency,
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	if h.opts.maxConcurrency <= 0 {
+		h.opts.maxConcurrency = DefaultMaxConcurrency
+	}
+	h.permits = make(chan struct{}, h.opts.maxConcurrency)
+	return h
+}
+
+type primaryResult struct {
+	resp interface{}
+	err  error
+}
+
+// Do 选择主服务实例发送请求并返回其应答，按照采样比例同时选择影子服务实例在后台发送同一请求，
+// 两者都完成后对比应答并上报对比结果。影子请求不影响主请求的应答及耗时，主请求失败时不做对比
+func (h *Harness) Do(ctx context.Context, method string, call Call) (interface{}, error) {
+	var primaryDone chan primaryResult
+	if h.sampled() && h.acquire() {
+		primaryDone = make(chan primaryResult, 1)
+		go h.runShadow(method, call, primaryDone)
+	}
+	var resp interface{}
+	instance, err := h.selectInstance(ctx, h.primary)
+	if err == nil {
+		resp, err = call(ctx, instance)
+	}
+	if primaryDone != nil {
+		primaryDone <- primaryResult{resp: resp, err: err}
+	}
+	return resp, err
+}
+
+func (h *Harness) sampled() bool {
+	return h.opts.sampleRate >= 1 || rand.Float64() < h.opts.sampleRate
+}
+
+func (h *Harness) acquire() bool {
+	select {
+	case h.permits <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *Harness) selectInstance(ctx context.Context, svcKey model.ServiceKey) (model.Instance, error) {
+	req := &api.GetOneInstanceRequest{}
+	req.Namespace = svcKey.Namespace
+	req.Service = svcKey.Service
+	req.SetContext(ctx)
+	resp, err := h.consumer.GetOneInstance(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetInstance(), nil
+}
+
+// runShadow 发送影子请求，等待主请求完成后对比应答并上报
+func (h *Harness) runShadow(method string, call Call, primaryDone <-chan primaryResult) {
+	defer func() {
+		<-h.permits
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), h.opts.timeout)
+	defer cancel()
+	var resp interface{}
+	instance, err := h.selectInstance(ctx, h.shadow)
+	if err == nil {
+		resp, err = call(ctx, instance)
+	}
+	primary := <-primaryDone
+	if primary.err != nil {
+		return
+	}
+	req := &api.ReportShadowComparisonRequest{}
+	req.Namespace = h.primary.Namespace
+	req.Service = h.primary.Service
+	req.ShadowNamespace = h.shadow.Namespace
+	req.ShadowService = h.shadow.Service
+	req.Method = method
+	if err != nil {
+		req.Result = model.ShadowError
+		req.Detail = err.Error()
+	} else if equal, detail := h.opts.comparator(primary.resp, resp); equal {
+		req.Result = model.ShadowMatch
+	} else {
+		req.Result = model.ShadowMismatch
+		req.Detail = detail
+	}
+	if reportErr := h.consumer.ReportShadowComparison(req); reportErr != nil {
+		log.GetBaseLogger().Warnf("[shadow] fail to report comparison of %s: %v", method, reportErr)
+	}
+}
@@ -0,0 +1,114 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package shadow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+type stubConsumerAPI struct {
+	api.ConsumerAPI
+	reports chan *api.ReportShadowComparisonRequest
+}
+
+func (s *stubConsumerAPI) GetOneInstance(req *api.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	svcKey := &model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	resp := &model.OneInstanceResponse{}
+	resp.Instances = []model.Instance{pb.NewInstanceInProto(&apiservice.Instance{
+		Host: wrapperspb.String(req.Service),
+		Port: wrapperspb.UInt32(8080),
+	}, svcKey, nil)}
+	return resp, nil
+}
+
+func (s *stubConsumerAPI) ReportShadowComparison(req *api.ReportShadowComparisonRequest) error {
+	s.reports <- req
+	return nil
+}
+
+func TestHarness(t *testing.T) {
+	consumer := &stubConsumerAPI{reports: make(chan *api.ReportShadowComparisonRequest, 1)}
+	primary := model.ServiceKey{Namespace: "Test", Service: "legacy"}
+	shadow := model.ServiceKey{Namespace: "Test", Service: "rewrite"}
+	h := New(consumer, primary, shadow)
+
+	answers := map[string]interface{}{"legacy": "v1", "rewrite": "v1"}
+	errs := map[string]error{}
+	call := func(ctx context.Context, instance model.Instance) (interface{}, error) {
+		return answers[instance.GetHost()], errs[instance.GetHost()]
+	}
+	expect := func(result model.ShadowComparisonResult) {
+		t.Helper()
+		resp, err := h.Do(context.Background(), "/get", call)
+		if err != nil || resp != answers["legacy"] {
+			t.Fatalf("expect primary response returned, got %v, %v", resp, err)
+		}
+		select {
+		case report := <-consumer.reports:
+			if report.Result != result || report.Service != "legacy" || report.ShadowService != "rewrite" ||
+				report.Method != "/get" {
+				t.Fatalf("unexpected report %+v", report.ReportShadowComparisonRequest)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expect comparison reported")
+		}
+	}
+	expect(model.ShadowMatch)
+	answers["rewrite"] = "v2"
+	expect(model.ShadowMismatch)
+	errs["rewrite"] = errors.New("unavailable")
+	expect(model.ShadowError)
+
+	// 主请求失败时不对比
+	if _, err := h.Do(context.Background(), "/get", func(ctx context.Context,
+		instance model.Instance) (interface{}, error) {
+		if instance.GetHost() == "legacy" {
+			return nil, errors.New("unavailable")
+		}
+		return "v1", nil
+	}); err == nil {
+		t.Fatal("expect primary error returned")
+	}
+	select {
+	case report := <-consumer.reports:
+		t.Fatalf("expect no report when primary fails, got %+v", report.ReportShadowComparisonRequest)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// 采样比例为0时不发送影子请求
+	h = New(consumer, primary, shadow, WithSampleRate(0))
+	if _, err := h.Do(context.Background(), "/get", func(ctx context.Context,
+		instance model.Instance) (interface{}, error) {
+		if instance.GetHost() == "rewrite" {
+			t.Error("expect no shadow request")
+		}
+		return nil, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package expr
+
+import (
+	"strings"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RuleLabelKey 路由规则source标签以及限流规则自定义参数中的保留key，值为表达式，
+// 表达式求值为true时该项匹配成功，可与其他标签组合使用
+const RuleLabelKey = "$expr"
+
+// NewLabelsActivation 基于请求标签构建表达式变量：
+// labels 为全部标签，request 为按 $method、$header. 等保留前缀拆分后的请求属性，
+// 包括 request.method、request.path、request.caller_ip 以及 request.header、request.query、
+// request.cookie、request.caller_service 几个map
+func NewLabelsActivation(labels map[string]string) Activation {
+	header := make(map[string]string)
+	query := make(map[string]string)
+	cookie := make(map[string]string)
+	callerService := make(map[string]string)
+	request := map[string]interface{}{
+		"header":         header,
+		"query":          query,
+		"cookie":         cookie,
+		"caller_service": callerService,
+	}
+	for key, value := range labels {
+		switch {
+		case key == model.LabelKeyMethod:
+			request["method"] = value
+		case key == model.LabelKeyPath:
+			request["path"] = value
+		case key == model.LabelKeyCallerIp:
+			request["caller_ip"] = value
+		case strings.HasPrefix(key, model.LabelKeyHeader):
+			header[key[len(model.LabelKeyHeader):]] = value
+		case strings.HasPrefix(key, model.LabelKeyQuery):
+			query[key[len(model.LabelKeyQuery):]] = value
+		case strings.HasPrefix(key, model.LabelKeyCookie):
+			cookie[key[len(model.LabelKeyCookie):]] = value
+		case strings.HasPrefix(key, model.LabelKeyCallerService):
+			callerService[key[len(model.LabelKeyCallerService):]] = value
+		}
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return Activation{"labels": labels, "request": request}
+}
@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package expr
+
+import (
+	"sync"
+)
+
+// maxCachedPrograms 编译缓存的最大表达式数，超过后清空重建，避免规则频繁变更时缓存无限增长
+const maxCachedPrograms = 4096
+
+type cachedProgram struct {
+	program *Program
+	err     error
+}
+
+var (
+	programCache = make(map[string]*cachedProgram)
+	cacheMutex   sync.RWMutex
+)
+
+// CompileCached 编译表达式并缓存编译结果，编译失败的结果同样缓存，避免对同一个非法表达式重复编译
+func CompileCached(text string) (*Program, error) {
+	cacheMutex.RLock()
+	cached, ok := programCache[text]
+	cacheMutex.RUnlock()
+	if ok {
+		return cached.program, cached.err
+	}
+	program, err := Compile(text)
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	if len(programCache) >= maxCachedPrograms {
+		programCache = make(map[string]*cachedProgram)
+	}
+	programCache[text] = &cachedProgram{program: program, err: err}
+	return program, err
+}
@@ -0,0 +1,638 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Activation 表达式求值时可访问的变量，值支持 string、int64、float64、bool、
+// map[string]string、map[string]interface{} 以及 []interface{}
+type Activation map[string]interface{}
+
+// Program 编译后的表达式，可并发求值
+type Program struct {
+	text string
+	root node
+}
+
+// Compile 编译表达式
+func Compile(text string) (*Program, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, fmt.Errorf("compile expression %q: %w", text, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, fmt.Errorf("compile expression %q: %w", text, err)
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("compile expression %q: unexpected %q at %d", text, t.text, t.pos)
+	}
+	return &Program{text: text, root: root}, nil
+}
+
+// String 表达式原文
+func (p *Program) String() string {
+	return p.text
+}
+
+// Eval 对表达式求值
+func (p *Program) Eval(vars Activation) (interface{}, error) {
+	return p.root.eval(vars)
+}
+
+// Match 对表达式求值并要求结果为bool，求值出错时返回false及错误
+func (p *Program) Match(vars Activation) (bool, error) {
+	value, err := p.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q returns %T, expect bool", p.text, value)
+	}
+	return matched, nil
+}
+
+type node interface {
+	eval(vars Activation) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(Activation) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(vars Activation) (interface{}, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("undeclared reference to %q", n.name)
+	}
+	return normalize(value), nil
+}
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+func (n *selectNode) eval(vars Activation) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	value, found, err := lookupKey(operand, n.field)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no such key: %s", n.field)
+	}
+	return value, nil
+}
+
+type hasNode struct {
+	sel *selectNode
+}
+
+func (n *hasNode) eval(vars Activation) (interface{}, error) {
+	operand, err := n.sel.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	_, found, err := lookupKey(operand, n.sel.field)
+	return found, err
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+func (n *indexNode) eval(vars Activation) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := operand.([]interface{}); ok {
+		i, ok := index.(int64)
+		if !ok {
+			return nil, fmt.Errorf("list index must be int, got %T", index)
+		}
+		if i < 0 || i >= int64(len(list)) {
+			return nil, fmt.Errorf("index out of range: %d", i)
+		}
+		return list[i], nil
+	}
+	key, ok := index.(string)
+	if !ok {
+		return nil, fmt.Errorf("map key must be string, got %T", index)
+	}
+	value, found, err := lookupKey(operand, key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no such key: %s", key)
+	}
+	return value, nil
+}
+
+type listNode struct {
+	elems []node
+}
+
+func (n *listNode) eval(vars Activation) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.elems))
+	for _, elem := range n.elems {
+		value, err := elem.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+type condNode struct {
+	cond      node
+	then      node
+	otherwise node
+}
+
+func (n *condNode) eval(vars Activation) (interface{}, error) {
+	cond, err := n.cond.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cond.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condition must be bool, got %T", cond)
+	}
+	if b {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(vars Activation) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case bool:
+		if n.op == "!" {
+			return !v, nil
+		}
+	case int64:
+		if n.op == "-" {
+			return -v, nil
+		}
+	case float64:
+		if n.op == "-" {
+			return -v, nil
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s%T", n.op, value)
+}
+
+// logicNode && 及 ||，一侧出错时以另一侧的确定结果为准
+type logicNode struct {
+	and   bool
+	left  node
+	right node
+}
+
+func (n *logicNode) eval(vars Activation) (interface{}, error) {
+	left, leftErr := evalBool(n.left, vars)
+	if leftErr == nil && left != n.and {
+		return left, nil
+	}
+	right, rightErr := evalBool(n.right, vars)
+	if rightErr == nil && right != n.and {
+		return right, nil
+	}
+	if leftErr != nil {
+		return nil, leftErr
+	}
+	if rightErr != nil {
+		return nil, rightErr
+	}
+	return n.and, nil
+}
+
+func evalBool(n node, vars Activation) (bool, error) {
+	value, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("logical operand must be bool, got %T", value)
+	}
+	return b, nil
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n *binaryNode) eval(vars Activation) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equals(left, right), nil
+	case "!=":
+		return !equals(left, right), nil
+	case "in":
+		return contains(right, left)
+	case "<", "<=", ">", ">=":
+		cmp, err := compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+	return arithmetic(n.op, left, right)
+}
+
+type callNode struct {
+	fn     string
+	target node
+	args   []node
+	// 参数为字面量时预编译的正则
+	regex *regexp.Regexp
+}
+
+// newCallNode 创建函数调用节点，matches的参数为字面量时在编译期编译正则
+func newCallNode(fn string, target node, args []node) (node, error) {
+	call := &callNode{fn: fn, target: target, args: args}
+	if fn == "matches" && len(args) == 1 {
+		if literal, ok := args[0].(*literalNode); ok {
+			pattern, ok := literal.value.(string)
+			if !ok {
+				return nil, fmt.Errorf("matches() requires string pattern")
+			}
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			call.regex = regex
+		}
+	}
+	return call, nil
+}
+
+func (n *callNode) eval(vars Activation) (interface{}, error) {
+	values := make([]interface{}, 0, len(n.args)+1)
+	if n.target != nil {
+		target, err := n.target.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, target)
+	}
+	for _, arg := range n.args {
+		value, err := arg.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	switch n.fn {
+	case "size":
+		if len(values) == 1 {
+			return size(values[0])
+		}
+	case "int":
+		if len(values) == 1 {
+			return toInt(values[0])
+		}
+	case "double":
+		if len(values) == 1 {
+			return toDouble(values[0])
+		}
+	case "string":
+		if len(values) == 1 {
+			return toString(values[0]), nil
+		}
+	case "startsWith", "endsWith", "contains", "matches":
+		if n.target != nil && len(values) == 2 {
+			s, ok1 := values[0].(string)
+			sub, ok2 := values[1].(string)
+			if !ok1 || !ok2 {
+				break
+			}
+			switch n.fn {
+			case "startsWith":
+				return strings.HasPrefix(s, sub), nil
+			case "endsWith":
+				return strings.HasSuffix(s, sub), nil
+			case "contains":
+				return strings.Contains(s, sub), nil
+			}
+			regex := n.regex
+			if regex == nil {
+				var err error
+				if regex, err = regexp.Compile(sub); err != nil {
+					return nil, fmt.Errorf("invalid pattern %q: %w", sub, err)
+				}
+			}
+			return regex.MatchString(s), nil
+		}
+	case "lowerAscii", "upperAscii":
+		if n.target != nil && len(values) == 1 {
+			s, ok := values[0].(string)
+			if !ok {
+				break
+			}
+			if n.fn == "lowerAscii" {
+				return strings.ToLower(s), nil
+			}
+			return strings.ToUpper(s), nil
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s with %d arguments", n.fn, len(values))
+}
+
+// normalize 将变量中的数值统一为int64及float64
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []string:
+		list := make([]interface{}, 0, len(v))
+		for _, s := range v {
+			list = append(list, s)
+		}
+		return list
+	case Activation:
+		return map[string]interface{}(v)
+	}
+	return value
+}
+
+func lookupKey(operand interface{}, key string) (interface{}, bool, error) {
+	switch m := operand.(type) {
+	case map[string]string:
+		value, ok := m[key]
+		return value, ok, nil
+	case map[string]interface{}:
+		value, ok := m[key]
+		return normalize(value), ok, nil
+	case nil:
+		return nil, false, nil
+	}
+	return nil, false, fmt.Errorf("type %T does not support field selection", operand)
+}
+
+func equals(left, right interface{}) bool {
+	if l, r, ok := toNumbers(left, right); ok {
+		return l == r
+	}
+	switch l := left.(type) {
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok || len(l) != len(r) {
+			return false
+		}
+		for i := range l {
+			if !equals(l[i], r[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]string, map[string]interface{}:
+		return false
+	}
+	return left == right
+}
+
+func contains(container, elem interface{}) (interface{}, error) {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, item := range c {
+			if equals(item, elem) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]string, map[string]interface{}:
+		key, ok := elem.(string)
+		if !ok {
+			return false, nil
+		}
+		_, found, err := lookupKey(c, key)
+		return found, err
+	}
+	return nil, fmt.Errorf("no such overload: in %T", container)
+}
+
+func compare(left, right interface{}) (int, error) {
+	if l, r, ok := toNumbers(left, right); ok {
+		switch {
+		case l < r:
+			return -1, nil
+		case l > r:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	l, ok1 := left.(string)
+	r, ok2 := right.(string)
+	if ok1 && ok2 {
+		return strings.Compare(l, r), nil
+	}
+	return 0, fmt.Errorf("no such overload: compare %T and %T", left, right)
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	li, lInt := left.(int64)
+	ri, rInt := right.(int64)
+	if lInt && rInt {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	if l, r, ok := toNumbers(left, right); ok {
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			return l / r, nil
+		case "%":
+			return math.Mod(l, r), nil
+		}
+	}
+	if op == "+" {
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+		if l, ok := left.([]interface{}); ok {
+			if r, ok := right.([]interface{}); ok {
+				return append(append(make([]interface{}, 0, len(l)+len(r)), l...), r...), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %T %s %T", left, op, right)
+}
+
+func toNumbers(left, right interface{}) (float64, float64, bool) {
+	l, ok1 := toFloat(left)
+	r, ok2 := toFloat(right)
+	return l, r, ok1 && ok2
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func size(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case []interface{}:
+		return int64(len(v)), nil
+	case map[string]string:
+		return int64(len(v)), nil
+	case map[string]interface{}:
+		return int64(len(v)), nil
+	}
+	return nil, fmt.Errorf("no such overload: size(%T)", value)
+}
+
+func toInt(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("int(%q): %w", v, err)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("no such overload: int(%T)", value)
+}
+
+func toDouble(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("double(%q): %w", v, err)
+		}
+		return f, nil
+	}
+	return nil, fmt.Errorf("no such overload: double(%T)", value)
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
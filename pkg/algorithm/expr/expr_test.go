@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package expr
+
+import (
+	"testing"
+)
+
+func TestProgramMatch(t *testing.T) {
+	labels := map[string]string{
+		"uid":               "10023",
+		"env":               "gray",
+		"$method":           "POST",
+		"$path":             "/api/v1/orders",
+		"$header.x-version": "v2",
+		"$caller_ip":        "10.0.0.8",
+	}
+	tests := []struct {
+		name    string
+		text    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "字符串方法与逻辑与", text: `labels.uid.endsWith("3") && request.method == "POST"`, want: true},
+		{name: "header取值", text: `request.header["x-version"] == "v2"`, want: true},
+		{name: "in列表", text: `labels.env in ["gray", "canary"]`, want: true},
+		{name: "has判断", text: `has(labels.region)`, want: false},
+		{name: "逻辑或短路", text: `has(labels.region) && labels.region == "sz" || request.path.startsWith("/api")`, want: true},
+		{name: "类型转换与取模", text: `int(labels.uid) % 100 < 50`, want: true},
+		{name: "正则匹配", text: `request.caller_ip.matches("^10\\.0\\.")`, want: true},
+		{name: "三元表达式", text: `(size(labels.uid) > 3 ? labels.env : "base") != "gray"`, want: false},
+		{name: "取不存在的key", text: `labels.region == "sz"`, wantErr: true},
+		{name: "结果非bool", text: `labels.uid`, wantErr: true},
+	}
+	vars := NewLabelsActivation(labels)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Compile(tt.text)
+			if err != nil {
+				t.Fatalf("compile %s: %v", tt.text, err)
+			}
+			got, err := program.Match(vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("match %s, err = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("match %s = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, text := range []string{``, `labels.uid ==`, `(labels.uid == "1"`, `labels.uid == "1" )`, `"abc`} {
+		if _, err := CompileCached(text); err == nil {
+			t.Errorf("compile %q expect error", text)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package hash
+
+import (
+	"hash/fnv"
+)
+
+// BucketCount 一致性分桶的桶数，百分比精确到两位小数
+const BucketCount = 10000
+
+// Bucket 计算标签值在指定盐值下所在的桶，范围 [0, BucketCount)，使用 FNV-1a 哈希，
+// 保证跨进程、跨语言的结果一致，灰度分桶路由与A/B实验分组共用该算法
+func Bucket(salt, value string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(salt))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(value))
+	return h.Sum32() % BucketCount
+}
+
+// PercentToBuckets 将百分比换算为桶数
+func PercentToBuckets(percent float64) uint32 {
+	if percent <= 0 {
+		return 0
+	}
+	if percent >= 100 {
+		return BucketCount
+	}
+	return uint32(percent * BucketCount / 100)
+}
+
+// InBucket 判断标签值是否落在百分比内
+func InBucket(salt, value string, percent float64) bool {
+	if percent <= 0 {
+		return false
+	}
+	if percent >= 100 {
+		return true
+	}
+	return Bucket(salt, value) < PercentToBuckets(percent)
+}
@@ -21,6 +21,8 @@ package clock
 import (
 	"sync/atomic"
 	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 // 全局时钟
@@ -71,7 +73,7 @@ func init() {
 	globalClock = &clockImpl{}
 	now := time.Now()
 	globalClock.currentTime.Store(&now)
-	go globalClock.updateTime()
+	profiling.Go("clock", "updateTime", globalClock.updateTime)
 }
 
 func CurrentMillis() int64 {
@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// AddressMappingConfig 静态地址映射规则.
+type AddressMappingConfig struct {
+	// 实例注册的地址，可以是IP或者CIDR
+	From string `yaml:"from" json:"from"`
+	// 转换后的地址，格式为host或者host:port，不带端口时保留实例的端口
+	To string `yaml:"to" json:"to"`
+}
+
+// AddressTranslationConfigImpl 实例地址转换配置，混合网络中按本进程所在的网络区域将实例地址转换为可达地址.
+type AddressTranslationConfigImpl struct {
+	// 本进程所在的网络区域，为空时不进行地址转换
+	Network string `yaml:"network" json:"network"`
+	// 实例元数据中各网络区域可达地址的key前缀，元数据<prefix><network>的值为host:port
+	MetadataKeyPrefix string `yaml:"metadataKeyPrefix" json:"metadataKeyPrefix"`
+	// 静态映射规则，实例元数据中没有本网络区域的地址时按顺序匹配
+	Mappings []*AddressMappingConfig `yaml:"mappings" json:"mappings"`
+}
+
+// GetNetwork 获取本进程所在的网络区域.
+func (a *AddressTranslationConfigImpl) GetNetwork() string {
+	return a.Network
+}
+
+// SetNetwork 设置本进程所在的网络区域.
+func (a *AddressTranslationConfigImpl) SetNetwork(network string) {
+	a.Network = network
+}
+
+// GetMetadataKeyPrefix 获取实例元数据中可达地址的key前缀.
+func (a *AddressTranslationConfigImpl) GetMetadataKeyPrefix() string {
+	return a.MetadataKeyPrefix
+}
+
+// SetMetadataKeyPrefix 设置实例元数据中可达地址的key前缀.
+func (a *AddressTranslationConfigImpl) SetMetadataKeyPrefix(prefix string) {
+	a.MetadataKeyPrefix = prefix
+}
+
+// GetMappings 获取静态映射规则.
+func (a *AddressTranslationConfigImpl) GetMappings() []*AddressMappingConfig {
+	return a.Mappings
+}
+
+// SetMappings 设置静态映射规则.
+func (a *AddressTranslationConfigImpl) SetMappings(mappings []*AddressMappingConfig) {
+	a.Mappings = mappings
+}
+
+// Verify 检验地址转换配置.
+func (a *AddressTranslationConfigImpl) Verify() error {
+	if nil == a {
+		return errors.New("AddressTranslationConfig is nil")
+	}
+	var errs error
+	for i, mapping := range a.Mappings {
+		if nil == mapping {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.addressTranslation.mappings[%d] is nil", i))
+			continue
+		}
+		if _, _, err := net.ParseCIDR(mapping.From); err != nil && net.ParseIP(mapping.From) == nil {
+			errs = multierror.Append(errs, fmt.Errorf(
+				"consumer.addressTranslation.mappings[%d].from %s is neither an IP nor a CIDR", i, mapping.From))
+		}
+		if len(strings.TrimSpace(mapping.To)) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.addressTranslation.mappings[%d].to is empty", i))
+		}
+	}
+	return errs
+}
+
+// SetDefault 设置地址转换配置的默认值.
+func (a *AddressTranslationConfigImpl) SetDefault() {
+	if len(a.MetadataKeyPrefix) == 0 {
+		a.MetadataKeyPrefix = DefaultAddressTranslationMetadataKeyPrefix
+	}
+}
+
+// Init 初始化地址转换配置.
+func (a *AddressTranslationConfigImpl) Init() {
+}
@@ -46,6 +46,29 @@ type GlobalConfig interface {
 	GetLocation() LocationConfig
 	// GetClient global.client前缀开头的所有配置项
 	GetClient() ClientConfig
+	// GetSidecar global.sidecar前缀开头的所有配置项
+	GetSidecar() SidecarConfig
+}
+
+// SidecarConfig 本机polaris-sidecar配置.
+type SidecarConfig interface {
+	BaseConfig
+	// GetMode 获取使用sidecar的模式，可选值：never、auto、always
+	GetMode() string
+	// SetMode 设置使用sidecar的模式
+	SetMode(string)
+	// GetAddress 获取sidecar服务发现接口的地址
+	GetAddress() string
+	// SetAddress 设置sidecar服务发现接口的地址
+	SetAddress(string)
+	// GetConfigAddress 获取sidecar配置中心接口的地址
+	GetConfigAddress() string
+	// SetConfigAddress 设置sidecar配置中心接口的地址
+	SetConfigAddress(string)
+	// GetDetectTimeout 获取自动模式下探测sidecar的超时时间
+	GetDetectTimeout() time.Duration
+	// SetDetectTimeout 设置自动模式下探测sidecar的超时时间
+	SetDetectTimeout(time.Duration)
 }
 
 // ConsumerConfig consumer config object.
@@ -63,6 +86,120 @@ type ConsumerConfig interface {
 	GetHealthCheck() HealthCheckConfig
 	// GetServiceSpecific 服务独立配置
 	GetServiceSpecific(namespace string, service string) ServiceSpecificConfig
+	// GetServicesSpecific 全部服务独立配置
+	GetServicesSpecific() []ServiceSpecificConfig
+	// GetNamespaceSpecific 命名空间级默认配置，命名空间下的服务继承
+	GetNamespaceSpecific(namespace string) NamespaceSpecificConfig
+	// GetNamespacesSpecific 全部命名空间级默认配置
+	GetNamespacesSpecific() []NamespaceSpecificConfig
+	// GetDNSServer 内置DNS服务配置
+	GetDNSServer() DNSServerConfig
+	// GetRequestBudget 单次逻辑请求的尝试预算配置
+	GetRequestBudget() RequestBudgetConfig
+	// GetAddressTranslation 实例地址转换配置
+	GetAddressTranslation() AddressTranslationConfig
+	// GetNameMapping 服务名映射配置
+	GetNameMapping() NameMappingConfig
+	// GetNamespaceFallback 命名空间回退配置
+	GetNamespaceFallback() NamespaceFallbackConfig
+	// GetContractValidation 契约校验配置
+	GetContractValidation() ContractValidationConfig
+}
+
+// ContractValidationConfig 契约校验配置.
+type ContractValidationConfig interface {
+	BaseConfig
+	// IsEnable 是否启用契约校验
+	IsEnable() bool
+	// SetEnable 设置是否启用契约校验
+	SetEnable(enable bool)
+	// GetProtocol 默认的契约协议
+	GetProtocol() string
+	// SetProtocol 设置默认的契约协议
+	SetProtocol(protocol string)
+	// GetRefreshInterval 服务契约的本地缓存时间
+	GetRefreshInterval() time.Duration
+	// SetRefreshInterval 设置服务契约的本地缓存时间
+	SetRefreshInterval(interval time.Duration)
+}
+
+// NamespaceFallbackConfig 命名空间回退配置.
+type NamespaceFallbackConfig interface {
+	BaseConfig
+	// GetRules 回退规则
+	GetRules() []*NamespaceFallbackRule
+	// SetRules 设置回退规则
+	SetRules([]*NamespaceFallbackRule)
+	// GetFallbacks 命名空间的回退链，未配置时返回nil
+	GetFallbacks(namespace string) []string
+}
+
+// NameMappingConfig 服务名映射配置.
+type NameMappingConfig interface {
+	BaseConfig
+	// GetRules 静态映射规则
+	GetRules() []*NameMappingRule
+	// SetRules 设置静态映射规则
+	SetRules([]*NameMappingRule)
+	// GetFile 映射规则文件，文件变更后自动生效
+	GetFile() string
+	// SetFile 设置映射规则文件
+	SetFile(string)
+	// GetRefreshInterval 映射规则文件的变更检查周期
+	GetRefreshInterval() time.Duration
+	// SetRefreshInterval 设置映射规则文件的变更检查周期
+	SetRefreshInterval(time.Duration)
+}
+
+// AddressTranslationConfig 实例地址转换配置.
+type AddressTranslationConfig interface {
+	BaseConfig
+	// GetNetwork 本进程所在的网络区域，为空时不进行地址转换
+	GetNetwork() string
+	// SetNetwork 设置本进程所在的网络区域
+	SetNetwork(string)
+	// GetMetadataKeyPrefix 实例元数据中各网络区域可达地址的key前缀
+	GetMetadataKeyPrefix() string
+	// SetMetadataKeyPrefix 设置实例元数据中可达地址的key前缀
+	SetMetadataKeyPrefix(string)
+	// GetMappings 静态映射规则
+	GetMappings() []*AddressMappingConfig
+	// SetMappings 设置静态映射规则
+	SetMappings([]*AddressMappingConfig)
+}
+
+// RequestBudgetConfig 单次逻辑请求的尝试预算配置.
+type RequestBudgetConfig interface {
+	BaseConfig
+	// GetMaxAttempts 获取单次逻辑请求最多发起的尝试次数，包括首次请求、重试及对冲请求
+	GetMaxAttempts() int
+	// SetMaxAttempts 设置单次逻辑请求最多发起的尝试次数
+	SetMaxAttempts(int)
+	// GetTimeoutMultiplier 获取总耗时上限相对于单次请求超时时间的倍数，为0时不限制总耗时
+	GetTimeoutMultiplier() float64
+	// SetTimeoutMultiplier 设置总耗时上限相对于单次请求超时时间的倍数
+	SetTimeoutMultiplier(float64)
+}
+
+// DNSServerConfig 内置DNS服务配置.
+type DNSServerConfig interface {
+	BaseConfig
+	// IsEnable 是否启用内置DNS服务
+	IsEnable() bool
+	// SetEnable 设置是否启用内置DNS服务
+	SetEnable(bool)
+	// GetAddress 获取监听地址，同时监听UDP及TCP
+	GetAddress() string
+	// SetAddress 设置监听地址
+	SetAddress(string)
+	// GetDomain 获取服务域名后缀，查询的域名格式为 service.namespace.<domain>
+	GetDomain() string
+	// SetDomain 设置服务域名后缀
+	SetDomain(string)
+	// GetTTL 获取DNS记录的TTL
+	GetTTL() time.Duration
+	// SetTTL 设置DNS记录的TTL
+	SetTTL(time.Duration)
 }
 
 // ProviderConfig 被调端配置对象.
@@ -72,6 +209,100 @@ type ProviderConfig interface {
 	GetRateLimit() RateLimitConfig
 	// GetMinRegisterInterval get minimum interval between two register operation
 	GetMinRegisterInterval() time.Duration
+	// GetFlapping 获取注册状态抖动检测配置
+	GetFlapping() FlappingConfig
+	// GetHeartbeat 获取心跳上报配置
+	GetHeartbeat() HeartbeatConfig
+	// GetDualRegistration 获取双注册配置
+	GetDualRegistration() DualRegistrationConfig
+	// GetProvisionalInstance 获取自注册实例本地注入配置
+	GetProvisionalInstance() ProvisionalInstanceConfig
+	// GetLameduckTTL 获取进入lameduck状态后实例的心跳TTL，单位秒
+	GetLameduckTTL() int
+	// SetLameduckTTL 设置进入lameduck状态后实例的心跳TTL
+	SetLameduckTTL(ttl int)
+	// GetShutdownDrainPeriod 获取优雅下线时等待在途请求处理完成的最长时间
+	GetShutdownDrainPeriod() time.Duration
+	// SetShutdownDrainPeriod 设置优雅下线时等待在途请求处理完成的最长时间
+	SetShutdownDrainPeriod(period time.Duration)
+}
+
+// ProvisionalInstanceConfig 自注册实例本地注入配置.
+type ProvisionalInstanceConfig interface {
+	BaseConfig
+	// IsEnable 是否在注册成功后立即将实例注入本进程的GetInstances结果
+	IsEnable() bool
+	// SetEnable 设置是否启用自注册实例本地注入
+	SetEnable(enable bool)
+	// GetTTL 未被服务端确认的临时实例的存活时间
+	GetTTL() time.Duration
+	// SetTTL 设置临时实例的存活时间
+	SetTTL(ttl time.Duration)
+}
+
+// DualRegistrationConfig 双注册配置，用于注册中心迁移期间同时注册到新旧两个集群.
+type DualRegistrationConfig interface {
+	BaseConfig
+	// IsEnable 是否启用双注册
+	IsEnable() bool
+	// SetEnable 设置是否启用双注册
+	SetEnable(enable bool)
+	// GetAddresses 旧集群的服务端地址列表
+	GetAddresses() []string
+	// SetAddresses 设置旧集群的服务端地址列表
+	SetAddresses(addresses []string)
+	// GetToken 旧集群的鉴权token，为空时使用serverConnector的token
+	GetToken() string
+	// SetToken 设置旧集群的鉴权token
+	SetToken(token string)
+	// IsReverse 是否反转主备角色，反转后旧集群为主集群
+	IsReverse() bool
+	// SetReverse 设置是否反转主备角色
+	SetReverse(reverse bool)
+}
+
+// HeartbeatConfig 心跳上报配置.
+type HeartbeatConfig interface {
+	BaseConfig
+	// GetProtocol 心跳协议，grpc或udp
+	GetProtocol() string
+	// SetProtocol 设置心跳协议
+	SetProtocol(protocol string)
+	// GetUDPAddresses UDP心跳的服务端地址列表
+	GetUDPAddresses() []string
+	// SetUDPAddresses 设置UDP心跳的服务端地址列表
+	SetUDPAddresses(addresses []string)
+	// GetUDPAckTimeout UDP心跳应答等待时间
+	GetUDPAckTimeout() time.Duration
+	// GetUDPMaxLoss UDP心跳连续丢包次数上限，超过后回退到GRPC
+	GetUDPMaxLoss() int
+	// GetUDPFallbackInterval 回退到GRPC后重新尝试UDP心跳的间隔
+	GetUDPFallbackInterval() time.Duration
+}
+
+// FlappingConfig 注册状态抖动检测配置.
+type FlappingConfig interface {
+	BaseConfig
+	// IsEnable 是否启用抖动检测
+	IsEnable() bool
+	// SetEnable 设置是否启用抖动检测
+	SetEnable(enable bool)
+	// GetWindow 获取统计窗口
+	GetWindow() time.Duration
+	// SetWindow 设置统计窗口
+	SetWindow(window time.Duration)
+	// GetThreshold 获取窗口内允许的最大状态变化次数
+	GetThreshold() int
+	// SetThreshold 设置窗口内允许的最大状态变化次数
+	SetThreshold(threshold int)
+	// GetBaseBackoff 获取首次退避时长
+	GetBaseBackoff() time.Duration
+	// SetBaseBackoff 设置首次退避时长
+	SetBaseBackoff(backoff time.Duration)
+	// GetMaxBackoff 获取最大退避时长
+	GetMaxBackoff() time.Duration
+	// SetMaxBackoff 设置最大退避时长
+	SetMaxBackoff(backoff time.Duration)
 }
 
 // ConfigFileConfig 配置中心的配置.
@@ -91,6 +322,18 @@ type ConfigFileConfig interface {
 	GetPropertiesValueExpireTime() int64
 	// GetLocalCache .
 	GetLocalCache() ConfigLocalCacheConfig
+	// GetResubscribeJitter 配置订阅断线恢复后重新订阅的随机延迟上限
+	GetResubscribeJitter() time.Duration
+	// SetResubscribeJitter 设置配置订阅断线恢复后重新订阅的随机延迟上限
+	SetResubscribeJitter(jitter time.Duration)
+	// GetMaxConcurrentFetches 从服务端拉取配置文件的最大并发数，0表示不限制
+	GetMaxConcurrentFetches() int
+	// SetMaxConcurrentFetches 设置从服务端拉取配置文件的最大并发数
+	SetMaxConcurrentFetches(maxFetches int)
+	// GetFetchJitter 收到配置变更通知后拉取配置的随机延迟上限
+	GetFetchJitter() time.Duration
+	// SetFetchJitter 设置收到配置变更通知后拉取配置的随机延迟上限
+	SetFetchJitter(jitter time.Duration)
 }
 
 // RateLimitConfig 限流相关配置.
@@ -117,6 +360,39 @@ type RateLimitConfig interface {
 	SetLimiterNamespace(value string)
 	// GetLimiterNamespace 获取限流命名空间
 	GetLimiterNamespace() string
+	// IsShareLocalQuota 同一主机上的多个进程是否共享单机限流配额
+	IsShareLocalQuota() bool
+	// SetShareLocalQuota 设置同一主机上的多个进程是否共享单机限流配额
+	SetShareLocalQuota(bool)
+	// GetShareDir 获取共享配额的内存映射文件目录
+	GetShareDir() string
+	// SetShareDir 设置共享配额的内存映射文件目录
+	SetShareDir(string)
+	// GetTopK 获取限流热点标签值统计配置
+	GetTopK() RateLimitTopKConfig
+	// GetExemptions 获取限流豁免列表
+	GetExemptions() []*RateLimitExemptionConfig
+}
+
+// RateLimitTopKConfig 限流热点标签值统计配置.
+type RateLimitTopKConfig interface {
+	BaseConfig
+	// IsEnable 是否按限流规则统计消耗配额最多的标签值
+	IsEnable() bool
+	// SetEnable 设置是否统计热点标签值
+	SetEnable(bool)
+	// GetSize 每条规则每个周期上报的标签值数量
+	GetSize() int
+	// SetSize 设置每条规则每个周期上报的标签值数量
+	SetSize(int)
+	// GetMaxTracked 每条规则最多跟踪的标签值数量，用于限制内存占用
+	GetMaxTracked() int
+	// SetMaxTracked 设置每条规则最多跟踪的标签值数量
+	SetMaxTracked(int)
+	// GetInterval 热点标签值的上报周期
+	GetInterval() time.Duration
+	// SetInterval 设置热点标签值的上报周期
+	SetInterval(time.Duration)
 }
 
 // SystemConfig 系统配置信息.
@@ -196,6 +472,26 @@ type APIConfig interface {
 	GetRetryInterval() time.Duration
 	// SetRetryInterval 设置api调用重试时间
 	SetRetryInterval(time.Duration)
+	// GetPluginPanicThreshold global.api.pluginPanicThreshold
+	// 插件在统计窗口内panic达到该次数后被禁用，0表示不禁用
+	GetPluginPanicThreshold() int
+	// SetPluginPanicThreshold 设置插件禁用的panic次数
+	SetPluginPanicThreshold(int)
+	// GetPluginPanicWindow global.api.pluginPanicWindow
+	// 插件panic次数的统计窗口
+	GetPluginPanicWindow() time.Duration
+	// SetPluginPanicWindow 设置插件panic次数的统计窗口
+	SetPluginPanicWindow(time.Duration)
+	// GetRecentCallsSize global.api.recentCallsSize
+	// 内存中保留的最近API调用记录数，-1表示不记录
+	GetRecentCallsSize() int
+	// SetRecentCallsSize 设置内存中保留的最近API调用记录数
+	SetRecentCallsSize(int)
+	// GetFlowBudget global.api.flowBudget
+	// 获取单个实例整个流程（缓存等待、路由及负载均衡）的默认耗时预算，0表示不限制
+	GetFlowBudget() time.Duration
+	// SetFlowBudget 设置获取单个实例整个流程的默认耗时预算
+	SetFlowBudget(time.Duration)
 }
 
 // StatReporterConfig 统计上报配置.
@@ -210,6 +506,29 @@ type StatReporterConfig interface {
 	GetChain() []string
 	// SetChain 设置统计上报器插件链
 	SetChain([]string)
+	// GetCostLabels 进程级别的成本归属标签（如 team、product、env），会附加到所有调用结果以及限流上报中
+	GetCostLabels() map[string]string
+	// SetCostLabels 设置成本归属标签
+	SetCostLabels(map[string]string)
+	// GetCallerLabels 服务调用指标中主调服务标签的配置
+	GetCallerLabels() CallerLabelsConfig
+}
+
+// CallerLabelsConfig 服务调用指标中主调服务标签(caller_namespace/caller_service)的配置.
+type CallerLabelsConfig interface {
+	BaseConfig
+	// IsEnable 是否在服务调用指标中携带主调服务标签
+	IsEnable() bool
+	// SetEnable 设置是否携带主调服务标签
+	SetEnable(enable bool)
+	// GetNamespace 调用请求未携带主调信息时使用的默认主调命名空间
+	GetNamespace() string
+	// SetNamespace 设置默认主调命名空间
+	SetNamespace(namespace string)
+	// GetService 调用请求未携带主调信息时使用的默认主调服务名
+	GetService() string
+	// SetService 设置默认主调服务名
+	SetService(service string)
 }
 
 // LocationConfig SDK获取自身当前地理位置配置.
@@ -227,6 +546,10 @@ type ClientConfig interface {
 	GetId() string
 	// GetLabels 获取客户端标签
 	GetLabels() map[string]string
+	// IsIdentityMetadataEnable 是否在访问服务端的请求中携带SDK版本、进程号、主机名及客户端标签等身份信息
+	IsIdentityMetadataEnable() bool
+	// SetIdentityMetadataEnable 设置是否在访问服务端的请求中携带客户端身份信息
+	SetIdentityMetadataEnable(enable bool)
 }
 
 // ServerConnectorConfig 与名字服务服务端的连接配置.
@@ -276,6 +599,26 @@ type ServerConnectorConfig interface {
 	GetToken() string
 	// SetToken .
 	SetToken(string)
+	// GetEndpoints 获取带优先级及权重的server地址
+	GetEndpoints() []*ServerEndpointConfig
+	// SetEndpoints 设置带优先级及权重的server地址
+	SetEndpoints([]*ServerEndpointConfig)
+	// GetFailbackInterval 切换到低优先级server后，尝试切回高优先级server的间隔
+	GetFailbackInterval() time.Duration
+	// SetFailbackInterval 设置尝试切回高优先级server的间隔
+	SetFailbackInterval(time.Duration)
+	// GetMaxConnectionAge 连接的最大存活时间，超过后重建连接，0表示不限制
+	GetMaxConnectionAge() time.Duration
+	// SetMaxConnectionAge 设置连接的最大存活时间
+	SetMaxConnectionAge(time.Duration)
+	// GetMaxConnectionIdle 连接的最大空闲时间，超过后释放连接，0表示不释放
+	GetMaxConnectionIdle() time.Duration
+	// SetMaxConnectionIdle 设置连接的最大空闲时间
+	SetMaxConnectionIdle(time.Duration)
+	// GetReconnectJitter 连接超过最大存活时间后重建的随机延迟上限
+	GetReconnectJitter() time.Duration
+	// SetReconnectJitter 设置连接超过最大存活时间后重建的随机延迟上限
+	SetReconnectJitter(time.Duration)
 }
 
 // LocalCacheConfig 本地缓存相关配置项.
@@ -307,6 +650,11 @@ type LocalCacheConfig interface {
 	GetType() string
 	// SetType 设置本地缓存类型
 	SetType(string)
+	// GetPersistBackend consumer.localCache.persistBackend
+	// 本地缓存持久化存储后端，file为每个资源一个文件，kv为单文件的嵌入式KV存储
+	GetPersistBackend() string
+	// SetPersistBackend 设置本地缓存持久化存储后端
+	SetPersistBackend(string)
 	// GetPersistMaxWriteRetry consumer.localCache.persistMaxWriteRetry
 	// 缓存最大写重试次数
 	GetPersistMaxWriteRetry() int
@@ -334,6 +682,81 @@ type LocalCacheConfig interface {
 	SetPushEmptyProtection(pushEmptyProtection bool)
 	// GetPushEmptyProtection 获取推空保护开关
 	GetPushEmptyProtection() bool
+	// GetWarmUpManifest 获取缓存预热清单文件路径
+	GetWarmUpManifest() string
+	// SetWarmUpManifest 设置缓存预热清单文件路径
+	SetWarmUpManifest(manifest string)
+	// GetHealthSmoothingThreshold 获取实例健康状态平滑阈值，连续观察到该次数的相同新状态后路由才使用新的健康状态
+	GetHealthSmoothingThreshold() int
+	// SetHealthSmoothingThreshold 设置实例健康状态平滑阈值
+	SetHealthSmoothingThreshold(threshold int)
+	// GetPreloadParallelism 获取启动时并行加载持久化缓存的协程数
+	GetPreloadParallelism() int
+	// SetPreloadParallelism 设置启动时并行加载持久化缓存的协程数
+	SetPreloadParallelism(parallelism int)
+	// GetPreloadDeadline 获取启动时加载持久化缓存的最长等待时间，超时后剩余的缓存转为后台加载，0表示等待全部加载完成
+	GetPreloadDeadline() time.Duration
+	// SetPreloadDeadline 设置启动时加载持久化缓存的最长等待时间
+	SetPreloadDeadline(deadline time.Duration)
+	// GetRevisionAudit 获取缓存版本巡检配置
+	GetRevisionAudit() RevisionAuditConfig
+	// GetMemoryBudget 获取缓存内存预算配置
+	GetMemoryBudget() MemoryBudgetConfig
+}
+
+// MemoryBudgetConfig 缓存内存预算配置.
+type MemoryBudgetConfig interface {
+	BaseConfig
+	// GetMaxBytes 缓存内存预算，单位字节，0表示不限制
+	GetMaxBytes() int64
+	// SetMaxBytes 设置缓存内存预算
+	SetMaxBytes(maxBytes int64)
+	// GetCheckInterval 内存预算检查周期
+	GetCheckInterval() time.Duration
+	// SetCheckInterval 设置内存预算检查周期
+	SetCheckInterval(interval time.Duration)
+	// GetShedRefreshMultiplier 降载模式下新订阅服务的刷新周期倍数
+	GetShedRefreshMultiplier() int
+	// SetShedRefreshMultiplier 设置降载模式下新订阅服务的刷新周期倍数
+	SetShedRefreshMultiplier(multiplier int)
+}
+
+// RevisionAuditConfig 缓存版本巡检配置.
+type RevisionAuditConfig interface {
+	BaseConfig
+	// IsEnable 是否启用缓存版本巡检
+	IsEnable() bool
+	// SetEnable 设置是否启用缓存版本巡检
+	SetEnable(enable bool)
+	// GetInterval 巡检周期
+	GetInterval() time.Duration
+	// SetInterval 设置巡检周期
+	SetInterval(interval time.Duration)
+	// GetSampleSize 每次巡检抽样的订阅资源数
+	GetSampleSize() int
+	// SetSampleSize 设置每次巡检抽样的订阅资源数
+	SetSampleSize(size int)
+}
+
+// SelfLabelsConfig 主调方自身标签配置.
+type SelfLabelsConfig interface {
+	BaseConfig
+	// IsEnable 是否启用自身标签匹配
+	IsEnable() bool
+	// SetEnable 设置是否启用自身标签匹配
+	SetEnable(enable bool)
+	// GetNamespace 获取主调方所在的命名空间，为空时使用注册实例的命名空间
+	GetNamespace() string
+	// SetNamespace 设置主调方所在的命名空间
+	SetNamespace(namespace string)
+	// GetService 获取主调方的服务名，为空时使用注册实例的服务名
+	GetService() string
+	// SetService 设置主调方的服务名
+	SetService(service string)
+	// GetMetadata 获取主调方的自身标签，与注册实例的元数据合并，配置的优先
+	GetMetadata() map[string]string
+	// SetMetadata 设置主调方的自身标签
+	SetMetadata(metadata map[string]string)
 }
 
 // NearbyConfig 就近路由配置.
@@ -392,6 +815,17 @@ type ServiceRouterConfig interface {
 	SetEnableRecoverAll(bool)
 	// GetNearbyConfig 获取就近路由配置
 	GetNearbyConfig() NearbyConfig
+	// GetDrainingGracePeriod 获取排空中实例承接已有会话的宽限期
+	GetDrainingGracePeriod() time.Duration
+	// SetDrainingGracePeriod 设置排空中实例承接已有会话的宽限期
+	SetDrainingGracePeriod(time.Duration)
+	// GetSelfLabels 获取主调方自身标签配置
+	GetSelfLabels() SelfLabelsConfig
+	// IsEnableParallel consumer.serviceRouter.enableParallel
+	// 是否并行执行路由链中相邻的独立路由插件，并对各自的过滤结果取交集
+	IsEnableParallel() bool
+	// SetEnableParallel 设置是否并行执行独立路由插件
+	SetEnableParallel(bool)
 }
 
 // LoadbalancerConfig 负载均衡相关配置项.
@@ -458,6 +892,20 @@ type CircuitBreakerConfig interface {
 	// GetErrorRateConfig 错误率熔断配置
 	// Deprecated: 不在使用
 	GetErrorRateConfig() ErrorRateConfig
+	// GetBulkheads 获取舱壁隔离配置
+	GetBulkheads() []*BulkheadConfig
+	// SetBulkheads 设置舱壁隔离配置
+	SetBulkheads([]*BulkheadConfig)
+	// IsStrictMode 是否启用严格熔断模式，开启后 GetOneInstance 选中的实例或接口处于熔断状态时返回熔断错误，
+	// 而不是静默返回该实例，用于没有调用 Check 的框架集成
+	IsStrictMode() bool
+	// SetStrictMode 设置是否启用严格熔断模式
+	SetStrictMode(bool)
+	// GetQuarantineTTL 熔断实例隔离名单的有效期，大于0时熔断的实例会持久化到本地缓存目录，
+	// 进程重启后在有效期内继续隔离这些实例，为0则不启用
+	GetQuarantineTTL() time.Duration
+	// SetQuarantineTTL 设置熔断实例隔离名单的有效期
+	SetQuarantineTTL(time.Duration)
 }
 
 // Configuration 全量配置对象.
@@ -511,6 +959,23 @@ type HealthCheckConfig interface {
 	SetChain([]string)
 }
 
+// NamespaceSpecificConfig 命名空间级默认配置，未设置的字段返回零值，表示继承consumer下的全局配置.
+type NamespaceSpecificConfig interface {
+	BaseConfig
+	// GetNamespace 命名空间
+	GetNamespace() string
+	// GetRouterChain 服务路由链
+	GetRouterChain() []string
+	// GetLbPolicy 负载均衡类型
+	GetLbPolicy() string
+	// GetServiceExpireTime 服务缓存淘汰时间
+	GetServiceExpireTime() time.Duration
+	// GetServiceRefreshInterval 服务定期刷新周期
+	GetServiceRefreshInterval() time.Duration
+	// GetCircuitBreakerEnable 是否启用熔断
+	GetCircuitBreakerEnable() *bool
+}
+
 // ServiceSpecificConfig 配置.
 type ServiceSpecificConfig interface {
 	BaseConfig
@@ -518,6 +983,20 @@ type ServiceSpecificConfig interface {
 	GetServiceCircuitBreaker() CircuitBreakerConfig
 
 	GetServiceRouter() ServiceRouterConfig
+	// GetNamespace 命名空间
+	GetNamespace() string
+	// GetService 服务名
+	GetService() string
+	// GetRouterChain 服务路由链，为空表示继承上级配置
+	GetRouterChain() []string
+	// GetLbPolicy 负载均衡类型，为空表示继承上级配置
+	GetLbPolicy() string
+	// GetCircuitBreakerEnable 是否启用熔断，为nil表示继承上级配置
+	GetCircuitBreakerEnable() *bool
+	// GetTimeout API超时时间，为0表示继承全局配置
+	GetTimeout() time.Duration
+	// GetConnectionLimit 客户端到该服务的连接数限制，为nil表示不限制
+	GetConnectionLimit() *ConnectionLimitConfig
 }
 
 type ConfigLocalCacheConfig interface {
@@ -559,6 +1038,12 @@ type ConfigConnectorConfig interface {
 
 	// GetConnectorType 后端服务器类型，默认是 polaris
 	GetConnectorType() string
+	// GetNamespaceTokens 命名空间级别的鉴权token
+	GetNamespaceTokens() map[string]string
+	// SetNamespaceTokens 设置命名空间级别的鉴权token
+	SetNamespaceTokens(tokens map[string]string)
+	// GetNamespaceToken 获取命名空间使用的鉴权token，未单独配置时返回全局token
+	GetNamespaceToken(namespace string) string
 }
 
 // ConfigFilterConfig 配置中心加密相关配置
@@ -46,10 +46,69 @@ type CircuitBreakerConfigImpl struct {
 	RecoverWindow *time.Duration `yaml:"recoverWindow" json:"recoverWindow"`
 	// RecoverNumBuckets 半开后的统计的滑窗数
 	RecoverNumBuckets int `yaml:"recoverNumBuckets" json:"recoverNumBuckets"`
+	// Bulkheads 舱壁隔离配置，限制对下游服务/接口的最大并发调用数
+	// 服务端熔断规则不包含并发限制的定义，舱壁隔离仅支持在本地配置
+	Bulkheads []*BulkheadConfig `yaml:"bulkheads" json:"bulkheads"`
+	// StrictMode 严格模式，GetOneInstance 选中的实例或接口处于熔断状态时直接返回熔断错误
+	StrictMode *bool `yaml:"strictMode" json:"strictMode"`
+	// QuarantineTTL 熔断实例隔离名单的有效期，熔断的实例会持久化到本地，进程重启后在有效期内继续隔离
+	QuarantineTTL *time.Duration `yaml:"quarantineTTL" json:"quarantineTTL"`
 	// Plugin 插件配置反序列化后的对象
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
 
+// BulkheadConfig 单条舱壁隔离配置
+type BulkheadConfig struct {
+	// Namespace 被调服务命名空间，为空或者*代表全部命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Service 被调服务名，为空或者*代表全部服务
+	Service string `yaml:"service" json:"service"`
+	// Method 被调接口名，为空代表服务级别的并发限制，*代表每个接口单独限制
+	Method string `yaml:"method" json:"method"`
+	// MaxConcurrency 最大并发调用数
+	MaxConcurrency int `yaml:"maxConcurrency" json:"maxConcurrency"`
+	// MaxQueueSize 并发满时最多允许排队等待的请求数，为0则直接拒绝
+	MaxQueueSize int `yaml:"maxQueueSize" json:"maxQueueSize"`
+	// MaxWaitTime 排队请求的最长等待时间
+	MaxWaitTime *time.Duration `yaml:"maxWaitTime" json:"maxWaitTime"`
+}
+
+// Verify 检验舱壁隔离配置
+func (b *BulkheadConfig) Verify() error {
+	if nil == b {
+		return errors.New("bulkhead config is nil")
+	}
+	var errs error
+	if b.MaxConcurrency <= 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.circuitbreaker.bulkheads.maxConcurrency must be greater than 0"))
+	}
+	if b.MaxQueueSize < 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.circuitbreaker.bulkheads.maxQueueSize can not be negative"))
+	}
+	if nil != b.MaxWaitTime && *b.MaxWaitTime < 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.circuitbreaker.bulkheads.maxWaitTime can not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置舱壁隔离配置的默认值
+func (b *BulkheadConfig) SetDefault() {
+	if nil == b.MaxWaitTime {
+		b.MaxWaitTime = model.ToDurationPtr(DefaultBulkheadMaxWaitTime)
+	}
+}
+
+// GetMaxWaitTime 获取排队请求的最长等待时间
+func (b *BulkheadConfig) GetMaxWaitTime() time.Duration {
+	if nil == b.MaxWaitTime {
+		return DefaultBulkheadMaxWaitTime
+	}
+	return *b.MaxWaitTime
+}
+
 // IsEnable 是否启用熔断
 func (c *CircuitBreakerConfigImpl) IsEnable() bool {
 	return *c.Enable
@@ -140,6 +199,39 @@ func (c *CircuitBreakerConfigImpl) GetErrorRateConfig() ErrorRateConfig {
 	return c.Plugin[DefaultCircuitBreakerErrRate].(ErrorRateConfig)
 }
 
+// GetBulkheads 获取舱壁隔离配置
+func (c *CircuitBreakerConfigImpl) GetBulkheads() []*BulkheadConfig {
+	return c.Bulkheads
+}
+
+// SetBulkheads 设置舱壁隔离配置
+func (c *CircuitBreakerConfigImpl) SetBulkheads(bulkheads []*BulkheadConfig) {
+	c.Bulkheads = bulkheads
+}
+
+// IsStrictMode 是否启用严格熔断模式
+func (c *CircuitBreakerConfigImpl) IsStrictMode() bool {
+	return c.StrictMode != nil && *c.StrictMode
+}
+
+// SetStrictMode 设置是否启用严格熔断模式
+func (c *CircuitBreakerConfigImpl) SetStrictMode(strict bool) {
+	c.StrictMode = &strict
+}
+
+// GetQuarantineTTL 获取熔断实例隔离名单的有效期
+func (c *CircuitBreakerConfigImpl) GetQuarantineTTL() time.Duration {
+	if nil == c.QuarantineTTL {
+		return DefaultCircuitBreakerQuarantineTTL
+	}
+	return *c.QuarantineTTL
+}
+
+// SetQuarantineTTL 设置熔断实例隔离名单的有效期
+func (c *CircuitBreakerConfigImpl) SetQuarantineTTL(ttl time.Duration) {
+	c.QuarantineTTL = &ttl
+}
+
 // Verify 检验LocalCacheConfig配置
 func (c *CircuitBreakerConfigImpl) Verify() error {
 	if nil == c {
@@ -173,6 +265,14 @@ func (c *CircuitBreakerConfigImpl) Verify() error {
 			fmt.Errorf(
 				"consumer.circuitbreaker.recoverNumBuckets must be greater than %d", MinRecoverNumBuckets))
 	}
+	if nil != c.QuarantineTTL && *c.QuarantineTTL < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.circuitbreaker.quarantineTTL can not be negative"))
+	}
+	for _, bulkhead := range c.Bulkheads {
+		if err := bulkhead.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	if err := c.Plugin.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
@@ -191,6 +291,13 @@ func (c *CircuitBreakerConfigImpl) SetDefault() {
 		enable := DefaultCircuitBreakerEnabled
 		c.Enable = &enable
 	}
+	if nil == c.StrictMode {
+		strict := DefaultCircuitBreakerStrictMode
+		c.StrictMode = &strict
+	}
+	if nil == c.QuarantineTTL {
+		c.QuarantineTTL = model.ToDurationPtr(DefaultCircuitBreakerQuarantineTTL)
+	}
 	if nil == c.SleepWindow {
 		c.SleepWindow = model.ToDurationPtr(DefaultSleepWindow)
 	}
@@ -206,6 +313,11 @@ func (c *CircuitBreakerConfigImpl) SetDefault() {
 	if c.RecoverNumBuckets == 0 {
 		c.RecoverNumBuckets = DefaultRecoverNumBuckets
 	}
+	for _, bulkhead := range c.Bulkheads {
+		if nil != bulkhead {
+			bulkhead.SetDefault()
+		}
+	}
 	c.Plugin.SetDefault(common.TypeCircuitBreaker)
 }
 
@@ -53,6 +53,24 @@ type ConfigConnectorConfigImpl struct {
 
 	Token string `yaml:"token" json:"token"`
 
+	// 命名空间级别的鉴权token，未配置的命名空间使用token
+	NamespaceTokens map[string]string `yaml:"namespaceTokens" json:"namespaceTokens"`
+
+	// 带优先级及权重的server地址，与addresses合并使用
+	Endpoints []*ServerEndpointConfig `yaml:"endpoints" json:"endpoints"`
+
+	// 切换到低优先级server后，尝试切回高优先级server的间隔
+	FailbackInterval *time.Duration `yaml:"failbackInterval" json:"failbackInterval"`
+
+	// 连接的最大存活时间，超过后重建连接
+	MaxConnectionAge *time.Duration `yaml:"maxConnectionAge" json:"maxConnectionAge"`
+
+	// 连接的最大空闲时间，超过后释放连接
+	MaxConnectionIdle *time.Duration `yaml:"maxConnectionIdle" json:"maxConnectionIdle"`
+
+	// 连接超过最大存活时间后重建的随机延迟上限
+	ReconnectJitter *time.Duration `yaml:"reconnectJitter" json:"reconnectJitter"`
+
 	ConnectorType string `yaml:"connectorType" json:"connectorType"`
 }
 
@@ -170,15 +188,113 @@ func (c *ConfigConnectorConfigImpl) SetToken(token string) {
 	c.Token = token
 }
 
+// GetNamespaceTokens config.configConnector.namespaceTokens
+// 命名空间级别的鉴权token.
+func (c *ConfigConnectorConfigImpl) GetNamespaceTokens() map[string]string {
+	return c.NamespaceTokens
+}
+
+// SetNamespaceTokens 设置命名空间级别的鉴权token.
+func (c *ConfigConnectorConfigImpl) SetNamespaceTokens(tokens map[string]string) {
+	c.NamespaceTokens = tokens
+}
+
+// GetNamespaceToken 获取命名空间使用的鉴权token，未单独配置时返回全局token.
+func (c *ConfigConnectorConfigImpl) GetNamespaceToken(namespace string) string {
+	if token, ok := c.NamespaceTokens[namespace]; ok && len(token) > 0 {
+		return token
+	}
+	return c.Token
+}
+
+// GetEndpoints config.configConnector.endpoints
+// 带优先级及权重的server地址.
+func (c *ConfigConnectorConfigImpl) GetEndpoints() []*ServerEndpointConfig {
+	return c.Endpoints
+}
+
+// SetEndpoints 设置带优先级及权重的server地址.
+func (c *ConfigConnectorConfigImpl) SetEndpoints(endpoints []*ServerEndpointConfig) {
+	c.Endpoints = endpoints
+}
+
+// GetFailbackInterval config.configConnector.failbackInterval
+// 切换到低优先级server后，尝试切回高优先级server的间隔.
+func (c *ConfigConnectorConfigImpl) GetFailbackInterval() time.Duration {
+	return *c.FailbackInterval
+}
+
+// SetFailbackInterval 设置尝试切回高优先级server的间隔.
+func (c *ConfigConnectorConfigImpl) SetFailbackInterval(interval time.Duration) {
+	c.FailbackInterval = &interval
+}
+
+// GetMaxConnectionAge config.configConnector.maxConnectionAge
+// 连接的最大存活时间.
+func (c *ConfigConnectorConfigImpl) GetMaxConnectionAge() time.Duration {
+	return *c.MaxConnectionAge
+}
+
+// SetMaxConnectionAge 设置连接的最大存活时间.
+func (c *ConfigConnectorConfigImpl) SetMaxConnectionAge(age time.Duration) {
+	c.MaxConnectionAge = &age
+}
+
+// GetMaxConnectionIdle config.configConnector.maxConnectionIdle
+// 连接的最大空闲时间.
+func (c *ConfigConnectorConfigImpl) GetMaxConnectionIdle() time.Duration {
+	return *c.MaxConnectionIdle
+}
+
+// SetMaxConnectionIdle 设置连接的最大空闲时间.
+func (c *ConfigConnectorConfigImpl) SetMaxConnectionIdle(idle time.Duration) {
+	c.MaxConnectionIdle = &idle
+}
+
+// GetReconnectJitter config.configConnector.reconnectJitter
+// 连接超过最大存活时间后重建的随机延迟上限.
+func (c *ConfigConnectorConfigImpl) GetReconnectJitter() time.Duration {
+	return *c.ReconnectJitter
+}
+
+// SetReconnectJitter 设置连接超过最大存活时间后重建的随机延迟上限.
+func (c *ConfigConnectorConfigImpl) SetReconnectJitter(jitter time.Duration) {
+	c.ReconnectJitter = &jitter
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigConnectorConfigImpl) Verify() error {
 	if nil == c {
 		return errors.New("ConfigConnectorConfig is nil")
 	}
 	var errs error
-	if len(c.Addresses) == 0 {
+	if len(c.Addresses) == 0 && len(c.Endpoints) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.configConnector.addresses is empty"))
 	}
+	for _, endpoint := range c.Endpoints {
+		if err := endpoint.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("config.configConnector.endpoints: %v", err))
+		}
+	}
+	if nil != c.FailbackInterval && *c.FailbackInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.failbackInterval %v is less than minimal timing interval %v",
+				*c.FailbackInterval, DefaultMinTimingInterval))
+	}
+	if nil != c.MaxConnectionAge && *c.MaxConnectionAge != 0 && *c.MaxConnectionAge < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.maxConnectionAge %v is less than minimal timing interval %v",
+				*c.MaxConnectionAge, DefaultMinTimingInterval))
+	}
+	if nil != c.MaxConnectionIdle && *c.MaxConnectionIdle != 0 && *c.MaxConnectionIdle < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.maxConnectionIdle %v is less than minimal timing interval %v",
+				*c.MaxConnectionIdle, DefaultMinTimingInterval))
+	}
+	if nil != c.ReconnectJitter && *c.ReconnectJitter < 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("config.configConnector.reconnectJitter %v should not be negative", *c.ReconnectJitter))
+	}
 	if nil != c.RequestQueueSize && *c.RequestQueueSize < 0 {
 		errs = multierror.Append(errs,
 			fmt.Errorf("config.configConnector.requestQueueSize %v is invalid", c.RequestQueueSize))
@@ -221,10 +337,22 @@ func (c *ConfigConnectorConfigImpl) SetDefault() {
 	if c.ReconnectInterval == nil {
 		c.ReconnectInterval = model.ToDurationPtr(DefaultReConnectInterval)
 	}
+	if c.FailbackInterval == nil {
+		c.FailbackInterval = model.ToDurationPtr(DefaultServerFailbackInterval)
+	}
+	if c.MaxConnectionAge == nil {
+		c.MaxConnectionAge = model.ToDurationPtr(DefaultMaxConnectionAge)
+	}
+	if c.MaxConnectionIdle == nil {
+		c.MaxConnectionIdle = model.ToDurationPtr(DefaultMaxConnectionIdle)
+	}
+	if c.ReconnectJitter == nil {
+		c.ReconnectJitter = model.ToDurationPtr(DefaultReconnectJitter)
+	}
 	if len(c.Protocol) == 0 {
 		c.Protocol = DefaultConfigConnector
 	}
-	if len(c.Addresses) == 0 {
+	if len(c.Addresses) == 0 && len(c.Endpoints) == 0 {
 		c.SetAddresses([]string{DefaultConfigConnectorAddresses})
 	}
 	if len(c.ConnectorType) == 0 {
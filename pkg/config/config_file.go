@@ -42,6 +42,12 @@ type ConfigFileConfigImpl struct {
 	Enable                    *bool  `yaml:"enable" json:"enable"`
 	PropertiesValueCacheSize  *int32 `yaml:"propertiesValueCacheSize" json:"propertiesValueCacheSize"`
 	PropertiesValueExpireTime *int64 `yaml:"propertiesValueExpireTime" json:"propertiesValueExpireTime"`
+	// 配置订阅断线恢复后重新订阅的随机延迟上限，避免大量进程同时重新订阅
+	ResubscribeJitter *time.Duration `yaml:"resubscribeJitter" json:"resubscribeJitter"`
+	// 从服务端拉取配置文件的最大并发数，0表示不限制
+	MaxConcurrentFetches *int `yaml:"maxConcurrentFetches" json:"maxConcurrentFetches"`
+	// 收到配置变更通知后拉取配置的随机延迟上限，大量配置同时发布时打散拉取请求
+	FetchJitter *time.Duration `yaml:"fetchJitter" json:"fetchJitter"`
 }
 
 // GetConfigConnectorConfig config.configConnector前缀开头的所有配置项.
@@ -89,6 +95,36 @@ func (c *ConfigFileConfigImpl) GetLocalCache() ConfigLocalCacheConfig {
 	return c.LocalCache
 }
 
+// GetResubscribeJitter config.resubscribeJitter.
+func (c *ConfigFileConfigImpl) GetResubscribeJitter() time.Duration {
+	return *c.ResubscribeJitter
+}
+
+// SetResubscribeJitter 设置配置订阅断线恢复后重新订阅的随机延迟上限.
+func (c *ConfigFileConfigImpl) SetResubscribeJitter(jitter time.Duration) {
+	c.ResubscribeJitter = &jitter
+}
+
+// GetMaxConcurrentFetches config.maxConcurrentFetches.
+func (c *ConfigFileConfigImpl) GetMaxConcurrentFetches() int {
+	return *c.MaxConcurrentFetches
+}
+
+// SetMaxConcurrentFetches 设置从服务端拉取配置文件的最大并发数.
+func (c *ConfigFileConfigImpl) SetMaxConcurrentFetches(maxFetches int) {
+	c.MaxConcurrentFetches = &maxFetches
+}
+
+// GetFetchJitter config.fetchJitter.
+func (c *ConfigFileConfigImpl) GetFetchJitter() time.Duration {
+	return *c.FetchJitter
+}
+
+// SetFetchJitter 设置收到配置变更通知后拉取配置的随机延迟上限.
+func (c *ConfigFileConfigImpl) SetFetchJitter(jitter time.Duration) {
+	c.FetchJitter = &jitter
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigFileConfigImpl) Verify() error {
 	if c == nil {
@@ -110,6 +146,15 @@ func (c *ConfigFileConfigImpl) Verify() error {
 	if c.PropertiesValueExpireTime != nil && *c.PropertiesValueExpireTime < 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.propertiesValueExpireTime %v is invalid", c.PropertiesValueExpireTime))
 	}
+	if c.ResubscribeJitter == nil || *c.ResubscribeJitter < 0 {
+		errs = multierror.Append(errs, errors.New("config.resubscribeJitter should not be negative"))
+	}
+	if c.MaxConcurrentFetches == nil || *c.MaxConcurrentFetches < 0 {
+		errs = multierror.Append(errs, errors.New("config.maxConcurrentFetches should not be negative"))
+	}
+	if c.FetchJitter == nil || *c.FetchJitter < 0 {
+		errs = multierror.Append(errs, errors.New("config.fetchJitter should not be negative"))
+	}
 	return errs
 }
 
@@ -127,6 +172,18 @@ func (c *ConfigFileConfigImpl) SetDefault() {
 	if c.PropertiesValueCacheSize == nil {
 		c.PropertiesValueExpireTime = proto.Int64(int64(DefaultPropertiesValueCacheSize))
 	}
+	if c.ResubscribeJitter == nil {
+		jitter := DefaultConfigResubscribeJitter
+		c.ResubscribeJitter = &jitter
+	}
+	if c.MaxConcurrentFetches == nil {
+		maxFetches := DefaultConfigMaxConcurrentFetches
+		c.MaxConcurrentFetches = &maxFetches
+	}
+	if c.FetchJitter == nil {
+		jitter := DefaultConfigFetchJitter
+		c.FetchJitter = &jitter
+	}
 }
 
 // Init 配置初始化.
@@ -0,0 +1,102 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ContractValidationConfigImpl 契约校验配置，按照被调服务上报的服务契约校验主调请求的路径及方法，
+// 请求未在契约中声明或者契约已下线时输出告警日志及指标，用于尽早发现主调与被调之间的接口漂移.
+type ContractValidationConfigImpl struct {
+	// 是否启用契约校验
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 默认的契约协议，请求中未指定协议时使用
+	Protocol string `yaml:"protocol" json:"protocol"`
+	// 服务契约的本地缓存时间，过期后重新向服务端拉取
+	RefreshInterval *time.Duration `yaml:"refreshInterval" json:"refreshInterval"`
+}
+
+// IsEnable 是否启用契约校验.
+func (c *ContractValidationConfigImpl) IsEnable() bool {
+	return *c.Enable
+}
+
+// SetEnable 设置是否启用契约校验.
+func (c *ContractValidationConfigImpl) SetEnable(enable bool) {
+	c.Enable = &enable
+}
+
+// GetProtocol 获取默认的契约协议.
+func (c *ContractValidationConfigImpl) GetProtocol() string {
+	return c.Protocol
+}
+
+// SetProtocol 设置默认的契约协议.
+func (c *ContractValidationConfigImpl) SetProtocol(protocol string) {
+	c.Protocol = protocol
+}
+
+// GetRefreshInterval 获取服务契约的本地缓存时间.
+func (c *ContractValidationConfigImpl) GetRefreshInterval() time.Duration {
+	return *c.RefreshInterval
+}
+
+// SetRefreshInterval 设置服务契约的本地缓存时间.
+func (c *ContractValidationConfigImpl) SetRefreshInterval(interval time.Duration) {
+	c.RefreshInterval = &interval
+}
+
+// Verify 检验契约校验配置.
+func (c *ContractValidationConfigImpl) Verify() error {
+	if nil == c {
+		return errors.New("ContractValidationConfig is nil")
+	}
+	if !c.IsEnable() {
+		return nil
+	}
+	var errs error
+	if len(c.Protocol) == 0 {
+		errs = multierror.Append(errs, errors.New("consumer.contractValidation.protocol can not be empty"))
+	}
+	if c.GetRefreshInterval() < time.Second {
+		errs = multierror.Append(errs,
+			errors.New("consumer.contractValidation.refreshInterval should not be less than 1s"))
+	}
+	return errs
+}
+
+// SetDefault 设置契约校验配置的默认值.
+func (c *ContractValidationConfigImpl) SetDefault() {
+	if nil == c.Enable {
+		c.SetEnable(DefaultContractValidationEnable)
+	}
+	if len(c.Protocol) == 0 {
+		c.Protocol = DefaultContractValidationProtocol
+	}
+	if nil == c.RefreshInterval {
+		c.SetRefreshInterval(DefaultContractValidationRefreshInterval)
+	}
+}
+
+// Init 初始化契约校验配置.
+func (c *ContractValidationConfigImpl) Init() {
+}
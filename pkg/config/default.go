@@ -35,6 +35,10 @@ const (
 	DefaultAPIMaxRetryTimes int = 1
 	// DefaultAPIRetryInterval 默认api调用重试间隔.
 	DefaultAPIRetryInterval = 1 * time.Second
+	// DefaultRecentCallsSize 默认内存中保留的最近API调用记录数.
+	DefaultRecentCallsSize = 256
+	// DefaultPluginPanicWindow 默认插件panic次数的统计窗口.
+	DefaultPluginPanicWindow = 1 * time.Minute
 	// DefaultDiscoverServiceRetryInterval 默认首次发现discovery服务重试间隔.
 	DefaultDiscoverServiceRetryInterval = 5 * time.Second
 	// DefaultServiceExpireTime 默认的服务超时淘汰时间.
@@ -55,6 +59,16 @@ const (
 	DefaultRequestQueueSize int = 1000
 	// DefaultServerSwitchInterval 默认server的切换时间时间.
 	DefaultServerSwitchInterval = 10 * time.Minute
+	// DefaultServerEndpointWeight 默认server地址的权重.
+	DefaultServerEndpointWeight = 100
+	// DefaultServerFailbackInterval 默认切换到容灾server后，多久尝试切回高优先级server.
+	DefaultServerFailbackInterval = 30 * time.Second
+	// DefaultMaxConnectionAge 默认的server连接最大存活时间，0表示不限制
+	DefaultMaxConnectionAge time.Duration = 0
+	// DefaultMaxConnectionIdle 默认的server连接最大空闲时间，0表示不释放空闲连接
+	DefaultMaxConnectionIdle time.Duration = 0
+	// DefaultReconnectJitter 默认的连接超过最大存活时间后重建的随机延迟上限
+	DefaultReconnectJitter = 30 * time.Second
 	// DefaultCachePersistEnable 默认缓存持久化存储开启.
 	DefaultCachePersistEnable bool = true
 	// DefaultCachePersistDir 默认缓存持久化存储目录.
@@ -73,10 +87,50 @@ const (
 	MinCircuitBreakerCheckPeriod = 1 * time.Second
 	// DefaultCircuitBreakerEnabled 熔断器默认开启与否.
 	DefaultCircuitBreakerEnabled bool = true
+	// DefaultCircuitBreakerStrictMode 严格熔断模式默认关闭.
+	DefaultCircuitBreakerStrictMode bool = false
+	// DefaultCircuitBreakerQuarantineTTL 熔断实例隔离名单的默认有效期，0代表不持久化熔断实例.
+	DefaultCircuitBreakerQuarantineTTL time.Duration = 0
 	// DefaultRecoverAllEnabled 服务路由的全死全活默认开启与否.
 	DefaultRecoverAllEnabled bool = true
+	// DefaultServiceRouterParallelEnabled 独立路由插件默认不并行执行.
+	DefaultServiceRouterParallelEnabled bool = false
 	// DefaultPercentOfMinInstances 路由至少返回节点数百分比.
 	DefaultPercentOfMinInstances float64 = 0.0
+	// DefaultDrainingGracePeriod 排空中实例承接已有会话的默认宽限期.
+	DefaultDrainingGracePeriod = 5 * time.Minute
+	// DefaultSelfLabelsEnable 规则路由默认不使用主调方自身标签匹配.
+	DefaultSelfLabelsEnable = false
+	// DefaultDNSServerEnable 内置DNS服务默认关闭.
+	DefaultDNSServerEnable = false
+	// DefaultDNSServerAddress 内置DNS服务默认只监听本机地址.
+	DefaultDNSServerAddress = "127.0.0.1:8853"
+	// DefaultDNSServerDomain 内置DNS服务默认的域名后缀.
+	DefaultDNSServerDomain = "polaris"
+	// DefaultDNSServerTTL 内置DNS服务默认的记录TTL.
+	DefaultDNSServerTTL = 5 * time.Second
+	// DefaultRequestBudgetMaxAttempts 单次逻辑请求默认最多发起的尝试次数.
+	DefaultRequestBudgetMaxAttempts = 3
+	// DefaultRequestBudgetTimeoutMultiplier 单次逻辑请求默认的总耗时上限倍数.
+	DefaultRequestBudgetTimeoutMultiplier = 2.0
+	// DefaultAddressTranslationMetadataKeyPrefix 实例元数据中各网络区域可达地址的默认key前缀.
+	DefaultAddressTranslationMetadataKeyPrefix = "polaris.address."
+	// DefaultNameMappingRefreshInterval 服务名映射规则文件默认的变更检查周期.
+	DefaultNameMappingRefreshInterval = 10 * time.Second
+	// DefaultContractValidationEnable 默认不启用契约校验.
+	DefaultContractValidationEnable = false
+	// DefaultContractValidationProtocol 契约校验默认的契约协议.
+	DefaultContractValidationProtocol = "http"
+	// DefaultContractValidationRefreshInterval 服务契约默认的本地缓存时间.
+	DefaultContractValidationRefreshInterval = time.Minute
+	// DefaultSidecarMode 默认不使用本机sidecar.
+	DefaultSidecarMode = SidecarModeNever
+	// DefaultSidecarAddress polaris-sidecar服务发现接口的默认本机地址.
+	DefaultSidecarAddress = "127.0.0.1:18091"
+	// DefaultSidecarConfigAddress polaris-sidecar配置中心接口的默认本机地址.
+	DefaultSidecarConfigAddress = "127.0.0.1:18093"
+	// DefaultSidecarDetectTimeout 默认探测本机sidecar的超时时间.
+	DefaultSidecarDetectTimeout = 200 * time.Millisecond
 	// DefaultHealthCheckConcurrency 默认心跳检测的并发数.
 	DefaultHealthCheckConcurrency int = 1
 	// DefaultHealthCheckConcurrencyAlways 默认持续心跳检测的并发数.
@@ -97,6 +151,8 @@ const (
 	DefaultSleepWindow = 30 * time.Second
 	// MinSleepWindow 最小熔断周期，1s.
 	MinSleepWindow = 1 * time.Second
+	// DefaultBulkheadMaxWaitTime 舱壁隔离排队请求默认的最长等待时间.
+	DefaultBulkheadMaxWaitTime = 1 * time.Second
 	// DefaultRecoverWindow 默认恢复周期，半开后按多久的统计窗口进行恢复统计.
 	DefaultRecoverWindow = 60 * time.Second
 	// MinRecoverWindow 最小恢复周期，10s.
@@ -141,6 +197,44 @@ const (
 	DefaultConfigConnectorAddresses = "127.0.0.1:8093"
 	// DefaultMinRegisterInterval
 	DefaultMinRegisterInterval = 30 * time.Second
+	// DefaultLameduckTTL 进入lameduck状态后实例的默认心跳TTL，单位秒
+	DefaultLameduckTTL = 2
+	// DefaultShutdownDrainPeriod 优雅下线时等待在途请求处理完成的默认最长时间
+	DefaultShutdownDrainPeriod = 30 * time.Second
+	// DefaultIdentityMetadataEnable 默认在访问服务端的请求中携带客户端身份信息
+	DefaultIdentityMetadataEnable = true
+	// DefaultFlappingWindow 默认的注册状态抖动统计窗口
+	DefaultFlappingWindow = time.Minute
+	// DefaultFlappingThreshold 默认的窗口内最大注册状态变化次数
+	DefaultFlappingThreshold = 10
+	// DefaultFlappingBaseBackoff 默认的抖动首次退避时长
+	DefaultFlappingBaseBackoff = 30 * time.Second
+	// DefaultFlappingMaxBackoff 默认的抖动最大退避时长
+	DefaultFlappingMaxBackoff = 5 * time.Minute
+	// DefaultRevisionAuditInterval 默认的缓存版本巡检周期
+	DefaultRevisionAuditInterval = 5 * time.Minute
+	// DefaultRevisionAuditSampleSize 默认每次巡检抽样的订阅资源数
+	DefaultRevisionAuditSampleSize = 10
+	// DefaultRevisionAuditEnable 默认关闭缓存版本巡检
+	DefaultRevisionAuditEnable = false
+	// DefaultMemoryBudgetCheckInterval 默认的缓存内存预算检查周期
+	DefaultMemoryBudgetCheckInterval = 10 * time.Second
+	// DefaultMemoryBudgetShedRefreshMultiplier 默认降载模式下新订阅服务的刷新周期倍数
+	DefaultMemoryBudgetShedRefreshMultiplier = 3
+	// DefaultHeartbeatUDPAckTimeout 默认的UDP心跳应答等待时间
+	DefaultHeartbeatUDPAckTimeout = 500 * time.Millisecond
+	// DefaultHeartbeatUDPMaxLoss 默认的UDP心跳连续丢包次数上限，超过后回退到GRPC
+	DefaultHeartbeatUDPMaxLoss = 3
+	// DefaultHeartbeatUDPFallbackInterval 默认回退到GRPC后重新尝试UDP心跳的间隔
+	DefaultHeartbeatUDPFallbackInterval = time.Minute
+	// DefaultConfigResubscribeJitter 默认的配置订阅断线恢复后重新订阅的随机延迟上限
+	DefaultConfigResubscribeJitter = 5 * time.Second
+	// DefaultConfigMaxConcurrentFetches 默认的配置文件最大并发拉取数，0表示不限制
+	DefaultConfigMaxConcurrentFetches = 0
+	// DefaultConfigFetchJitter 默认的配置变更通知后拉取配置的随机延迟上限，0表示收到通知后立即拉取
+	DefaultConfigFetchJitter time.Duration = 0
+	// DefaultProvisionalInstanceTTL 默认的未确认自注册实例存活时间
+	DefaultProvisionalInstanceTTL = 30 * time.Second
 	// DefaultConfigFilterEnabled 默认配置过滤是否开启
 	DefaultConfigFilterEnabled bool = true
 )
@@ -163,6 +257,10 @@ var containerNameEnvs = []string{
 const (
 	// DefaultServerConnector 默认的服务端连接器插件.
 	DefaultServerConnector string = "grpc"
+	// HeartbeatProtocolGRPC 通过服务端连接器上报心跳.
+	HeartbeatProtocolGRPC string = "grpc"
+	// HeartbeatProtocolUDP 优先通过UDP上报心跳，丢包时回退到服务端连接器.
+	HeartbeatProtocolUDP string = "udp"
 	// DefaultLocalCache 默认本地缓存策略.
 	DefaultLocalCache string = "inmemory"
 	// DefaultServiceRouterRuleBased 默认规则路由.
@@ -179,6 +277,10 @@ const (
 	DefaultServiceRouterCanary string = "canaryRouter"
 	// DefaultServiceRouterZeroProtect 零实例保护
 	DefaultServiceRouterZeroProtect string = "zeroProtectRouter"
+	// DefaultServiceRouterGrayBucket 按主调标签一致性分桶的灰度路由
+	DefaultServiceRouterGrayBucket string = "grayBucketRouter"
+	// DefaultServiceRouterSessionAffinity 基于请求标签的会话亲和路由
+	DefaultServiceRouterSessionAffinity string = "sessionAffinityRouter"
 
 	// DefaultLoadBalancerWR 默认负载均衡器,权重随机.
 	DefaultLoadBalancerWR string = "weightedRandom"
@@ -209,6 +311,12 @@ const (
 	DefaultWarmUpRateLimiter = "warmUp"
 	// DefaultUniformRateLimiter 默认的匀速限流器.
 	DefaultUniformRateLimiter = "unirate"
+	// DefaultSlidingLogRateLimiter 滑动窗口日志限流器，精确限流.
+	DefaultSlidingLogRateLimiter = "slidinglog"
+	// DefaultSlidingCounterRateLimiter 滑动窗口计数限流器.
+	DefaultSlidingCounterRateLimiter = "slidingcounter"
+	// DefaultLeakyBucketRateLimiter 带排队队列的漏桶限流器.
+	DefaultLeakyBucketRateLimiter = "leakybucket"
 	// DefaultWarmUpWaitLimiter 默认限流插件，预热匀速.
 	DefaultWarmUpWaitLimiter = "warmup-wait"
 	// SubscribeLocalChannel 默认订阅事件处理插件.
@@ -218,6 +326,14 @@ const (
 	MaxRateLimitWindowSize = 20000
 	// DefaultRateLimitPurgeInterval 默认超时清理时延.
 	DefaultRateLimitPurgeInterval = 1 * time.Minute
+	// DefaultRateLimitShareDirName 默认共享限流配额的目录名，位于系统临时目录下.
+	DefaultRateLimitShareDirName = "polaris-ratelimit"
+	// DefaultRateLimitTopKSize 默认每条限流规则上报的热点标签值数量.
+	DefaultRateLimitTopKSize = 10
+	// DefaultRateLimitTopKMaxTracked 默认每条限流规则最多跟踪的标签值数量.
+	DefaultRateLimitTopKMaxTracked = 1000
+	// DefaultRateLimitTopKInterval 默认热点标签值的上报周期.
+	DefaultRateLimitTopKInterval = 1 * time.Minute
 	// DefaultConfigConnector 默认的注册中心连接器插件.
 	DefaultConfigConnector string = "polaris"
 	// DefaultLimiterNamespace 默认的限流服务
@@ -232,6 +348,7 @@ const (
 	RegionLevel       = "region"
 	ZoneLevel         = "zone"
 	CampusLevel       = "campus"
+	RackLevel         = "rack"
 	AllLevel          = ""
 )
 
@@ -252,6 +369,10 @@ const (
 	DefaultStatReportEnabled = true
 	// DefaultMetricsChain .
 	DefaultMetricsChain = "prometheus"
+	// CostLabelsEnv 成本归属标签的环境变量，格式为 key1=value1,key2=value2，优先级高于配置文件
+	CostLabelsEnv = "POLARIS_COST_LABELS"
+	// DefaultCallerLabelsEnabled 默认在服务调用指标中携带主调服务标签
+	DefaultCallerLabelsEnabled = true
 )
 
 const (
@@ -394,6 +515,18 @@ func (a *APIConfigImpl) Verify() error {
 	if *a.RetryInterval < DefaultAPIRetryInterval {
 		return fmt.Errorf("global.api.retryInterval must be greater than %v", DefaultAPIRetryInterval)
 	}
+	if a.PluginPanicThreshold < 0 {
+		return fmt.Errorf("global.api.pluginPanicThreshold must not be negative")
+	}
+	if *a.PluginPanicWindow <= 0 {
+		return fmt.Errorf("global.api.pluginPanicWindow must be greater than 0")
+	}
+	if a.RecentCallsSize < -1 {
+		return fmt.Errorf("global.api.recentCallsSize must be greater than or equal to -1")
+	}
+	if *a.FlowBudget < 0 {
+		return fmt.Errorf("global.api.flowBudget must not be negative")
+	}
 	return nil
 }
 
@@ -411,6 +544,15 @@ func (a *APIConfigImpl) SetDefault() {
 	if a.MaxRetryTimes == 0 {
 		a.MaxRetryTimes = DefaultAPIMaxRetryTimes
 	}
+	if nil == a.PluginPanicWindow {
+		a.PluginPanicWindow = model.ToDurationPtr(DefaultPluginPanicWindow)
+	}
+	if a.RecentCallsSize == 0 {
+		a.RecentCallsSize = DefaultRecentCallsSize
+	}
+	if nil == a.FlowBudget {
+		a.FlowBudget = model.ToDurationPtr(0)
+	}
 	if len(a.BindIP) > 0 {
 		a.BindIPValue = a.BindIP
 	}
@@ -438,6 +580,9 @@ func (g *GlobalConfigImpl) Verify() error {
 	if err = g.Location.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = g.Sidecar.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -448,6 +593,11 @@ func (g *GlobalConfigImpl) SetDefault() {
 	g.System.SetDefault()
 	g.StatReporter.SetDefault()
 	g.Location.SetDefault()
+	g.Sidecar.SetDefault()
+	if nil == g.Client {
+		g.Client = &ClientConfigImpl{}
+	}
+	g.Client.SetDefault()
 }
 
 // Init 全局配置初始化.
@@ -463,6 +613,8 @@ func (g *GlobalConfigImpl) Init() {
 	g.Location.Init()
 	g.Client = &ClientConfigImpl{}
 	g.Client.Init()
+	g.Sidecar = &SidecarConfigImpl{}
+	g.Sidecar.Init()
 }
 
 // Init 初始化ConsumerConfigImpl.
@@ -477,6 +629,18 @@ func (c *ConsumerConfigImpl) Init() {
 	c.Loadbalancer.Init()
 	c.HealthCheck = &HealthCheckConfigImpl{}
 	c.HealthCheck.Init()
+	c.DNSServer = &DNSServerConfigImpl{}
+	c.DNSServer.Init()
+	c.RequestBudget = &RequestBudgetConfigImpl{}
+	c.RequestBudget.Init()
+	c.AddressTranslation = &AddressTranslationConfigImpl{}
+	c.AddressTranslation.Init()
+	c.NameMapping = &NameMappingConfigImpl{}
+	c.NameMapping.Init()
+	c.NamespaceFallback = &NamespaceFallbackConfigImpl{}
+	c.NamespaceFallback.Init()
+	c.ContractValidation = &ContractValidationConfigImpl{}
+	c.ContractValidation.Init()
 }
 
 // Verify 检验consumerConfig配置.
@@ -501,6 +665,48 @@ func (c *ConsumerConfigImpl) Verify() error {
 	if err = c.HealthCheck.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = c.DNSServer.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.RequestBudget.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.AddressTranslation.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.NameMapping.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.NamespaceFallback.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.ContractValidation.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	namespaces := make(map[string]struct{}, len(c.NamespacesSpecific))
+	for _, ns := range c.NamespacesSpecific {
+		if err = ns.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if _, ok := namespaces[ns.Namespace]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.namespacesSpecific: duplicate namespace %s",
+				ns.Namespace))
+		}
+		namespaces[ns.Namespace] = struct{}{}
+	}
+	services := make(map[string]struct{}, len(c.ServicesSpecific))
+	for _, svc := range c.ServicesSpecific {
+		if err = svc.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		svcKey := svc.Namespace + "/" + svc.Service
+		if _, ok := services[svcKey]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.servicesSpecific: duplicate service %s", svcKey))
+		}
+		services[svcKey] = struct{}{}
+	}
 	return errs
 }
 
@@ -511,6 +717,12 @@ func (c *ConsumerConfigImpl) SetDefault() {
 	c.ServiceRouter.SetDefault()
 	c.CircuitBreaker.SetDefault()
 	c.HealthCheck.SetDefault()
+	c.DNSServer.SetDefault()
+	c.RequestBudget.SetDefault()
+	c.AddressTranslation.SetDefault()
+	c.NameMapping.SetDefault()
+	c.NamespaceFallback.SetDefault()
+	c.ContractValidation.SetDefault()
 }
 
 // Init 初始化整体配置对象.
@@ -622,6 +834,8 @@ func (s *ServerClusterConfigImpl) Verify() error {
 type ClientConfigImpl struct {
 	ID     string            `yaml:"id" json:"id"`
 	Labels map[string]string `yaml:"labels" json:"labels"`
+	// 是否在访问服务端的请求中携带SDK版本、进程号、主机名及客户端标签等身份信息
+	IdentityMetadata *bool `yaml:"identityMetadata" json:"identityMetadata"`
 }
 
 // Init 初始化
@@ -657,10 +871,23 @@ func (c *ClientConfigImpl) GetLabels() map[string]string {
 	return copyM
 }
 
+// IsIdentityMetadataEnable 是否在访问服务端的请求中携带客户端身份信息
+func (c *ClientConfigImpl) IsIdentityMetadataEnable() bool {
+	return *c.IdentityMetadata
+}
+
+// SetIdentityMetadataEnable 设置是否在访问服务端的请求中携带客户端身份信息
+func (c *ClientConfigImpl) SetIdentityMetadataEnable(enable bool) {
+	c.IdentityMetadata = &enable
+}
+
 func (c *ClientConfigImpl) SetDefault() {
 	if len(c.Labels) == 0 {
 		c.Labels = map[string]string{}
 	}
+	if c.IdentityMetadata == nil {
+		c.IdentityMetadata = model.ToBoolPtr(DefaultIdentityMetadataEnable)
+	}
 }
 
 func (c *ClientConfigImpl) Verify() error {
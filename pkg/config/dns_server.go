@@ -0,0 +1,120 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DNSServerConfigImpl 内置DNS服务配置，将本地缓存中的服务实例以DNS记录的方式暴露给无法接入SDK的同机进程.
+type DNSServerConfigImpl struct {
+	// 是否启用内置DNS服务
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 监听地址，同时监听UDP及TCP
+	Address string `yaml:"address" json:"address"`
+	// 服务域名后缀，查询的域名格式为 service.namespace.<domain>
+	Domain string `yaml:"domain" json:"domain"`
+	// DNS记录的TTL
+	TTL *time.Duration `yaml:"ttl" json:"ttl"`
+}
+
+// IsEnable 是否启用内置DNS服务.
+func (d *DNSServerConfigImpl) IsEnable() bool {
+	return *d.Enable
+}
+
+// SetEnable 设置是否启用内置DNS服务.
+func (d *DNSServerConfigImpl) SetEnable(enable bool) {
+	d.Enable = &enable
+}
+
+// GetAddress 获取监听地址.
+func (d *DNSServerConfigImpl) GetAddress() string {
+	return d.Address
+}
+
+// SetAddress 设置监听地址.
+func (d *DNSServerConfigImpl) SetAddress(address string) {
+	d.Address = address
+}
+
+// GetDomain 获取服务域名后缀.
+func (d *DNSServerConfigImpl) GetDomain() string {
+	return d.Domain
+}
+
+// SetDomain 设置服务域名后缀.
+func (d *DNSServerConfigImpl) SetDomain(domain string) {
+	d.Domain = domain
+}
+
+// GetTTL 获取DNS记录的TTL.
+func (d *DNSServerConfigImpl) GetTTL() time.Duration {
+	return *d.TTL
+}
+
+// SetTTL 设置DNS记录的TTL.
+func (d *DNSServerConfigImpl) SetTTL(ttl time.Duration) {
+	d.TTL = &ttl
+}
+
+// Verify 检验内置DNS服务配置.
+func (d *DNSServerConfigImpl) Verify() error {
+	if nil == d {
+		return errors.New("DNSServerConfig is nil")
+	}
+	if !d.IsEnable() {
+		return nil
+	}
+	var errs error
+	if _, _, err := net.SplitHostPort(d.Address); err != nil {
+		errs = multierror.Append(errs, errors.New("consumer.dnsServer.address must be host:port"))
+	}
+	if len(strings.Trim(d.Domain, ".")) == 0 {
+		errs = multierror.Append(errs, errors.New("consumer.dnsServer.domain can not be empty"))
+	}
+	if d.GetTTL() < time.Second {
+		errs = multierror.Append(errs, errors.New("consumer.dnsServer.ttl must be at least 1s"))
+	}
+	return errs
+}
+
+// SetDefault 设置内置DNS服务配置的默认值.
+func (d *DNSServerConfigImpl) SetDefault() {
+	if nil == d.Enable {
+		d.SetEnable(DefaultDNSServerEnable)
+	}
+	if len(d.Address) == 0 {
+		d.Address = DefaultDNSServerAddress
+	}
+	if len(d.Domain) == 0 {
+		d.Domain = DefaultDNSServerDomain
+	}
+	if nil == d.TTL {
+		d.SetTTL(DefaultDNSServerTTL)
+	}
+}
+
+// Init 初始化内置DNS服务配置.
+func (d *DNSServerConfigImpl) Init() {
+}
@@ -65,6 +65,7 @@ type GlobalConfigImpl struct {
 	StatReporter    *StatReporterConfigImpl    `yaml:"statReporter" json:"statReporter"`
 	Location        *LocationConfigImpl        `yaml:"location" json:"location"`
 	Client          *ClientConfigImpl          `yaml:"client" json:"client"`
+	Sidecar         *SidecarConfigImpl         `yaml:"sidecar" json:"sidecar"`
 }
 
 // GetSystem 获取系统配置.
@@ -97,6 +98,11 @@ func (g *GlobalConfigImpl) GetClient() ClientConfig {
 	return g.Client
 }
 
+// GetSidecar global.sidecar前缀开头的所有配置项.
+func (g *GlobalConfigImpl) GetSidecar() SidecarConfig {
+	return g.Sidecar
+}
+
 // ConsumerConfigImpl 消费者配置.
 type ConsumerConfigImpl struct {
 	LocalCache       *LocalCacheConfigImpl     `yaml:"localCache" json:"localCache"`
@@ -105,6 +111,20 @@ type ConsumerConfigImpl struct {
 	CircuitBreaker   *CircuitBreakerConfigImpl `yaml:"circuitBreaker" json:"circuitBreaker"`
 	HealthCheck      *HealthCheckConfigImpl    `yaml:"healthCheck" json:"healthCheck"`
 	ServicesSpecific []*ServiceSpecific        `yaml:"servicesSpecific" json:"servicesSpecific"`
+	// 命名空间级默认配置
+	NamespacesSpecific []*NamespaceSpecific `yaml:"namespacesSpecific" json:"namespacesSpecific"`
+	// 内置DNS服务
+	DNSServer *DNSServerConfigImpl `yaml:"dnsServer" json:"dnsServer"`
+	// 单次逻辑请求的尝试预算
+	RequestBudget *RequestBudgetConfigImpl `yaml:"requestBudget" json:"requestBudget"`
+	// 实例地址转换
+	AddressTranslation *AddressTranslationConfigImpl `yaml:"addressTranslation" json:"addressTranslation"`
+	// 服务名映射
+	NameMapping *NameMappingConfigImpl `yaml:"nameMapping" json:"nameMapping"`
+	// 命名空间回退
+	NamespaceFallback *NamespaceFallbackConfigImpl `yaml:"namespaceFallback" json:"namespaceFallback"`
+	// 契约校验
+	ContractValidation *ContractValidationConfigImpl `yaml:"contractValidation" json:"contractValidation"`
 }
 
 // GetLocalCache consumer.localCache前缀开头的所有配置.
@@ -132,6 +152,36 @@ func (c *ConsumerConfigImpl) GetHealthCheck() HealthCheckConfig {
 	return c.HealthCheck
 }
 
+// GetDNSServer consumer.dnsServer前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetDNSServer() DNSServerConfig {
+	return c.DNSServer
+}
+
+// GetRequestBudget consumer.requestBudget前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetRequestBudget() RequestBudgetConfig {
+	return c.RequestBudget
+}
+
+// GetAddressTranslation consumer.addressTranslation前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetAddressTranslation() AddressTranslationConfig {
+	return c.AddressTranslation
+}
+
+// GetNameMapping consumer.nameMapping前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetNameMapping() NameMappingConfig {
+	return c.NameMapping
+}
+
+// GetNamespaceFallback consumer.namespaceFallback前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetNamespaceFallback() NamespaceFallbackConfig {
+	return c.NamespaceFallback
+}
+
+// GetContractValidation consumer.contractValidation前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetContractValidation() ContractValidationConfig {
+	return c.ContractValidation
+}
+
 // GetServiceSpecific 服务独立配置.
 func (c *ConsumerConfigImpl) GetServiceSpecific(namespace string, service string) ServiceSpecificConfig {
 	for _, v := range c.ServicesSpecific {
@@ -142,6 +192,34 @@ func (c *ConsumerConfigImpl) GetServiceSpecific(namespace string, service string
 	return nil
 }
 
+// GetServicesSpecific 全部服务独立配置.
+func (c *ConsumerConfigImpl) GetServicesSpecific() []ServiceSpecificConfig {
+	values := make([]ServiceSpecificConfig, 0, len(c.ServicesSpecific))
+	for _, v := range c.ServicesSpecific {
+		values = append(values, v)
+	}
+	return values
+}
+
+// GetNamespaceSpecific 命名空间级默认配置.
+func (c *ConsumerConfigImpl) GetNamespaceSpecific(namespace string) NamespaceSpecificConfig {
+	for _, v := range c.NamespacesSpecific {
+		if v.Namespace == namespace {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetNamespacesSpecific 全部命名空间级默认配置.
+func (c *ConsumerConfigImpl) GetNamespacesSpecific() []NamespaceSpecificConfig {
+	values := make([]NamespaceSpecificConfig, 0, len(c.NamespacesSpecific))
+	for _, v := range c.NamespacesSpecific {
+		values = append(values, v)
+	}
+	return values
+}
+
 // SystemConfigImpl 系统配置.
 type SystemConfigImpl struct {
 	// SDK运行模式
@@ -267,6 +345,14 @@ type APIConfigImpl struct {
 	ReportInterval *time.Duration `yaml:"reportInterval" json:"reportInterval"`
 	MaxRetryTimes  int            `yaml:"maxRetryTimes" json:"maxRetryTimes"`
 	RetryInterval  *time.Duration `yaml:"retryInterval" json:"retryInterval"`
+	// PluginPanicThreshold 插件在统计窗口内panic达到该次数后被禁用，0表示不禁用
+	PluginPanicThreshold int `yaml:"pluginPanicThreshold" json:"pluginPanicThreshold"`
+	// PluginPanicWindow 插件panic次数的统计窗口
+	PluginPanicWindow *time.Duration `yaml:"pluginPanicWindow" json:"pluginPanicWindow"`
+	// RecentCallsSize 内存中保留的最近API调用记录数，-1表示不记录
+	RecentCallsSize int `yaml:"recentCallsSize" json:"recentCallsSize"`
+	// FlowBudget 获取单个实例整个流程的默认耗时预算，0表示不限制
+	FlowBudget *time.Duration `yaml:"flowBudget" json:"flowBudget"`
 }
 
 // GetTimeout 默认调用超时时间.
@@ -329,6 +415,46 @@ func (a *APIConfigImpl) SetRetryInterval(interval time.Duration) {
 	a.RetryInterval = &interval
 }
 
+// GetPluginPanicThreshold 插件禁用的panic次数.
+func (a *APIConfigImpl) GetPluginPanicThreshold() int {
+	return a.PluginPanicThreshold
+}
+
+// SetPluginPanicThreshold 设置插件禁用的panic次数.
+func (a *APIConfigImpl) SetPluginPanicThreshold(threshold int) {
+	a.PluginPanicThreshold = threshold
+}
+
+// GetPluginPanicWindow 插件panic次数的统计窗口.
+func (a *APIConfigImpl) GetPluginPanicWindow() time.Duration {
+	return *a.PluginPanicWindow
+}
+
+// SetPluginPanicWindow 设置插件panic次数的统计窗口.
+func (a *APIConfigImpl) SetPluginPanicWindow(window time.Duration) {
+	a.PluginPanicWindow = &window
+}
+
+// GetRecentCallsSize 内存中保留的最近API调用记录数.
+func (a *APIConfigImpl) GetRecentCallsSize() int {
+	return a.RecentCallsSize
+}
+
+// SetRecentCallsSize 设置内存中保留的最近API调用记录数.
+func (a *APIConfigImpl) SetRecentCallsSize(size int) {
+	a.RecentCallsSize = size
+}
+
+// GetFlowBudget 获取单个实例整个流程的默认耗时预算.
+func (a *APIConfigImpl) GetFlowBudget() time.Duration {
+	return *a.FlowBudget
+}
+
+// SetFlowBudget 设置获取单个实例整个流程的默认耗时预算.
+func (a *APIConfigImpl) SetFlowBudget(budget time.Duration) {
+	a.FlowBudget = &budget
+}
+
 // NewDefaultConfiguration 创建默认配置对象.
 func NewDefaultConfiguration(addresses []string) *ConfigurationImpl {
 	cfg := &ConfigurationImpl{}
@@ -394,6 +520,10 @@ func LoadConfiguration(buf []byte) (*ConfigurationImpl, error) {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
 			"fail to decode config string")
 	}
+	if err = VerifyPluginConfigSchemas(cfg); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"fail to verify plugin config")
+	}
 	cfg.SetDefault()
 	if err = cfg.Verify(); err != nil {
 		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
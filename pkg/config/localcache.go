@@ -44,6 +44,9 @@ type LocalCacheConfigImpl struct {
 	Type string `yaml:"type" json:"type"`
 	// 是否启用本地缓存
 	PersistEnable *bool `yaml:"persistEnable" json:"persistEnable"`
+	// consumer.localCache.persistBackend
+	// 本地缓存持久化存储后端，file为每个资源一个文件，kv为单文件的嵌入式KV存储
+	PersistBackend string `yaml:"persistBackend" json:"persistBackend"`
 	// consumer.localCache.persistMaxWriteRetry
 	PersistMaxWriteRetry int `yaml:"persistMaxWriteRetry" json:"persistMaxWriteRetry"`
 	// consumer.localCache.persistReadRetry
@@ -56,6 +59,18 @@ type LocalCacheConfigImpl struct {
 	StartUseFileCache *bool `yaml:"startUseFileCache" json:"startUseFileCache"`
 	// PushEmptyProtection 推空保护开关
 	PushEmptyProtection *bool `yaml:"pushEmptyProtection" json:"pushEmptyProtection"`
+	// WarmUpManifest 缓存预热清单文件路径，启动时会预先订阅并加载清单中的服务
+	WarmUpManifest string `yaml:"warmUpManifest" json:"warmUpManifest"`
+	// RevisionAudit 缓存版本巡检，定期抽样与服务端核对缓存版本
+	RevisionAudit *RevisionAuditConfigImpl `yaml:"revisionAudit" json:"revisionAudit"`
+	// MemoryBudget 缓存内存预算，超出后进入降载模式
+	MemoryBudget *MemoryBudgetConfigImpl `yaml:"memoryBudget" json:"memoryBudget"`
+	// HealthSmoothingThreshold 实例健康状态平滑阈值，连续观察到该次数的相同新状态后路由才使用新的健康状态
+	HealthSmoothingThreshold int `yaml:"healthSmoothingThreshold" json:"healthSmoothingThreshold"`
+	// PreloadParallelism 启动时并行加载持久化缓存的协程数
+	PreloadParallelism int `yaml:"preloadParallelism" json:"preloadParallelism"`
+	// PreloadDeadline 启动时加载持久化缓存的最长等待时间，超时后启动继续，剩余的缓存转为后台加载，0表示等待全部加载完成
+	PreloadDeadline *time.Duration `yaml:"preloadDeadline" json:"preloadDeadline"`
 	// 插件相关配置
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
@@ -65,6 +80,12 @@ var (
 	DefaultUseFileCacheFlag = true
 	// DefaultPushEmptyProtection 推空保护默认关闭
 	DefaultPushEmptyProtection = false
+	// DefaultPersistBackend 默认使用每个资源一个文件的持久化格式
+	DefaultPersistBackend = "file"
+	// DefaultHealthSmoothingThreshold 默认不对实例健康状态做平滑，观察到变化后立即生效
+	DefaultHealthSmoothingThreshold = 1
+	// DefaultPreloadParallelism 默认串行加载持久化缓存
+	DefaultPreloadParallelism = 1
 )
 
 // GetServiceExpireTime consumer.localCache.service.expireTime,
@@ -111,6 +132,17 @@ func (l *LocalCacheConfigImpl) SetPersistDir(dir string) {
 	l.PersistDir = dir
 }
 
+// GetPersistBackend consumer.localCache.persistBackend
+// 本地缓存持久化存储后端.
+func (l *LocalCacheConfigImpl) GetPersistBackend() string {
+	return l.PersistBackend
+}
+
+// SetPersistBackend 设置本地缓存持久化存储后端.
+func (l *LocalCacheConfigImpl) SetPersistBackend(backend string) {
+	l.PersistBackend = backend
+}
+
 // GetPersistMaxWriteRetry consumer.localCache.persist.maxWriteRetry.
 func (l *LocalCacheConfigImpl) GetPersistMaxWriteRetry() int {
 	return l.PersistMaxWriteRetry
@@ -182,6 +214,56 @@ func (l *LocalCacheConfigImpl) GetPushEmptyProtection() bool {
 	return *l.PushEmptyProtection
 }
 
+// GetWarmUpManifest 获取缓存预热清单文件路径
+func (l *LocalCacheConfigImpl) GetWarmUpManifest() string {
+	return l.WarmUpManifest
+}
+
+// SetWarmUpManifest 设置缓存预热清单文件路径
+func (l *LocalCacheConfigImpl) SetWarmUpManifest(manifest string) {
+	l.WarmUpManifest = manifest
+}
+
+// GetHealthSmoothingThreshold 获取实例健康状态平滑阈值
+func (l *LocalCacheConfigImpl) GetHealthSmoothingThreshold() int {
+	return l.HealthSmoothingThreshold
+}
+
+// SetHealthSmoothingThreshold 设置实例健康状态平滑阈值
+func (l *LocalCacheConfigImpl) SetHealthSmoothingThreshold(threshold int) {
+	l.HealthSmoothingThreshold = threshold
+}
+
+// GetPreloadParallelism 获取启动时并行加载持久化缓存的协程数
+func (l *LocalCacheConfigImpl) GetPreloadParallelism() int {
+	return l.PreloadParallelism
+}
+
+// SetPreloadParallelism 设置启动时并行加载持久化缓存的协程数
+func (l *LocalCacheConfigImpl) SetPreloadParallelism(parallelism int) {
+	l.PreloadParallelism = parallelism
+}
+
+// GetPreloadDeadline 获取启动时加载持久化缓存的最长等待时间
+func (l *LocalCacheConfigImpl) GetPreloadDeadline() time.Duration {
+	return *l.PreloadDeadline
+}
+
+// SetPreloadDeadline 设置启动时加载持久化缓存的最长等待时间
+func (l *LocalCacheConfigImpl) SetPreloadDeadline(deadline time.Duration) {
+	l.PreloadDeadline = &deadline
+}
+
+// GetRevisionAudit 获取缓存版本巡检配置
+func (l *LocalCacheConfigImpl) GetRevisionAudit() RevisionAuditConfig {
+	return l.RevisionAudit
+}
+
+// GetMemoryBudget 获取缓存内存预算配置
+func (l *LocalCacheConfigImpl) GetMemoryBudget() MemoryBudgetConfig {
+	return l.MemoryBudget
+}
+
 // GetPluginConfig consumer.localCache.plugin.
 func (l *LocalCacheConfigImpl) GetPluginConfig(pluginName string) BaseConfig {
 	cfgValue, ok := l.Plugin[pluginName]
@@ -206,6 +288,24 @@ func (l *LocalCacheConfigImpl) Verify() error {
 		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.serviceExpireTime %v"+
 			" is less than the minimal allowed duration %v", l.ServiceExpireTime, DefaultMinServiceExpireTime))
 	}
+	if l.HealthSmoothingThreshold < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.healthSmoothingThreshold %d"+
+			" must be greater than 0", l.HealthSmoothingThreshold))
+	}
+	if l.PreloadParallelism < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.preloadParallelism %d"+
+			" must be greater than 0", l.PreloadParallelism))
+	}
+	if *l.PreloadDeadline < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.preloadDeadline %v"+
+			" must not be negative", *l.PreloadDeadline))
+	}
+	if err := l.RevisionAudit.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := l.MemoryBudget.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	plugErr := l.Plugin.Verify()
 	if nil != plugErr {
 		errs = multierror.Append(errs, plugErr)
@@ -230,6 +330,9 @@ func (l *LocalCacheConfigImpl) SetDefault() {
 	if len(l.Type) == 0 {
 		l.Type = DefaultLocalCache
 	}
+	if len(l.PersistBackend) == 0 {
+		l.PersistBackend = DefaultPersistBackend
+	}
 	if nil == l.PersistRetryInterval {
 		l.PersistRetryInterval = model.ToDurationPtr(DefaultPersistRetryInterval)
 	}
@@ -248,6 +351,23 @@ func (l *LocalCacheConfigImpl) SetDefault() {
 	if nil == l.PushEmptyProtection {
 		l.PushEmptyProtection = &DefaultPushEmptyProtection
 	}
+	if l.HealthSmoothingThreshold == 0 {
+		l.HealthSmoothingThreshold = DefaultHealthSmoothingThreshold
+	}
+	if l.PreloadParallelism == 0 {
+		l.PreloadParallelism = DefaultPreloadParallelism
+	}
+	if nil == l.PreloadDeadline {
+		l.PreloadDeadline = model.ToDurationPtr(0)
+	}
+	if nil == l.RevisionAudit {
+		l.RevisionAudit = &RevisionAuditConfigImpl{}
+	}
+	l.RevisionAudit.SetDefault()
+	if nil == l.MemoryBudget {
+		l.MemoryBudget = &MemoryBudgetConfigImpl{}
+	}
+	l.MemoryBudget.SetDefault()
 	l.Plugin.SetDefault(common.TypeLocalRegistry)
 }
 
@@ -255,4 +375,159 @@ func (l *LocalCacheConfigImpl) SetDefault() {
 func (l *LocalCacheConfigImpl) Init() {
 	l.Plugin = PluginConfigs{}
 	l.Plugin.Init(common.TypeLocalRegistry)
+	l.RevisionAudit = &RevisionAuditConfigImpl{}
+	l.MemoryBudget = &MemoryBudgetConfigImpl{}
+}
+
+// RevisionAuditConfigImpl 缓存版本巡检配置，定期抽样部分已订阅的资源，通过独立的请求与服务端核对版本号，
+// 发现不一致时上报指标并强制刷新缓存，用于发现网络分区等场景下长期不更新的缓存.
+type RevisionAuditConfigImpl struct {
+	// 是否启用巡检
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 巡检周期
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// 每次巡检抽样的订阅资源数
+	SampleSize int `yaml:"sampleSize" json:"sampleSize"`
+}
+
+// IsEnable 是否启用缓存版本巡检
+func (r *RevisionAuditConfigImpl) IsEnable() bool {
+	return *r.Enable
+}
+
+// SetEnable 设置是否启用缓存版本巡检
+func (r *RevisionAuditConfigImpl) SetEnable(enable bool) {
+	r.Enable = &enable
+}
+
+// GetInterval 获取巡检周期
+func (r *RevisionAuditConfigImpl) GetInterval() time.Duration {
+	return r.Interval
+}
+
+// SetInterval 设置巡检周期
+func (r *RevisionAuditConfigImpl) SetInterval(interval time.Duration) {
+	r.Interval = interval
+}
+
+// GetSampleSize 获取每次巡检抽样的订阅资源数
+func (r *RevisionAuditConfigImpl) GetSampleSize() int {
+	return r.SampleSize
+}
+
+// SetSampleSize 设置每次巡检抽样的订阅资源数
+func (r *RevisionAuditConfigImpl) SetSampleSize(size int) {
+	r.SampleSize = size
+}
+
+// Verify 检验缓存版本巡检配置
+func (r *RevisionAuditConfigImpl) Verify() error {
+	if nil == r {
+		return errors.New("RevisionAuditConfig is nil")
+	}
+	if nil == r.Enable || !*r.Enable {
+		return nil
+	}
+	var errs error
+	if r.Interval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.revisionAudit.interval %v"+
+			" is less than the minimal allowed duration %v", r.Interval, DefaultMinTimingInterval))
+	}
+	if r.SampleSize <= 0 {
+		errs = multierror.Append(errs,
+			errors.New("consumer.localCache.revisionAudit.sampleSize should be greater than zero"))
+	}
+	return errs
+}
+
+// SetDefault 设置缓存版本巡检配置的默认值
+func (r *RevisionAuditConfigImpl) SetDefault() {
+	if nil == r.Enable {
+		r.Enable = model.ToBoolPtr(DefaultRevisionAuditEnable)
+	}
+	if r.Interval == 0 {
+		r.Interval = DefaultRevisionAuditInterval
+	}
+	if r.SampleSize == 0 {
+		r.SampleSize = DefaultRevisionAuditSampleSize
+	}
+}
+
+// MemoryBudgetConfigImpl 缓存内存预算配置，本地缓存的估算内存超出预算后进入降载模式，
+// 按最近最少使用淘汰未被订阅的服务缓存，并放大新订阅服务的刷新周期，避免SDK占满宿主进程的内存.
+type MemoryBudgetConfigImpl struct {
+	// 缓存内存预算，单位字节，0表示不限制
+	MaxBytes int64 `yaml:"maxBytes" json:"maxBytes"`
+	// 内存预算检查周期
+	CheckInterval time.Duration `yaml:"checkInterval" json:"checkInterval"`
+	// 降载模式下新订阅服务的刷新周期倍数
+	ShedRefreshMultiplier int `yaml:"shedRefreshMultiplier" json:"shedRefreshMultiplier"`
+}
+
+// GetMaxBytes 获取缓存内存预算
+func (m *MemoryBudgetConfigImpl) GetMaxBytes() int64 {
+	return m.MaxBytes
+}
+
+// SetMaxBytes 设置缓存内存预算
+func (m *MemoryBudgetConfigImpl) SetMaxBytes(maxBytes int64) {
+	m.MaxBytes = maxBytes
+}
+
+// GetCheckInterval 获取内存预算检查周期
+func (m *MemoryBudgetConfigImpl) GetCheckInterval() time.Duration {
+	return m.CheckInterval
+}
+
+// SetCheckInterval 设置内存预算检查周期
+func (m *MemoryBudgetConfigImpl) SetCheckInterval(interval time.Duration) {
+	m.CheckInterval = interval
+}
+
+// GetShedRefreshMultiplier 获取降载模式下新订阅服务的刷新周期倍数
+func (m *MemoryBudgetConfigImpl) GetShedRefreshMultiplier() int {
+	return m.ShedRefreshMultiplier
+}
+
+// SetShedRefreshMultiplier 设置降载模式下新订阅服务的刷新周期倍数
+func (m *MemoryBudgetConfigImpl) SetShedRefreshMultiplier(multiplier int) {
+	m.ShedRefreshMultiplier = multiplier
+}
+
+// Verify 检验缓存内存预算配置
+func (m *MemoryBudgetConfigImpl) Verify() error {
+	if nil == m {
+		return errors.New("MemoryBudgetConfig is nil")
+	}
+	var errs error
+	if m.MaxBytes < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.memoryBudget.maxBytes %d"+
+			" must not be negative", m.MaxBytes))
+	}
+	if m.MaxBytes == 0 {
+		return errs
+	}
+	if m.CheckInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.memoryBudget.checkInterval %v"+
+			" is less than the minimal allowed duration %v", m.CheckInterval, DefaultMinTimingInterval))
+	}
+	if m.ShedRefreshMultiplier < 1 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.localCache.memoryBudget.shedRefreshMultiplier %d"+
+			" must be greater than 0", m.ShedRefreshMultiplier))
+	}
+	return errs
+}
+
+// SetDefault 设置缓存内存预算配置的默认值
+func (m *MemoryBudgetConfigImpl) SetDefault() {
+	if m.CheckInterval == 0 {
+		m.CheckInterval = DefaultMemoryBudgetCheckInterval
+	}
+	if m.ShedRefreshMultiplier == 0 {
+		m.ShedRefreshMultiplier = DefaultMemoryBudgetShedRefreshMultiplier
+	}
+}
+
+// Init 初始化缓存内存预算配置
+func (m *MemoryBudgetConfigImpl) Init() {
 }
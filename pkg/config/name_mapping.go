@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// NameMappingRule 服务名映射规则.
+type NameMappingRule struct {
+	// 调用方使用的命名空间，为空代表全部命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 调用方使用的服务名，以*结尾时代表前缀匹配
+	Service string `yaml:"service" json:"service"`
+	// 映射后的命名空间，为空时保持不变
+	TargetNamespace string `yaml:"targetNamespace" json:"targetNamespace"`
+	// 映射后的服务名，为空时保持不变；前缀规则中以*结尾时替换匹配的前缀
+	TargetService string `yaml:"targetService" json:"targetService"`
+}
+
+// Verify 检验服务名映射规则.
+func (r *NameMappingRule) Verify() error {
+	if nil == r {
+		return errors.New("name mapping rule is nil")
+	}
+	if len(r.Service) == 0 {
+		return errors.New("service is required")
+	}
+	if len(r.TargetNamespace) == 0 && len(r.TargetService) == 0 {
+		return fmt.Errorf("targetNamespace or targetService is required for %s", r.Service)
+	}
+	if strings.HasSuffix(r.TargetService, "*") && !strings.HasSuffix(r.Service, "*") {
+		return fmt.Errorf("targetService %s is a prefix but service %s is not", r.TargetService, r.Service)
+	}
+	return nil
+}
+
+// NameMappingConfigImpl 服务名映射配置，调用方可以继续使用旧的命名，发现前映射为实际的服务名，返回时再映射回旧的命名.
+type NameMappingConfigImpl struct {
+	// 静态映射规则，按顺序匹配，第一个匹配的规则生效
+	Rules []*NameMappingRule `yaml:"rules" json:"rules"`
+	// 映射规则文件，内容为rules列表，文件变更后自动生效，优先于静态规则匹配
+	File string `yaml:"file" json:"file"`
+	// 映射规则文件的变更检查周期
+	RefreshInterval *time.Duration `yaml:"refreshInterval" json:"refreshInterval"`
+}
+
+// GetRules 获取静态映射规则.
+func (n *NameMappingConfigImpl) GetRules() []*NameMappingRule {
+	return n.Rules
+}
+
+// SetRules 设置静态映射规则.
+func (n *NameMappingConfigImpl) SetRules(rules []*NameMappingRule) {
+	n.Rules = rules
+}
+
+// GetFile 获取映射规则文件.
+func (n *NameMappingConfigImpl) GetFile() string {
+	return n.File
+}
+
+// SetFile 设置映射规则文件.
+func (n *NameMappingConfigImpl) SetFile(file string) {
+	n.File = file
+}
+
+// GetRefreshInterval 获取映射规则文件的变更检查周期.
+func (n *NameMappingConfigImpl) GetRefreshInterval() time.Duration {
+	if nil == n.RefreshInterval {
+		return DefaultNameMappingRefreshInterval
+	}
+	return *n.RefreshInterval
+}
+
+// SetRefreshInterval 设置映射规则文件的变更检查周期.
+func (n *NameMappingConfigImpl) SetRefreshInterval(interval time.Duration) {
+	n.RefreshInterval = &interval
+}
+
+// Verify 检验服务名映射配置.
+func (n *NameMappingConfigImpl) Verify() error {
+	if nil == n {
+		return errors.New("NameMappingConfig is nil")
+	}
+	var errs error
+	for i, rule := range n.Rules {
+		if err := rule.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.nameMapping.rules[%d]: %v", i, err))
+		}
+	}
+	if nil != n.RefreshInterval && *n.RefreshInterval < time.Second {
+		errs = multierror.Append(errs, errors.New("consumer.nameMapping.refreshInterval should not be less than 1s"))
+	}
+	return errs
+}
+
+// SetDefault 设置服务名映射配置的默认值.
+func (n *NameMappingConfigImpl) SetDefault() {
+	if nil == n.RefreshInterval {
+		interval := DefaultNameMappingRefreshInterval
+		n.RefreshInterval = &interval
+	}
+}
+
+// Init 初始化服务名映射配置.
+func (n *NameMappingConfigImpl) Init() {
+}
@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// NamespaceFallbackRule 命名空间回退规则.
+type NamespaceFallbackRule struct {
+	// 调用方请求的命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 服务在请求的命名空间中不存在时，按顺序尝试的回退命名空间
+	Fallbacks []string `yaml:"fallbacks" json:"fallbacks"`
+}
+
+// Verify 检验命名空间回退规则.
+func (r *NamespaceFallbackRule) Verify() error {
+	if nil == r {
+		return errors.New("namespace fallback rule is nil")
+	}
+	if len(r.Namespace) == 0 {
+		return errors.New("namespace is required")
+	}
+	if len(r.Fallbacks) == 0 {
+		return fmt.Errorf("fallbacks is required for namespace %s", r.Namespace)
+	}
+	visited := map[string]struct{}{r.Namespace: {}}
+	for _, fallback := range r.Fallbacks {
+		if len(fallback) == 0 {
+			return fmt.Errorf("empty fallback namespace for namespace %s", r.Namespace)
+		}
+		if _, ok := visited[fallback]; ok {
+			return fmt.Errorf("duplicated fallback namespace %s for namespace %s", fallback, r.Namespace)
+		}
+		visited[fallback] = struct{}{}
+	}
+	return nil
+}
+
+// NamespaceFallbackConfigImpl 命名空间回退配置，服务在请求的命名空间中不存在时，按顺序到回退命名空间中查找，
+// 用于使用平台提供的共享服务.
+type NamespaceFallbackConfigImpl struct {
+	// 回退规则，每个命名空间最多一条
+	Rules []*NamespaceFallbackRule `yaml:"rules" json:"rules"`
+}
+
+// GetRules 获取回退规则.
+func (n *NamespaceFallbackConfigImpl) GetRules() []*NamespaceFallbackRule {
+	return n.Rules
+}
+
+// SetRules 设置回退规则.
+func (n *NamespaceFallbackConfigImpl) SetRules(rules []*NamespaceFallbackRule) {
+	n.Rules = rules
+}
+
+// GetFallbacks 获取命名空间的回退链，未配置时返回nil.
+func (n *NamespaceFallbackConfigImpl) GetFallbacks(namespace string) []string {
+	for _, rule := range n.Rules {
+		if nil != rule && rule.Namespace == namespace {
+			return rule.Fallbacks
+		}
+	}
+	return nil
+}
+
+// Verify 检验命名空间回退配置.
+func (n *NamespaceFallbackConfigImpl) Verify() error {
+	if nil == n {
+		return errors.New("NamespaceFallbackConfig is nil")
+	}
+	var errs error
+	namespaces := make(map[string]struct{}, len(n.Rules))
+	for i, rule := range n.Rules {
+		if err := rule.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.namespaceFallback.rules[%d]: %v", i, err))
+			continue
+		}
+		if _, ok := namespaces[rule.Namespace]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("consumer.namespaceFallback.rules[%d]: duplicated namespace %s",
+				i, rule.Namespace))
+		}
+		namespaces[rule.Namespace] = struct{}{}
+	}
+	return errs
+}
+
+// SetDefault 设置命名空间回退配置的默认值.
+func (n *NamespaceFallbackConfigImpl) SetDefault() {
+}
+
+// Init 初始化命名空间回退配置.
+func (n *NamespaceFallbackConfigImpl) Init() {
+}
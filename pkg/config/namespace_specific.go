@@ -0,0 +1,109 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// NamespaceSpecific 命名空间级别的默认配置，命名空间下的所有服务都会继承，除非在服务级配置中进行了覆盖
+// 未配置的字段继承consumer下的全局配置
+type NamespaceSpecific struct {
+	// 命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 服务路由链，为空则继承consumer.serviceRouter.chain
+	RouterChain []string `yaml:"routerChain" json:"routerChain"`
+	// 负载均衡类型，为空则继承consumer.loadbalancer.type
+	LbPolicy string `yaml:"lbPolicy" json:"lbPolicy"`
+	// 服务缓存淘汰时间，为空则继承consumer.localCache.serviceExpireTime
+	ServiceExpireTime *time.Duration `yaml:"serviceExpireTime" json:"serviceExpireTime"`
+	// 服务定期刷新周期，为空则继承consumer.localCache.serviceRefreshInterval
+	ServiceRefreshInterval *time.Duration `yaml:"serviceRefreshInterval" json:"serviceRefreshInterval"`
+	// 是否启用熔断，为空则继承consumer.circuitBreaker.enable
+	CircuitBreakerEnable *bool `yaml:"circuitBreakerEnable" json:"circuitBreakerEnable"`
+}
+
+// GetNamespace 获取命名空间.
+func (n *NamespaceSpecific) GetNamespace() string {
+	return n.Namespace
+}
+
+// GetRouterChain 获取命名空间级的路由链，为空表示继承全局配置.
+func (n *NamespaceSpecific) GetRouterChain() []string {
+	return n.RouterChain
+}
+
+// GetLbPolicy 获取命名空间级的负载均衡类型，为空表示继承全局配置.
+func (n *NamespaceSpecific) GetLbPolicy() string {
+	return n.LbPolicy
+}
+
+// GetServiceExpireTime 获取命名空间级的服务缓存淘汰时间，为0表示继承全局配置.
+func (n *NamespaceSpecific) GetServiceExpireTime() time.Duration {
+	if n.ServiceExpireTime == nil {
+		return 0
+	}
+	return *n.ServiceExpireTime
+}
+
+// GetServiceRefreshInterval 获取命名空间级的服务刷新周期，为0表示继承全局配置.
+func (n *NamespaceSpecific) GetServiceRefreshInterval() time.Duration {
+	if n.ServiceRefreshInterval == nil {
+		return 0
+	}
+	return *n.ServiceRefreshInterval
+}
+
+// GetCircuitBreakerEnable 获取命名空间级的熔断开关，为nil表示继承全局配置.
+func (n *NamespaceSpecific) GetCircuitBreakerEnable() *bool {
+	return n.CircuitBreakerEnable
+}
+
+// Init 初始化.
+func (n *NamespaceSpecific) Init() {
+}
+
+// SetDefault 设置默认值，命名空间级配置的缺省值即为全局配置，这里无需处理.
+func (n *NamespaceSpecific) SetDefault() {
+}
+
+// Verify 校验配置.
+func (n *NamespaceSpecific) Verify() error {
+	if nil == n {
+		return errors.New("NamespaceSpecific is nil")
+	}
+	if len(n.Namespace) == 0 {
+		return errors.New("consumer.namespacesSpecific.namespace is empty")
+	}
+	var errs error
+	if n.ServiceExpireTime != nil && *n.ServiceExpireTime < DefaultMinServiceExpireTime {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.namespacesSpecific[%s].serviceExpireTime %v"+
+			" is less than the minimal allowed duration %v", n.Namespace, *n.ServiceExpireTime,
+			DefaultMinServiceExpireTime))
+	}
+	if n.ServiceRefreshInterval != nil && *n.ServiceRefreshInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.namespacesSpecific[%s].serviceRefreshInterval %v"+
+			" is less than the minimal allowed duration %v", n.Namespace, *n.ServiceRefreshInterval,
+			DefaultMinTimingInterval))
+	}
+	return errs
+}
@@ -155,8 +155,9 @@ func (p PluginConfigs) SetDefault(typ common.Type) {
 	}
 }
 
-// Verify 校验插件配置.
+// Verify 校验插件配置，汇总所有插件的校验错误.
 func (p PluginConfigs) Verify() error {
+	var errs error
 	for name, cfgValue := range p {
 		cfg, ok := cfgValue.(BaseConfig)
 		if !ok {
@@ -164,10 +165,11 @@ func (p PluginConfigs) Verify() error {
 		}
 		// 检验插件配置
 		if err := cfg.Verify(); err != nil {
-			return fmt.Errorf("fail to verify plugin %s config, err is %v", name, err)
+			errs = multierror.Append(errs, fmt.Errorf("fail to verify plugin %s config at %s, err is %v",
+				name, pluginConfigPathOf(name, cfg), err))
 		}
 	}
-	return nil
+	return errs
 }
 
 // SetPluginConfig 设置单独一个插件的值.
@@ -0,0 +1,174 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// pluginConfigPaths 各类型插件配置在配置文件中的路径，用于在错误信息中定位配置项
+var pluginConfigPaths = map[common.Type]string{
+	common.TypeServerConnector: "global.serverConnector.plugin",
+	common.TypeStatReporter:    "global.statReporter.plugin",
+	common.TypeLocalRegistry:   "consumer.localCache.plugin",
+	common.TypeServiceRouter:   "consumer.serviceRouter.plugin",
+	common.TypeLoadBalancer:    "consumer.loadbalancer.plugin",
+	common.TypeCircuitBreaker:  "consumer.circuitBreaker.plugin",
+	common.TypeHealthCheck:     "consumer.healthCheck.plugin",
+	common.TypeRateLimiter:     "provider.rateLimit.plugin",
+	common.TypeConfigConnector: "config.configConnector.plugin",
+	common.TypeConfigFilter:    "config.configFilter.plugin",
+}
+
+// yaml解码错误中的行号是重新序列化后的行号，对用户没有意义
+var yamlLinePattern = regexp.MustCompile(`line \d+: `)
+
+// pluginConfigPath 获取插件配置在配置文件中的路径
+func pluginConfigPath(typ common.Type, name string) string {
+	path, ok := pluginConfigPaths[typ]
+	if !ok {
+		path = typ.String() + ".plugin"
+	}
+	return path + "." + name
+}
+
+// pluginConfigPathOf 根据配置对象的类型查找插件配置在配置文件中的路径
+func pluginConfigPathOf(name string, cfg interface{}) string {
+	cfgType := reflect.TypeOf(cfg)
+	if cfgType.Kind() == reflect.Ptr {
+		cfgType = cfgType.Elem()
+	}
+	for typ, cfgTypes := range pluginConfigTypes {
+		if registered, ok := cfgTypes[name]; ok && registered == cfgType {
+			return pluginConfigPath(typ, name)
+		}
+	}
+	return name
+}
+
+// VerifyPluginConfigSchemas 按插件注册的配置类型严格校验配置文件中的插件配置，
+// 需要在设置默认值之前调用，未知字段以及类型不匹配的错误会汇总返回，错误信息包含插件配置的路径
+func VerifyPluginConfigSchemas(cfg *ConfigurationImpl) error {
+	var errs error
+	check := func(typ common.Type, plugins PluginConfigs) {
+		if err := plugins.verifySchema(typ); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if global := cfg.Global; global != nil {
+		if global.ServerConnector != nil {
+			check(common.TypeServerConnector, global.ServerConnector.Plugin)
+		}
+		if global.StatReporter != nil {
+			check(common.TypeStatReporter, global.StatReporter.Plugin)
+		}
+	}
+	if consumer := cfg.Consumer; consumer != nil {
+		if consumer.LocalCache != nil {
+			check(common.TypeLocalRegistry, consumer.LocalCache.Plugin)
+		}
+		if consumer.ServiceRouter != nil {
+			check(common.TypeServiceRouter, consumer.ServiceRouter.Plugin)
+		}
+		if consumer.Loadbalancer != nil {
+			check(common.TypeLoadBalancer, consumer.Loadbalancer.Plugin)
+		}
+		if consumer.CircuitBreaker != nil {
+			check(common.TypeCircuitBreaker, consumer.CircuitBreaker.Plugin)
+		}
+		if consumer.HealthCheck != nil {
+			check(common.TypeHealthCheck, consumer.HealthCheck.Plugin)
+		}
+	}
+	if cfg.Provider != nil && cfg.Provider.RateLimit != nil {
+		check(common.TypeRateLimiter, cfg.Provider.RateLimit.Plugin)
+	}
+	if configFile := cfg.Config; configFile != nil {
+		if configFile.ConfigConnectorConfig != nil {
+			check(common.TypeConfigConnector, configFile.ConfigConnectorConfig.Plugin)
+		}
+		if configFile.ConfigFilterConfig != nil {
+			check(common.TypeConfigFilter, configFile.ConfigFilterConfig.Plugin)
+		}
+	}
+	return errs
+}
+
+// verifySchema 校验从配置文件加载的插件配置是否与插件注册的配置类型一致，未注册配置类型的插件不做校验
+func (p PluginConfigs) verifySchema(typ common.Type) error {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs error
+	for _, name := range names {
+		values, ok := p[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		cfgType, ok := getPluginConfigType(typ, name)
+		if !ok {
+			continue
+		}
+		path := pluginConfigPath(typ, name)
+		// 逐个字段解码，使错误信息可以定位到具体的字段
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field := map[interface{}]interface{}{key: values[key]}
+			if err := decodePluginConfig(cfgType, field); err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("invalid plugin config %s.%s (type %s): %s",
+					path, key, cfgType, err))
+			}
+		}
+	}
+	return errs
+}
+
+// decodePluginConfig 严格解码插件配置，不允许出现配置类型中不存在的字段
+func decodePluginConfig(cfgType reflect.Type, values map[interface{}]interface{}) error {
+	buf, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err = yaml.UnmarshalStrict(buf, reflect.New(cfgType).Interface()); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return err
+		}
+		details := make([]string, 0, len(typeErr.Errors))
+		for _, detail := range typeErr.Errors {
+			details = append(details, yamlLinePattern.ReplaceAllString(detail, ""))
+		}
+		return fmt.Errorf("%s", strings.Join(details, "; "))
+	}
+	return nil
+}
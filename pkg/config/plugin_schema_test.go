@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+type schemaTestConfig struct {
+	Threshold int            `yaml:"threshold"`
+	Interval  *time.Duration `yaml:"interval"`
+}
+
+func (s *schemaTestConfig) Verify() error {
+	if s.Threshold < 0 {
+		return errors.New("threshold must not be negative")
+	}
+	return nil
+}
+
+func (s *schemaTestConfig) SetDefault() {
+	if s.Interval == nil {
+		interval := time.Second
+		s.Interval = &interval
+	}
+}
+
+// TestVerifyPluginConfigSchemas 测试插件配置的未知字段、类型不匹配以及校验失败汇总报错，且包含配置路径
+func TestVerifyPluginConfigSchemas(t *testing.T) {
+	RegisterPluginConfigType(common.TypeServiceRouter, "schemaTest", &schemaTestConfig{})
+	defer delete(pluginConfigTypes[common.TypeServiceRouter], "schemaTest")
+
+	_, err := LoadConfiguration([]byte(`
+consumer:
+  serviceRouter:
+    plugin:
+      schemaTest:
+        threshold: abc
+        unknownField: 1
+`))
+	if err == nil {
+		t.Fatal("expect plugin config schema error")
+	}
+	for _, expect := range []string{"consumer.serviceRouter.plugin.schemaTest.unknownField",
+		"consumer.serviceRouter.plugin.schemaTest.threshold"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Fatalf("expect %q in error, got %v", expect, err)
+		}
+	}
+
+	_, err = LoadConfiguration([]byte(`
+consumer:
+  serviceRouter:
+    plugin:
+      schemaTest:
+        threshold: -1
+`))
+	if err == nil || !strings.Contains(err.Error(), "consumer.serviceRouter.plugin.schemaTest") {
+		t.Fatalf("expect plugin verify error with path, got %v", err)
+	}
+
+	cfg, err := LoadConfiguration([]byte(`
+global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  serviceRouter:
+    plugin:
+      schemaTest:
+        threshold: 3
+        interval: 5s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pluginCfg := cfg.GetConsumer().GetServiceRouter().GetPluginConfig("schemaTest").(*schemaTestConfig)
+	if pluginCfg.Threshold != 3 || *pluginCfg.Interval != 5*time.Second {
+		t.Fatalf("unexpected plugin config %+v", pluginCfg)
+	}
+}
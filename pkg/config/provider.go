@@ -19,6 +19,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -27,12 +28,39 @@ import (
 // DefaultRateLimitEnable 默认打开限流能力
 var DefaultRateLimitEnable = true
 
+// DefaultFlappingEnable 默认打开注册状态抖动检测
+var DefaultFlappingEnable = true
+
+// DefaultRateLimitShareLocalQuota 默认不在进程间共享单机限流配额
+var DefaultRateLimitShareLocalQuota = false
+
+// DefaultDualRegistrationEnable 默认不启用双注册
+var DefaultDualRegistrationEnable = false
+
+// DefaultDualRegistrationReverse 默认不反转双注册的主备角色
+var DefaultDualRegistrationReverse = false
+
+// DefaultProvisionalInstanceEnable 默认不在本地注入未确认的自注册实例
+var DefaultProvisionalInstanceEnable = false
+
 // ProviderConfigImpl 服务提供者配置.
 type ProviderConfigImpl struct {
 	// 限流配置
 	RateLimit *RateLimitConfigImpl `yaml:"rateLimit" json:"rateLimit"`
 	// minimum interval between tow register operation
 	MinRgisterInterval time.Duration `yaml:"minRegisterInterval" json:"minRegisterInterval"`
+	// 注册状态抖动检测配置
+	Flapping *FlappingConfigImpl `yaml:"flapping" json:"flapping"`
+	// 心跳上报配置
+	Heartbeat *HeartbeatConfigImpl `yaml:"heartbeat" json:"heartbeat"`
+	// 双注册配置
+	DualRegistration *DualRegistrationConfigImpl `yaml:"dualRegistration" json:"dualRegistration"`
+	// 自注册实例本地注入配置
+	ProvisionalInstance *ProvisionalInstanceConfigImpl `yaml:"provisionalInstance" json:"provisionalInstance"`
+	// 进入lameduck状态后实例的心跳TTL，单位秒，用于进程即将被强制终止时让服务端尽快摘除实例
+	LameduckTTL int `yaml:"lameduckTTL" json:"lameduckTTL"`
+	// 优雅下线时隔离实例后等待在途请求处理完成的最长时间
+	ShutdownDrainPeriod time.Duration `yaml:"shutdownDrainPeriod" json:"shutdownDrainPeriod"`
 }
 
 // GetRateLimit 是否启用限流能力.
@@ -45,6 +73,46 @@ func (p *ProviderConfigImpl) GetMinRegisterInterval() time.Duration {
 	return p.MinRgisterInterval
 }
 
+// GetFlapping 获取注册状态抖动检测配置.
+func (p *ProviderConfigImpl) GetFlapping() FlappingConfig {
+	return p.Flapping
+}
+
+// GetHeartbeat 获取心跳上报配置.
+func (p *ProviderConfigImpl) GetHeartbeat() HeartbeatConfig {
+	return p.Heartbeat
+}
+
+// GetDualRegistration 获取双注册配置.
+func (p *ProviderConfigImpl) GetDualRegistration() DualRegistrationConfig {
+	return p.DualRegistration
+}
+
+// GetProvisionalInstance 获取自注册实例本地注入配置.
+func (p *ProviderConfigImpl) GetProvisionalInstance() ProvisionalInstanceConfig {
+	return p.ProvisionalInstance
+}
+
+// GetLameduckTTL 获取进入lameduck状态后实例的心跳TTL.
+func (p *ProviderConfigImpl) GetLameduckTTL() int {
+	return p.LameduckTTL
+}
+
+// SetLameduckTTL 设置进入lameduck状态后实例的心跳TTL.
+func (p *ProviderConfigImpl) SetLameduckTTL(ttl int) {
+	p.LameduckTTL = ttl
+}
+
+// GetShutdownDrainPeriod 获取优雅下线时等待在途请求处理完成的最长时间.
+func (p *ProviderConfigImpl) GetShutdownDrainPeriod() time.Duration {
+	return p.ShutdownDrainPeriod
+}
+
+// SetShutdownDrainPeriod 设置优雅下线时等待在途请求处理完成的最长时间.
+func (p *ProviderConfigImpl) SetShutdownDrainPeriod(period time.Duration) {
+	p.ShutdownDrainPeriod = period
+}
+
 // Verify 校验配置参数.
 func (p *ProviderConfigImpl) Verify() error {
 	if nil == p {
@@ -58,6 +126,24 @@ func (p *ProviderConfigImpl) Verify() error {
 	if p.MinRgisterInterval <= 0 {
 		errs = multierror.Append(errs, errors.New("minRegisterInterval should be greater than zero"))
 	}
+	if err = p.Flapping.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = p.Heartbeat.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = p.DualRegistration.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = p.ProvisionalInstance.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if p.LameduckTTL <= 0 {
+		errs = multierror.Append(errs, errors.New("lameduckTTL should be greater than zero"))
+	}
+	if p.ShutdownDrainPeriod < 0 {
+		errs = multierror.Append(errs, errors.New("shutdownDrainPeriod can not be negative"))
+	}
 	return errs
 }
 
@@ -70,10 +156,368 @@ func (p *ProviderConfigImpl) SetDefault() {
 	if p.MinRgisterInterval == 0 {
 		p.MinRgisterInterval = DefaultMinRegisterInterval
 	}
+	if nil == p.Flapping {
+		p.Flapping = &FlappingConfigImpl{}
+	}
+	p.Flapping.SetDefault()
+	if nil == p.Heartbeat {
+		p.Heartbeat = &HeartbeatConfigImpl{}
+	}
+	p.Heartbeat.SetDefault()
+	if nil == p.DualRegistration {
+		p.DualRegistration = &DualRegistrationConfigImpl{}
+	}
+	p.DualRegistration.SetDefault()
+	if nil == p.ProvisionalInstance {
+		p.ProvisionalInstance = &ProvisionalInstanceConfigImpl{}
+	}
+	p.ProvisionalInstance.SetDefault()
+	if p.LameduckTTL == 0 {
+		p.LameduckTTL = DefaultLameduckTTL
+	}
+	if p.ShutdownDrainPeriod == 0 {
+		p.ShutdownDrainPeriod = DefaultShutdownDrainPeriod
+	}
 }
 
 // Init 配置初始化.
 func (p *ProviderConfigImpl) Init() {
 	p.RateLimit = &RateLimitConfigImpl{}
 	p.RateLimit.Init()
+	p.Flapping = &FlappingConfigImpl{}
+	p.Heartbeat = &HeartbeatConfigImpl{}
+	p.DualRegistration = &DualRegistrationConfigImpl{}
+	p.ProvisionalInstance = &ProvisionalInstanceConfigImpl{}
+}
+
+// FlappingConfigImpl 注册状态抖动检测配置，实例的注册、反注册及心跳状态在窗口内变化过于频繁时，
+// 对重新注册进行退避，避免崩溃循环的实例频繁变更注册中心数据.
+type FlappingConfigImpl struct {
+	// 是否启用抖动检测
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 统计窗口
+	Window time.Duration `yaml:"window" json:"window"`
+	// 窗口内允许的最大状态变化次数，超过则判定为抖动
+	Threshold int `yaml:"threshold" json:"threshold"`
+	// 首次判定为抖动后的退避时长，持续抖动时成倍增加
+	BaseBackoff time.Duration `yaml:"baseBackoff" json:"baseBackoff"`
+	// 最大退避时长
+	MaxBackoff time.Duration `yaml:"maxBackoff" json:"maxBackoff"`
+}
+
+// IsEnable 是否启用抖动检测.
+func (f *FlappingConfigImpl) IsEnable() bool {
+	return *f.Enable
+}
+
+// SetEnable 设置是否启用抖动检测.
+func (f *FlappingConfigImpl) SetEnable(enable bool) {
+	f.Enable = &enable
+}
+
+// GetWindow 获取统计窗口.
+func (f *FlappingConfigImpl) GetWindow() time.Duration {
+	return f.Window
+}
+
+// SetWindow 设置统计窗口.
+func (f *FlappingConfigImpl) SetWindow(window time.Duration) {
+	f.Window = window
+}
+
+// GetThreshold 获取窗口内允许的最大状态变化次数.
+func (f *FlappingConfigImpl) GetThreshold() int {
+	return f.Threshold
+}
+
+// SetThreshold 设置窗口内允许的最大状态变化次数.
+func (f *FlappingConfigImpl) SetThreshold(threshold int) {
+	f.Threshold = threshold
+}
+
+// GetBaseBackoff 获取首次退避时长.
+func (f *FlappingConfigImpl) GetBaseBackoff() time.Duration {
+	return f.BaseBackoff
+}
+
+// SetBaseBackoff 设置首次退避时长.
+func (f *FlappingConfigImpl) SetBaseBackoff(backoff time.Duration) {
+	f.BaseBackoff = backoff
+}
+
+// GetMaxBackoff 获取最大退避时长.
+func (f *FlappingConfigImpl) GetMaxBackoff() time.Duration {
+	return f.MaxBackoff
+}
+
+// SetMaxBackoff 设置最大退避时长.
+func (f *FlappingConfigImpl) SetMaxBackoff(backoff time.Duration) {
+	f.MaxBackoff = backoff
+}
+
+// Verify 校验配置参数.
+func (f *FlappingConfigImpl) Verify() error {
+	if nil == f {
+		return errors.New("FlappingConfig is nil")
+	}
+	if nil == f.Enable {
+		return errors.New("provider.flapping.enable must not be nil")
+	}
+	var errs error
+	if f.Window <= 0 {
+		errs = multierror.Append(errs, errors.New("provider.flapping.window should be greater than zero"))
+	}
+	if f.Threshold <= 0 {
+		errs = multierror.Append(errs, errors.New("provider.flapping.threshold should be greater than zero"))
+	}
+	if f.BaseBackoff <= 0 || f.MaxBackoff < f.BaseBackoff {
+		errs = multierror.Append(errs,
+			errors.New("provider.flapping.baseBackoff should be greater than zero and not greater than maxBackoff"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认参数.
+func (f *FlappingConfigImpl) SetDefault() {
+	if nil == f.Enable {
+		f.Enable = &DefaultFlappingEnable
+	}
+	if f.Window == 0 {
+		f.Window = DefaultFlappingWindow
+	}
+	if f.Threshold == 0 {
+		f.Threshold = DefaultFlappingThreshold
+	}
+	if f.BaseBackoff == 0 {
+		f.BaseBackoff = DefaultFlappingBaseBackoff
+	}
+	if f.MaxBackoff == 0 {
+		f.MaxBackoff = DefaultFlappingMaxBackoff
+	}
+}
+
+// HeartbeatConfigImpl 心跳上报配置，大规模实例场景下可以使用UDP上报心跳以降低控制面开销，
+// 需要服务端支持UDP心跳，连续丢包时回退到GRPC.
+type HeartbeatConfigImpl struct {
+	// 心跳协议，grpc或udp
+	Protocol string `yaml:"protocol" json:"protocol"`
+	// UDP心跳的服务端地址列表
+	UDPAddresses []string `yaml:"udpAddresses" json:"udpAddresses"`
+	// UDP心跳应答等待时间
+	UDPAckTimeout time.Duration `yaml:"udpAckTimeout" json:"udpAckTimeout"`
+	// UDP心跳连续丢包次数上限，超过后回退到GRPC
+	UDPMaxLoss int `yaml:"udpMaxLoss" json:"udpMaxLoss"`
+	// 回退到GRPC后重新尝试UDP心跳的间隔
+	UDPFallbackInterval time.Duration `yaml:"udpFallbackInterval" json:"udpFallbackInterval"`
+}
+
+// GetProtocol 获取心跳协议.
+func (h *HeartbeatConfigImpl) GetProtocol() string {
+	return h.Protocol
+}
+
+// SetProtocol 设置心跳协议.
+func (h *HeartbeatConfigImpl) SetProtocol(protocol string) {
+	h.Protocol = protocol
+}
+
+// GetUDPAddresses 获取UDP心跳的服务端地址列表.
+func (h *HeartbeatConfigImpl) GetUDPAddresses() []string {
+	return h.UDPAddresses
+}
+
+// SetUDPAddresses 设置UDP心跳的服务端地址列表.
+func (h *HeartbeatConfigImpl) SetUDPAddresses(addresses []string) {
+	h.UDPAddresses = addresses
+}
+
+// GetUDPAckTimeout 获取UDP心跳应答等待时间.
+func (h *HeartbeatConfigImpl) GetUDPAckTimeout() time.Duration {
+	return h.UDPAckTimeout
+}
+
+// GetUDPMaxLoss 获取UDP心跳连续丢包次数上限.
+func (h *HeartbeatConfigImpl) GetUDPMaxLoss() int {
+	return h.UDPMaxLoss
+}
+
+// GetUDPFallbackInterval 获取回退到GRPC后重新尝试UDP心跳的间隔.
+func (h *HeartbeatConfigImpl) GetUDPFallbackInterval() time.Duration {
+	return h.UDPFallbackInterval
+}
+
+// Verify 校验配置参数.
+func (h *HeartbeatConfigImpl) Verify() error {
+	if nil == h {
+		return errors.New("HeartbeatConfig is nil")
+	}
+	switch h.Protocol {
+	case HeartbeatProtocolGRPC:
+		return nil
+	case HeartbeatProtocolUDP:
+	default:
+		return fmt.Errorf("provider.heartbeat.protocol %s is not supported, must be %s or %s",
+			h.Protocol, HeartbeatProtocolGRPC, HeartbeatProtocolUDP)
+	}
+	var errs error
+	if len(h.UDPAddresses) == 0 {
+		errs = multierror.Append(errs, errors.New("provider.heartbeat.udpAddresses can not be empty"))
+	}
+	if h.UDPAckTimeout <= 0 {
+		errs = multierror.Append(errs, errors.New("provider.heartbeat.udpAckTimeout should be greater than zero"))
+	}
+	if h.UDPMaxLoss <= 0 {
+		errs = multierror.Append(errs, errors.New("provider.heartbeat.udpMaxLoss should be greater than zero"))
+	}
+	if h.UDPFallbackInterval <= 0 {
+		errs = multierror.Append(errs,
+			errors.New("provider.heartbeat.udpFallbackInterval should be greater than zero"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认参数.
+func (h *HeartbeatConfigImpl) SetDefault() {
+	if len(h.Protocol) == 0 {
+		h.Protocol = HeartbeatProtocolGRPC
+	}
+	if h.UDPAckTimeout == 0 {
+		h.UDPAckTimeout = DefaultHeartbeatUDPAckTimeout
+	}
+	if h.UDPMaxLoss == 0 {
+		h.UDPMaxLoss = DefaultHeartbeatUDPMaxLoss
+	}
+	if h.UDPFallbackInterval == 0 {
+		h.UDPFallbackInterval = DefaultHeartbeatUDPFallbackInterval
+	}
+}
+
+// DualRegistrationConfigImpl 双注册配置，注册中心迁移期间同时向新旧两个服务端集群注册、心跳及反注册，
+// 两个集群的失败相互独立，只有主集群的结果会返回给调用方.
+type DualRegistrationConfigImpl struct {
+	// 是否启用双注册
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 旧集群的服务端地址列表
+	Addresses []string `yaml:"addresses" json:"addresses"`
+	// 旧集群的鉴权token，为空时使用serverConnector的token
+	Token string `yaml:"token" json:"token"`
+	// 是否反转主备角色，反转后旧集群为主集群，serverConnector配置的集群为备集群
+	Reverse *bool `yaml:"reverse" json:"reverse"`
+}
+
+// IsEnable 是否启用双注册.
+func (d *DualRegistrationConfigImpl) IsEnable() bool {
+	return *d.Enable
+}
+
+// SetEnable 设置是否启用双注册.
+func (d *DualRegistrationConfigImpl) SetEnable(enable bool) {
+	d.Enable = &enable
+}
+
+// GetAddresses 获取旧集群的服务端地址列表.
+func (d *DualRegistrationConfigImpl) GetAddresses() []string {
+	return d.Addresses
+}
+
+// SetAddresses 设置旧集群的服务端地址列表.
+func (d *DualRegistrationConfigImpl) SetAddresses(addresses []string) {
+	d.Addresses = addresses
+}
+
+// GetToken 获取旧集群的鉴权token.
+func (d *DualRegistrationConfigImpl) GetToken() string {
+	return d.Token
+}
+
+// SetToken 设置旧集群的鉴权token.
+func (d *DualRegistrationConfigImpl) SetToken(token string) {
+	d.Token = token
+}
+
+// IsReverse 是否反转主备角色.
+func (d *DualRegistrationConfigImpl) IsReverse() bool {
+	return *d.Reverse
+}
+
+// SetReverse 设置是否反转主备角色.
+func (d *DualRegistrationConfigImpl) SetReverse(reverse bool) {
+	d.Reverse = &reverse
+}
+
+// Verify 校验配置参数.
+func (d *DualRegistrationConfigImpl) Verify() error {
+	if nil == d {
+		return errors.New("DualRegistrationConfig is nil")
+	}
+	if nil == d.Enable || nil == d.Reverse {
+		return errors.New("provider.dualRegistration.enable and reverse must not be nil")
+	}
+	if *d.Enable && len(d.Addresses) == 0 {
+		return errors.New("provider.dualRegistration.addresses can not be empty when enabled")
+	}
+	return nil
+}
+
+// SetDefault 设置默认参数.
+func (d *DualRegistrationConfigImpl) SetDefault() {
+	if nil == d.Enable {
+		d.Enable = &DefaultDualRegistrationEnable
+	}
+	if nil == d.Reverse {
+		d.Reverse = &DefaultDualRegistrationReverse
+	}
+}
+
+// ProvisionalInstanceConfigImpl 自注册实例本地注入配置，注册成功后立即将实例注入本进程的GetInstances结果，
+// 实例标记为临时状态，直到服务端推送的实例列表中包含该实例或者超过存活时间.
+type ProvisionalInstanceConfigImpl struct {
+	// 是否启用自注册实例本地注入
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 临时实例的存活时间，超过后即使服务端未确认也不再注入
+	TTL time.Duration `yaml:"ttl" json:"ttl"`
+}
+
+// IsEnable 是否启用自注册实例本地注入.
+func (p *ProvisionalInstanceConfigImpl) IsEnable() bool {
+	return *p.Enable
+}
+
+// SetEnable 设置是否启用自注册实例本地注入.
+func (p *ProvisionalInstanceConfigImpl) SetEnable(enable bool) {
+	p.Enable = &enable
+}
+
+// GetTTL 获取临时实例的存活时间.
+func (p *ProvisionalInstanceConfigImpl) GetTTL() time.Duration {
+	return p.TTL
+}
+
+// SetTTL 设置临时实例的存活时间.
+func (p *ProvisionalInstanceConfigImpl) SetTTL(ttl time.Duration) {
+	p.TTL = ttl
+}
+
+// Verify 校验配置参数.
+func (p *ProvisionalInstanceConfigImpl) Verify() error {
+	if nil == p {
+		return errors.New("ProvisionalInstanceConfig is nil")
+	}
+	if nil == p.Enable {
+		return errors.New("provider.provisionalInstance.enable must not be nil")
+	}
+	if p.TTL <= 0 {
+		return errors.New("provider.provisionalInstance.ttl should be greater than zero")
+	}
+	return nil
+}
+
+// SetDefault 设置默认参数.
+func (p *ProvisionalInstanceConfigImpl) SetDefault() {
+	if nil == p.Enable {
+		p.Enable = &DefaultProvisionalInstanceEnable
+	}
+	if p.TTL == 0 {
+		p.TTL = DefaultProvisionalInstanceTTL
+	}
 }
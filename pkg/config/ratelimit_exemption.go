@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// RateLimitExemptionConfig 限流豁免配置，匹配的请求不占用配额也不会被限流，单独统计豁免的请求数，
+// 用于内部健康检查、运维工具等调用方；配置的各项条件需要同时满足
+type RateLimitExemptionConfig struct {
+	// Name 豁免名称，用于统计豁免的请求数
+	Name string `yaml:"name" json:"name"`
+	// Namespace 被调服务的命名空间，为空匹配全部
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Service 被调服务名，为空匹配全部
+	Service string `yaml:"service" json:"service"`
+	// Method 被调方法，为空匹配全部
+	Method string `yaml:"method" json:"method"`
+	// CallerIPs 主调IP或者CIDR网段，任一匹配即可
+	CallerIPs []string `yaml:"callerIPs" json:"callerIPs"`
+	// CallerServices 主调服务，格式为 namespace/service，任一匹配即可
+	CallerServices []string `yaml:"callerServices" json:"callerServices"`
+	// Labels 自定义参数，需要全部相等
+	Labels map[string]string `yaml:"labels" json:"labels"`
+}
+
+// Verify 校验限流豁免配置
+func (r *RateLimitExemptionConfig) Verify() error {
+	if len(r.Name) == 0 {
+		return errors.New("provider.rateLimit.exemptions[].name must not be empty")
+	}
+	if len(r.CallerIPs) == 0 && len(r.CallerServices) == 0 && len(r.Labels) == 0 {
+		return fmt.Errorf("provider.rateLimit.exemptions[%s] requires callerIPs, callerServices or labels", r.Name)
+	}
+	for _, callerIP := range r.CallerIPs {
+		if _, err := ParseIPNet(callerIP); err != nil {
+			return fmt.Errorf("provider.rateLimit.exemptions[%s].callerIPs: %v", r.Name, err)
+		}
+	}
+	for _, callerService := range r.CallerServices {
+		if namespace, service := SplitCallerService(callerService); len(namespace) == 0 || len(service) == 0 {
+			return fmt.Errorf("provider.rateLimit.exemptions[%s].callerServices %s, expect namespace/service",
+				r.Name, callerService)
+		}
+	}
+	return nil
+}
+
+// ParseIPNet 解析IP或者CIDR网段，单个IP按照全掩码处理
+func ParseIPNet(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, ipNet, err := net.ParseCIDR(value)
+		return ipNet, err
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip %s", value)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// SplitCallerService 拆分 namespace/service 格式的主调服务
+func SplitCallerService(value string) (string, string) {
+	idx := strings.Index(value, "/")
+	if idx < 0 {
+		return "", value
+	}
+	return value[:idx], value[idx+1:]
+}
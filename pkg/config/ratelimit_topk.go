@@ -0,0 +1,119 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RateLimitTopKConfigImpl 限流热点标签值统计配置，按规则统计消耗配额最多的标签值并周期上报.
+type RateLimitTopKConfigImpl struct {
+	// 是否启用热点标签值统计
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 每条规则每个周期上报的标签值数量
+	Size int `yaml:"size" json:"size"`
+	// 每条规则最多跟踪的标签值数量，超出后淘汰计数最小的标签值
+	MaxTracked int `yaml:"maxTracked" json:"maxTracked"`
+	// 上报周期
+	Interval *time.Duration `yaml:"interval" json:"interval"`
+}
+
+// IsEnable 是否启用热点标签值统计.
+func (r *RateLimitTopKConfigImpl) IsEnable() bool {
+	return *r.Enable
+}
+
+// SetEnable 设置是否启用热点标签值统计.
+func (r *RateLimitTopKConfigImpl) SetEnable(enable bool) {
+	r.Enable = &enable
+}
+
+// GetSize 获取每条规则每个周期上报的标签值数量.
+func (r *RateLimitTopKConfigImpl) GetSize() int {
+	return r.Size
+}
+
+// SetSize 设置每条规则每个周期上报的标签值数量.
+func (r *RateLimitTopKConfigImpl) SetSize(size int) {
+	r.Size = size
+}
+
+// GetMaxTracked 获取每条规则最多跟踪的标签值数量.
+func (r *RateLimitTopKConfigImpl) GetMaxTracked() int {
+	return r.MaxTracked
+}
+
+// SetMaxTracked 设置每条规则最多跟踪的标签值数量.
+func (r *RateLimitTopKConfigImpl) SetMaxTracked(maxTracked int) {
+	r.MaxTracked = maxTracked
+}
+
+// GetInterval 获取上报周期.
+func (r *RateLimitTopKConfigImpl) GetInterval() time.Duration {
+	return *r.Interval
+}
+
+// SetInterval 设置上报周期.
+func (r *RateLimitTopKConfigImpl) SetInterval(interval time.Duration) {
+	r.Interval = &interval
+}
+
+// Verify 检验热点标签值统计配置.
+func (r *RateLimitTopKConfigImpl) Verify() error {
+	if nil == r {
+		return errors.New("RateLimitTopKConfig is nil")
+	}
+	var errs error
+	if r.Size < 1 {
+		errs = multierror.Append(errs, errors.New("provider.rateLimit.topK.size must be greater than 0"))
+	}
+	if r.MaxTracked < r.Size {
+		errs = multierror.Append(errs,
+			errors.New("provider.rateLimit.topK.maxTracked can not be less than provider.rateLimit.topK.size"))
+	}
+	if nil == r.Interval || *r.Interval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			errors.New("provider.rateLimit.topK.interval can not be less than 100ms"))
+	}
+	return errs
+}
+
+// SetDefault 设置热点标签值统计配置的默认值.
+func (r *RateLimitTopKConfigImpl) SetDefault() {
+	if nil == r.Enable {
+		r.Enable = model.ToBoolPtr(false)
+	}
+	if r.Size == 0 {
+		r.Size = DefaultRateLimitTopKSize
+	}
+	if r.MaxTracked == 0 {
+		r.MaxTracked = DefaultRateLimitTopKMaxTracked
+	}
+	if nil == r.Interval {
+		r.SetInterval(DefaultRateLimitTopKInterval)
+	}
+}
+
+// Init 初始化热点标签值统计配置.
+func (r *RateLimitTopKConfigImpl) Init() {
+}
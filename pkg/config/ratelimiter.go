@@ -20,8 +20,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
@@ -39,6 +43,14 @@ type RateLimitConfigImpl struct {
 	LimiterNamespace string `yaml:"limiterNamespace" json:"limiterNamespace"`
 	// LimiterService 限流服务的服务名
 	LimiterService string `yaml:"limiterService" json:"limiterService"`
+	// ShareLocalQuota 同一主机上的多个进程是否共享单机限流配额
+	ShareLocalQuota *bool `yaml:"shareLocalQuota" json:"shareLocalQuota"`
+	// ShareDir 共享配额的内存映射文件所在目录，需要共享配额的进程必须配置相同的目录
+	ShareDir string `yaml:"shareDir" json:"shareDir"`
+	// TopK 按限流规则统计消耗配额最多的标签值
+	TopK *RateLimitTopKConfigImpl `yaml:"topK" json:"topK"`
+	// Exemptions 限流豁免列表，匹配的请求绕过限流
+	Exemptions []*RateLimitExemptionConfig `yaml:"exemptions" json:"exemptions"`
 }
 
 // IsEnable 是否启用限流能力.
@@ -62,7 +74,25 @@ func (r *RateLimitConfigImpl) Verify() error {
 	if nil == r.Enable {
 		return fmt.Errorf("provider.rateLimit.enable must not be nil")
 	}
-	return r.Plugin.Verify()
+	var errs error
+	if err := r.TopK.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := r.Plugin.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	names := make(map[string]struct{}, len(r.Exemptions))
+	for _, exemption := range r.Exemptions {
+		if err := exemption.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if _, ok := names[exemption.Name]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("provider.rateLimit.exemptions[%s] is duplicated", exemption.Name))
+		}
+		names[exemption.Name] = struct{}{}
+	}
+	return errs
 }
 
 // GetPluginConfig 获取插件配置.
@@ -91,6 +121,16 @@ func (r *RateLimitConfigImpl) SetDefault() {
 	if len(r.LimiterService) == 0 {
 		r.LimiterService = DefaultLimiterService
 	}
+	if r.ShareLocalQuota == nil {
+		r.ShareLocalQuota = &DefaultRateLimitShareLocalQuota
+	}
+	if len(r.ShareDir) == 0 {
+		r.ShareDir = filepath.Join(os.TempDir(), DefaultRateLimitShareDirName)
+	}
+	if nil == r.TopK {
+		r.TopK = &RateLimitTopKConfigImpl{}
+	}
+	r.TopK.SetDefault()
 	r.Plugin.SetDefault(common.TypeRateLimiter)
 }
 
@@ -103,6 +143,8 @@ func (r *RateLimitConfigImpl) SetPluginConfig(pluginName string, value BaseConfi
 func (r *RateLimitConfigImpl) Init() {
 	r.Plugin = PluginConfigs{}
 	r.Plugin.Init(common.TypeRateLimiter)
+	r.TopK = &RateLimitTopKConfigImpl{}
+	r.TopK.Init()
 }
 
 // GetMaxWindowSize .
@@ -140,3 +182,33 @@ func (r *RateLimitConfigImpl) SetLimiterNamespace(value string) {
 func (r *RateLimitConfigImpl) GetLimiterNamespace() string {
 	return r.LimiterNamespace
 }
+
+// IsShareLocalQuota 同一主机上的多个进程是否共享单机限流配额.
+func (r *RateLimitConfigImpl) IsShareLocalQuota() bool {
+	return *r.ShareLocalQuota
+}
+
+// SetShareLocalQuota 设置同一主机上的多个进程是否共享单机限流配额.
+func (r *RateLimitConfigImpl) SetShareLocalQuota(value bool) {
+	r.ShareLocalQuota = &value
+}
+
+// GetShareDir 获取共享配额的内存映射文件目录.
+func (r *RateLimitConfigImpl) GetShareDir() string {
+	return r.ShareDir
+}
+
+// SetShareDir 设置共享配额的内存映射文件目录.
+func (r *RateLimitConfigImpl) SetShareDir(dir string) {
+	r.ShareDir = dir
+}
+
+// GetTopK 获取限流热点标签值统计配置.
+func (r *RateLimitConfigImpl) GetTopK() RateLimitTopKConfig {
+	return r.TopK
+}
+
+// GetExemptions 获取限流豁免列表.
+func (r *RateLimitConfigImpl) GetExemptions() []*RateLimitExemptionConfig {
+	return r.Exemptions
+}
@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// RequestBudgetConfigImpl 单次逻辑请求的尝试预算配置，重试、对冲及熔断共享同一份预算.
+type RequestBudgetConfigImpl struct {
+	// 单次逻辑请求最多发起的尝试次数，包括首次请求、重试及对冲请求
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts"`
+	// 单次逻辑请求的总耗时上限相对于单次请求超时时间的倍数，为0时不限制总耗时
+	TimeoutMultiplier *float64 `yaml:"timeoutMultiplier" json:"timeoutMultiplier"`
+}
+
+// GetMaxAttempts 获取单次逻辑请求最多发起的尝试次数.
+func (r *RequestBudgetConfigImpl) GetMaxAttempts() int {
+	return r.MaxAttempts
+}
+
+// SetMaxAttempts 设置单次逻辑请求最多发起的尝试次数.
+func (r *RequestBudgetConfigImpl) SetMaxAttempts(maxAttempts int) {
+	r.MaxAttempts = maxAttempts
+}
+
+// GetTimeoutMultiplier 获取总耗时上限相对于单次请求超时时间的倍数.
+func (r *RequestBudgetConfigImpl) GetTimeoutMultiplier() float64 {
+	return *r.TimeoutMultiplier
+}
+
+// SetTimeoutMultiplier 设置总耗时上限相对于单次请求超时时间的倍数.
+func (r *RequestBudgetConfigImpl) SetTimeoutMultiplier(multiplier float64) {
+	r.TimeoutMultiplier = &multiplier
+}
+
+// Verify 检验请求预算配置.
+func (r *RequestBudgetConfigImpl) Verify() error {
+	if nil == r {
+		return errors.New("RequestBudgetConfig is nil")
+	}
+	var errs error
+	if r.MaxAttempts < 1 {
+		errs = multierror.Append(errs, errors.New("consumer.requestBudget.maxAttempts must be greater than 0"))
+	}
+	if r.GetTimeoutMultiplier() < 0 {
+		errs = multierror.Append(errs, errors.New("consumer.requestBudget.timeoutMultiplier can not be negative"))
+	}
+	return errs
+}
+
+// SetDefault 设置请求预算配置的默认值.
+func (r *RequestBudgetConfigImpl) SetDefault() {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = DefaultRequestBudgetMaxAttempts
+	}
+	if nil == r.TimeoutMultiplier {
+		r.SetTimeoutMultiplier(DefaultRequestBudgetTimeoutMultiplier)
+	}
+}
+
+// Init 初始化请求预算配置.
+func (r *RequestBudgetConfigImpl) Init() {
+}
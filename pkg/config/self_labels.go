@@ -0,0 +1,96 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SelfLabelsConfigImpl 主调方自身标签配置，启用后规则路由除了请求标签外，还会使用主调方自身的服务信息及实例元数据进行匹配，
+// 自身信息来自本配置以及最近一次注册成功且标记为 SelfInstance 的自身实例，配置的元数据优先.
+type SelfLabelsConfigImpl struct {
+	// 是否启用自身标签匹配
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 主调方所在的命名空间，为空时使用注册实例的命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 主调方的服务名，为空时使用注册实例的服务名
+	Service string `yaml:"service" json:"service"`
+	// 主调方的自身标签，例如部署分组、版本号
+	Metadata map[string]string `yaml:"metadata" json:"metadata"`
+}
+
+// IsEnable 是否启用自身标签匹配.
+func (s *SelfLabelsConfigImpl) IsEnable() bool {
+	return *s.Enable
+}
+
+// SetEnable 设置是否启用自身标签匹配.
+func (s *SelfLabelsConfigImpl) SetEnable(enable bool) {
+	s.Enable = &enable
+}
+
+// GetNamespace 获取主调方所在的命名空间.
+func (s *SelfLabelsConfigImpl) GetNamespace() string {
+	return s.Namespace
+}
+
+// SetNamespace 设置主调方所在的命名空间.
+func (s *SelfLabelsConfigImpl) SetNamespace(namespace string) {
+	s.Namespace = namespace
+}
+
+// GetService 获取主调方的服务名.
+func (s *SelfLabelsConfigImpl) GetService() string {
+	return s.Service
+}
+
+// SetService 设置主调方的服务名.
+func (s *SelfLabelsConfigImpl) SetService(service string) {
+	s.Service = service
+}
+
+// GetMetadata 获取主调方的自身标签.
+func (s *SelfLabelsConfigImpl) GetMetadata() map[string]string {
+	return s.Metadata
+}
+
+// SetMetadata 设置主调方的自身标签.
+func (s *SelfLabelsConfigImpl) SetMetadata(metadata map[string]string) {
+	s.Metadata = metadata
+}
+
+// Verify 检验自身标签配置.
+func (s *SelfLabelsConfigImpl) Verify() error {
+	if nil == s {
+		return errors.New("SelfLabelsConfig is nil")
+	}
+	if len(s.Namespace) > 0 && len(s.Service) == 0 {
+		return fmt.Errorf("consumer.serviceRouter.selfLabels.service must be set with namespace %s", s.Namespace)
+	}
+	return nil
+}
+
+// SetDefault 设置自身标签配置的默认值.
+func (s *SelfLabelsConfigImpl) SetDefault() {
+	if nil == s.Enable {
+		s.Enable = model.ToBoolPtr(DefaultSelfLabelsEnable)
+	}
+}
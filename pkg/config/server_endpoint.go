@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ServerEndpointConfig 服务端地址配置，用于指定地址的优先级及权重.
+type ServerEndpointConfig struct {
+	// Address 服务端地址，格式为<host>:<port>
+	Address string `yaml:"address" json:"address"`
+	// Priority 优先级，数值越小越优先，只有高优先级的地址全部不可用时才会使用低优先级（容灾）地址
+	Priority int `yaml:"priority" json:"priority"`
+	// Weight 同一优先级内的权重，为0时使用默认权重
+	Weight int `yaml:"weight" json:"weight"`
+}
+
+// GetWeight 获取地址权重.
+func (e *ServerEndpointConfig) GetWeight() int {
+	if e.Weight <= 0 {
+		return DefaultServerEndpointWeight
+	}
+	return e.Weight
+}
+
+// Verify 检验服务端地址配置.
+func (e *ServerEndpointConfig) Verify() error {
+	if nil == e {
+		return errors.New("ServerEndpointConfig is nil")
+	}
+	if len(e.Address) == 0 {
+		return errors.New("endpoint address is empty")
+	}
+	if e.Priority < 0 {
+		return fmt.Errorf("priority of endpoint %s can not be negative", e.Address)
+	}
+	if e.Weight < 0 {
+		return fmt.Errorf("weight of endpoint %s can not be negative", e.Address)
+	}
+	return nil
+}
+
+// MergeServerEndpoints 合并addresses及endpoints配置，addresses中的地址使用最高优先级及默认权重.
+func MergeServerEndpoints(addresses []string, endpoints []*ServerEndpointConfig) []*ServerEndpointConfig {
+	ret := make([]*ServerEndpointConfig, 0, len(addresses)+len(endpoints))
+	exists := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		if nil == endpoint || exists[endpoint.Address] {
+			continue
+		}
+		exists[endpoint.Address] = true
+		ret = append(ret, endpoint)
+	}
+	for _, address := range addresses {
+		if exists[address] {
+			continue
+		}
+		exists[address] = true
+		ret = append(ret, &ServerEndpointConfig{Address: address, Weight: DefaultServerEndpointWeight})
+	}
+	return ret
+}
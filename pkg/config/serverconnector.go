@@ -51,6 +51,21 @@ type ServerConnectorConfigImpl struct {
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 
 	Token string `yaml:"token" json:"token"`
+
+	// 带优先级及权重的server地址，与addresses合并使用
+	Endpoints []*ServerEndpointConfig `yaml:"endpoints" json:"endpoints"`
+
+	// 切换到低优先级server后，尝试切回高优先级server的间隔
+	FailbackInterval *time.Duration `yaml:"failbackInterval" json:"failbackInterval"`
+
+	// 连接的最大存活时间，超过后重建连接，避免连接长期绑定在同一个server上
+	MaxConnectionAge *time.Duration `yaml:"maxConnectionAge" json:"maxConnectionAge"`
+
+	// 连接的最大空闲时间，超过后释放连接
+	MaxConnectionIdle *time.Duration `yaml:"maxConnectionIdle" json:"maxConnectionIdle"`
+
+	// 连接超过最大存活时间后重建的随机延迟上限，避免大量客户端同时重连
+	ReconnectJitter *time.Duration `yaml:"reconnectJitter" json:"reconnectJitter"`
 }
 
 // GetAddresses global.serverConnector.addresses
@@ -166,15 +181,94 @@ func (s *ServerConnectorConfigImpl) SetToken(t string) {
 }
 
 
+// GetEndpoints global.serverConnector.endpoints
+// 带优先级及权重的server地址.
+func (s *ServerConnectorConfigImpl) GetEndpoints() []*ServerEndpointConfig {
+	return s.Endpoints
+}
+
+// SetEndpoints 设置带优先级及权重的server地址.
+func (s *ServerConnectorConfigImpl) SetEndpoints(endpoints []*ServerEndpointConfig) {
+	s.Endpoints = endpoints
+}
+
+// GetFailbackInterval global.serverConnector.failbackInterval
+// 切换到低优先级server后，尝试切回高优先级server的间隔.
+func (s *ServerConnectorConfigImpl) GetFailbackInterval() time.Duration {
+	return *s.FailbackInterval
+}
+
+// SetFailbackInterval 设置尝试切回高优先级server的间隔.
+func (s *ServerConnectorConfigImpl) SetFailbackInterval(interval time.Duration) {
+	s.FailbackInterval = &interval
+}
+
+// GetMaxConnectionAge global.serverConnector.maxConnectionAge
+// 连接的最大存活时间.
+func (s *ServerConnectorConfigImpl) GetMaxConnectionAge() time.Duration {
+	return *s.MaxConnectionAge
+}
+
+// SetMaxConnectionAge 设置连接的最大存活时间.
+func (s *ServerConnectorConfigImpl) SetMaxConnectionAge(age time.Duration) {
+	s.MaxConnectionAge = &age
+}
+
+// GetMaxConnectionIdle global.serverConnector.maxConnectionIdle
+// 连接的最大空闲时间.
+func (s *ServerConnectorConfigImpl) GetMaxConnectionIdle() time.Duration {
+	return *s.MaxConnectionIdle
+}
+
+// SetMaxConnectionIdle 设置连接的最大空闲时间.
+func (s *ServerConnectorConfigImpl) SetMaxConnectionIdle(idle time.Duration) {
+	s.MaxConnectionIdle = &idle
+}
+
+// GetReconnectJitter global.serverConnector.reconnectJitter
+// 连接超过最大存活时间后重建的随机延迟上限.
+func (s *ServerConnectorConfigImpl) GetReconnectJitter() time.Duration {
+	return *s.ReconnectJitter
+}
+
+// SetReconnectJitter 设置连接超过最大存活时间后重建的随机延迟上限.
+func (s *ServerConnectorConfigImpl) SetReconnectJitter(jitter time.Duration) {
+	s.ReconnectJitter = &jitter
+}
+
 // Verify 检验ServerConnector配置.
 func (s *ServerConnectorConfigImpl) Verify() error {
 	if nil == s {
 		return errors.New("ServerConnectorConfig is nil")
 	}
 	var errs error
-	if len(s.Addresses) == 0 {
+	if len(s.Addresses) == 0 && len(s.Endpoints) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.addresses is empty"))
 	}
+	for _, endpoint := range s.Endpoints {
+		if err := endpoint.Verify(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.endpoints: %v", err))
+		}
+	}
+	if nil != s.FailbackInterval && *s.FailbackInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.failbackInterval %v is less than minimal timing interval %v",
+				*s.FailbackInterval, DefaultMinTimingInterval))
+	}
+	if nil != s.MaxConnectionAge && *s.MaxConnectionAge != 0 && *s.MaxConnectionAge < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.maxConnectionAge %v is less than minimal timing interval %v",
+				*s.MaxConnectionAge, DefaultMinTimingInterval))
+	}
+	if nil != s.MaxConnectionIdle && *s.MaxConnectionIdle != 0 && *s.MaxConnectionIdle < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.maxConnectionIdle %v is less than minimal timing interval %v",
+				*s.MaxConnectionIdle, DefaultMinTimingInterval))
+	}
+	if nil != s.ReconnectJitter && *s.ReconnectJitter < 0 {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.reconnectJitter %v should not be negative", *s.ReconnectJitter))
+	}
 	if nil != s.RequestQueueSize && *s.RequestQueueSize < 0 {
 		errs = multierror.Append(errs,
 			fmt.Errorf("global.serverConnector.requestQueueSize %v is invalid", s.RequestQueueSize))
@@ -214,6 +308,18 @@ func (s *ServerConnectorConfigImpl) SetDefault() {
 	if nil == s.ReconnectInterval {
 		s.ReconnectInterval = model.ToDurationPtr(DefaultReConnectInterval)
 	}
+	if nil == s.FailbackInterval {
+		s.FailbackInterval = model.ToDurationPtr(DefaultServerFailbackInterval)
+	}
+	if nil == s.MaxConnectionAge {
+		s.MaxConnectionAge = model.ToDurationPtr(DefaultMaxConnectionAge)
+	}
+	if nil == s.MaxConnectionIdle {
+		s.MaxConnectionIdle = model.ToDurationPtr(DefaultMaxConnectionIdle)
+	}
+	if nil == s.ReconnectJitter {
+		s.ReconnectJitter = model.ToDurationPtr(DefaultReconnectJitter)
+	}
 	if len(s.Protocol) == 0 {
 		s.Protocol = DefaultServerConnector
 	}
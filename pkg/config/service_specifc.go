@@ -18,15 +18,57 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/modern-go/reflect2"
 )
 
-// ServiceSpecific .
+// ServiceSpecific 服务级配置，优先级高于命名空间级以及consumer下的全局配置，未配置的字段逐级继承
 type ServiceSpecific struct {
 	Namespace      string                    `yaml:"namespace" json:"namespace"`
 	Service        string                    `yaml:"service" json:"service"`
 	ServiceRouter  *ServiceRouterConfigImpl  `yaml:"serviceRouter" json:"serviceRouter"`
 	CircuitBreaker *CircuitBreakerConfigImpl `yaml:"circuitBreaker" json:"circuitBreaker"`
+	// 负载均衡配置，仅type生效
+	Loadbalancer *LoadBalancerConfigImpl `yaml:"loadbalancer" json:"loadbalancer"`
+	// 获取该服务实例的API超时时间，请求未显式设置超时时间时生效
+	Timeout *time.Duration `yaml:"timeout" json:"timeout"`
+	// 到该服务的客户端连接数限制，由 http/grpc 集成在建立连接时执行，为空表示不限制
+	ConnectionLimit *ConnectionLimitConfig `yaml:"connectionLimit" json:"connectionLimit"`
+}
+
+// ConnectionLimitConfig 客户端到被调服务全部实例的连接数限制
+type ConnectionLimitConfig struct {
+	// MaxConnections 最大连接数
+	MaxConnections int `yaml:"maxConnections" json:"maxConnections"`
+	// MaxQueueSize 连接数已满时最多允许排队等待建立连接的请求数，为0则直接拒绝
+	MaxQueueSize int `yaml:"maxQueueSize" json:"maxQueueSize"`
+	// MaxWaitTime 排队等待建立连接的最长时间
+	MaxWaitTime *time.Duration `yaml:"maxWaitTime" json:"maxWaitTime"`
+}
+
+// Verify 检验连接数限制配置
+func (c *ConnectionLimitConfig) Verify() error {
+	if c.MaxConnections <= 0 {
+		return errors.New("connectionLimit.maxConnections must be greater than 0")
+	}
+	if c.MaxQueueSize < 0 {
+		return errors.New("connectionLimit.maxQueueSize can not be negative")
+	}
+	if c.MaxWaitTime != nil && *c.MaxWaitTime < 0 {
+		return errors.New("connectionLimit.maxWaitTime can not be negative")
+	}
+	return nil
+}
+
+// GetMaxWaitTime 获取排队等待建立连接的最长时间
+func (c *ConnectionLimitConfig) GetMaxWaitTime() time.Duration {
+	if c.MaxWaitTime == nil {
+		return 0
+	}
+	return *c.MaxWaitTime
 }
 
 // ServicesSpecificImpl .
@@ -36,6 +78,21 @@ type ServicesSpecificImpl struct {
 
 // Verify .验证
 func (s *ServiceSpecific) Verify() error {
+	if nil == s {
+		return errors.New("ServiceSpecific is nil")
+	}
+	if len(s.Namespace) == 0 || len(s.Service) == 0 {
+		return errors.New("consumer.servicesSpecific.namespace and service can not be empty")
+	}
+	if s.Timeout != nil && *s.Timeout < DefaultMinTimingInterval {
+		return fmt.Errorf("consumer.servicesSpecific[%s/%s].timeout %v is less than the minimal allowed duration %v",
+			s.Namespace, s.Service, *s.Timeout, DefaultMinTimingInterval)
+	}
+	if s.ConnectionLimit != nil {
+		if err := s.ConnectionLimit.Verify(); err != nil {
+			return fmt.Errorf("consumer.servicesSpecific[%s/%s].%v", s.Namespace, s.Service, err)
+		}
+	}
 	return nil
 }
 
@@ -45,6 +102,8 @@ func (s *ServiceSpecific) Init() {
 	s.ServiceRouter.Init()
 	s.CircuitBreaker = &CircuitBreakerConfigImpl{}
 	s.CircuitBreaker.Init()
+	s.Loadbalancer = &LoadBalancerConfigImpl{}
+	s.Loadbalancer.Init()
 }
 
 // SetDefault 设置默认
@@ -66,3 +125,50 @@ func (s *ServiceSpecific) GetServiceCircuitBreaker() CircuitBreakerConfig {
 func (s *ServiceSpecific) GetServiceRouter() ServiceRouterConfig {
 	return s.ServiceRouter
 }
+
+// GetNamespace 获取命名空间
+func (s *ServiceSpecific) GetNamespace() string {
+	return s.Namespace
+}
+
+// GetService 获取服务名
+func (s *ServiceSpecific) GetService() string {
+	return s.Service
+}
+
+// GetRouterChain 获取服务级的路由链，为空表示继承上级配置
+func (s *ServiceSpecific) GetRouterChain() []string {
+	if s.ServiceRouter == nil {
+		return nil
+	}
+	return s.ServiceRouter.Chain
+}
+
+// GetLbPolicy 获取服务级的负载均衡类型，为空表示继承上级配置
+func (s *ServiceSpecific) GetLbPolicy() string {
+	if s.Loadbalancer == nil {
+		return ""
+	}
+	return s.Loadbalancer.Type
+}
+
+// GetCircuitBreakerEnable 获取服务级的熔断开关，为nil表示继承上级配置
+func (s *ServiceSpecific) GetCircuitBreakerEnable() *bool {
+	if s.CircuitBreaker == nil {
+		return nil
+	}
+	return s.CircuitBreaker.Enable
+}
+
+// GetConnectionLimit 获取到该服务的客户端连接数限制，为nil表示不限制
+func (s *ServiceSpecific) GetConnectionLimit() *ConnectionLimitConfig {
+	return s.ConnectionLimit
+}
+
+// GetTimeout 获取服务级的API超时时间，为0表示继承全局配置
+func (s *ServiceSpecific) GetTimeout() time.Duration {
+	if s.Timeout == nil {
+		return 0
+	}
+	return *s.Timeout
+}
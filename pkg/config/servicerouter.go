@@ -20,6 +20,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 
@@ -38,6 +39,17 @@ type ServiceRouterConfigImpl struct {
 	PercentOfMinInstances *float64 `yaml:"percentOfMinInstances" json:"percentOfMinInstances"`
 	// 是否启用全死全活机制
 	EnableRecoverAll *bool `yaml:"enableRecoverAll" json:"enableRecoverAll"`
+	// 排空中实例承接已有会话的宽限期
+	DrainingGracePeriod *time.Duration `yaml:"drainingGracePeriod" json:"drainingGracePeriod"`
+	// 主调方自身标签，用于规则路由按主调方自身的元数据匹配
+	SelfLabels *SelfLabelsConfigImpl `yaml:"selfLabels" json:"selfLabels"`
+	// 是否并行执行路由链中相邻的独立路由插件
+	EnableParallel *bool `yaml:"enableParallel" json:"enableParallel"`
+}
+
+// GetSelfLabels 获取主调方自身标签配置.
+func (s *ServiceRouterConfigImpl) GetSelfLabels() SelfLabelsConfig {
+	return s.SelfLabels
 }
 
 // GetNearbyConfig 获取就近路由配置.
@@ -106,6 +118,26 @@ func (s *ServiceRouterConfigImpl) SetEnableRecoverAll(recoverAll bool) {
 	s.EnableRecoverAll = &recoverAll
 }
 
+// GetDrainingGracePeriod 获取排空中实例承接已有会话的宽限期.
+func (s *ServiceRouterConfigImpl) GetDrainingGracePeriod() time.Duration {
+	return *(s.DrainingGracePeriod)
+}
+
+// SetDrainingGracePeriod 设置排空中实例承接已有会话的宽限期.
+func (s *ServiceRouterConfigImpl) SetDrainingGracePeriod(period time.Duration) {
+	s.DrainingGracePeriod = &period
+}
+
+// IsEnableParallel 是否并行执行路由链中相邻的独立路由插件.
+func (s *ServiceRouterConfigImpl) IsEnableParallel() bool {
+	return *(s.EnableParallel)
+}
+
+// SetEnableParallel 设置是否并行执行路由链中相邻的独立路由插件.
+func (s *ServiceRouterConfigImpl) SetEnableParallel(parallel bool) {
+	s.EnableParallel = &parallel
+}
+
 // Verify 检验ServiceRouterConfig配置.
 func (s *ServiceRouterConfigImpl) Verify() error {
 	if s == nil {
@@ -115,6 +147,12 @@ func (s *ServiceRouterConfigImpl) Verify() error {
 	if *(s.PercentOfMinInstances) >= 1 || *(s.PercentOfMinInstances) < 0 {
 		errs = multierror.Append(errs, fmt.Errorf("consumer.servicerouter.percentOfMinInstances must be in range [0.0, 1.0)"))
 	}
+	if *(s.DrainingGracePeriod) < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.servicerouter.drainingGracePeriod must not be negative"))
+	}
+	if err := s.SelfLabels.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	plugErr := s.Plugin.Verify()
 	if plugErr != nil {
 		errs = multierror.Append(errs, plugErr)
@@ -139,6 +177,18 @@ func (s *ServiceRouterConfigImpl) SetDefault() {
 		s.EnableRecoverAll = new(bool)
 		*(s.EnableRecoverAll) = DefaultRecoverAllEnabled
 	}
+	if nil == s.DrainingGracePeriod {
+		s.DrainingGracePeriod = new(time.Duration)
+		*(s.DrainingGracePeriod) = DefaultDrainingGracePeriod
+	}
+	if nil == s.EnableParallel {
+		s.EnableParallel = new(bool)
+		*(s.EnableParallel) = DefaultServiceRouterParallelEnabled
+	}
+	if nil == s.SelfLabels {
+		s.SelfLabels = &SelfLabelsConfigImpl{}
+	}
+	s.SelfLabels.SetDefault()
 	s.Plugin.SetDefault(common.TypeServiceRouter)
 }
 
@@ -146,4 +196,5 @@ func (s *ServiceRouterConfigImpl) SetDefault() {
 func (s *ServiceRouterConfigImpl) Init() {
 	s.Plugin = PluginConfigs{}
 	s.Plugin.Init(common.TypeServiceRouter)
+	s.SelfLabels = &SelfLabelsConfigImpl{}
 }
@@ -0,0 +1,153 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// SidecarModeNever 不使用本机sidecar，直接访问远端服务端
+	SidecarModeNever = "never"
+	// SidecarModeAuto 启动时探测本机sidecar，探测成功则优先经由sidecar访问，远端服务端地址作为容灾地址
+	SidecarModeAuto = "auto"
+	// SidecarModeAlways 总是经由本机sidecar访问，不再直连远端服务端
+	SidecarModeAlways = "always"
+)
+
+// SidecarConfigImpl 本机polaris-sidecar配置，在同机部署大量进程时由sidecar代理服务发现及配置拉取，减少到服务端的连接数.
+type SidecarConfigImpl struct {
+	// 使用sidecar的模式，可选值：never、auto、always
+	Mode string `yaml:"mode" json:"mode"`
+	// sidecar服务发现接口的本机地址，格式为<host>:<port>
+	Address string `yaml:"address" json:"address"`
+	// sidecar配置中心接口的本机地址，格式为<host>:<port>
+	ConfigAddress string `yaml:"configAddress" json:"configAddress"`
+	// 自动模式下探测sidecar的超时时间
+	DetectTimeout *time.Duration `yaml:"detectTimeout" json:"detectTimeout"`
+}
+
+// GetMode 获取使用sidecar的模式.
+func (s *SidecarConfigImpl) GetMode() string {
+	return s.Mode
+}
+
+// SetMode 设置使用sidecar的模式.
+func (s *SidecarConfigImpl) SetMode(mode string) {
+	s.Mode = mode
+}
+
+// GetAddress 获取sidecar服务发现接口的地址.
+func (s *SidecarConfigImpl) GetAddress() string {
+	return s.Address
+}
+
+// SetAddress 设置sidecar服务发现接口的地址.
+func (s *SidecarConfigImpl) SetAddress(address string) {
+	s.Address = address
+}
+
+// GetConfigAddress 获取sidecar配置中心接口的地址.
+func (s *SidecarConfigImpl) GetConfigAddress() string {
+	return s.ConfigAddress
+}
+
+// SetConfigAddress 设置sidecar配置中心接口的地址.
+func (s *SidecarConfigImpl) SetConfigAddress(address string) {
+	s.ConfigAddress = address
+}
+
+// GetDetectTimeout 获取探测sidecar的超时时间.
+func (s *SidecarConfigImpl) GetDetectTimeout() time.Duration {
+	return *s.DetectTimeout
+}
+
+// SetDetectTimeout 设置探测sidecar的超时时间.
+func (s *SidecarConfigImpl) SetDetectTimeout(timeout time.Duration) {
+	s.DetectTimeout = &timeout
+}
+
+// Verify 检验sidecar配置.
+func (s *SidecarConfigImpl) Verify() error {
+	if nil == s {
+		return errors.New("SidecarConfig is nil")
+	}
+	var errs error
+	switch s.Mode {
+	case SidecarModeNever:
+		return nil
+	case SidecarModeAuto, SidecarModeAlways:
+	default:
+		return fmt.Errorf("global.sidecar.mode %s is invalid, must be one of never, auto, always", s.Mode)
+	}
+	if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		errs = multierror.Append(errs, errors.New("global.sidecar.address must be host:port"))
+	}
+	if _, _, err := net.SplitHostPort(s.ConfigAddress); err != nil {
+		errs = multierror.Append(errs, errors.New("global.sidecar.configAddress must be host:port"))
+	}
+	if s.GetDetectTimeout() <= 0 {
+		errs = multierror.Append(errs, errors.New("global.sidecar.detectTimeout must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置sidecar配置的默认值.
+func (s *SidecarConfigImpl) SetDefault() {
+	if len(s.Mode) == 0 {
+		s.Mode = DefaultSidecarMode
+	}
+	if len(s.Address) == 0 {
+		s.Address = DefaultSidecarAddress
+	}
+	if len(s.ConfigAddress) == 0 {
+		s.ConfigAddress = DefaultSidecarConfigAddress
+	}
+	if nil == s.DetectTimeout {
+		s.SetDetectTimeout(DefaultSidecarDetectTimeout)
+	}
+}
+
+// Init 初始化sidecar配置.
+func (s *SidecarConfigImpl) Init() {
+}
+
+// DelegateToSidecar 将sidecar地址作为最高优先级的服务端地址，
+// fallback为true时原有地址降低一级优先级作为容灾地址，否则只使用sidecar地址.
+func DelegateToSidecar(connector ServerConnectorConfig, sidecarAddress string, fallback bool) {
+	endpoints := []*ServerEndpointConfig{{Address: sidecarAddress, Weight: DefaultServerEndpointWeight}}
+	if fallback {
+		for _, endpoint := range MergeServerEndpoints(connector.GetAddresses(), connector.GetEndpoints()) {
+			if endpoint.Address == sidecarAddress {
+				continue
+			}
+			endpoints = append(endpoints, &ServerEndpointConfig{
+				Address:  endpoint.Address,
+				Priority: endpoint.Priority + 1,
+				Weight:   endpoint.Weight,
+			})
+		}
+	}
+	connector.SetAddresses(nil)
+	connector.SetEndpoints(endpoints)
+}
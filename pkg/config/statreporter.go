@@ -18,9 +18,20 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
+// costLabelKeyRegex 成本归属标签的key需要满足监控系统的标签命名规范
+var costLabelKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // StatReporterConfigImpl global.statReporter.
 type StatReporterConfigImpl struct {
 	// 是否启动上报
@@ -29,6 +40,10 @@ type StatReporterConfigImpl struct {
 	Chain []string `yaml:"chain" json:"chain"`
 	// 插件相关配置
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
+	// 成本归属标签
+	CostLabels map[string]string `yaml:"costLabels" json:"costLabels"`
+	// 主调服务标签配置
+	CallerLabels *CallerLabelsConfigImpl `yaml:"callerLabels" json:"callerLabels"`
 }
 
 // IsEnable 是否启用上报.
@@ -51,6 +66,21 @@ func (s *StatReporterConfigImpl) SetChain(chain []string) {
 	s.Chain = chain
 }
 
+// GetCostLabels 获取成本归属标签.
+func (s *StatReporterConfigImpl) GetCostLabels() map[string]string {
+	return s.CostLabels
+}
+
+// SetCostLabels 设置成本归属标签.
+func (s *StatReporterConfigImpl) SetCostLabels(labels map[string]string) {
+	s.CostLabels = labels
+}
+
+// GetCallerLabels 获取主调服务标签配置.
+func (s *StatReporterConfigImpl) GetCallerLabels() CallerLabelsConfig {
+	return s.CallerLabels
+}
+
 // GetPluginConfig 获取一个插件的配置.
 func (s *StatReporterConfigImpl) GetPluginConfig(name string) BaseConfig {
 	value, ok := s.Plugin[name]
@@ -62,7 +92,19 @@ func (s *StatReporterConfigImpl) GetPluginConfig(name string) BaseConfig {
 
 // Verify 检测statReporter配置.
 func (s *StatReporterConfigImpl) Verify() error {
-	return s.Plugin.Verify()
+	var errs error
+	for key := range s.CostLabels {
+		if !costLabelKeyRegex.MatchString(key) {
+			errs = multierror.Append(errs, fmt.Errorf("global.statReporter.costLabels: invalid label key %s", key))
+		}
+	}
+	if err := s.CallerLabels.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := s.Plugin.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
 }
 
 // SetDefault 设置statReporter默认值.
@@ -74,16 +116,102 @@ func (s *StatReporterConfigImpl) SetDefault() {
 	if len(s.Chain) == 0 {
 		s.Chain = []string{DefaultMetricsChain}
 	}
+	if envLabels := parseCostLabels(os.Getenv(CostLabelsEnv)); len(envLabels) > 0 {
+		if s.CostLabels == nil {
+			s.CostLabels = make(map[string]string, len(envLabels))
+		}
+		for k, v := range envLabels {
+			s.CostLabels[k] = v
+		}
+	}
+	if s.CallerLabels == nil {
+		s.CallerLabels = &CallerLabelsConfigImpl{}
+	}
+	s.CallerLabels.SetDefault()
 	s.Plugin.SetDefault(common.TypeStatReporter)
 }
 
+// parseCostLabels 解析 key1=value1,key2=value2 格式的标签
+func parseCostLabels(text string) map[string]string {
+	labels := make(map[string]string)
+	for _, item := range strings.Split(text, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 || len(strings.TrimSpace(kv[0])) == 0 {
+			continue
+		}
+		labels[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return labels
+}
+
 // Init 配置初始化.
 func (s *StatReporterConfigImpl) Init() {
 	s.Plugin = PluginConfigs{}
 	s.Plugin.Init(common.TypeStatReporter)
+	s.CallerLabels = &CallerLabelsConfigImpl{}
 }
 
 // SetPluginConfig 输出插件具体配置.
 func (s *StatReporterConfigImpl) SetPluginConfig(plugName string, value BaseConfig) error {
 	return s.Plugin.SetPluginConfig(common.TypeStatReporter, plugName, value)
 }
+
+// CallerLabelsConfigImpl global.statReporter.callerLabels
+// 服务调用指标中的主调服务标签会带来额外的时间序列，主调方数量较多时可以关闭.
+type CallerLabelsConfigImpl struct {
+	// 是否携带主调服务标签
+	Enable *bool `yaml:"enable" json:"enable"`
+	// 默认主调命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 默认主调服务名
+	Service string `yaml:"service" json:"service"`
+}
+
+// IsEnable 是否携带主调服务标签.
+func (c *CallerLabelsConfigImpl) IsEnable() bool {
+	return *c.Enable
+}
+
+// SetEnable 设置是否携带主调服务标签.
+func (c *CallerLabelsConfigImpl) SetEnable(enable bool) {
+	c.Enable = &enable
+}
+
+// GetNamespace 获取默认主调命名空间.
+func (c *CallerLabelsConfigImpl) GetNamespace() string {
+	return c.Namespace
+}
+
+// SetNamespace 设置默认主调命名空间.
+func (c *CallerLabelsConfigImpl) SetNamespace(namespace string) {
+	c.Namespace = namespace
+}
+
+// GetService 获取默认主调服务名.
+func (c *CallerLabelsConfigImpl) GetService() string {
+	return c.Service
+}
+
+// SetService 设置默认主调服务名.
+func (c *CallerLabelsConfigImpl) SetService(service string) {
+	c.Service = service
+}
+
+// Verify 检验主调服务标签配置.
+func (c *CallerLabelsConfigImpl) Verify() error {
+	if nil == c {
+		return errors.New("CallerLabelsConfig is nil")
+	}
+	if len(c.Service) > 0 && len(c.Namespace) == 0 {
+		return errors.New("global.statReporter.callerLabels.namespace is required when service is set")
+	}
+	return nil
+}
+
+// SetDefault 设置主调服务标签默认值.
+func (c *CallerLabelsConfigImpl) SetDefault() {
+	if nil == c.Enable {
+		enable := DefaultCallerLabelsEnabled
+		c.Enable = &enable
+	}
+}
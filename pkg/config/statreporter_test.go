@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// TestStatReporterCostLabels 测试环境变量中的成本归属标签覆盖配置文件，非法的标签key校验失败
+func TestStatReporterCostLabels(t *testing.T) {
+	if err := os.Setenv(CostLabelsEnv, " team = infra ,env=prod,broken, =empty"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(CostLabelsEnv)
+	cfg := &StatReporterConfigImpl{CostLabels: map[string]string{"team": "search", "product": "polaris"}}
+	cfg.Init()
+	cfg.SetDefault()
+	labels := cfg.GetCostLabels()
+	expect := map[string]string{"team": "infra", "env": "prod", "product": "polaris"}
+	if len(labels) != len(expect) {
+		t.Fatalf("expect labels %v, got %v", expect, labels)
+	}
+	for k, v := range expect {
+		if labels[k] != v {
+			t.Fatalf("expect label %s=%s, got %v", k, v, labels)
+		}
+	}
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.SetCostLabels(map[string]string{"cost-center": "a"})
+	if err := cfg.Verify(); err == nil {
+		t.Fatal("expect invalid label key rejected")
+	}
+}
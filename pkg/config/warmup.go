@@ -0,0 +1,60 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// WarmUpManifest 缓存预热清单
+type WarmUpManifest struct {
+	// Services 需要预热的服务列表
+	Services []*WarmUpService `yaml:"services" json:"services"`
+}
+
+// WarmUpService 需要预热的服务
+type WarmUpService struct {
+	Namespace string `yaml:"namespace" json:"namespace"`
+	Service   string `yaml:"service" json:"service"`
+}
+
+// LoadWarmUpManifest 从文件中加载缓存预热清单
+func LoadWarmUpManifest(path string) (*WarmUpManifest, error) {
+	if !model.IsFile(path) {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "invalid warm up manifest %s", path)
+	}
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to read warm up manifest %s", path)
+	}
+	manifest := &WarmUpManifest{}
+	if err = yaml.Unmarshal(buff, manifest); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to decode warm up manifest %s", path)
+	}
+	for _, svc := range manifest.Services {
+		if svc == nil || svc.Namespace == "" || svc.Service == "" {
+			return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, nil,
+				"namespace and service are required in warm up manifest %s", path)
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadWarmUpManifest 测试加载缓存预热清单，缺少命名空间或服务名时拒绝
+func TestLoadWarmUpManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "warmup.yaml")
+	content := "services:\n  - namespace: default\n    service: echo\n  - namespace: Test\n    service: order\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := LoadWarmUpManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Services) != 2 || manifest.Services[1].Namespace != "Test" ||
+		manifest.Services[1].Service != "order" {
+		t.Fatalf("unexpected manifest services %+v", manifest.Services)
+	}
+
+	if err = ioutil.WriteFile(path, []byte("services:\n  - namespace: default\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadWarmUpManifest(path); err == nil {
+		t.Fatal("expect error when service name is missing")
+	}
+	if _, err = LoadWarmUpManifest(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Fatal("expect error when manifest does not exist")
+	}
+}
@@ -0,0 +1,181 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// translatedInstance 地址转换后的实例，实例ID及四元组标识保持不变，熔断及调用统计仍然按注册地址聚合
+type translatedInstance struct {
+	model.Instance
+	host string
+	port uint32
+}
+
+// GetHost 转换后的地址
+func (t *translatedInstance) GetHost() string {
+	return t.host
+}
+
+// GetPort 转换后的端口
+func (t *translatedInstance) GetPort() uint32 {
+	return t.port
+}
+
+// DeepClone 复制实例并保留转换后的地址
+func (t *translatedInstance) DeepClone() model.Instance {
+	return &translatedInstance{Instance: t.Instance.DeepClone(), host: t.host, port: t.port}
+}
+
+// AddAddressTranslator 添加实例地址转换器，按添加顺序执行，第一个完成转换的生效，配置的转换规则最后执行
+func (e *Engine) AddAddressTranslator(translator model.AddressTranslator) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	translators := make([]model.AddressTranslator, 0, len(e.selectorHooks.translators)+1)
+	translators = append(translators, e.selectorHooks.translators...)
+	e.selectorHooks.translators = append(translators, translator)
+}
+
+func (e *Engine) getAddressTranslators() []model.AddressTranslator {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	if e.configAddressTranslator == nil {
+		return e.selectorHooks.translators
+	}
+	translators := make([]model.AddressTranslator, 0, len(e.selectorHooks.translators)+1)
+	translators = append(translators, e.selectorHooks.translators...)
+	return append(translators, e.configAddressTranslator)
+}
+
+// applyAddressTranslation 对返回给调用方的实例进行地址转换，转换结果写入新的实例列表，不影响缓存
+func (e *Engine) applyAddressTranslation(resp *model.InstancesResponse) {
+	if resp == nil || len(resp.Instances) == 0 {
+		return
+	}
+	translators := e.getAddressTranslators()
+	if len(translators) == 0 {
+		return
+	}
+	svcKey := model.ServiceKey{Namespace: resp.Namespace, Service: resp.Service}
+	var result []model.Instance
+	for i, instance := range resp.Instances {
+		translated := translateAddress(translators, svcKey, instance)
+		if translated == instance {
+			continue
+		}
+		if result == nil {
+			result = make([]model.Instance, len(resp.Instances))
+			copy(result, resp.Instances)
+		}
+		result[i] = translated
+	}
+	if result != nil {
+		resp.Instances = result
+	}
+}
+
+func translateAddress(translators []model.AddressTranslator, svcKey model.ServiceKey,
+	instance model.Instance) model.Instance {
+	for _, translator := range translators {
+		host, port, ok := translator(svcKey, instance)
+		if !ok {
+			continue
+		}
+		if host == instance.GetHost() && port == instance.GetPort() {
+			return instance
+		}
+		return &translatedInstance{Instance: instance, host: host, port: port}
+	}
+	return instance
+}
+
+// addressMapping 解析后的静态地址映射规则
+type addressMapping struct {
+	from   *net.IPNet
+	toHost string
+	toPort uint32
+}
+
+// newConfigAddressTranslator 根据配置创建地址转换器，未配置网络区域时返回nil
+func newConfigAddressTranslator(cfg config.AddressTranslationConfig) model.AddressTranslator {
+	if cfg == nil || len(cfg.GetNetwork()) == 0 {
+		return nil
+	}
+	metadataKey := cfg.GetMetadataKeyPrefix() + cfg.GetNetwork()
+	mappings := make([]*addressMapping, 0, len(cfg.GetMappings()))
+	for _, mappingCfg := range cfg.GetMappings() {
+		mapping := &addressMapping{}
+		if _, ipNet, err := net.ParseCIDR(mappingCfg.From); err == nil {
+			mapping.from = ipNet
+		} else {
+			ip := net.ParseIP(mappingCfg.From)
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			mapping.from = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		}
+		mapping.toHost, mapping.toPort = splitTranslatedAddress(mappingCfg.To)
+		mappings = append(mappings, mapping)
+	}
+	return func(svcKey model.ServiceKey, instance model.Instance) (string, uint32, bool) {
+		if address, ok := instance.GetMetadata()[metadataKey]; ok && len(address) > 0 {
+			host, port := splitTranslatedAddress(address)
+			if port == 0 {
+				port = instance.GetPort()
+			}
+			return host, port, true
+		}
+		ip := net.ParseIP(instance.GetHost())
+		if ip == nil {
+			return "", 0, false
+		}
+		for _, mapping := range mappings {
+			if !mapping.from.Contains(ip) {
+				continue
+			}
+			port := mapping.toPort
+			if port == 0 {
+				port = instance.GetPort()
+			}
+			return mapping.toHost, port, true
+		}
+		return "", 0, false
+	}
+}
+
+// splitTranslatedAddress 解析host或者host:port格式的地址，不带端口时端口为0
+func splitTranslatedAddress(address string) (string, uint32) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return address, 0
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[AddressTranslation] invalid port in translated address %s", address)
+		return host, 0
+	}
+	return host, uint32(port)
+}
@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type addressInstance struct {
+	metadataInstance
+	host string
+	port uint32
+}
+
+func (a *addressInstance) GetHost() string {
+	return a.host
+}
+
+func (a *addressInstance) GetPort() uint32 {
+	return a.port
+}
+
+// TestApplyAddressTranslation 测试元数据地址优先于静态映射，且不修改原实例列表
+func TestApplyAddressTranslation(t *testing.T) {
+	cfg := &config.AddressTranslationConfigImpl{
+		Network: "office",
+		Mappings: []*config.AddressMappingConfig{
+			{From: "10.0.0.0/8", To: "1.1.1.1"},
+			{From: "192.168.1.1", To: "2.2.2.2:9090"},
+		},
+	}
+	cfg.SetDefault()
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	engine := &Engine{configAddressTranslator: newConfigAddressTranslator(cfg)}
+	origin := []model.Instance{
+		&addressInstance{metadataInstance: metadataInstance{id: "a", metadata: map[string]string{
+			config.DefaultAddressTranslationMetadataKeyPrefix + "office": "3.3.3.3:7070"}},
+			host: "10.0.0.1", port: 8080},
+		&addressInstance{metadataInstance: metadataInstance{id: "b"}, host: "10.0.0.2", port: 8080},
+		&addressInstance{metadataInstance: metadataInstance{id: "c"}, host: "192.168.1.1", port: 8080},
+		&addressInstance{metadataInstance: metadataInstance{id: "d"}, host: "172.16.0.1", port: 8080},
+	}
+	resp := &model.InstancesResponse{Instances: origin}
+	engine.applyAddressTranslation(resp)
+	expects := []struct {
+		host string
+		port uint32
+	}{{"3.3.3.3", 7070}, {"1.1.1.1", 8080}, {"2.2.2.2", 9090}, {"172.16.0.1", 8080}}
+	for i, expect := range expects {
+		instance := resp.Instances[i]
+		if instance.GetHost() != expect.host || instance.GetPort() != expect.port {
+			t.Fatalf("instance %s expect %s:%d, got %s:%d", instance.GetId(), expect.host, expect.port,
+				instance.GetHost(), instance.GetPort())
+		}
+	}
+	if origin[0].GetHost() != "10.0.0.1" || resp.Instances[3] != origin[3] {
+		t.Fatal("origin instances should not be modified")
+	}
+	engine.AddAddressTranslator(func(svcKey model.ServiceKey, instance model.Instance) (string, uint32, bool) {
+		return "4.4.4.4", instance.GetPort(), instance.GetId() == "d"
+	})
+	resp = &model.InstancesResponse{Instances: origin}
+	engine.applyAddressTranslation(resp)
+	if resp.Instances[3].GetHost() != "4.4.4.4" {
+		t.Fatalf("custom translator should take effect, got %s", resp.Instances[3].GetHost())
+	}
+}
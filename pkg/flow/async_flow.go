@@ -26,6 +26,12 @@ import (
 
 // AsyncGetQuota 异步获取配额信息
 func (e *Engine) AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
+	if e.isDraining() {
+		return model.QuotaFutureWithResponse(&model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: "sdk context is draining",
+		}), nil
+	}
 	commonRequest := data.PoolGetCommonRateLimitRequest()
 	commonRequest.InitByGetQuotaRequest(request, e.configuration)
 	startTime := model.CurrentMillisecond()
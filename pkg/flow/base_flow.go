@@ -298,6 +298,7 @@ func (e *Engine) afterLazyGetInstances(
 	req *data.CommonInstancesRequest) (cls *model.Cluster, redirected *model.ServiceInfo, err model.SDKError) {
 	var result *servicerouter.RouteResult
 	req.RouteInfo.FilterOnlyRouter = e.finalRouterPlugin
+	req.RouteInfo.EnableParallel = e.configuration.GetConsumer().GetServiceRouter().IsEnableParallel()
 	// 服务路由
 	if !req.SkipRouteFilter {
 		result, err = e.getServiceRoutedInstances(req)
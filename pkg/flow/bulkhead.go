@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const matchAll = "*"
+
+// bulkhead 单个服务/接口的舱壁，通过信号量控制最大并发调用数
+type bulkhead struct {
+	namespace   string
+	service     string
+	method      string
+	permits     chan struct{}
+	maxQueue    int32
+	maxWaitTime time.Duration
+	// queued 当前排队等待的请求数
+	queued int32
+}
+
+func newBulkhead(resource model.Resource, method string, cfg *config.BulkheadConfig) *bulkhead {
+	return &bulkhead{
+		namespace:   resource.GetService().Namespace,
+		service:     resource.GetService().Service,
+		method:      method,
+		permits:     make(chan struct{}, cfg.MaxConcurrency),
+		maxQueue:    int32(cfg.MaxQueueSize),
+		maxWaitTime: cfg.GetMaxWaitTime(),
+	}
+}
+
+// acquire 获取并发许可，并发已满时在队列未满的情况下排队等待，直到超时
+func (b *bulkhead) acquire() model.BulkheadResult {
+	select {
+	case b.permits <- struct{}{}:
+		return model.BulkheadPassed
+	default:
+	}
+	if b.maxQueue <= 0 || b.maxWaitTime <= 0 {
+		return model.BulkheadRejected
+	}
+	if atomic.AddInt32(&b.queued, 1) > b.maxQueue {
+		atomic.AddInt32(&b.queued, -1)
+		return model.BulkheadRejected
+	}
+	defer atomic.AddInt32(&b.queued, -1)
+	timer := time.NewTimer(b.maxWaitTime)
+	defer timer.Stop()
+	select {
+	case b.permits <- struct{}{}:
+		return model.BulkheadQueued
+	case <-timer.C:
+		return model.BulkheadRejected
+	}
+}
+
+// release 归还并发许可
+func (b *bulkhead) release() {
+	select {
+	case <-b.permits:
+	default:
+	}
+}
+
+func (b *bulkhead) toGauge(result model.BulkheadResult) *model.BulkheadGauge {
+	return &model.BulkheadGauge{
+		Namespace:   b.namespace,
+		Service:     b.service,
+		Method:      b.method,
+		Result:      result,
+		Concurrency: len(b.permits),
+		QueueSize:   int(atomic.LoadInt32(&b.queued)),
+	}
+}
+
+// bulkheadManager 管理所有服务/接口的舱壁
+// 服务端的熔断/故障探测规则中没有并发限制的定义，因此舱壁只从本地熔断配置（consumer.circuitBreaker.bulkheads）中读取
+type bulkheadManager struct {
+	rules     []*config.BulkheadConfig
+	lock      sync.RWMutex
+	bulkheads map[string]*bulkhead
+}
+
+func newBulkheadManager(rules []*config.BulkheadConfig) *bulkheadManager {
+	return &bulkheadManager{
+		rules:     rules,
+		bulkheads: make(map[string]*bulkhead),
+	}
+}
+
+// getBulkhead 查找资源对应的舱壁，create为true时不存在则按照匹配的配置进行创建
+func (m *bulkheadManager) getBulkhead(resource model.Resource, create bool) *bulkhead {
+	if m == nil || len(m.rules) == 0 || resource == nil || resource.GetService() == nil {
+		return nil
+	}
+	var method string
+	switch res := resource.(type) {
+	case *model.ServiceResource:
+	case *model.MethodResource:
+		method = res.Method
+	default:
+		return nil
+	}
+	svcKey := resource.GetService()
+	key := svcKey.Namespace + "#" + svcKey.Service + "#" + method
+	m.lock.RLock()
+	b, ok := m.bulkheads[key]
+	m.lock.RUnlock()
+	if ok || !create {
+		return b
+	}
+	// 没有匹配配置的资源不做记录，避免调用方传入任意的服务/接口名导致缓存无限增长
+	rule := m.matchRule(svcKey, method)
+	if rule == nil {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if b, ok = m.bulkheads[key]; ok {
+		return b
+	}
+	b = newBulkhead(resource, method, rule)
+	m.bulkheads[key] = b
+	return b
+}
+
+// matchRule 按配置顺序匹配第一条生效的舱壁配置
+func (m *bulkheadManager) matchRule(svcKey *model.ServiceKey, method string) *config.BulkheadConfig {
+	for _, rule := range m.rules {
+		if rule == nil {
+			continue
+		}
+		if !matchValue(rule.Namespace, svcKey.Namespace) || !matchValue(rule.Service, svcKey.Service) {
+			continue
+		}
+		if method == "" {
+			if rule.Method == "" {
+				return rule
+			}
+			continue
+		}
+		if rule.Method == matchAll || rule.Method == method {
+			return rule
+		}
+	}
+	return nil
+}
+
+func matchValue(expect, actual string) bool {
+	return expect == "" || expect == matchAll || expect == actual
+}
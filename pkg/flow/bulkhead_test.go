@@ -0,0 +1,153 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestBulkheadAcquireAndRelease(t *testing.T) {
+	waitTime := 20 * time.Millisecond
+	manager := newBulkheadManager([]*config.BulkheadConfig{
+		{Service: "svc", Method: "*", MaxConcurrency: 1, MaxQueueSize: 1, MaxWaitTime: &waitTime},
+		{Service: "svc", MaxConcurrency: 2},
+	})
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "svc"}
+	svcRes, _ := model.NewServiceResource(svcKey, nil)
+	b := manager.getBulkhead(svcRes, true)
+	if b == nil {
+		t.Fatal("service bulkhead should be created")
+	}
+	if b.acquire() != model.BulkheadPassed || b.acquire() != model.BulkheadPassed {
+		t.Fatal("first two acquires should pass")
+	}
+	if b.acquire() != model.BulkheadRejected {
+		t.Fatal("acquire should be rejected without queue")
+	}
+	b.release()
+	if b.acquire() != model.BulkheadPassed {
+		t.Fatal("acquire should pass after release")
+	}
+
+	methodRes, _ := model.NewMethodResource(svcKey, nil, "echo")
+	mb := manager.getBulkhead(methodRes, true)
+	if mb == nil || mb == b {
+		t.Fatal("method bulkhead should be created separately")
+	}
+	if mb.acquire() != model.BulkheadPassed {
+		t.Fatal("first method acquire should pass")
+	}
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		mb.release()
+	}()
+	if ret := mb.acquire(); ret != model.BulkheadQueued {
+		t.Fatalf("queued acquire should pass after release, got %v", ret)
+	}
+	if ret := mb.acquire(); ret != model.BulkheadRejected {
+		t.Fatalf("queued acquire should timeout, got %v", ret)
+	}
+
+	otherRes, _ := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "other"}, nil)
+	if manager.getBulkhead(otherRes, true) != nil {
+		t.Fatal("unmatched service should have no bulkhead")
+	}
+	if len(manager.bulkheads) != 2 {
+		t.Fatalf("unmatched service should not be cached, got %d bulkheads", len(manager.bulkheads))
+	}
+}
+
+// TestBulkheadPermitReleasedByReport 测试只有携带Check占用许可的上报才会归还舱壁并发许可
+func TestBulkheadPermitReleasedByReport(t *testing.T) {
+	cbFlow := &CircuitBreakerFlow{
+		resourceBreaker: &stubBreaker{},
+		bulkheads:       newBulkheadManager([]*config.BulkheadConfig{{Service: "svc", MaxConcurrency: 1}}),
+	}
+	svcRes, _ := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "svc"}, nil)
+	result, err := cbFlow.Check(svcRes)
+	if err != nil || !result.Pass || result.Permit == nil {
+		t.Fatalf("first check should pass with permit, got %+v, %v", result, err)
+	}
+	// 未经过Check的上报（例如直接调用 CircuitBreakerAPI.Report）不能归还其他调用占用的许可
+	if err = cbFlow.Report(&model.ResourceStat{Resource: svcRes, RetStatus: model.RetSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	if rejected, _ := cbFlow.Check(svcRes); rejected.Pass {
+		t.Fatal("report without permit should not release the bulkhead")
+	}
+	stat := &model.ResourceStat{Resource: svcRes, RetStatus: model.RetSuccess, Permit: result.Permit}
+	if err = cbFlow.Report(stat); err != nil {
+		t.Fatal(err)
+	}
+	// 重复上报同一许可不会多归还
+	if err = cbFlow.Report(stat); err != nil {
+		t.Fatal(err)
+	}
+	next, _ := cbFlow.Check(svcRes)
+	if !next.Pass {
+		t.Fatal("check should pass after the permit is reported")
+	}
+	if again, _ := cbFlow.Check(svcRes); again.Pass {
+		t.Fatal("permit should be released only once")
+	}
+	next.Permit.Release()
+}
+
+// TestInvokeHandlerReleasesOwnPermits 测试装饰函数每次调用只归还本次调用占用的许可
+func TestInvokeHandlerReleasesOwnPermits(t *testing.T) {
+	cbFlow := &CircuitBreakerFlow{
+		resourceBreaker: &stubBreaker{},
+		bulkheads: newBulkheadManager([]*config.BulkheadConfig{
+			{Service: "svc", MaxConcurrency: 2},
+			{Service: "svc", Method: "echo", MaxConcurrency: 1},
+		}),
+	}
+	reqCtx := &model.RequestContext{Callee: &model.ServiceKey{Namespace: "default", Service: "svc"}, Method: "echo"}
+	inside := make(chan struct{})
+	block := make(chan struct{})
+	decorated := cbFlow.MakeFunctionDecorator(func(ctx context.Context, args interface{}) (interface{}, error) {
+		if args == "block" {
+			close(inside)
+			<-block
+		}
+		return nil, nil
+	}, reqCtx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = decorated(context.Background(), "block")
+	}()
+	<-inside
+	// 接口级并发已满，被拒绝的调用归还服务级许可，且不影响进行中的调用
+	if _, aborted, _ := decorated(context.Background(), "fast"); aborted == nil {
+		t.Fatal("call should be rejected by method bulkhead")
+	}
+	if _, aborted, _ := decorated(context.Background(), "fast"); aborted == nil {
+		t.Fatal("rejected call should not release permits of the in-flight call")
+	}
+	close(block)
+	<-done
+	if _, aborted, err := decorated(context.Background(), "fast"); aborted != nil || err != nil {
+		t.Fatalf("call should pass after in-flight call finishes, got %v, %v", aborted, err)
+	}
+}
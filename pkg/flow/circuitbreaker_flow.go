@@ -20,6 +20,7 @@ package flow
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -37,6 +38,19 @@ func (e *Engine) Report(reportStat *model.ResourceStat) error {
 	return e.circuitBreakerFlow.Report(reportStat)
 }
 
+// ReportExternalHealth 上报应用自行判定的实例健康状态
+func (e *Engine) ReportExternalHealth(report *model.ExternalHealthReport) error {
+	return e.circuitBreakerFlow.ReportExternalHealth(report)
+}
+
+// ClearQuarantine 清除熔断实例隔离名单
+func (e *Engine) ClearQuarantine(keys ...model.InstanceKey) int {
+	if e.circuitBreakerFlow == nil {
+		return 0
+	}
+	return e.circuitBreakerFlow.ClearQuarantine(keys...)
+}
+
 // MakeFunctionDecorator
 func (e *Engine) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *model.RequestContext) model.DecoratorFunction {
 	return e.circuitBreakerFlow.MakeFunctionDecorator(f, reqCtx)
@@ -50,13 +64,24 @@ func (e *Engine) MakeInvokeHandler(reqCtx *model.RequestContext) model.InvokeHan
 type CircuitBreakerFlow struct {
 	engine          *Engine
 	resourceBreaker circuitbreaker.CircuitBreaker
+	bulkheads       *bulkheadManager
+	// 应用上报的不健康实例，key为 model.InstanceKey
+	externalUnhealthy sync.Map
+	// 熔断实例隔离名单，未启用时为空
+	quarantine *instanceQuarantine
 }
 
 func newCircuitBreakerFlow(e *Engine, breaker circuitbreaker.CircuitBreaker) *CircuitBreakerFlow {
-	return &CircuitBreakerFlow{
+	cbFlow := &CircuitBreakerFlow{
 		engine:          e,
 		resourceBreaker: breaker,
+		bulkheads:       newBulkheadManager(e.configuration.GetConsumer().GetCircuitBreaker().GetBulkheads()),
+	}
+	if ttl := e.configuration.GetConsumer().GetCircuitBreaker().GetQuarantineTTL(); ttl > 0 {
+		cbFlow.quarantine = newInstanceQuarantine(
+			cbFlow, e.configuration.GetConsumer().GetLocalCache().GetPersistDir(), ttl)
 	}
+	return cbFlow
 }
 
 func (e *CircuitBreakerFlow) Check(resource model.Resource) (*model.CheckResult, error) {
@@ -64,16 +89,83 @@ func (e *CircuitBreakerFlow) Check(resource model.Resource) (*model.CheckResult,
 		return nil, model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
 	}
 
+	result := &model.CheckResult{
+		Pass:         true,
+		RuleName:     "",
+		FallbackInfo: nil,
+	}
+	if !e.isEnable(resource) {
+		return result, nil
+	}
+	if verdict, ok := e.loadExternalUnhealthy(resource); ok {
+		return circuitBreakerStatusToResult(verdict), nil
+	}
 	status := e.resourceBreaker.CheckResource(resource)
 	if status != nil {
-		return circuitBreakerStatusToResult(status), nil
+		result = circuitBreakerStatusToResult(status)
+		if !result.Pass {
+			return result, nil
+		}
+	}
+	// 熔断器放通后，再检查舱壁的并发限制，通过后占用一个并发许可，通过 CheckResult.Permit 归还
+	permit, ok := e.acquireBulkhead(resource)
+	if !ok {
+		return &model.CheckResult{
+			Pass:         false,
+			RuleName:     model.BulkheadRuleName,
+			FallbackInfo: nil,
+		}, nil
 	}
+	result.Permit = permit
+	return result, nil
+}
 
-	return &model.CheckResult{
-		Pass:         true,
-		RuleName:     "",
-		FallbackInfo: nil,
-	}, nil
+// isEnable 资源所属服务是否启用熔断
+func (e *CircuitBreakerFlow) isEnable(resource model.Resource) bool {
+	if e.engine == nil || resource == nil {
+		return true
+	}
+	svcKey := resource.GetService()
+	if svcKey == nil {
+		return true
+	}
+	return e.engine.isCircuitBreakerEnable(*svcKey)
+}
+
+// checkOpen 查询资源是否处于熔断打开状态，只查询状态，不占用舱壁并发许可
+func (e *CircuitBreakerFlow) checkOpen(resource model.Resource) (string, bool) {
+	if e.resourceBreaker == nil || !e.isEnable(resource) {
+		return "", false
+	}
+	if verdict, ok := e.loadExternalUnhealthy(resource); ok {
+		return verdict.GetCircuitBreaker(), verdict.GetStatus() == model.Open
+	}
+	status := e.resourceBreaker.CheckResource(resource)
+	if status == nil {
+		return "", false
+	}
+	return status.GetCircuitBreaker(), status.GetStatus() == model.Open
+}
+
+// acquireBulkhead 获取资源的舱壁并发许可，资源没有配置舱壁时直接放通并返回nil许可
+func (e *CircuitBreakerFlow) acquireBulkhead(resource model.Resource) (*model.BulkheadPermit, bool) {
+	b := e.bulkheads.getBulkhead(resource, true)
+	if b == nil {
+		return nil, true
+	}
+	result := b.acquire()
+	if result != model.BulkheadPassed {
+		if result == model.BulkheadRejected {
+			log.GetBaseLogger().Debugf("[CircuitBreaker] bulkhead of resource %s is saturated, reject", resource.String())
+		}
+		if e.engine != nil {
+			_ = e.engine.SyncReportStat(model.BulkheadStat, b.toGauge(result))
+		}
+	}
+	if result == model.BulkheadRejected {
+		return nil, false
+	}
+	return model.NewBulkheadPermit(b.release), true
 }
 
 func circuitBreakerStatusToResult(breakerStatus model.CircuitBreakerStatus) *model.CheckResult {
@@ -96,14 +188,19 @@ func (e *CircuitBreakerFlow) Report(reportStat *model.ResourceStat) error {
 	if e.resourceBreaker == nil {
 		return model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
 	}
+	// 只归还该次调用Check时占用的许可，没有携带许可的上报不影响舱壁
+	reportStat.Permit.Release()
+	if !e.isEnable(reportStat.Resource) {
+		return nil
+	}
 	return e.resourceBreaker.Report(reportStat)
 }
 
 func (e *CircuitBreakerFlow) MakeFunctionDecorator(f model.CustomerFunction, reqCtx *model.RequestContext) model.DecoratorFunction {
 	decorator := &DefaultFunctionalDecorator{
-		invoke: &DefaultInvokeHandler{
-			flow:   e,
-			reqCtx: reqCtx,
+		// 装饰后的函数可能被并发调用，每次调用使用独立的处理器记录本次调用占用的并发许可
+		newInvoke: func() model.InvokeHandler {
+			return e.MakeInvokeHandler(reqCtx)
 		},
 		customerFunc: f,
 	}
@@ -118,12 +215,12 @@ func (e *CircuitBreakerFlow) MakeInvokeHandler(reqCtx *model.RequestContext) mod
 }
 
 type DefaultFunctionalDecorator struct {
-	invoke       model.InvokeHandler
+	newInvoke    func() model.InvokeHandler
 	customerFunc model.CustomerFunction
 }
 
 func (df *DefaultFunctionalDecorator) Decorator(ctx context.Context, args interface{}) (interface{}, *model.CallAborted, error) {
-	invoke := df.invoke
+	invoke := df.newInvoke()
 	pass, aborted, err := invoke.AcquirePermission()
 	if err != nil {
 		return nil, nil, err
@@ -162,19 +259,54 @@ func (df *DefaultFunctionalDecorator) Decorator(ctx context.Context, args interf
 	return ret, nil, nil
 }
 
+// DefaultInvokeHandler 一次调用的熔断处理器，AcquirePermission 与 OnSuccess/OnError 需成对调用，不支持并发调用
 type DefaultInvokeHandler struct {
 	flow   *CircuitBreakerFlow
 	reqCtx *model.RequestContext
+	// 最近一次 AcquirePermission 占用的并发许可，在 OnSuccess/OnError 上报时归还
+	mutex   sync.Mutex
+	permits invokePermits
+}
+
+// invokePermits 一次调用在服务级及接口级占用的并发许可
+type invokePermits struct {
+	service *model.BulkheadPermit
+	method  *model.BulkheadPermit
+}
+
+func (p invokePermits) release() {
+	p.service.Release()
+	p.method.Release()
+}
+
+// takePermits 取出最近一次 AcquirePermission 占用的并发许可
+func (h *DefaultInvokeHandler) takePermits() invokePermits {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	permits := h.permits
+	h.permits = invokePermits{}
+	return permits
 }
 
 func (h *DefaultInvokeHandler) AcquirePermission() (bool, *model.CallAborted, error) {
-	check, err := h.commonCheck(h.reqCtx)
+	budget := h.reqCtx.Budget
+	if budget != nil && !budget.TryAcquire() {
+		return false, model.NewCallAborted(model.ErrRequestBudgetExhausted, "", nil), nil
+	}
+	check, permits, err := h.commonCheck(h.reqCtx)
 	if err != nil {
 		return true, nil, err
 	}
 	if check != nil {
+		// 被熔断拦截的调用没有实际发出，归还尝试预算
+		if budget != nil {
+			budget.Release()
+		}
 		return false, model.NewCallAborted(model.ErrorCallAborted, check.RuleName, check.FallbackInfo), nil
 	}
+	h.mutex.Lock()
+	h.permits = permits
+	h.mutex.Unlock()
 	return true, nil, nil
 }
 
@@ -205,36 +337,47 @@ func (h *DefaultInvokeHandler) OnError(respCtx *model.ResponseContext) {
 	}
 }
 
-func (h *DefaultInvokeHandler) commonCheck(reqCtx *model.RequestContext) (*model.CheckResult, error) {
+func (h *DefaultInvokeHandler) commonCheck(
+	reqCtx *model.RequestContext) (*model.CheckResult, invokePermits, error) {
+	var permits invokePermits
 	svcRes, err := model.NewServiceResource(reqCtx.Callee, reqCtx.Caller)
 	if err != nil {
-		return nil, err
+		return nil, permits, err
 	}
 	result, err := h.flow.Check(svcRes)
 	if err != nil {
-		return nil, err
+		return nil, permits, err
 	}
 	if !result.Pass {
-		return result, nil
+		return result, permits, nil
 	}
+	permits.service = result.Permit
 	if reqCtx.Method != "" {
 		methodSvc, err := model.NewMethodResource(reqCtx.Callee, reqCtx.Caller, reqCtx.Method)
 		if err != nil {
-			return nil, err
+			permits.release()
+			return nil, invokePermits{}, err
 		}
 		result, err := h.flow.Check(methodSvc)
 		if err != nil {
-			return nil, err
+			permits.release()
+			return nil, invokePermits{}, err
 		}
 		if !result.Pass {
-			return result, nil
+			// 接口级别被拒绝，归还服务级别已经占用的并发许可
+			permits.release()
+			return result, invokePermits{}, nil
 		}
+		permits.method = result.Permit
 	}
-	return nil, nil
+	return nil, permits, nil
 }
 
 func (h *DefaultInvokeHandler) commonReport(reqCtx *model.RequestContext, delay time.Duration, code string,
 	retStatus model.RetStatus) error {
+	permits := h.takePermits()
+	// 上报失败时同样归还并发许可
+	defer permits.release()
 	svcRes, err := model.NewServiceResource(reqCtx.Callee, reqCtx.Caller)
 	if err != nil {
 		return err
@@ -244,6 +387,7 @@ func (h *DefaultInvokeHandler) commonReport(reqCtx *model.RequestContext, delay
 		RetCode:   code,
 		Delay:     delay,
 		RetStatus: retStatus,
+		Permit:    permits.service,
 	}
 	if err := h.flow.Report(resourceStat); err != nil {
 		return err
@@ -258,6 +402,7 @@ func (h *DefaultInvokeHandler) commonReport(reqCtx *model.RequestContext, delay
 			RetCode:   code,
 			Delay:     delay,
 			RetStatus: retStatus,
+			Permit:    permits.method,
 		}
 		if err := h.flow.Report(resourceStat); err != nil {
 			return err
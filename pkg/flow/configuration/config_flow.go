@@ -20,6 +20,7 @@ package configuration
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 // ConfigFileFlow 配置中心核心服务门面类
@@ -48,6 +50,12 @@ type ConfigFileFlow struct {
 	conf      config.Configuration
 
 	persistHandler *CachePersistHandler
+	// 限制从服务端拉取配置文件的并发数
+	limiter *fetchLimiter
+	// 已订阅配置文件的变更观察者
+	changeObserver model.OnConfigFileChange
+	// 统计数据上报函数
+	statReporter StatReporter
 
 	startLongPollingTaskOnce sync.Once
 }
@@ -74,6 +82,7 @@ func NewConfigFileFlow(connector configconnector.ConfigConnector, chain configfi
 		configFilePool:  map[string]*ConfigFileRepo{},
 		notifiedVersion: map[string]uint64{},
 		persistHandler:  persistHandler,
+		limiter:         newFetchLimiter(conf.GetConfigFile().GetMaxConcurrentFetches()),
 	}
 
 	return configFileService, nil
@@ -86,6 +95,11 @@ func (c *ConfigFileFlow) Destroy() {
 	}
 }
 
+// SetChangeObserver 设置已订阅配置文件的变更观察者，需在获取配置文件之前设置
+func (c *ConfigFileFlow) SetChangeObserver(observer model.OnConfigFileChange) {
+	c.changeObserver = observer
+}
+
 // GetConfigFile 获取配置文件
 func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.ConfigFile, error) {
 	configFileMetadata := &model.DefaultConfigFileMetadata{
@@ -113,13 +127,16 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 		return configFile, nil
 	}
 
-	fileRepo, err := newConfigFileRepo(configFileMetadata, c.connector, c.chain, c.conf, c.persistHandler)
+	fileRepo, err := newConfigFileRepo(configFileMetadata, c.connector, c.chain, c.conf, c.persistHandler, c.limiter)
 	if err != nil {
 		return nil, err
 	}
 	configFile = newDefaultConfigFile(configFileMetadata, fileRepo)
 
 	if req.Subscribe {
+		if c.changeObserver != nil {
+			configFile.AddChangeListener(c.changeObserver)
+		}
 		c.addConfigFileToLongPollingPool(fileRepo)
 		c.repos = append(c.repos, fileRepo)
 		c.configFileCache[cacheKey] = configFile
@@ -128,12 +145,13 @@ func (c *ConfigFileFlow) GetConfigFile(req *model.GetConfigFileRequest) (model.C
 }
 
 // CreateConfigFile 创建配置文件
-func (c *ConfigFileFlow) CreateConfigFile(namespace, fileGroup, fileName, content string) error {
+func (c *ConfigFileFlow) CreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
 	// 校验参数
 	configFile := &configconnector.ConfigFile{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
+		Token:     buildOperationOptions(opts).Token,
 	}
 	configFile.SetContent(content)
 
@@ -163,12 +181,13 @@ func (c *ConfigFileFlow) CreateConfigFile(namespace, fileGroup, fileName, conten
 }
 
 // UpdateConfigFile 更新配置文件
-func (c *ConfigFileFlow) UpdateConfigFile(namespace, fileGroup, fileName, content string) error {
+func (c *ConfigFileFlow) UpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
 	// 校验参数
 	configFile := &configconnector.ConfigFile{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
+		Token:     buildOperationOptions(opts).Token,
 	}
 	configFile.SetContent(content)
 
@@ -198,12 +217,13 @@ func (c *ConfigFileFlow) UpdateConfigFile(namespace, fileGroup, fileName, conten
 }
 
 // PublishConfigFile 发布配置文件
-func (c *ConfigFileFlow) PublishConfigFile(namespace, fileGroup, fileName string) error {
+func (c *ConfigFileFlow) PublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error {
 	// 检验参数
 	configFile := &configconnector.ConfigFile{
 		Namespace: namespace,
 		FileGroup: fileGroup,
 		FileName:  fileName,
+		Token:     buildOperationOptions(opts).Token,
 	}
 
 	if err := model.CheckConfigFileMetadata(configFile); err != nil {
@@ -231,6 +251,15 @@ func (c *ConfigFileFlow) PublishConfigFile(namespace, fileGroup, fileName string
 	return nil
 }
 
+// buildOperationOptions 合并配置文件操作选项
+func buildOperationOptions(opts []model.ConfigFileOperationOption) *model.ConfigFileOperationOptions {
+	options := &model.ConfigFileOperationOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
 func (c *ConfigFileFlow) addConfigFileToLongPollingPool(fileRepo *ConfigFileRepo) {
 	configFileMetadata := fileRepo.configFileMetadata
 	version := fileRepo.getVersion()
@@ -239,6 +268,7 @@ func (c *ConfigFileFlow) addConfigFileToLongPollingPool(fileRepo *ConfigFileRepo
 		configFileMetadata, version)
 
 	cacheKey := genCacheKeyByMetadata(configFileMetadata)
+	fileRepo.statReporter = c.statReporter
 	c.configFilePool[cacheKey] = fileRepo
 	c.notifiedVersion[cacheKey] = version
 
@@ -246,10 +276,10 @@ func (c *ConfigFileFlow) addConfigFileToLongPollingPool(fileRepo *ConfigFileRepo
 	c.startLongPollingTaskOnce.Do(func() {
 		ctx, cancel := context.WithCancel(context.Background())
 		c.cancel = cancel
-		go func() {
+		profiling.Go("configuration", "longPolling", func() {
 			time.Sleep(5 * time.Second)
 			c.mainLoop(ctx)
-		}()
+		})
 	})
 }
 
@@ -300,6 +330,12 @@ func (c *ConfigFileFlow) startCheckVersionTask(ctx context.Context) {
 }
 
 func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
+	pollingRetryPolicy := retryPolicy{
+		delayMinTime: delayMinTime,
+		delayMaxTime: delayMaxTime,
+	}
+	// 订阅失败后，恢复订阅时以实际拉取到的版本号订阅，补齐断线期间错过的变更
+	resuming := false
 	for {
 		select {
 		case <-ctx.Done():
@@ -307,25 +343,30 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 		default:
 		}
 
-		pollingRetryPolicy := retryPolicy{
-			delayMinTime: delayMinTime,
-			delayMaxTime: delayMaxTime,
-		}
-
 		// 1. 生成订阅配置列表
-		watchConfigFiles := c.assembleWatchConfigFiles()
+		watchConfigFiles := c.assembleWatchConfigFiles(resuming)
 
-		log.GetBaseLogger().Infof("[Config] do long polling. config file size = %d, delay time = %d",
-			len(watchConfigFiles), pollingRetryPolicy.currentDelayTime)
+		log.GetBaseLogger().Infof("[Config] do long polling. config file size = %d, delay time = %d, resuming = %v",
+			len(watchConfigFiles), pollingRetryPolicy.currentDelayTime, resuming)
 
 		// 2. 调用 connector watch接口
+		watchStartTime := time.Now()
 		response, err := c.connector.WatchConfigFiles(watchConfigFiles)
+		c.reportWatch(response, err, watchStartTime)
 		if err != nil {
 			log.GetBaseLogger().Errorf("[Config] long polling failed.", err)
 			pollingRetryPolicy.fail()
 			pollingRetryPolicy.delay()
+			resuming = true
+			c.resubscribeDelay(ctx)
 			continue
 		}
+		if resuming && (response.GetCode() == uint32(apimodel.Code_ExecuteSuccess) ||
+			response.GetCode() == uint32(apimodel.Code_DataNoChange)) {
+			resuming = false
+			log.GetBaseLogger().Infof("[Config] long polling resumed from last known versions. config file size = %d",
+				len(watchConfigFiles))
+		}
 
 		responseCode := response.GetCode()
 
@@ -354,7 +395,7 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 
 			// 通知 remoteConfigFileRepo 拉取最新配置
 			remoteConfigFileRepo := c.getRemoteConfigFileRepo(cacheKey)
-			remoteConfigFileRepo.onLongPollingNotified(maxVersion)
+			c.onFileChangeNotified(ctx, cacheKey, remoteConfigFileRepo)
 
 			continue
 		}
@@ -370,22 +411,44 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 		log.GetBaseLogger().Errorf("[Config] long polling result with unexpect code. code = {}", responseCode)
 		pollingRetryPolicy.fail()
 		pollingRetryPolicy.delay()
+		resuming = true
+		c.resubscribeDelay(ctx)
+	}
+}
+
+// resubscribeDelay 订阅失败后随机延迟再重新订阅，避免服务端恢复时大量进程同时重新订阅
+func (c *ConfigFileFlow) resubscribeDelay(ctx context.Context) {
+	jitter := c.conf.GetConfigFile().GetResubscribeJitter()
+	if jitter <= 0 {
+		return
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(jitter))))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
 	}
 }
 
-func (c *ConfigFileFlow) assembleWatchConfigFiles() []*configconnector.ConfigFile {
+// assembleWatchConfigFiles 生成订阅配置列表，恢复订阅时使用通知版本号与实际拉取版本号中较小的一个，
+// 断线期间收到通知但拉取失败的配置可以重新收到变更
+func (c *ConfigFileFlow) assembleWatchConfigFiles(resuming bool) []*configconnector.ConfigFile {
 	c.fclock.RLock()
 	defer c.fclock.RUnlock()
 	watchConfigFiles := make([]*configconnector.ConfigFile, 0, len(c.configFilePool))
 
-	for cacheKey := range c.configFilePool {
+	for cacheKey, repo := range c.configFilePool {
 		configFileMetadata := extractConfigFileMetadata(cacheKey)
+		version := c.getConfigFileNotifiedVersion(cacheKey, false)
+		if pulled := repo.getVersion(); resuming && pulled < version {
+			version = pulled
+		}
 
 		watchConfigFiles = append(watchConfigFiles, &configconnector.ConfigFile{
 			Namespace: configFileMetadata.GetNamespace(),
 			FileGroup: configFileMetadata.GetFileGroup(),
 			FileName:  configFileMetadata.GetFileName(),
-			Version:   c.getConfigFileNotifiedVersion(cacheKey, false),
+			Version:   version,
 		})
 	}
 
@@ -0,0 +1,103 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// batchFetchConcurrency 批量获取配置文件时的最大并发拉取数
+const batchFetchConcurrency = 8
+
+// GetConfigFiles 批量获取同一分组下的多个配置文件并全部订阅，返回文件名到配置文件的映射。
+// 服务端暂无批量拉取接口，未缓存的文件并发拉取，拉取期间不持有缓存锁；
+// 部分文件获取失败时，返回获取成功的文件以及汇总的错误
+func (c *ConfigFileFlow) GetConfigFiles(req *model.GetConfigFilesRequest) (map[string]model.ConfigFile, error) {
+	result := make(map[string]model.ConfigFile, len(req.FileNames))
+	missing := make([]*model.DefaultConfigFileMetadata, 0, len(req.FileNames))
+	c.fclock.RLock()
+	for _, fileName := range req.FileNames {
+		if _, ok := result[fileName]; ok {
+			continue
+		}
+		metadata := &model.DefaultConfigFileMetadata{
+			Namespace: req.Namespace,
+			FileGroup: req.FileGroup,
+			FileName:  fileName,
+			Mode:      req.Mode,
+		}
+		if configFile, ok := c.configFileCache[genCacheKeyByMetadata(metadata)]; ok {
+			result[fileName] = configFile
+			continue
+		}
+		// 占位用于去重，拉取完成后替换
+		result[fileName] = nil
+		missing = append(missing, metadata)
+	}
+	c.fclock.RUnlock()
+
+	repos := make([]*ConfigFileRepo, len(missing))
+	errs := make([]error, len(missing))
+	wg := &sync.WaitGroup{}
+	tokens := make(chan struct{}, batchFetchConcurrency)
+	for i := range missing {
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			repos[i], errs[i] = newConfigFileRepo(missing[i], c.connector, c.chain, c.conf, c.persistHandler,
+				c.limiter)
+		}(i)
+	}
+	wg.Wait()
+
+	var fetchErrs error
+	c.fclock.Lock()
+	defer c.fclock.Unlock()
+	for i, metadata := range missing {
+		if errs[i] != nil {
+			delete(result, metadata.FileName)
+			fetchErrs = multierror.Append(fetchErrs, fmt.Errorf("fail to get config file %s: %w",
+				metadata.FileName, errs[i]))
+			continue
+		}
+		cacheKey := genCacheKeyByMetadata(metadata)
+		// 并发获取期间其他调用可能已经订阅了该文件
+		if configFile, ok := c.configFileCache[cacheKey]; ok {
+			result[metadata.FileName] = configFile
+			continue
+		}
+		configFile := newDefaultConfigFile(metadata, repos[i])
+		if c.changeObserver != nil {
+			configFile.AddChangeListener(c.changeObserver)
+		}
+		c.addConfigFileToLongPollingPool(repos[i])
+		c.repos = append(c.repos, repos[i])
+		c.configFileCache[cacheKey] = configFile
+		result[metadata.FileName] = configFile
+	}
+	return result, fetchErrs
+}
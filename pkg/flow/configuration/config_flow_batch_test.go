@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"sync/atomic"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+type countingConfigConnector struct {
+	configconnector.ConfigConnector
+	calls int32
+}
+
+func (c *countingConfigConnector) GetConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	atomic.AddInt32(&c.calls, 1)
+	file := &configconnector.ConfigFile{Namespace: configFile.Namespace, FileGroup: configFile.FileGroup,
+		FileName: configFile.FileName, Version: 1}
+	file.SetContent(configFile.FileName)
+	return &configconnector.ConfigFileResponse{Code: uint32(apimodel.Code_ExecuteSuccess), ConfigFile: file}, nil
+}
+
+func TestGetConfigFiles(t *testing.T) {
+	conf := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	conf.GetConfigFile().GetLocalCache().SetPersistDir(t.TempDir())
+	connector := &countingConfigConnector{}
+	flow, err := NewConfigFileFlow(connector, nil, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer flow.Destroy()
+
+	if _, err := flow.GetConfigFile(&model.GetConfigFileRequest{Namespace: "default", FileGroup: "group",
+		FileName: "a.yaml", Subscribe: true}); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"a.yaml", "b.yaml", "c.yaml", "b.yaml"}
+	files, err := flow.GetConfigFiles(&model.GetConfigFilesRequest{Namespace: "default", FileGroup: "group",
+		FileNames: names})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expect 3 files, got %d", len(files))
+	}
+	for name, file := range files {
+		if file.GetContent() != name {
+			t.Fatalf("unexpected content %s of file %s", file.GetContent(), name)
+		}
+	}
+	// 已缓存及重复的文件不会重复拉取
+	if calls := atomic.LoadInt32(&connector.calls); calls != 3 {
+		t.Fatalf("expect 3 pulls, got %d", calls)
+	}
+	if len(flow.configFilePool) != 3 {
+		t.Fatalf("expect all files subscribed, got %d", len(flow.configFilePool))
+	}
+}
@@ -0,0 +1,77 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// fetchLimiter 限制从服务端拉取配置文件的并发数，nil 表示不限制
+type fetchLimiter struct {
+	tokens chan struct{}
+}
+
+func newFetchLimiter(maxFetches int) *fetchLimiter {
+	if maxFetches <= 0 {
+		return nil
+	}
+	return &fetchLimiter{tokens: make(chan struct{}, maxFetches)}
+}
+
+func (l *fetchLimiter) acquire() {
+	if l == nil {
+		return
+	}
+	l.tokens <- struct{}{}
+}
+
+func (l *fetchLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.tokens
+}
+
+// onFileChangeNotified 收到配置变更通知后拉取配置，设置了随机延迟时异步延迟拉取，
+// 大量配置同时发布时打散拉取请求，避免所有客户端同时拉取
+func (c *ConfigFileFlow) onFileChangeNotified(ctx context.Context, cacheKey string, fileRepo *ConfigFileRepo) {
+	jitter := c.conf.GetConfigFile().GetFetchJitter()
+	if jitter <= 0 {
+		fileRepo.onLongPollingNotified(c.getConfigFileNotifiedVersion(cacheKey, true))
+		return
+	}
+	// 已有待执行的拉取时直接复用，拉取时使用最新的通知版本号
+	if !atomic.CompareAndSwapUint32(&fileRepo.fetchScheduled, 0, 1) {
+		return
+	}
+	delay := time.Duration(rand.Int63n(int64(jitter)))
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		atomic.StoreUint32(&fileRepo.fetchScheduled, 0)
+		fileRepo.onLongPollingNotified(c.getConfigFileNotifiedVersion(cacheKey, true))
+	}()
+}
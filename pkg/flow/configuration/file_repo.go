@@ -62,6 +62,14 @@ type ConfigFileRepo struct {
 	persistHandler *CachePersistHandler
 
 	fallbackToLocalCache bool
+	// 是否已经向监听器通知过配置，1表示已通知
+	delivered uint32
+	// 统计数据上报函数，订阅后设置
+	statReporter StatReporter
+	// 限制从服务端拉取配置文件的并发数
+	limiter *fetchLimiter
+	// 是否已有延迟执行的变更拉取，1表示已有
+	fetchScheduled uint32
 }
 
 // ConfigFileRepoChangeListener 远程配置文件发布监听器
@@ -72,7 +80,8 @@ func newConfigFileRepo(metadata model.ConfigFileMetadata,
 	connector configconnector.ConfigConnector,
 	chain configfilter.Chain,
 	conf config.Configuration,
-	persistHandler *CachePersistHandler) (*ConfigFileRepo, error) {
+	persistHandler *CachePersistHandler,
+	limiter *fetchLimiter) (*ConfigFileRepo, error) {
 	repo := &ConfigFileRepo{
 		connector:          connector,
 		chain:              chain,
@@ -85,6 +94,7 @@ func newConfigFileRepo(metadata model.ConfigFileMetadata,
 		},
 		remoteConfigFileRef:  &atomic.Value{},
 		persistHandler:       persistHandler,
+		limiter:              limiter,
 		fallbackToLocalCache: conf.GetConfigFile().GetLocalCache().IsFallbackToLocalCache(),
 	}
 	repo.remoteConfigFileRef.Store(&configconnector.ConfigFile{
@@ -170,7 +180,9 @@ func (r *ConfigFileRepo) pull() error {
 	for retryTimes < 3 {
 		startTime := time.Now()
 
+		r.limiter.acquire()
 		response, err := r.chain.Execute(pullConfigFileReq, r.connector.GetConfigFile)
+		r.limiter.release()
 
 		if err != nil {
 			log.GetBaseLogger().Errorf("[Config] failed to pull config file. retry times = %d, err = %v", retryTimes, err)
@@ -311,9 +323,26 @@ func (r *ConfigFileRepo) onLongPollingNotified(newVersion uint64) {
 	r.notifiedVersion = newVersion
 	if err := r.pull(); err != nil {
 		log.GetBaseLogger().Errorf("[Config] pull config file error by check version task.", zap.Error(err))
+		return
+	}
+	// 监听器在拉取时同步回调，拉取到新版本即说明变更已传播到监听器
+	if pulled := r.loadRemoteFile(); pulled != nil && pulled != remoteConfigFile && pulled.GetVersion() >= newVersion {
+		reportPropagation(r.statReporter, pulled, time.Now())
 	}
 }
 
+// isDelivered 判断配置是否与最近一次通知监听器的版本及内容一致
+func (r *ConfigFileRepo) isDelivered(f *configconnector.ConfigFile) bool {
+	if atomic.LoadUint32(&r.delivered) == 0 {
+		return false
+	}
+	prev := r.loadRemoteFile()
+	if f.NotExist {
+		return prev == nil
+	}
+	return prev != nil && prev.GetVersion() == f.GetVersion() && prev.GetContent() == f.GetContent()
+}
+
 // AddChangeListener 添加配置文件变更监听器
 func (r *ConfigFileRepo) AddChangeListener(listener ConfigFileRepoChangeListener) {
 	r.listeners = append(r.listeners, listener)
@@ -323,6 +352,13 @@ func (r *ConfigFileRepo) fireChangeEvent(f *configconnector.ConfigFile) {
 	if f.GetContent() == "" {
 		f.SetContent(f.GetSourceContent())
 	}
+	if r.isDelivered(f) {
+		// 重连后重复拉取或者回退到本地缓存时，内容未变化则不重复通知监听器
+		log.GetBaseLogger().Debugf("[Config] skip notifying unchanged config file. file = %+v, version = %d",
+			r.configFileMetadata, f.GetVersion())
+		return
+	}
+	atomic.StoreUint32(&r.delivered, 1)
 	if f.NotExist {
 		r.remoteConfigFileRef = &atomic.Value{}
 	} else {
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+func TestConfigFileRepoResume(t *testing.T) {
+	metadata := &model.DefaultConfigFileMetadata{Namespace: "default", FileGroup: "group", FileName: "app.yaml"}
+	repo := &ConfigFileRepo{configFileMetadata: metadata, remoteConfigFileRef: &atomic.Value{}}
+	var notified []string
+	repo.AddChangeListener(func(_ model.ConfigFileMetadata, content string, _ model.Persistent) error {
+		notified = append(notified, content)
+		return nil
+	})
+	newFile := func(version uint64, content string) *configconnector.ConfigFile {
+		file := &configconnector.ConfigFile{Namespace: "default", FileGroup: "group", FileName: "app.yaml",
+			Version: version}
+		file.SetContent(content)
+		return file
+	}
+
+	// 重连后重复拉取到相同版本及内容时不重复通知
+	repo.fireChangeEvent(newFile(1, "v1"))
+	repo.fireChangeEvent(newFile(1, "v1"))
+	repo.fireChangeEvent(newFile(2, "v2"))
+	if len(notified) != 2 || notified[1] != "v2" {
+		t.Fatalf("expect only changed content notified, got %v", notified)
+	}
+	repo.fireChangeEvent(&configconnector.ConfigFile{NotExist: true, SourceContent: NotExistedFileContent})
+	repo.fireChangeEvent(&configconnector.ConfigFile{NotExist: true, SourceContent: NotExistedFileContent})
+	if len(notified) != 3 {
+		t.Fatalf("expect deletion notified once, got %v", notified)
+	}
+
+	// 恢复订阅时使用实际拉取到的版本号，补齐断线期间拉取失败的变更
+	repo.fireChangeEvent(newFile(3, "v3"))
+	flow := &ConfigFileFlow{configFilePool: map[string]*ConfigFileRepo{}, notifiedVersion: map[string]uint64{}}
+	cacheKey := genCacheKeyByMetadata(metadata)
+	flow.configFilePool[cacheKey] = repo
+	flow.notifiedVersion[cacheKey] = 5
+	if files := flow.assembleWatchConfigFiles(false); files[0].Version != 5 {
+		t.Fatalf("expect notified version watched, got %d", files[0].Version)
+	}
+	if files := flow.assembleWatchConfigFiles(true); files[0].Version != 3 {
+		t.Fatalf("expect pulled version watched when resuming, got %d", files[0].Version)
+	}
+}
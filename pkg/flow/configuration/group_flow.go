@@ -26,6 +26,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 type ConfigGroupFlow struct {
@@ -50,7 +51,9 @@ func newConfigGroupFlow(connector configconnector.ConfigConnector, configuration
 		groupCache:    map[string]model.ConfigFileGroup{},
 	}
 
-	go groupFlow.doSync(ctx)
+	profiling.Go("configuration", "syncConfigGroup", func() {
+		groupFlow.doSync(ctx)
+	})
 	return groupFlow, nil
 }
 
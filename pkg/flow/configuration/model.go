@@ -35,8 +35,16 @@ type defaultConfigFile struct {
 	lock                sync.RWMutex
 	changeListeners     []func(event model.ConfigFileChangeEvent)
 	changeListenerChans []chan model.ConfigFileChangeEvent
+	// 最近的变更事件，用于给后添加的监听器回放
+	history []model.ConfigFileChangeEvent
+	// 安全发布选项及最近一次通过校验的变更，用于回滚
+	safeApply   *model.SafeApplyOptions
+	lastApplied *appliedChange
 }
 
+// MaxChangeHistory 每个配置文件在内存中保留的最近变更事件数量
+const MaxChangeHistory = 16
+
 func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo) *defaultConfigFile {
 	configFile := &defaultConfigFile{
 		fileRepo:   repo,
@@ -107,28 +115,75 @@ func (c *defaultConfigFile) repoChangeListener(configFileMetadata model.ConfigFi
 	}
 	c.content = newContent
 
+	if changeType != model.NotChanged && !c.validateChange(oldContent, event) {
+		return nil
+	}
 	c.fireChangeEvent(event)
 	return nil
 }
 
 // AddChangeListenerWithChannel 增加配置文件变更监听器
-func (c *defaultConfigFile) AddChangeListenerWithChannel() <-chan model.ConfigFileChangeEvent {
+func (c *defaultConfigFile) AddChangeListenerWithChannel(
+	opts ...model.ChangeListenerOption) <-chan model.ConfigFileChangeEvent {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	changeChan := make(chan model.ConfigFileChangeEvent, 64)
+	for _, event := range c.replayEvents(opts) {
+		changeChan <- event
+	}
 	c.changeListenerChans = append(c.changeListenerChans, changeChan)
 	return changeChan
 }
 
-// AddChangeListener 增加配置文件变更监听器
-func (c *defaultConfigFile) AddChangeListener(cb model.OnConfigFileChange) {
+// AddChangeListener 增加配置文件变更监听器，回放事件在持有锁时同步回调，回调中不可再添加监听器
+func (c *defaultConfigFile) AddChangeListener(cb model.OnConfigFileChange, opts ...model.ChangeListenerOption) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	for _, event := range c.replayEvents(opts) {
+		cb(event)
+	}
 	c.changeListeners = append(c.changeListeners, cb)
 }
 
+// replayEvents 计算需要回放的事件，调用方需持有锁
+func (c *defaultConfigFile) replayEvents(opts []model.ChangeListenerOption) []model.ConfigFileChangeEvent {
+	options := &model.ChangeListenerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	var events []model.ConfigFileChangeEvent
+	if options.ReplayHistory > 0 {
+		history := c.history
+		if len(history) > options.ReplayHistory {
+			history = history[len(history)-options.ReplayHistory:]
+		}
+		for _, event := range history {
+			event.Replayed = true
+			events = append(events, event)
+		}
+	}
+	if options.ReplayCurrent {
+		events = append(events, model.ConfigFileChangeEvent{
+			ConfigFileMetadata: &c.DefaultConfigFileMetadata,
+			OldValue:           c.GetContent(),
+			NewValue:           c.GetContent(),
+			ChangeType:         model.NotChanged,
+			Persistent:         c.persistent,
+			Replayed:           true,
+		})
+	}
+	return events
+}
+
 func (c *defaultConfigFile) fireChangeEvent(event model.ConfigFileChangeEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.history = append(c.history, event)
+	if len(c.history) > MaxChangeHistory {
+		c.history = c.history[len(c.history)-MaxChangeHistory:]
+	}
 	for _, listenerChan := range c.changeListenerChans {
 		listenerChan <- event
 	}
@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestConfigFileListenerReplay(t *testing.T) {
+	file := &defaultConfigFile{content: NotExistedFileContent}
+	for _, content := range []string{"v1", "v2", "v3"} {
+		if err := file.repoChangeListener(&file.DefaultConfigFileMetadata, content, model.Persistent{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var events []model.ConfigFileChangeEvent
+	file.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		events = append(events, event)
+	}, model.WithReplayHistory(2), model.WithReplayCurrent())
+	if len(events) != 3 {
+		t.Fatalf("expect 3 replayed events, got %d", len(events))
+	}
+	if events[0].NewValue != "v2" || events[1].NewValue != "v3" || !events[0].Replayed {
+		t.Fatalf("unexpected history replay %+v", events[:2])
+	}
+	if events[2].ChangeType != model.NotChanged || events[2].NewValue != "v3" {
+		t.Fatalf("unexpected current replay %+v", events[2])
+	}
+
+	changeChan := file.AddChangeListenerWithChannel(model.WithReplayCurrent())
+	if err := file.repoChangeListener(&file.DefaultConfigFileMetadata, "v4", model.Persistent{}); err != nil {
+		t.Fatal(err)
+	}
+	if event := <-changeChan; !event.Replayed || event.NewValue != "v3" {
+		t.Fatalf("unexpected current replay %+v", event)
+	}
+	if event := <-changeChan; event.Replayed || event.NewValue != "v4" || event.ChangeType != model.Modified {
+		t.Fatalf("unexpected change event %+v", event)
+	}
+	if len(events) != 4 || events[3].Replayed {
+		t.Fatalf("expect live event delivered after replay, got %d events", len(events))
+	}
+}
+
+func TestConfigFileSafeApply(t *testing.T) {
+	file := &defaultConfigFile{content: "v1"}
+	file.EnableSafeApply(model.SafeApplyOptions{
+		Validator: func(event model.ConfigFileChangeEvent) error {
+			if file.GetContent() != event.NewValue {
+				t.Fatalf("change should be applied before validate")
+			}
+			if event.NewValue == "bad" {
+				return errors.New("invalid content")
+			}
+			return nil
+		},
+		RollbackWindow: time.Minute,
+	})
+	var events []model.ConfigFileChangeEvent
+	file.AddChangeListener(func(event model.ConfigFileChangeEvent) {
+		events = append(events, event)
+	})
+
+	if err := file.repoChangeListener(&file.DefaultConfigFileMetadata, "bad", model.Persistent{}); err != nil {
+		t.Fatal(err)
+	}
+	if file.GetContent() != "v1" || len(events) != 1 || events[0].ChangeType != model.RolledBack ||
+		events[0].NewValue != "v1" || events[0].RollbackReason != "invalid content" {
+		t.Fatalf("expect rollback on validate failure, content %s, events %+v", file.GetContent(), events)
+	}
+	if err := file.Rollback("manual"); err == nil {
+		t.Fatal("expect no change to rollback")
+	}
+
+	if err := file.repoChangeListener(&file.DefaultConfigFileMetadata, "v2", model.Persistent{}); err != nil {
+		t.Fatal(err)
+	}
+	if file.GetContent() != "v2" || len(events) != 2 || events[1].ChangeType != model.Modified {
+		t.Fatalf("expect change applied, events %+v", events)
+	}
+	if err := file.Rollback("manual"); err != nil {
+		t.Fatal(err)
+	}
+	if file.GetContent() != "v1" || len(events) != 3 || events[2].ChangeType != model.RolledBack ||
+		events[2].OldValue != "v2" || events[2].RollbackReason != "manual" {
+		t.Fatalf("expect manual rollback, events %+v", events)
+	}
+}
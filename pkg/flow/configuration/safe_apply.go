@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// appliedChange 最近一次通过校验的变更
+type appliedChange struct {
+	previousContent string
+	appliedTime     time.Time
+}
+
+// EnableSafeApply 开启安全发布
+func (c *defaultConfigFile) EnableSafeApply(options model.SafeApplyOptions) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.safeApply = &options
+	c.lastApplied = nil
+}
+
+// validateChange 变更已应用到内存后调用校验回调，校验失败则回退并发出回滚事件，返回变更是否生效
+func (c *defaultConfigFile) validateChange(oldContent string, event model.ConfigFileChangeEvent) bool {
+	c.lock.RLock()
+	safeApply := c.safeApply
+	c.lock.RUnlock()
+	if safeApply == nil {
+		return true
+	}
+	if safeApply.Validator != nil {
+		if err := safeApply.Validator(event); err != nil {
+			log.GetBaseLogger().Errorf("[Config] validate change failed, rollback. file = %+v, err = %v",
+				event.ConfigFileMetadata, err)
+			c.content = oldContent
+			c.fireChangeEvent(c.buildRollbackEvent(event.NewValue, oldContent, event.Persistent, err.Error()))
+			return false
+		}
+	}
+	c.lock.Lock()
+	c.lastApplied = &appliedChange{previousContent: oldContent, appliedTime: time.Now()}
+	c.lock.Unlock()
+	return true
+}
+
+// Rollback 在回滚窗口内将内存中的值回退到上一个版本
+func (c *defaultConfigFile) Rollback(reason string) error {
+	c.lock.Lock()
+	if c.safeApply == nil || c.safeApply.RollbackWindow <= 0 {
+		c.lock.Unlock()
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "safe apply rollback not enabled")
+	}
+	applied := c.lastApplied
+	if applied == nil || time.Since(applied.appliedTime) > c.safeApply.RollbackWindow {
+		c.lock.Unlock()
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"no change to rollback within window %v", c.safeApply.RollbackWindow)
+	}
+	c.lastApplied = nil
+	c.lock.Unlock()
+
+	log.GetBaseLogger().Warnf("[Config] rollback change. file = %+v, reason = %s", &c.DefaultConfigFileMetadata, reason)
+	currentContent := c.GetContent()
+	c.content = applied.previousContent
+	c.fireChangeEvent(c.buildRollbackEvent(currentContent, applied.previousContent, c.persistent, reason))
+	return nil
+}
+
+func (c *defaultConfigFile) buildRollbackEvent(oldValue, newContent string,
+	persistent model.Persistent, reason string) model.ConfigFileChangeEvent {
+	newValue := newContent
+	if newValue == NotExistedFileContent {
+		newValue = ""
+	}
+	return model.ConfigFileChangeEvent{
+		ConfigFileMetadata: &c.DefaultConfigFileMetadata,
+		OldValue:           oldValue,
+		NewValue:           newValue,
+		ChangeType:         model.RolledBack,
+		Persistent:         persistent,
+		RollbackReason:     reason,
+	}
+}
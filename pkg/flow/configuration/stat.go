@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// StatReporter 配置中心统计数据的上报函数
+type StatReporter func(typ model.MetricType, gauge model.InstanceGauge) error
+
+// SetStatReporter 设置统计数据的上报函数，需在获取配置文件之前设置
+func (c *ConfigFileFlow) SetStatReporter(reporter StatReporter) {
+	c.statReporter = reporter
+}
+
+// reportOperation 上报配置中心操作的耗时及结果
+func reportOperation(reporter StatReporter, namespace, fileGroup string, operation model.ConfigOperation,
+	startTime time.Time, success bool) {
+	if reporter == nil {
+		return
+	}
+	_ = reporter(model.ConfigOperationStat, &model.ConfigOperationGauge{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		Operation: operation,
+		Success:   success,
+		Delay:     time.Since(startTime),
+	})
+}
+
+// reportWatch 上报一次长轮询的耗时及结果，有变更时按变更的配置文件归属上报
+func (c *ConfigFileFlow) reportWatch(response *configconnector.ConfigFileResponse, err error, startTime time.Time) {
+	var (
+		namespace, fileGroup string
+		success              bool
+	)
+	if err == nil && response != nil {
+		if changed := response.GetConfigFile(); changed != nil {
+			namespace = changed.GetNamespace()
+			fileGroup = changed.GetFileGroup()
+		}
+		success = response.GetCode() == uint32(apimodel.Code_ExecuteSuccess) ||
+			response.GetCode() == uint32(apimodel.Code_DataNoChange)
+	}
+	reportOperation(c.statReporter, namespace, fileGroup, model.ConfigOperationWatch, startTime, success)
+}
+
+// reportPropagation 上报配置从发布到通知监听器的传播时延，发布时间未知或者本地时钟落后时不上报
+func reportPropagation(reporter StatReporter, file *configconnector.ConfigFile, notifiedTime time.Time) {
+	if reporter == nil || file == nil || file.ReleaseTime.IsZero() {
+		return
+	}
+	delay := notifiedTime.Sub(file.ReleaseTime)
+	if delay < 0 {
+		log.GetBaseLogger().Debugf("[Config] skip reporting propagation delay, release time %v is after local time %v",
+			file.ReleaseTime, notifiedTime)
+		return
+	}
+	_ = reporter(model.ConfigPropagationStat, &model.ConfigPropagationGauge{
+		Namespace: file.GetNamespace(),
+		FileGroup: file.GetFileGroup(),
+		FileName:  file.GetFileName(),
+		Delay:     delay,
+	})
+}
@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+func TestReportPropagation(t *testing.T) {
+	var gauges []*model.ConfigPropagationGauge
+	reporter := func(typ model.MetricType, gauge model.InstanceGauge) error {
+		if typ != model.ConfigPropagationStat {
+			t.Fatalf("unexpected metric type %s", model.DescMetricType(typ))
+		}
+		gauges = append(gauges, gauge.(*model.ConfigPropagationGauge))
+		return nil
+	}
+	releaseTime := configconnector.ParseReleaseTime("2024-01-02 15:04:05")
+	if releaseTime.IsZero() {
+		t.Fatal("expect release time parsed")
+	}
+	file := &configconnector.ConfigFile{Namespace: "default", FileGroup: "group", FileName: "app.yaml",
+		ReleaseTime: releaseTime}
+
+	reportPropagation(reporter, file, releaseTime.Add(1500*time.Millisecond))
+	if len(gauges) != 1 || gauges[0].Delay != 1500*time.Millisecond || gauges[0].FileGroup != "group" {
+		t.Fatalf("expect propagation delay reported, got %+v", gauges)
+	}
+	// 本地时钟落后于发布时间或者发布时间未知时不上报
+	reportPropagation(reporter, file, releaseTime.Add(-time.Second))
+	reportPropagation(reporter, &configconnector.ConfigFile{Namespace: "default"}, time.Now())
+	if len(gauges) != 1 {
+		t.Fatalf("expect skewed or unknown release time skipped, got %+v", gauges)
+	}
+	if !configconnector.ParseReleaseTime("invalid").IsZero() {
+		t.Fatal("expect invalid release time parsed as zero")
+	}
+}
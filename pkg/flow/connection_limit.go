@@ -0,0 +1,102 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// connectionLimits 按照consumer.servicesSpecific[].connectionLimit限制客户端到被调服务的连接数，
+// 每个服务使用一个舱壁信号量，一个许可对应一条连接
+type connectionLimits struct {
+	mutex    sync.Mutex
+	limiters map[model.ServiceKey]*bulkhead
+}
+
+// getLimiter 获取服务的连接数限制，服务未配置时返回nil
+func (c *connectionLimits) getLimiter(svcKey model.ServiceKey, consumerCfg config.ConsumerConfig) *bulkhead {
+	svcCfg := consumerCfg.GetServiceSpecific(svcKey.Namespace, svcKey.Service)
+	if reflect2.IsNil(svcCfg) || svcCfg.GetConnectionLimit() == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if limiter, ok := c.limiters[svcKey]; ok {
+		return limiter
+	}
+	if c.limiters == nil {
+		c.limiters = make(map[model.ServiceKey]*bulkhead)
+	}
+	limitCfg := svcCfg.GetConnectionLimit()
+	limiter := &bulkhead{
+		namespace:   svcKey.Namespace,
+		service:     svcKey.Service,
+		permits:     make(chan struct{}, limitCfg.MaxConnections),
+		maxQueue:    int32(limitCfg.MaxQueueSize),
+		maxWaitTime: limitCfg.GetMaxWaitTime(),
+	}
+	c.limiters[svcKey] = limiter
+	return limiter
+}
+
+// AcquireConnection 按照被调服务的连接数限制获取建立连接的许可
+func (e *Engine) AcquireConnection(svcKey model.ServiceKey) (func(), error) {
+	limiter := e.connectionLimits.getLimiter(svcKey, e.configuration.GetConsumer())
+	if limiter == nil {
+		return func() {}, nil
+	}
+	result := limiter.acquire()
+	e.reportConnectionLimit(limiter, result)
+	if result == model.BulkheadRejected {
+		return nil, fmt.Errorf("%w: %s::%s reaches max connections %d", model.ErrConnectionLimited,
+			svcKey.Namespace, svcKey.Service, cap(limiter.permits))
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			limiter.release()
+			e.reportConnectionLimit(limiter, model.BulkheadPassed)
+		})
+	}, nil
+}
+
+func (e *Engine) reportConnectionLimit(limiter *bulkhead, result model.BulkheadResult) {
+	gauge := &model.ConnectionLimitGauge{
+		Namespace:   limiter.namespace,
+		Service:     limiter.service,
+		Result:      result,
+		Connections: len(limiter.permits),
+		QueueSize:   int(atomic.LoadInt32(&limiter.queued)),
+	}
+	if result == model.BulkheadRejected {
+		log.GetBaseLogger().Warnf("[ConnectionLimit] connections to %s::%s reach the limit %d, reject",
+			limiter.namespace, limiter.service, cap(limiter.permits))
+	}
+	if err := e.SyncReportStat(model.ConnectionLimitStat, gauge); err != nil {
+		log.GetBaseLogger().Errorf("fail to report connection limit of %s::%s: %v",
+			limiter.namespace, limiter.service, err)
+	}
+}
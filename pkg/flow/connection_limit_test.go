@@ -0,0 +1,75 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestConnectionLimits(t *testing.T) {
+	cfg, err := config.LoadConfiguration([]byte(`
+global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  servicesSpecific:
+    - namespace: Test
+      service: echo
+      connectionLimit:
+        maxConnections: 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits := &connectionLimits{}
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "echo"}
+	limiter := limits.getLimiter(svcKey, cfg.GetConsumer())
+	if limiter == nil || limits.getLimiter(svcKey, cfg.GetConsumer()) != limiter {
+		t.Fatal("connection limiter should be created once")
+	}
+	if limiter.acquire() != model.BulkheadPassed || limiter.acquire() != model.BulkheadRejected {
+		t.Fatal("connections should be limited to 1")
+	}
+	limiter.release()
+	if limiter.acquire() != model.BulkheadPassed {
+		t.Fatal("acquire should pass after release")
+	}
+	if limits.getLimiter(model.ServiceKey{Namespace: "Test", Service: "other"}, cfg.GetConsumer()) != nil {
+		t.Fatal("unconfigured service should not be limited")
+	}
+
+	if _, err := config.LoadConfiguration([]byte(`
+global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  servicesSpecific:
+    - namespace: Test
+      service: echo
+      connectionLimit:
+        maxConnections: 0
+`)); err == nil || !strings.Contains(err.Error(), "maxConnections") {
+		t.Fatalf("maxConnections 0 should be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// contractKey 服务契约的缓存key
+type contractKey struct {
+	namespace string
+	service   string
+	protocol  string
+	version   string
+}
+
+// contractEntry 缓存的服务契约，服务未上报契约时contract为nil
+type contractEntry struct {
+	contract  *model.ServiceContract
+	fetchTime time.Time
+}
+
+// serviceContracts 按照consumer.contractValidation.refreshInterval缓存的被调服务契约
+type serviceContracts struct {
+	mutex   sync.Mutex
+	entries map[contractKey]*contractEntry
+}
+
+// get 获取缓存的契约，不存在时返回nil
+func (s *serviceContracts) get(key contractKey) *contractEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.entries[key]
+}
+
+// put 缓存从服务端拉取的契约
+func (s *serviceContracts) put(key contractKey, entry *contractEntry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[contractKey]*contractEntry)
+	}
+	s.entries[key] = entry
+}
+
+// SyncValidateContract 按照被调服务上报的契约校验请求的方法及路径，不一致时输出告警日志并上报指标。
+// 未启用契约校验或者被调服务未上报契约时不做校验
+func (e *Engine) SyncValidateContract(req *model.ValidateContractRequest) (*model.ContractValidationResult, error) {
+	cfg := e.configuration.GetConsumer().GetContractValidation()
+	result := &model.ContractValidationResult{}
+	if !cfg.IsEnable() {
+		return result, nil
+	}
+	key := contractKey{
+		namespace: req.Namespace,
+		service:   req.Service,
+		protocol:  req.Protocol,
+		version:   req.Version,
+	}
+	if len(key.protocol) == 0 {
+		key.protocol = cfg.GetProtocol()
+	}
+	contract, err := e.loadServiceContract(key, cfg.GetRefreshInterval())
+	if err != nil {
+		return nil, err
+	}
+	if nil == contract {
+		return result, nil
+	}
+	result.Checked = true
+	result.Revision = contract.Revision
+	result.Reason = contract.Check(req.Method, req.Path)
+	if result.IsMismatch() {
+		log.GetBaseLogger().Warnf("[ContractValidation] request %s %s to %s/%s mismatches %s contract %s, reason: %s",
+			req.Method, req.Path, req.Namespace, req.Service, key.protocol, contract.Revision, result.Reason)
+		_ = e.SyncReportStat(model.ContractMismatchStat, &model.ContractMismatchGauge{
+			Namespace: req.Namespace,
+			Service:   req.Service,
+			Protocol:  key.protocol,
+			Method:    req.Method,
+			Path:      req.Path,
+			Reason:    result.Reason,
+		})
+	}
+	return result, nil
+}
+
+// loadServiceContract 获取被调服务的契约，缓存过期时向服务端重新拉取，
+// 拉取失败时继续使用过期的缓存，并在下一个刷新周期再重试
+func (e *Engine) loadServiceContract(key contractKey, refreshInterval time.Duration) (*model.ServiceContract, error) {
+	now := e.globalCtx.Now()
+	entry := e.serviceContracts.get(key)
+	if nil != entry && now.Sub(entry.fetchTime) < refreshInterval {
+		return entry.contract, nil
+	}
+	contract, err := e.connector.GetServiceContract(&model.GetServiceContractRequest{
+		Namespace: key.namespace,
+		Service:   key.service,
+		Protocol:  key.protocol,
+		Version:   key.version,
+		Timeout:   e.configuration.GetGlobal().GetAPI().GetTimeout(),
+	})
+	if err != nil {
+		if nil != entry {
+			log.GetBaseLogger().Warnf("[ContractValidation] fail to refresh contract of %s/%s, use cached one: %v",
+				key.namespace, key.service, err)
+			e.serviceContracts.put(key, &contractEntry{contract: entry.contract, fetchTime: now})
+			return entry.contract, nil
+		}
+		return nil, err
+	}
+	e.serviceContracts.put(key, &contractEntry{contract: contract, fetchTime: now})
+	return contract, nil
+}
@@ -188,6 +188,18 @@ type CommonInstancesRequest struct {
 	LbPolicy string
 	// 路由插件列表
 	Routers []servicerouter.ServiceRouter
+	// 调试用的强制路由地址
+	ForceHostPort string
+	// 可接受的实例缓存最大陈旧时间
+	MaxStaleness time.Duration
+	// 调用的接口名，取自请求中的方法参数，用于严格熔断模式下检查接口级熔断状态
+	Method string
+	// 整个获取流程的耗时预算，未设置时为nil
+	FlowBudget *model.FlowBudget
+	// 发生命名空间回退时为调用方请求的命名空间
+	FallbackFrom string
+	// 已经尝试的回退命名空间数量
+	FallbackTimes int
 }
 
 // clearValues 清理请求体
@@ -202,12 +214,18 @@ func (c *CommonInstancesRequest) clearValues(cfg config.Configuration) {
 	c.Criteria.ReplicateInfo.Count = 0
 	c.Criteria.ReplicateInfo.Nodes = nil
 	c.DoLoadBalance = false
+	c.Method = ""
 	c.HasSrcService = false
 	c.SkipRouteFilter = false
 	c.FetchAll = false
 	c.response = nil
 	c.LbPolicy = ""
 	c.Routers = nil
+	c.ForceHostPort = ""
+	c.MaxStaleness = 0
+	c.FlowBudget = nil
+	c.FallbackFrom = ""
+	c.FallbackTimes = 0
 }
 
 // InitByGetOneRequest 通过获取单个请求初始化通用请求对象
@@ -241,8 +259,23 @@ func (c *CommonInstancesRequest) InitByGetOneRequest(request *model.GetOneInstan
 	c.CallResult.APIName = model.ApiGetOneInstance
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
+	c.CallResult.Namespace, c.CallResult.Service = c.DstService.Namespace, c.DstService.Service
 	c.LbPolicy = request.LbPolicy
-	BuildControlParam(request, cfg, &c.ControlParam)
+	c.ForceHostPort = request.ForceHostPort
+	for _, argument := range request.Arguments {
+		if argument.ArgumentType() == model.ArgumentTypeMethod {
+			c.Method = argument.Value()
+		}
+	}
+	BuildServiceControlParam(request, cfg, &c.DstService, &c.ControlParam)
+	flowBudget := cfg.GetGlobal().GetAPI().GetFlowBudget()
+	if nil != request.FlowBudget {
+		flowBudget = *request.FlowBudget
+	}
+	if flowBudget > 0 {
+		c.FlowBudget = model.NewFlowBudget(flowBudget, time.Now())
+		c.ControlParam.Deadline = c.FlowBudget.Deadline()
+	}
 }
 
 func (c *CommonInstancesRequest) InitByProcessLoadBalanceRequest(
@@ -258,7 +291,7 @@ func (c *CommonInstancesRequest) InitByProcessLoadBalanceRequest(
 	c.Criteria.ReplicateInfo.Count = request.ReplicateCount
 	c.LbPolicy = request.LbPolicy
 	if len(c.LbPolicy) == 0 {
-		c.LbPolicy = cfg.GetConsumer().GetLoadbalancer().GetType()
+		c.LbPolicy = GetLbPolicy(cfg, &c.DstService)
 	}
 	if clsOwner, ok := request.DstInstances.(model.ClusterOwner); ok {
 		c.Criteria.Cluster = clsOwner.GetCluster()
@@ -268,6 +301,7 @@ func (c *CommonInstancesRequest) InitByProcessLoadBalanceRequest(
 	c.CallResult.APIName = model.ApiProcessLoadBalance
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
+	c.CallResult.Namespace, c.CallResult.Service = c.DstService.Namespace, c.DstService.Service
 }
 
 func (c *CommonInstancesRequest) InitByProcessRoutersRequest(
@@ -294,6 +328,7 @@ func (c *CommonInstancesRequest) InitByProcessRoutersRequest(
 	c.CallResult.APIName = model.ApiProcessRouters
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
+	c.CallResult.Namespace, c.CallResult.Service = c.DstService.Namespace, c.DstService.Service
 	BuildControlParam(request, cfg, &c.ControlParam)
 }
 
@@ -322,7 +357,9 @@ func (c *CommonInstancesRequest) InitByGetMultiRequest(request *model.GetInstanc
 	c.CallResult.APIName = model.ApiGetInstances
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &c.ControlParam)
+	c.CallResult.Namespace, c.CallResult.Service = c.DstService.Namespace, c.DstService.Service
+	c.MaxStaleness = request.MaxStaleness
+	BuildServiceControlParam(request, cfg, &c.DstService, &c.ControlParam)
 }
 
 // InitByGetAllRequest 通过获取全部请求初始化通用请求对象
@@ -338,7 +375,8 @@ func (c *CommonInstancesRequest) InitByGetAllRequest(request *model.GetAllInstan
 	c.CallResult.APIName = model.ApiGetAllInstances
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &c.ControlParam)
+	c.CallResult.Namespace, c.CallResult.Service = c.DstService.Namespace, c.DstService.Service
+	BuildServiceControlParam(request, cfg, &c.DstService, &c.ControlParam)
 }
 
 // RefreshByRedirect 通过重定向服务来进行刷新
@@ -351,10 +389,61 @@ func (c *CommonInstancesRequest) RefreshByRedirect(redirectedService *model.Serv
 	c.DstInstances = nil
 }
 
+// mappedServiceMetadata 服务名映射后的被调服务，元数据保持调用方传入的值
+type mappedServiceMetadata struct {
+	model.ServiceMetadata
+	svcKey model.ServiceKey
+}
+
+// GetNamespace 映射后的命名空间
+func (m *mappedServiceMetadata) GetNamespace() string {
+	return m.svcKey.Namespace
+}
+
+// GetService 映射后的服务名
+func (m *mappedServiceMetadata) GetService() string {
+	return m.svcKey.Service
+}
+
+// MapDstService 服务名映射，后续的发现及路由使用映射后的被调服务
+func (c *CommonInstancesRequest) MapDstService(svcKey model.ServiceKey) {
+	c.DstService = svcKey
+	if c.RouteInfo.DestService != nil {
+		c.RouteInfo.DestService = &mappedServiceMetadata{ServiceMetadata: c.RouteInfo.DestService, svcKey: svcKey}
+	}
+}
+
+// FallbackToNamespace 服务在当前命名空间中不存在，回退到下一个命名空间重新查找
+func (c *CommonInstancesRequest) FallbackToNamespace(namespace string) {
+	if len(c.FallbackFrom) == 0 {
+		c.FallbackFrom = c.DstService.Namespace
+	}
+	c.FallbackTimes++
+	c.MapDstService(model.ServiceKey{Namespace: namespace, Service: c.DstService.Service})
+	c.RouteInfo.DestRouteRule = nil
+	c.DstInstances = nil
+}
+
+// ResetFallback 回退命名空间中均未找到服务，还原为调用方请求的命名空间
+func (c *CommonInstancesRequest) ResetFallback() {
+	if len(c.FallbackFrom) == 0 {
+		return
+	}
+	c.MapDstService(model.ServiceKey{Namespace: c.FallbackFrom, Service: c.DstService.Service})
+	c.RouteInfo.DestRouteRule = nil
+	c.DstInstances = nil
+	c.FallbackFrom = ""
+}
+
 // BuildInstancesResponse 构建查询实例的应答
 func (c *CommonInstancesRequest) BuildInstancesResponse(dstService model.ServiceKey, cluster *model.Cluster,
 	instances []model.Instance, totalWeight int, svcInstances model.ServiceInstances) *model.InstancesResponse {
-	return buildInstancesResponse(c.response, dstService, cluster, instances, totalWeight, svcInstances)
+	response := buildInstancesResponse(c.response, dstService, cluster, instances, totalWeight, svcInstances)
+	response.FallbackNamespace = ""
+	if len(c.FallbackFrom) > 0 {
+		response.FallbackNamespace = dstService.Namespace
+	}
+	return response
 }
 
 // GetDstService 获取目标服务
@@ -476,6 +565,7 @@ func (cr *ServicesRequest) InitByGetServicesRequest(
 	cr.CallResult.APIName = model.ApiServices
 	cr.CallResult.RetStatus = model.RetSuccess
 	cr.CallResult.RetCode = model.ErrCodeSuccess
+	cr.CallResult.Namespace, cr.CallResult.Service = request.Namespace, request.Business
 	cr.DstService.Namespace = request.Namespace
 	cr.DstService.Service = request.Business
 	cr.Trigger.EnableServices = true
@@ -516,6 +606,7 @@ func (cr *CommonRuleRequest) InitByGetRuleRequest(
 	cr.CallResult.APIName = model.ApiGetRouteRule
 	cr.CallResult.RetStatus = model.RetSuccess
 	cr.CallResult.RetCode = model.ErrCodeSuccess
+	cr.CallResult.Namespace, cr.CallResult.Service = request.Namespace, request.Service
 	cr.DstService.Namespace = request.Namespace
 	cr.DstService.Service = request.Service
 	cr.DstService.Type = eventType
@@ -617,6 +708,7 @@ func (cl *CommonRateLimitRequest) InitByGetQuotaRequest(request *model.QuotaRequ
 	cl.CallResult.APIName = model.ApiGetQuota
 	cl.CallResult.RetStatus = model.RetSuccess
 	cl.CallResult.RetCode = model.ErrCodeSuccess
+	cl.CallResult.Namespace, cl.CallResult.Service = cl.DstService.Namespace, cl.DstService.Service
 	BuildControlParam(request, cfg, &cl.ControlParam)
 
 	// 限流相关同步请求，减少重试此数和重试间隔
@@ -695,6 +787,10 @@ func (c *CommonServiceCallResultRequest) InitByServiceCallResult(request *model.
 	c.CallResult.APIName = model.ApiUpdateServiceCallResult
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
+	c.CallResult.Namespace, c.CallResult.Service = "", ""
+	if instance := request.GetCalledInstance(); instance != nil {
+		c.CallResult.Namespace, c.CallResult.Service = instance.GetNamespace(), instance.GetService()
+	}
 }
 
 // ConsumerInitCallServiceResultRequest 初始化消费者调用服务结果请求
@@ -711,4 +807,5 @@ func (c *ConsumerInitCallServiceResultRequest) InitByServiceCallResult(req *mode
 	c.CallResult.APIName = model.ApiInitCalleeServices
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
+	c.CallResult.Namespace, c.CallResult.Service = req.Namespace, req.Service
 }
@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"time"
 
 	"github.com/modern-go/reflect2"
@@ -38,6 +39,12 @@ type ControlParamProvider interface {
 	SetRetryCount(int)
 }
 
+// ContextProvider 携带调用方上下文的请求
+type ContextProvider interface {
+	// Context 获取调用方上下文
+	Context() context.Context
+}
+
 // BuildControlParam 为服务注册的请求设置默认值
 func BuildControlParam(
 	provider ControlParamProvider, cfg config.Configuration, param *model.ControlParam) {
@@ -52,8 +59,31 @@ func BuildControlParam(
 		param.MaxRetry = *provider.GetRetryCountPtr()
 	}
 	param.RetryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	param.Deadline = time.Time{}
+	param.Context = nil
+	if ctxProvider, ok := provider.(ContextProvider); ok && !reflect2.IsNil(provider) {
+		param.Context = ctxProvider.Context()
+	}
 	if !reflect2.IsNil(provider) {
 		provider.SetTimeout(param.Timeout)
 		provider.SetRetryCount(param.MaxRetry)
 	}
 }
+
+// BuildServiceControlParam 为获取服务实例的请求设置默认值，请求未显式设置超时时间时优先使用服务级配置
+func BuildServiceControlParam(provider ControlParamProvider, cfg config.Configuration,
+	svcKey *model.ServiceKey, param *model.ControlParam) {
+	explicitTimeout := !reflect2.IsNil(provider) && nil != provider.GetTimeoutPtr()
+	BuildControlParam(provider, cfg, param)
+	if explicitTimeout {
+		return
+	}
+	svcCfg := cfg.GetConsumer().GetServiceSpecific(svcKey.Namespace, svcKey.Service)
+	if svcCfg == nil || svcCfg.GetTimeout() <= 0 {
+		return
+	}
+	param.Timeout = svcCfg.GetTimeout()
+	if !reflect2.IsNil(provider) {
+		provider.SetTimeout(param.Timeout)
+	}
+}
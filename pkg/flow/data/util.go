@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -121,7 +122,12 @@ func GetHealthCheckers(cfg config.Configuration, supplier plugin.Supplier) ([]he
 
 // GetServiceRouterChain 获取服务路由插件链
 func GetServiceRouterChain(cfg config.Configuration, supplier plugin.Supplier) (*servicerouter.RouterChain, error) {
-	filterChain := cfg.GetConsumer().GetServiceRouter().GetChain()
+	return GetServiceRouterChainByNames(cfg.GetConsumer().GetServiceRouter().GetChain(), supplier)
+}
+
+// GetServiceRouterChainByNames 根据插件名获取服务路由插件链
+func GetServiceRouterChainByNames(filterChain []string,
+	supplier plugin.Supplier) (*servicerouter.RouterChain, error) {
 	filters := &servicerouter.RouterChain{
 		Chain: make([]servicerouter.ServiceRouter, 0, len(filterChain)),
 	}
@@ -167,6 +173,19 @@ func GetLoadBalancer(cfg config.Configuration, supplier plugin.Supplier) (loadba
 	return targetPlugin.(loadbalancer.LoadBalancer), nil
 }
 
+// GetLbPolicy 获取服务生效的负载均衡类型，依次查找服务级、命名空间级配置，都未配置时使用全局配置
+func GetLbPolicy(cfg config.Configuration, svcKey *model.ServiceKey) string {
+	consumerCfg := cfg.GetConsumer()
+	if svcCfg := consumerCfg.GetServiceSpecific(svcKey.Namespace, svcKey.Service); svcCfg != nil &&
+		len(svcCfg.GetLbPolicy()) > 0 {
+		return svcCfg.GetLbPolicy()
+	}
+	if nsCfg := consumerCfg.GetNamespaceSpecific(svcKey.Namespace); nsCfg != nil && len(nsCfg.GetLbPolicy()) > 0 {
+		return nsCfg.GetLbPolicy()
+	}
+	return consumerCfg.GetLoadbalancer().GetType()
+}
+
 // GetLoadBalancerByLbType 获取负载均衡插件
 func GetLoadBalancerByLbType(lbType string, supplier plugin.Supplier) (loadbalancer.LoadBalancer, error) {
 	targetPlugin, err := supplier.GetPlugin(common.TypeLoadBalancer, lbType)
@@ -176,6 +195,22 @@ func GetLoadBalancerByLbType(lbType string, supplier plugin.Supplier) (loadbalan
 	return targetPlugin.(loadbalancer.LoadBalancer), nil
 }
 
+// SleepWithContext 等待指定时长，调用方上下文提前取消时返回false
+func SleepWithContext(ctx context.Context, duration time.Duration) bool {
+	if nil == ctx {
+		time.Sleep(duration)
+		return true
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // SingleInvoke 同步调用的通用方法定义
 type SingleInvoke func(request interface{}) (interface{}, error)
 
@@ -187,6 +222,10 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 	var err error
 	retryInterval := param.RetryInterval
 	for retryTimes < param.MaxRetry {
+		if ctxErr := model.ContextError(param.Context); ctxErr != nil {
+			return resp, model.NewSDKError(model.ErrCodeAPITimeoutError, ctxErr,
+				fmt.Sprintf("fail to do %s after retry %v times", name, retryTimes))
+		}
 		startTime := clock.GetClock().Now()
 		resp, err = call(request)
 		consumeTime := clock.GetClock().Now().Sub(startTime)
@@ -201,7 +240,9 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 		if retryTimes >= param.MaxRetry {
 			break
 		}
-		time.Sleep(retryInterval)
+		if !SleepWithContext(param.Context, retryInterval) {
+			continue
+		}
 		log.GetBaseLogger().Warnf("retry %s for timeout, consume time %v,"+
 			" Namespace: %s, Service: %s, retry times: %d",
 			name, consumeTime, svcKey.Namespace, svcKey.Service, retryTimes)
@@ -0,0 +1,46 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+)
+
+// TestMain 将测试过程中的日志输出到临时目录，避免在源码目录下生成日志文件
+func TestMain(m *testing.M) {
+	logDir, err := ioutil.TempDir("", "polaris-pkg-flow-dnsserver-test")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = log.ConfigBaseLogger(log.DefaultLogger, log.CreateDefaultLoggerOptions(
+		filepath.Join(logDir, log.DefaultBaseLogRotationPath), log.DefaultBaseLogLevel)); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(logDir)
+	os.Exit(code)
+}
@@ -0,0 +1,236 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+const (
+	typeA    uint16 = 1
+	typeAAAA uint16 = 28
+	typeSRV  uint16 = 33
+	typeANY  uint16 = 255
+
+	classINET uint16 = 1
+
+	rcodeSuccess        = 0
+	rcodeFormatError    = 1
+	rcodeServerFailure  = 2
+	rcodeNameError      = 3
+	rcodeNotImplemented = 4
+	rcodeRefused        = 5
+
+	headerSize = 12
+	// maxUDPSize 未使用EDNS时UDP应答的最大长度
+	maxUDPSize = 512
+	// maxTCPSize TCP应答的最大长度
+	maxTCPSize = 65535
+	// maxNameSize 域名的最大长度
+	maxNameSize = 255
+
+	flagQR = 1 << 15
+	flagAA = 1 << 10
+	flagTC = 1 << 9
+	flagRD = 1 << 8
+	// questionPointer 指向报文头之后的查询域名的压缩指针
+	questionPointer = 0xC000 | headerSize
+)
+
+var errFormat = errors.New("malformed dns message")
+
+// header DNS报文头
+type header struct {
+	id      uint16
+	flags   uint16
+	qdCount uint16
+}
+
+// question DNS查询
+type question struct {
+	// name 查询的域名，不含末尾的点
+	name string
+	// raw 查询域名的报文格式
+	raw    []byte
+	qtype  uint16
+	qclass uint16
+}
+
+// parseHeader 解析报文头
+func parseHeader(msg []byte) (*header, error) {
+	if len(msg) < headerSize {
+		return nil, errFormat
+	}
+	return &header{
+		id:      binary.BigEndian.Uint16(msg[0:2]),
+		flags:   binary.BigEndian.Uint16(msg[2:4]),
+		qdCount: binary.BigEndian.Uint16(msg[4:6]),
+	}, nil
+}
+
+// parseQuestion 解析报文头之后的第一个查询，查询中不应出现压缩指针
+func parseQuestion(msg []byte) (*question, error) {
+	offset := headerSize
+	labels := make([]string, 0, 4)
+	for {
+		if offset >= len(msg) {
+			return nil, errFormat
+		}
+		length := int(msg[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if length&0xC0 != 0 || offset+length > len(msg) {
+			return nil, errFormat
+		}
+		labels = append(labels, string(msg[offset:offset+length]))
+		offset += length
+		if offset-headerSize > maxNameSize {
+			return nil, errFormat
+		}
+	}
+	if offset+4 > len(msg) {
+		return nil, errFormat
+	}
+	return &question{
+		name:   strings.Join(labels, "."),
+		raw:    msg[headerSize:offset],
+		qtype:  binary.BigEndian.Uint16(msg[offset : offset+2]),
+		qclass: binary.BigEndian.Uint16(msg[offset+2 : offset+4]),
+	}, nil
+}
+
+// encodeName 将域名编码为报文格式
+func encodeName(name string) []byte {
+	buf := make([]byte, 0, len(name)+2)
+	for _, label := range strings.Split(strings.Trim(name, "."), ".") {
+		if len(label) == 0 {
+			continue
+		}
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	return append(buf, 0)
+}
+
+// record 资源记录，name 为空时使用指向查询域名的压缩指针
+type record struct {
+	name  []byte
+	rtype uint16
+	ttl   uint32
+	data  []byte
+}
+
+func (r *record) size() int {
+	nameSize := 2
+	if r.name != nil {
+		nameSize = len(r.name)
+	}
+	return nameSize + 10 + len(r.data)
+}
+
+func (r *record) appendTo(buf []byte) []byte {
+	if r.name != nil {
+		buf = append(buf, r.name...)
+	} else {
+		buf = appendUint16(buf, questionPointer)
+	}
+	buf = appendUint16(buf, r.rtype)
+	buf = appendUint16(buf, classINET)
+	buf = appendUint32(buf, r.ttl)
+	buf = appendUint16(buf, uint16(len(r.data)))
+	return append(buf, r.data...)
+}
+
+// srvData 编码SRV记录的数据
+func srvData(priority, weight, port uint16, target []byte) []byte {
+	data := make([]byte, 0, 6+len(target))
+	data = appendUint16(data, priority)
+	data = appendUint16(data, weight)
+	data = appendUint16(data, port)
+	return append(data, target...)
+}
+
+// response DNS应答
+type response struct {
+	id         uint16
+	flags      uint16
+	rcode      int
+	question   *question
+	answers    []*record
+	additional []*record
+}
+
+// pack 编码应答，超出最大长度时截断应答记录并设置TC标记，附加记录放不下时直接丢弃
+func (r *response) pack(maxSize int) []byte {
+	buf := make([]byte, headerSize, maxUDPSize)
+	size := headerSize
+	if r.question != nil {
+		size += len(r.question.raw) + 4
+	}
+	flags := r.flags
+	answers := 0
+	for _, answer := range r.answers {
+		if size+answer.size() > maxSize {
+			flags |= flagTC
+			break
+		}
+		size += answer.size()
+		answers++
+	}
+	additional := 0
+	if flags&flagTC == 0 {
+		for _, extra := range r.additional {
+			if size+extra.size() > maxSize {
+				break
+			}
+			size += extra.size()
+			additional++
+		}
+	}
+	binary.BigEndian.PutUint16(buf[0:2], r.id)
+	binary.BigEndian.PutUint16(buf[2:4], flags|uint16(r.rcode))
+	if r.question != nil {
+		binary.BigEndian.PutUint16(buf[4:6], 1)
+	}
+	binary.BigEndian.PutUint16(buf[6:8], uint16(answers))
+	binary.BigEndian.PutUint16(buf[10:12], uint16(additional))
+	if r.question != nil {
+		buf = append(buf, r.question.raw...)
+		buf = appendUint16(buf, r.question.qtype)
+		buf = appendUint16(buf, r.question.qclass)
+	}
+	for _, answer := range r.answers[:answers] {
+		buf = answer.appendTo(buf)
+	}
+	for _, extra := range r.additional[:additional] {
+		buf = extra.appendTo(buf)
+	}
+	return buf
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
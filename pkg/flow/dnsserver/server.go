@@ -0,0 +1,308 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package dnsserver 内置DNS服务，将本地缓存中的健康实例以 A/AAAA/SRV 记录的方式暴露给无法接入SDK的同机进程，
+// 查询的域名格式为 service.namespace.<domain>，SRV记录的目标地址为 <ip>.addr.<domain>，其中ip中的分隔符替换为'-'
+package dnsserver
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+)
+
+const (
+	// addrLabel SRV记录目标地址使用的保留标签
+	addrLabel = "addr"
+	// tcpIdleTimeout TCP连接的空闲超时时间
+	tcpIdleTimeout = 10 * time.Second
+)
+
+// Resolver 查询服务的可用实例
+type Resolver func(namespace, service string) ([]model.Instance, error)
+
+// Server 内置DNS服务
+type Server struct {
+	address  string
+	domain   string
+	ttl      uint32
+	resolver Resolver
+
+	udpConn  net.PacketConn
+	listener net.Listener
+	wg       sync.WaitGroup
+	closed   uint32
+}
+
+// NewServer 创建内置DNS服务
+func NewServer(cfg config.DNSServerConfig, resolver Resolver) *Server {
+	return &Server{
+		address:  cfg.GetAddress(),
+		domain:   strings.ToLower(strings.Trim(cfg.GetDomain(), ".")),
+		ttl:      uint32(math.Ceil(cfg.GetTTL().Seconds())),
+		resolver: resolver,
+	}
+}
+
+// Start 同时监听UDP及TCP并开始处理查询
+func (s *Server) Start() error {
+	udpConn, err := net.ListenPacket("udp", s.address)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to listen dns server on udp %s", s.address)
+	}
+	// 端口为0时TCP使用与UDP相同的端口
+	listener, err := net.Listen("tcp", udpConn.LocalAddr().String())
+	if err != nil {
+		_ = udpConn.Close()
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to listen dns server on tcp %s", s.address)
+	}
+	s.udpConn = udpConn
+	s.listener = listener
+	s.wg.Add(2)
+	profiling.Go("dnsServer", "serveUDP", s.serveUDP)
+	profiling.Go("dnsServer", "serveTCP", s.serveTCP)
+	log.GetBaseLogger().Infof("[DNSServer] serving %s on %s", s.domain, udpConn.LocalAddr())
+	return nil
+}
+
+// Addr 实际监听的地址
+func (s *Server) Addr() net.Addr {
+	return s.udpConn.LocalAddr()
+}
+
+// Stop 停止服务
+func (s *Server) Stop() {
+	if !atomic.CompareAndSwapUint32(&s.closed, 0, 1) {
+		return
+	}
+	if s.udpConn != nil {
+		_ = s.udpConn.Close()
+	}
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	s.wg.Wait()
+}
+
+func (s *Server) isClosed() bool {
+	return atomic.LoadUint32(&s.closed) == 1
+}
+
+func (s *Server) serveUDP() {
+	defer s.wg.Done()
+	buf := make([]byte, maxTCPSize)
+	for {
+		n, addr, err := s.udpConn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			log.GetBaseLogger().Warnf("[DNSServer] fail to read udp query: %v", err)
+			continue
+		}
+		if resp := s.handle(buf[:n], maxUDPSize); resp != nil {
+			if _, err = s.udpConn.WriteTo(resp, addr); err != nil {
+				log.GetBaseLogger().Debugf("[DNSServer] fail to write udp response to %s: %v", addr, err)
+			}
+		}
+	}
+}
+
+func (s *Server) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return
+			}
+			log.GetBaseLogger().Warnf("[DNSServer] fail to accept tcp connection: %v", err)
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn 处理TCP连接上的查询，报文以2字节长度作为前缀
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	lenBuf := make([]byte, 2)
+	for !s.isClosed() {
+		_ = conn.SetReadDeadline(time.Now().Add(tcpIdleTimeout))
+		if _, err := io.ReadFull(conn, lenBuf); err != nil {
+			return
+		}
+		msg := make([]byte, binary.BigEndian.Uint16(lenBuf))
+		if _, err := io.ReadFull(conn, msg); err != nil {
+			return
+		}
+		resp := s.handle(msg, maxTCPSize)
+		if resp == nil {
+			return
+		}
+		if _, err := conn.Write(append(appendUint16(nil, uint16(len(resp))), resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// handle 处理一个查询报文，返回nil表示丢弃
+func (s *Server) handle(msg []byte, maxSize int) []byte {
+	hdr, err := parseHeader(msg)
+	if err != nil || hdr.flags&flagQR != 0 {
+		return nil
+	}
+	resp := &response{id: hdr.id, flags: flagQR | flagAA | hdr.flags&(flagRD|0x7800)}
+	if opcode := (hdr.flags >> 11) & 0xF; opcode != 0 {
+		resp.rcode = rcodeNotImplemented
+		return resp.pack(maxSize)
+	}
+	if hdr.qdCount != 1 {
+		resp.rcode = rcodeFormatError
+		return resp.pack(maxSize)
+	}
+	q, err := parseQuestion(msg)
+	if err != nil {
+		resp.rcode = rcodeFormatError
+		return resp.pack(maxSize)
+	}
+	resp.question = q
+	s.answer(q, resp)
+	return resp.pack(maxSize)
+}
+
+// answer 根据查询填充应答
+func (s *Server) answer(q *question, resp *response) {
+	if q.qclass != classINET {
+		resp.rcode = rcodeRefused
+		return
+	}
+	prefix, ok := s.trimDomain(q.name)
+	if !ok {
+		resp.rcode = rcodeRefused
+		return
+	}
+	if strings.HasSuffix(strings.ToLower(prefix), "."+addrLabel) {
+		s.answerAddr(q, strings.TrimSuffix(prefix[:len(prefix)-len(addrLabel)], "."), resp)
+		return
+	}
+	idx := strings.LastIndex(prefix, ".")
+	if idx <= 0 {
+		resp.rcode = rcodeNameError
+		return
+	}
+	namespace, service := prefix[idx+1:], prefix[:idx]
+	instances, err := s.resolver(namespace, service)
+	if err != nil {
+		if sdkErr, ok := err.(model.SDKError); ok && sdkErr.ErrorCode() == model.ErrCodeServiceNotFound {
+			resp.rcode = rcodeNameError
+			return
+		}
+		log.GetBaseLogger().Warnf("[DNSServer] fail to resolve %s/%s: %v", namespace, service, err)
+		resp.rcode = rcodeServerFailure
+		return
+	}
+	for _, instance := range instances {
+		ip := net.ParseIP(instance.GetHost())
+		if ip == nil {
+			continue
+		}
+		switch q.qtype {
+		case typeA, typeAAAA, typeANY:
+			if r := s.addressRecord(nil, q.qtype, ip); r != nil {
+				resp.answers = append(resp.answers, r)
+			}
+		case typeSRV:
+			target := encodeName(addrName(ip) + "." + addrLabel + "." + s.domain)
+			weight := instance.GetWeight()
+			if weight > math.MaxUint16 {
+				weight = math.MaxUint16
+			}
+			resp.answers = append(resp.answers, &record{
+				rtype: typeSRV,
+				ttl:   s.ttl,
+				data:  srvData(0, uint16(weight), uint16(instance.GetPort()), target),
+			})
+			if r := s.addressRecord(target, typeANY, ip); r != nil {
+				resp.additional = append(resp.additional, r)
+			}
+		}
+	}
+}
+
+// answerAddr 应答SRV记录目标地址的查询
+func (s *Server) answerAddr(q *question, label string, resp *response) {
+	ip := parseAddrName(label)
+	if ip == nil {
+		resp.rcode = rcodeNameError
+		return
+	}
+	if r := s.addressRecord(nil, q.qtype, ip); r != nil {
+		resp.answers = append(resp.answers, r)
+	}
+}
+
+// addressRecord 构建与查询类型匹配的 A/AAAA 记录，类型不匹配时返回nil
+func (s *Server) addressRecord(name []byte, qtype uint16, ip net.IP) *record {
+	if v4 := ip.To4(); v4 != nil {
+		if qtype != typeA && qtype != typeANY {
+			return nil
+		}
+		return &record{name: name, rtype: typeA, ttl: s.ttl, data: v4}
+	}
+	if qtype != typeAAAA && qtype != typeANY {
+		return nil
+	}
+	return &record{name: name, rtype: typeAAAA, ttl: s.ttl, data: ip.To16()}
+}
+
+// trimDomain 去除域名后缀，域名后缀不区分大小写，服务名保留原始大小写
+func (s *Server) trimDomain(name string) (string, bool) {
+	suffix := "." + s.domain
+	if len(name) <= len(suffix) || !strings.EqualFold(name[len(name)-len(suffix):], suffix) {
+		return "", false
+	}
+	return name[:len(name)-len(suffix)], true
+}
+
+// addrName 将IP编码为域名标签
+func addrName(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return strings.Replace(v4.String(), ".", "-", -1)
+	}
+	return strings.Replace(ip.String(), ":", "-", -1)
+}
+
+// parseAddrName 解析域名标签中的IP
+func parseAddrName(label string) net.IP {
+	if strings.Count(label, "-") == 3 {
+		if ip := net.ParseIP(strings.Replace(label, "-", ".", -1)); ip != nil {
+			return ip
+		}
+	}
+	return net.ParseIP(strings.Replace(label, "-", ":", -1))
+}
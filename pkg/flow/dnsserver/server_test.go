@@ -0,0 +1,101 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package dnsserver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type testInstance struct {
+	model.Instance
+	host string
+	port uint32
+}
+
+func (i *testInstance) GetHost() string {
+	return i.host
+}
+
+func (i *testInstance) GetPort() uint32 {
+	return i.port
+}
+
+func (i *testInstance) GetWeight() int {
+	return 100
+}
+
+// TestServer 使用标准库的DNS解析器验证 A/SRV 记录
+func TestServer(t *testing.T) {
+	cfg := &config.DNSServerConfigImpl{Address: "127.0.0.1:0"}
+	cfg.SetDefault()
+	server := NewServer(cfg, func(namespace, service string) ([]model.Instance, error) {
+		if namespace != "default" || service != "Echo" {
+			return nil, model.NewSDKError(model.ErrCodeServiceNotFound, nil, "service not found")
+		}
+		return []model.Instance{
+			&testInstance{host: "10.0.0.1", port: 8080},
+			&testInstance{host: "10.0.0.2", port: 8081},
+		}, nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	for _, network := range []string{"udp", "tcp"} {
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, server.Addr().String())
+			},
+		}
+		addrs, err := resolver.LookupHost(context.Background(), "Echo.default.polaris.")
+		if err != nil {
+			t.Fatalf("%s: %v", network, err)
+		}
+		sort.Strings(addrs)
+		if len(addrs) != 2 || addrs[0] != "10.0.0.1" || addrs[1] != "10.0.0.2" {
+			t.Fatalf("%s: unexpected addrs %v", network, addrs)
+		}
+		_, srvs, err := resolver.LookupSRV(context.Background(), "", "", "Echo.default.polaris.")
+		if err != nil {
+			t.Fatalf("%s: %v", network, err)
+		}
+		if len(srvs) != 2 {
+			t.Fatalf("%s: unexpected srvs %v", network, srvs)
+		}
+		for _, srv := range srvs {
+			targets, err := resolver.LookupHost(context.Background(), srv.Target)
+			if err != nil || len(targets) != 1 {
+				t.Fatalf("%s: fail to resolve srv target %s: %v %v", network, srv.Target, targets, err)
+			}
+			if (targets[0] == "10.0.0.1" && srv.Port != 8080) || (targets[0] == "10.0.0.2" && srv.Port != 8081) {
+				t.Fatalf("%s: unexpected srv %+v -> %v", network, srv, targets)
+			}
+		}
+		if _, err = resolver.LookupHost(context.Background(), "other.default.polaris."); err == nil {
+			t.Fatalf("%s: expect not found", network)
+		}
+	}
+}
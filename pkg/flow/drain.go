@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+)
+
+// Drain 下线前按顺序排空：停止心跳并反注册实例、停止分配配额、刷新统计上报，
+// ctx 到期时中止剩余的反注册，仍会尝试刷新统计上报。
+// 配置中心通过长轮询获取变更，不存在需要刷新的确认消息；与服务端的连接及流在随后的 Destroy 中关闭，不在排空范围内
+func (e *Engine) Drain(ctx context.Context) error {
+	atomic.StoreUint32(&e.draining, 1)
+	var (
+		errs         error
+		deregistered int
+	)
+	instances := e.registerStates.DrainRegistered(ctx)
+	for _, instance := range instances {
+		if err := ctx.Err(); err != nil {
+			errs = multierror.Append(errs, err)
+			break
+		}
+		req := &model.InstanceDeRegisterRequest{
+			Namespace:    instance.Namespace,
+			Service:      instance.Service,
+			ServiceToken: instance.ServiceToken,
+			InstanceID:   instance.InstanceId,
+			Host:         instance.Host,
+			Port:         instance.Port,
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			req.SetTimeout(time.Until(deadline))
+			req.SetRetryCount(0)
+		}
+		if err := e.SyncDeregister(req); err != nil {
+			log.GetBaseLogger().Errorf("[Drain] fail to deregister instance {%s, %s, %s:%d}: %v",
+				instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		deregistered++
+	}
+	for _, reporter := range e.reporterChain {
+		flusher, ok := reporter.(statreporter.Flusher)
+		if !ok {
+			continue
+		}
+		if err := flusher.Flush(); err != nil {
+			log.GetBaseLogger().Errorf("[Drain] fail to flush stat reporter %s: %v", reporter.Name(), err)
+			errs = multierror.Append(errs, err)
+		}
+	}
+	log.GetBaseLogger().Infof("[Drain] sdk context drained, %d of %d instances deregistered",
+		deregistered, len(instances))
+	return errs
+}
+
+// isDraining 是否正在下线排空
+func (e *Engine) isDraining() bool {
+	return atomic.LoadUint32(&e.draining) > 0
+}
@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// applyDrainingInstances 过滤排空中的实例：未携带亲和键的请求不再分配到排空中的实例，
+// 携带亲和键（哈希键）的请求在宽限期内仍可分配到原实例，宽限期过后的实例完全摘除；过滤后没有实例时不做过滤
+func (e *Engine) applyDrainingInstances(commonRequest *data.CommonInstancesRequest) {
+	cluster := commonRequest.Criteria.Cluster
+	if cluster == nil {
+		return
+	}
+	instances, _ := cluster.GetInstances()
+	selected := filterDrainingInstances(instances, hasAffinityKey(commonRequest), time.Now(),
+		e.configuration.GetConsumer().GetServiceRouter().GetDrainingGracePeriod())
+	if len(selected) == len(instances) {
+		return
+	}
+	if len(selected) == 0 {
+		log.GetBaseLogger().Warnf("[Draining] all instances of %s are draining, skip filter", commonRequest.DstService)
+		return
+	}
+	replaceBalanceCluster(commonRequest, selected)
+}
+
+// hasAffinityKey 请求是否携带用于会话亲和的哈希键
+func hasAffinityKey(commonRequest *data.CommonInstancesRequest) bool {
+	return len(commonRequest.Criteria.HashKey) > 0 || commonRequest.Criteria.HashValue > 0
+}
+
+// filterDrainingInstances 按排空状态过滤实例，没有排空中的实例时返回原列表
+func filterDrainingInstances(instances []model.Instance, affinity bool, now time.Time,
+	gracePeriod time.Duration) []model.Instance {
+	var selected []model.Instance
+	for i, instance := range instances {
+		state := model.GetDrainingState(instance, now, gracePeriod)
+		keep := state == model.NotDraining || (state == model.DrainingInGrace && affinity)
+		if keep && selected != nil {
+			selected = append(selected, instance)
+			continue
+		}
+		if !keep && selected == nil {
+			selected = make([]model.Instance, 0, len(instances))
+			selected = append(selected, instances[:i]...)
+		}
+	}
+	if selected == nil {
+		return instances
+	}
+	return selected
+}
@@ -0,0 +1,75 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type metadataInstance struct {
+	model.Instance
+	id       string
+	metadata map[string]string
+}
+
+func (m *metadataInstance) GetId() string {
+	return m.id
+}
+
+func (m *metadataInstance) GetMetadata() map[string]string {
+	return m.metadata
+}
+
+// TestFilterDrainingInstances 测试排空中实例只在宽限期内承接携带亲和键的请求
+func TestFilterDrainingInstances(t *testing.T) {
+	now := time.Now()
+	instances := []model.Instance{
+		&metadataInstance{id: "a"},
+		&metadataInstance{id: "b", metadata: map[string]string{
+			model.DrainingMetadata: model.DrainingMetadataValue(now.Add(-time.Minute))}},
+		&metadataInstance{id: "c", metadata: map[string]string{
+			model.DrainingMetadata: model.DrainingMetadataValue(now.Add(-time.Hour))}},
+	}
+	ids := func(instances []model.Instance) string {
+		var s string
+		for _, instance := range instances {
+			s += instance.GetId()
+		}
+		return s
+	}
+	if s := ids(filterDrainingInstances(instances, false, now, 5*time.Minute)); s != "a" {
+		t.Fatalf("expect only a for new sessions, got %s", s)
+	}
+	if s := ids(filterDrainingInstances(instances, true, now, 5*time.Minute)); s != "ab" {
+		t.Fatalf("expect a and b for existing sessions, got %s", s)
+	}
+	if s := ids(filterDrainingInstances(instances[:1], false, now, 5*time.Minute)); s != "a" {
+		t.Fatalf("expect origin instances, got %s", s)
+	}
+	invalid := &metadataInstance{id: "d", metadata: map[string]string{model.DrainingMetadata: "now"}}
+	if model.GetDrainingState(invalid, now, time.Minute) != model.DrainingInGrace {
+		t.Fatal("expect unparsable draining time treated as just started")
+	}
+	if _, err := strconv.ParseInt(model.DrainingMetadataValue(now), 10, 64); err != nil {
+		t.Fatal(err)
+	}
+}
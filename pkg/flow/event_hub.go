@@ -0,0 +1,205 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// eventHub SDK事件的统一分发中心，将插件事件转换为类型化的事件投递给订阅者
+type eventHub struct {
+	lock        sync.RWMutex
+	subscribers map[model.EventKind]map[*model.EventSubscriber]struct{}
+	destroyed   bool
+	done        chan struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers: make(map[model.EventKind]map[*model.EventSubscriber]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// subscribe 添加订阅者，ctx取消或者分发中心销毁时自动取消订阅
+func (h *eventHub) subscribe(ctx context.Context, subscriber *model.EventSubscriber) error {
+	if subscriber == nil || subscriber.Deliver == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "event subscriber deliver func is nil")
+	}
+	h.lock.Lock()
+	if h.destroyed {
+		h.lock.Unlock()
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil, "event hub has been destroyed")
+	}
+	subscribers, ok := h.subscribers[subscriber.Kind]
+	if !ok {
+		subscribers = make(map[*model.EventSubscriber]struct{})
+		h.subscribers[subscriber.Kind] = subscribers
+	}
+	subscribers[subscriber] = struct{}{}
+	h.lock.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.unsubscribe(subscriber)
+		case <-h.done:
+		}
+	}()
+	return nil
+}
+
+func (h *eventHub) unsubscribe(subscriber *model.EventSubscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	subscribers := h.subscribers[subscriber.Kind]
+	if _, ok := subscribers[subscriber]; !ok {
+		return
+	}
+	delete(subscribers, subscriber)
+	closeSubscriber(subscriber)
+}
+
+// publish 投递事件，持有读锁保证取消订阅之后不再投递，订阅者的 Deliver 必须是非阻塞的，
+// 阻塞的 Deliver 会拖住发布事件的流程以及需要写锁的取消订阅
+func (h *eventHub) publish(kind model.EventKind, event interface{}) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	for subscriber := range h.subscribers[kind] {
+		subscriber.Deliver(event)
+	}
+}
+
+func (h *eventHub) hasSubscribers(kind model.EventKind) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return len(h.subscribers[kind]) > 0
+}
+
+// destroy 取消所有订阅
+func (h *eventHub) destroy() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.destroyed {
+		return
+	}
+	h.destroyed = true
+	close(h.done)
+	for _, subscribers := range h.subscribers {
+		for subscriber := range subscribers {
+			closeSubscriber(subscriber)
+		}
+	}
+	h.subscribers = make(map[model.EventKind]map[*model.EventSubscriber]struct{})
+}
+
+func closeSubscriber(subscriber *model.EventSubscriber) {
+	if subscriber.Close != nil {
+		subscriber.Close()
+	}
+}
+
+// onServiceEvent 服务缓存变更回调，转换为实例变更以及限流规则变更事件
+func (h *eventHub) onServiceEvent(event *common.PluginEvent) error {
+	svcEvent, ok := event.EventObject.(*common.ServiceEventObject)
+	if !ok {
+		return nil
+	}
+	switch svcEvent.SvcEventKey.Type {
+	case model.EventInstances:
+		if !h.hasSubscribers(model.EventKindInstancesChange) {
+			return nil
+		}
+		h.publish(model.EventKindInstancesChange, model.InstancesChangeEvent{
+			Service:  svcEvent.SvcEventKey.ServiceKey,
+			Revision: revisionOf(svcEvent.NewValue),
+			InstanceEvent: &model.InstanceEvent{
+				AddEvent:    data.CheckAddInstances(svcEvent),
+				UpdateEvent: data.CheckUpdateInstances(svcEvent),
+				DeleteEvent: data.CheckDeleteInstances(svcEvent),
+			},
+		})
+	case model.EventRateLimiting:
+		if !h.hasSubscribers(model.EventKindRateLimitRule) {
+			return nil
+		}
+		h.publish(model.EventKindRateLimitRule, model.RateLimitRuleEvent{
+			Service:  svcEvent.SvcEventKey.ServiceKey,
+			Revision: revisionOf(svcEvent.NewValue),
+			Deleted:  event.EventType == common.OnServiceDeleted,
+		})
+	}
+	return nil
+}
+
+// onCircuitBreakerEvent 熔断状态变更回调
+func (h *eventHub) onCircuitBreakerEvent(event *common.PluginEvent) error {
+	cbEvent, ok := event.EventObject.(*model.CircuitBreakerEvent)
+	if !ok {
+		return nil
+	}
+	h.publish(model.EventKindCircuitBreaker, *cbEvent)
+	return nil
+}
+
+// onCachePreloadEvent 持久化缓存加载进度回调
+func (h *eventHub) onCachePreloadEvent(event *common.PluginEvent) error {
+	preloadEvent, ok := event.EventObject.(*model.CachePreloadEvent)
+	if !ok {
+		return nil
+	}
+	h.publish(model.EventKindCachePreload, *preloadEvent)
+	return nil
+}
+
+// onMemoryShedEvent 缓存内存降载模式变更回调
+func (h *eventHub) onMemoryShedEvent(event *common.PluginEvent) error {
+	shedEvent, ok := event.EventObject.(*model.MemoryShedEvent)
+	if !ok {
+		return nil
+	}
+	h.publish(model.EventKindMemoryShed, *shedEvent)
+	return nil
+}
+
+// onConfigFileChange 已订阅配置文件的变更回调
+func (h *eventHub) onConfigFileChange(event model.ConfigFileChangeEvent) {
+	h.publish(model.EventKindConfigChange, event)
+}
+
+func revisionOf(value interface{}) string {
+	if revisioned, ok := value.(interface{ GetRevision() string }); ok {
+		return revisioned.GetRevision()
+	}
+	return ""
+}
+
+// SubscribeEvents 订阅SDK事件，ctx取消或者SDK销毁时自动取消订阅
+func (e *Engine) SubscribeEvents(ctx context.Context, subscriber *model.EventSubscriber) error {
+	if err := e.events.subscribe(ctx, subscriber); err != nil {
+		return err
+	}
+	log.GetBaseLogger().Infof("[Events] subscribe %s events", subscriber.Kind)
+	return nil
+}
@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+func TestEventHub(t *testing.T) {
+	hub := newEventHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan interface{}, 4)
+	closed := make(chan struct{})
+	subscriber := &model.EventSubscriber{
+		Kind:    model.EventKindRateLimitRule,
+		Deliver: func(event interface{}) { received <- event },
+		Close:   func() { close(closed) },
+	}
+	if err := hub.subscribe(ctx, subscriber); err != nil {
+		t.Fatal(err)
+	}
+
+	svcKey := model.ServiceKey{Namespace: "default", Service: "svc"}
+	_ = hub.onServiceEvent(&common.PluginEvent{
+		EventType: common.OnServiceDeleted,
+		EventObject: &common.ServiceEventObject{
+			SvcEventKey: model.ServiceEventKey{ServiceKey: svcKey, Type: model.EventRateLimiting},
+		},
+	})
+	// 没有订阅者的事件类型不投递
+	hub.onConfigFileChange(model.ConfigFileChangeEvent{})
+	if len(received) != 1 {
+		t.Fatalf("expect 1 event, got %d", len(received))
+	}
+	event := (<-received).(model.RateLimitRuleEvent)
+	if event.Service != svcKey || !event.Deleted {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	cancel()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expect subscriber closed after ctx cancel")
+	}
+	if hub.hasSubscribers(model.EventKindRateLimitRule) {
+		t.Fatal("expect subscriber removed")
+	}
+
+	hub.destroy()
+	if err := hub.subscribe(context.Background(), subscriber); err == nil {
+		t.Fatal("expect error after destroy")
+	}
+}
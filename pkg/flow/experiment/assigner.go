@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package experiment 基于配置中心下发的实验定义进行A/B实验分组，分桶算法与灰度分桶路由一致，
+// 使产品实验与流量路由共用同一份分组结果
+package experiment
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/expr"
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// compiledExperiment 预处理后的实验定义
+type compiledExperiment struct {
+	definition *model.Experiment
+	salt       string
+	targeting  *expr.Program
+	// 进入实验的桶数，桶号小于该值的单元进入实验
+	trafficBuckets uint32
+	// 各分组占用桶区间的上界，分组i占用 [bounds[i-1], bounds[i])
+	bounds []uint32
+}
+
+// Assigner 实验分组器，实验定义可并发更新
+type Assigner struct {
+	// map[string]*compiledExperiment
+	experiments atomic.Value
+}
+
+// NewAssigner 创建分组器
+func NewAssigner() *Assigner {
+	a := &Assigner{}
+	a.experiments.Store(map[string]*compiledExperiment{})
+	return a
+}
+
+// Update 解析并替换实验定义，解析或校验失败时保留原有定义
+func (a *Assigner) Update(content string) error {
+	definitions := &model.ExperimentDefinitions{}
+	if err := yaml.Unmarshal([]byte(content), definitions); err != nil {
+		return fmt.Errorf("parse experiment definitions: %w", err)
+	}
+	if err := definitions.Verify(); err != nil {
+		return err
+	}
+	experiments := make(map[string]*compiledExperiment, len(definitions.Experiments))
+	for _, definition := range definitions.Experiments {
+		compiled, err := compile(definition)
+		if err != nil {
+			return err
+		}
+		experiments[definition.Key] = compiled
+	}
+	a.experiments.Store(experiments)
+	return nil
+}
+
+// Keys 当前生效的实验标识
+func (a *Assigner) Keys() []string {
+	experiments := a.experiments.Load().(map[string]*compiledExperiment)
+	keys := make([]string, 0, len(experiments))
+	for key := range experiments {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Assign 计算实验单元的分组，实验不存在时返回错误
+func (a *Assigner) Assign(experimentKey, unitID string, labels map[string]string) (*model.ExperimentAssignment, error) {
+	experiments := a.experiments.Load().(map[string]*compiledExperiment)
+	experiment, ok := experiments[experimentKey]
+	if !ok {
+		return nil, fmt.Errorf("experiment %s not found", experimentKey)
+	}
+	assignment := &model.ExperimentAssignment{
+		ExperimentKey: experimentKey,
+		UnitID:        unitID,
+		Bucket:        hash.Bucket(experiment.salt, unitID),
+	}
+	if experiment.definition.Disable {
+		assignment.Reason = model.ExperimentExcludeDisabled
+		return assignment, nil
+	}
+	if experiment.targeting != nil {
+		matched, err := experiment.targeting.Match(expr.NewLabelsActivation(labels))
+		if err != nil {
+			log.GetBaseLogger().Debugf("[Experiment] fail to eval targeting of %s, err: %v", experimentKey, err)
+		}
+		if !matched {
+			assignment.Reason = model.ExperimentExcludeTargeting
+			return assignment, nil
+		}
+	}
+	if assignment.Bucket >= experiment.trafficBuckets {
+		assignment.Reason = model.ExperimentExcludeTraffic
+		return assignment, nil
+	}
+	for i, bound := range experiment.bounds {
+		if assignment.Bucket < bound {
+			assignment.Variant = experiment.definition.Variants[i].Name
+			break
+		}
+	}
+	return assignment, nil
+}
+
+func compile(definition *model.Experiment) (*compiledExperiment, error) {
+	compiled := &compiledExperiment{
+		definition:     definition,
+		salt:           definition.GetSalt(),
+		trafficBuckets: hash.PercentToBuckets(definition.Traffic),
+		bounds:         make([]uint32, len(definition.Variants)),
+	}
+	if len(definition.Targeting) > 0 {
+		program, err := expr.CompileCached(definition.Targeting)
+		if err != nil {
+			return nil, fmt.Errorf("experiment %s targeting: %w", definition.Key, err)
+		}
+		compiled.targeting = program
+	}
+	var totalWeight, accWeight uint64
+	for _, variant := range definition.Variants {
+		totalWeight += uint64(variant.Weight)
+	}
+	for i, variant := range definition.Variants {
+		accWeight += uint64(variant.Weight)
+		compiled.bounds[i] = uint32(uint64(compiled.trafficBuckets) * accWeight / totalWeight)
+	}
+	return compiled, nil
+}
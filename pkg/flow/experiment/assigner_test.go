@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package experiment
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const definitions = `
+experiments:
+  - key: checkout
+    salt: gray-checkout
+    traffic: 40
+    targeting: 'labels.region in ["sz", "gz"]'
+    variants:
+      - name: treatment
+        weight: 1
+      - name: control
+        weight: 1
+  - key: banner
+    disable: true
+    traffic: 100
+    variants:
+      - name: a
+        weight: 1
+`
+
+func TestAssign(t *testing.T) {
+	assigner := NewAssigner()
+	if err := assigner.Update(definitions); err != nil {
+		t.Fatal(err)
+	}
+	labels := map[string]string{"region": "sz"}
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		uid := strconv.Itoa(i)
+		assignment, err := assigner.Assign("checkout", uid, labels)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[assignment.Variant]++
+		// 第一个分组与相同盐值、百分比为 traffic*weight 的灰度分桶结果一致
+		if (assignment.Variant == "treatment") != hash.InBucket("gray-checkout", uid, 20) {
+			t.Fatalf("unit %s assignment %s inconsistent with gray bucket", uid, assignment.Variant)
+		}
+	}
+	for variant, expect := range map[string]int{"treatment": 2000, "control": 2000, "": 6000} {
+		if counts[variant] < expect*9/10 || counts[variant] > expect*11/10 {
+			t.Errorf("variant %q count %d, expect about %d", variant, counts[variant], expect)
+		}
+	}
+
+	assignment, _ := assigner.Assign("checkout", "1", map[string]string{"region": "sh"})
+	if assignment.InExperiment() || assignment.Reason != model.ExperimentExcludeTargeting {
+		t.Errorf("expect excluded by targeting, got %+v", assignment)
+	}
+	assignment, _ = assigner.Assign("banner", "1", nil)
+	if assignment.InExperiment() || assignment.Reason != model.ExperimentExcludeDisabled {
+		t.Errorf("expect excluded by disable, got %+v", assignment)
+	}
+	if _, err := assigner.Assign("unknown", "1", nil); err == nil {
+		t.Error("expect error for unknown experiment")
+	}
+
+	// 非法定义不替换原有定义
+	if err := assigner.Update("experiments:\n  - key: checkout\n    traffic: 120\n"); err == nil {
+		t.Fatal("expect verify error")
+	}
+	if _, err := assigner.Assign("checkout", "1", labels); err != nil {
+		t.Errorf("previous definitions should be kept: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// ReportExternalHealth 上报应用自行判定的实例健康状态：
+// 判定结果会作为一次调用结果进入熔断统计，同时不健康的实例会被直接熔断，直到应用上报其恢复健康
+func (e *CircuitBreakerFlow) ReportExternalHealth(report *model.ExternalHealthReport) error {
+	if e.resourceBreaker == nil {
+		return model.NewSDKError(model.ErrCodeInternalError, nil, "circuitbreaker not found")
+	}
+	svcKey := report.ServiceKey
+	resource, err := model.NewInstanceResource(&svcKey, nil, "", report.Host, uint32(report.Port))
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid instance %s", report.InstanceKey)
+	}
+	retStatus := model.RetSuccess
+	if !report.Healthy {
+		retStatus = model.RetFail
+	}
+	if err := e.resourceBreaker.Report(&model.ResourceStat{
+		Resource:  resource,
+		RetCode:   model.ExternalHealthRuleName,
+		RetStatus: retStatus,
+	}); err != nil {
+		return err
+	}
+	if !report.Healthy {
+		status := model.NewCircuitBreakerStatus(externalHealthBreakerName(report.Reason), model.Open, time.Now())
+		e.externalUnhealthy.Store(report.InstanceKey, status)
+		log.GetBaseLogger().Infof("[CircuitBreaker] instance %s reported unhealthy by application, reason: %s",
+			report.InstanceKey, report.Reason)
+		return e.updateInstanceStatus(resource, status)
+	}
+	if _, ok := e.externalUnhealthy.Load(report.InstanceKey); !ok {
+		return nil
+	}
+	e.externalUnhealthy.Delete(report.InstanceKey)
+	log.GetBaseLogger().Infof("[CircuitBreaker] instance %s reported healthy by application, reason: %s",
+		report.InstanceKey, report.Reason)
+	// 恢复为熔断插件自身判定的状态
+	status := e.resourceBreaker.CheckResource(resource)
+	if status == nil {
+		status = model.NewCircuitBreakerStatus(model.ExternalHealthRuleName, model.Close, time.Now())
+	}
+	return e.updateInstanceStatus(resource, status)
+}
+
+// loadExternalUnhealthy 查询实例是否被应用上报为不健康
+func (e *CircuitBreakerFlow) loadExternalUnhealthy(resource model.Resource) (model.CircuitBreakerStatus, bool) {
+	insRes, ok := resource.(*model.InstanceResource)
+	if !ok {
+		return nil, false
+	}
+	key := model.InstanceKey{
+		ServiceKey: *insRes.GetService(),
+		Host:       insRes.GetNode().Host,
+		Port:       int(insRes.GetNode().Port),
+	}
+	value, ok := e.externalUnhealthy.Load(key)
+	if !ok {
+		return nil, false
+	}
+	return value.(model.CircuitBreakerStatus), true
+}
+
+// updateInstanceStatus 更新本地缓存中实例的熔断状态，使路由及负载均衡感知到应用的判定结果
+func (e *CircuitBreakerFlow) updateInstanceStatus(resource *model.InstanceResource, status model.CircuitBreakerStatus) error {
+	if e.engine == nil || e.engine.registry == nil {
+		return nil
+	}
+	return e.engine.registry.UpdateInstances(&localregistry.ServiceUpdateRequest{
+		ServiceKey: *resource.GetService(),
+		Properties: []localregistry.InstanceProperties{
+			{
+				Host:       resource.GetNode().Host,
+				Port:       resource.GetNode().Port,
+				Service:    resource.GetService(),
+				Properties: map[string]interface{}{localregistry.PropertyCircuitBreakerStatus: status},
+			},
+		},
+	})
+}
+
+func externalHealthBreakerName(reason string) string {
+	if len(reason) == 0 {
+		return model.ExternalHealthRuleName
+	}
+	return fmt.Sprintf("%s(%s)", model.ExternalHealthRuleName, reason)
+}
@@ -0,0 +1,71 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+)
+
+// stubBreaker 记录上报结果的熔断插件
+type stubBreaker struct {
+	circuitbreaker.CircuitBreaker
+	stats []*model.ResourceStat
+}
+
+func (s *stubBreaker) CheckResource(model.Resource) model.CircuitBreakerStatus {
+	return nil
+}
+
+func (s *stubBreaker) Report(stat *model.ResourceStat) error {
+	s.stats = append(s.stats, stat)
+	return nil
+}
+
+// TestReportExternalHealth 测试应用上报的健康状态参与熔断判定
+func TestReportExternalHealth(t *testing.T) {
+	breaker := &stubBreaker{}
+	cbFlow := &CircuitBreakerFlow{resourceBreaker: breaker, bulkheads: newBulkheadManager(nil)}
+	key := model.InstanceKey{ServiceKey: model.ServiceKey{Namespace: "default", Service: "svc"}, Host: "127.0.0.1", Port: 8080}
+	res, _ := model.NewInstanceResource(&key.ServiceKey, nil, "", key.Host, uint32(key.Port))
+
+	err := cbFlow.ReportExternalHealth(&model.ExternalHealthReport{InstanceKey: key, Healthy: false, Reason: "lag"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaker.stats) != 1 || breaker.stats[0].RetStatus != model.RetFail {
+		t.Fatalf("unhealthy verdict should be reported as failure, got %v", breaker.stats)
+	}
+	result, _ := cbFlow.Check(res)
+	if result.Pass {
+		t.Fatal("instance reported unhealthy should not pass")
+	}
+
+	if err = cbFlow.ReportExternalHealth(&model.ExternalHealthReport{InstanceKey: key, Healthy: true}); err != nil {
+		t.Fatal(err)
+	}
+	if breaker.stats[1].RetStatus != model.RetSuccess {
+		t.Fatal("healthy verdict should be reported as success")
+	}
+	result, _ = cbFlow.Check(res)
+	if !result.Pass {
+		t.Fatal("instance reported healthy should pass")
+	}
+}
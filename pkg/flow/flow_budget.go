@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// checkFlowBudget 记录流程步骤的耗时，预算在该步骤耗尽时上报统计并返回超时错误，未设置预算时直接返回
+func (e *Engine) checkFlowBudget(req *data.CommonInstancesRequest, step model.FlowStep) error {
+	budget := req.FlowBudget
+	if budget == nil || budget.Mark(step, e.globalCtx.Now()) {
+		return nil
+	}
+	log.GetBaseLogger().Warnf("[FlowBudget] budget %v exhausted at step %s, service %s, steps %v",
+		budget.Budget(), step, req.DstService, budget.Steps())
+	_ = e.SyncReportStat(model.FlowBudgetStat, &model.FlowBudgetGauge{
+		Namespace: req.DstService.Namespace,
+		Service:   req.DstService.Service,
+		API:       req.CallResult.APIName,
+		Step:      step,
+		Budget:    budget.Budget(),
+	})
+	return model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+		"flow budget %v exhausted at step %s, service %s", budget.Budget(), step, req.DstService)
+}
+
+// flowTrace 返回设置了流程预算时各步骤的耗时
+func flowTrace(req *data.CommonInstancesRequest) []model.FlowStepCost {
+	if req.FlowBudget == nil {
+		return nil
+	}
+	steps := req.FlowBudget.Steps()
+	trace := make([]model.FlowStepCost, len(steps))
+	copy(trace, steps)
+	return trace
+}
@@ -0,0 +1,65 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// forceRouteInstance 调试用的强制路由，跳过路由及负载均衡直接返回缓存中地址匹配的实例，
+// 实例不存在或者获取失败时返回nil，由调用方继续走正常流程
+func (e *Engine) forceRouteInstance(
+	startTime time.Time, commonRequest *data.CommonInstancesRequest) *model.OneInstanceResponse {
+	if err := e.SyncGetResources(commonRequest); err != nil || commonRequest.DstInstances == nil {
+		return nil
+	}
+	if instance := findForceRouteInstance(commonRequest.DstInstances.GetInstances(),
+		commonRequest.ForceHostPort); instance != nil {
+		log.GetBaseLogger().Infof("[ForceRoute] flowId %d, namespace %s, service %s, force route to instance %s(%s)",
+			commonRequest.FlowID, commonRequest.DstService.Namespace, commonRequest.DstService.Service,
+			commonRequest.ForceHostPort, instance.GetId())
+		(&commonRequest.CallResult).SetSuccess(e.globalCtx.Since(startTime))
+		instancesResp := commonRequest.BuildInstancesResponse(commonRequest.DstService, nil,
+			[]model.Instance{instance}, 0, commonRequest.DstInstances)
+		return &model.OneInstanceResponse{InstancesResponse: *instancesResp}
+	}
+	log.GetBaseLogger().Warnf("[ForceRoute] flowId %d, namespace %s, service %s, instance %s not found, "+
+		"fallback to normal routing", commonRequest.FlowID, commonRequest.DstService.Namespace,
+		commonRequest.DstService.Service, commonRequest.ForceHostPort)
+	return nil
+}
+
+// findForceRouteInstance 查找地址与强制路由地址匹配的实例，地址非法或者不存在时返回nil
+func findForceRouteInstance(instances []model.Instance, hostPort string) model.Instance {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return nil
+	}
+	for _, instance := range instances {
+		if instance.GetHost() == host && strconv.Itoa(int(instance.GetPort())) == port {
+			return instance
+		}
+	}
+	return nil
+}
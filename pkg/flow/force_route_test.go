@@ -0,0 +1,44 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestFindForceRouteInstance 测试按 host:port 查找强制路由的实例，不存在或者地址非法时返回空
+func TestFindForceRouteInstance(t *testing.T) {
+	instances := []model.Instance{
+		&fakeInstance{host: "10.0.0.1", port: 8080},
+		&fakeInstance{host: "10.0.0.2", port: 8080},
+		&fakeInstance{host: "::1", port: 8080},
+	}
+	if instance := findForceRouteInstance(instances, "10.0.0.2:8080"); instance != instances[1] {
+		t.Fatalf("expect 10.0.0.2:8080 pinned, got %v", instance)
+	}
+	if instance := findForceRouteInstance(instances, "[::1]:8080"); instance != instances[2] {
+		t.Fatalf("expect [::1]:8080 pinned, got %v", instance)
+	}
+	for _, hostPort := range []string{"10.0.0.2:9090", "10.0.0.3:8080", "10.0.0.1"} {
+		if instance := findForceRouteInstance(instances, hostPort); instance != nil {
+			t.Fatalf("expect no instance for %s, got %v", hostPort, instance)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// shutdownPollInterval 优雅下线时检查在途请求数的间隔
+const shutdownPollInterval = 100 * time.Millisecond
+
+// TrackServerRequest 记录一个开始处理的服务端请求，请求处理完成时调用返回的函数，用于优雅下线时等待在途请求
+func (e *Engine) TrackServerRequest() func() {
+	atomic.AddInt32(&e.serverInflight, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&e.serverInflight, -1)
+		})
+	}
+}
+
+// GracefulShutdown 优雅下线：将自动心跳的实例隔离，等待在途请求处理完成（最长 provider.shutdownDrainPeriod），
+// 再执行 Drain 反注册实例并刷新统计上报；ctx 到期时停止等待并中止剩余的反注册
+func (e *Engine) GracefulShutdown(ctx context.Context) error {
+	var errs error
+	isolated, err := e.registerStates.IsolateRegistered(e.doSyncRegister)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	start := time.Now()
+	inflight := e.waitServerRequests(ctx, e.configuration.GetProvider().GetShutdownDrainPeriod())
+	if inflight > 0 {
+		log.GetBaseLogger().Warnf("[GracefulShutdown] %d requests still in flight after %v, deregister anyway",
+			inflight, time.Since(start))
+	} else {
+		log.GetBaseLogger().Infof("[GracefulShutdown] %d instances isolated, in flight requests drained in %v",
+			isolated, time.Since(start))
+	}
+	if err := e.Drain(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
+
+// waitServerRequests 等待在途请求数归零，返回等待结束时的在途请求数
+func (e *Engine) waitServerRequests(ctx context.Context, period time.Duration) int32 {
+	timer := time.NewTimer(period)
+	defer timer.Stop()
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		inflight := atomic.LoadInt32(&e.serverInflight)
+		if inflight <= 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return inflight
+		case <-timer.C:
+			return inflight
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWaitServerRequests 测试优雅下线时等待在途请求
+func TestWaitServerRequests(t *testing.T) {
+	e := &Engine{}
+	if inflight := e.waitServerRequests(context.Background(), time.Second); inflight != 0 {
+		t.Fatalf("expect no inflight, got %d", inflight)
+	}
+
+	done := e.TrackServerRequest()
+	if inflight := e.waitServerRequests(context.Background(), 2*shutdownPollInterval); inflight != 1 {
+		t.Fatalf("expect 1 inflight after period, got %d", inflight)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if inflight := e.waitServerRequests(ctx, time.Minute); inflight != 1 {
+		t.Fatalf("expect 1 inflight after ctx done, got %d", inflight)
+	}
+
+	go func() {
+		time.Sleep(shutdownPollInterval)
+		done()
+		done()
+	}()
+	if inflight := e.waitServerRequests(context.Background(), time.Minute); inflight != 0 {
+		t.Fatalf("expect inflight drained, got %d", inflight)
+	}
+	if e.serverInflight != 0 {
+		t.Fatalf("expect done to be idempotent, got %d", e.serverInflight)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/configuration"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/flow/dnsserver"
 	"github.com/polarismesh/polaris-go/pkg/flow/quota"
 	"github.com/polarismesh/polaris-go/pkg/flow/registerstate"
 	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
@@ -86,6 +87,44 @@ type Engine struct {
 	watchEngine *WatchEngine
 	// 配置过滤链
 	configFilterChain configfilter.Chain
+	// 缓存预热任务
+	warmUp *cacheWarmUp
+	// 负载均衡前后执行的实例选择钩子
+	selectorHooks selectorHooks
+	// 根据配置创建的实例地址转换器，未配置时为nil
+	configAddressTranslator model.AddressTranslator
+	// 配置的服务名映射规则
+	configNameMapper *configNameMapper
+	// 未被服务端确认的自注册实例
+	provisional provisionalInstances
+	// 最近一次注册成功的自身实例，用于规则路由的自身标签匹配
+	selfLabels selfLabels
+	// 成本归属标签，附加到调用结果以及限流上报中
+	costLabels map[string]string
+	// 对外返回过的实例版本，用于计算增量变更
+	instancesHistory instancesHistory
+	// 是否正在下线排空，1表示排空中
+	draining uint32
+	// 是否处于lameduck状态，1表示已进入
+	lameduck uint32
+	// 正在处理的服务端请求数，优雅下线时等待归零
+	serverInflight int32
+	// 最近的API调用记录，用于故障排查
+	recentCalls *recentCalls
+	// 服务级及命名空间级配置对应的插件
+	namespaceSpecific *namespaceSpecific
+	// SDK事件分发中心
+	events *eventHub
+	// 外部金丝雀分析控制器设置的本地流量比例
+	trafficSplits trafficSplits
+	// 内置DNS服务
+	dnsServer *dnsserver.Server
+	// 契约校验使用的被调服务契约缓存
+	serviceContracts serviceContracts
+	// 客户端到被调服务的连接数限制
+	connectionLimits connectionLimits
+	// 外部控制器设置的本地实例权重
+	weightOverrides weightOverrides
 }
 
 // InitFlowEngine 初始化flowEngine实例
@@ -96,6 +135,8 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	globalCtx := initContext.ValueCtx
 	flowEngine.configuration = cfg
 	flowEngine.plugins = plugins
+	flowEngine.costLabels = cfg.GetGlobal().GetStatReporter().GetCostLabels()
+	flowEngine.recentCalls = newRecentCalls(cfg.GetGlobal().GetAPI().GetRecentCallsSize())
 	// 加载服务端连接器
 	flowEngine.connector, err = data.GetServerConnector(cfg, plugins)
 	if err != nil {
@@ -115,7 +156,8 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	}
 
 	// 加载配置中心连接器
-	if len(cfg.GetConfigFile().GetConfigConnectorConfig().GetAddresses()) > 0 {
+	if len(cfg.GetConfigFile().GetConfigConnectorConfig().GetAddresses()) > 0 ||
+		len(cfg.GetConfigFile().GetConfigConnectorConfig().GetEndpoints()) > 0 {
 		flowEngine.configConnector, err = data.GetConfigConnector(cfg, plugins)
 		if err != nil {
 			return err
@@ -142,7 +184,7 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 		return err
 	}
 	// 加载熔断器插件
-	if enable := cfg.GetConsumer().GetCircuitBreaker().IsEnable(); enable {
+	if enable := isCircuitBreakerRequired(cfg); enable {
 		breakers, err := data.GetCircuitBreakers(cfg, flowEngine.plugins)
 		if err != nil {
 			return err
@@ -162,6 +204,26 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 	}
 	initContext.Plugins.RegisterEventSubscriber(common.OnServiceAdded, callbackHandler)
 	initContext.Plugins.RegisterEventSubscriber(common.OnServiceUpdated, callbackHandler)
+	flowEngine.events = newEventHub()
+	eventsHandler := common.PluginEventHandler{Callback: flowEngine.events.onServiceEvent}
+	initContext.Plugins.RegisterEventSubscriber(common.OnServiceAdded, eventsHandler)
+	initContext.Plugins.RegisterEventSubscriber(common.OnServiceUpdated, eventsHandler)
+	initContext.Plugins.RegisterEventSubscriber(common.OnServiceDeleted, eventsHandler)
+	initContext.Plugins.RegisterEventSubscriber(common.OnCircuitBreakerStatusChanged,
+		common.PluginEventHandler{Callback: flowEngine.events.onCircuitBreakerEvent})
+	initContext.Plugins.RegisterEventSubscriber(common.OnCachePreloadProgress,
+		common.PluginEventHandler{Callback: flowEngine.events.onCachePreloadEvent})
+	initContext.Plugins.RegisterEventSubscriber(common.OnMemoryShedChanged,
+		common.PluginEventHandler{Callback: flowEngine.events.onMemoryShedEvent})
+	if flowEngine.circuitBreakerFlow != nil && flowEngine.circuitBreakerFlow.quarantine != nil {
+		quarantine := flowEngine.circuitBreakerFlow.quarantine
+		initContext.Plugins.RegisterEventSubscriber(common.OnCircuitBreakerStatusChanged,
+			common.PluginEventHandler{Callback: quarantine.onCircuitBreakerEvent})
+		initContext.Plugins.RegisterEventSubscriber(common.OnServiceAdded,
+			common.PluginEventHandler{Callback: quarantine.onServiceEvent})
+	}
+	flowEngine.configAddressTranslator = newConfigAddressTranslator(cfg.GetConsumer().GetAddressTranslation())
+	flowEngine.configNameMapper = newConfigNameMapper(cfg.GetConsumer().GetNameMapping())
 	globalCtx.SetValue(model.ContextKeyEngine, flowEngine)
 
 	// 初始化配置中心服务
@@ -170,11 +232,18 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 		if err != nil {
 			return err
 		}
+		configFlow.SetChangeObserver(flowEngine.events.onConfigFileChange)
+		configFlow.SetStatReporter(flowEngine.SyncReportStat)
 		flowEngine.configFlow = configFlow
 	}
 
 	// 初始注册状态管理器
-	flowEngine.registerStates = registerstate.NewRegisterStateManager(flowEngine.configuration.GetProvider().GetMinRegisterInterval())
+	flowEngine.registerStates = registerstate.NewRegisterStateManager(
+		flowEngine.configuration.GetProvider().GetMinRegisterInterval(),
+		flowEngine.configuration.GetProvider().GetFlapping(),
+		func(gauge *model.RegisterFlappingGauge) {
+			_ = flowEngine.SyncReportStat(model.RegisterFlappingStat, gauge)
+		})
 	return nil
 }
 
@@ -207,7 +276,7 @@ func (e *Engine) LoadFlowRouteChain() error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return e.loadNamespaceSpecific()
 }
 
 // FlowQuotaAssistant 获取流程辅助类
@@ -289,9 +358,27 @@ func (e *Engine) Start() error {
 	schedule.StartTask(
 		taskConfigReport, configReportTaskValues, map[interface{}]model.TaskValue{
 			taskConfigReport: &data.AllEqualsComparable{}})
+	// 按照预热清单加载服务缓存
+	e.startWarmUp()
+	// 启动内置DNS服务
+	if dnsCfg := e.configuration.GetConsumer().GetDNSServer(); dnsCfg.IsEnable() {
+		e.dnsServer = dnsserver.NewServer(dnsCfg, e.resolveForDNS)
+		if err = e.dnsServer.Start(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// resolveForDNS 内置DNS服务查询服务实例，返回经过路由及健康过滤的实例
+func (e *Engine) resolveForDNS(namespace, service string) ([]model.Instance, error) {
+	resp, err := e.SyncGetInstances(&model.GetInstancesRequest{Namespace: namespace, Service: service})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetInstances(), nil
+}
+
 // getRouterChain 根据服务获取路由链
 func (e *Engine) getRouterChain(svcInstances model.ServiceInstances) *servicerouter.RouterChain {
 	svcInstancesProto, ok := svcInstances.(*pb.ServiceInstancesInProto)
@@ -301,6 +388,10 @@ func (e *Engine) getRouterChain(svcInstances model.ServiceInstances) *servicerou
 			return routerChain
 		}
 	}
+	svcKey := model.ServiceKey{Namespace: svcInstances.GetNamespace(), Service: svcInstances.GetService()}
+	if routerChain := e.getSpecificRouterChain(svcKey); routerChain != nil {
+		return routerChain
+	}
 	return e.routerChain
 }
 
@@ -316,6 +407,10 @@ func (e *Engine) getLoadBalancer(svcInstances model.ServiceInstances, chooseAlgo
 		}
 	}
 	if chooseAlgorithm == "" {
+		svcKey := model.ServiceKey{Namespace: svcInstances.GetNamespace(), Service: svcInstances.GetService()}
+		if balancer := e.getSpecificLoadBalancer(svcKey); balancer != nil {
+			return balancer, nil
+		}
 		return e.loadbalancer, nil
 	}
 	return data.GetLoadBalancerByLbType(chooseAlgorithm, e.plugins)
@@ -334,6 +429,15 @@ func (e *Engine) Destroy() error {
 	if e.configFlow != nil {
 		e.configFlow.Destroy()
 	}
+	if e.events != nil {
+		e.events.destroy()
+	}
+	if e.dnsServer != nil {
+		e.dnsServer.Stop()
+	}
+	if e.configNameMapper != nil {
+		e.configNameMapper.destroy()
+	}
 	e.registerStates.Destroy()
 	return nil
 }
@@ -355,6 +459,7 @@ func (e *Engine) SyncReportStat(typ model.MetricType, stat model.InstanceGauge)
 
 // reportAPIStat 上报api数据
 func (e *Engine) reportAPIStat(result *model.APICallResult) error {
+	e.recentCalls.add(result)
 	// TODO: SDK 本身和北极星 server 的服务调用监控数据不能和用户的监控数据混合在一起，这里可以打印在本地日志中
 	// return e.SyncReportStat(model.SDKAPIStat, result)
 	return nil
@@ -362,6 +467,9 @@ func (e *Engine) reportAPIStat(result *model.APICallResult) error {
 
 // reportSvcStat 上报服务数据
 func (e *Engine) reportSvcStat(result *model.ServiceCallResult) error {
+	if len(result.CostLabels) == 0 {
+		result.CostLabels = e.costLabels
+	}
 	return e.SyncReportStat(model.ServiceStat, result)
 }
 
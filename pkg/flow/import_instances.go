@@ -0,0 +1,179 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"reflect"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncImportInstances 将外部系统的实例全集同步到北极星，只对带有同步标签的实例进行对账.
+// 对账直接基于服务端查询的实例，本地缓存可能尚未收到上一次同步的变更，基于缓存对账会重复注册或者遗漏移除
+func (e *Engine) SyncImportInstances(req *model.ImportInstancesRequest) (*model.ImportInstancesResponse, error) {
+	svcInstances, err := e.connector.QueryInstances(&model.QueryInstancesRequest{
+		Namespace: req.Namespace,
+		Service:   req.Service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := reconcileImport(req, svcInstances.GetInstances())
+	log.GetBaseLogger().Infof("[ImportInstances] %s/%s label %s=%s, added %d, updated %d, removed %d, unchanged %d, dryRun %v",
+		req.Namespace, req.Service, req.GetSyncLabelKey(), req.SyncLabel, len(result.Added), len(result.Updated),
+		len(result.Removed), result.Unchanged, req.DryRun)
+	if req.DryRun {
+		return result, nil
+	}
+	for _, spec := range result.Removed {
+		if err := e.SyncDeregister(importDeregisterRequest(req, spec)); err != nil {
+			result.Failed[spec.Endpoint()] = err
+		}
+	}
+	// 服务端注册已存在的实例不会更新属性，因此变更的实例需要先反注册再注册
+	for _, spec := range result.Updated {
+		if err := e.SyncDeregister(importDeregisterRequest(req, spec)); err != nil {
+			result.Failed[spec.Endpoint()] = err
+			continue
+		}
+		if _, err := e.SyncRegister(importRegisterRequest(req, spec)); err != nil {
+			result.Failed[spec.Endpoint()] = err
+		}
+	}
+	for _, spec := range result.Added {
+		if _, err := e.SyncRegister(importRegisterRequest(req, spec)); err != nil {
+			result.Failed[spec.Endpoint()] = err
+		}
+	}
+	return result, nil
+}
+
+// reconcileImport 将期望的实例全集与服务端已有的实例进行对账，计算需要新增、变更及移除的实例
+func reconcileImport(req *model.ImportInstancesRequest, instances []model.Instance) *model.ImportInstancesResponse {
+	labelKey := req.GetSyncLabelKey()
+	owned := make(map[string]model.Instance)
+	others := make(map[string]struct{})
+	for _, instance := range instances {
+		spec := instanceToSpec(instance)
+		if instance.GetMetadata()[labelKey] == req.SyncLabel {
+			owned[spec.Endpoint()] = instance
+		} else {
+			others[spec.Endpoint()] = struct{}{}
+		}
+	}
+	result := &model.ImportInstancesResponse{
+		DryRun: req.DryRun,
+		Failed: make(map[string]error),
+	}
+	desired := make(map[string]struct{}, len(req.Instances))
+	for _, spec := range req.Instances {
+		endpoint := spec.Endpoint()
+		desired[endpoint] = struct{}{}
+		spec.Metadata = withSyncLabel(spec.Metadata, labelKey, req.SyncLabel)
+		if _, ok := others[endpoint]; ok {
+			result.Failed[endpoint] = model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+				"instance %s of %s/%s is not owned by sync label %s", endpoint, req.Namespace, req.Service, req.SyncLabel)
+			continue
+		}
+		current, ok := owned[endpoint]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, spec)
+		case isSpecChanged(instanceToSpec(current), spec):
+			result.Updated = append(result.Updated, spec)
+		default:
+			result.Unchanged++
+		}
+	}
+	for endpoint, instance := range owned {
+		if _, ok := desired[endpoint]; !ok {
+			result.Removed = append(result.Removed, instanceToSpec(instance))
+		}
+	}
+	return result
+}
+
+// withSyncLabel 复制元数据并加上同步标签
+func withSyncLabel(metadata map[string]string, key, value string) map[string]string {
+	labeled := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		labeled[k] = v
+	}
+	labeled[key] = value
+	return labeled
+}
+
+// instanceToSpec 将已注册的实例转换为实例描述
+func instanceToSpec(instance model.Instance) model.InstanceSpec {
+	weight := instance.GetWeight()
+	return model.InstanceSpec{
+		Host:     instance.GetHost(),
+		Port:     int(instance.GetPort()),
+		Protocol: instance.GetProtocol(),
+		Version:  instance.GetVersion(),
+		Weight:   &weight,
+		Metadata: instance.GetMetadata(),
+	}
+}
+
+// isSpecChanged 判断实例描述是否发生变更
+func isSpecChanged(current, expect model.InstanceSpec) bool {
+	if current.Protocol != expect.Protocol || current.Version != expect.Version ||
+		current.GetWeight() != expect.GetWeight() {
+		return true
+	}
+	if len(current.Metadata) == 0 && len(expect.Metadata) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(current.Metadata, expect.Metadata)
+}
+
+func importRegisterRequest(req *model.ImportInstancesRequest, spec model.InstanceSpec) *model.InstanceRegisterRequest {
+	weight := spec.GetWeight()
+	registerReq := &model.InstanceRegisterRequest{
+		Namespace:    req.Namespace,
+		Service:      req.Service,
+		ServiceToken: req.ServiceToken,
+		Host:         spec.Host,
+		Port:         spec.Port,
+		Weight:       &weight,
+		Metadata:     spec.Metadata,
+		Timeout:      req.Timeout,
+		RetryCount:   req.RetryCount,
+	}
+	if len(spec.Protocol) > 0 {
+		registerReq.Protocol = &spec.Protocol
+	}
+	if len(spec.Version) > 0 {
+		registerReq.Version = &spec.Version
+	}
+	return registerReq
+}
+
+func importDeregisterRequest(req *model.ImportInstancesRequest, spec model.InstanceSpec) *model.InstanceDeRegisterRequest {
+	return &model.InstanceDeRegisterRequest{
+		Namespace:    req.Namespace,
+		Service:      req.Service,
+		ServiceToken: req.ServiceToken,
+		Host:         spec.Host,
+		Port:         spec.Port,
+		Timeout:      req.Timeout,
+		RetryCount:   req.RetryCount,
+	}
+}
@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+type fakeInstance struct {
+	model.Instance
+	host     string
+	port     uint32
+	weight   int
+	metadata map[string]string
+}
+
+func (i *fakeInstance) GetHost() string {
+	return i.host
+}
+
+func (i *fakeInstance) GetPort() uint32 {
+	return i.port
+}
+
+func (i *fakeInstance) GetProtocol() string {
+	return ""
+}
+
+func (i *fakeInstance) GetVersion() string {
+	return ""
+}
+
+func (i *fakeInstance) GetWeight() int {
+	return i.weight
+}
+
+func (i *fakeInstance) GetMetadata() map[string]string {
+	return i.metadata
+}
+
+// TestReconcileImport 测试只对同步标签下的实例进行对账，其他来源的实例不被修改
+func TestReconcileImport(t *testing.T) {
+	owned := map[string]string{model.DefaultImportSyncLabelKey: "vip1"}
+	instances := []model.Instance{
+		&fakeInstance{host: "10.0.0.1", port: 80, weight: 100, metadata: owned},
+		&fakeInstance{host: "10.0.0.2", port: 80, weight: 100, metadata: owned},
+		&fakeInstance{host: "10.0.0.3", port: 80, weight: 100, metadata: owned},
+		&fakeInstance{host: "10.0.0.4", port: 80, weight: 100},
+		&fakeInstance{host: "10.0.0.5", port: 80, weight: 100,
+			metadata: map[string]string{model.DefaultImportSyncLabelKey: "vip2"}},
+	}
+	weight := 50
+	req := &model.ImportInstancesRequest{
+		Namespace: "Test",
+		Service:   "svc",
+		SyncLabel: "vip1",
+		DryRun:    true,
+		Instances: []model.InstanceSpec{
+			{Host: "10.0.0.1", Port: 80},
+			{Host: "10.0.0.2", Port: 80, Weight: &weight},
+			{Host: "10.0.0.4", Port: 80},
+			{Host: "10.0.0.6", Port: 80, Metadata: map[string]string{"zone": "a"}},
+		},
+	}
+	result := reconcileImport(req, instances)
+	if !result.DryRun || result.Unchanged != 1 {
+		t.Fatalf("expect dry run with 1 unchanged instance, got %+v", result)
+	}
+	if len(result.Updated) != 1 || result.Updated[0].Endpoint() != "10.0.0.2:80" || result.Updated[0].GetWeight() != 50 {
+		t.Fatalf("expect weight of 10.0.0.2:80 updated, got %+v", result.Updated)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].Endpoint() != "10.0.0.3:80" {
+		t.Fatalf("expect stale 10.0.0.3:80 removed, got %+v", result.Removed)
+	}
+	if len(result.Added) != 1 || result.Added[0].Endpoint() != "10.0.0.6:80" {
+		t.Fatalf("expect 10.0.0.6:80 added, got %+v", result.Added)
+	}
+	if metadata := result.Added[0].Metadata; metadata["zone"] != "a" || metadata[model.DefaultImportSyncLabelKey] != "vip1" {
+		t.Fatalf("expect added instance labeled, got %v", metadata)
+	}
+	if _, ok := result.Failed["10.0.0.4:80"]; !ok || len(result.Failed) != 1 {
+		t.Fatalf("expect instance without sync label refused, got %v", result.Failed)
+	}
+	if req.Instances[3].Metadata[model.DefaultImportSyncLabelKey] != "" {
+		t.Fatal("request metadata should not be modified")
+	}
+}
+
+// queryConnector 返回服务端最新实例的连接器
+type queryConnector struct {
+	serverconnector.ServerConnector
+	instances []model.Instance
+	queries   []*model.QueryInstancesRequest
+}
+
+func (c *queryConnector) QueryInstances(req *model.QueryInstancesRequest) (model.ServiceInstances, error) {
+	c.queries = append(c.queries, req)
+	return &queriedInstances{instances: c.instances}, nil
+}
+
+type queriedInstances struct {
+	model.ServiceInstances
+	instances []model.Instance
+}
+
+func (s *queriedInstances) GetInstances() []model.Instance {
+	return s.instances
+}
+
+// TestSyncImportInstancesQueryServer 测试对账使用服务端查询的最新实例，而不是本地缓存
+func TestSyncImportInstancesQueryServer(t *testing.T) {
+	connector := &queryConnector{instances: []model.Instance{
+		&fakeInstance{host: "10.0.0.1", port: 80, weight: 100,
+			metadata: map[string]string{model.DefaultImportSyncLabelKey: "vip1"}},
+	}}
+	engine := &Engine{connector: connector}
+	result, err := engine.SyncImportInstances(&model.ImportInstancesRequest{
+		Namespace: "Test",
+		Service:   "svc",
+		SyncLabel: "vip1",
+		DryRun:    true,
+		Instances: []model.InstanceSpec{{Host: "10.0.0.1", Port: 80}, {Host: "10.0.0.2", Port: 80}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(connector.queries) != 1 || connector.queries[0].Namespace != "Test" || connector.queries[0].Service != "svc" {
+		t.Fatalf("expect instances queried from server, got %v", connector.queries)
+	}
+	if result.Unchanged != 1 || len(result.Added) != 1 || result.Added[0].Endpoint() != "10.0.0.2:80" {
+		t.Fatalf("expect reconciled against server instances, got %+v", result)
+	}
+}
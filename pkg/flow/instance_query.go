@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sort"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncQueryInstances 直接向服务端查询服务实例并按条件过滤分页，不经过本地缓存，也不会订阅服务
+func (e *Engine) SyncQueryInstances(req *model.QueryInstancesRequest) (*model.QueryInstancesResponse, error) {
+	svcInstances, err := e.connector.QueryInstances(req)
+	if err != nil {
+		return nil, err
+	}
+	return pageQueryInstances(req, svcInstances)
+}
+
+// pageQueryInstances 按照过滤条件筛选实例，并从分页标识指向的实例之后截取一页
+func pageQueryInstances(
+	req *model.QueryInstancesRequest, svcInstances model.ServiceInstances) (*model.QueryInstancesResponse, error) {
+	lastID, err := model.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "invalid pageToken")
+	}
+	resp := &model.QueryInstancesResponse{
+		ServiceInfo: model.ServiceInfo{
+			Namespace: req.Namespace,
+			Service:   req.Service,
+			Metadata:  svcInstances.GetMetadata(),
+		},
+		Revision:  svcInstances.GetRevision(),
+		NotExists: svcInstances.IsNotExists(),
+	}
+	matched := make([]model.Instance, 0, len(svcInstances.GetInstances()))
+	for _, instance := range svcInstances.GetInstances() {
+		if req.Match(instance) {
+			matched = append(matched, instance)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].GetId() < matched[j].GetId()
+	})
+	resp.TotalCount = len(matched)
+	start := sort.Search(len(matched), func(i int) bool {
+		return matched[i].GetId() > lastID
+	})
+	end := start + req.GetPageSize()
+	if end < len(matched) {
+		resp.NextPageToken = model.EncodePageToken(matched[end-1].GetId())
+	} else {
+		end = len(matched)
+	}
+	resp.Instances = matched[start:end]
+	return resp, nil
+}
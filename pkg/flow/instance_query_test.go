@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// TestPageQueryInstances 测试实例查询的过滤及分页
+func TestPageQueryInstances(t *testing.T) {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String("Test"),
+			Name:      wrapperspb.String("echo"),
+			Revision:  wrapperspb.String("r1"),
+		},
+	}
+	for i := 0; i < 10; i++ {
+		env := "prod"
+		if i%2 == 1 {
+			env = "test"
+		}
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:       wrapperspb.String(fmt.Sprintf("inst-%02d", i)),
+			Host:     wrapperspb.String("127.0.0.1"),
+			Port:     wrapperspb.UInt32(uint32(8000 + i)),
+			Weight:   wrapperspb.UInt32(100),
+			Healthy:  wrapperspb.Bool(i != 4),
+			Metadata: map[string]string{"env": env},
+		})
+	}
+	svcInstances := pb.NewServiceInstancesInProto(resp, func(string) local.InstanceLocalValue {
+		return local.NewInstanceLocalValue()
+	}, nil, nil)
+
+	req := &model.QueryInstancesRequest{
+		Namespace:   "Test",
+		Service:     "echo",
+		Metadata:    map[string]string{"env": "prod"},
+		HealthyOnly: true,
+		PageSize:    2,
+	}
+	var ids []string
+	for page := 0; ; page++ {
+		result, err := pageQueryInstances(req, svcInstances)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.TotalCount != 4 || result.Revision != "r1" {
+			t.Fatalf("unexpected total %d, revision %s", result.TotalCount, result.Revision)
+		}
+		for _, instance := range result.Instances {
+			ids = append(ids, instance.GetId())
+		}
+		if len(result.NextPageToken) == 0 {
+			break
+		}
+		if page > 2 {
+			t.Fatal("pagination does not terminate")
+		}
+		req.PageToken = result.NextPageToken
+	}
+	expects := []string{"inst-00", "inst-02", "inst-06", "inst-08"}
+	if fmt.Sprint(ids) != fmt.Sprint(expects) {
+		t.Fatalf("expect instances %v, got %v", expects, ids)
+	}
+
+	req.PageToken = "!"
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect malformed page token rejected")
+	}
+}
@@ -0,0 +1,134 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// maxInstancesHistory 每个服务保留的历史版本数
+const maxInstancesHistory = 16
+
+// instancesSnapshot 某个版本的实例快照
+type instancesSnapshot struct {
+	revision  string
+	instances map[string]model.Instance
+}
+
+// instancesHistory 记录对外返回过的实例版本，用于计算增量变更
+type instancesHistory struct {
+	mutex     sync.Mutex
+	snapshots map[model.ServiceKey][]*instancesSnapshot
+}
+
+// record 记录版本快照，并返回起始版本的快照，不存在则返回nil
+func (h *instancesHistory) record(svcKey model.ServiceKey, since string,
+	current *instancesSnapshot) *instancesSnapshot {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.snapshots == nil {
+		h.snapshots = make(map[model.ServiceKey][]*instancesSnapshot)
+	}
+	var prev *instancesSnapshot
+	snapshots := h.snapshots[svcKey]
+	exists := false
+	for _, snapshot := range snapshots {
+		if len(since) > 0 && snapshot.revision == since {
+			prev = snapshot
+		}
+		if snapshot.revision == current.revision {
+			exists = true
+		}
+	}
+	if !exists {
+		snapshots = append(snapshots, current)
+		if len(snapshots) > maxInstancesHistory {
+			snapshots = snapshots[len(snapshots)-maxInstancesHistory:]
+		}
+		h.snapshots[svcKey] = snapshots
+	}
+	return prev
+}
+
+// GetInstancesDiff 获取服务实例自起始版本以来的增量变更，起始版本为空或已过期时返回全量实例
+func (e *Engine) GetInstancesDiff(svcKey model.ServiceKey, sinceRevision string) (*model.InstancesDiffResponse, error) {
+	resp, err := e.SyncGetAllInstances(&model.GetAllInstancesRequest{
+		Namespace: svcKey.Namespace,
+		Service:   svcKey.Service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	current := &instancesSnapshot{
+		revision:  resp.GetRevision(),
+		instances: make(map[string]model.Instance, len(resp.GetInstances())),
+	}
+	for _, instance := range resp.GetInstances() {
+		current.instances[instance.GetId()] = instance
+	}
+	diff := &model.InstancesDiffResponse{
+		ServiceKey:    svcKey,
+		SinceRevision: sinceRevision,
+		Revision:      current.revision,
+	}
+	prev := e.instancesHistory.record(svcKey, sinceRevision, current)
+	if prev == nil {
+		diff.FullSync = true
+		diff.Added = resp.GetInstances()
+		return diff, nil
+	}
+	if prev.revision == current.revision {
+		return diff, nil
+	}
+	for _, instance := range resp.GetInstances() {
+		prevInstance, ok := prev.instances[instance.GetId()]
+		if !ok {
+			diff.Added = append(diff.Added, instance)
+			continue
+		}
+		if isInstanceChanged(prevInstance, instance) {
+			diff.Changed = append(diff.Changed, instance)
+		}
+	}
+	for id, instance := range prev.instances {
+		if _, ok := current.instances[id]; !ok {
+			diff.Removed = append(diff.Removed, instance)
+		}
+	}
+	return diff, nil
+}
+
+// isInstanceChanged 判断实例是否发生变更，优先比较实例版本号
+func isInstanceChanged(prev, cur model.Instance) bool {
+	if len(prev.GetRevision()) > 0 && len(cur.GetRevision()) > 0 {
+		return prev.GetRevision() != cur.GetRevision()
+	}
+	return prev.GetHost() != cur.GetHost() ||
+		prev.GetPort() != cur.GetPort() ||
+		prev.GetWeight() != cur.GetWeight() ||
+		prev.GetPriority() != cur.GetPriority() ||
+		prev.IsHealthy() != cur.IsHealthy() ||
+		prev.IsIsolated() != cur.IsIsolated() ||
+		prev.GetProtocol() != cur.GetProtocol() ||
+		prev.GetVersion() != cur.GetVersion() ||
+		prev.GetLogicSet() != cur.GetLogicSet() ||
+		!reflect.DeepEqual(prev.GetMetadata(), cur.GetMetadata())
+}
@@ -0,0 +1,46 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestInstancesHistoryRecord 测试实例版本历史的记录与淘汰
+func TestInstancesHistoryRecord(t *testing.T) {
+	h := &instancesHistory{}
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	if prev := h.record(svcKey, "", &instancesSnapshot{revision: "r0"}); prev != nil {
+		t.Fatal("empty since revision should return nil")
+	}
+	for i := 1; i <= maxInstancesHistory; i++ {
+		prev := h.record(svcKey, fmt.Sprintf("r%d", i-1), &instancesSnapshot{revision: fmt.Sprintf("r%d", i)})
+		if prev == nil || prev.revision != fmt.Sprintf("r%d", i-1) {
+			t.Fatalf("expect previous revision r%d, got %v", i-1, prev)
+		}
+	}
+	if prev := h.record(svcKey, "r0", &instancesSnapshot{revision: "r0"}); prev != nil {
+		t.Fatal("evicted revision should return nil")
+	}
+	if len(h.snapshots[svcKey]) != maxInstancesHistory {
+		t.Fatalf("expect %d snapshots, got %d", maxInstancesHistory, len(h.snapshots[svcKey]))
+	}
+}
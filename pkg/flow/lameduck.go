@@ -0,0 +1,39 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// EnterLameduck 进入lameduck状态：将自动心跳实例的TTL缩短为配置的lameduckTTL，之后注册的实例同样使用该TTL，
+// 用于容器preStop阶段，即使进程随后被SIGKILL来不及反注册，服务端也能在数秒内摘除实例
+func (e *Engine) EnterLameduck() error {
+	atomic.StoreUint32(&e.lameduck, 1)
+	ttl := e.configuration.GetProvider().GetLameduckTTL()
+	err := e.registerStates.ShortenLease(ttl, e.doSyncRegister)
+	log.GetBaseLogger().Infof("[Lameduck] sdk context entered lameduck, instance ttl shortened to %ds", ttl)
+	return err
+}
+
+// isLameduck 是否处于lameduck状态
+func (e *Engine) isLameduck() bool {
+	return atomic.LoadUint32(&e.lameduck) > 0
+}
@@ -0,0 +1,237 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+)
+
+// AddServiceNameMapper 添加服务名映射器，按添加顺序执行，第一个完成映射的生效，配置的映射规则最后执行
+func (e *Engine) AddServiceNameMapper(mapper model.ServiceNameMapper) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	mappers := make([]model.ServiceNameMapper, 0, len(e.selectorHooks.nameMappers)+1)
+	mappers = append(mappers, e.selectorHooks.nameMappers...)
+	e.selectorHooks.nameMappers = append(mappers, mapper)
+}
+
+// mapServiceName 映射调用方使用的服务名，未映射时返回false
+func (e *Engine) mapServiceName(svcKey model.ServiceKey) (model.ServiceKey, bool) {
+	e.selectorHooks.mutex.Lock()
+	mappers := e.selectorHooks.nameMappers
+	e.selectorHooks.mutex.Unlock()
+	for _, mapper := range mappers {
+		if mapped, ok := mapper(svcKey); ok && mapped != svcKey {
+			return mapped, true
+		}
+	}
+	if e.configNameMapper != nil {
+		if mapped, ok := e.configNameMapper.mapName(svcKey); ok && mapped != svcKey {
+			return mapped, true
+		}
+	}
+	return svcKey, false
+}
+
+// applyNameMapping 对实例查询请求进行服务名映射，返回调用方使用的服务名，未映射时返回nil
+func (e *Engine) applyNameMapping(commonRequest *data.CommonInstancesRequest) *model.ServiceKey {
+	origin := commonRequest.DstService
+	mapped, ok := e.mapServiceName(origin)
+	if !ok {
+		return nil
+	}
+	commonRequest.MapDstService(mapped)
+	return &origin
+}
+
+// restoreServiceName 将应答中的服务名还原为调用方使用的服务名
+func restoreServiceName(resp *model.InstancesResponse, origin *model.ServiceKey) {
+	if resp == nil || origin == nil {
+		return
+	}
+	resp.Namespace, resp.Service = origin.Namespace, origin.Service
+}
+
+// nameMappingRule 解析后的服务名映射规则
+type nameMappingRule struct {
+	*config.NameMappingRule
+	prefix       bool
+	servicePart  string
+	targetPrefix bool
+	targetPart   string
+}
+
+// mapName 规则匹配时返回映射后的服务名
+func (r *nameMappingRule) mapName(svcKey model.ServiceKey) (model.ServiceKey, bool) {
+	if len(r.Namespace) > 0 && r.Namespace != svcKey.Namespace {
+		return svcKey, false
+	}
+	mapped := svcKey
+	if r.prefix {
+		if !strings.HasPrefix(svcKey.Service, r.servicePart) {
+			return svcKey, false
+		}
+		if r.targetPrefix {
+			mapped.Service = r.targetPart + strings.TrimPrefix(svcKey.Service, r.servicePart)
+		} else if len(r.TargetService) > 0 {
+			mapped.Service = r.TargetService
+		}
+	} else {
+		if svcKey.Service != r.Service {
+			return svcKey, false
+		}
+		if len(r.TargetService) > 0 {
+			mapped.Service = r.TargetService
+		}
+	}
+	if len(r.TargetNamespace) > 0 {
+		mapped.Namespace = r.TargetNamespace
+	}
+	return mapped, true
+}
+
+func compileNameMappingRules(rules []*config.NameMappingRule) []*nameMappingRule {
+	compiled := make([]*nameMappingRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Verify(); err != nil {
+			log.GetBaseLogger().Warnf("[NameMapping] ignore invalid rule: %v", err)
+			continue
+		}
+		compiled = append(compiled, &nameMappingRule{
+			NameMappingRule: rule,
+			prefix:          strings.HasSuffix(rule.Service, "*"),
+			servicePart:     strings.TrimSuffix(rule.Service, "*"),
+			targetPrefix:    strings.HasSuffix(rule.TargetService, "*"),
+			targetPart:      strings.TrimSuffix(rule.TargetService, "*"),
+		})
+	}
+	return compiled
+}
+
+// configNameMapper 基于配置的服务名映射，映射规则文件优先于静态规则
+type configNameMapper struct {
+	staticRules []*nameMappingRule
+	file        string
+	// []*nameMappingRule，来自映射规则文件
+	fileRules atomic.Value
+	modTime   time.Time
+	stopChan  chan struct{}
+	stopOnce  sync.Once
+}
+
+// newConfigNameMapper 根据配置创建服务名映射器，未配置任何规则时返回nil
+func newConfigNameMapper(cfg config.NameMappingConfig) *configNameMapper {
+	if cfg == nil || (len(cfg.GetRules()) == 0 && len(cfg.GetFile()) == 0) {
+		return nil
+	}
+	m := &configNameMapper{
+		staticRules: compileNameMappingRules(cfg.GetRules()),
+		file:        model.ReplaceHomeVar(cfg.GetFile()),
+		stopChan:    make(chan struct{}),
+	}
+	m.fileRules.Store([]*nameMappingRule{})
+	if len(m.file) > 0 {
+		m.reload()
+		interval := cfg.GetRefreshInterval()
+		profiling.Go("nameMapping", "watchFile", func() {
+			m.watch(interval)
+		})
+	}
+	return m
+}
+
+// mapName 按规则映射服务名
+func (m *configNameMapper) mapName(svcKey model.ServiceKey) (model.ServiceKey, bool) {
+	for _, rule := range m.fileRules.Load().([]*nameMappingRule) {
+		if mapped, ok := rule.mapName(svcKey); ok {
+			return mapped, true
+		}
+	}
+	for _, rule := range m.staticRules {
+		if mapped, ok := rule.mapName(svcKey); ok {
+			return mapped, true
+		}
+	}
+	return svcKey, false
+}
+
+// watch 周期性检查映射规则文件是否变更
+func (m *configNameMapper) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.reload()
+		}
+	}
+}
+
+// reload 文件修改时间变化时重新加载映射规则，文件被删除时清空文件规则，解析失败时保留原有规则
+func (m *configNameMapper) reload() {
+	info, err := os.Stat(m.file)
+	if err != nil {
+		if os.IsNotExist(err) && !m.modTime.IsZero() {
+			log.GetBaseLogger().Warnf("[NameMapping] mapping file %s removed, clear file rules", m.file)
+			m.modTime = time.Time{}
+			m.fileRules.Store([]*nameMappingRule{})
+		}
+		return
+	}
+	if info.ModTime().Equal(m.modTime) {
+		return
+	}
+	content, err := ioutil.ReadFile(m.file)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[NameMapping] fail to read mapping file %s: %v", m.file, err)
+		return
+	}
+	fileCfg := &struct {
+		Rules []*config.NameMappingRule `yaml:"rules"`
+	}{}
+	if err = yaml.Unmarshal(content, fileCfg); err != nil {
+		log.GetBaseLogger().Errorf("[NameMapping] fail to parse mapping file %s, keep previous rules: %v", m.file, err)
+		return
+	}
+	m.modTime = info.ModTime()
+	rules := compileNameMappingRules(fileCfg.Rules)
+	m.fileRules.Store(rules)
+	log.GetBaseLogger().Infof("[NameMapping] %d rules loaded from mapping file %s", len(rules), m.file)
+}
+
+// destroy 停止监听映射规则文件
+func (m *configNameMapper) destroy() {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+}
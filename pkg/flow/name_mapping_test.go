@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestMapServiceName 测试映射器、映射规则文件及静态规则的优先级，以及前缀规则
+func TestMapServiceName(t *testing.T) {
+	mappingFile := filepath.Join(t.TempDir(), "mapping.yaml")
+	cfg := &config.NameMappingConfigImpl{
+		Rules: []*config.NameMappingRule{
+			{Namespace: "legacy", Service: "order-svc", TargetNamespace: "Production", TargetService: "order"},
+			{Service: "old-*", TargetService: "new-*"},
+		},
+		File: mappingFile,
+	}
+	cfg.SetDefault()
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	mapper := newConfigNameMapper(cfg)
+	defer mapper.destroy()
+	engine := &Engine{configNameMapper: mapper}
+
+	expects := []struct {
+		from, to model.ServiceKey
+		mapped   bool
+	}{
+		{model.ServiceKey{Namespace: "legacy", Service: "order-svc"},
+			model.ServiceKey{Namespace: "Production", Service: "order"}, true},
+		{model.ServiceKey{Namespace: "default", Service: "order-svc"},
+			model.ServiceKey{Namespace: "default", Service: "order-svc"}, false},
+		{model.ServiceKey{Namespace: "default", Service: "old-pay"},
+			model.ServiceKey{Namespace: "default", Service: "new-pay"}, true},
+	}
+	for _, expect := range expects {
+		to, mapped := engine.mapServiceName(expect.from)
+		if to != expect.to || mapped != expect.mapped {
+			t.Fatalf("map %s expect %s(%v), got %s(%v)", expect.from, expect.to, expect.mapped, to, mapped)
+		}
+	}
+
+	// 映射规则文件优先于静态规则
+	content := "rules:\n  - service: old-pay\n    targetNamespace: Finance\n"
+	if err := ioutil.WriteFile(mappingFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mapper.reload()
+	to, _ := engine.mapServiceName(model.ServiceKey{Namespace: "default", Service: "old-pay"})
+	if to != (model.ServiceKey{Namespace: "Finance", Service: "old-pay"}) {
+		t.Fatalf("file rule should take effect, got %s", to)
+	}
+	if err := os.Remove(mappingFile); err != nil {
+		t.Fatal(err)
+	}
+	mapper.reload()
+	to, _ = engine.mapServiceName(model.ServiceKey{Namespace: "default", Service: "old-pay"})
+	if to.Service != "new-pay" {
+		t.Fatalf("file rules should be cleared after file removed, got %s", to)
+	}
+
+	// 自定义映射器先于配置的规则执行
+	engine.AddServiceNameMapper(func(svcKey model.ServiceKey) (model.ServiceKey, bool) {
+		return model.ServiceKey{Namespace: "custom", Service: svcKey.Service}, svcKey.Service == "order-svc"
+	})
+	to, _ = engine.mapServiceName(model.ServiceKey{Namespace: "legacy", Service: "order-svc"})
+	if to.Namespace != "custom" {
+		t.Fatalf("custom mapper should take precedence, got %s", to)
+	}
+
+	resp := &model.InstancesResponse{ServiceInfo: model.ServiceInfo{Namespace: "custom", Service: "order-svc"}}
+	restoreServiceName(resp, &model.ServiceKey{Namespace: "legacy", Service: "order-svc"})
+	if resp.Namespace != "legacy" {
+		t.Fatal("service name in response should be restored")
+	}
+}
@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// syncFallbackNamespace 服务在请求的命名空间中不存在时，按配置的回退链依次到回退命名空间中查找
+func (e *Engine) syncFallbackNamespace(req *data.CommonInstancesRequest) error {
+	// 已经发生过回退（如回退后再经过路由重定向），不再重复回退
+	if req.FallbackTimes > 0 || nil == req.DstInstances || !req.DstInstances.IsNotExists() {
+		return nil
+	}
+	origin := req.DstService
+	fallbacks := e.configuration.GetConsumer().GetNamespaceFallback().GetFallbacks(origin.Namespace)
+	if len(fallbacks) == 0 {
+		return nil
+	}
+	for _, namespace := range fallbacks {
+		req.FallbackToNamespace(namespace)
+		if err := e.SyncGetResources(req); err != nil {
+			return err
+		}
+		if !req.DstInstances.IsNotExists() {
+			log.GetBaseLogger().Infof("[NamespaceFallback] service %s not found, fallback to namespace %s",
+				origin, namespace)
+			e.reportNamespaceFallback(origin, namespace, true)
+			return nil
+		}
+	}
+	e.reportNamespaceFallback(origin, fallbacks[len(fallbacks)-1], false)
+	req.ResetFallback()
+	return e.SyncGetResources(req)
+}
+
+// reportNamespaceFallback 上报命名空间回退的结果
+func (e *Engine) reportNamespaceFallback(origin model.ServiceKey, fallback string, found bool) {
+	_ = e.SyncReportStat(model.NamespaceFallbackStat, &model.NamespaceFallbackGauge{
+		Namespace:         origin.Namespace,
+		Service:           origin.Service,
+		FallbackNamespace: fallback,
+		Found:             found,
+	})
+}
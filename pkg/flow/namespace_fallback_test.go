@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestNamespaceFallbackConfig 测试回退规则的校验及回退链的查找
+func TestNamespaceFallbackConfig(t *testing.T) {
+	cfg := &config.NamespaceFallbackConfigImpl{
+		Rules: []*config.NamespaceFallbackRule{
+			{Namespace: "Production", Fallbacks: []string{"Shared", "Platform"}},
+		},
+	}
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if fallbacks := cfg.GetFallbacks("Production"); len(fallbacks) != 2 || fallbacks[0] != "Shared" {
+		t.Fatalf("unexpected fallbacks %v", fallbacks)
+	}
+	if fallbacks := cfg.GetFallbacks("Test"); fallbacks != nil {
+		t.Fatalf("expect no fallbacks, got %v", fallbacks)
+	}
+	invalids := [][]*config.NamespaceFallbackRule{
+		{{Namespace: "Production"}},
+		{{Namespace: "Production", Fallbacks: []string{"Production"}}},
+		{{Namespace: "Production", Fallbacks: []string{"Shared", "Shared"}}},
+		{{Namespace: "Production", Fallbacks: []string{"Shared"}}, {Namespace: "Production", Fallbacks: []string{"A"}}},
+	}
+	for i, rules := range invalids {
+		if err := (&config.NamespaceFallbackConfigImpl{Rules: rules}).Verify(); err == nil {
+			t.Fatalf("expect invalid rules %d", i)
+		}
+	}
+}
+
+// TestFallbackToNamespace 测试回退后应答记录实际使用的命名空间，及未找到时还原请求的命名空间
+func TestFallbackToNamespace(t *testing.T) {
+	req := &data.CommonInstancesRequest{}
+	req.InitByGetAllRequest(&model.GetAllInstancesRequest{Namespace: "Production", Service: "echo"},
+		config.NewDefaultConfigurationWithDomain())
+	req.FallbackToNamespace("Shared")
+	if req.DstService.Namespace != "Shared" || req.FallbackFrom != "Production" || req.FallbackTimes != 1 {
+		t.Fatalf("unexpected request after fallback: %v, %s, %d", req.DstService, req.FallbackFrom, req.FallbackTimes)
+	}
+	if req.RouteInfo.DestService.GetNamespace() != "Shared" {
+		t.Fatalf("expect route dest namespace Shared, got %s", req.RouteInfo.DestService.GetNamespace())
+	}
+	svcInstances := model.NewDefaultServiceInstances(model.ServiceInfo{Namespace: "Shared", Service: "echo"}, nil)
+	resp := req.BuildInstancesResponse(req.DstService, nil, nil, 0, svcInstances)
+	if resp.Namespace != "Shared" || resp.FallbackNamespace != "Shared" {
+		t.Fatalf("unexpected response namespace %s, fallback %s", resp.Namespace, resp.FallbackNamespace)
+	}
+
+	req.FallbackToNamespace("Platform")
+	req.ResetFallback()
+	if req.DstService.Namespace != "Production" || len(req.FallbackFrom) != 0 || req.FallbackTimes != 2 {
+		t.Fatalf("unexpected request after reset: %v, %s, %d", req.DstService, req.FallbackFrom, req.FallbackTimes)
+	}
+	resp = req.BuildInstancesResponse(req.DstService, nil, nil, 0, svcInstances)
+	if len(resp.FallbackNamespace) != 0 {
+		t.Fatalf("expect no fallback namespace, got %s", resp.FallbackNamespace)
+	}
+}
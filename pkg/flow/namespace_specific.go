@@ -0,0 +1,161 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// namespaceSpecific 服务级及命名空间级配置所对应的插件实例，在引擎初始化时加载，加载后只读
+// 查找顺序为：服务级 -> 命名空间级 -> consumer全局配置
+type namespaceSpecific struct {
+	// 命名空间级的服务路由链
+	routerChains map[string]*servicerouter.RouterChain
+	// 命名空间级的负载均衡器
+	loadbalancers map[string]loadbalancer.LoadBalancer
+	// 服务级的服务路由链
+	serviceRouterChains map[model.ServiceKey]*servicerouter.RouterChain
+	// 服务级的负载均衡器
+	serviceLoadbalancers map[model.ServiceKey]loadbalancer.LoadBalancer
+}
+
+// specificPlugins 服务级及命名空间级配置共有的插件配置项
+type specificPlugins interface {
+	GetRouterChain() []string
+	GetLbPolicy() string
+}
+
+// loadSpecificPlugins 根据配置加载路由链及负载均衡插件，插件不存在时直接返回错误
+func loadSpecificPlugins(specific specificPlugins, supplier plugin.Supplier) (
+	*servicerouter.RouterChain, loadbalancer.LoadBalancer, error) {
+	var routerChain *servicerouter.RouterChain
+	var balancer loadbalancer.LoadBalancer
+	var err error
+	if chain := specific.GetRouterChain(); len(chain) > 0 {
+		if routerChain, err = data.GetServiceRouterChainByNames(chain, supplier); err != nil {
+			return nil, nil, err
+		}
+	}
+	if lbPolicy := specific.GetLbPolicy(); len(lbPolicy) > 0 {
+		if balancer, err = data.GetLoadBalancerByLbType(lbPolicy, supplier); err != nil {
+			return nil, nil, err
+		}
+	}
+	return routerChain, balancer, nil
+}
+
+// loadNamespaceSpecific 加载服务级及命名空间级配置的路由链及负载均衡插件
+func (e *Engine) loadNamespaceSpecific() error {
+	consumerCfg := e.configuration.GetConsumer()
+	nsCfgs := consumerCfg.GetNamespacesSpecific()
+	svcCfgs := consumerCfg.GetServicesSpecific()
+	values := &namespaceSpecific{
+		routerChains:         make(map[string]*servicerouter.RouterChain, len(nsCfgs)),
+		loadbalancers:        make(map[string]loadbalancer.LoadBalancer, len(nsCfgs)),
+		serviceRouterChains:  make(map[model.ServiceKey]*servicerouter.RouterChain, len(svcCfgs)),
+		serviceLoadbalancers: make(map[model.ServiceKey]loadbalancer.LoadBalancer, len(svcCfgs)),
+	}
+	for _, nsCfg := range nsCfgs {
+		routerChain, balancer, err := loadSpecificPlugins(nsCfg, e.plugins)
+		if err != nil {
+			return err
+		}
+		if routerChain != nil {
+			values.routerChains[nsCfg.GetNamespace()] = routerChain
+		}
+		if balancer != nil {
+			values.loadbalancers[nsCfg.GetNamespace()] = balancer
+		}
+	}
+	for _, svcCfg := range svcCfgs {
+		routerChain, balancer, err := loadSpecificPlugins(svcCfg, e.plugins)
+		if err != nil {
+			return err
+		}
+		svcKey := model.ServiceKey{Namespace: svcCfg.GetNamespace(), Service: svcCfg.GetService()}
+		if routerChain != nil {
+			values.serviceRouterChains[svcKey] = routerChain
+		}
+		if balancer != nil {
+			values.serviceLoadbalancers[svcKey] = balancer
+		}
+	}
+	e.namespaceSpecific = values
+	return nil
+}
+
+// getSpecificRouterChain 获取服务级或命名空间级路由链，都未配置时返回nil
+func (e *Engine) getSpecificRouterChain(svcKey model.ServiceKey) *servicerouter.RouterChain {
+	if e.namespaceSpecific == nil {
+		return nil
+	}
+	if routerChain, ok := e.namespaceSpecific.serviceRouterChains[svcKey]; ok {
+		return routerChain
+	}
+	return e.namespaceSpecific.routerChains[svcKey.Namespace]
+}
+
+// getSpecificLoadBalancer 获取服务级或命名空间级负载均衡器，都未配置时返回nil
+func (e *Engine) getSpecificLoadBalancer(svcKey model.ServiceKey) loadbalancer.LoadBalancer {
+	if e.namespaceSpecific == nil {
+		return nil
+	}
+	if balancer, ok := e.namespaceSpecific.serviceLoadbalancers[svcKey]; ok {
+		return balancer
+	}
+	return e.namespaceSpecific.loadbalancers[svcKey.Namespace]
+}
+
+// isCircuitBreakerEnable 判断服务是否启用熔断，依次查找服务级、命名空间级配置，都未配置时使用全局开关
+func (e *Engine) isCircuitBreakerEnable(svcKey model.ServiceKey) bool {
+	consumerCfg := e.configuration.GetConsumer()
+	if svcCfg := consumerCfg.GetServiceSpecific(svcKey.Namespace, svcKey.Service); svcCfg != nil {
+		if enable := svcCfg.GetCircuitBreakerEnable(); enable != nil {
+			return *enable
+		}
+	}
+	if nsCfg := consumerCfg.GetNamespaceSpecific(svcKey.Namespace); nsCfg != nil {
+		if enable := nsCfg.GetCircuitBreakerEnable(); enable != nil {
+			return *enable
+		}
+	}
+	return consumerCfg.GetCircuitBreaker().IsEnable()
+}
+
+// isCircuitBreakerRequired 全局开启熔断，或者有任一服务、命名空间单独开启熔断时，需要加载熔断器插件
+func isCircuitBreakerRequired(cfg config.Configuration) bool {
+	if cfg.GetConsumer().GetCircuitBreaker().IsEnable() {
+		return true
+	}
+	for _, nsCfg := range cfg.GetConsumer().GetNamespacesSpecific() {
+		if enable := nsCfg.GetCircuitBreakerEnable(); enable != nil && *enable {
+			return true
+		}
+	}
+	for _, svcCfg := range cfg.GetConsumer().GetServicesSpecific() {
+		if enable := svcCfg.GetCircuitBreakerEnable(); enable != nil && *enable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestSpecificConfigInherit(t *testing.T) {
+	cfg, err := config.LoadConfiguration([]byte(`
+global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  circuitBreaker:
+    enable: false
+  namespacesSpecific:
+    - namespace: legacy
+      circuitBreakerEnable: true
+    - namespace: test
+      lbPolicy: ringHash
+  servicesSpecific:
+    - namespace: legacy
+      service: slow
+      loadbalancer:
+        type: maglev
+      circuitBreaker:
+        enable: false
+      timeout: 5s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCircuitBreakerRequired(cfg) {
+		t.Fatal("expect circuit breaker required by namespace legacy")
+	}
+	engine := &Engine{configuration: cfg}
+	if !engine.isCircuitBreakerEnable(model.ServiceKey{Namespace: "legacy"}) {
+		t.Fatal("expect circuit breaker enabled for namespace legacy")
+	}
+	if engine.isCircuitBreakerEnable(model.ServiceKey{Namespace: "test"}) ||
+		engine.isCircuitBreakerEnable(model.ServiceKey{Namespace: "default"}) {
+		t.Fatal("expect circuit breaker inherit global switch")
+	}
+	if engine.isCircuitBreakerEnable(model.ServiceKey{Namespace: "legacy", Service: "slow"}) {
+		t.Fatal("expect circuit breaker disabled by service legacy/slow")
+	}
+	svcKey := &model.ServiceKey{Namespace: "legacy", Service: "slow"}
+	if lbPolicy := data.GetLbPolicy(cfg, svcKey); lbPolicy != config.DefaultLoadBalancerMaglev {
+		t.Fatalf("expect service lbPolicy maglev, got %s", lbPolicy)
+	}
+	if lbPolicy := data.GetLbPolicy(cfg, &model.ServiceKey{Namespace: "test", Service: "any"}); lbPolicy != config.DefaultLoadBalancerRingHash {
+		t.Fatalf("expect namespace lbPolicy ringHash, got %s", lbPolicy)
+	}
+	request := &model.GetOneInstanceRequest{}
+	param := &model.ControlParam{}
+	data.BuildServiceControlParam(request, cfg, svcKey, param)
+	if param.Timeout != 5*time.Second {
+		t.Fatalf("expect service timeout 5s, got %v", param.Timeout)
+	}
+	cbFlow := &CircuitBreakerFlow{engine: engine, bulkheads: newBulkheadManager(nil)}
+	resource, err := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "svc"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cbFlow.isEnable(resource) {
+		t.Fatal("expect resource of namespace default not checked")
+	}
+
+	if _, err = config.LoadConfiguration([]byte(`
+global:
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  namespacesSpecific:
+    - namespace: test
+    - namespace: test
+`)); err == nil {
+		t.Fatal("expect duplicate namespace rejected")
+	}
+}
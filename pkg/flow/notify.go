@@ -124,6 +124,14 @@ func (c *CombineNotifyContext) logNotifier(operation string, notifier *SingleNot
 // Wait notify 异步任务执行回调函数
 // 返回值，是否超时
 func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
+	return c.WaitContext(context.Background(), timeout)
+}
+
+// WaitContext 等待所有的通知器完成，调用方上下文取消时提前返回
+func (c *CombineNotifyContext) WaitContext(parent context.Context, timeout time.Duration) (exceedTime bool) {
+	if nil == parent {
+		parent = context.Background()
+	}
 	var restWait = atomic.LoadInt32(&c.waitCount)
 	if restWait == 0 {
 		return false
@@ -153,6 +161,8 @@ func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
 			select {
 			case <-afterTimer:
 				return
+			case <-parent.Done():
+				return
 			case <-notifier.notifier.GetContext().Done():
 				doneKeyChan <- notifier.name.Operation
 				nextWait := atomic.AddInt32(&c.waitCount, -1)
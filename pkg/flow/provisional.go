@@ -0,0 +1,178 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// defaultProvisionalWeight 未指定权重时临时实例的权重，与服务端默认值保持一致
+const defaultProvisionalWeight = 100
+
+// provisionalInstance 本进程注册成功但尚未被服务端推送确认的实例
+type provisionalInstance struct {
+	instance model.Instance
+	expireAt time.Time
+}
+
+// provisionalInstances 未确认的自注册实例，GetInstances时注入结果，避免自调用及冒烟测试等待服务端推送，
+// 服务端推送的实例列表包含该实例或者超过存活时间后移除
+type provisionalInstances struct {
+	mutex     sync.RWMutex
+	instances map[model.ServiceKey]map[string]*provisionalInstance
+}
+
+// addProvisionalInstance 注册成功后记录临时实例，隔离或者不健康的实例不做注入
+func (e *Engine) addProvisionalInstance(req *model.InstanceRegisterRequest, resp *model.InstanceRegisterResponse) {
+	cfg := e.configuration.GetProvider().GetProvisionalInstance()
+	if !cfg.IsEnable() {
+		return
+	}
+	if (req.Isolate != nil && *req.Isolate) || (req.Healthy != nil && !*req.Healthy) {
+		return
+	}
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	instance := newProvisionalInstance(&svcKey, req, resp)
+	p := &e.provisional
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if nil == p.instances {
+		p.instances = make(map[model.ServiceKey]map[string]*provisionalInstance)
+	}
+	svcProvisional, ok := p.instances[svcKey]
+	if !ok {
+		svcProvisional = make(map[string]*provisionalInstance)
+		p.instances[svcKey] = svcProvisional
+	}
+	svcProvisional[provisionalKey(req.Host, uint32(req.Port))] = &provisionalInstance{
+		instance: instance,
+		expireAt: e.globalCtx.Now().Add(cfg.GetTTL()),
+	}
+}
+
+// removeProvisionalInstance 反注册时移除临时实例
+func (e *Engine) removeProvisionalInstance(namespace string, service string, host string, port int) {
+	p := &e.provisional
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	svcKey := model.ServiceKey{Namespace: namespace, Service: service}
+	svcProvisional, ok := p.instances[svcKey]
+	if !ok {
+		return
+	}
+	delete(svcProvisional, provisionalKey(host, uint32(port)))
+	if len(svcProvisional) == 0 {
+		delete(p.instances, svcKey)
+	}
+}
+
+// injectProvisionalInstances 将未确认的临时实例追加到返回结果中，已被服务端确认或者过期的临时实例直接移除
+func (e *Engine) injectProvisionalInstances(svcKey model.ServiceKey, svcInstances model.ServiceInstances,
+	instances []model.Instance, totalWeight int) ([]model.Instance, int) {
+	if !e.configuration.GetProvider().GetProvisionalInstance().IsEnable() {
+		return instances, totalWeight
+	}
+	p := &e.provisional
+	p.mutex.RLock()
+	pending := len(p.instances[svcKey])
+	p.mutex.RUnlock()
+	if pending == 0 {
+		return instances, totalWeight
+	}
+	confirmed := make(map[string]bool)
+	if nil != svcInstances {
+		for _, instance := range svcInstances.GetInstances() {
+			confirmed[provisionalKey(instance.GetHost(), instance.GetPort())] = true
+		}
+	}
+	now := e.globalCtx.Now()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	svcProvisional := p.instances[svcKey]
+	// 不能修改缓存中的实例列表，注入前先复制
+	result := make([]model.Instance, len(instances), len(instances)+len(svcProvisional))
+	copy(result, instances)
+	for addr, provisional := range svcProvisional {
+		if confirmed[addr] || now.After(provisional.expireAt) {
+			delete(svcProvisional, addr)
+			continue
+		}
+		result = append(result, provisional.instance)
+		totalWeight += provisional.instance.GetWeight()
+	}
+	if len(svcProvisional) == 0 {
+		delete(p.instances, svcKey)
+	}
+	return result, totalWeight
+}
+
+func provisionalKey(host string, port uint32) string {
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// newProvisionalInstance 根据注册请求构建临时实例
+func newProvisionalInstance(svcKey *model.ServiceKey, req *model.InstanceRegisterRequest,
+	resp *model.InstanceRegisterResponse) model.Instance {
+	weight := defaultProvisionalWeight
+	if req.Weight != nil {
+		weight = *req.Weight
+	}
+	requestMetadata := model.WithTopologyMetadata(req.Metadata, req.Location)
+	metadata := make(map[string]string, len(requestMetadata)+1)
+	for k, v := range requestMetadata {
+		metadata[k] = v
+	}
+	metadata[model.ProvisionalMetadata] = "true"
+	instance := &apiservice.Instance{
+		Id:        &wrappers.StringValue{Value: resp.InstanceID},
+		Namespace: &wrappers.StringValue{Value: req.Namespace},
+		Service:   &wrappers.StringValue{Value: req.Service},
+		Host:      &wrappers.StringValue{Value: req.Host},
+		Port:      &wrappers.UInt32Value{Value: uint32(req.Port)},
+		Weight:    &wrappers.UInt32Value{Value: uint32(weight)},
+		Healthy:   &wrappers.BoolValue{Value: true},
+		Metadata:  metadata,
+	}
+	if req.Protocol != nil {
+		instance.Protocol = &wrappers.StringValue{Value: *req.Protocol}
+	}
+	if req.Version != nil {
+		instance.Version = &wrappers.StringValue{Value: *req.Version}
+	}
+	if req.Priority != nil {
+		instance.Priority = &wrappers.UInt32Value{Value: uint32(*req.Priority)}
+	}
+	if req.Location != nil {
+		instance.Location = &apimodel.Location{
+			Region: &wrappers.StringValue{Value: req.Location.Region},
+			Zone:   &wrappers.StringValue{Value: req.Location.Zone},
+			Campus: &wrappers.StringValue{Value: req.Location.Campus},
+		}
+	}
+	return pb.NewInstanceInProto(instance, svcKey, local.NewInstanceLocalValue())
+}
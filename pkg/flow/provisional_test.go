@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestProvisionalInstances 测试自注册实例在服务端确认前注入GetInstances结果
+func TestProvisionalInstances(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	cfg.GetProvider().GetProvisionalInstance().SetEnable(true)
+	cfg.GetProvider().GetProvisionalInstance().SetTTL(time.Minute)
+	engine := &Engine{configuration: cfg, globalCtx: model.NewValueContext()}
+	svcKey := model.ServiceKey{Namespace: "default", Service: "svc"}
+	req := &model.InstanceRegisterRequest{Namespace: "default", Service: "svc", Host: "127.0.0.1", Port: 8080,
+		Metadata: map[string]string{"env": "test"}}
+	engine.addProvisionalInstance(req, &model.InstanceRegisterResponse{InstanceID: "ins-1"})
+
+	empty := model.NewDefaultServiceInstances(model.ServiceInfo{Namespace: "default", Service: "svc"}, nil)
+	instances, totalWeight := engine.injectProvisionalInstances(svcKey, empty, nil, 0)
+	if len(instances) != 1 || totalWeight != defaultProvisionalWeight {
+		t.Fatalf("expect 1 provisional instance, got %d, weight %d", len(instances), totalWeight)
+	}
+	metadata := instances[0].GetMetadata()
+	if metadata[model.ProvisionalMetadata] != "true" || metadata["env"] != "test" || instances[0].GetId() != "ins-1" {
+		t.Fatalf("unexpected provisional instance %+v", metadata)
+	}
+
+	// 服务端推送包含该实例后不再注入
+	pushed := model.NewDefaultServiceInstances(model.ServiceInfo{Namespace: "default", Service: "svc"},
+		[]model.Instance{instances[0]})
+	instances, _ = engine.injectProvisionalInstances(svcKey, pushed, pushed.GetInstances(), 0)
+	if len(instances) != 1 {
+		t.Fatalf("confirmed instance should not be injected twice, got %d", len(instances))
+	}
+	instances, _ = engine.injectProvisionalInstances(svcKey, empty, nil, 0)
+	if len(instances) != 0 {
+		t.Fatalf("confirmed instance should be removed, got %d", len(instances))
+	}
+
+	// 反注册后移除
+	engine.addProvisionalInstance(req, &model.InstanceRegisterResponse{InstanceID: "ins-1"})
+	engine.removeProvisionalInstance("default", "svc", "127.0.0.1", 8080)
+	if instances, _ = engine.injectProvisionalInstances(svcKey, empty, nil, 0); len(instances) != 0 {
+		t.Fatalf("deregistered instance should be removed, got %d", len(instances))
+	}
+}
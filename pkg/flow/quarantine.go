@@ -0,0 +1,309 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+const (
+	// 隔离名单在本地缓存目录下的子目录，避免被当作服务缓存文件加载
+	quarantineDir  = "quarantine"
+	quarantineFile = "instances.json"
+)
+
+// quarantineEntry 隔离名单的持久化条目
+type quarantineEntry struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	// 实例被熔断的时间，毫秒
+	OpenTime int64 `json:"openTime"`
+}
+
+// instanceQuarantine 熔断实例隔离名单：记录最近被熔断的实例并持久化，
+// 进程重启后对上一个进程判定为异常的实例在有效期内继续熔断，避免刚启动就把流量发往异常实例
+type instanceQuarantine struct {
+	flow *CircuitBreakerFlow
+	ttl  time.Duration
+	path string
+
+	mutex   sync.Mutex
+	entries map[model.InstanceKey]time.Time
+	// 从持久化文件恢复、由隔离名单施加了熔断状态的实例
+	applied map[model.InstanceKey]*time.Timer
+}
+
+// newInstanceQuarantine 创建隔离名单并加载上一个进程持久化的条目
+func newInstanceQuarantine(flow *CircuitBreakerFlow, persistDir string, ttl time.Duration) *instanceQuarantine {
+	q := &instanceQuarantine{
+		flow:    flow,
+		ttl:     ttl,
+		path:    filepath.Join(model.ReplaceHomeVar(persistDir), quarantineDir, quarantineFile),
+		entries: make(map[model.InstanceKey]time.Time),
+		applied: make(map[model.InstanceKey]*time.Timer),
+	}
+	q.load()
+	return q
+}
+
+// load 加载持久化的隔离名单，丢弃已过期的条目
+func (q *instanceQuarantine) load() {
+	content, err := ioutil.ReadFile(q.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.GetBaseLogger().Warnf("[CircuitBreaker] fail to read quarantine file %s: %v", q.path, err)
+		}
+		return
+	}
+	var entries []*quarantineEntry
+	if err = json.Unmarshal(content, &entries); err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to parse quarantine file %s: %v", q.path, err)
+		return
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		openTime := time.Unix(0, entry.OpenTime*int64(time.Millisecond))
+		if now.Sub(openTime) >= q.ttl {
+			continue
+		}
+		key := model.InstanceKey{
+			ServiceKey: model.ServiceKey{Namespace: entry.Namespace, Service: entry.Service},
+			Host:       entry.Host,
+			Port:       entry.Port,
+		}
+		q.entries[key] = openTime
+	}
+	if len(q.entries) > 0 {
+		log.GetBaseLogger().Infof("[CircuitBreaker] %d quarantined instances loaded from %s", len(q.entries), q.path)
+	}
+}
+
+// persist 持久化隔离名单，同时清理已过期的条目，调用方需持有锁
+func (q *instanceQuarantine) persist() {
+	now := time.Now()
+	entries := make([]*quarantineEntry, 0, len(q.entries))
+	for key, openTime := range q.entries {
+		if now.Sub(openTime) >= q.ttl {
+			delete(q.entries, key)
+			continue
+		}
+		entries = append(entries, &quarantineEntry{
+			Namespace: key.Namespace,
+			Service:   key.Service,
+			Host:      key.Host,
+			Port:      key.Port,
+			OpenTime:  openTime.UnixNano() / int64(time.Millisecond),
+		})
+	}
+	content, err := json.Marshal(entries)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to marshal quarantine entries: %v", err)
+		return
+	}
+	if err = model.EnsureAndVerifyDir(filepath.Dir(q.path)); err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to create quarantine dir: %v", err)
+		return
+	}
+	tmpPath := q.path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, content, 0600); err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to write quarantine file %s: %v", tmpPath, err)
+		return
+	}
+	if err = os.Rename(tmpPath, q.path); err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to rename quarantine file %s: %v", q.path, err)
+	}
+}
+
+// onCircuitBreakerEvent 实例熔断时加入隔离名单，恢复时移出
+func (q *instanceQuarantine) onCircuitBreakerEvent(event *common.PluginEvent) error {
+	cbEvent, ok := event.EventObject.(*model.CircuitBreakerEvent)
+	if !ok || cbEvent.Status == nil {
+		return nil
+	}
+	insRes, ok := cbEvent.Resource.(*model.InstanceResource)
+	if !ok {
+		return nil
+	}
+	key := model.InstanceKey{
+		ServiceKey: *insRes.GetService(),
+		Host:       insRes.GetNode().Host,
+		Port:       int(insRes.GetNode().Port),
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	switch cbEvent.Status.GetStatus() {
+	case model.Open:
+		q.entries[key] = cbEvent.Status.GetStartTime()
+	case model.Close:
+		if _, exist := q.entries[key]; !exist {
+			return nil
+		}
+		delete(q.entries, key)
+	default:
+		return nil
+	}
+	// 熔断插件重新接管了该实例的状态
+	q.releaseTimer(key)
+	q.persist()
+	return nil
+}
+
+// onServiceEvent 服务实例首次加载到缓存时，对隔离名单内的实例施加熔断状态
+func (q *instanceQuarantine) onServiceEvent(event *common.PluginEvent) error {
+	svcEvent, ok := event.EventObject.(*common.ServiceEventObject)
+	if !ok || svcEvent.SvcEventKey.Type != model.EventInstances {
+		return nil
+	}
+	svcKey := svcEvent.SvcEventKey.ServiceKey
+	now := time.Now()
+	q.mutex.Lock()
+	keys := make([]model.InstanceKey, 0)
+	for key, openTime := range q.entries {
+		if key.ServiceKey != svcKey {
+			continue
+		}
+		if _, exist := q.applied[key]; exist {
+			continue
+		}
+		remain := q.ttl - now.Sub(openTime)
+		if remain <= 0 {
+			continue
+		}
+		expireKey := key
+		q.applied[key] = time.AfterFunc(remain, func() {
+			q.expire(expireKey, openTime)
+		})
+		keys = append(keys, key)
+	}
+	q.mutex.Unlock()
+	if len(keys) == 0 {
+		return nil
+	}
+	// 事件回调中缓存尚在更新，异步施加熔断状态
+	go func() {
+		for _, key := range keys {
+			resource, err := model.NewInstanceResource(&key.ServiceKey, nil, "", key.Host, uint32(key.Port))
+			if err != nil {
+				continue
+			}
+			status := model.NewCircuitBreakerStatus(model.QuarantineRuleName, model.Open, now)
+			if err = q.flow.updateInstanceStatus(resource, status); err != nil {
+				log.GetBaseLogger().Warnf("[CircuitBreaker] fail to quarantine instance %s: %v", key, err)
+				continue
+			}
+			log.GetBaseLogger().Infof("[CircuitBreaker] instance %s quarantined by previous process", key)
+		}
+	}()
+	return nil
+}
+
+// expire 隔离到期，恢复为熔断插件自身判定的状态
+func (q *instanceQuarantine) expire(key model.InstanceKey, openTime time.Time) {
+	q.mutex.Lock()
+	if _, exist := q.applied[key]; !exist {
+		q.mutex.Unlock()
+		return
+	}
+	delete(q.applied, key)
+	if current, exist := q.entries[key]; exist && current.Equal(openTime) {
+		delete(q.entries, key)
+		q.persist()
+	}
+	q.mutex.Unlock()
+	q.restore(key)
+}
+
+// clear 管理员操作，清除指定实例或全部实例的隔离，返回清除的条目数
+func (q *instanceQuarantine) clear(keys []model.InstanceKey) int {
+	q.mutex.Lock()
+	if len(keys) == 0 {
+		keys = make([]model.InstanceKey, 0, len(q.entries))
+		for key := range q.entries {
+			keys = append(keys, key)
+		}
+	}
+	restores := make([]model.InstanceKey, 0, len(keys))
+	var cleared int
+	for _, key := range keys {
+		if _, exist := q.entries[key]; exist {
+			delete(q.entries, key)
+			cleared++
+		}
+		if q.releaseTimer(key) {
+			restores = append(restores, key)
+		}
+	}
+	if cleared > 0 {
+		q.persist()
+	}
+	q.mutex.Unlock()
+	for _, key := range restores {
+		q.restore(key)
+	}
+	return cleared
+}
+
+// releaseTimer 取消实例的隔离到期任务，调用方需持有锁
+func (q *instanceQuarantine) releaseTimer(key model.InstanceKey) bool {
+	timer, exist := q.applied[key]
+	if !exist {
+		return false
+	}
+	timer.Stop()
+	delete(q.applied, key)
+	return true
+}
+
+// restore 恢复实例为熔断插件自身判定的状态
+func (q *instanceQuarantine) restore(key model.InstanceKey) {
+	resource, err := model.NewInstanceResource(&key.ServiceKey, nil, "", key.Host, uint32(key.Port))
+	if err != nil {
+		return
+	}
+	var status model.CircuitBreakerStatus
+	if q.flow.resourceBreaker != nil {
+		status = q.flow.resourceBreaker.CheckResource(resource)
+	}
+	if status == nil {
+		status = model.NewCircuitBreakerStatus(model.QuarantineRuleName, model.Close, time.Now())
+	}
+	if err = q.flow.updateInstanceStatus(resource, status); err != nil {
+		log.GetBaseLogger().Warnf("[CircuitBreaker] fail to release quarantined instance %s: %v", key, err)
+		return
+	}
+	log.GetBaseLogger().Infof("[CircuitBreaker] instance %s released from quarantine", key)
+}
+
+// ClearQuarantine 清除熔断实例隔离名单，不指定实例时清除全部
+func (e *CircuitBreakerFlow) ClearQuarantine(keys ...model.InstanceKey) int {
+	if e.quarantine == nil {
+		return 0
+	}
+	return e.quarantine.clear(keys)
+}
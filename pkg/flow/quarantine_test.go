@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// TestInstanceQuarantine 测试熔断实例隔离名单的持久化、过期及清除
+func TestInstanceQuarantine(t *testing.T) {
+	persistDir := t.TempDir()
+	cbFlow := &CircuitBreakerFlow{resourceBreaker: &stubBreaker{}, bulkheads: newBulkheadManager(nil)}
+	svcKey := model.ServiceKey{Namespace: "default", Service: "svc"}
+	fresh := model.InstanceKey{ServiceKey: svcKey, Host: "127.0.0.1", Port: 8080}
+	stale := model.InstanceKey{ServiceKey: svcKey, Host: "127.0.0.1", Port: 8081}
+	recovered := model.InstanceKey{ServiceKey: svcKey, Host: "127.0.0.1", Port: 8082}
+	fire := func(q *instanceQuarantine, key model.InstanceKey, status model.Status, at time.Time) {
+		res, _ := model.NewInstanceResource(&key.ServiceKey, nil, "", key.Host, uint32(key.Port))
+		_ = q.onCircuitBreakerEvent(&common.PluginEvent{
+			EventType: common.OnCircuitBreakerStatusChanged,
+			EventObject: &model.CircuitBreakerEvent{
+				Resource: res,
+				Status:   model.NewCircuitBreakerStatus("rule", status, at),
+			},
+		})
+	}
+
+	q := newInstanceQuarantine(cbFlow, persistDir, time.Minute)
+	fire(q, fresh, model.Open, time.Now())
+	fire(q, stale, model.Open, time.Now().Add(-2*time.Minute))
+	fire(q, recovered, model.Open, time.Now())
+	fire(q, recovered, model.Close, time.Now())
+
+	// 模拟进程重启，过期及已恢复的实例不再隔离
+	restarted := newInstanceQuarantine(cbFlow, persistDir, time.Minute)
+	if len(restarted.entries) != 1 {
+		t.Fatalf("expect 1 quarantined instance, got %v", restarted.entries)
+	}
+	if _, ok := restarted.entries[fresh]; !ok {
+		t.Fatalf("instance %s should be quarantined", fresh)
+	}
+	_ = restarted.onServiceEvent(&common.PluginEvent{
+		EventType: common.OnServiceAdded,
+		EventObject: &common.ServiceEventObject{
+			SvcEventKey: model.ServiceEventKey{ServiceKey: svcKey, Type: model.EventInstances},
+		},
+	})
+	if _, ok := restarted.applied[fresh]; !ok {
+		t.Fatalf("quarantine should be applied to %s", fresh)
+	}
+
+	if cleared := restarted.clear(nil); cleared != 1 {
+		t.Fatalf("expect 1 instance cleared, got %d", cleared)
+	}
+	if len(restarted.applied) != 0 {
+		t.Fatal("quarantine timers should be released")
+	}
+	if len(newInstanceQuarantine(cbFlow, persistDir, time.Minute).entries) != 0 {
+		t.Fatal("cleared quarantine should be persisted")
+	}
+}
@@ -27,8 +27,10 @@ import (
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/expr"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -41,6 +43,8 @@ const (
 	Disabled = "rateLimit disabled"
 	// RuleNotExists is a constant for rules not exist.
 	RuleNotExists = "quota rule not exists"
+
+	taskRateLimitTopK = "quota-topk"
 )
 
 // FlowQuotaAssistant 限额流程的辅助类
@@ -70,6 +74,10 @@ type FlowQuotaAssistant struct {
 
 	remoteNamespace string
 	remoteService   string
+	// 热点标签值统计，未启用时为nil
+	topK *RateLimitTopK
+	// 本地配置的限流豁免
+	exemptions []*rateLimitExemption
 }
 
 // AsyncRateLimitConnector 异步限流连接器
@@ -129,6 +137,17 @@ func (f *FlowQuotaAssistant) Init(engine model.Engine, cfg config.Configuration,
 		DelayStart: true,
 	})
 	f.taskValues = taskValues
+	if topKCfg := cfg.GetProvider().GetRateLimit().GetTopK(); topKCfg.IsEnable() {
+		f.topK = NewRateLimitTopK(engine, topKCfg)
+		_, topKTaskValues := engine.ScheduleTask(&model.PeriodicTask{
+			Name:     taskRateLimitTopK,
+			CallBack: f.topK,
+			LongRun:  true,
+			Period:   topKCfg.GetInterval(),
+		})
+		schedule.StartTask(taskRateLimitTopK, topKTaskValues, map[interface{}]model.TaskValue{
+			taskRateLimitTopK: &data.AllEqualsComparable{}})
+	}
 	supplier.RegisterEventSubscriber(common.OnServiceUpdated,
 		common.PluginEventHandler{Callback: f.OnServiceUpdated})
 	supplier.RegisterEventSubscriber(common.OnServiceDeleted,
@@ -140,6 +159,7 @@ func (f *FlowQuotaAssistant) Init(engine model.Engine, cfg config.Configuration,
 	f.purgeIntervalMilli = model.ToMilliSeconds(cfg.GetProvider().GetRateLimit().GetPurgeInterval())
 	f.remoteNamespace = cfg.GetProvider().GetRateLimit().GetLimiterNamespace()
 	f.remoteService = cfg.GetProvider().GetRateLimit().GetLimiterService()
+	f.exemptions = newRateLimitExemptions(cfg.GetProvider().GetRateLimit().GetExemptions())
 	f.mutex = &sync.Mutex{}
 	f.svcToWindowSet = &sync.Map{}
 	return nil
@@ -243,10 +263,17 @@ func (f *FlowQuotaAssistant) GetQuota(commonRequest *data.CommonRateLimitRequest
 		}
 		return model.QuotaFutureWithResponse(resp), nil
 	}
-	windows, err := f.lookupRateLimitWindow(commonRequest)
-	if err != nil {
+	if exemption := f.lookupLocalExemption(commonRequest); len(exemption) > 0 {
+		return exemptedResponse(exemption), nil
+	}
+	if err := f.syncRateLimitRule(commonRequest); err != nil {
 		return nil, err
 	}
+	if rule := lookupExemptionRule(commonRequest.RateLimitRule, commonRequest.Method,
+		commonRequest.Arguments); rule != nil {
+		return exemptedResponse(rule.GetName().GetValue()), nil
+	}
+	windows := f.lookupRateLimitWindow(commonRequest)
 	if len(windows) == 0 {
 		// 没有限流规则，直接放通
 		resp := &model.QuotaResponse{
@@ -256,40 +283,47 @@ func (f *FlowQuotaAssistant) GetQuota(commonRequest *data.CommonRateLimitRequest
 		return model.QuotaFutureWithResponse(resp), nil
 	}
 	var maxWaitMs int64 = 0
+	var maxQueueDepth int
 	for _, window := range windows {
 		window.Init()
 		quotaResult := window.AllocateQuota(commonRequest)
+		if nil != f.topK {
+			f.topK.Record(commonRequest, window.Rule, quotaResult.Code == model.QuotaResultLimited)
+		}
 		if quotaResult.Code == model.QuotaResultLimited {
 			return model.QuotaFutureWithResponse(quotaResult), nil
 		}
 		if quotaResult.WaitMs > maxWaitMs {
 			maxWaitMs = quotaResult.WaitMs
 		}
+		if quotaResult.QueueDepth > maxQueueDepth {
+			maxQueueDepth = quotaResult.QueueDepth
+		}
 	}
 	return model.QuotaFutureWithResponse(&model.QuotaResponse{
-		Code:   model.QuotaResultOk,
-		WaitMs: maxWaitMs,
+		Code:       model.QuotaResultOk,
+		WaitMs:     maxWaitMs,
+		QueueDepth: maxQueueDepth,
 	}), nil
 }
 
-// lookupRateLimitWindow 计算限流窗口
-func (f *FlowQuotaAssistant) lookupRateLimitWindow(
-	commonRequest *data.CommonRateLimitRequest) ([]*RateLimitWindow, error) {
-	var err error
-	// 1. 并发获取被调服务信息和限流配置，服务不存在，返回错误
-	if err = f.engine.SyncGetResources(commonRequest); err != nil {
+// syncRateLimitRule 并发获取被调服务信息和限流配置，服务不存在时不返回错误
+func (f *FlowQuotaAssistant) syncRateLimitRule(commonRequest *data.CommonRateLimitRequest) error {
+	if err := f.engine.SyncGetResources(commonRequest); err != nil {
 		sdkErr, ok := err.(model.SDKError)
-		if !ok {
-			return nil, err
-		}
-		if sdkErr.ErrorCode() != model.ErrCodeServiceNotFound {
-			return nil, err
+		if !ok || sdkErr.ErrorCode() != model.ErrCodeServiceNotFound {
+			return err
 		}
 	}
-	// 2. 寻找匹配的规则
+	return nil
+}
+
+// lookupRateLimitWindow 计算限流窗口
+func (f *FlowQuotaAssistant) lookupRateLimitWindow(commonRequest *data.CommonRateLimitRequest) []*RateLimitWindow {
+	// 1. 寻找匹配的规则
 	rules := lookupRules(commonRequest.RateLimitRule, commonRequest.Method, commonRequest.Arguments)
 	if len(rules) == 0 {
-		return nil, nil
+		return nil
 	}
 	windows := make([]*RateLimitWindow, 0, len(rules))
 	for _, rule := range rules {
@@ -305,7 +339,31 @@ func (f *FlowQuotaAssistant) lookupRateLimitWindow(
 			windows = append(windows, window)
 		}
 	}
-	return windows, nil
+	return windows
+}
+
+// matchLabelValue 匹配可能为多值的标签，NOT_EQUALS 及 NOT_IN 需要全部取值满足，其他匹配方式任一取值满足即可
+func matchLabelValue(matchString *apimodel.MatchString, value string, ruleCache model.RuleCache) bool {
+	if !strings.Contains(value, model.MultiValueSeparator) {
+		return matchStringValue(matchString, value, ruleCache)
+	}
+	values := strings.Split(value, model.MultiValueSeparator)
+	switch matchString.GetType() {
+	case apimodel.MatchString_NOT_EQUALS, apimodel.MatchString_NOT_IN:
+		for _, v := range values {
+			if !matchStringValue(matchString, v, ruleCache) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, v := range values {
+			if matchStringValue(matchString, v, ruleCache) {
+				return true
+			}
+		}
+		return false
+	}
 }
 
 func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache model.RuleCache) bool {
@@ -317,7 +375,7 @@ func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache
 
 	switch matchType {
 	case apimodel.MatchString_EXACT:
-		return value == matchValue
+		return matchToken(matchValue, value)
 	case apimodel.MatchString_REGEX:
 		regexObj, err := ruleCache.GetRegexMatcher(matchValue)
 		if nil != err {
@@ -336,11 +394,11 @@ func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache
 		}
 		return true
 	case apimodel.MatchString_NOT_EQUALS:
-		return value != matchValue
+		return !matchToken(matchValue, value)
 	case apimodel.MatchString_IN:
 		tokens := strings.Split(matchValue, ",")
 		for _, token := range tokens {
-			if token == value {
+			if matchToken(token, value) {
 				return true
 			}
 		}
@@ -348,17 +406,19 @@ func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache
 	case apimodel.MatchString_NOT_IN:
 		tokens := strings.Split(matchValue, ",")
 		for _, token := range tokens {
-			if token == value {
+			if matchToken(token, value) {
 				return false
 			}
 		}
 		return true
+	case apimodel.MatchString_RANGE:
+		return matchRange(matchValue, value)
 	}
 	return false
 }
 
-// lookupRule 寻址规则
-func lookupRules(svcRule model.ServiceRule, method string, arguments map[apitraffic.MatchArgument_Type]map[string]string) []*apitraffic.Rule {
+// getEnabledRules 获取校验通过的规则集中未停用的规则
+func getEnabledRules(svcRule model.ServiceRule) []*apitraffic.Rule {
 	if reflect2.IsNil(svcRule) || reflect2.IsNil(svcRule.GetValue()) {
 		// 规则集为空
 		return nil
@@ -367,53 +427,75 @@ func lookupRules(svcRule model.ServiceRule, method string, arguments map[apitraf
 	if nil != validateErr {
 		return nil
 	}
-	ruleCache := svcRule.GetRuleCache()
 	rateLimiting := svcRule.GetValue().(*apitraffic.RateLimit)
-	rulesList := rateLimiting.Rules
+	rules := make([]*apitraffic.Rule, 0, len(rateLimiting.Rules))
+	for _, rule := range rateLimiting.Rules {
+		if nil != rule.GetDisable() && rule.GetDisable().GetValue() {
+			// 规则被停用
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// lookupRule 寻址规则
+func lookupRules(svcRule model.ServiceRule, method string, arguments map[apitraffic.MatchArgument_Type]map[string]string) []*apitraffic.Rule {
+	rulesList := getEnabledRules(svcRule)
 	if len(rulesList) == 0 {
 		return nil
 	}
+	ruleCache := svcRule.GetRuleCache()
 	matchRules := make([]*apitraffic.Rule, 0)
 	for _, rule := range rulesList {
-		if nil != rule.GetDisable() && rule.GetDisable().GetValue() {
-			// 规则被停用
+		if len(rule.Amounts) == 0 || isExemptionRule(rule) {
 			continue
 		}
-		if len(rule.Amounts) == 0 {
-			continue
+		if matchRule(rule, method, arguments, ruleCache) {
+			matchRules = append(matchRules, rule)
 		}
-		methodMatcher := rule.Method
-		if nil != methodMatcher {
-			matchMethod := matchStringValue(methodMatcher, method, ruleCache)
-			if !matchMethod {
-				continue
-			}
+	}
+	return matchRules
+}
+
+// lookupExemptionRule 查找请求匹配的豁免规则
+func lookupExemptionRule(svcRule model.ServiceRule, method string,
+	arguments map[apitraffic.MatchArgument_Type]map[string]string) *apitraffic.Rule {
+	for _, rule := range getEnabledRules(svcRule) {
+		if isExemptionRule(rule) && matchRule(rule, method, arguments, svcRule.GetRuleCache()) {
+			return rule
 		}
-		argumentMatchers := rule.Arguments
-		matched := true
-		if len(argumentMatchers) > 0 {
-			for _, argumentMatcher := range argumentMatchers {
-				stringStringMap := arguments[argumentMatcher.Type]
-				if len(stringStringMap) == 0 {
-					matched = false
-					break
-				}
-				labelValue, ok := getLabelValue(argumentMatcher, stringStringMap)
-				if !ok {
-					matched = false
-				} else {
-					matched = matchStringValue(argumentMatcher.GetValue(), labelValue, ruleCache)
-				}
-				if !matched {
-					break
-				}
+	}
+	return nil
+}
+
+// matchRule 请求的方法及参数是否匹配规则
+func matchRule(rule *apitraffic.Rule, method string, arguments map[apitraffic.MatchArgument_Type]map[string]string,
+	ruleCache model.RuleCache) bool {
+	methodMatcher := rule.Method
+	if nil != methodMatcher {
+		matchMethod := matchStringValue(methodMatcher, method, ruleCache)
+		if !matchMethod {
+			return false
+		}
+	}
+	for _, argumentMatcher := range rule.Arguments {
+		if isExprArgument(argumentMatcher) {
+			if !matchExprArgument(argumentMatcher, method, arguments) {
+				return false
 			}
+			continue
 		}
-		if matched {
-			matchRules = append(matchRules, rule)
+		stringStringMap := arguments[argumentMatcher.Type]
+		if len(stringStringMap) == 0 {
+			return false
+		}
+		labelValue, ok := getLabelValue(argumentMatcher, stringStringMap)
+		if !ok || !matchLabelValue(argumentMatcher.GetValue(), labelValue, ruleCache) {
+			return false
 		}
 	}
-	return matchRules
+	return true
 }
 
 // FormatLabelToStr 格式化字符串
@@ -437,13 +519,19 @@ func FormatLabelToStr(request *data.CommonRateLimitRequest, rule *apitraffic.Rul
 	for _, argumentMatcher := range argumentsList {
 		var labelValue string
 		valueMatcher := argumentMatcher.GetValue()
-		if regexCombine && valueMatcher.GetType() != apimodel.MatchString_EXACT {
+		if isExprArgument(argumentMatcher) {
+			// 表达式匹配的请求共享同一个限流窗口
+			labelValue = valueMatcher.GetValue().GetValue()
+		} else if regexCombine && valueMatcher.GetType() != apimodel.MatchString_EXACT {
 			labelValue = valueMatcher.GetValue().GetValue()
 		} else {
 			stringStringMap := request.Arguments[argumentMatcher.GetType()]
 			labelValue, _ = getLabelValue(argumentMatcher, stringStringMap)
 			if valueMatcher.GetType() != apimodel.MatchString_EXACT {
 				regexSpread = true
+			} else if strings.Contains(labelValue, model.MultiValueSeparator) {
+				// 多值标签精确匹配时，按规则中的取值共享同一个限流窗口
+				labelValue = valueMatcher.GetValue().GetValue()
 			}
 		}
 		labelEntry := getLabelEntry(argumentMatcher, labelValue)
@@ -455,6 +543,50 @@ func FormatLabelToStr(request *data.CommonRateLimitRequest, rule *apitraffic.Rul
 	return methodValue + config.DefaultMapKVTupleSeparator + strings.Join(tmpList, config.DefaultMapKVTupleSeparator), regexSpread
 }
 
+// isExprArgument 是否为表达式参数，自定义参数的key为 $expr 时，value为表达式
+func isExprArgument(matchArgument *apitraffic.MatchArgument) bool {
+	return matchArgument.GetType() == apitraffic.MatchArgument_CUSTOM && matchArgument.GetKey() == expr.RuleLabelKey
+}
+
+// matchExprArgument 将请求参数转换为标签后对表达式进行求值
+func matchExprArgument(matchArgument *apitraffic.MatchArgument, method string,
+	arguments map[apitraffic.MatchArgument_Type]map[string]string) bool {
+	exprText := matchArgument.GetValue().GetValue().GetValue()
+	program, err := expr.CompileCached(exprText)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[RateLimit] fail to compile expression %s, err: %v", exprText, err)
+		return false
+	}
+	labels := make(map[string]string)
+	if len(method) > 0 {
+		labels[model.LabelKeyMethod] = method
+	}
+	for argType, values := range arguments {
+		for key, value := range values {
+			switch argType {
+			case apitraffic.MatchArgument_CUSTOM:
+				labels[key] = value
+			case apitraffic.MatchArgument_METHOD:
+				labels[model.LabelKeyMethod] = value
+			case apitraffic.MatchArgument_CALLER_IP:
+				labels[model.LabelKeyCallerIp] = value
+			case apitraffic.MatchArgument_HEADER:
+				labels[model.LabelKeyHeader+key] = value
+			case apitraffic.MatchArgument_QUERY:
+				labels[model.LabelKeyQuery+key] = value
+			case apitraffic.MatchArgument_CALLER_SERVICE:
+				labels[model.LabelKeyCallerService+key] = value
+			}
+		}
+	}
+	matched, err := program.Match(expr.NewLabelsActivation(labels))
+	if err != nil {
+		log.GetBaseLogger().Debugf("[RateLimit] fail to eval expression %s, err: %v", exprText, err)
+		return false
+	}
+	return matched
+}
+
 func getLabelValue(matchArgument *apitraffic.MatchArgument, stringStringMap map[string]string) (string, bool) {
 	switch matchArgument.GetType() {
 	case apitraffic.MatchArgument_CUSTOM, apitraffic.MatchArgument_HEADER, apitraffic.MatchArgument_QUERY, apitraffic.MatchArgument_CALLER_SERVICE:
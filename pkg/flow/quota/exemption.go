@@ -0,0 +1,149 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"net"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// RuleMetadataExemption 限流规则metadata中标记豁免规则的key，取值为true时匹配规则的请求绕过限流
+	RuleMetadataExemption = "exemption"
+	// Exempted 请求匹配限流豁免，直接放通
+	Exempted = "quota exempted"
+)
+
+// rateLimitExemption 本地配置的限流豁免，CIDR在初始化时解析
+type rateLimitExemption struct {
+	name           string
+	namespace      string
+	service        string
+	method         string
+	callerIPs      []*net.IPNet
+	callerServices []model.ServiceKey
+	labels         map[string]string
+}
+
+func newRateLimitExemptions(cfgs []*config.RateLimitExemptionConfig) []*rateLimitExemption {
+	exemptions := make([]*rateLimitExemption, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		exemption := &rateLimitExemption{
+			name:      cfg.Name,
+			namespace: cfg.Namespace,
+			service:   cfg.Service,
+			method:    cfg.Method,
+			labels:    cfg.Labels,
+		}
+		for _, callerIP := range cfg.CallerIPs {
+			// 配置校验时已经检查过格式
+			if ipNet, err := config.ParseIPNet(callerIP); err == nil {
+				exemption.callerIPs = append(exemption.callerIPs, ipNet)
+			}
+		}
+		for _, callerService := range cfg.CallerServices {
+			namespace, service := config.SplitCallerService(callerService)
+			exemption.callerServices = append(exemption.callerServices,
+				model.ServiceKey{Namespace: namespace, Service: service})
+		}
+		exemptions = append(exemptions, exemption)
+	}
+	return exemptions
+}
+
+// match 请求是否匹配豁免，配置的各项条件需要同时满足
+func (e *rateLimitExemption) match(request *data.CommonRateLimitRequest) bool {
+	if len(e.namespace) > 0 && e.namespace != request.DstService.Namespace {
+		return false
+	}
+	if len(e.service) > 0 && e.service != request.DstService.Service {
+		return false
+	}
+	if len(e.method) > 0 && e.method != request.Method {
+		return false
+	}
+	if len(e.callerIPs) > 0 && !e.matchCallerIP(request.Arguments[apitraffic.MatchArgument_CALLER_IP]) {
+		return false
+	}
+	if len(e.callerServices) > 0 &&
+		!e.matchCallerService(request.Arguments[apitraffic.MatchArgument_CALLER_SERVICE]) {
+		return false
+	}
+	customs := request.Arguments[apitraffic.MatchArgument_CUSTOM]
+	for key, value := range e.labels {
+		if actual, ok := customs[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *rateLimitExemption) matchCallerIP(values map[string]string) bool {
+	for _, value := range values {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range e.callerIPs {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *rateLimitExemption) matchCallerService(values map[string]string) bool {
+	for namespace, service := range values {
+		for _, callerService := range e.callerServices {
+			if callerService.Namespace == namespace && callerService.Service == service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupLocalExemption 查找请求匹配的本地豁免，返回豁免名称
+func (f *FlowQuotaAssistant) lookupLocalExemption(request *data.CommonRateLimitRequest) string {
+	for _, exemption := range f.exemptions {
+		if exemption.match(request) {
+			return exemption.name
+		}
+	}
+	return ""
+}
+
+// isExemptionRule 是否为豁免规则
+func isExemptionRule(rule *apitraffic.Rule) bool {
+	return rule.GetMetadata()[RuleMetadataExemption] == "true"
+}
+
+// exemptedResponse 豁免请求的应答
+func exemptedResponse(exemption string) *model.QuotaFutureImpl {
+	return model.QuotaFutureWithResponse(&model.QuotaResponse{
+		Code:      model.QuotaResultOk,
+		Info:      Exempted,
+		Exemption: exemption,
+	})
+}
@@ -0,0 +1,141 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject"
+)
+
+// TestLocalExemption 测试本地配置的限流豁免匹配
+func TestLocalExemption(t *testing.T) {
+	exemption := &config.RateLimitExemptionConfig{
+		Name:           "health-checker",
+		Service:        "echo",
+		CallerIPs:      []string{"10.0.0.0/8", "192.168.1.1"},
+		CallerServices: []string{"Polaris/checker"},
+	}
+	if err := exemption.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&config.RateLimitExemptionConfig{Name: "all"}).Verify(); err == nil {
+		t.Fatal("exemption without conditions should be rejected")
+	}
+	if err := (&config.RateLimitExemptionConfig{Name: "ip", CallerIPs: []string{"10.0.0"}}).Verify(); err == nil {
+		t.Fatal("invalid caller ip should be rejected")
+	}
+	assistant := &FlowQuotaAssistant{exemptions: newRateLimitExemptions([]*config.RateLimitExemptionConfig{
+		exemption, {Name: "admin", Labels: map[string]string{"client": "admin"}},
+	})}
+	newRequest := func(service string, arguments ...model.Argument) *data.CommonRateLimitRequest {
+		request := &data.CommonRateLimitRequest{
+			DstService: model.ServiceKey{Namespace: "default", Service: service},
+			Arguments:  map[apitraffic.MatchArgument_Type]map[string]string{},
+		}
+		for _, argument := range arguments {
+			argType := apitraffic.MatchArgument_CUSTOM
+			switch argument.ArgumentType() {
+			case model.ArgumentTypeCallerIP:
+				argType = apitraffic.MatchArgument_CALLER_IP
+			case model.ArgumentTypeCallerService:
+				argType = apitraffic.MatchArgument_CALLER_SERVICE
+			}
+			if request.Arguments[argType] == nil {
+				request.Arguments[argType] = map[string]string{}
+			}
+			request.Arguments[argType][argument.Key()] = argument.Value()
+		}
+		return request
+	}
+	checker := model.BuildCallerServiceArgument("Polaris", "checker")
+	cases := []struct {
+		name    string
+		request *data.CommonRateLimitRequest
+		expect  string
+	}{
+		{"cidr and caller", newRequest("echo", model.BuildCallerIPArgument("10.1.2.3"), checker), "health-checker"},
+		{"single ip", newRequest("echo", model.BuildCallerIPArgument("192.168.1.1"), checker), "health-checker"},
+		{"ip miss", newRequest("echo", model.BuildCallerIPArgument("11.1.2.3"), checker), ""},
+		{"caller miss", newRequest("echo", model.BuildCallerIPArgument("10.1.2.3")), ""},
+		{"service miss", newRequest("other", model.BuildCallerIPArgument("10.1.2.3"), checker), ""},
+		{"labels", newRequest("other", model.BuildCustomArgument("client", "admin")), "admin"},
+		{"labels miss", newRequest("other", model.BuildCustomArgument("client", "user")), ""},
+	}
+	for _, c := range cases {
+		if actual := assistant.lookupLocalExemption(c.request); actual != c.expect {
+			t.Errorf("%s: expect %q, actual %q", c.name, c.expect, actual)
+		}
+	}
+}
+
+// TestExemptionRule 测试限流规则metadata标记的豁免规则
+func TestExemptionRule(t *testing.T) {
+	callerIP := &apitraffic.MatchArgument{
+		Type:  apitraffic.MatchArgument_CALLER_IP,
+		Value: newMatchString(apimodel.MatchString_EXACT, "10.0.0.0/8"),
+	}
+	svcRule := pb.NewServiceRuleInProto(&apiservice.DiscoverResponse{
+		Type:    apiservice.DiscoverResponse_RATE_LIMIT,
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String("echo")},
+		RateLimit: &apitraffic.RateLimit{
+			Revision: wrapperspb.String("v1"),
+			Rules: []*apitraffic.Rule{
+				{
+					Name:      wrapperspb.String("internal"),
+					Arguments: []*apitraffic.MatchArgument{callerIP},
+					Metadata:  map[string]string{RuleMetadataExemption: "true"},
+				},
+				{
+					Name:    wrapperspb.String("limit"),
+					Amounts: []*apitraffic.Amount{{MaxAmount: wrapperspb.UInt32(1), ValidDuration: durationpb.New(time.Second)}},
+				},
+			},
+		},
+	})
+	if err := svcRule.ValidateAndBuildCache(); err != nil {
+		t.Fatal(err)
+	}
+	internal := map[apitraffic.MatchArgument_Type]map[string]string{
+		apitraffic.MatchArgument_CALLER_IP: {"": "10.1.2.3"},
+	}
+	if rule := lookupExemptionRule(svcRule, "", internal); rule.GetName().GetValue() != "internal" {
+		t.Fatalf("expect exemption rule internal, got %v", rule)
+	}
+	external := map[apitraffic.MatchArgument_Type]map[string]string{
+		apitraffic.MatchArgument_CALLER_IP: {"": "11.1.2.3"},
+	}
+	if rule := lookupExemptionRule(svcRule, "", external); rule != nil {
+		t.Fatalf("expect no exemption rule, got %v", rule)
+	}
+	rules := lookupRules(svcRule, "", internal)
+	if len(rules) != 1 || rules[0].GetName().GetValue() != "limit" {
+		t.Fatalf("exemption rule should not be used as limit rule, got %v", rules)
+	}
+}
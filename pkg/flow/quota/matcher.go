@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// rangeSeparator RANGE 匹配的上下界分隔符，格式为 min~max，任一侧为空表示不设界
+const rangeSeparator = "~"
+
+// cidrCache 规则中解析过的 CIDR，解析失败的记录为nil
+var cidrCache sync.Map
+
+// matchToken 单个规则取值的匹配，规则取值为 CIDR 且标签为IP时按网段匹配
+func matchToken(token string, value string) bool {
+	if token == value {
+		return true
+	}
+	if !strings.Contains(token, "/") {
+		return false
+	}
+	ipNet := parseCIDR(token)
+	if ipNet == nil {
+		return false
+	}
+	ip := net.ParseIP(value)
+	return ip != nil && ipNet.Contains(ip)
+}
+
+func parseCIDR(token string) *net.IPNet {
+	if value, ok := cidrCache.Load(token); ok {
+		return value.(*net.IPNet)
+	}
+	_, ipNet, err := net.ParseCIDR(token)
+	if err != nil {
+		ipNet = nil
+	}
+	cidrCache.Store(token, ipNet)
+	return ipNet
+}
+
+// matchRange 数值区间匹配，上下界均为闭区间
+func matchRange(ruleValue string, value string) bool {
+	bounds := strings.SplitN(ruleValue, rangeSeparator, 2)
+	if len(bounds) != 2 {
+		return false
+	}
+	num, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return false
+	}
+	if lower := strings.TrimSpace(bounds[0]); len(lower) > 0 {
+		min, err := strconv.ParseFloat(lower, 64)
+		if err != nil || num < min {
+			return false
+		}
+	}
+	if upper := strings.TrimSpace(bounds[1]); len(upper) > 0 {
+		max, err := strconv.ParseFloat(upper, 64)
+		if err != nil || num > max {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newMatchString(matchType apimodel.MatchString_MatchStringType, value string) *apimodel.MatchString {
+	return &apimodel.MatchString{Type: matchType, Value: &wrapperspb.StringValue{Value: value}}
+}
+
+// TestMatchLabelValue 测试多值、区间及CIDR标签匹配
+func TestMatchLabelValue(t *testing.T) {
+	multi := model.BuildMultiValueArgument(model.ArgumentTypeCustom, "product", "p1", "p2").Value()
+	cases := []struct {
+		name    string
+		matcher *apimodel.MatchString
+		value   string
+		expect  bool
+	}{
+		{"exact multi hit", newMatchString(apimodel.MatchString_EXACT, "p2"), multi, true},
+		{"exact multi miss", newMatchString(apimodel.MatchString_EXACT, "p3"), multi, false},
+		{"in multi", newMatchString(apimodel.MatchString_IN, "p3,p1"), multi, true},
+		{"not equals multi", newMatchString(apimodel.MatchString_NOT_EQUALS, "p1"), multi, false},
+		{"not in multi", newMatchString(apimodel.MatchString_NOT_IN, "p3,p4"), multi, true},
+		{"range hit", newMatchString(apimodel.MatchString_RANGE, "10~20"), "15", true},
+		{"range upper bound", newMatchString(apimodel.MatchString_RANGE, "10~20"), "20", true},
+		{"range miss", newMatchString(apimodel.MatchString_RANGE, "10~20"), "20.5", false},
+		{"range open lower", newMatchString(apimodel.MatchString_RANGE, "~0"), "-3", true},
+		{"range not number", newMatchString(apimodel.MatchString_RANGE, "10~"), "abc", false},
+		{"cidr hit", newMatchString(apimodel.MatchString_EXACT, "10.0.0.0/8"), "10.1.2.3", true},
+		{"cidr miss", newMatchString(apimodel.MatchString_EXACT, "10.0.0.0/8"), "11.1.2.3", false},
+		{"cidr in", newMatchString(apimodel.MatchString_IN, "192.168.0.0/16,10.0.0.1"), "10.0.0.1", true},
+		{"cidr not in", newMatchString(apimodel.MatchString_NOT_IN, "192.168.0.0/16"), "192.168.3.4", false},
+	}
+	for _, c := range cases {
+		if actual := matchLabelValue(c.matcher, c.value, nil); actual != c.expect {
+			t.Errorf("%s: expect %v, actual %v", c.name, c.expect, actual)
+		}
+	}
+}
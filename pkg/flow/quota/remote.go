@@ -38,6 +38,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	limitpb "github.com/polarismesh/polaris-go/pkg/model/pb/metric/v2"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 // ResponseCallBack 应答回调函数
@@ -219,7 +220,9 @@ func (s *StreamCounterSet) preInitCheck(
 			return nil
 		}
 		s.serviceStream = serviceStream
-		go s.processResponse(s.serviceStream)
+		profiling.Go("rateLimiter", "processQuotaResponse", func() {
+			s.processResponse(serviceStream)
+		})
 	}
 	if nil == s.initialingWindows {
 		s.initialingWindows = make(map[CounterIdentifier]*InitializeRecord)
@@ -0,0 +1,203 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// topKCounter 单个标签值的计数，weight用于排序，淘汰时会继承被淘汰计数器的weight
+type topKCounter struct {
+	labels  string
+	weight  int64
+	passed  int64
+	limited int64
+}
+
+// ruleTopK 单条限流规则的热点标签值统计，使用Space-Saving算法限制跟踪的标签值数量
+type ruleTopK struct {
+	mutex     sync.Mutex
+	namespace string
+	service   string
+	ruleName  string
+	counters  map[string]*topKCounter
+}
+
+// record 记录一次配额分配结果
+func (r *ruleTopK) record(labels string, limited bool, maxTracked int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	counter, ok := r.counters[labels]
+	if !ok {
+		var weight int64
+		if len(r.counters) >= maxTracked {
+			// 淘汰计数最小的标签值，新标签值继承其计数，保证真实的热点不会被低估
+			least := r.minCounter()
+			delete(r.counters, least.labels)
+			weight = least.weight
+		}
+		counter = &topKCounter{labels: labels, weight: weight}
+		r.counters[labels] = counter
+	}
+	counter.weight++
+	if limited {
+		counter.limited++
+	} else {
+		counter.passed++
+	}
+}
+
+func (r *ruleTopK) minCounter() *topKCounter {
+	var least *topKCounter
+	for _, counter := range r.counters {
+		if least == nil || counter.weight < least.weight {
+			least = counter
+		}
+	}
+	return least
+}
+
+// collect 取出计数最大的size个标签值并重置统计
+func (r *ruleTopK) collect(size int) []model.RateLimitTopKEntry {
+	r.mutex.Lock()
+	counters := make([]*topKCounter, 0, len(r.counters))
+	for _, counter := range r.counters {
+		counters = append(counters, counter)
+	}
+	r.counters = make(map[string]*topKCounter)
+	r.mutex.Unlock()
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].weight != counters[j].weight {
+			return counters[i].weight > counters[j].weight
+		}
+		return counters[i].labels < counters[j].labels
+	})
+	if len(counters) > size {
+		counters = counters[:size]
+	}
+	entries := make([]model.RateLimitTopKEntry, 0, len(counters))
+	for _, counter := range counters {
+		entries = append(entries, model.RateLimitTopKEntry{
+			Labels:  counter.labels,
+			Passed:  counter.passed,
+			Limited: counter.limited,
+		})
+	}
+	return entries
+}
+
+// RateLimitTopK 按限流规则统计消耗配额最多的标签值，并周期性通过统计上报插件上报
+type RateLimitTopK struct {
+	engine     model.Engine
+	size       int
+	maxTracked int
+	interval   time.Duration
+	// 规则ID到统计数据的映射
+	rules sync.Map
+}
+
+// NewRateLimitTopK 创建热点标签值统计
+func NewRateLimitTopK(engine model.Engine, cfg config.RateLimitTopKConfig) *RateLimitTopK {
+	return &RateLimitTopK{
+		engine:     engine,
+		size:       cfg.GetSize(),
+		maxTracked: cfg.GetMaxTracked(),
+		interval:   cfg.GetInterval(),
+	}
+}
+
+// Record 记录请求在各条命中规则上的配额分配结果
+func (t *RateLimitTopK) Record(commonRequest *data.CommonRateLimitRequest, rule *apitraffic.Rule, limited bool) {
+	labels := formatMatchedLabels(commonRequest, rule)
+	if len(labels) == 0 {
+		return
+	}
+	ruleID := rule.GetId().GetValue()
+	value, ok := t.rules.Load(ruleID)
+	if !ok {
+		ruleName := rule.GetName().GetValue()
+		if len(ruleName) == 0 {
+			ruleName = ruleID
+		}
+		value, _ = t.rules.LoadOrStore(ruleID, &ruleTopK{
+			namespace: commonRequest.DstService.Namespace,
+			service:   commonRequest.DstService.Service,
+			ruleName:  ruleName,
+			counters:  make(map[string]*topKCounter),
+		})
+	}
+	value.(*ruleTopK).record(labels, limited, t.maxTracked)
+}
+
+// Report 上报本周期各规则的热点标签值，周期内没有流量的规则会被移除
+func (t *RateLimitTopK) Report() {
+	t.rules.Range(func(key, value interface{}) bool {
+		stat := value.(*ruleTopK)
+		entries := stat.collect(t.size)
+		if len(entries) == 0 {
+			t.rules.Delete(key)
+			return true
+		}
+		_ = t.engine.SyncReportStat(model.RateLimitTopKStat, &model.RateLimitTopKGauge{
+			Namespace: stat.namespace,
+			Service:   stat.service,
+			RuleName:  stat.ruleName,
+			Entries:   entries,
+			Interval:  t.interval,
+		})
+		return true
+	})
+}
+
+// Process 周期上报任务回调
+func (t *RateLimitTopK) Process(
+	taskKey interface{}, taskValue interface{}, lastProcessTime time.Time) model.TaskResult {
+	t.Report()
+	return model.CONTINUE
+}
+
+// OnTaskEvent 任务事件回调
+func (t *RateLimitTopK) OnTaskEvent(event model.TaskEvent) {
+}
+
+// formatMatchedLabels 使用请求中的实际取值格式化规则匹配的标签，与窗口标签不同，正则及多值匹配时不会合并取值
+func formatMatchedLabels(request *data.CommonRateLimitRequest, rule *apitraffic.Rule) string {
+	var entries []string
+	if methodMatcher := rule.GetMethod(); nil != methodMatcher && !pb.IsMatchAllValue(methodMatcher) {
+		entries = append(entries, apitraffic.MatchArgument_METHOD.String()+
+			config.DefaultMapKeyValueSeparator+request.Method)
+	}
+	for _, argumentMatcher := range rule.GetArguments() {
+		labelValue, _ := getLabelValue(argumentMatcher, request.Arguments[argumentMatcher.GetType()])
+		if entry := getLabelEntry(argumentMatcher, labelValue); len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, config.DefaultMapKVTupleSeparator)
+}
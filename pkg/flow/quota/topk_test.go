@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"fmt"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+)
+
+// TestRuleTopK 测试热点标签值在跟踪数量受限时仍能被统计出来
+func TestRuleTopK(t *testing.T) {
+	stat := &ruleTopK{counters: make(map[string]*topKCounter)}
+	const maxTracked = 4
+	for i := 0; i < 100; i++ {
+		stat.record("uid:hot", i%10 == 0, maxTracked)
+		// 大量只出现一次的标签值不断挤占跟踪位置
+		stat.record(fmt.Sprintf("uid:cold-%d", i), false, maxTracked)
+	}
+	if len(stat.counters) > maxTracked {
+		t.Fatalf("tracked %d label values, expect at most %d", len(stat.counters), maxTracked)
+	}
+	entries := stat.collect(2)
+	if len(entries) != 2 {
+		t.Fatalf("expect 2 entries, actual %d", len(entries))
+	}
+	if entries[0].Labels != "uid:hot" || entries[0].Passed != 90 || entries[0].Limited != 10 {
+		t.Fatalf("unexpected top entry %+v", entries[0])
+	}
+	if len(stat.collect(2)) != 0 {
+		t.Fatalf("counters should be reset after collect")
+	}
+}
+
+// TestFormatMatchedLabels 测试使用请求的实际取值格式化标签
+func TestFormatMatchedLabels(t *testing.T) {
+	rule := &apitraffic.Rule{
+		Arguments: []*apitraffic.MatchArgument{
+			{Type: apitraffic.MatchArgument_CUSTOM, Key: "uid",
+				Value: newMatchString(apimodel.MatchString_REGEX, ".*")},
+		},
+	}
+	request := &data.CommonRateLimitRequest{
+		Arguments: map[apitraffic.MatchArgument_Type]map[string]string{
+			apitraffic.MatchArgument_CUSTOM: {"uid": "u1"},
+		},
+	}
+	if labels := formatMatchedLabels(request, rule); labels != "CUSTOM:uid:u1" {
+		t.Fatalf("unexpected labels %s", labels)
+	}
+}
@@ -18,6 +18,7 @@
 package quota
 
 import (
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -259,10 +260,15 @@ func (rs *RateLimitWindowSet) deleteContainer(revision string) {
 	}
 }
 
-// deleteWindow 从RateLimitWindowSet中删除一个RateLimitWindow
+// deleteWindow 从RateLimitWindowSet中删除一个RateLimitWindow，流量整形窗口实现了io.Closer时释放其占用的资源
 func (rs *RateLimitWindowSet) deleteWindow(window *RateLimitWindow) {
 	window.SetStatus(Deleted)
 	rs.flowAssistant.DelWindowCount()
+	if closer, ok := window.trafficShapingBucket.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.GetBaseLogger().Warnf("[RateLimit]fail to release window %s, err: %v", window.uniqueKey, err)
+		}
+	}
 }
 
 // WindowContainer 窗口容器
@@ -0,0 +1,69 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// closingBucket 记录是否被释放的配额桶
+type closingBucket struct {
+	ratelimiter.QuotaBucket
+	closed int
+}
+
+// Close 释放配额桶
+func (c *closingBucket) Close() error {
+	c.closed++
+	return nil
+}
+
+// TestWindowExpiredReleaseBucket 测试窗口过期后释放其配额桶
+func TestWindowExpiredReleaseBucket(t *testing.T) {
+	bucket := &closingBucket{}
+	window := &RateLimitWindow{
+		Rule:                 &apitraffic.Rule{Revision: wrapperspb.String("v1")},
+		uniqueKey:            "rule#uid:1",
+		expireDuration:       time.Second,
+		trafficShapingBucket: bucket,
+	}
+	windowSet := NewRateLimitWindowSet(&FlowQuotaAssistant{})
+	windowSet.windowByRule["v1"] = &WindowContainer{MainWindow: window}
+
+	if windowSet.OnWindowExpired(500, window) {
+		t.Fatal("window should not expire before expireDuration")
+	}
+	if bucket.closed != 0 {
+		t.Fatal("bucket should not be released before expired")
+	}
+	if !windowSet.OnWindowExpired(2000, window) {
+		t.Fatal("window should expire after expireDuration")
+	}
+	if bucket.closed != 1 {
+		t.Fatalf("expect bucket released once, got %d", bucket.closed)
+	}
+	if _, ok := windowSet.windowByRule["v1"]; ok {
+		t.Fatal("expired window should be removed")
+	}
+}
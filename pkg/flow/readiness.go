@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// WaitForPeers 等待服务的健康实例数达到下限，ctx取消或超时时返回错误
+func (e *Engine) WaitForPeers(ctx context.Context, req *model.WaitForPeersRequest) error {
+	return waitForPeers(ctx, req, func() (int, error) {
+		return e.countHealthyPeers(&req.ServiceKey)
+	})
+}
+
+// waitForPeers 按照检查间隔调用countPeers统计健康实例数，直到达到下限或者ctx结束
+func waitForPeers(ctx context.Context, req *model.WaitForPeersRequest, countPeers func() (int, error)) error {
+	ticker := time.NewTicker(req.GetCheckInterval())
+	defer ticker.Stop()
+	lastHealthy := -1
+	for {
+		healthy, err := countPeers()
+		if err != nil {
+			log.GetBaseLogger().Warnf("[Readiness] fail to get instances of %s: %v", req.ServiceKey, err)
+		} else if healthy != lastHealthy {
+			lastHealthy = healthy
+			progress := &model.PeersProgress{
+				ServiceKey:   req.ServiceKey,
+				HealthyPeers: healthy,
+				MinPeers:     req.MinPeers,
+				Ready:        healthy >= req.MinPeers,
+			}
+			log.GetBaseLogger().Infof("[Readiness] %s healthy peers %d/%d", req.ServiceKey, healthy, req.MinPeers)
+			if req.PeersListener != nil {
+				req.PeersListener.OnPeersProgress(progress)
+			}
+			if progress.Ready {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return model.NewSDKError(model.ErrCodeAPITimeoutError, ctx.Err(),
+				"wait for peers of %s canceled, healthy peers %d/%d", req.ServiceKey, lastHealthy, req.MinPeers)
+		case <-ticker.C:
+		}
+	}
+}
+
+// countHealthyPeers 统计健康且未隔离的实例数
+func (e *Engine) countHealthyPeers(svcKey *model.ServiceKey) (int, error) {
+	resp, err := e.SyncGetAllInstances(&model.GetAllInstancesRequest{
+		Namespace: svcKey.Namespace,
+		Service:   svcKey.Service,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return healthyPeers(resp.GetInstances()), nil
+}
+
+// healthyPeers 统计实例列表中健康且未隔离的实例数
+func healthyPeers(instances []model.Instance) int {
+	var healthy int
+	for _, instance := range instances {
+		if instance.IsHealthy() && !instance.IsIsolated() {
+			healthy++
+		}
+	}
+	return healthy
+}
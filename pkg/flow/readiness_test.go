@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type peersRecorder struct {
+	progresses []*model.PeersProgress
+}
+
+func (p *peersRecorder) OnPeersProgress(progress *model.PeersProgress) {
+	p.progresses = append(p.progresses, progress)
+}
+
+type peerInstance struct {
+	model.Instance
+	healthy  bool
+	isolated bool
+}
+
+func (p *peerInstance) IsHealthy() bool {
+	return p.healthy
+}
+
+func (p *peerInstance) IsIsolated() bool {
+	return p.isolated
+}
+
+// TestWaitForPeers 测试健康实例数变化时回调进度，达到下限后返回，查询失败时继续等待
+func TestWaitForPeers(t *testing.T) {
+	counts := []int{-1, 1, 1, 2, 3}
+	var calls int
+	recorder := &peersRecorder{}
+	req := &model.WaitForPeersRequest{
+		ServiceKey:    model.ServiceKey{Namespace: "Test", Service: "quorum"},
+		MinPeers:      3,
+		CheckInterval: time.Millisecond,
+		PeersListener: recorder,
+	}
+	err := waitForPeers(context.Background(), req, func() (int, error) {
+		count := counts[calls]
+		calls++
+		if count < 0 {
+			return 0, errors.New("discover failed")
+		}
+		return count, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != len(counts) {
+		t.Fatalf("expect %d checks, got %d", len(counts), calls)
+	}
+	if len(recorder.progresses) != 3 {
+		t.Fatalf("expect progress reported only on change, got %d", len(recorder.progresses))
+	}
+	for i, expect := range []int{1, 2, 3} {
+		progress := recorder.progresses[i]
+		if progress.HealthyPeers != expect || progress.MinPeers != 3 || progress.Ready != (expect == 3) {
+			t.Fatalf("unexpected progress %d: %+v", i, progress)
+		}
+	}
+}
+
+// TestWaitForPeersCanceled 测试健康实例数不足时等待到ctx超时
+func TestWaitForPeersCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := &model.WaitForPeersRequest{
+		ServiceKey:    model.ServiceKey{Namespace: "Test", Service: "quorum"},
+		MinPeers:      3,
+		CheckInterval: time.Millisecond,
+	}
+	err := waitForPeers(ctx, req, func() (int, error) {
+		return 2, nil
+	})
+	sdkErr, ok := err.(model.SDKError)
+	if !ok || sdkErr.ErrorCode() != model.ErrCodeAPITimeoutError {
+		t.Fatalf("expect timeout error, got %v", err)
+	}
+}
+
+// TestHealthyPeers 测试只统计健康且未隔离的实例
+func TestHealthyPeers(t *testing.T) {
+	instances := []model.Instance{
+		&peerInstance{healthy: true},
+		&peerInstance{healthy: true, isolated: true},
+		&peerInstance{healthy: false},
+		&peerInstance{healthy: true},
+	}
+	if healthy := healthyPeers(instances); healthy != 2 {
+		t.Fatalf("expect 2 healthy peers, got %d", healthy)
+	}
+}
@@ -0,0 +1,83 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// recentCalls 最近API调用的环形缓冲区，容量固定，写满后覆盖最早的记录
+type recentCalls struct {
+	mutex   sync.Mutex
+	records []model.APICallRecord
+	next    int
+	full    bool
+}
+
+func newRecentCalls(size int) *recentCalls {
+	if size <= 0 {
+		return nil
+	}
+	return &recentCalls{records: make([]model.APICallRecord, size)}
+}
+
+// add 记录一次API调用，缓冲区为nil时不记录
+func (r *recentCalls) add(result *model.APICallResult) {
+	if r == nil {
+		return
+	}
+	record := model.APICallRecord{
+		Time:      time.Now(),
+		API:       result.APIName.String(),
+		Namespace: result.Namespace,
+		Service:   result.Service,
+		RetCode:   result.RetCode,
+		Delay:     *result.GetDelay(),
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records[r.next] = record
+	r.next++
+	if r.next == len(r.records) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// list 按时间先后返回缓冲区中的记录
+func (r *recentCalls) list() []model.APICallRecord {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.full {
+		return append([]model.APICallRecord(nil), r.records[:r.next]...)
+	}
+	records := make([]model.APICallRecord, 0, len(r.records))
+	records = append(records, r.records[r.next:]...)
+	return append(records, r.records[:r.next]...)
+}
+
+// RecentCalls 获取最近的API调用记录，按时间先后排列
+func (e *Engine) RecentCalls() []model.APICallRecord {
+	return e.recentCalls.list()
+}
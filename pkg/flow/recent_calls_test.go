@@ -0,0 +1,48 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestRecentCalls(t *testing.T) {
+	calls := newRecentCalls(3)
+	for i := 0; i < 5; i++ {
+		result := &model.APICallResult{Service: string(rune('a' + i))}
+		result.APIName = model.ApiGetOneInstance
+		result.SetSuccess(time.Duration(i) * time.Millisecond)
+		calls.add(result)
+	}
+	records := calls.list()
+	if len(records) != 3 || records[0].Service != "c" || records[2].Service != "e" {
+		t.Fatalf("expect latest 3 records in order, got %+v", records)
+	}
+	if records[2].Delay != 4*time.Millisecond || records[2].API != model.ApiGetOneInstance.String() {
+		t.Fatalf("unexpected record %+v", records[2])
+	}
+
+	disabled := newRecentCalls(-1)
+	disabled.add(&model.APICallResult{})
+	if len(disabled.list()) != 0 {
+		t.Fatal("expect no records when disabled")
+	}
+}
@@ -0,0 +1,172 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package registerstate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// InstanceState 实例在客户端视角下的注册状态
+type InstanceState int
+
+const (
+	// StateRegistered 已注册且心跳正常
+	StateRegistered InstanceState = iota + 1
+	// StateDeregistered 已反注册
+	StateDeregistered
+	// StateHeartbeatFailed 心跳失败
+	StateHeartbeatFailed
+)
+
+type flappingReporter func(gauge *model.RegisterFlappingGauge)
+
+// flappingDetector 注册状态抖动检测，状态在窗口内的变化次数超过阈值时对重新注册进行指数退避
+type flappingDetector struct {
+	mu       sync.Mutex
+	cfg      config.FlappingConfig
+	records  map[string]*flappingRecord
+	reporter flappingReporter
+	// lastEvict 最近一次清理过期记录的时间
+	lastEvict time.Time
+}
+
+type flappingRecord struct {
+	lastState    InstanceState
+	lastSeen     time.Time
+	changes      []time.Time
+	backoff      time.Duration
+	backoffUntil time.Time
+}
+
+func newFlappingDetector(cfg config.FlappingConfig, reporter flappingReporter) *flappingDetector {
+	return &flappingDetector{
+		cfg:      cfg,
+		records:  map[string]*flappingRecord{},
+		reporter: reporter,
+	}
+}
+
+func (f *flappingDetector) enabled() bool {
+	return f != nil && f.cfg != nil && f.cfg.IsEnable()
+}
+
+// recordState 记录实例状态，状态发生变化时计入窗口
+func (f *flappingDetector) recordState(namespace, service, host string, port int, state InstanceState) {
+	if !f.enabled() {
+		return
+	}
+	key := buildRegisterStateKey(namespace, service, host, port)
+	now := time.Now()
+	window := f.cfg.GetWindow()
+	f.mu.Lock()
+	f.evictExpired(now, window)
+	record, ok := f.records[key]
+	if !ok {
+		record = &flappingRecord{}
+		f.records[key] = record
+	}
+	record.lastSeen = now
+	if record.lastState == state {
+		f.mu.Unlock()
+		return
+	}
+	first := record.lastState == 0
+	record.lastState = state
+	if first {
+		f.mu.Unlock()
+		return
+	}
+	record.changes = append(record.changes, now)
+	var expired int
+	for expired < len(record.changes) && now.Sub(record.changes[expired]) > window {
+		expired++
+	}
+	record.changes = record.changes[expired:]
+	if now.Before(record.backoffUntil) {
+		// 退避期内不重复判定
+		f.mu.Unlock()
+		return
+	}
+	if len(record.changes) <= f.cfg.GetThreshold() {
+		if record.backoff > 0 && now.Sub(record.backoffUntil) > window {
+			// 退避结束后一个窗口内没有再次抖动，退避时长复位
+			record.backoff = 0
+		}
+		f.mu.Unlock()
+		return
+	}
+	// 持续抖动时退避时长成倍增加
+	record.backoff *= 2
+	if record.backoff == 0 {
+		record.backoff = f.cfg.GetBaseBackoff()
+	}
+	if record.backoff > f.cfg.GetMaxBackoff() {
+		record.backoff = f.cfg.GetMaxBackoff()
+	}
+	record.backoffUntil = now.Add(record.backoff)
+	gauge := &model.RegisterFlappingGauge{
+		Namespace: namespace,
+		Service:   service,
+		Host:      host,
+		Port:      port,
+		Changes:   len(record.changes),
+		Backoff:   record.backoff,
+	}
+	f.mu.Unlock()
+	log.GetBaseLogger().Warnf("[Provider][Flapping] instance {%s, %s, %s:%d} changed state %d times in %v,"+
+		" re-register is backed off for %v", namespace, service, host, port, gauge.Changes, window, gauge.Backoff)
+	if f.reporter != nil {
+		f.reporter(gauge)
+	}
+}
+
+// evictExpired 清理超过一个检测窗口没有更新且不在退避期的记录，避免已下线实例的记录一直保留，
+// 每个窗口最多清理一次
+func (f *flappingDetector) evictExpired(now time.Time, window time.Duration) {
+	if now.Sub(f.lastEvict) <= window {
+		return
+	}
+	f.lastEvict = now
+	for key, record := range f.records {
+		if now.Sub(record.lastSeen) > window && now.Sub(record.backoffUntil) > window {
+			delete(f.records, key)
+		}
+	}
+}
+
+// checkRegister 检查实例当前是否允许注册，处于退避期时返回错误
+func (f *flappingDetector) checkRegister(namespace, service, host string, port int) error {
+	if !f.enabled() {
+		return nil
+	}
+	key := buildRegisterStateKey(namespace, service, host, port)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	record, ok := f.records[key]
+	if !ok || !time.Now().Before(record.backoffUntil) {
+		return nil
+	}
+	return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+		"instance {%s, %s, %s:%d} is flapping, register is backed off until %s",
+		namespace, service, host, port, record.backoffUntil.Format(time.RFC3339))
+}
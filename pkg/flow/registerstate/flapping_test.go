@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package registerstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestFlappingDetector(t *testing.T) {
+	cfg := &config.FlappingConfigImpl{}
+	cfg.SetDefault()
+	cfg.SetThreshold(3)
+	var reported []*model.RegisterFlappingGauge
+	detector := newFlappingDetector(cfg, func(gauge *model.RegisterFlappingGauge) {
+		reported = append(reported, gauge)
+	})
+	// 首次记录不计入变化，重复的状态也不计入，共计3次变化
+	states := []InstanceState{StateRegistered, StateHeartbeatFailed, StateRegistered, StateRegistered,
+		StateHeartbeatFailed}
+	for _, state := range states {
+		detector.recordState("Test", "svc", "127.0.0.1", 8080, state)
+	}
+	if err := detector.checkRegister("Test", "svc", "127.0.0.1", 8080); err != nil {
+		t.Fatalf("expect register allowed after 3 changes, got %v", err)
+	}
+	detector.recordState("Test", "svc", "127.0.0.1", 8080, StateDeregistered)
+	if len(reported) != 1 || reported[0].Backoff != config.DefaultFlappingBaseBackoff {
+		t.Fatalf("expect one flapping report with base backoff, got %v", reported)
+	}
+	if err := detector.checkRegister("Test", "svc", "127.0.0.1", 8080); err == nil {
+		t.Fatal("expect register backed off")
+	}
+	if err := detector.checkRegister("Test", "svc", "127.0.0.1", 8081); err != nil {
+		t.Fatalf("expect other instance not affected, got %v", err)
+	}
+	cfg.SetEnable(false)
+	if err := detector.checkRegister("Test", "svc", "127.0.0.1", 8080); err != nil {
+		t.Fatalf("expect disabled detector allows register, got %v", err)
+	}
+}
+
+// TestFlappingDetectorEvict 测试超过检测窗口没有更新且不在退避期的记录被清理
+func TestFlappingDetectorEvict(t *testing.T) {
+	cfg := &config.FlappingConfigImpl{}
+	cfg.SetDefault()
+	detector := newFlappingDetector(cfg, nil)
+	detector.recordState("Test", "svc", "127.0.0.1", 8080, StateRegistered)
+	detector.recordState("Test", "svc", "127.0.0.1", 8081, StateRegistered)
+	detector.recordState("Test", "svc", "127.0.0.1", 8082, StateRegistered)
+
+	expired := time.Now().Add(-2 * cfg.GetWindow())
+	detector.mu.Lock()
+	detector.lastEvict = expired
+	detector.records[buildRegisterStateKey("Test", "svc", "127.0.0.1", 8080)].lastSeen = expired
+	backedOff := detector.records[buildRegisterStateKey("Test", "svc", "127.0.0.1", 8081)]
+	backedOff.lastSeen = expired
+	backedOff.backoffUntil = time.Now().Add(time.Minute)
+	detector.mu.Unlock()
+
+	detector.recordState("Test", "svc", "127.0.0.1", 8083, StateRegistered)
+	if len(detector.records) != 3 {
+		t.Fatalf("expect expired record evicted, got %d records", len(detector.records))
+	}
+	if _, ok := detector.records[buildRegisterStateKey("Test", "svc", "127.0.0.1", 8080)]; ok {
+		t.Fatal("expect record not seen within window evicted")
+	}
+	if err := detector.checkRegister("Test", "svc", "127.0.0.1", 8081); err == nil {
+		t.Fatal("expect record in backoff kept")
+	}
+}
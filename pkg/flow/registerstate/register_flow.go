@@ -23,8 +23,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 type (
@@ -36,12 +40,18 @@ const (
 	_maxHeartbeatErrorCount = 2
 	_headerKeyAsyncRegis    = "async-regis"
 	_headerValueAsyncRegis  = "true"
+	// 自动重新注册失败的首次退避时长，连续失败时成倍增加
+	_reRegisterBaseBackoff = time.Second
+	// 自动重新注册失败的最大退避时长
+	_reRegisterMaxBackoff = time.Minute
 )
 
-func NewRegisterStateManager(minRegisterInterval time.Duration) *RegisterStateManager {
+func NewRegisterStateManager(minRegisterInterval time.Duration, flapping config.FlappingConfig,
+	reporter func(gauge *model.RegisterFlappingGauge)) *RegisterStateManager {
 	return &RegisterStateManager{
 		minRegisterInterval: minRegisterInterval,
 		states:              map[string]*registerState{},
+		flapping:            newFlappingDetector(flapping, reporter),
 	}
 }
 
@@ -49,12 +59,71 @@ type RegisterStateManager struct {
 	mu                  sync.RWMutex
 	minRegisterInterval time.Duration
 	states              map[string]*registerState
+	flapping            *flappingDetector
+}
+
+// CheckRegister 检查实例是否处于抖动退避期，处于退避期时不允许注册
+func (c *RegisterStateManager) CheckRegister(instance *model.InstanceRegisterRequest) error {
+	return c.flapping.checkRegister(instance.Namespace, instance.Service, instance.Host, instance.Port)
+}
+
+// RecordState 记录实例的注册状态，用于抖动检测
+func (c *RegisterStateManager) RecordState(namespace, service, host string, port int, state InstanceState) {
+	c.flapping.recordState(namespace, service, host, port, state)
 }
 
 type registerState struct {
+	mu               sync.Mutex
 	instance         *model.InstanceRegisterRequest
 	lastRegisterTime time.Time
 	cancel           context.CancelFunc
+	// 心跳协程退出时关闭
+	done chan struct{}
+	// 连续自动重新注册的次数
+	reRegisterAttempts int
+	// 下一次允许自动重新注册的时间
+	nextReRegisterTime time.Time
+	// 缩短租约后通知心跳协程调整心跳周期
+	leaseChanged chan struct{}
+}
+
+func (s *registerState) getInstance() *model.InstanceRegisterRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.instance
+}
+
+func (s *registerState) setInstance(instance *model.InstanceRegisterRequest) {
+	s.mu.Lock()
+	s.instance = instance
+	s.mu.Unlock()
+	select {
+	case s.leaseChanged <- struct{}{}:
+	default:
+	}
+}
+
+// DrainRegistered 停止所有实例的心跳任务并等待正在进行的心跳结束，返回停止心跳的实例，用于下线前反注册，
+// 避免反注册与心跳并发导致实例被重新拉起
+func (c *RegisterStateManager) DrainRegistered(ctx context.Context) []*model.InstanceRegisterRequest {
+	c.mu.Lock()
+	pre := c.states
+	c.states = make(map[string]*registerState)
+	c.mu.Unlock()
+
+	instances := make([]*model.InstanceRegisterRequest, 0, len(pre))
+	for _, state := range pre {
+		state.cancel()
+		instances = append(instances, state.getInstance())
+	}
+	for _, state := range pre {
+		select {
+		case <-state.done:
+		case <-ctx.Done():
+			return instances
+		}
+	}
+	return instances
 }
 
 func (c *RegisterStateManager) Destroy() {
@@ -82,9 +151,13 @@ func (c *RegisterStateManager) PutRegister(instance *model.InstanceRegisterReque
 		instance:         instance,
 		lastRegisterTime: time.Now(),
 		cancel:           cancel,
+		done:             make(chan struct{}),
+		leaseChanged:     make(chan struct{}, 1),
 	}
 	c.states[key] = state
-	go c.runHeartbeat(ctx, state, regis, beat)
+	profiling.Go("registerState", "heartbeat", func() {
+		c.runHeartbeat(ctx, state, regis, beat)
+	})
 	return state, true
 }
 
@@ -99,12 +172,80 @@ func (c *RegisterStateManager) RemoveRegister(instance *model.InstanceDeRegister
 	}
 }
 
+// ShortenLease 使用更短的TTL重新注册所有自动心跳的实例，并按新的TTL上报心跳，
+// 用于进程即将被强制终止、来不及反注册时，让服务端在数秒内摘除实例
+func (c *RegisterStateManager) ShortenLease(ttl int, regis registerFunc) error {
+	c.mu.RLock()
+	states := make([]*registerState, 0, len(c.states))
+	for _, state := range c.states {
+		states = append(states, state)
+	}
+	c.mu.RUnlock()
+
+	var errs error
+	for _, state := range states {
+		instance := state.getInstance()
+		if instance.TTL != nil && *instance.TTL <= ttl {
+			continue
+		}
+		leased := *instance
+		leased.SetTTL(ttl)
+		if _, err := regis(&leased, CreateRegisterV2Header()); err != nil {
+			log.GetBaseLogger().Errorf("[Provider][Heartbeat] fail to shorten lease {%s, %s, %s:%d}: %v",
+				instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		state.setInstance(&leased)
+		log.GetBaseLogger().Infof("[Provider][Heartbeat] lease shortened to %ds {%s, %s, %s:%d}",
+			ttl, instance.Namespace, instance.Service, instance.Host, instance.Port)
+	}
+	return errs
+}
+
+// IsolateRegistered 将所有自动心跳的实例重新注册为隔离状态，主调方不再向实例分配新的请求，心跳保持不变，
+// 用于优雅下线时在反注册前排空在途请求，返回隔离的实例数
+func (c *RegisterStateManager) IsolateRegistered(regis registerFunc) (int, error) {
+	c.mu.RLock()
+	states := make([]*registerState, 0, len(c.states))
+	for _, state := range c.states {
+		states = append(states, state)
+	}
+	c.mu.RUnlock()
+
+	var (
+		errs     error
+		isolated int
+	)
+	for _, state := range states {
+		instance := state.getInstance()
+		if instance.Isolate != nil && *instance.Isolate {
+			isolated++
+			continue
+		}
+		isolating := *instance
+		isolating.SetIsolate(true)
+		if _, err := regis(&isolating, CreateRegisterV2Header()); err != nil {
+			log.GetBaseLogger().Errorf("[Provider][Heartbeat] fail to isolate {%s, %s, %s:%d}: %v",
+				instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		state.setInstance(&isolating)
+		isolated++
+		log.GetBaseLogger().Infof("[Provider][Heartbeat] instance isolated {%s, %s, %s:%d}",
+			instance.Namespace, instance.Service, instance.Host, instance.Port)
+	}
+	return isolated, errs
+}
+
 func buildRegisterStateKey(namespace string, service string, host string, port int) string {
 	return fmt.Sprintf("%s##%s##%s##%d", namespace, service, host, port)
 }
 
 func (c *RegisterStateManager) runHeartbeat(ctx context.Context, state *registerState, regis registerFunc, beat heartbeatFunc) {
-	instance := state.instance
+	defer close(state.done)
+	instance := state.getInstance()
 	log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task started {%s, %s, %s:%d}",
 		instance.Namespace, instance.Service, instance.Host, instance.Port)
 	ticker := time.NewTicker(time.Duration(*instance.TTL) * time.Second)
@@ -119,6 +260,9 @@ func (c *RegisterStateManager) runHeartbeat(ctx context.Context, state *register
 			log.GetBaseLogger().Infof("[Provider][Heartbeat] instance heartbeat task stopped {%s, %s, %s:%d}",
 				instance.Namespace, instance.Service, instance.Host, instance.Port)
 			return
+		case <-state.leaseChanged:
+			instance = state.getInstance()
+			ticker.Reset(time.Duration(*instance.TTL) * time.Second)
 		case <-ticker.C:
 			hbReq := &model.InstanceHeartbeatRequest{
 				Namespace:    instance.Namespace,
@@ -133,30 +277,82 @@ func (c *RegisterStateManager) runHeartbeat(ctx context.Context, state *register
 				log.GetBaseLogger().Errorf("[Provider][Heartbeat] heartbeat failed {%s, %s, %s:%d}",
 					instance.Namespace, instance.Service, instance.Host, instance.Port, err)
 				errCnt++
-
-				needRegis := errCnt > _maxHeartbeatErrorCount && time.Since(state.lastRegisterTime) > minInterval
-				if needRegis {
-					// 重新记录注册的时间
-					state.lastRegisterTime = time.Now()
-					_, err = regis(instance, CreateRegisterV2Header())
-					if err == nil {
-						log.GetBaseLogger().Infof("[Provider][Heartbeat] re-register instatnce success {%s, %s, %s:%d}",
-							instance.Namespace, instance.Service, instance.Host, instance.Port)
-					} else {
-						log.GetBaseLogger().Warnf("[Provider][Heartbeat] re-register instatnce failed {%s, %s, %s:%d}",
-							instance.Namespace, instance.Service, instance.Host, instance.Port, err)
+				c.RecordState(instance.Namespace, instance.Service, instance.Host, instance.Port, StateHeartbeatFailed)
+				if model.IsInstanceNotFoundError(err) {
+					// 服务端已经丢失实例，无需等待连续失败，直接使用原始注册请求重新注册
+					if c.reRegister(state, regis, err) {
+						errCnt = 0
 					}
+					break
+				}
+				// 连续心跳失败时重新注册，与服务端丢失实例时共用退避
+				needRegis := errCnt > _maxHeartbeatErrorCount && time.Since(state.lastRegisterTime) > minInterval
+				if needRegis && c.reRegister(state, regis, err) {
+					errCnt = 0
 				}
 				break
 			}
 			log.GetBaseLogger().Debugf("[Provider][Heartbeat] success {%s, %s, %s:%d} cost:%d ms",
 				instance.Namespace, instance.Service, instance.Host, instance.Port, time.Since(start).Milliseconds())
 			errCnt = 0
+			c.RecordState(instance.Namespace, instance.Service, instance.Host, instance.Port, StateRegistered)
 			break
 		}
 	}
 }
 
+// reRegister 服务端丢失实例或者心跳连续失败后重新注册，失败时按指数退避，返回是否重新注册成功
+func (c *RegisterStateManager) reRegister(state *registerState, regis registerFunc, cause error) bool {
+	instance := state.getInstance()
+	now := time.Now()
+	if now.Before(state.nextReRegisterTime) {
+		log.GetBaseLogger().Debugf("[Provider][Heartbeat] instance {%s, %s, %s:%d} re-register backoff until %v",
+			instance.Namespace, instance.Service, instance.Host, instance.Port, state.nextReRegisterTime)
+		return false
+	}
+	if err := c.CheckRegister(instance); err != nil {
+		log.GetBaseLogger().Warnf("[Provider][Heartbeat] skip re-register instance: %v", err)
+		return false
+	}
+	state.lastRegisterTime = now
+	state.reRegisterAttempts++
+	event := &model.InstanceReRegisterEvent{
+		Namespace: instance.Namespace,
+		Service:   instance.Service,
+		Host:      instance.Host,
+		Port:      instance.Port,
+		Cause:     cause,
+		Attempt:   state.reRegisterAttempts,
+	}
+	resp, err := regis(instance, CreateRegisterV2Header())
+	if err == nil {
+		state.reRegisterAttempts = 0
+		state.nextReRegisterTime = time.Time{}
+		event.InstanceID = resp.InstanceID
+		c.RecordState(instance.Namespace, instance.Service, instance.Host, instance.Port, StateRegistered)
+		log.GetBaseLogger().Infof("[Provider][Heartbeat] re-register instance success {%s, %s, %s:%d}",
+			instance.Namespace, instance.Service, instance.Host, instance.Port)
+	} else {
+		backoff := _reRegisterBaseBackoff
+		for i := 1; i < event.Attempt && backoff < _reRegisterMaxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > _reRegisterMaxBackoff {
+			backoff = _reRegisterMaxBackoff
+		}
+		state.nextReRegisterTime = now.Add(backoff)
+		event.Err = err
+		event.Backoff = backoff
+		log.GetBaseLogger().Warnf("[Provider][Heartbeat] re-register instance failed {%s, %s, %s:%d}, "+
+			"attempt %d, backoff %v: %v", instance.Namespace, instance.Service, instance.Host, instance.Port,
+			event.Attempt, backoff, err)
+	}
+	if instance.ReRegisterHandler != nil {
+		instance.ReRegisterHandler(event)
+	}
+	return err == nil
+}
+
 func CreateRegisterV2Header() map[string]string {
 	header := map[string]string{
 		_headerKeyAsyncRegis: _headerValueAsyncRegis,
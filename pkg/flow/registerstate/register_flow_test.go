@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package registerstate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestDrainRegistered(t *testing.T) {
+	manager := NewRegisterStateManager(time.Second, nil, nil)
+	instance := &model.InstanceRegisterRequest{Namespace: "Test", Service: "svc", Host: "127.0.0.1", Port: 8080}
+	instance.SetTTL(1)
+	state, ok := manager.PutRegister(instance, nil, func(*model.InstanceHeartbeatRequest) error { return nil })
+	if !ok {
+		t.Fatal("expect register state created")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	instances := manager.DrainRegistered(ctx)
+	if len(instances) != 1 || instances[0] != instance {
+		t.Fatalf("expect drained instance returned, got %v", instances)
+	}
+	select {
+	case <-state.done:
+	default:
+		t.Fatal("expect heartbeat task stopped after drain")
+	}
+	if instances = manager.DrainRegistered(ctx); len(instances) != 0 {
+		t.Fatalf("expect no instance left, got %d", len(instances))
+	}
+}
+
+func TestReRegisterLostInstance(t *testing.T) {
+	manager := NewRegisterStateManager(time.Second, nil, nil)
+	var events []*model.InstanceReRegisterEvent
+	instance := &model.InstanceRegisterRequest{Namespace: "Test", Service: "svc", Host: "127.0.0.1", Port: 8080,
+		ReRegisterHandler: func(event *model.InstanceReRegisterEvent) {
+			events = append(events, event)
+		}}
+	state := &registerState{instance: instance}
+	cause := model.NewSDKErrorWithServerInfo(model.ErrCodeServerUserError, nil, 400303, "not found instance",
+		"fail to heartbeat")
+	if !model.IsInstanceNotFoundError(cause) {
+		t.Fatal("expect instance not found error")
+	}
+
+	registerErr := errors.New("server unavailable")
+	regis := func(*model.InstanceRegisterRequest, map[string]string) (*model.InstanceRegisterResponse, error) {
+		if registerErr != nil {
+			return nil, registerErr
+		}
+		return &model.InstanceRegisterResponse{InstanceID: "ins-1"}, nil
+	}
+	if manager.reRegister(state, regis, cause) {
+		t.Fatal("expect re-register failed")
+	}
+	if len(events) != 1 || events[0].Err == nil || events[0].Backoff != _reRegisterBaseBackoff {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	// 退避期内不会再次重新注册
+	registerErr = nil
+	if manager.reRegister(state, regis, cause) || len(events) != 1 {
+		t.Fatalf("expect re-register skipped during backoff, events %d", len(events))
+	}
+	state.nextReRegisterTime = time.Now().Add(-time.Millisecond)
+	if !manager.reRegister(state, regis, cause) {
+		t.Fatal("expect re-register success after backoff")
+	}
+	if len(events) != 2 || events[1].Err != nil || events[1].Attempt != 2 || events[1].InstanceID != "ins-1" {
+		t.Fatalf("unexpected event %+v", events[1])
+	}
+	if state.reRegisterAttempts != 0 {
+		t.Fatalf("expect attempts reset, got %d", state.reRegisterAttempts)
+	}
+}
+
+func TestShortenLease(t *testing.T) {
+	manager := NewRegisterStateManager(time.Second, nil, nil)
+	defer manager.Destroy()
+	instance := &model.InstanceRegisterRequest{Namespace: "Test", Service: "svc", Host: "127.0.0.1", Port: 8080}
+	instance.SetTTL(60)
+	beats := make(chan struct{}, 1)
+	state, _ := manager.PutRegister(instance, nil, func(*model.InstanceHeartbeatRequest) error {
+		select {
+		case beats <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	var registeredTTL int
+	regis := func(req *model.InstanceRegisterRequest, _ map[string]string) (*model.InstanceRegisterResponse, error) {
+		registeredTTL = *req.TTL
+		return &model.InstanceRegisterResponse{}, nil
+	}
+	if err := manager.ShortenLease(1, regis); err != nil {
+		t.Fatal(err)
+	}
+	if registeredTTL != 1 || *state.getInstance().TTL != 1 || *instance.TTL != 60 {
+		t.Fatalf("unexpected ttl, registered %d, state %d, origin %d", registeredTTL,
+			*state.getInstance().TTL, *instance.TTL)
+	}
+	// 心跳周期随TTL缩短
+	select {
+	case <-beats:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expect heartbeat with shortened ttl")
+	}
+	// 已经缩短过的实例不再重复注册
+	registeredTTL = 0
+	if err := manager.ShortenLease(1, regis); err != nil || registeredTTL != 0 {
+		t.Fatalf("expect no re-register, got ttl %d, %v", registeredTTL, err)
+	}
+}
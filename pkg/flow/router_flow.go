@@ -33,6 +33,7 @@ func (e *Engine) ProcessRouters(req *model.ProcessRoutersRequest) (*model.Instan
 	}
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByProcessRoutersRequest(req, e.configuration, routers)
+	e.applySelfLabels(commonRequest)
 	resp, err := e.doSyncGetInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
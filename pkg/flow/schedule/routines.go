@@ -28,6 +28,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 // TaskRoutine 任务调度协程接口
@@ -103,10 +104,10 @@ func (t *taskRoutine) start() {
 	t.ctx, t.cancel = context.WithCancel(context.Background())
 	if t.periodicTask.TakePriority {
 		log.GetBaseLogger().Infof("task %s started priority", t.periodicTask.Name)
-		go t.runTakePriority()
+		profiling.Go("schedule", t.periodicTask.Name, t.runTakePriority)
 	} else {
 		log.GetBaseLogger().Infof("task %s started period %v", t.periodicTask.Name, t.periodicTask.Period)
-		go t.runPeriod()
+		profiling.Go("schedule", t.periodicTask.Name, t.runPeriod)
 	}
 }
 
@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package secret
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// Parse 解析PEM格式的证书及私钥，校验私钥与证书匹配且证书在有效期内，caPEM为空时不解析CA
+func Parse(certPEM, keyPEM, caPEM string, now time.Time) (*model.TLSSecret, error) {
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, errors.New("certificate and key should not be empty")
+	}
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate/key pair: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid leaf certificate: %w", err)
+	}
+	if now.Before(leaf.NotBefore) {
+		return nil, fmt.Errorf("certificate %s is not valid before %v", leaf.Subject, leaf.NotBefore)
+	}
+	if !now.Before(leaf.NotAfter) {
+		return nil, fmt.Errorf("certificate %s expired at %v", leaf.Subject, leaf.NotAfter)
+	}
+	cert.Leaf = leaf
+	secret := &model.TLSSecret{
+		Certificate: cert,
+		Leaf:        leaf,
+		NotAfter:    leaf.NotAfter,
+	}
+	if len(caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, errors.New("no valid CA certificate found")
+		}
+		secret.CAPool = pool
+	}
+	return secret, nil
+}
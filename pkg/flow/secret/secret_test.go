@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package secret
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newPair 生成自签名证书及私钥
+func newPair(t *testing.T, notBefore, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "polaris"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestParse(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := newPair(t, now.Add(-time.Hour), now.Add(time.Hour))
+	secret, err := Parse(certPEM, keyPEM, certPEM, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Leaf.Subject.CommonName != "polaris" || secret.CAPool == nil || secret.Certificate.Leaf == nil {
+		t.Fatalf("unexpected secret %+v", secret)
+	}
+	if secret, err = Parse(certPEM, keyPEM, "", now); err != nil || secret.CAPool != nil {
+		t.Fatalf("expect no CA pool without CA file, got %+v, %v", secret, err)
+	}
+
+	// 证书与私钥不匹配
+	_, otherKey := newPair(t, now.Add(-time.Hour), now.Add(time.Hour))
+	if _, err = Parse(certPEM, otherKey, "", now); err == nil {
+		t.Fatal("expect mismatched key refused")
+	}
+	// 证书过期或者尚未生效
+	expiredCert, expiredKey := newPair(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	if _, err = Parse(expiredCert, expiredKey, "", now); err == nil {
+		t.Fatal("expect expired certificate refused")
+	}
+	futureCert, futureKey := newPair(t, now.Add(time.Hour), now.Add(2*time.Hour))
+	if _, err = Parse(futureCert, futureKey, "", now); err == nil {
+		t.Fatal("expect not yet valid certificate refused")
+	}
+	// CA文件内容不合法
+	if _, err = Parse(certPEM, keyPEM, "invalid", now); err == nil {
+		t.Fatal("expect invalid CA refused")
+	}
+}
@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// selectorHooks 负载均衡前后执行的实例选择钩子，添加时复制切片，读取时在锁内获取快照，执行钩子时不持有锁
+type selectorHooks struct {
+	mutex sync.Mutex
+	pre   []model.PreLoadBalanceHook
+	post  []model.PostLoadBalanceHook
+	// GetInstances结果的后处理钩子
+	result []model.InstancesResultHook
+	// 实例地址转换器
+	translators []model.AddressTranslator
+	// 服务名映射器
+	nameMappers []model.ServiceNameMapper
+}
+
+// AddPreLoadBalanceHook 添加负载均衡前执行的实例过滤钩子
+func (e *Engine) AddPreLoadBalanceHook(hook model.PreLoadBalanceHook) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	hooks := make([]model.PreLoadBalanceHook, 0, len(e.selectorHooks.pre)+1)
+	hooks = append(hooks, e.selectorHooks.pre...)
+	e.selectorHooks.pre = append(hooks, hook)
+}
+
+// AddPostLoadBalanceHook 添加负载均衡后执行的实例选择钩子
+func (e *Engine) AddPostLoadBalanceHook(hook model.PostLoadBalanceHook) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	hooks := make([]model.PostLoadBalanceHook, 0, len(e.selectorHooks.post)+1)
+	hooks = append(hooks, e.selectorHooks.post...)
+	e.selectorHooks.post = append(hooks, hook)
+}
+
+// AddInstancesResultHook 添加GetInstances结果的后处理钩子
+func (e *Engine) AddInstancesResultHook(hook model.InstancesResultHook) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	hooks := make([]model.InstancesResultHook, 0, len(e.selectorHooks.result)+1)
+	hooks = append(hooks, e.selectorHooks.result...)
+	e.selectorHooks.result = append(hooks, hook)
+}
+
+func (e *Engine) getInstancesResultHooks() []model.InstancesResultHook {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	return e.selectorHooks.result
+}
+
+func (e *Engine) getSelectorHooks() ([]model.PreLoadBalanceHook, []model.PostLoadBalanceHook) {
+	e.selectorHooks.mutex.Lock()
+	defer e.selectorHooks.mutex.Unlock()
+	return e.selectorHooks.pre, e.selectorHooks.post
+}
+
+// applyPreLoadBalanceHooks 使用钩子返回的实例重建负载均衡的集群，钩子操作的是实例列表的副本，不影响缓存
+func (e *Engine) applyPreLoadBalanceHooks(
+	hooks []model.PreLoadBalanceHook, commonRequest *data.CommonInstancesRequest) error {
+	cluster := commonRequest.Criteria.Cluster
+	if len(hooks) == 0 || cluster == nil {
+		return nil
+	}
+	clusterInstances, _ := cluster.GetInstances()
+	instances := make([]model.Instance, len(clusterInstances))
+	copy(instances, clusterInstances)
+	for _, hook := range hooks {
+		instances = hook(commonRequest.DstService, instances)
+		if len(instances) == 0 {
+			return model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+				"no instance of %s left after pre load balance hooks", commonRequest.DstService)
+		}
+	}
+	// 钩子可能在不改变数量的情况下替换实例，始终使用钩子返回的结果
+	replaceBalanceCluster(commonRequest, instances)
+	return nil
+}
+
+// replaceBalanceCluster 使用过滤后的实例替换负载均衡的集群
+func replaceBalanceCluster(commonRequest *data.CommonInstancesRequest, instances []model.Instance) {
+	cluster := commonRequest.Criteria.Cluster
+	svcInstances := model.NewDefaultServiceInstances(model.ServiceInfo{
+		Namespace: commonRequest.DstService.Namespace,
+		Service:   commonRequest.DstService.Service,
+		Metadata:  commonRequest.DstInstances.GetMetadata(),
+	}, instances)
+	newCluster := model.NewCluster(svcInstances.GetServiceClusters(), nil)
+	// 保持全死全活及半开实例的选择语义
+	newCluster.HasLimitedInstances = cluster.HasLimitedInstances
+	newCluster.IncludeHalfOpen = cluster.IncludeHalfOpen
+	cluster.PoolPut()
+	commonRequest.Criteria.Cluster = newCluster
+}
+
+// applyPostLoadBalanceHooks 依次执行负载均衡后的钩子
+func (e *Engine) applyPostLoadBalanceHooks(hooks []model.PostLoadBalanceHook, svcKey model.ServiceKey,
+	instance model.Instance) (model.Instance, error) {
+	var err error
+	for _, hook := range hooks {
+		if instance, err = hook(svcKey, instance); err != nil {
+			return nil, err
+		}
+		if instance == nil {
+			return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+				"no instance of %s selected after post load balance hooks", svcKey)
+		}
+	}
+	return instance, nil
+}
+
+// applyInstancesResultHooks 依次执行GetInstances结果的后处理钩子，钩子操作的是实例列表的副本，不影响缓存
+func (e *Engine) applyInstancesResultHooks(hooks []model.InstancesResultHook, svcKey model.ServiceKey,
+	instances []model.Instance, totalWeight int) ([]model.Instance, int) {
+	if len(hooks) == 0 {
+		return instances, totalWeight
+	}
+	result := make([]model.Instance, len(instances))
+	copy(result, instances)
+	for _, hook := range hooks {
+		if processed := hook(svcKey, result); processed != nil {
+			result = processed
+		}
+	}
+	totalWeight = 0
+	for _, instance := range result {
+		totalWeight += instance.GetWeight()
+	}
+	return result, totalWeight
+}
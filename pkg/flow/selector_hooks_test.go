@@ -0,0 +1,155 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+type weightedInstance struct {
+	model.Instance
+	id     string
+	weight int
+}
+
+func (w *weightedInstance) GetId() string {
+	return w.id
+}
+
+func (w *weightedInstance) GetWeight() int {
+	return w.weight
+}
+
+// TestApplyInstancesResultHooks 测试结果后处理钩子在副本上排序及过滤
+func TestApplyInstancesResultHooks(t *testing.T) {
+	e := &Engine{}
+	e.AddInstancesResultHook(func(svcKey model.ServiceKey, instances []model.Instance) []model.Instance {
+		sort.Slice(instances, func(i, j int) bool {
+			return instances[i].GetWeight() > instances[j].GetWeight()
+		})
+		return nil
+	})
+	e.AddInstancesResultHook(func(svcKey model.ServiceKey, instances []model.Instance) []model.Instance {
+		return instances[:2]
+	})
+	origin := []model.Instance{
+		&weightedInstance{id: "a", weight: 10},
+		&weightedInstance{id: "b", weight: 30},
+		&weightedInstance{id: "c", weight: 20},
+	}
+	result, totalWeight := e.applyInstancesResultHooks(
+		e.getInstancesResultHooks(), model.ServiceKey{Namespace: "Test", Service: "svc"}, origin, 60)
+	if len(result) != 2 || result[0].GetId() != "b" || result[1].GetId() != "c" || totalWeight != 50 {
+		t.Fatalf("unexpected result %v, totalWeight %d", result, totalWeight)
+	}
+	if origin[0].GetId() != "a" {
+		t.Fatal("origin instances should not be modified")
+	}
+}
+
+func newHookTestInstance(svcKey model.ServiceKey, id string) model.Instance {
+	return pb.NewInstanceInProto(&apiservice.Instance{
+		Id:      wrapperspb.String(id),
+		Host:    wrapperspb.String("127.0.0.1"),
+		Port:    wrapperspb.UInt32(8080),
+		Weight:  wrapperspb.UInt32(100),
+		Healthy: wrapperspb.Bool(true),
+	}, &svcKey, local.NewInstanceLocalValue())
+}
+
+// TestApplyPreLoadBalanceHooks 测试负载均衡前的钩子替换实例后，即使数量不变也使用钩子返回的实例
+func TestApplyPreLoadBalanceHooks(t *testing.T) {
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	svcInstances := model.NewDefaultServiceInstances(model.ServiceInfo{
+		Namespace: svcKey.Namespace, Service: svcKey.Service}, []model.Instance{
+		newHookTestInstance(svcKey, "a"), newHookTestInstance(svcKey, "b")})
+	commonRequest := &data.CommonInstancesRequest{DstService: svcKey, DstInstances: svcInstances}
+	commonRequest.Criteria.Cluster = model.NewCluster(svcInstances.GetServiceClusters(), nil)
+
+	replaced := newHookTestInstance(svcKey, "c")
+	e := &Engine{}
+	e.AddPreLoadBalanceHook(func(svcKey model.ServiceKey, instances []model.Instance) []model.Instance {
+		instances[1] = replaced
+		return instances
+	})
+	preHooks, _ := e.getSelectorHooks()
+	if err := e.applyPreLoadBalanceHooks(preHooks, commonRequest); err != nil {
+		t.Fatal(err)
+	}
+	instances, _ := commonRequest.Criteria.Cluster.GetInstances()
+	if len(instances) != 2 || instances[0].GetId() != "a" || instances[1].GetId() != "c" {
+		t.Fatalf("expect replaced instance used, got %v", instances)
+	}
+	if origin := svcInstances.GetInstances(); origin[1].GetId() != "b" {
+		t.Fatal("cached instances should not be modified")
+	}
+
+	e.AddPreLoadBalanceHook(func(svcKey model.ServiceKey, instances []model.Instance) []model.Instance {
+		return nil
+	})
+	preHooks, _ = e.getSelectorHooks()
+	if err := e.applyPreLoadBalanceHooks(preHooks, commonRequest); err == nil {
+		t.Fatal("expect error when no instance left")
+	}
+}
+
+// TestApplyPostLoadBalanceHooks 测试负载均衡后的钩子依次替换选中的实例，返回错误或空实例时选择失败
+func TestApplyPostLoadBalanceHooks(t *testing.T) {
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	selected := &weightedInstance{id: "a"}
+	replaced := &weightedInstance{id: "b"}
+	e := &Engine{}
+	e.AddPostLoadBalanceHook(func(svcKey model.ServiceKey, instance model.Instance) (model.Instance, error) {
+		return replaced, nil
+	})
+	var received model.Instance
+	e.AddPostLoadBalanceHook(func(svcKey model.ServiceKey, instance model.Instance) (model.Instance, error) {
+		received = instance
+		return instance, nil
+	})
+	_, postHooks := e.getSelectorHooks()
+	instance, err := e.applyPostLoadBalanceHooks(postHooks, svcKey, selected)
+	if err != nil || instance != replaced || received != replaced {
+		t.Fatalf("expect replaced instance passed along, got %v, %v", instance, err)
+	}
+
+	e.AddPostLoadBalanceHook(func(svcKey model.ServiceKey, instance model.Instance) (model.Instance, error) {
+		return nil, nil
+	})
+	_, postHooks = e.getSelectorHooks()
+	if _, err = e.applyPostLoadBalanceHooks(postHooks, svcKey, selected); err == nil {
+		t.Fatal("expect error when hook selects no instance")
+	}
+	hookErr := errors.New("rejected")
+	if _, err = e.applyPostLoadBalanceHooks([]model.PostLoadBalanceHook{
+		func(svcKey model.ServiceKey, instance model.Instance) (model.Instance, error) {
+			return nil, hookErr
+		}}, svcKey, selected); err != hookErr {
+		t.Fatalf("expect hook error returned, got %v", err)
+	}
+}
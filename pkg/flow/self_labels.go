@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// selfLabels 最近一次注册成功的自身实例信息，与配置的自身标签合并后作为规则路由的主调方信息
+type selfLabels struct {
+	mutex sync.RWMutex
+	// 最近一次注册成功的自身实例的副本
+	registered *model.InstanceRegisterRequest
+}
+
+// recordSelfInstance 注册成功后记录自身实例，只记录标记为 SelfInstance 的实例，
+// 保存副本以免调用方后续修改请求对象影响自身信息
+func (e *Engine) recordSelfInstance(req *model.InstanceRegisterRequest) {
+	if !req.SelfInstance {
+		return
+	}
+	registered := &model.InstanceRegisterRequest{
+		Namespace: req.Namespace,
+		Service:   req.Service,
+		Host:      req.Host,
+		Port:      req.Port,
+		Metadata:  make(map[string]string, len(req.Metadata)),
+	}
+	for k, v := range req.Metadata {
+		registered.Metadata[k] = v
+	}
+	s := &e.selfLabels
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.registered = registered
+}
+
+// removeSelfInstance 反注册时移除自身实例
+func (e *Engine) removeSelfInstance(namespace string, service string, host string, port int) {
+	s := &e.selfLabels
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.registered == nil {
+		return
+	}
+	if s.registered.Namespace == namespace && s.registered.Service == service &&
+		s.registered.Host == host && s.registered.Port == port {
+		s.registered = nil
+	}
+}
+
+// getSelfService 获取主调方自身的服务信息，配置的服务名及元数据优先于注册实例，均不存在时返回nil
+func (e *Engine) getSelfService() *model.ServiceInfo {
+	cfg := e.configuration.GetConsumer().GetServiceRouter().GetSelfLabels()
+	self := &model.ServiceInfo{Metadata: make(map[string]string)}
+	s := &e.selfLabels
+	s.mutex.RLock()
+	if s.registered != nil {
+		self.Namespace = s.registered.Namespace
+		self.Service = s.registered.Service
+		for k, v := range s.registered.Metadata {
+			self.Metadata[k] = v
+		}
+	}
+	s.mutex.RUnlock()
+	if len(cfg.GetService()) > 0 {
+		self.Namespace = cfg.GetNamespace()
+		self.Service = cfg.GetService()
+	}
+	for k, v := range cfg.GetMetadata() {
+		self.Metadata[k] = v
+	}
+	if self.IsEmpty() {
+		return nil
+	}
+	return self
+}
+
+// applySelfLabels 将主调方自身信息合并到路由的主调方信息中，请求中指定的标签优先，
+// 请求未指定主调服务时使用自身的服务名，从而可以匹配主调方的出流量规则
+func (e *Engine) applySelfLabels(req *data.CommonInstancesRequest) {
+	if !e.configuration.GetConsumer().GetServiceRouter().GetSelfLabels().IsEnable() {
+		return
+	}
+	self := e.getSelfService()
+	if self == nil {
+		return
+	}
+	// 不修改调用方传入的请求对象
+	source := req.RouteInfo.SourceService
+	merged := &model.ServiceInfo{Namespace: self.Namespace, Service: self.Service, Metadata: self.Metadata}
+	if !reflect2.IsNil(source) {
+		if len(source.GetNamespace()) > 0 && len(source.GetService()) > 0 {
+			merged.Namespace = source.GetNamespace()
+			merged.Service = source.GetService()
+		}
+		for k, v := range source.GetMetadata() {
+			merged.Metadata[k] = v
+		}
+	}
+	req.RouteInfo.SourceService = merged
+	req.HasSrcService = true
+	req.SrcService.Namespace = merged.Namespace
+	req.SrcService.Service = merged.Service
+	if merged.HasService() {
+		req.Trigger.EnableSrcRoute = true
+	}
+}
@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestApplySelfLabels 测试自身实例元数据以及配置的自身标签合并到路由的主调方信息中
+func TestApplySelfLabels(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	selfCfg := cfg.GetConsumer().GetServiceRouter().GetSelfLabels()
+	selfCfg.SetMetadata(map[string]string{"group": "g1"})
+	engine := &Engine{configuration: cfg}
+	self := &model.InstanceRegisterRequest{Namespace: "default", Service: "caller", Host: "127.0.0.1", Port: 8080,
+		Metadata: map[string]string{"version": "v2", "group": "g0"}, SelfInstance: true}
+	engine.recordSelfInstance(self)
+	// 代替其他实例注册以及修改请求对象都不影响自身信息
+	engine.recordSelfInstance(&model.InstanceRegisterRequest{Namespace: "default", Service: "imported",
+		Host: "10.0.0.1", Port: 80, Metadata: map[string]string{"version": "v9"}})
+	self.Service = "changed"
+	self.Metadata["version"] = "v3"
+
+	newRequest := func(source *model.ServiceInfo) *data.CommonInstancesRequest {
+		request := &data.CommonInstancesRequest{}
+		request.InitByGetOneRequest(&model.GetOneInstanceRequest{Namespace: "default", Service: "svc",
+			SourceService: source}, cfg)
+		engine.applySelfLabels(request)
+		return request
+	}
+	if request := newRequest(nil); request.HasSrcService {
+		t.Fatal("self labels should not be applied when disabled")
+	}
+
+	selfCfg.SetEnable(true)
+	request := newRequest(nil)
+	source := request.RouteInfo.SourceService
+	if !request.Trigger.EnableSrcRoute || source.GetService() != "caller" ||
+		source.GetMetadata()["version"] != "v2" || source.GetMetadata()["group"] != "g1" {
+		t.Fatalf("unexpected source service %v", source)
+	}
+
+	// 请求中指定的标签优先，且不修改请求对象
+	reqSource := &model.ServiceInfo{Metadata: map[string]string{"version": "v3"}}
+	source = newRequest(reqSource).RouteInfo.SourceService
+	if source.GetMetadata()["version"] != "v3" || source.GetMetadata()["group"] != "g1" || len(reqSource.Metadata) != 1 {
+		t.Fatalf("unexpected source service %v", source)
+	}
+
+	engine.removeSelfInstance("default", "caller", "127.0.0.1", 8080)
+	source = newRequest(nil).RouteInfo.SourceService
+	if source.GetService() != "" || source.GetMetadata()["version"] != "" || source.GetMetadata()["group"] != "g1" {
+		t.Fatalf("unexpected source service after deregister %v", source)
+	}
+}
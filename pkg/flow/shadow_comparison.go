@@ -0,0 +1,44 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// SyncReportShadowComparison 上报影子请求对比结果，不一致或者影子服务调用失败时输出日志，
+// 对比结果按主服务、影子服务、方法及结果上报到统计插件，用于计算影子服务的不一致率
+func (e *Engine) SyncReportShadowComparison(req *model.ReportShadowComparisonRequest) error {
+	switch req.Result {
+	case model.ShadowMismatch:
+		log.GetBaseLogger().Warnf("[ShadowComparison] %s of %s/%s mismatches shadow %s/%s: %s",
+			req.Method, req.Namespace, req.Service, req.ShadowNamespace, req.ShadowService, req.Detail)
+	case model.ShadowError:
+		log.GetBaseLogger().Debugf("[ShadowComparison] %s of shadow %s/%s failed: %s",
+			req.Method, req.ShadowNamespace, req.ShadowService, req.Detail)
+	}
+	return e.SyncReportStat(model.ShadowComparisonStat, &model.ShadowComparisonGauge{
+		Namespace:       req.Namespace,
+		Service:         req.Service,
+		ShadowNamespace: req.ShadowNamespace,
+		ShadowService:   req.ShadowService,
+		Method:          req.Method,
+		Result:          req.Result,
+	})
+}
@@ -0,0 +1,28 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+// ExportSnapshot 导出本地缓存快照
+func (e *Engine) ExportSnapshot(path string) error {
+	return e.registry.ExportSnapshot(path)
+}
+
+// ImportSnapshot 导入本地缓存快照，导入的缓存在远程更新前直接可用
+func (e *Engine) ImportSnapshot(path string) error {
+	return e.registry.ImportSnapshot(path)
+}
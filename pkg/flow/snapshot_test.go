@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// snapshotRegistry 记录快照导入导出路径的本地缓存
+type snapshotRegistry struct {
+	localregistry.LocalRegistry
+	exported string
+	imported string
+	err      error
+}
+
+func (r *snapshotRegistry) ExportSnapshot(path string) error {
+	r.exported = path
+	return r.err
+}
+
+func (r *snapshotRegistry) ImportSnapshot(path string) error {
+	r.imported = path
+	return r.err
+}
+
+// TestSnapshot 测试快照导入导出由本地缓存完成，并透传本地缓存的错误
+func TestSnapshot(t *testing.T) {
+	registry := &snapshotRegistry{}
+	engine := &Engine{registry: registry}
+	if err := engine.ExportSnapshot("/tmp/export.snapshot"); err != nil || registry.exported != "/tmp/export.snapshot" {
+		t.Fatalf("expect snapshot exported by registry, got %q %v", registry.exported, err)
+	}
+	if err := engine.ImportSnapshot("/tmp/import.snapshot"); err != nil || registry.imported != "/tmp/import.snapshot" {
+		t.Fatalf("expect snapshot imported by registry, got %q %v", registry.imported, err)
+	}
+	registry.err = errors.New("broken snapshot")
+	if err := engine.ImportSnapshot("/tmp/import.snapshot"); err != registry.err {
+		t.Fatalf("expect registry error returned, got %v", err)
+	}
+}
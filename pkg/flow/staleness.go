@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// refreshStaleInstances 实例缓存超过请求可接受的陈旧时间时，同步等待下一次服务端应答并重新读取缓存
+func (e *Engine) refreshStaleInstances(req *data.CommonInstancesRequest) error {
+	notifier, err := e.registry.RefreshInstances(&req.DstService, req.MaxStaleness)
+	if err != nil {
+		return err
+	}
+	if notifier == nil {
+		return nil
+	}
+	timeout := req.ControlParam.Timeout * time.Duration(req.ControlParam.MaxRetry+1)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-notifier.GetContext().Done():
+		if sdkErr := notifier.GetError(); sdkErr != nil {
+			return sdkErr
+		}
+	case <-timer.C:
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, nil,
+			"instances of %s are staler than %v and not refreshed in %v", req.DstService, req.MaxStaleness, timeout)
+	}
+	instances := e.registry.GetInstances(&req.DstService, false, false)
+	if instances.IsInitialized() {
+		req.DstInstances = instances
+	}
+	log.GetBaseLogger().Debugf("instances of %s refreshed for max staleness %v, revision %s",
+		req.DstService, req.MaxStaleness, req.DstInstances.GetRevision())
+	return nil
+}
@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// checkStrictCircuitBreaker 严格熔断模式下，检查负载均衡选中的实例以及调用的接口是否处于熔断状态，
+// 处于熔断状态时返回 model.CircuitOpenError，避免未调用 Check 的框架集成绕过熔断
+func (e *Engine) checkStrictCircuitBreaker(commonRequest *data.CommonInstancesRequest, inst model.Instance) error {
+	if e.circuitBreakerFlow == nil || inst == nil ||
+		!e.configuration.GetConsumer().GetCircuitBreaker().IsStrictMode() {
+		return nil
+	}
+	dstService := commonRequest.DstService
+	var caller *model.ServiceKey
+	if commonRequest.HasSrcService {
+		srcService := commonRequest.SrcService
+		caller = &srcService
+	}
+	resources := make([]model.Resource, 0, 2)
+	if insRes, err := model.NewInstanceResource(&dstService, caller, inst.GetProtocol(), inst.GetHost(),
+		inst.GetPort()); err == nil {
+		resources = append(resources, insRes)
+	}
+	if len(commonRequest.Method) > 0 {
+		if methodRes, err := model.NewMethodResource(&dstService, caller, commonRequest.Method); err == nil {
+			resources = append(resources, methodRes)
+		}
+	}
+	for _, resource := range resources {
+		if ruleName, open := e.circuitBreakerFlow.checkOpen(resource); open {
+			return model.NewSDKError(model.ErrCodeCircuitBreakerError,
+				&model.CircuitOpenError{Resource: resource, RuleName: ruleName},
+				"instance %s:%d of service %s is circuit broken", inst.GetHost(), inst.GetPort(), dstService)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+)
+
+// openMethodBreaker 对指定接口返回熔断打开状态的熔断插件
+type openMethodBreaker struct {
+	circuitbreaker.CircuitBreaker
+	method string
+}
+
+func (b *openMethodBreaker) CheckResource(resource model.Resource) model.CircuitBreakerStatus {
+	if methodRes, ok := resource.(*model.MethodResource); ok && methodRes.Method == b.method {
+		return model.NewCircuitBreakerStatus("method-rule", model.Open, time.Now())
+	}
+	return nil
+}
+
+type strictTestInstance struct {
+	model.Instance
+}
+
+func (i *strictTestInstance) GetHost() string {
+	return "127.0.0.1"
+}
+
+func (i *strictTestInstance) GetPort() uint32 {
+	return 8080
+}
+
+func (i *strictTestInstance) GetProtocol() string {
+	return "grpc"
+}
+
+// TestStrictCircuitBreaker 测试严格熔断模式下选中被熔断接口时返回熔断错误
+func TestStrictCircuitBreaker(t *testing.T) {
+	cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+	engine := &Engine{configuration: cfg}
+	engine.circuitBreakerFlow = &CircuitBreakerFlow{engine: engine,
+		resourceBreaker: &openMethodBreaker{method: "/echo"}, bulkheads: newBulkheadManager(nil)}
+	request := &data.CommonInstancesRequest{}
+	request.InitByGetOneRequest(&model.GetOneInstanceRequest{Namespace: "default", Service: "svc",
+		Arguments: []model.Argument{model.BuildMethodArgument("/echo")}}, cfg)
+
+	if err := engine.checkStrictCircuitBreaker(request, &strictTestInstance{}); err != nil {
+		t.Fatalf("expect no error without strict mode, got %v", err)
+	}
+	cfg.GetConsumer().GetCircuitBreaker().SetStrictMode(true)
+	err := engine.checkStrictCircuitBreaker(request, &strictTestInstance{})
+	if !errors.Is(err, model.ErrCircuitOpen) {
+		t.Fatalf("expect ErrCircuitOpen, got %v", err)
+	}
+	var openErr *model.CircuitOpenError
+	if !errors.As(err, &openErr) || openErr.RuleName != "method-rule" {
+		t.Fatalf("expect CircuitOpenError with rule name, got %v", err)
+	}
+	request.Method = "/ping"
+	if err = engine.checkStrictCircuitBreaker(request, &strictTestInstance{}); err != nil {
+		t.Fatalf("expect method /ping pass, got %v", err)
+	}
+}
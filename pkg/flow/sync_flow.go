@@ -42,7 +42,9 @@ func (e *Engine) syncInstancesReportAndFinalize(commonRequest *data.CommonInstan
 func (e *Engine) syncRateLimitReportAndFinalize(commonRequest *data.CommonRateLimitRequest, resp *model.QuotaResponse) {
 	// 调用api的结果上报
 	_ = e.reportAPIStat(&commonRequest.CallResult)
-	if resp != nil {
+	if resp != nil && len(resp.Exemption) > 0 {
+		e.reportRateLimitExemption(commonRequest.QuotaRequest, resp)
+	} else if resp != nil {
 		e.reportRateLimitGauge(commonRequest.QuotaRequest, resp)
 	}
 	data.PoolPutCommonRateLimitRequest(commonRequest)
@@ -55,10 +57,21 @@ func (e *Engine) reportRateLimitGauge(req *model.QuotaRequestImpl, resp *model.Q
 		Service:            req.GetService(),
 		Result:             resp.Code,
 		Arguments:          req.Arguments(),
+		CostLabels:         e.costLabels,
 	}
 	_ = e.SyncReportStat(model.RateLimitStat, stat)
 }
 
+func (e *Engine) reportRateLimitExemption(req *model.QuotaRequestImpl, resp *model.QuotaResponse) {
+	stat := &model.RateLimitExemptionGauge{
+		Namespace: req.GetNamespace(),
+		Service:   req.GetService(),
+		Method:    req.GetMethod(),
+		Exemption: resp.Exemption,
+	}
+	_ = e.SyncReportStat(model.RateLimitExemptionStat, stat)
+}
+
 // syncRuleReportAndFinalize 结果上报及归还请求实例规则对象
 func (e *Engine) syncRuleReportAndFinalize(commonRequest *data.CommonRuleRequest) {
 	// 调用api的结果上报
@@ -86,14 +99,25 @@ func (e *Engine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.On
 	// 方法开始时间
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetOneRequest(req, e.configuration)
+	e.applySelfLabels(commonRequest)
+	origin := e.applyNameMapping(commonRequest)
 	resp, err := e.doSyncGetOneInstance(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
+	if err == nil {
+		e.applyAddressTranslation(&resp.InstancesResponse)
+		restoreServiceName(&resp.InstancesResponse, origin)
+	}
 	return resp, err
 }
 
 // doSyncGetOneInstance 操作主要业务逻辑
 func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
 	startTime := e.globalCtx.Now()
+	if len(commonRequest.ForceHostPort) > 0 {
+		if resp := e.forceRouteInstance(startTime, commonRequest); resp != nil {
+			return resp, nil
+		}
+	}
 	err := e.syncGetWrapInstances(commonRequest)
 	consumeTime := e.globalCtx.Since(startTime)
 	if err != nil {
@@ -109,7 +133,27 @@ func (e *Engine) doLoadBalanceToOneInstance(
 	if err != nil {
 		return nil, err
 	}
+	e.applyDrainingInstances(commonRequest)
+	e.applyTrafficSplit(commonRequest)
+	e.applyWeightOverrides(commonRequest)
+	preHooks, postHooks := e.getSelectorHooks()
+	if err = e.applyPreLoadBalanceHooks(preHooks, commonRequest); err != nil {
+		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), e.globalCtx.Since(startTime))
+		return nil, err
+	}
 	inst, err := loadbalancer.ChooseInstance(e.globalCtx, balancer, &commonRequest.Criteria, commonRequest.DstInstances)
+	if err == nil {
+		inst = unwrapWeightOverride(inst)
+	}
+	if err == nil && len(postHooks) > 0 {
+		inst, err = e.applyPostLoadBalanceHooks(postHooks, commonRequest.DstService, inst)
+	}
+	if err == nil {
+		err = e.checkStrictCircuitBreaker(commonRequest, inst)
+	}
+	if err == nil {
+		err = e.checkFlowBudget(commonRequest, model.FlowStepLoadBalance)
+	}
 	consumeTime := e.globalCtx.Since(startTime)
 	if err != nil {
 		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), consumeTime)
@@ -122,12 +166,14 @@ func (e *Engine) doLoadBalanceToOneInstance(
 		instances = make([]model.Instance, 0, len(replicateInstances)+1)
 		instances = append(instances, inst)
 		instances = append(instances, replicateInstances...)
+	} else if owner, ok := inst.(data.SingleInstancesOwner); ok {
+		instances = owner.SingleInstances()
 	} else {
-		instances = inst.(data.SingleInstancesOwner).SingleInstances()
+		instances = []model.Instance{inst}
 	}
 	instancesResp := commonRequest.BuildInstancesResponse(commonRequest.DstService, nil, instances, 0,
 		commonRequest.DstInstances)
-	return &model.OneInstanceResponse{InstancesResponse: *instancesResp}, nil
+	return &model.OneInstanceResponse{InstancesResponse: *instancesResp, FlowTrace: flowTrace(commonRequest)}, nil
 }
 
 // SyncGetResources 同步加载资源
@@ -138,8 +184,12 @@ func (e *Engine) SyncGetResources(req model.CacheValueQuery) error {
 	dstService := req.GetDstService()
 	param := req.GetControlParam()
 	var totalConsumedTime, totalSleepTime time.Duration
+	deadline := param.GetDeadline()
 outLoop:
 	for retryTimes < param.MaxRetry {
+		if err = model.ContextError(param.Context); err != nil {
+			break outLoop
+		}
 		startTime := e.globalCtx.Now()
 		// 尝试获取本地缓存的值
 		combineContext, err = getAndLoadCacheValues(e.registry, req, retryTimes < param.MaxRetry)
@@ -150,10 +200,20 @@ outLoop:
 		if nil == combineContext {
 			return nil
 		}
-		// 发起并等待远程的结果
+		// 发起并等待远程的结果，设置了流程截止时间时等待时长不超过剩余预算
+		waitTimeout := param.Timeout
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(startTime)
+			if remaining <= 0 {
+				break outLoop
+			}
+			if remaining < waitTimeout {
+				waitTimeout = remaining
+			}
+		}
 		retryTimes++
 		syncCtx := combineContext
-		exceedTimeout := syncCtx.Wait(param.Timeout)
+		exceedTimeout := syncCtx.WaitContext(param.Context, waitTimeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		totalConsumedTime += consumedTime
@@ -165,7 +225,10 @@ outLoop:
 		}
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(param.RetryInterval)
+			if !data.SleepWithContext(param.Context, param.RetryInterval) {
+				err = model.ContextError(param.Context)
+				break outLoop
+			}
 			totalSleepTime += param.RetryInterval
 			continue
 		}
@@ -233,6 +296,15 @@ func (e *Engine) syncGetWrapInstances(req *data.CommonInstancesRequest) error {
 	var redirectedService *model.ServiceInfo
 	for redirectedTimes <= config.MaxRedirectTimes {
 		err := e.SyncGetResources(req)
+		if err == nil {
+			err = e.syncFallbackNamespace(req)
+		}
+		if err == nil && req.MaxStaleness > 0 {
+			err = e.refreshStaleInstances(req)
+		}
+		if budgetErr := e.checkFlowBudget(req, model.FlowStepCache); budgetErr != nil {
+			return budgetErr
+		}
 		if err != nil {
 			return err
 		}
@@ -242,6 +314,9 @@ func (e *Engine) syncGetWrapInstances(req *data.CommonInstancesRequest) error {
 		} else {
 			// 走就近路由
 			cluster, redirectedService, err = e.afterLazyGetInstances(req)
+			if budgetErr := e.checkFlowBudget(req, model.FlowStepRoute); budgetErr != nil {
+				return budgetErr
+			}
 			if err != nil {
 				return err
 			}
@@ -279,8 +354,14 @@ func verifyCluster(svcInstances model.ServiceInstances, cluster *model.Cluster)
 func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetMultiRequest(req, e.configuration)
+	e.applySelfLabels(commonRequest)
+	origin := e.applyNameMapping(commonRequest)
 	resp, err := e.doSyncGetInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
+	if err == nil {
+		e.applyAddressTranslation(resp)
+		restoreServiceName(resp, origin)
+	}
 	return resp, err
 }
 
@@ -288,8 +369,13 @@ func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.Instan
 func (e *Engine) SyncGetAllInstances(req *model.GetAllInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	commonRequest.InitByGetAllRequest(req, e.configuration)
+	origin := e.applyNameMapping(commonRequest)
 	resp, err := e.doSyncGetAllInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
+	if err == nil {
+		e.applyAddressTranslation(resp)
+		restoreServiceName(resp, origin)
+	}
 	return resp, err
 }
 
@@ -304,8 +390,10 @@ func (e *Engine) doSyncGetAllInstances(commonRequest *data.CommonInstancesReques
 	}
 	(&commonRequest.CallResult).SetSuccess(consumeTime)
 	dstInstances := commonRequest.DstInstances
+	instances, totalWeight := e.injectProvisionalInstances(commonRequest.DstService, dstInstances,
+		dstInstances.GetInstances(), dstInstances.GetTotalWeight())
 	return commonRequest.BuildInstancesResponse(commonRequest.DstService, commonRequest.Criteria.Cluster,
-		dstInstances.GetInstances(), dstInstances.GetTotalWeight(), dstInstances), nil
+		instances, totalWeight, dstInstances), nil
 }
 
 // doSyncGetInstances 同步获取服务实例
@@ -326,23 +414,51 @@ func (e *Engine) doSyncGetInstances(commonRequest *data.CommonInstancesRequest)
 	} else {
 		instances, totalWeight = targetCls.GetInstances()
 	}
+	instances, totalWeight = e.injectProvisionalInstances(
+		commonRequest.DstService, commonRequest.DstInstances, instances, totalWeight)
+	instances, totalWeight = e.applyInstancesResultHooks(
+		e.getInstancesResultHooks(), commonRequest.DstService, instances, totalWeight)
 	return commonRequest.BuildInstancesResponse(
 		commonRequest.DstService, targetCls, instances, totalWeight, commonRequest.DstInstances), nil
 }
 
 // SyncRegister 同步进行服务注册
 func (e *Engine) SyncRegister(instance *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := e.registerStates.CheckRegister(instance); err != nil {
+		return nil, err
+	}
+	var (
+		resp *model.InstanceRegisterResponse
+		err  error
+	)
 	if instance.AutoHeartbeat {
+		if e.isLameduck() {
+			instance.SetTTL(e.configuration.GetProvider().GetLameduckTTL())
+		}
 		instance.SetDefaultTTL()
-		resp, err := e.doSyncRegister(instance, registerstate.CreateRegisterV2Header())
-		if err != nil {
-			return nil, err
+		resp, err = e.doSyncRegister(instance, registerstate.CreateRegisterV2Header())
+		if err == nil {
+			e.registerStates.PutRegister(instance, e.doBackgroundRegister, e.SyncHeartbeat)
 		}
-
-		e.registerStates.PutRegister(instance, e.doSyncRegister, e.SyncHeartbeat)
-		return resp, nil
+	} else {
+		resp, err = e.doSyncRegister(instance, nil)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return e.doSyncRegister(instance, nil)
+	e.registerStates.RecordState(instance.Namespace, instance.Service, instance.Host, instance.Port,
+		registerstate.StateRegistered)
+	e.addProvisionalInstance(instance, resp)
+	e.recordSelfInstance(instance)
+	return resp, nil
+}
+
+// doBackgroundRegister 后台重新注册，不受首次注册时调用方上下文的影响
+func (e *Engine) doBackgroundRegister(instance *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	background := *instance
+	background.SetContext(nil)
+	return e.doSyncRegister(&background, header)
 }
 
 // doSyncRegister 同步进行服务注册
@@ -354,6 +470,8 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 			RetCode: model.ErrCodeSuccess,
 		},
 		RetStatus: model.RetSuccess,
+		Namespace: instance.Namespace,
+		Service:   instance.Service,
 	}
 	defer func() {
 		_ = e.reportAPIStat(apiCallResult)
@@ -364,9 +482,11 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 	startTime := e.globalCtx.Now()
 	svcKey := model.ServiceKey{Namespace: instance.Namespace, Service: instance.Service}
 
-	// 如果注册请求没有设置 Location 信息，则由内部自动设置
-	if instance.Location == nil {
+	// 如果注册请求没有设置 Location 信息，则由内部自动设置；显式设置时覆盖自动探测的位置
+	if instance.Location == nil || instance.Location.IsEmpty() {
 		instance.Location = e.globalCtx.GetCurrentLocation().GetLocation()
+	} else {
+		log.GetBaseLogger().Debugf("[Register] register %s with explicit location %s", instance, instance.Location)
 	}
 
 	resp, err := data.RetrySyncCall("register", &svcKey, instance, func(request interface{}) (interface{}, error) {
@@ -384,6 +504,10 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 // SyncDeregister 同步进行服务反注册
 func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error {
 	e.registerStates.RemoveRegister(instance)
+	e.registerStates.RecordState(instance.Namespace, instance.Service, instance.Host, instance.Port,
+		registerstate.StateDeregistered)
+	e.removeProvisionalInstance(instance.Namespace, instance.Service, instance.Host, instance.Port)
+	e.removeSelfInstance(instance.Namespace, instance.Service, instance.Host, instance.Port)
 	// 调用api的结果上报
 	apiCallResult := &model.APICallResult{
 		APICallKey: model.APICallKey{
@@ -391,6 +515,8 @@ func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error
 			RetCode: model.ErrCodeSuccess,
 		},
 		RetStatus: model.RetSuccess,
+		Namespace: instance.Namespace,
+		Service:   instance.Service,
 	}
 	defer func() {
 		_ = e.reportAPIStat(apiCallResult)
@@ -421,6 +547,8 @@ func (e *Engine) SyncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
 			RetCode: model.ErrCodeSuccess,
 		},
 		RetStatus: model.RetSuccess,
+		Namespace: instance.Namespace,
+		Service:   instance.Service,
 	}
 	defer func() {
 		_ = e.reportAPIStat(apiCallResult)
@@ -492,7 +620,14 @@ func (e *Engine) SyncGetServiceRule(
 	eventType model.EventType, req *model.GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
 	commonRequest := data.PoolGetCommonRuleRequest()
 	commonRequest.InitByGetRuleRequest(eventType, req, e.configuration)
+	origin := commonRequest.DstService.ServiceKey
+	if mapped, ok := e.mapServiceName(origin); ok {
+		commonRequest.DstService.ServiceKey = mapped
+	}
 	resp, err := e.doSyncGetServiceRule(commonRequest)
+	if err == nil {
+		resp.Service.Namespace, resp.Service.Service = origin.Namespace, origin.Service
+	}
 	e.syncRuleReportAndFinalize(commonRequest)
 	return resp, err
 }
@@ -595,7 +730,15 @@ func (e *Engine) realInitCalleeService(req *model.InitCalleeServiceRequest,
 
 // SyncGetConfigFile 同步获取配置文件
 func (e *Engine) SyncGetConfigFile(req *model.GetConfigFileRequest) (model.ConfigFile, error) {
-	return e.configFlow.GetConfigFile(req)
+	startTime := time.Now()
+	file, err := e.configFlow.GetConfigFile(req)
+	e.reportConfigOperation(req.Namespace, req.FileGroup, model.ConfigOperationFetch, startTime, err)
+	return file, err
+}
+
+// SyncGetConfigFiles 同步批量获取配置文件
+func (e *Engine) SyncGetConfigFiles(req *model.GetConfigFilesRequest) (map[string]model.ConfigFile, error) {
+	return e.configFlow.GetConfigFiles(req)
 }
 
 // SyncGetConfigGroup 同步获取配置文件
@@ -609,18 +752,39 @@ func (e *Engine) SyncGetConfigGroupWithReq(req *model.GetConfigGroupRequest) (mo
 }
 
 // SyncCreateConfigFile 同步创建配置文件
-func (e *Engine) SyncCreateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return e.configFlow.CreateConfigFile(namespace, fileGroup, fileName, content)
+func (e *Engine) SyncCreateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	startTime := time.Now()
+	err := e.configFlow.CreateConfigFile(namespace, fileGroup, fileName, content, opts...)
+	e.reportConfigOperation(namespace, fileGroup, model.ConfigOperationCreate, startTime, err)
+	return err
 }
 
 // SyncUpdateConfigFile 同步更新配置文件
-func (e *Engine) SyncUpdateConfigFile(namespace, fileGroup, fileName, content string) error {
-	return e.configFlow.UpdateConfigFile(namespace, fileGroup, fileName, content)
+func (e *Engine) SyncUpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...model.ConfigFileOperationOption) error {
+	startTime := time.Now()
+	err := e.configFlow.UpdateConfigFile(namespace, fileGroup, fileName, content, opts...)
+	e.reportConfigOperation(namespace, fileGroup, model.ConfigOperationUpdate, startTime, err)
+	return err
 }
 
 // SyncPublishConfigFile 同步发布配置文件
-func (e *Engine) SyncPublishConfigFile(namespace, fileGroup, fileName string) error {
-	return e.configFlow.PublishConfigFile(namespace, fileGroup, fileName)
+func (e *Engine) SyncPublishConfigFile(namespace, fileGroup, fileName string, opts ...model.ConfigFileOperationOption) error {
+	startTime := time.Now()
+	err := e.configFlow.PublishConfigFile(namespace, fileGroup, fileName, opts...)
+	e.reportConfigOperation(namespace, fileGroup, model.ConfigOperationPublish, startTime, err)
+	return err
+}
+
+// reportConfigOperation 上报配置中心接口调用的耗时及结果
+func (e *Engine) reportConfigOperation(namespace, fileGroup string, operation model.ConfigOperation,
+	startTime time.Time, err error) {
+	_ = e.SyncReportStat(model.ConfigOperationStat, &model.ConfigOperationGauge{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		Operation: operation,
+		Success:   err == nil,
+		Delay:     time.Since(startTime),
+	})
 }
 
 // WatchAllInstances 监听所有的实例
@@ -0,0 +1,150 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// trafficSplit 服务的本地流量比例
+type trafficSplit struct {
+	versions    []string
+	percents    []uint32
+	metadataKey string
+	expireTime  time.Time
+}
+
+// trafficSplits 外部金丝雀分析控制器设置的本地流量比例，优先于服务端下发的路由规则，到期后自动失效
+type trafficSplits struct {
+	mutex  sync.RWMutex
+	splits map[model.ServiceKey]*trafficSplit
+}
+
+// SetTrafficSplit 设置服务的本地流量比例，到期后自动失效
+func (e *Engine) SetTrafficSplit(req *model.TrafficSplitRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = model.DefaultTrafficSplitTTL
+	}
+	split := &trafficSplit{
+		metadataKey: req.MetadataKey,
+		expireTime:  time.Now().Add(ttl),
+	}
+	if len(split.metadataKey) == 0 {
+		split.metadataKey = model.DefaultTrafficSplitMetadataKey
+	}
+	for version := range req.Split {
+		split.versions = append(split.versions, version)
+	}
+	sort.Strings(split.versions)
+	for _, version := range split.versions {
+		split.percents = append(split.percents, req.Split[version])
+	}
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	e.trafficSplits.mutex.Lock()
+	if e.trafficSplits.splits == nil {
+		e.trafficSplits.splits = make(map[model.ServiceKey]*trafficSplit)
+	}
+	e.trafficSplits.splits[svcKey] = split
+	e.trafficSplits.mutex.Unlock()
+	log.GetBaseLogger().Infof("[TrafficSplit] set split of %s to %v by %s, ttl %v", svcKey, req.Split,
+		split.metadataKey, ttl)
+	return nil
+}
+
+// ClearTrafficSplit 清除服务的本地流量比例
+func (e *Engine) ClearTrafficSplit(svcKey model.ServiceKey) {
+	e.trafficSplits.mutex.Lock()
+	defer e.trafficSplits.mutex.Unlock()
+	if _, ok := e.trafficSplits.splits[svcKey]; ok {
+		delete(e.trafficSplits.splits, svcKey)
+		log.GetBaseLogger().Infof("[TrafficSplit] clear split of %s", svcKey)
+	}
+}
+
+// getTrafficSplit 获取未过期的流量比例，过期的比例会被删除
+func (e *Engine) getTrafficSplit(svcKey model.ServiceKey) *trafficSplit {
+	e.trafficSplits.mutex.RLock()
+	split, ok := e.trafficSplits.splits[svcKey]
+	e.trafficSplits.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	if time.Now().Before(split.expireTime) {
+		return split
+	}
+	e.trafficSplits.mutex.Lock()
+	if e.trafficSplits.splits[svcKey] == split {
+		delete(e.trafficSplits.splits, svcKey)
+		log.GetBaseLogger().Infof("[TrafficSplit] split of %s expired, fallback to server rules", svcKey)
+	}
+	e.trafficSplits.mutex.Unlock()
+	return nil
+}
+
+// pick 按比例随机选择本次请求的版本
+func (s *trafficSplit) pick() string {
+	point := uint32(rand.Intn(100))
+	var acc uint32
+	for i, percent := range s.percents {
+		acc += percent
+		if point < acc {
+			return s.versions[i]
+		}
+	}
+	return s.versions[len(s.versions)-1]
+}
+
+// applyTrafficSplit 按本地流量比例选择版本，将负载均衡的集群限制为该版本的实例，版本没有实例时不做过滤
+func (e *Engine) applyTrafficSplit(commonRequest *data.CommonInstancesRequest) {
+	cluster := commonRequest.Criteria.Cluster
+	if cluster == nil {
+		return
+	}
+	split := e.getTrafficSplit(commonRequest.DstService)
+	if split == nil {
+		return
+	}
+	version := split.pick()
+	instances, _ := cluster.GetInstances()
+	selected := make([]model.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if instance.GetMetadata()[split.metadataKey] == version {
+			selected = append(selected, instance)
+		}
+	}
+	if len(selected) == 0 {
+		log.GetBaseLogger().Debugf("[TrafficSplit] %s has no instance of %s=%s, skip split",
+			commonRequest.DstService, split.metadataKey, version)
+		return
+	}
+	if len(selected) == len(instances) {
+		return
+	}
+	replaceBalanceCluster(commonRequest, selected)
+}
@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestTrafficSplit 测试本地流量比例的校验、选择以及过期
+func TestTrafficSplit(t *testing.T) {
+	e := &Engine{}
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	invalid := []map[string]uint32{
+		{"v1": 90, "v2": 20},
+		{"v1": 101},
+		{"v1": 50},
+	}
+	for _, split := range invalid {
+		if err := e.SetTrafficSplit(&model.TrafficSplitRequest{
+			Namespace: svcKey.Namespace, Service: svcKey.Service, Split: split}); err == nil {
+			t.Fatalf("expect invalid split %v", split)
+		}
+	}
+	if err := e.SetTrafficSplit(&model.TrafficSplitRequest{Namespace: svcKey.Namespace, Service: svcKey.Service,
+		Split: map[string]uint32{"v1": 100}, TTL: 48 * time.Hour}); err == nil {
+		t.Fatal("expect ttl out of bounds")
+	}
+
+	if err := e.SetTrafficSplit(&model.TrafficSplitRequest{Namespace: svcKey.Namespace, Service: svcKey.Service,
+		Split: map[string]uint32{"v1": 0, "v2": 100}}); err != nil {
+		t.Fatal(err)
+	}
+	split := e.getTrafficSplit(svcKey)
+	if split == nil || split.metadataKey != model.DefaultTrafficSplitMetadataKey {
+		t.Fatalf("unexpected split %+v", split)
+	}
+	for i := 0; i < 100; i++ {
+		if version := split.pick(); version != "v2" {
+			t.Fatalf("expect v2, got %s", version)
+		}
+	}
+
+	split.expireTime = time.Now().Add(-time.Second)
+	if e.getTrafficSplit(svcKey) != nil {
+		t.Fatal("expect split expired")
+	}
+	if err := e.SetTrafficSplit(&model.TrafficSplitRequest{Namespace: svcKey.Namespace, Service: svcKey.Service,
+		Split: map[string]uint32{"v1": 100}}); err != nil {
+		t.Fatal(err)
+	}
+	e.ClearTrafficSplit(svcKey)
+	if e.getTrafficSplit(svcKey) != nil {
+		t.Fatal("expect split cleared")
+	}
+}
@@ -0,0 +1,126 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+)
+
+// maxWarmUpConcurrency 缓存预热时同时加载的服务数上限，避免清单较大时启动瞬间向服务端发起过多请求
+const maxWarmUpConcurrency = 16
+
+// cacheWarmUp 缓存预热任务，按照预热清单预先订阅并加载服务
+type cacheWarmUp struct {
+	done chan struct{}
+	err  error
+}
+
+// startWarmUp 启动缓存预热，未配置预热清单时直接就绪
+func (e *Engine) startWarmUp() {
+	e.warmUp = &cacheWarmUp{done: make(chan struct{})}
+	manifestPath := e.configuration.GetConsumer().GetLocalCache().GetWarmUpManifest()
+	if manifestPath == "" {
+		close(e.warmUp.done)
+		return
+	}
+	manifest, err := config.LoadWarmUpManifest(manifestPath)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[WarmUp] fail to load manifest %s: %v", manifestPath, err)
+		e.warmUp.err = err
+		close(e.warmUp.done)
+		return
+	}
+	profiling.Go("warmUp", "warmUp", func() {
+		e.doWarmUp(manifest.Services)
+	})
+}
+
+func (e *Engine) doWarmUp(services []*config.WarmUpService) {
+	defer close(e.warmUp.done)
+	start := time.Now()
+	e.warmUp.err = warmUpServices(services, maxWarmUpConcurrency, func(svc *config.WarmUpService) error {
+		req := &model.GetInstancesRequest{
+			Namespace:                    svc.Namespace,
+			Service:                      svc.Service,
+			SkipRouteFilter:              true,
+			IncludeCircuitBreakInstances: true,
+			IncludeUnhealthyInstances:    true,
+		}
+		_, err := e.SyncGetInstances(req)
+		return err
+	})
+	log.GetBaseLogger().Infof("[WarmUp] %d services warmed up, cost %v", len(services), time.Since(start))
+}
+
+// warmUpServices 以不超过concurrency的并发度逐个加载服务，返回所有加载失败的错误
+func warmUpServices(services []*config.WarmUpService, concurrency int,
+	load func(svc *config.WarmUpService) error) error {
+	if concurrency > len(services) {
+		concurrency = len(services)
+	}
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		errs error
+	)
+	svcChan := make(chan *config.WarmUpService)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for svc := range svcChan {
+				if err := load(svc); err != nil {
+					log.GetBaseLogger().Errorf("[WarmUp] fail to load service %s/%s: %v",
+						svc.Namespace, svc.Service, err)
+					lock.Lock()
+					errs = multierror.Append(errs, err)
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	for _, svc := range services {
+		svcChan <- svc
+	}
+	close(svcChan)
+	wg.Wait()
+	return errs
+}
+
+// WaitForReady 等待缓存预热完成，超时或者预热失败时返回错误
+func (e *Engine) WaitForReady(timeout time.Duration) error {
+	if e.warmUp == nil {
+		return nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-e.warmUp.done:
+		return e.warmUp.err
+	case <-timer.C:
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, nil, "wait for cache warm up timeout after %v", timeout)
+	}
+}
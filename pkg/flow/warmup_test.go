@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// TestWarmUpServicesBounded 测试预热清单中的服务全部加载，并发度不超过上限，加载失败的错误被汇总
+func TestWarmUpServicesBounded(t *testing.T) {
+	var services []*config.WarmUpService
+	for i := 0; i < 20; i++ {
+		services = append(services, &config.WarmUpService{Namespace: "Test", Service: fmt.Sprintf("svc-%d", i)})
+	}
+	var (
+		running    int32
+		maxRunning int32
+		loaded     sync.Map
+	)
+	err := warmUpServices(services, 3, func(svc *config.WarmUpService) error {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&maxRunning)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxRunning, old, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		loaded.Store(svc.Service, true)
+		if svc.Service == "svc-1" || svc.Service == "svc-7" {
+			return errors.New("discover failed")
+		}
+		return nil
+	})
+	if peak := atomic.LoadInt32(&maxRunning); peak > 3 {
+		t.Fatalf("expect at most 3 services loaded concurrently, got %d", peak)
+	}
+	for _, svc := range services {
+		if _, ok := loaded.Load(svc.Service); !ok {
+			t.Fatalf("service %s not warmed up", svc.Service)
+		}
+	}
+	merr, ok := err.(*multierror.Error)
+	if !ok || len(merr.Errors) != 2 {
+		t.Fatalf("expect 2 load errors, got %v", err)
+	}
+}
+
+// TestWaitForReady 测试预热未完成时等待超时，完成后返回预热结果
+func TestWaitForReady(t *testing.T) {
+	engine := &Engine{warmUp: &cacheWarmUp{done: make(chan struct{})}}
+	if err := engine.WaitForReady(10 * time.Millisecond); err == nil {
+		t.Fatal("expect timeout before warm up finished")
+	}
+	warmUpErr := errors.New("discover failed")
+	engine.warmUp.err = warmUpErr
+	close(engine.warmUp.done)
+	if err := engine.WaitForReady(time.Second); err != warmUpErr {
+		t.Fatalf("expect warm up error, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// weightOverride 实例的本地权重
+type weightOverride struct {
+	weight     int
+	expireTime time.Time
+}
+
+// weightOverrides 外部控制器（弹性伸缩、压测及降级演练等）设置的本地实例权重，只影响本进程的负载均衡，到期后自动失效
+type weightOverrides struct {
+	mutex     sync.RWMutex
+	overrides map[model.ServiceKey]map[string]*weightOverride
+}
+
+// OverrideInstanceWeight 在本地覆盖实例参与负载均衡的权重，不修改服务端数据，到期后自动失效
+func (e *Engine) OverrideInstanceWeight(svcKey model.ServiceKey, instanceID string, weight int,
+	ttl time.Duration) error {
+	if len(svcKey.Namespace) == 0 || len(svcKey.Service) == 0 || len(instanceID) == 0 {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"namespace, service and instanceID are required to override instance weight")
+	}
+	if weight < model.MinWeight || weight > model.MaxWeight {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"instance weight should be in [%d, %d], got %d", model.MinWeight, model.MaxWeight, weight)
+	}
+	if ttl < 0 || ttl > model.MaxWeightOverrideTTL {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"weight override ttl should be in [0, %v], got %v", model.MaxWeightOverrideTTL, ttl)
+	}
+	if ttl == 0 {
+		ttl = model.DefaultWeightOverrideTTL
+	}
+	e.weightOverrides.mutex.Lock()
+	if e.weightOverrides.overrides == nil {
+		e.weightOverrides.overrides = make(map[model.ServiceKey]map[string]*weightOverride)
+	}
+	instances, ok := e.weightOverrides.overrides[svcKey]
+	if !ok {
+		instances = make(map[string]*weightOverride)
+		e.weightOverrides.overrides[svcKey] = instances
+	}
+	instances[instanceID] = &weightOverride{weight: weight, expireTime: time.Now().Add(ttl)}
+	e.weightOverrides.mutex.Unlock()
+	log.GetBaseLogger().Infof("[WeightOverride] override weight of instance %s of %s to %d, ttl %v",
+		instanceID, svcKey, weight, ttl)
+	return nil
+}
+
+// ClearInstanceWeightOverride 清除实例的本地权重覆盖
+func (e *Engine) ClearInstanceWeightOverride(svcKey model.ServiceKey, instanceID string) {
+	e.weightOverrides.mutex.Lock()
+	defer e.weightOverrides.mutex.Unlock()
+	instances := e.weightOverrides.overrides[svcKey]
+	if _, ok := instances[instanceID]; !ok {
+		return
+	}
+	delete(instances, instanceID)
+	if len(instances) == 0 {
+		delete(e.weightOverrides.overrides, svcKey)
+	}
+	log.GetBaseLogger().Infof("[WeightOverride] clear weight override of instance %s of %s", instanceID, svcKey)
+}
+
+// getWeightOverrides 获取服务未过期的实例权重，过期的权重会被删除
+func (e *Engine) getWeightOverrides(svcKey model.ServiceKey) map[string]int {
+	now := time.Now()
+	e.weightOverrides.mutex.RLock()
+	instances := e.weightOverrides.overrides[svcKey]
+	if len(instances) == 0 {
+		e.weightOverrides.mutex.RUnlock()
+		return nil
+	}
+	weights := make(map[string]int, len(instances))
+	var expired bool
+	for instanceID, override := range instances {
+		if now.Before(override.expireTime) {
+			weights[instanceID] = override.weight
+		} else {
+			expired = true
+		}
+	}
+	e.weightOverrides.mutex.RUnlock()
+	if expired {
+		e.purgeWeightOverrides(svcKey, now)
+	}
+	return weights
+}
+
+func (e *Engine) purgeWeightOverrides(svcKey model.ServiceKey, now time.Time) {
+	e.weightOverrides.mutex.Lock()
+	defer e.weightOverrides.mutex.Unlock()
+	instances := e.weightOverrides.overrides[svcKey]
+	for instanceID, override := range instances {
+		if !now.Before(override.expireTime) {
+			delete(instances, instanceID)
+			log.GetBaseLogger().Infof("[WeightOverride] weight override of instance %s of %s expired",
+				instanceID, svcKey)
+		}
+	}
+	if len(instances) == 0 {
+		delete(e.weightOverrides.overrides, svcKey)
+	}
+}
+
+// weightOverrideInstance 使用本地权重参与负载均衡的实例，选中后还原为原始实例
+type weightOverrideInstance struct {
+	model.Instance
+	weight int
+}
+
+// GetWeight 返回本地覆盖的权重
+func (w *weightOverrideInstance) GetWeight() int {
+	return w.weight
+}
+
+// unwrapWeightOverride 还原负载均衡选中的实例
+func unwrapWeightOverride(instance model.Instance) model.Instance {
+	if override, ok := instance.(*weightOverrideInstance); ok {
+		return override.Instance
+	}
+	return instance
+}
+
+// applyWeightOverrides 使用本地实例权重重建负载均衡的集群，覆盖后总权重为0时不做覆盖
+func (e *Engine) applyWeightOverrides(commonRequest *data.CommonInstancesRequest) {
+	cluster := commonRequest.Criteria.Cluster
+	if cluster == nil {
+		return
+	}
+	weights := e.getWeightOverrides(commonRequest.DstService)
+	if len(weights) == 0 {
+		return
+	}
+	instances, _ := cluster.GetInstances()
+	selected := make([]model.Instance, 0, len(instances))
+	var (
+		overridden  bool
+		totalWeight int
+	)
+	for _, instance := range instances {
+		if weight, ok := weights[instance.GetId()]; ok {
+			instance = &weightOverrideInstance{Instance: instance, weight: weight}
+			overridden = true
+		}
+		totalWeight += instance.GetWeight()
+		selected = append(selected, instance)
+	}
+	if !overridden {
+		return
+	}
+	if totalWeight == 0 {
+		log.GetBaseLogger().Warnf("[WeightOverride] total weight of %s is 0 after override, skip override",
+			commonRequest.DstService)
+		return
+	}
+	replaceBalanceCluster(commonRequest, selected)
+}
@@ -0,0 +1,69 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package flow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestWeightOverride 测试本地实例权重的校验、过期、清除以及选中后还原实例
+func TestWeightOverride(t *testing.T) {
+	e := &Engine{}
+	svcKey := model.ServiceKey{Namespace: "Test", Service: "svc"}
+	if err := e.OverrideInstanceWeight(svcKey, "", 100, 0); err == nil {
+		t.Fatal("expect instance id required")
+	}
+	if err := e.OverrideInstanceWeight(svcKey, "ins-1", model.MaxWeight+1, 0); err == nil {
+		t.Fatal("expect weight out of bounds")
+	}
+	if err := e.OverrideInstanceWeight(svcKey, "ins-1", 100, 48*time.Hour); err == nil {
+		t.Fatal("expect ttl out of bounds")
+	}
+
+	if err := e.OverrideInstanceWeight(svcKey, "ins-1", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.OverrideInstanceWeight(svcKey, "ins-2", 500, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	weights := e.getWeightOverrides(svcKey)
+	if len(weights) != 2 || weights["ins-1"] != 0 || weights["ins-2"] != 500 {
+		t.Fatalf("unexpected weights %v", weights)
+	}
+	if len(e.getWeightOverrides(model.ServiceKey{Namespace: "Test", Service: "other"})) != 0 {
+		t.Fatal("expect no override for other service")
+	}
+
+	e.weightOverrides.overrides[svcKey]["ins-2"].expireTime = time.Now().Add(-time.Second)
+	if weights = e.getWeightOverrides(svcKey); len(weights) != 1 {
+		t.Fatalf("expect override of ins-2 expired, got %v", weights)
+	}
+	e.ClearInstanceWeightOverride(svcKey, "ins-1")
+	if len(e.getWeightOverrides(svcKey)) != 0 || len(e.weightOverrides.overrides) != 0 {
+		t.Fatal("expect overrides cleared")
+	}
+
+	origin := &weightedInstance{id: "ins-1", weight: 100}
+	wrapped := &weightOverrideInstance{Instance: origin, weight: 10}
+	if wrapped.GetWeight() != 10 || wrapped.GetId() != "ins-1" || unwrapWeightOverride(wrapped) != origin {
+		t.Fatal("expect instance restored after load balance")
+	}
+}
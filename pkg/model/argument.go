@@ -54,6 +54,9 @@ const (
 	LabelKeyCookie        = "$cookie."
 )
 
+// MultiValueSeparator 多值参数在内部传递时各个值之间的分隔符
+const MultiValueSeparator = "\x1f"
+
 // Argument 限流/路由参数
 type Argument struct {
 	argumentType int
@@ -75,6 +78,11 @@ func (a Argument) Value() string {
 	return a.value
 }
 
+// Values 获取参数的全部取值，单值参数只返回一个值
+func (a Argument) Values() []string {
+	return strings.Split(a.value, MultiValueSeparator)
+}
+
 func (a Argument) String() string {
 	return fmt.Sprintf("%s:%s:%s", argumentTypeToName[a.argumentType], a.key, a.value)
 }
@@ -140,6 +148,16 @@ func BuildCookieArgument(key, value string) Argument {
 	}
 }
 
+// BuildMultiValueArgument 构建多值参数，例如一个请求同时涉及多个产品编码，
+// 限流规则匹配时任一取值满足即视为匹配，NOT_EQUALS 及 NOT_IN 需要全部取值满足
+func BuildMultiValueArgument(argumentType int, key string, values ...string) Argument {
+	return Argument{
+		argumentType: argumentType,
+		key:          key,
+		value:        strings.Join(values, MultiValueSeparator),
+	}
+}
+
 func BuildArgumentFromLabel(labelKey string, labelValue string) Argument {
 	if labelKey == LabelKeyMethod {
 		return BuildMethodArgument(labelValue)
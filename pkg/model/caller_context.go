@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+)
+
+// CallerContext 请求携带的调用方上下文，调用方的截止时间及取消信号会传递到缓存等待、重试以及与服务端的交互中，
+// 先于SDK内部的超时时间生效
+type CallerContext struct {
+	ctx context.Context
+}
+
+// Context 获取调用方上下文，未设置时返回context.Background()
+func (c *CallerContext) Context() context.Context {
+	if nil == c.ctx {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// SetContext 设置调用方上下文
+func (c *CallerContext) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// ContextError 调用方上下文已取消或者超过截止时间时返回对应的错误，否则返回nil
+func ContextError(ctx context.Context) error {
+	if nil == ctx {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return NewSDKError(ErrCodeAPITimeoutError, err, "caller context done")
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestControlParamDeadline 测试调用方上下文截止时间与流程截止时间的合并
+func TestControlParamDeadline(t *testing.T) {
+	now := time.Now()
+	param := &ControlParam{Deadline: now.Add(time.Second)}
+	if !param.GetDeadline().Equal(param.Deadline) {
+		t.Fatal("expect flow deadline without caller context")
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(100*time.Millisecond))
+	defer cancel()
+	param.Context = ctx
+	if !param.GetDeadline().Equal(now.Add(100 * time.Millisecond)) {
+		t.Fatal("expect earlier caller deadline")
+	}
+	param.Deadline = now.Add(10 * time.Millisecond)
+	if !param.GetDeadline().Equal(param.Deadline) {
+		t.Fatal("expect earlier flow deadline")
+	}
+}
+
+// TestContextError 测试调用方上下文取消后返回的错误
+func TestContextError(t *testing.T) {
+	var req GetOneInstanceRequest
+	if err := ContextError(req.Context()); err != nil {
+		t.Fatalf("expect no error for background context, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	req.SetContext(ctx)
+	cancel()
+	err := ContextError(req.Context())
+	if err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expect context canceled error, got %v", err)
+	}
+}
@@ -34,6 +34,8 @@ type ResourceStat struct {
 	RetCode   string
 	Delay     time.Duration
 	RetStatus RetStatus
+	// Permit 可选，Check 返回的 CheckResult.Permit，上报时归还该许可
+	Permit *BulkheadPermit
 }
 
 type Node struct {
@@ -45,6 +47,56 @@ func (n Node) String() string {
 	return n.Host + ":" + strconv.FormatUint(uint64(n.Port), 10)
 }
 
+// BulkheadRuleName 舱壁并发已满时，CheckResult中返回的规则名
+const BulkheadRuleName = "bulkhead"
+
+// BulkheadPermit Check 通过舱壁检查时占用的并发许可，只会归还一次
+type BulkheadPermit struct {
+	once    sync.Once
+	release func()
+}
+
+// NewBulkheadPermit 创建并发许可，release 为归还许可的方法
+func NewBulkheadPermit(release func()) *BulkheadPermit {
+	return &BulkheadPermit{release: release}
+}
+
+// Release 归还并发许可，重复调用或者许可为nil时忽略
+func (p *BulkheadPermit) Release() {
+	if p == nil {
+		return
+	}
+	p.once.Do(p.release)
+}
+
+// ExternalHealthRuleName 应用上报实例不健康时，CheckResult中返回的规则名
+const ExternalHealthRuleName = "external-health"
+
+// QuarantineRuleName 实例因上一个进程的熔断记录被隔离时，熔断状态中的规则名
+const QuarantineRuleName = "quarantine"
+
+// ExternalHealthReport 应用自行判定的实例健康状态，例如基于复制延迟等比调用成功率更丰富的信号
+type ExternalHealthReport struct {
+	// 必选，实例标识
+	InstanceKey
+	// 必选，是否健康
+	Healthy bool
+	// 可选，判定原因
+	Reason string
+}
+
+// Validate 校验健康状态上报
+func (r *ExternalHealthReport) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ExternalHealthReport can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.Service) == 0 || len(r.Host) == 0 || r.Port <= 0 || r.Port >= 65536 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"ExternalHealthReport: namespace, service, host and port are required, got %s", r.InstanceKey)
+	}
+	return nil
+}
+
 // Resource
 type Resource interface {
 	fmt.Stringer
@@ -297,6 +349,9 @@ type CheckResult struct {
 	Pass         bool
 	RuleName     string
 	FallbackInfo *FallbackInfo
+	// Permit 资源配置了舱壁并且检查通过时占用的并发许可，调用结束后通过 ResourceStat.Permit 随 Report 归还，
+	// 不上报调用结果时需直接调用 Permit.Release 归还
+	Permit *BulkheadPermit
 }
 
 type RequestContext struct {
@@ -304,6 +359,8 @@ type RequestContext struct {
 	Callee      *ServiceKey
 	Method      string
 	CodeConvert ResultToErrorCode
+	// Budget 可选，逻辑请求的尝试预算，每次通过熔断检查的调用消耗一次尝试
+	Budget *RequestBudget
 }
 
 type ResponseContext struct {
@@ -486,3 +543,24 @@ func (c *HalfOpenStatus) IsAvailable() bool {
 }
 
 var ErrorCallAborted = errors.New("call aborted")
+
+// ErrCircuitOpen 严格熔断模式下，选中的实例或接口处于熔断状态，可通过 errors.Is 判断
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError 严格熔断模式下 GetOneInstance 返回的熔断错误，携带被熔断的资源以及熔断规则
+type CircuitOpenError struct {
+	// Resource 处于熔断状态的资源
+	Resource Resource
+	// RuleName 触发熔断的规则名
+	RuleName string
+}
+
+// Error 错误信息
+func (c *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s, resource %s, rule %s", ErrCircuitOpen.Error(), c.Resource.String(), c.RuleName)
+}
+
+// Is 与 ErrCircuitOpen 等价
+func (c *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
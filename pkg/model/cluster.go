@@ -33,6 +33,12 @@ const (
 	composedMetaSeparator = ","
 	NearbyMetadataEnable  = "internal-enable-nearby"
 	CanaryMetadataEnable  = "internal-canary"
+	// ProvisionalMetadata 本地注入的、尚未被服务端确认的自注册实例标记
+	ProvisionalMetadata = "internal-provisional"
+	// TopologyNodeMetadata 实例所在节点名称的元数据key，注册时写入，实例创建时解析为拓扑字段
+	TopologyNodeMetadata = "internal-topology-node"
+	// TopologyRackMetadata 实例所在机架的元数据key，注册时写入，实例创建时解析为拓扑字段
+	TopologyRackMetadata = "internal-topology-rack"
 
 	CanaryMetaKey = "canary"
 )
@@ -42,16 +48,21 @@ type Location struct {
 	Region string
 	Zone   string
 	Campus string
+	// Rack 机架，园区内更细粒度的位置，就近路由开启机架级别匹配时使用
+	Rack string
 }
 
 // String 位置信息ToString
 func (l Location) String() string {
-	return fmt.Sprintf("{region: %s, zone: %s, campus: %s}", l.Region, l.Zone, l.Campus)
+	if l.Rack == "" {
+		return fmt.Sprintf("{region: %s, zone: %s, campus: %s}", l.Region, l.Zone, l.Campus)
+	}
+	return fmt.Sprintf("{region: %s, zone: %s, campus: %s, rack: %s}", l.Region, l.Zone, l.Campus, l.Rack)
 }
 
 // IsEmpty 位置信息是否为空
 func (l *Location) IsEmpty() bool {
-	return l.Zone == "" && l.Region == "" && l.Campus == ""
+	return l.Zone == "" && l.Region == "" && l.Campus == "" && l.Rack == ""
 }
 
 // ClusterKey 集群缓存KEY对象
@@ -132,6 +143,7 @@ func (c *Cluster) reset(clusters ServiceClusters) {
 	c.Location.Region = ""
 	c.Location.Campus = ""
 	c.Location.Zone = ""
+	c.Location.Rack = ""
 	c.Metadata = nil
 	c.HasLimitedInstances = false
 	c.MissLocationInstances = false
@@ -1137,5 +1149,10 @@ func matchLocation(instance Instance, location Location) bool {
 	if len(location.Campus) > 0 && len(campus) > 0 && location.Campus != campus {
 		return false
 	}
+	if len(location.Rack) > 0 {
+		if rack := instance.GetRack(); len(rack) > 0 && location.Rack != rack {
+			return false
+		}
+	}
 	return true
 }
@@ -31,6 +31,8 @@ const (
 	Added
 	// NotChanged 没有变更
 	NotChanged
+	// RolledBack 安全发布校验失败或收到回滚信号，内存中的值已回退到上一个版本
+	RolledBack
 )
 
 type (
@@ -51,6 +53,62 @@ type ConfigFileChangeEvent struct {
 	ChangeType ChangeType
 	// 配置文件持久化数据
 	Persistent Persistent
+	// Replayed 是否为添加监听器时回放的事件，而非实时变更
+	Replayed bool
+	// RollbackReason 回滚原因，仅 ChangeType 为 RolledBack 时有值
+	RollbackReason string
+}
+
+// ConfigFileValidator 安全发布的校验回调，变更已应用到内存后调用，返回错误则回滚到上一个版本
+type ConfigFileValidator func(event ConfigFileChangeEvent) error
+
+// SafeApplyOptions 配置文件安全发布选项
+type SafeApplyOptions struct {
+	// Validator 变更应用后的校验回调，为空时只支持在回滚窗口内手动回滚
+	Validator ConfigFileValidator
+	// RollbackWindow 变更通过校验后，允许通过 Rollback 手动回滚的时间窗口，为0时不允许手动回滚
+	RollbackWindow time.Duration
+}
+
+// ChangeListenerOptions 添加配置文件变更监听器的选项
+type ChangeListenerOptions struct {
+	// ReplayCurrent 添加时立即以当前值回放一个 NotChanged 事件，OldValue 与 NewValue 均为当前值
+	ReplayCurrent bool
+	// ReplayHistory 添加时回放内存中保留的最近 N 个变更事件，先于当前值回放
+	ReplayHistory int
+}
+
+// ChangeListenerOption 配置文件变更监听器选项
+type ChangeListenerOption func(*ChangeListenerOptions)
+
+// WithReplayCurrent 添加监听器时立即回放当前值，简化监听器的初始化逻辑
+func WithReplayCurrent() ChangeListenerOption {
+	return func(o *ChangeListenerOptions) {
+		o.ReplayCurrent = true
+	}
+}
+
+// WithReplayHistory 添加监听器时回放最近 n 个变更事件，内存中保留的事件数量有上限，超出部分不会回放
+func WithReplayHistory(n int) ChangeListenerOption {
+	return func(o *ChangeListenerOptions) {
+		o.ReplayHistory = n
+	}
+}
+
+// ConfigFileOperationOptions 创建、更新及发布配置文件的选项
+type ConfigFileOperationOptions struct {
+	// Token 本次操作使用的鉴权token，为空时依次使用命名空间级别及全局配置的token
+	Token string
+}
+
+// ConfigFileOperationOption 创建、更新及发布配置文件的选项
+type ConfigFileOperationOption func(*ConfigFileOperationOptions)
+
+// WithConfigToken 指定本次操作使用的鉴权token，用于不同命名空间使用不同写权限token的场景
+func WithConfigToken(token string) ConfigFileOperationOption {
+	return func(o *ConfigFileOperationOptions) {
+		o.Token = token
+	}
 }
 
 // Persistent 配置文件持久化数据
@@ -111,12 +169,17 @@ type ConfigFile interface {
 	GetContent() string
 	// HasContent 是否有配置内容
 	HasContent() bool
-	// AddChangeListenerWithChannel 增加配置文件变更监听器
-	AddChangeListenerWithChannel() <-chan ConfigFileChangeEvent
-	// AddChangeListener 增加配置文件变更监听器
-	AddChangeListener(cb OnConfigFileChange)
+	// AddChangeListenerWithChannel 增加配置文件变更监听器，可通过选项回放当前值以及历史变更
+	AddChangeListenerWithChannel(opts ...ChangeListenerOption) <-chan ConfigFileChangeEvent
+	// AddChangeListener 增加配置文件变更监听器，可通过选项回放当前值以及历史变更
+	AddChangeListener(cb OnConfigFileChange, opts ...ChangeListenerOption)
 	// GetPersistent 获取文件持久化数据
 	GetPersistent() Persistent
+	// EnableSafeApply 开启安全发布：变更先应用到内存并调用校验回调，校验通过后才通知监听器，
+	// 校验失败则回退到上一个版本并发出 RolledBack 事件。回滚只作用于内存中的值，不影响服务端发布的版本
+	EnableSafeApply(options SafeApplyOptions)
+	// Rollback 在回滚窗口内将内存中的值回退到上一个版本，并发出 RolledBack 事件
+	Rollback(reason string) error
 }
 
 // DefaultConfigFileMetadata 默认 ConfigFileMetadata 实现类
@@ -169,6 +232,14 @@ type GetConfigFileRequest struct {
 	Mode      GetConfigFileRequestMode
 }
 
+// GetConfigFilesRequest 批量获取同一分组下的多个配置文件，获取的文件均会被订阅
+type GetConfigFilesRequest struct {
+	Namespace string
+	FileGroup string
+	FileNames []string
+	Mode      GetConfigFileRequestMode
+}
+
 type GetConfigGroupRequest struct {
 	Namespace string
 	FileGroup string
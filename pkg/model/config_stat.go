@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// ConfigOperation 配置中心的操作类型
+type ConfigOperation string
+
+const (
+	// ConfigOperationFetch 获取配置文件
+	ConfigOperationFetch ConfigOperation = "fetch"
+	// ConfigOperationWatch 长轮询监听配置文件变更
+	ConfigOperationWatch ConfigOperation = "watch"
+	// ConfigOperationCreate 创建配置文件
+	ConfigOperationCreate ConfigOperation = "create"
+	// ConfigOperationUpdate 更新配置文件
+	ConfigOperationUpdate ConfigOperation = "update"
+	// ConfigOperationPublish 发布配置文件
+	ConfigOperationPublish ConfigOperation = "publish"
+)
+
+// ConfigOperationGauge 配置中心操作的统计，按命名空间及分组上报
+type ConfigOperationGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	FileGroup string
+	// Operation 操作类型
+	Operation ConfigOperation
+	// Success 操作是否成功
+	Success bool
+	// Delay 操作耗时
+	Delay time.Duration
+}
+
+// ConfigPropagationGauge 配置变更从服务端发布到通知监听器的传播时延
+type ConfigPropagationGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	FileGroup string
+	FileName  string
+	// Delay 发布时间到监听器回调完成的时延
+	Delay time.Duration
+}
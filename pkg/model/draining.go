@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// DrainingMetadata 实例排空状态的元数据键，值为开始排空的Unix时间戳（秒）。
+// 与隔离不同，排空中的实例不再接收新会话，但在宽限期内仍然可以承接携带亲和键的已有会话，宽限期过后完全摘除
+const DrainingMetadata = "internal-draining"
+
+// DrainingMetadataValue 构造开始排空时间对应的元数据值，供服务提供方更新实例元数据使用
+func DrainingMetadataValue(since time.Time) string {
+	return strconv.FormatInt(since.Unix(), 10)
+}
+
+// DrainingSince 获取实例开始排空的时间，实例未处于排空状态时返回false
+func DrainingSince(instance Instance) (time.Time, bool) {
+	value, ok := instance.GetMetadata()[DrainingMetadata]
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		// 无法解析时间时视为刚开始排空
+		return time.Now(), true
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// DrainingState 实例在排空状态下的可用性
+type DrainingState int
+
+const (
+	// NotDraining 实例未处于排空状态
+	NotDraining DrainingState = iota
+	// DrainingInGrace 实例排空中，宽限期内只承接已有会话
+	DrainingInGrace
+	// Drained 实例已超过排空宽限期，不再承接任何请求
+	Drained
+)
+
+// GetDrainingState 获取实例在当前时间的排空状态
+func GetDrainingState(instance Instance, now time.Time, gracePeriod time.Duration) DrainingState {
+	since, ok := DrainingSince(instance)
+	if !ok {
+		return NotDraining
+	}
+	if now.Sub(since) < gracePeriod {
+		return DrainingInGrace
+	}
+	return Drained
+}
@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"time"
 )
 
@@ -44,6 +45,22 @@ type ControlParam struct {
 	Timeout       time.Duration
 	MaxRetry      int
 	RetryInterval time.Duration
+	// 可选，整个流程的截止时间，缓存等待不超过该时间，零值表示不限制
+	Deadline time.Time
+	// 可选，调用方上下文，取消或者超过截止时间时提前结束等待及重试
+	Context context.Context
+}
+
+// GetDeadline 获取流程截止时间，取Deadline与调用方上下文截止时间中较早的一个，零值表示不限制
+func (c *ControlParam) GetDeadline() time.Time {
+	deadline := c.Deadline
+	if nil == c.Context {
+		return deadline
+	}
+	if ctxDeadline, ok := c.Context.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		return ctxDeadline
+	}
+	return deadline
 }
 
 // CacheValueQuery 缓存查询请求对象
@@ -110,16 +127,18 @@ type Engine interface {
 	InitCalleeService(req *InitCalleeServiceRequest) error
 	// SyncGetConfigFile 同步获取配置文件
 	SyncGetConfigFile(req *GetConfigFileRequest) (ConfigFile, error)
+	// SyncGetConfigFiles 同步批量获取配置文件
+	SyncGetConfigFiles(req *GetConfigFilesRequest) (map[string]ConfigFile, error)
 	// SyncGetConfigGroup 同步获取配置文件
 	SyncGetConfigGroup(namespace, fileGroup string) (ConfigFileGroup, error)
 	// SyncGetConfigGroupWithReq 同步获取配置文件
 	SyncGetConfigGroupWithReq(req *GetConfigGroupRequest) (ConfigFileGroup, error)
 	// SyncCreateConfigFile 同步创建配置文件
-	SyncCreateConfigFile(namespace, fileGroup, fileName, content string) error
+	SyncCreateConfigFile(namespace, fileGroup, fileName, content string, opts ...ConfigFileOperationOption) error
 	// SyncUpdateConfigFile 同步更新配置文件
-	SyncUpdateConfigFile(namespace, fileGroup, fileName, content string) error
+	SyncUpdateConfigFile(namespace, fileGroup, fileName, content string, opts ...ConfigFileOperationOption) error
 	// SyncPublishConfigFile 同步发布配置文件
-	SyncPublishConfigFile(namespace, fileGroup, fileName string) error
+	SyncPublishConfigFile(namespace, fileGroup, fileName string, opts ...ConfigFileOperationOption) error
 	// ProcessRouters 执行路由链过滤，返回经过路由后的实例列表
 	ProcessRouters(req *ProcessRoutersRequest) (*InstancesResponse, error)
 	// ProcessLoadBalance 执行负载均衡策略，返回负载均衡后的实例
@@ -132,8 +151,81 @@ type Engine interface {
 	Check(Resource) (*CheckResult, error)
 	// Report
 	Report(*ResourceStat) error
+	// ReportExternalHealth 上报应用自行判定的实例健康状态
+	ReportExternalHealth(*ExternalHealthReport) error
+	// ClearQuarantine 清除熔断实例隔离名单，不指定实例时清除全部，返回清除的实例数
+	ClearQuarantine(keys ...InstanceKey) int
+	// AcquireConnection 按照被调服务的连接数限制获取建立连接的许可，连接关闭时调用返回的release，
+	// 服务未配置限制时直接放通，超过限制时返回的错误可通过 errors.Is(err, ErrConnectionLimited) 判断
+	AcquireConnection(svcKey ServiceKey) (release func(), err error)
 	// MakeFunctionDecorator
 	MakeFunctionDecorator(CustomerFunction, *RequestContext) DecoratorFunction
 	// MakeInvokeHandler
 	MakeInvokeHandler(*RequestContext) InvokeHandler
+	// WaitForReady 等待缓存预热完成
+	WaitForReady(timeout time.Duration) error
+	// ExportSnapshot 导出本地缓存快照
+	ExportSnapshot(path string) error
+	// ImportSnapshot 导入本地缓存快照
+	ImportSnapshot(path string) error
+	// WaitForPeers 等待服务的健康实例数达到下限
+	WaitForPeers(ctx context.Context, req *WaitForPeersRequest) error
+	// GetInstancesDiff 获取服务实例自起始版本以来的增量变更
+	GetInstancesDiff(svcKey ServiceKey, sinceRevision string) (*InstancesDiffResponse, error)
+	// SyncQueryInstances 直接向服务端查询服务实例，不经过本地缓存
+	SyncQueryInstances(req *QueryInstancesRequest) (*QueryInstancesResponse, error)
+	// SyncValidateContract 按照被调服务上报的契约校验请求的方法及路径
+	SyncValidateContract(req *ValidateContractRequest) (*ContractValidationResult, error)
+	// SyncReportShadowComparison 上报影子请求与主请求应答的对比结果
+	SyncReportShadowComparison(req *ReportShadowComparisonRequest) error
+	// SyncImportInstances 同步外部系统的实例全集
+	SyncImportInstances(req *ImportInstancesRequest) (*ImportInstancesResponse, error)
+	// AddPreLoadBalanceHook 添加负载均衡前执行的实例过滤钩子
+	AddPreLoadBalanceHook(hook PreLoadBalanceHook)
+	// AddPostLoadBalanceHook 添加负载均衡后执行的实例选择钩子
+	AddPostLoadBalanceHook(hook PostLoadBalanceHook)
+	// AddInstancesResultHook 添加GetInstances结果的后处理钩子
+	AddInstancesResultHook(hook InstancesResultHook)
+	// AddAddressTranslator 添加实例地址转换器
+	AddAddressTranslator(translator AddressTranslator)
+	// AddServiceNameMapper 添加服务名映射器
+	AddServiceNameMapper(mapper ServiceNameMapper)
+	// Drain 下线前排空：反注册实例、停止分配配额、刷新统计上报
+	Drain(ctx context.Context) error
+	// EnterLameduck 缩短自动心跳实例的TTL，使进程被强制终止后服务端能尽快摘除实例
+	EnterLameduck() error
+	// TrackServerRequest 记录一个开始处理的服务端请求，请求处理完成时调用返回的函数
+	TrackServerRequest() (done func())
+	// GracefulShutdown 优雅下线：隔离实例、等待在途请求处理完成、反注册实例并刷新统计上报
+	GracefulShutdown(ctx context.Context) error
+	// RecentCalls 获取最近的API调用记录，按时间先后排列
+	RecentCalls() []APICallRecord
+	// SubscribeEvents 订阅SDK事件，ctx取消或者SDK销毁时自动取消订阅
+	SubscribeEvents(ctx context.Context, subscriber *EventSubscriber) error
+	// SetTrafficSplit 设置服务的本地流量比例，到期后自动失效
+	SetTrafficSplit(req *TrafficSplitRequest) error
+	// ClearTrafficSplit 清除服务的本地流量比例
+	ClearTrafficSplit(svcKey ServiceKey)
+	// OverrideInstanceWeight 在本地覆盖实例参与负载均衡的权重，不修改服务端数据，到期后自动失效
+	OverrideInstanceWeight(svcKey ServiceKey, instanceID string, weight int, ttl time.Duration) error
+	// ClearInstanceWeightOverride 清除实例的本地权重覆盖
+	ClearInstanceWeightOverride(svcKey ServiceKey, instanceID string)
 }
+
+// PreLoadBalanceHook 负载均衡前执行的实例过滤钩子，返回参与负载均衡的实例，返回空列表时本次选择失败
+type PreLoadBalanceHook func(svcKey ServiceKey, instances []Instance) []Instance
+
+// PostLoadBalanceHook 负载均衡后执行的钩子，可以替换负载均衡选中的实例，返回错误时本次选择失败
+type PostLoadBalanceHook func(svcKey ServiceKey, instance Instance) (Instance, error)
+
+// InstancesResultHook GetInstances结果的后处理钩子，在路由链之后、构建应答之前执行，
+// 可对实例重新排序，或者返回包装后的实例以附加信息；传入的列表为副本，可以原地修改，返回nil时保持原列表
+type InstancesResultHook func(svcKey ServiceKey, instances []Instance) []Instance
+
+// AddressTranslator 实例地址转换器，在实例返回给调用方之前执行，用于混合网络中将注册地址转换为本进程可达的地址，
+// 返回false表示不做转换；转换后实例的ID及四元组标识保持不变
+type AddressTranslator func(svcKey ServiceKey, instance Instance) (host string, port uint32, translated bool)
+
+// ServiceNameMapper 服务名映射器，在服务发现之前将调用方使用的服务名映射为实际的服务名，应答中再还原为调用方使用的服务名，
+// 返回false表示不做映射
+type ServiceNameMapper func(svcKey ServiceKey) (mapped ServiceKey, ok bool)
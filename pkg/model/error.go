@@ -18,6 +18,7 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -148,6 +149,11 @@ func (s *sdkError) Error() string {
 	return fmt.Sprintf("Polaris-%v(%s): %s", s.ErrorCode(), errCodeStr, s.errDetail)
 }
 
+// Unwrap 返回导致错误的原因，用于 errors.Is/errors.As 判断
+func (s *sdkError) Unwrap() error {
+	return s.cause
+}
+
 // ServerCode 服务端返回码
 func (s *sdkError) ServerCode() uint32 {
 	return s.serverCode
@@ -197,6 +203,16 @@ func IsSuccessResultCode(retCode uint32) bool {
 	return retCode/RetCodeDivFactor == SuccessRetCode || retCode == uint32(apimodel.Code_NotFoundResource)
 }
 
+// IsInstanceNotFoundError 判断是否为服务端找不到实例的错误，通常是服务端清理了实例导致心跳失败
+func IsInstanceNotFoundError(err error) bool {
+	var sdkErr SDKError
+	if !errors.As(err, &sdkErr) {
+		return false
+	}
+	code := sdkErr.ServerCode()
+	return code == uint32(apimodel.Code_NotFoundInstance) || code == uint32(apimodel.Code_NotFoundResource)
+}
+
 // IsServerException 判断是否为内部server错误
 func IsServerException(retCode uint32) bool {
 	return retCode/RetCodeDivFactor == ServerExceptionRetCode
@@ -0,0 +1,176 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "time"
+
+// EventKind 可订阅的SDK事件类型
+type EventKind int
+
+const (
+	// EventKindInstancesChange 服务实例变更事件，事件对象为 InstancesChangeEvent
+	EventKindInstancesChange EventKind = iota
+	// EventKindCircuitBreaker 熔断状态变更事件，事件对象为 CircuitBreakerEvent
+	EventKindCircuitBreaker
+	// EventKindConfigChange 已订阅配置文件的变更事件，事件对象为 ConfigFileChangeEvent
+	EventKindConfigChange
+	// EventKindRateLimitRule 限流规则变更事件，事件对象为 RateLimitRuleEvent
+	EventKindRateLimitRule
+	// EventKindCachePreload 持久化缓存加载进度事件，事件对象为 CachePreloadEvent
+	EventKindCachePreload
+	// EventKindMemoryShed 缓存内存超出预算进入或退出降载模式的事件，事件对象为 MemoryShedEvent
+	EventKindMemoryShed
+)
+
+// String 事件类型名称
+func (k EventKind) String() string {
+	switch k {
+	case EventKindInstancesChange:
+		return "InstancesChange"
+	case EventKindCircuitBreaker:
+		return "CircuitBreaker"
+	case EventKindConfigChange:
+		return "ConfigChange"
+	case EventKindRateLimitRule:
+		return "RateLimitRule"
+	case EventKindCachePreload:
+		return "CachePreload"
+	case EventKindMemoryShed:
+		return "MemoryShed"
+	}
+	return "Unknown"
+}
+
+// InstancesChangeEvent 服务实例变更事件
+type InstancesChangeEvent struct {
+	// Service 发生变更的服务
+	Service ServiceKey
+	// Revision 变更后的服务实例版本，服务被删除时为空
+	Revision string
+	// InstanceEvent 新增、更新、删除的实例
+	InstanceEvent *InstanceEvent
+}
+
+// CircuitBreakerEvent 熔断状态变更事件
+type CircuitBreakerEvent struct {
+	// Resource 熔断资源
+	Resource Resource
+	// Status 变更后的熔断状态
+	Status CircuitBreakerStatus
+}
+
+// RateLimitRuleEvent 限流规则变更事件
+type RateLimitRuleEvent struct {
+	// Service 限流规则所属的服务
+	Service ServiceKey
+	// Revision 变更后的规则版本，规则被删除时为空
+	Revision string
+	// Deleted 规则是否已从缓存中删除
+	Deleted bool
+}
+
+// CachePreloadEvent 持久化缓存加载进度事件
+type CachePreloadEvent struct {
+	// Loaded 已加载成功的缓存数
+	Loaded int
+	// Failed 加载失败的缓存数
+	Failed int
+	// Total 需要加载的缓存总数
+	Total int
+	// Elapsed 从开始加载到当前的耗时
+	Elapsed time.Duration
+	// Background 启动等待已超时，当前为后台加载
+	Background bool
+	// Done 是否已全部加载完成
+	Done bool
+}
+
+// MemoryShedEvent 缓存内存降载模式变更事件
+type MemoryShedEvent struct {
+	// Shedding 变更后是否处于降载模式
+	Shedding bool
+	// UsedBytes 本地缓存的估算内存
+	UsedBytes int64
+	// BudgetBytes 缓存内存预算
+	BudgetBytes int64
+	// Evicted 本次淘汰的服务缓存数
+	Evicted int
+}
+
+// EventBufferPolicy 订阅者缓冲区满时的处理策略
+type EventBufferPolicy int
+
+const (
+	// DropNewestEvent 缓冲区满时丢弃新到达的事件
+	DropNewestEvent EventBufferPolicy = iota
+	// DropOldestEvent 缓冲区满时丢弃最早的事件，保证订阅者总能看到最新的事件
+	DropOldestEvent
+)
+
+const (
+	// DefaultEventBufferSize 默认的订阅者缓冲区大小
+	DefaultEventBufferSize = 64
+)
+
+// EventSubscribeOptions 事件订阅选项
+type EventSubscribeOptions struct {
+	// BufferSize 订阅者缓冲区大小，不大于0时使用默认值
+	BufferSize int
+	// BufferPolicy 缓冲区满时的处理策略
+	BufferPolicy EventBufferPolicy
+}
+
+// EventSubscribeOption 事件订阅选项设置函数
+type EventSubscribeOption func(*EventSubscribeOptions)
+
+// WithEventBufferSize 设置订阅者缓冲区大小
+func WithEventBufferSize(size int) EventSubscribeOption {
+	return func(o *EventSubscribeOptions) {
+		o.BufferSize = size
+	}
+}
+
+// WithEventBufferPolicy 设置缓冲区满时的处理策略
+func WithEventBufferPolicy(policy EventBufferPolicy) EventSubscribeOption {
+	return func(o *EventSubscribeOptions) {
+		o.BufferPolicy = policy
+	}
+}
+
+// BuildEventSubscribeOptions 构建事件订阅选项
+func BuildEventSubscribeOptions(opts []EventSubscribeOption) *EventSubscribeOptions {
+	options := &EventSubscribeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.BufferSize <= 0 {
+		options.BufferSize = DefaultEventBufferSize
+	}
+	return options
+}
+
+// EventSubscriber 事件订阅者
+type EventSubscriber struct {
+	// Kind 订阅的事件类型
+	Kind EventKind
+	// Deliver 投递事件，在发布事件的协程中持有事件分发的读锁调用，必须立即返回：
+	// 不可阻塞等待消费方，也不可在其中订阅或取消订阅，否则会阻塞事件发布方以及取消订阅
+	Deliver func(event interface{})
+	// Close 取消订阅后调用，调用后不会再投递事件
+	Close func()
+}
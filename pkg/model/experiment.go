@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ExperimentDefinitions 配置中心下发的A/B实验定义，配置文件内容为yaml或json
+type ExperimentDefinitions struct {
+	Experiments []*Experiment `yaml:"experiments" json:"experiments"`
+}
+
+// Experiment 单个实验的定义
+type Experiment struct {
+	// 必选，实验标识
+	Key string `yaml:"key" json:"key"`
+	// 可选，是否停用实验，停用后所有单元都不进入实验
+	Disable bool `yaml:"disable" json:"disable"`
+	// 可选，哈希盐值，默认为实验标识，与灰度分桶路由规则使用相同盐值时分组结果一致
+	Salt string `yaml:"salt" json:"salt"`
+	// 进入实验的流量百分比，范围 [0, 100]，支持两位小数
+	Traffic float64 `yaml:"traffic" json:"traffic"`
+	// 可选，定向规则表达式，基于请求标签求值，为true的单元才会进入实验
+	Targeting string `yaml:"targeting" json:"targeting"`
+	// 必选，实验分组，实验流量按权重依次划分给各分组
+	Variants []*ExperimentVariant `yaml:"variants" json:"variants"`
+}
+
+// ExperimentVariant 实验分组
+type ExperimentVariant struct {
+	// 分组名
+	Name string `yaml:"name" json:"name"`
+	// 分组权重
+	Weight uint32 `yaml:"weight" json:"weight"`
+}
+
+// GetSalt 获取哈希盐值
+func (e *Experiment) GetSalt() string {
+	if len(e.Salt) > 0 {
+		return e.Salt
+	}
+	return e.Key
+}
+
+// Verify 校验实验定义
+func (d *ExperimentDefinitions) Verify() error {
+	var errs error
+	keys := make(map[string]struct{}, len(d.Experiments))
+	for i, experiment := range d.Experiments {
+		if experiment == nil {
+			errs = multierror.Append(errs, fmt.Errorf("experiment %d is nil", i))
+			continue
+		}
+		if len(experiment.Key) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("experiment %d key is required", i))
+			continue
+		}
+		if _, ok := keys[experiment.Key]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("experiment %s is duplicated", experiment.Key))
+		}
+		keys[experiment.Key] = struct{}{}
+		if experiment.Traffic < 0 || experiment.Traffic > 100 {
+			errs = multierror.Append(errs, fmt.Errorf("experiment %s traffic should be in [0, 100]", experiment.Key))
+		}
+		var totalWeight uint32
+		for _, variant := range experiment.Variants {
+			if variant == nil || len(variant.Name) == 0 {
+				errs = multierror.Append(errs, fmt.Errorf("experiment %s variant name is required", experiment.Key))
+				continue
+			}
+			totalWeight += variant.Weight
+		}
+		if totalWeight == 0 {
+			errs = multierror.Append(errs, errors.New("experiment "+experiment.Key+" variants weight should be positive"))
+		}
+	}
+	return errs
+}
+
+// ExperimentAssignment 实验单元的分组结果
+type ExperimentAssignment struct {
+	// 实验标识
+	ExperimentKey string
+	// 实验单元标识，例如用户ID
+	UnitID string
+	// 单元所在的桶，范围 [0, 10000)
+	Bucket uint32
+	// 分组名，未进入实验时为空
+	Variant string
+	// 未进入实验的原因，进入实验时为空
+	Reason ExperimentExcludeReason
+}
+
+// InExperiment 单元是否进入实验
+func (a *ExperimentAssignment) InExperiment() bool {
+	return len(a.Variant) > 0
+}
+
+// ExperimentExcludeReason 单元未进入实验的原因
+type ExperimentExcludeReason string
+
+const (
+	// ExperimentExcludeDisabled 实验已停用
+	ExperimentExcludeDisabled ExperimentExcludeReason = "disabled"
+	// ExperimentExcludeTargeting 不满足定向规则
+	ExperimentExcludeTargeting ExperimentExcludeReason = "targeting"
+	// ExperimentExcludeTraffic 不在实验流量内
+	ExperimentExcludeTraffic ExperimentExcludeReason = "traffic"
+)
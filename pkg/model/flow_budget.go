@@ -0,0 +1,130 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"time"
+)
+
+// FlowStep 服务发现流程中的步骤
+type FlowStep string
+
+const (
+	// FlowStepCache 等待实例及规则缓存加载
+	FlowStepCache FlowStep = "cache"
+	// FlowStepRoute 执行路由链
+	FlowStepRoute FlowStep = "route"
+	// FlowStepLoadBalance 执行负载均衡及前后置钩子
+	FlowStepLoadBalance FlowStep = "loadbalance"
+)
+
+// FlowStepCost 流程步骤的耗时
+type FlowStepCost struct {
+	Step FlowStep
+	Cost time.Duration
+}
+
+// FlowBudget 单次服务发现流程的耗时预算，缓存等待、路由链以及负载均衡共享同一个截止时间，
+// 同时记录各步骤的耗时，用于定位耗尽预算的步骤。非并发安全，只在单次流程中使用
+type FlowBudget struct {
+	// 预算总时长
+	budget time.Duration
+	// 预算截止时间
+	deadline time.Time
+	// 上一个步骤结束的时间
+	last time.Time
+	// 各步骤耗时，同一步骤多次执行时耗时累加
+	steps []FlowStepCost
+	// 耗尽预算的步骤
+	exhaustedStep FlowStep
+}
+
+// NewFlowBudget 创建流程预算，now为流程开始时间
+func NewFlowBudget(budget time.Duration, now time.Time) *FlowBudget {
+	return &FlowBudget{
+		budget:   budget,
+		deadline: now.Add(budget),
+		last:     now,
+		steps:    make([]FlowStepCost, 0, 3),
+	}
+}
+
+// Budget 预算总时长
+func (f *FlowBudget) Budget() time.Duration {
+	return f.budget
+}
+
+// Deadline 预算截止时间
+func (f *FlowBudget) Deadline() time.Time {
+	return f.deadline
+}
+
+// Remaining 剩余的预算，已耗尽时返回0
+func (f *FlowBudget) Remaining(now time.Time) time.Duration {
+	if remaining := f.deadline.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Mark 记录步骤结束，返回false表示预算已在该步骤耗尽
+func (f *FlowBudget) Mark(step FlowStep, now time.Time) bool {
+	cost := now.Sub(f.last)
+	f.last = now
+	found := false
+	for i := range f.steps {
+		if f.steps[i].Step == step {
+			f.steps[i].Cost += cost
+			found = true
+			break
+		}
+	}
+	if !found {
+		f.steps = append(f.steps, FlowStepCost{Step: step, Cost: cost})
+	}
+	if now.Before(f.deadline) {
+		return true
+	}
+	if len(f.exhaustedStep) == 0 {
+		f.exhaustedStep = step
+	}
+	return false
+}
+
+// Steps 已记录的各步骤耗时
+func (f *FlowBudget) Steps() []FlowStepCost {
+	return f.steps
+}
+
+// ExhaustedStep 耗尽预算的步骤，未耗尽时为空
+func (f *FlowBudget) ExhaustedStep() FlowStep {
+	return f.exhaustedStep
+}
+
+// FlowBudgetGauge 服务发现流程预算耗尽时上报的统计
+type FlowBudgetGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	// API 调用的接口名
+	API ApiOperation
+	// Step 耗尽预算的步骤
+	Step FlowStep
+	// Budget 流程预算
+	Budget time.Duration
+}
@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFlowBudget 测试流程预算的步骤耗时累加及耗尽步骤记录
+func TestFlowBudget(t *testing.T) {
+	start := time.Now()
+	budget := NewFlowBudget(100*time.Millisecond, start)
+	if !budget.Mark(FlowStepCache, start.Add(30*time.Millisecond)) {
+		t.Fatal("budget should not be exhausted at cache step")
+	}
+	if !budget.Mark(FlowStepRoute, start.Add(50*time.Millisecond)) {
+		t.Fatal("budget should not be exhausted at route step")
+	}
+	if !budget.Mark(FlowStepCache, start.Add(60*time.Millisecond)) {
+		t.Fatal("budget should not be exhausted at redirected cache step")
+	}
+	if remaining := budget.Remaining(start.Add(60 * time.Millisecond)); remaining != 40*time.Millisecond {
+		t.Fatalf("expect remaining 40ms, got %v", remaining)
+	}
+	if budget.Mark(FlowStepLoadBalance, start.Add(120*time.Millisecond)) {
+		t.Fatal("budget should be exhausted at loadbalance step")
+	}
+	if budget.ExhaustedStep() != FlowStepLoadBalance {
+		t.Fatalf("expect exhausted at loadbalance, got %s", budget.ExhaustedStep())
+	}
+	expects := []FlowStepCost{
+		{Step: FlowStepCache, Cost: 40 * time.Millisecond},
+		{Step: FlowStepRoute, Cost: 20 * time.Millisecond},
+		{Step: FlowStepLoadBalance, Cost: 60 * time.Millisecond},
+	}
+	steps := budget.Steps()
+	if len(steps) != len(expects) {
+		t.Fatalf("expect %d steps, got %v", len(expects), steps)
+	}
+	for i, expect := range expects {
+		if steps[i] != expect {
+			t.Fatalf("expect step %v, got %v", expect, steps[i])
+		}
+	}
+}
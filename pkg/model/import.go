@@ -0,0 +1,142 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultImportSyncLabelKey 外部导入实例默认使用的同步标签key
+const DefaultImportSyncLabelKey = "polaris.sync.label"
+
+// InstanceSpec 外部系统导入的实例描述
+type InstanceSpec struct {
+	// 必选，实例的域名/IP
+	Host string
+	// 必选，实例的端口
+	Port int
+	// 可选，实例的协议
+	Protocol string
+	// 可选，实例的版本
+	Version string
+	// 可选，实例的权重，默认100
+	Weight *int
+	// 可选，实例的元数据
+	Metadata map[string]string
+}
+
+// Endpoint 实例的地址
+func (s InstanceSpec) Endpoint() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// GetWeight 获取实例权重，未设置时返回默认权重
+func (s InstanceSpec) GetWeight() int {
+	if s.Weight == nil {
+		return 100
+	}
+	return *s.Weight
+}
+
+// ImportInstancesRequest 外部实例批量导入请求，对同步标签下的实例进行全量对账：
+// 注册新增及变更的实例，反注册不在导入列表中的实例，不带同步标签的实例不受影响
+type ImportInstancesRequest struct {
+	// 必选，命名空间
+	Namespace string
+	// 必选，服务名
+	Service string
+	// 可选，服务访问Token
+	ServiceToken string
+	// 可选，同步标签的key，默认为 polaris.sync.label
+	SyncLabelKey string
+	// 必选，同步标签的值，用于标识实例的导入来源，例如 VIP 池或者云负载均衡的名称
+	SyncLabel string
+	// 期望的实例全集，为空时会移除该同步标签下的所有实例
+	Instances []InstanceSpec
+	// 可选，仅计算对账结果，不进行实际的注册与反注册
+	DryRun bool
+	// 可选，单次注册/反注册的超时时间，默认直接获取全局的超时配置
+	Timeout *time.Duration
+	// 可选，单次注册/反注册的重试次数，默认直接获取全局的超时配置
+	RetryCount *int
+}
+
+// GetSyncLabelKey 获取同步标签的key
+func (r *ImportInstancesRequest) GetSyncLabelKey() string {
+	if len(r.SyncLabelKey) == 0 {
+		return DefaultImportSyncLabelKey
+	}
+	return r.SyncLabelKey
+}
+
+// Validate 校验导入请求
+func (r *ImportInstancesRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ImportInstancesRequest can not be nil")
+	}
+	var errs error
+	if len(r.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: namespace should not be empty"))
+	}
+	if len(r.Service) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: service should not be empty"))
+	}
+	if len(r.SyncLabel) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: syncLabel should not be empty"))
+	}
+	endpoints := make(map[string]struct{}, len(r.Instances))
+	for _, spec := range r.Instances {
+		if len(spec.Host) == 0 || spec.Port <= 0 || spec.Port >= 65536 {
+			errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: invalid instance %s", spec.Endpoint()))
+			continue
+		}
+		if spec.Weight != nil && (*spec.Weight < MinWeight || *spec.Weight > MaxWeight) {
+			errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: weight of %s should be in range [%d, %d]",
+				spec.Endpoint(), MinWeight, MaxWeight))
+		}
+		if _, ok := endpoints[spec.Endpoint()]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("ImportInstancesRequest: duplicate instance %s", spec.Endpoint()))
+		}
+		endpoints[spec.Endpoint()] = struct{}{}
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate ImportInstancesRequest: ")
+	}
+	return nil
+}
+
+// ImportInstancesResponse 外部实例批量导入的对账结果
+type ImportInstancesResponse struct {
+	// 是否为演练模式，演练模式下仅返回对账结果
+	DryRun bool
+	// 新增的实例
+	Added []InstanceSpec
+	// 变更的实例
+	Updated []InstanceSpec
+	// 移除的实例
+	Removed []InstanceSpec
+	// 未变化的实例数
+	Unchanged int
+	// 处理失败的实例，key为实例地址
+	Failed map[string]error
+}
@@ -0,0 +1,46 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "testing"
+
+// TestImportInstancesRequestValidate 测试导入请求需要同步标签，且实例地址合法、不重复
+func TestImportInstancesRequestValidate(t *testing.T) {
+	req := &ImportInstancesRequest{Namespace: "Test", Service: "svc", SyncLabel: "vip1",
+		Instances: []InstanceSpec{{Host: "10.0.0.1", Port: 80}, {Host: "10.0.0.2", Port: 80}}}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if key := req.GetSyncLabelKey(); key != DefaultImportSyncLabelKey {
+		t.Fatalf("expect default sync label key, got %s", key)
+	}
+	weight := MaxWeight + 1
+	for _, invalid := range []*ImportInstancesRequest{
+		nil,
+		{Namespace: "Test", Service: "svc"},
+		{Namespace: "Test", Service: "svc", SyncLabel: "vip1", Instances: []InstanceSpec{{Host: "10.0.0.1"}}},
+		{Namespace: "Test", Service: "svc", SyncLabel: "vip1",
+			Instances: []InstanceSpec{{Host: "10.0.0.1", Port: 80, Weight: &weight}}},
+		{Namespace: "Test", Service: "svc", SyncLabel: "vip1",
+			Instances: []InstanceSpec{{Host: "10.0.0.1", Port: 80}, {Host: "10.0.0.1", Port: 80}}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expect invalid request %+v", invalid)
+		}
+	}
+}
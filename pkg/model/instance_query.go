@@ -0,0 +1,141 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+const (
+	// DefaultQueryInstancesPageSize 实例查询默认的分页大小
+	DefaultQueryInstancesPageSize = 100
+	// MaxQueryInstancesPageSize 实例查询最大的分页大小
+	MaxQueryInstancesPageSize = 1000
+)
+
+// QueryInstancesRequest 直接向服务端查询实例的请求，不经过本地缓存，也不会订阅服务，适用于巡检及盘点等一次性查询
+type QueryInstancesRequest struct {
+	// 必选，服务名
+	Service string
+	// 必选，命名空间
+	Namespace string
+	// 可选，实例元数据过滤条件，实例需匹配全部的键值对
+	Metadata map[string]string
+	// 可选，只查询健康的实例
+	HealthyOnly bool
+	// 可选，实例地域过滤条件，只比较非空的字段
+	Location Location
+	// 可选，分页大小，默认为DefaultQueryInstancesPageSize
+	PageSize int
+	// 可选，分页标识，取上一页应答的NextPageToken，为空时查询第一页
+	PageToken string
+}
+
+// GetService 获取服务名
+func (q *QueryInstancesRequest) GetService() string {
+	return q.Service
+}
+
+// GetNamespace 获取命名空间
+func (q *QueryInstancesRequest) GetNamespace() string {
+	return q.Namespace
+}
+
+// GetMetadata 获取元数据过滤条件
+func (q *QueryInstancesRequest) GetMetadata() map[string]string {
+	return q.Metadata
+}
+
+// GetPageSize 获取分页大小
+func (q *QueryInstancesRequest) GetPageSize() int {
+	if q.PageSize <= 0 {
+		return DefaultQueryInstancesPageSize
+	}
+	return q.PageSize
+}
+
+// Match 实例是否满足过滤条件
+func (q *QueryInstancesRequest) Match(instance Instance) bool {
+	if q.HealthyOnly && (!instance.IsHealthy() || instance.IsIsolated()) {
+		return false
+	}
+	if !matchLocation(instance, q.Location) {
+		return false
+	}
+	metadata := instance.GetMetadata()
+	for k, v := range q.Metadata {
+		if value, ok := metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate 校验实例查询请求
+func (q *QueryInstancesRequest) Validate() error {
+	if nil == q {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "QueryInstancesRequest can not be nil")
+	}
+	if err := validateServiceMetadata("QueryInstancesRequest", q); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "fail to validate QueryInstancesRequest")
+	}
+	if q.PageSize > MaxQueryInstancesPageSize {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"QueryInstancesRequest: pageSize %d exceeds %d", q.PageSize, MaxQueryInstancesPageSize)
+	}
+	if _, err := DecodePageToken(q.PageToken); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "QueryInstancesRequest: invalid pageToken")
+	}
+	return nil
+}
+
+// QueryInstancesResponse 实例查询的应答
+type QueryInstancesResponse struct {
+	ServiceInfo
+	// 查询时服务端的实例版本号
+	Revision string
+	// 服务是否存在
+	NotExists bool
+	// 当前页的实例，按实例ID排序
+	Instances []Instance
+	// 满足过滤条件的实例总数
+	TotalCount int
+	// 下一页的分页标识，为空代表已经是最后一页
+	NextPageToken string
+}
+
+// EncodePageToken 将当前页最后一个实例的ID编码为分页标识，服务端实例变化时分页仍然保持有序且不重复
+func EncodePageToken(lastInstanceID string) string {
+	if len(lastInstanceID) == 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(lastInstanceID))
+}
+
+// DecodePageToken 解析分页标识，返回上一页最后一个实例的ID
+func DecodePageToken(token string) (string, error) {
+	if len(token) == 0 {
+		return "", nil
+	}
+	value, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("malformed page token %s: %v", token, err)
+	}
+	return string(value), nil
+}
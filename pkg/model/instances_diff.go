@@ -0,0 +1,41 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// InstancesDiffResponse 服务实例增量变更结果
+type InstancesDiffResponse struct {
+	// 服务标识
+	ServiceKey
+	// 查询的起始版本号
+	SinceRevision string
+	// 当前的版本号，下次查询时作为起始版本号传入
+	Revision string
+	// 是否为全量结果，起始版本号为空或者已经过期时，返回全量实例作为新增实例
+	FullSync bool
+	// 新增的实例
+	Added []Instance
+	// 删除的实例
+	Removed []Instance
+	// 属性发生变更的实例，返回变更后的实例
+	Changed []Instance
+}
+
+// IsEmpty 是否没有任何变更
+func (r *InstancesDiffResponse) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
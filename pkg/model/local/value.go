@@ -54,6 +54,23 @@ type DefaultInstanceLocalValue struct {
 	extendedData *sync.Map
 	cbStatus     atomic.Value
 	odStatus     atomic.Value
+	// 平滑后的健康状态
+	smoothing healthSmoothing
+}
+
+const (
+	smoothedUnknown int32 = iota
+	smoothedHealthy
+	smoothedUnhealthy
+)
+
+// healthSmoothing 实例健康状态平滑记录
+type healthSmoothing struct {
+	mutex sync.Mutex
+	// 当前生效的健康状态
+	effective int32
+	// 连续观察到与生效状态不同的次数
+	pending int
 }
 
 // GetSliceWindows 获取滑窗
@@ -102,6 +119,46 @@ func (lv *DefaultInstanceLocalValue) GetCircuitBreakerStatus() model.CircuitBrea
 	return nil
 }
 
+// ObserveHealth 记录一次服务端下发的健康状态，连续threshold次观察到相同的新状态后才切换生效状态，
+// 返回生效状态是否发生了切换
+func (lv *DefaultInstanceLocalValue) ObserveHealth(healthy bool, threshold int) bool {
+	observed := smoothedUnhealthy
+	if healthy {
+		observed = smoothedHealthy
+	}
+	s := &lv.smoothing
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	effective := atomic.LoadInt32(&s.effective)
+	if effective == smoothedUnknown {
+		atomic.StoreInt32(&s.effective, observed)
+		return false
+	}
+	if effective == observed {
+		s.pending = 0
+		return false
+	}
+	s.pending++
+	if s.pending < threshold {
+		return false
+	}
+	s.pending = 0
+	atomic.StoreInt32(&s.effective, observed)
+	return true
+}
+
+// GetSmoothedHealth 返回平滑后的健康状态，未观察过健康状态时ok为false
+func (lv *DefaultInstanceLocalValue) GetSmoothedHealth() (healthy bool, ok bool) {
+	switch atomic.LoadInt32(&lv.smoothing.effective) {
+	case smoothedHealthy:
+		return true, true
+	case smoothedUnhealthy:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 // GetActiveDetectStatus 返回健康检测信息
 func (lv *DefaultInstanceLocalValue) GetActiveDetectStatus() model.ActiveDetectStatus {
 	res := lv.odStatus.Load()
@@ -154,6 +154,32 @@ func (s *ServiceInstancesInProto) ReloadServiceClusters() {
 	s.clusterCache.Store(clusterCache)
 }
 
+// healthSmoother 支持健康状态平滑的实例本地信息.
+type healthSmoother interface {
+	ObserveHealth(healthy bool, threshold int) bool
+	GetSmoothedHealth() (healthy bool, ok bool)
+}
+
+// ObserveHealth 将服务端下发的实例健康状态记录到平滑器中，生效状态发生切换时重建缓存索引，
+// 返回是否有实例的生效状态发生切换.
+func (s *ServiceInstancesInProto) ObserveHealth(threshold int) bool {
+	var changed bool
+	for _, inst := range s.instances {
+		instInProto := inst.(*InstanceInProto)
+		smoother, ok := instInProto.localValue.(healthSmoother)
+		if !ok {
+			continue
+		}
+		if smoother.ObserveHealth(instInProto.IsRawHealthy(), threshold) {
+			changed = true
+		}
+	}
+	if changed {
+		s.ReloadServiceClusters()
+	}
+	return changed
+}
+
 // GetServiceClusters 获取缓存索引.
 func (s *ServiceInstancesInProto) GetServiceClusters() model.ServiceClusters {
 	return s.clusterCache.Load().(model.ServiceClusters)
@@ -282,6 +308,10 @@ type InstanceInProto struct {
 	localValue local.InstanceLocalValue
 	// 保存单个实例的数组引用
 	singleInstances []model.Instance
+	// 从元数据解析出的拓扑信息，构造时解析一次，避免在路由过程中反复查找元数据
+	topologyParsed bool
+	nodeName       string
+	rack           string
 }
 
 // NewInstanceInProto InstanceInProto的构造函数.
@@ -301,9 +331,18 @@ func NewInstanceInProto(
 		Port: int(instance.GetPort().GetValue()),
 	}
 	instInProto.singleInstances = []model.Instance{instInProto}
+	instInProto.parseTopology()
 	return instInProto
 }
 
+// parseTopology 从元数据解析实例的拓扑信息
+func (i *InstanceInProto) parseTopology() {
+	metadata := i.GetMetadata()
+	i.nodeName = metadata[model.TopologyNodeMetadata]
+	i.rack = metadata[model.TopologyRackMetadata]
+	i.topologyParsed = true
+}
+
 // GetNamespace 命名空间.
 func (i *InstanceInProto) GetNamespace() string {
 	return i.instanceKey.Namespace
@@ -384,8 +423,18 @@ func (i *InstanceInProto) GetActiveDetectStatus() model.ActiveDetectStatus {
 	return i.localValue.GetActiveDetectStatus()
 }
 
-// IsHealthy instance health status.
+// IsHealthy instance health status, smoothed when health smoothing is enabled.
 func (i *InstanceInProto) IsHealthy() bool {
+	if smoother, ok := i.localValue.(healthSmoother); ok {
+		if healthy, observed := smoother.GetSmoothedHealth(); observed {
+			return healthy
+		}
+	}
+	return i.GetHealthy().GetValue()
+}
+
+// IsRawHealthy instance health status pushed by server, without smoothing.
+func (i *InstanceInProto) IsRawHealthy() bool {
 	return i.GetHealthy().GetValue()
 }
 
@@ -424,6 +473,22 @@ func (i *InstanceInProto) GetCampus() string {
 	return i.GetLocation().GetCampus().GetValue()
 }
 
+// GetNodeName instance node name.
+func (i *InstanceInProto) GetNodeName() string {
+	if !i.topologyParsed {
+		return i.GetMetadata()[model.TopologyNodeMetadata]
+	}
+	return i.nodeName
+}
+
+// GetRack instance rack.
+func (i *InstanceInProto) GetRack() string {
+	if !i.topologyParsed {
+		return i.GetMetadata()[model.TopologyRackMetadata]
+	}
+	return i.rack
+}
+
 // GetInstanceKey 获取实例的四元组标识.
 func (i *InstanceInProto) GetInstanceKey() model.InstanceKey {
 	return *i.instanceKey
@@ -496,6 +561,7 @@ func (i *InstanceInProto) DeepClone() model.Instance {
 		localValue: i.GetInstanceLocalValue(),
 	}
 	copyIns.singleInstances = []model.Instance{copyIns}
+	copyIns.parseTopology()
 	return copyIns
 }
 
@@ -0,0 +1,107 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+)
+
+func newSmoothingResponse(revision string, healthy bool) *apiservice.DiscoverResponse {
+	return &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String("Test"),
+			Name:      wrapperspb.String("echo"),
+			Revision:  wrapperspb.String(revision),
+		},
+		Instances: []*apiservice.Instance{{
+			Id:      wrapperspb.String("inst-1"),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(8080),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(healthy),
+		}},
+	}
+}
+
+// TestServiceInstancesObserveHealth 测试健康状态需连续多次观察到才生效，原始状态保持可见
+func TestServiceInstancesObserveHealth(t *testing.T) {
+	localValue := local.NewInstanceLocalValue()
+	createLocalValue := func(string) local.InstanceLocalValue {
+		return localValue
+	}
+	const threshold = 3
+	instances := NewServiceInstancesInProto(newSmoothingResponse("v1", true), createLocalValue, nil, nil)
+	instances.ObserveHealth(threshold)
+	if !instances.GetInstances()[0].IsHealthy() {
+		t.Fatal("expect instance healthy at first observation")
+	}
+
+	instances = NewServiceInstancesInProto(newSmoothingResponse("v2", false), createLocalValue, nil, nil)
+	for i := 1; i < threshold; i++ {
+		if instances.ObserveHealth(threshold) {
+			t.Fatalf("expect health not switched after %d observations", i)
+		}
+		inst := instances.GetInstances()[0].(*InstanceInProto)
+		if !inst.IsHealthy() || inst.IsRawHealthy() {
+			t.Fatal("expect smoothed healthy and raw unhealthy")
+		}
+	}
+	if !instances.ObserveHealth(threshold) {
+		t.Fatal("expect health switched after consecutive observations")
+	}
+	if instances.GetInstances()[0].IsHealthy() {
+		t.Fatal("expect instance unhealthy after smoothing")
+	}
+
+	// 状态恢复时中断的观察不累计
+	instances = NewServiceInstancesInProto(newSmoothingResponse("v3", true), createLocalValue, nil, nil)
+	instances.ObserveHealth(threshold)
+	instances = NewServiceInstancesInProto(newSmoothingResponse("v4", false), createLocalValue, nil, nil)
+	instances.ObserveHealth(threshold)
+	instances = NewServiceInstancesInProto(newSmoothingResponse("v5", true), createLocalValue, nil, nil)
+	instances.ObserveHealth(threshold)
+	if instances.GetInstances()[0].IsHealthy() {
+		t.Fatal("expect interrupted observations not accumulated")
+	}
+}
+
+// TestInstanceTopology 测试从元数据中解析实例的节点和机架信息
+func TestInstanceTopology(t *testing.T) {
+	loc := &model.Location{Region: "south", Zone: "sz", Campus: "sz-1", Rack: "rack-1"}
+	metadata := model.WithTopologyMetadata(map[string]string{model.TopologyNodeMetadata: "node-1"}, loc)
+	instance := NewInstanceInProto(&apiservice.Instance{
+		Host:     wrapperspb.String("127.0.0.1"),
+		Port:     wrapperspb.UInt32(8080),
+		Metadata: metadata,
+	}, &model.ServiceKey{Namespace: "Test", Service: "echo"}, local.NewInstanceLocalValue())
+	if instance.GetNodeName() != "node-1" {
+		t.Fatalf("expect node name node-1, got %s", instance.GetNodeName())
+	}
+	if instance.GetRack() != "rack-1" {
+		t.Fatalf("expect rack rack-1, got %s", instance.GetRack())
+	}
+	if cloned := instance.DeepClone(); cloned.GetRack() != "rack-1" {
+		t.Fatalf("expect cloned rack rack-1, got %s", cloned.GetRack())
+	}
+}
@@ -19,12 +19,16 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
 )
 
+// ErrQuotaLimited 请求被限流，可通过 errors.Is 判断.
+var ErrQuotaLimited = errors.New("quota limited")
+
 // QuotaRequestImpl 配额获取的请求.
 type QuotaRequestImpl struct {
 	// 必选，命名空间
@@ -164,6 +168,10 @@ type QuotaResponse struct {
 	Info string
 	// 需要等待的时间段
 	WaitMs int64
+	// 排队时位于当前请求前面的请求数
+	QueueDepth int
+	// 请求匹配的限流豁免名称，非空表示请求绕过了限流
+	Exemption string
 }
 
 // QuotaFutureImpl 异步获取配额的future.
@@ -195,6 +203,24 @@ func (q *QuotaFutureImpl) GetImmediately() *QuotaResponse {
 	return q.resp
 }
 
+// GetExpectedWait 获取剩余的预期排队等待时间.
+func (q *QuotaFutureImpl) GetExpectedWait() time.Duration {
+	if nil == q.deadlineCtx {
+		return 0
+	}
+	deadline, _ := q.deadlineCtx.Deadline()
+	wait := time.Until(deadline)
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// GetQueueDepth 获取分配配额时排在当前请求前面的请求数.
+func (q *QuotaFutureImpl) GetQueueDepth() int {
+	return q.resp.QueueDepth
+}
+
 // Get 获取分配结果.
 func (q *QuotaFutureImpl) Get() *QuotaResponse {
 	if nil != q.deadlineCtx {
@@ -0,0 +1,45 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestQuotaFutureExpectedWait 测试排队的配额结果暴露剩余等待时间和排队深度，等待结束后剩余时间归零
+func TestQuotaFutureExpectedWait(t *testing.T) {
+	future := QuotaFutureWithResponse(&QuotaResponse{Code: QuotaResultOk, WaitMs: 50, QueueDepth: 3})
+	if wait := future.GetExpectedWait(); wait <= 0 || wait > 50*time.Millisecond {
+		t.Fatalf("expect expected wait in (0, 50ms], got %v", wait)
+	}
+	if depth := future.GetQueueDepth(); depth != 3 {
+		t.Fatalf("expect queue depth 3, got %d", depth)
+	}
+	if resp := future.Get(); resp.Code != QuotaResultOk {
+		t.Fatalf("expect quota granted, got %+v", resp)
+	}
+	if wait := future.GetExpectedWait(); wait != 0 {
+		t.Fatalf("expect no wait after Get returned, got %v", wait)
+	}
+
+	future = QuotaFutureWithResponse(&QuotaResponse{Code: QuotaResultOk})
+	if wait := future.GetExpectedWait(); wait != 0 || future.GetQueueDepth() != 0 {
+		t.Fatalf("expect no wait and empty queue, got %v, %d", wait, future.GetQueueDepth())
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// DefaultPeersCheckInterval 默认的健康实例数检查间隔
+const DefaultPeersCheckInterval = time.Second
+
+// WaitForPeersRequest 等待服务的健康实例数达到下限的请求，用于依赖法定人数的服务在就绪前进行检查
+type WaitForPeersRequest struct {
+	// 必选，等待的服务，可以是自身服务也可以是依赖的服务
+	ServiceKey
+	// 必选，最小健康实例数
+	MinPeers int
+	// 可选，检查间隔，默认1s
+	CheckInterval time.Duration
+	// 可选，进度监听器，健康实例数发生变化时回调
+	PeersListener PeersListener
+}
+
+// Validate 校验请求
+func (req *WaitForPeersRequest) Validate() error {
+	if nil == req {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "WaitForPeersRequest can not be nil")
+	}
+	var errs error
+	if len(req.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("namespace is empty"))
+	}
+	if len(req.Service) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("service is empty"))
+	}
+	if req.MinPeers <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("minPeers should be greater than zero"))
+	}
+	if req.CheckInterval < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("checkInterval should not be negative"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate WaitForPeersRequest: ")
+	}
+	return nil
+}
+
+// GetCheckInterval 获取检查间隔
+func (req *WaitForPeersRequest) GetCheckInterval() time.Duration {
+	if req.CheckInterval == 0 {
+		return DefaultPeersCheckInterval
+	}
+	return req.CheckInterval
+}
+
+// PeersProgress 健康实例数的等待进度
+type PeersProgress struct {
+	ServiceKey
+	// 当前健康且未隔离的实例数
+	HealthyPeers int
+	// 最小健康实例数
+	MinPeers int
+	// 是否已经就绪
+	Ready bool
+}
+
+// PeersListener 健康实例数等待进度监听器
+type PeersListener interface {
+	// OnPeersProgress 健康实例数发生变化时回调
+	OnPeersProgress(*PeersProgress)
+}
@@ -0,0 +1,44 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWaitForPeersRequestValidate 测试等待健康实例数请求的校验及默认检查间隔
+func TestWaitForPeersRequestValidate(t *testing.T) {
+	req := &WaitForPeersRequest{ServiceKey: ServiceKey{Namespace: "Test", Service: "quorum"}, MinPeers: 3}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if interval := req.GetCheckInterval(); interval != DefaultPeersCheckInterval {
+		t.Fatalf("expect default check interval, got %v", interval)
+	}
+	for _, invalid := range []*WaitForPeersRequest{
+		nil,
+		{ServiceKey: ServiceKey{Namespace: "Test"}, MinPeers: 3},
+		{ServiceKey: ServiceKey{Namespace: "Test", Service: "quorum"}},
+		{ServiceKey: ServiceKey{Namespace: "Test", Service: "quorum"}, MinPeers: 3, CheckInterval: -time.Second},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expect invalid request %+v", invalid)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRequestBudgetExhausted 单次逻辑请求的尝试预算已耗尽，可通过 errors.Is 判断
+var ErrRequestBudgetExhausted = errors.New("request budget exhausted")
+
+// RequestBudget 单次逻辑请求的尝试预算，在重试、对冲以及熔断之间共享，
+// 保证所有机制发起的尝试总数及总耗时不超过同一份预算
+type RequestBudget struct {
+	// 最多发起的尝试次数
+	maxAttempts int32
+	// 已发起的尝试次数
+	attempts int32
+	// 预算创建时间
+	start time.Time
+	// 预算截止时间，为零值时不限制总耗时
+	deadline time.Time
+}
+
+// NewRequestBudget 创建尝试预算，maxDuration为0时不限制总耗时
+func NewRequestBudget(maxAttempts int, maxDuration time.Duration) *RequestBudget {
+	budget := &RequestBudget{
+		maxAttempts: int32(maxAttempts),
+		start:       time.Now(),
+	}
+	if maxDuration > 0 {
+		budget.deadline = budget.start.Add(maxDuration)
+	}
+	return budget
+}
+
+// TryAcquire 申请发起一次尝试，预算耗尽时返回false
+func (b *RequestBudget) TryAcquire() bool {
+	if !b.deadline.IsZero() && !time.Now().Before(b.deadline) {
+		return false
+	}
+	for {
+		attempts := atomic.LoadInt32(&b.attempts)
+		if attempts >= b.maxAttempts {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&b.attempts, attempts, attempts+1) {
+			return true
+		}
+	}
+}
+
+// Release 归还一次申请到但未实际发出的尝试，例如被熔断拦截的请求
+func (b *RequestBudget) Release() {
+	atomic.AddInt32(&b.attempts, -1)
+}
+
+// Attempts 已发起的尝试次数
+func (b *RequestBudget) Attempts() int {
+	return int(atomic.LoadInt32(&b.attempts))
+}
+
+// MaxAttempts 最多发起的尝试次数
+func (b *RequestBudget) MaxAttempts() int {
+	return int(b.maxAttempts)
+}
+
+// Deadline 预算截止时间，ok为false时不限制总耗时
+func (b *RequestBudget) Deadline() (deadline time.Time, ok bool) {
+	return b.deadline, !b.deadline.IsZero()
+}
+
+// Elapsed 预算创建以来的耗时
+func (b *RequestBudget) Elapsed() time.Duration {
+	return time.Since(b.start)
+}
+
+// Exhausted 预算是否已经耗尽
+func (b *RequestBudget) Exhausted() bool {
+	if !b.deadline.IsZero() && !time.Now().Before(b.deadline) {
+		return true
+	}
+	return atomic.LoadInt32(&b.attempts) >= b.maxAttempts
+}
+
+// RequestAttemptsGauge 单次逻辑请求结束后上报的尝试次数统计
+type RequestAttemptsGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	Method    string
+	// Attempts 本次逻辑请求发起的尝试总数
+	Attempts int
+	// Success 逻辑请求是否成功
+	Success bool
+	// Exhausted 逻辑请求是否因预算耗尽而结束
+	Exhausted bool
+}
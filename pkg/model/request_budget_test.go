@@ -0,0 +1,50 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRequestBudget 测试尝试次数及总耗时预算
+func TestRequestBudget(t *testing.T) {
+	budget := NewRequestBudget(2, 0)
+	if !budget.TryAcquire() || !budget.TryAcquire() {
+		t.Fatal("expect attempts acquired within budget")
+	}
+	if budget.TryAcquire() || !budget.Exhausted() {
+		t.Fatal("expect budget exhausted after max attempts")
+	}
+	budget.Release()
+	if budget.Attempts() != 1 || !budget.TryAcquire() {
+		t.Fatal("expect released attempt reusable")
+	}
+	if _, ok := budget.Deadline(); ok {
+		t.Fatal("expect no deadline when max duration is 0")
+	}
+
+	budget = NewRequestBudget(10, 10*time.Millisecond)
+	if !budget.TryAcquire() {
+		t.Fatal("expect attempt acquired before deadline")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if budget.TryAcquire() || !budget.Exhausted() {
+		t.Fatal("expect budget exhausted after deadline")
+	}
+}
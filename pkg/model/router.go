@@ -123,3 +123,59 @@ func (p *ProcessLoadBalanceRequest) Validate() error {
 func (p *ProcessLoadBalanceRequest) GetResponse() *InstancesResponse {
 	return &p.response
 }
+
+const (
+	// DefaultTrafficSplitTTL default expiry of a local traffic split
+	DefaultTrafficSplitTTL = 5 * time.Minute
+	// MaxTrafficSplitTTL max expiry of a local traffic split, the controller must refresh it periodically
+	MaxTrafficSplitTTL = 24 * time.Hour
+	// DefaultTrafficSplitMetadataKey default instance metadata key of the version
+	DefaultTrafficSplitMetadataKey = "version"
+)
+
+// TrafficSplitRequest the input request parameters for RouterAPI.SetSplit
+type TrafficSplitRequest struct {
+	// Namespace destination service namespace, required.
+	Namespace string
+	// Service destination service name, required.
+	Service string
+	// Split percent of traffic for each version, required.
+	// Each percent must be in [0, 100] and the total must be 100.
+	Split map[string]uint32
+	// MetadataKey instance metadata key of the version, optional, default is "version".
+	MetadataKey string
+	// TTL the split expires after TTL and traffic falls back to the server rules, optional.
+	// Default is 5m, max is 24h.
+	TTL time.Duration
+}
+
+// Validate validate the request object
+func (r *TrafficSplitRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "TrafficSplitRequest can not be nil")
+	}
+	if len(r.Namespace) == 0 || len(r.Service) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"TrafficSplitRequest.Namespace and TrafficSplitRequest.Service can not be empty")
+	}
+	if len(r.Split) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "TrafficSplitRequest.Split can not be empty")
+	}
+	var total uint32
+	for version, percent := range r.Split {
+		if percent > 100 {
+			return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+				"TrafficSplitRequest.Split percent of version %s should be in [0, 100], got %d", version, percent)
+		}
+		total += percent
+	}
+	if total != 100 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"TrafficSplitRequest.Split total percent should be 100, got %d", total)
+	}
+	if r.TTL < 0 || r.TTL > MaxTrafficSplitTTL {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"TrafficSplitRequest.TTL should be in [0, %v], got %v", MaxTrafficSplitTTL, r.TTL)
+	}
+	return nil
+}
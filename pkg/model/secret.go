@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+)
+
+// SecretFiles 证书在配置中心中的文件位置，证书及私钥文件必须设置，CA文件可选
+type SecretFiles struct {
+	Namespace string
+	FileGroup string
+	// CertFile PEM格式的证书文件名，可以包含证书链
+	CertFile string
+	// KeyFile PEM格式的私钥文件名
+	KeyFile string
+	// CAFile PEM格式的CA证书文件名，为空时不下发CA
+	CAFile string
+}
+
+// TLSSecret 通过校验的证书及私钥
+type TLSSecret struct {
+	// Certificate 证书及私钥，可直接用于 tls.Config
+	Certificate tls.Certificate
+	// Leaf 证书链中的叶子证书
+	Leaf *x509.Certificate
+	// CAPool CA证书池，未设置CA文件时为空
+	CAPool *x509.CertPool
+	// NotAfter 证书的过期时间
+	NotAfter time.Time
+}
+
+// TLSReloader 证书变更回调，返回错误时只记录日志，不影响其他回调
+type TLSReloader func(secret *TLSSecret) error
@@ -18,7 +18,9 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -124,6 +126,10 @@ type Instance interface {
 	GetIDC() string
 	// GetCampus 实例所属的园区信息
 	GetCampus() string
+	// GetNodeName 实例所在的节点名称，来自注册时的 TopologyNodeMetadata 元数据
+	GetNodeName() string
+	// GetRack 实例所在的机架，来自注册时的 TopologyRackMetadata 元数据
+	GetRack() string
 	// GetRevision .获取实例的修订版本信息
 	// 与上一次比较，用于确认服务实例是否发生变更
 	GetRevision() string
@@ -191,6 +197,8 @@ type GetOneInstanceRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，整个获取流程（缓存等待、路由及负载均衡）的耗时预算，默认取全局的flowBudget配置，0表示不限制
+	FlowBudget *time.Duration
 	// 可选，备份节点数
 	// 对于一致性hash等有状态的负载均衡方式
 	ReplicateCount int
@@ -202,8 +210,16 @@ type GetOneInstanceRequest struct {
 	Canary string
 	// 可选，是否包含被熔断的服务实例，默认false
 	IncludeCircuitBreakInstances bool
+	// 可选，调试用的强制路由地址，格式为 host:port，通常由网关从可信的调试请求头 ForceRouteHeader 中填充，
+	// 实例存在于缓存中时跳过路由及负载均衡直接返回该实例，不存在时按正常流程选择实例
+	ForceHostPort string
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
 }
 
+// ForceRouteHeader 网关透传强制路由地址使用的调试请求头，仅应从可信来源接受
+const ForceRouteHeader = "X-Polaris-Force-Route"
+
 // SetTimeout 设置超时时间
 func (g *GetOneInstanceRequest) SetTimeout(duration time.Duration) {
 	g.Timeout = ToDurationPtr(duration)
@@ -214,6 +230,11 @@ func (g *GetOneInstanceRequest) SetRetryCount(retryCount int) {
 	g.RetryCount = &retryCount
 }
 
+// SetFlowBudget 设置整个获取流程的耗时预算
+func (g *GetOneInstanceRequest) SetFlowBudget(budget time.Duration) {
+	g.FlowBudget = ToDurationPtr(budget)
+}
+
 // GetService 获取服务名
 func (g *GetOneInstanceRequest) GetService() string {
 	return g.Service
@@ -271,6 +292,12 @@ func (g *GetOneInstanceRequest) Validate() error {
 		return NewSDKError(ErrCodeAPIInvalidArgument, err,
 			"fail to validate GetInstancesRequest")
 	}
+	if len(g.ForceHostPort) > 0 {
+		if _, _, err := net.SplitHostPort(g.ForceHostPort); err != nil {
+			return NewSDKError(ErrCodeAPIInvalidArgument, err,
+				"invalid ForceHostPort %s in GetOneInstanceRequest", g.ForceHostPort)
+		}
+	}
 	return nil
 }
 
@@ -289,6 +316,8 @@ type GetAllInstancesRequest struct {
 	RetryCount *int
 	// 应答，无需用户填充，由主流程进行填充
 	response InstancesResponse
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
 }
 
 // SetTimeout 设置超时时间
@@ -374,6 +403,11 @@ type GetInstancesRequest struct {
 	response InstancesResponse
 	// 金丝雀
 	Canary string
+	// 可选，可接受的缓存最大陈旧时间，缓存超过该时间未经服务端确认时，同步等待下一次服务端应答后再返回，
+	// 缓存足够新鲜时不与服务端交互，默认0表示直接使用缓存
+	MaxStaleness time.Duration
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
 }
 
 // SetTimeout 设置超时时间
@@ -618,6 +652,8 @@ func (i *ServiceInfo) HasService() bool {
 // OneInstanceResponse 单个服务实例
 type OneInstanceResponse struct {
 	InstancesResponse
+	// 设置了流程预算时，缓存等待、路由及负载均衡各步骤的耗时
+	FlowTrace []FlowStepCost
 }
 
 // GetInstance get the only instance
@@ -646,6 +682,8 @@ type InstancesResponse struct {
 	Cluster *Cluster
 	// 服务是否存在
 	NotExists bool
+	// 服务在请求的命名空间中不存在而回退到其他命名空间时，为实际找到服务的命名空间，否则为空
+	FallbackNamespace string
 }
 
 // GetType 获取配置类型
@@ -756,6 +794,10 @@ type ServiceCallResult struct {
 	RuleName string
 	// 可选，主调服务实例的服务信息
 	SourceService *ServiceInfo
+	// 可选，成本归属标签，为空时由SDK使用全局配置的标签填充
+	CostLabels map[string]string
+	// 可选，本次调用所属链路的 trace id，例如 OTel span 的 TraceID，开启 exemplar 时附加到时延直方图样本上
+	TraceID string
 }
 
 // RateLimitGauge Rate Limit Gauge
@@ -767,6 +809,203 @@ type RateLimitGauge struct {
 	Arguments []Argument
 	Result    QuotaResultCode
 	RuleName  string
+	// 成本归属标签，由SDK使用全局配置的标签填充
+	CostLabels map[string]string
+}
+
+// RateLimitExemptionGauge 匹配限流豁免、绕过限流的请求，不计入限流统计
+type RateLimitExemptionGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	Method    string
+	// Exemption 本地豁免的名称或者豁免规则的名称
+	Exemption string
+}
+
+// RateLimitTopKEntry 单个标签值在统计周期内的配额消耗
+type RateLimitTopKEntry struct {
+	// Labels 规则匹配到的标签值，格式与限流窗口的标签一致
+	Labels string
+	// Passed 周期内获取配额成功的次数
+	Passed int64
+	// Limited 周期内被限流的次数
+	Limited int64
+}
+
+// RateLimitTopKGauge 限流规则在统计周期内消耗配额最多的标签值，按Passed降序排列
+type RateLimitTopKGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	RuleName  string
+	// Entries 消耗配额最多的标签值
+	Entries []RateLimitTopKEntry
+	// Interval 统计周期
+	Interval time.Duration
+}
+
+// BulkheadResult 舱壁隔离的准入结果
+type BulkheadResult int
+
+const (
+	// BulkheadPassed 直接获取到并发许可
+	BulkheadPassed BulkheadResult = iota
+	// BulkheadQueued 排队等待后获取到并发许可
+	BulkheadQueued
+	// BulkheadRejected 并发已满被拒绝
+	BulkheadRejected
+)
+
+// BulkheadGauge Bulkhead Gauge，仅在舱壁饱和（排队或拒绝）时上报
+type BulkheadGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	Method    string
+	Result    BulkheadResult
+	// Concurrency 上报时的并发调用数
+	Concurrency int
+	// QueueSize 上报时的排队请求数
+	QueueSize int
+}
+
+// ErrConnectionLimited 到被调服务的连接数已达上限，可通过 errors.Is 判断
+var ErrConnectionLimited = errors.New("connection limit exceeded")
+
+// ConnectionLimitGauge 客户端到被调服务的连接数，在建立及关闭连接时上报
+type ConnectionLimitGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	// Result 建立连接时的准入结果，关闭连接时为 BulkheadPassed
+	Result BulkheadResult
+	// Connections 上报时的连接数
+	Connections int
+	// QueueSize 上报时排队等待建立连接的请求数
+	QueueSize int
+}
+
+// ServerEndpointGauge 与server的连接所绑定的地址，在连接切换时上报
+type ServerEndpointGauge struct {
+	EmptyInstanceGauge
+	// Cluster server集群类型
+	Cluster string
+	// Address server地址
+	Address string
+	// Priority 地址优先级
+	Priority int
+	// Pinned 当前是否有连接绑定在该地址上
+	Pinned bool
+}
+
+const (
+	// ConnectionRetireSwitch 定期切换server时替换连接
+	ConnectionRetireSwitch = "switch"
+	// ConnectionRetireFailback 切回高优先级server时替换连接
+	ConnectionRetireFailback = "failback"
+	// ConnectionRetireMaxAge 连接超过最大存活时间后重建
+	ConnectionRetireMaxAge = "max_age"
+	// ConnectionRetireIdle 连接空闲超时后释放
+	ConnectionRetireIdle = "idle"
+	// ConnectionRetireDown 连接故障后释放
+	ConnectionRetireDown = "down"
+)
+
+// ConnectionAgeGauge 与server的连接被替换或者释放时的存活时长
+type ConnectionAgeGauge struct {
+	EmptyInstanceGauge
+	// Cluster server集群类型
+	Cluster string
+	// Address server地址
+	Address string
+	// Reason 连接被替换或者释放的原因
+	Reason string
+	// Age 连接的存活时长
+	Age time.Duration
+}
+
+const (
+	// TrafficInbound 从server接收的流量
+	TrafficInbound = "in"
+	// TrafficOutbound 发送到server的流量
+	TrafficOutbound = "out"
+)
+
+// ServerTrafficGauge 与server的单次调用累计的流量，流式调用按上报间隔分批上报
+type ServerTrafficGauge struct {
+	EmptyInstanceGauge
+	// Method 调用的接口名
+	Method string
+	// Direction 流量方向，in或者out
+	Direction string
+	// Compression 压缩算法，为空表示未压缩
+	Compression string
+	// RawBytes 压缩前的字节数
+	RawBytes int
+	// WireBytes 实际在链路上传输的字节数
+	WireBytes int
+}
+
+// RegisterFlappingGauge 实例注册状态抖动
+type RegisterFlappingGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	Host      string
+	Port      int
+	// Changes 统计窗口内的状态变化次数
+	Changes int
+	// Backoff 本次重新注册的退避时长
+	Backoff time.Duration
+}
+
+// PluginPanicGauge 插件调用发生panic
+type PluginPanicGauge struct {
+	EmptyInstanceGauge
+	PluginType string
+	PluginName string
+	Method     string
+	// Disabled 本次panic后插件是否已被禁用
+	Disabled bool
+}
+
+// NamespaceFallbackGauge 服务在请求的命名空间中不存在，回退到其他命名空间查找
+type NamespaceFallbackGauge struct {
+	EmptyInstanceGauge
+	// Namespace 调用方请求的命名空间
+	Namespace string
+	Service   string
+	// FallbackNamespace 最后一次尝试的回退命名空间
+	FallbackNamespace string
+	// Found 是否在回退命名空间中找到服务
+	Found bool
+}
+
+// CacheMemoryGauge 本地缓存的估算内存及降载状态
+type CacheMemoryGauge struct {
+	EmptyInstanceGauge
+	// UsedBytes 本地缓存的估算内存
+	UsedBytes int64
+	// BudgetBytes 缓存内存预算
+	BudgetBytes int64
+	// Shedding 是否处于降载模式
+	Shedding bool
+	// Evicted 本次检查淘汰的服务缓存数
+	Evicted int
+}
+
+// CacheDivergenceGauge 缓存版本巡检发现本地缓存与服务端版本不一致
+type CacheDivergenceGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	// EventType 资源类型，如instance、routing
+	EventType string
+	// LocalRevision 本地缓存的版本号
+	LocalRevision string
+	// ServerRevision 服务端的版本号
+	ServerRevision string
 }
 
 // CircuitBreakGauge Circuit Break Gauge
@@ -908,6 +1147,12 @@ func (s *ServiceCallResult) SetDelay(duration time.Duration) *ServiceCallResult
 	return s
 }
 
+// SetTraceID 设置调用链路的 trace id
+func (s *ServiceCallResult) SetTraceID(traceID string) *ServiceCallResult {
+	s.TraceID = traceID
+	return s
+}
+
 // GetRetStatus 获取本地调用状态
 func (s *ServiceCallResult) GetRetStatus() RetStatus {
 	return s.RetStatus
@@ -929,6 +1174,11 @@ func (s *ServiceCallResult) GetCallerNamespace() string {
 	return ""
 }
 
+// GetCostLabels 获取成本归属标签
+func (s *ServiceCallResult) GetCostLabels() map[string]string {
+	return s.CostLabels
+}
+
 // APICallResult sdk api调用结果
 type APICallResult struct {
 	EmptyInstanceGauge
@@ -937,6 +1187,35 @@ type APICallResult struct {
 	RetStatus RetStatus
 	// 必选，调用延时
 	delay time.Duration
+	// 可选，调用涉及的服务，用于API调用审计
+	Namespace string
+	Service   string
+}
+
+// GetNamespace 获取调用涉及的服务命名空间
+func (a *APICallResult) GetNamespace() string {
+	return a.Namespace
+}
+
+// GetService 获取调用涉及的服务名
+func (a *APICallResult) GetService() string {
+	return a.Service
+}
+
+// APICallRecord 一次 SDK API 调用的审计记录
+type APICallRecord struct {
+	// Time 调用结束时间
+	Time time.Time `json:"time"`
+	// API 调用的API接口名字
+	API string `json:"api"`
+	// Namespace 调用涉及的服务命名空间
+	Namespace string `json:"namespace,omitempty"`
+	// Service 调用涉及的服务名
+	Service string `json:"service,omitempty"`
+	// RetCode 返回码
+	RetCode ErrCode `json:"retCode"`
+	// Delay 调用耗时
+	Delay time.Duration `json:"delay"`
 }
 
 // SetSuccess 设置成功的调用结果
@@ -1009,6 +1288,8 @@ type InstanceHeartbeatRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
 }
 
 // String 打印消息内容
@@ -1058,7 +1339,7 @@ func (g *InstanceHeartbeatRequest) Validate() error {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceHeartbeatRequest:"+
 			" host should not be empty when instanceId is empty"))
 	}
-	if g.Port <= 0 || g.Port >= 65536 {
+	if !isValidPort(g.Host, g.Port) {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceRegisterRequest: port should be in range (0, 65536)"))
 	}
 	if errs != nil {
@@ -1086,6 +1367,8 @@ type InstanceDeRegisterRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
 }
 
 // String 打印消息内容
@@ -1135,7 +1418,7 @@ func (g *InstanceDeRegisterRequest) Validate() error {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceHeartbeatRequest:"+
 			" host should not be empty when instanceId is empty"))
 	}
-	if g.Port <= 0 || g.Port >= 65536 {
+	if !isValidPort(g.Host, g.Port) {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceRegisterRequest: port should be in range (0, 65536)"))
 	}
 	if errs != nil {
@@ -1153,6 +1436,10 @@ const (
 	MinPriority = 0
 	// MaxPriority 最大优先级
 	MaxPriority = 9
+	// DefaultWeightOverrideTTL 本地实例权重覆盖的默认有效期
+	DefaultWeightOverrideTTL = 5 * time.Minute
+	// MaxWeightOverrideTTL 本地实例权重覆盖的最长有效期，外部控制器需要定期刷新
+	MaxWeightOverrideTTL = 24 * time.Hour
 )
 
 const (
@@ -1193,6 +1480,8 @@ type InstanceRegisterRequest struct {
 	// ttl超时时间，如果节点要调用heartbeat上报，则必须填写，否则会400141错误码，单位：秒
 	TTL *int
 
+	// 可选，实例的地理位置，设置后覆盖SDK自动探测的位置，用于网关等代替远端设备注册实例的场景；
+	// 为空时使用自动探测的位置。地域层级需要完整，即设置了Campus必须设置Zone，设置了Zone必须设置Region
 	Location *Location
 
 	// 可选，单次查询超时时间，默认直接获取全局的超时配置
@@ -1204,6 +1493,31 @@ type InstanceRegisterRequest struct {
 	InstanceId string
 	// 可选, 是否将心跳上报交由 SDK 内部定时任务进行处理
 	AutoHeartbeat bool
+	// 可选，AutoHeartbeat 开启时，服务端丢失实例或者心跳连续失败后 SDK 自动重新注册的结果回调
+	ReRegisterHandler func(event *InstanceReRegisterEvent)
+	// 可选，是否为进程自身的实例，为true时注册成功后该实例的服务及元数据作为规则路由的主调方自身信息，
+	// 代替其他实例注册（例如批量导入、选主候选）时不应设置
+	SelfInstance bool
+	// 可选，调用方上下文，通过SetContext设置
+	CallerContext
+}
+
+// InstanceReRegisterEvent 服务端丢失实例或者心跳连续失败后自动重新注册的事件
+type InstanceReRegisterEvent struct {
+	Namespace string
+	Service   string
+	Host      string
+	Port      int
+	// Cause 触发重新注册的心跳错误
+	Cause error
+	// Attempt 连续重新注册的次数，从1开始
+	Attempt int
+	// InstanceID 重新注册成功后服务端返回的实例ID
+	InstanceID string
+	// Err 重新注册的错误，为nil表示成功
+	Err error
+	// Backoff 重新注册失败后距离下一次重试的退避时长
+	Backoff time.Duration
 }
 
 // String 打印消息内容
@@ -1275,6 +1589,36 @@ func validateMetadata(prefix string, metadata map[string]string) error {
 	return nil
 }
 
+// validateLocation 校验显式指定的地理位置层级是否完整
+func validateLocation(prefix string, loc *Location) error {
+	if nil == loc || loc.IsEmpty() {
+		return nil
+	}
+	if len(loc.Campus) > 0 && len(loc.Zone) == 0 {
+		return fmt.Errorf("%s: location zone should not be empty when campus is set", prefix)
+	}
+	if len(loc.Zone) > 0 && len(loc.Region) == 0 {
+		return fmt.Errorf("%s: location region should not be empty when zone is set", prefix)
+	}
+	if len(loc.Rack) > 0 && len(loc.Campus) == 0 {
+		return fmt.Errorf("%s: location campus should not be empty when rack is set", prefix)
+	}
+	return nil
+}
+
+// WithTopologyMetadata 将位置中的机架信息写入实例元数据，返回新的元数据，不修改入参
+func WithTopologyMetadata(metadata map[string]string, loc *Location) map[string]string {
+	if nil == loc || len(loc.Rack) == 0 {
+		return metadata
+	}
+	merged := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	merged[TopologyRackMetadata] = loc.Rack
+	return merged
+}
+
 // Validate 校验InstanceRegisterRequest
 func (g *InstanceRegisterRequest) Validate() error {
 	if nil == g {
@@ -1290,7 +1634,7 @@ func (g *InstanceRegisterRequest) Validate() error {
 	if len(g.Host) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceRegisterRequest: host should not be empty"))
 	}
-	if g.Port <= 0 || g.Port >= 65536 {
+	if !isValidPort(g.Host, g.Port) {
 		errs = multierror.Append(errs, fmt.Errorf("InstanceRegisterRequest: port should be in range (0, 65536)"))
 	}
 	if nil != g.Weight && (*g.Weight < MinWeight || *g.Weight > MaxWeight) {
@@ -1308,6 +1652,9 @@ func (g *InstanceRegisterRequest) Validate() error {
 	if err = validateMetadata("InstanceRegisterRequest", g.Metadata); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = validateLocation("InstanceRegisterRequest", g.Location); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	if errs != nil {
 		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate InstanceRegisterRequest: ")
 	}
@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ContractStatusOffline 服务契约已下线
+	ContractStatusOffline = "Offline"
+)
+
+// ContractInterface 服务契约中声明的接口
+type ContractInterface struct {
+	// Method 方法名，对应http method、grpc方法名等，为空代表匹配任意方法
+	Method string
+	// Path 接口路径，对应http path、grpc service等，路径段支持{param}、:param及*通配，以/**结尾时匹配任意后缀
+	Path string
+}
+
+// ServiceContract 被调服务上报的服务契约
+type ServiceContract struct {
+	Namespace string
+	Service   string
+	// Protocol 契约协议，如http、grpc
+	Protocol string
+	// Version 契约版本
+	Version string
+	// Revision 契约摘要，契约内容变化时改变
+	Revision string
+	// Status 契约状态，Offline代表契约已下线
+	Status string
+	// Interfaces 契约中声明的接口
+	Interfaces []*ContractInterface
+}
+
+// IsOffline 契约是否已下线
+func (c *ServiceContract) IsOffline() bool {
+	return strings.EqualFold(c.Status, ContractStatusOffline)
+}
+
+// MatchInterface 查找与请求方法及路径匹配的接口，不存在时返回nil
+func (c *ServiceContract) MatchInterface(method string, path string) *ContractInterface {
+	for _, itf := range c.Interfaces {
+		if len(itf.Method) > 0 && !strings.EqualFold(itf.Method, method) {
+			continue
+		}
+		if matchContractPath(itf.Path, path) {
+			return itf
+		}
+	}
+	return nil
+}
+
+// Check 按照契约校验请求的方法及路径，返回不一致的原因，一致时返回ContractMismatchNone
+func (c *ServiceContract) Check(method string, path string) ContractMismatchReason {
+	if nil == c.MatchInterface(method, path) {
+		return ContractMismatchUnknownEndpoint
+	}
+	if c.IsOffline() {
+		return ContractMismatchDeprecated
+	}
+	return ContractMismatchNone
+}
+
+// matchContractPath 按路径段匹配契约路径
+func matchContractPath(pattern string, path string) bool {
+	patternSegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegs := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range patternSegs {
+		if seg == "**" && i == len(patternSegs)-1 {
+			return true
+		}
+		if i >= len(pathSegs) {
+			return false
+		}
+		if seg == "*" || strings.HasPrefix(seg, ":") || (strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+			continue
+		}
+		if seg != pathSegs[i] {
+			return false
+		}
+	}
+	return len(patternSegs) == len(pathSegs)
+}
+
+// GetServiceContractRequest 向服务端查询服务契约的请求
+type GetServiceContractRequest struct {
+	Namespace string
+	Service   string
+	// Protocol 契约协议
+	Protocol string
+	// Version 可选，契约版本
+	Version string
+	// 可选，单次查询超时时间，默认直接获取全局的超时配置
+	Timeout time.Duration
+}
+
+// ContractMismatchReason 请求与契约不一致的原因
+type ContractMismatchReason string
+
+const (
+	// ContractMismatchNone 请求与契约一致
+	ContractMismatchNone ContractMismatchReason = ""
+	// ContractMismatchUnknownEndpoint 请求的路径及方法未在契约中声明
+	ContractMismatchUnknownEndpoint ContractMismatchReason = "unknown_endpoint"
+	// ContractMismatchDeprecated 请求的接口所在的契约已下线
+	ContractMismatchDeprecated ContractMismatchReason = "deprecated"
+)
+
+// ValidateContractRequest 按照被调服务的契约校验请求的请求
+type ValidateContractRequest struct {
+	// 必选，被调服务名
+	Service string
+	// 必选，被调命名空间
+	Namespace string
+	// 可选，契约协议，默认使用consumer.contractValidation.protocol配置
+	Protocol string
+	// 可选，契约版本
+	Version string
+	// 可选，请求方法，如http method
+	Method string
+	// 必选，请求路径，如http path
+	Path string
+}
+
+// GetService 获取服务名
+func (r *ValidateContractRequest) GetService() string {
+	return r.Service
+}
+
+// GetNamespace 获取命名空间
+func (r *ValidateContractRequest) GetNamespace() string {
+	return r.Namespace
+}
+
+// GetMetadata 契约校验请求没有元数据
+func (r *ValidateContractRequest) GetMetadata() map[string]string {
+	return nil
+}
+
+// Validate 校验ValidateContractRequest
+func (r *ValidateContractRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ValidateContractRequest can not be nil")
+	}
+	if err := validateServiceMetadata("ValidateContractRequest", r); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "fail to validate ValidateContractRequest")
+	}
+	if len(r.Path) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ValidateContractRequest: path is empty")
+	}
+	return nil
+}
+
+// ContractValidationResult 契约校验结果
+type ContractValidationResult struct {
+	// Checked 是否进行了校验，未启用契约校验或者被调服务未上报契约时为false
+	Checked bool
+	// Reason 请求与契约不一致的原因，一致时为空
+	Reason ContractMismatchReason
+	// Revision 校验使用的契约摘要
+	Revision string
+}
+
+// IsMismatch 请求是否与契约不一致
+func (r *ContractValidationResult) IsMismatch() bool {
+	return r.Reason != ContractMismatchNone
+}
+
+// ContractMismatchGauge 请求与被调服务契约不一致
+type ContractMismatchGauge struct {
+	EmptyInstanceGauge
+	Namespace string
+	Service   string
+	Protocol  string
+	Method    string
+	Path      string
+	Reason    ContractMismatchReason
+}
@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+)
+
+// TestServiceContractCheck 测试按照服务契约校验请求的方法及路径
+func TestServiceContractCheck(t *testing.T) {
+	contract := &ServiceContract{
+		Interfaces: []*ContractInterface{
+			{Method: "GET", Path: "/users/{id}"},
+			{Method: "POST", Path: "/users"},
+			{Path: "/static/**"},
+		},
+	}
+	cases := []struct {
+		method string
+		path   string
+		expect ContractMismatchReason
+	}{
+		{"get", "/users/42", ContractMismatchNone},
+		{"POST", "/users/", ContractMismatchNone},
+		{"DELETE", "/users/42", ContractMismatchUnknownEndpoint},
+		{"GET", "/users/42/orders", ContractMismatchUnknownEndpoint},
+		{"PUT", "/static/js/app.js", ContractMismatchNone},
+		{"GET", "/orders", ContractMismatchUnknownEndpoint},
+	}
+	for _, c := range cases {
+		if reason := contract.Check(c.method, c.path); reason != c.expect {
+			t.Fatalf("%s %s: expect %q, got %q", c.method, c.path, c.expect, reason)
+		}
+	}
+	contract.Status = ContractStatusOffline
+	if reason := contract.Check("GET", "/users/42"); reason != ContractMismatchDeprecated {
+		t.Fatalf("expect offline contract reported as deprecated, got %q", reason)
+	}
+}
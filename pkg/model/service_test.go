@@ -0,0 +1,52 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "testing"
+
+func TestInstanceRegisterRequestLocation(t *testing.T) {
+	req := &InstanceRegisterRequest{Namespace: "Test", Service: "echo", Host: "127.0.0.1", Port: 8080}
+	req.SetLocation(&Location{Region: "south-china", Zone: "shenzhen", Campus: "nanshan"})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expect complete location valid, got %v", err)
+	}
+	req.SetLocation(&Location{})
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expect empty location valid, got %v", err)
+	}
+	req.SetLocation(&Location{Zone: "shenzhen"})
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect zone without region invalid")
+	}
+	req.SetLocation(&Location{Region: "south-china", Campus: "nanshan"})
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect campus without zone invalid")
+	}
+}
+
+// TestGetOneInstanceRequestForceHostPort 测试强制路由地址需要为 host:port 格式
+func TestGetOneInstanceRequestForceHostPort(t *testing.T) {
+	req := &GetOneInstanceRequest{Namespace: "Test", Service: "svc", ForceHostPort: "10.0.0.1:8080"}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	req.ForceHostPort = "10.0.0.1"
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect ForceHostPort without port rejected")
+	}
+}
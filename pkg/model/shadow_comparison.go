@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// ShadowComparisonResult 影子请求与主请求应答的对比结果
+type ShadowComparisonResult string
+
+const (
+	// ShadowMatch 影子服务与主服务的应答一致
+	ShadowMatch ShadowComparisonResult = "match"
+	// ShadowMismatch 影子服务与主服务的应答不一致
+	ShadowMismatch ShadowComparisonResult = "mismatch"
+	// ShadowError 影子服务调用失败，无法对比
+	ShadowError ShadowComparisonResult = "shadow_error"
+)
+
+// ReportShadowComparisonRequest 上报影子请求对比结果的请求
+type ReportShadowComparisonRequest struct {
+	// 必选，主服务名
+	Service string
+	// 必选，主服务命名空间
+	Namespace string
+	// 必选，影子服务名
+	ShadowService string
+	// 必选，影子服务命名空间
+	ShadowNamespace string
+	// 可选，请求方法，如http path或者grpc方法名
+	Method string
+	// 必选，对比结果
+	Result ShadowComparisonResult
+	// 可选，不一致或者失败的描述，仅输出到日志，不作为指标维度
+	Detail string
+}
+
+// GetService 获取服务名
+func (r *ReportShadowComparisonRequest) GetService() string {
+	return r.Service
+}
+
+// GetNamespace 获取命名空间
+func (r *ReportShadowComparisonRequest) GetNamespace() string {
+	return r.Namespace
+}
+
+// GetMetadata 影子对比上报请求没有元数据
+func (r *ReportShadowComparisonRequest) GetMetadata() map[string]string {
+	return nil
+}
+
+// Validate 校验ReportShadowComparisonRequest
+func (r *ReportShadowComparisonRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ReportShadowComparisonRequest can not be nil")
+	}
+	if err := validateServiceMetadata("ReportShadowComparisonRequest", r); err != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, err, "fail to validate ReportShadowComparisonRequest")
+	}
+	if len(r.ShadowService) == 0 || len(r.ShadowNamespace) == 0 {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"ReportShadowComparisonRequest: shadow service or namespace is empty")
+	}
+	switch r.Result {
+	case ShadowMatch, ShadowMismatch, ShadowError:
+	default:
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil,
+			"ReportShadowComparisonRequest: invalid result %q", r.Result)
+	}
+	return nil
+}
+
+// ShadowComparisonGauge 影子请求对比结果
+type ShadowComparisonGauge struct {
+	EmptyInstanceGauge
+	Namespace       string
+	Service         string
+	ShadowNamespace string
+	ShadowService   string
+	Method          string
+	Result          ShadowComparisonResult
+}
@@ -62,6 +62,24 @@ const (
 	LoadBalanceStat
 	RateLimitStat
 	RouteStat
+	BulkheadStat
+	ServerEndpointStat
+	ServerTrafficStat
+	RegisterFlappingStat
+	PluginPanicStat
+	CacheDivergenceStat
+	RequestAttemptsStat
+	RateLimitTopKStat
+	FlowBudgetStat
+	ConfigOperationStat
+	ConfigPropagationStat
+	ConnectionAgeStat
+	NamespaceFallbackStat
+	CacheMemoryStat
+	ContractMismatchStat
+	ConnectionLimitStat
+	RateLimitExemptionStat
+	ShadowComparisonStat
 )
 
 func DescMetricType(t MetricType) string {
@@ -84,6 +102,42 @@ func DescMetricType(t MetricType) string {
 		return "RateLimitStat"
 	case RouteStat:
 		return "RouteStat"
+	case BulkheadStat:
+		return "BulkheadStat"
+	case ServerEndpointStat:
+		return "ServerEndpointStat"
+	case ServerTrafficStat:
+		return "ServerTrafficStat"
+	case RegisterFlappingStat:
+		return "RegisterFlappingStat"
+	case PluginPanicStat:
+		return "PluginPanicStat"
+	case CacheDivergenceStat:
+		return "CacheDivergenceStat"
+	case RequestAttemptsStat:
+		return "RequestAttemptsStat"
+	case RateLimitTopKStat:
+		return "RateLimitTopKStat"
+	case FlowBudgetStat:
+		return "FlowBudgetStat"
+	case ConfigOperationStat:
+		return "ConfigOperationStat"
+	case ConfigPropagationStat:
+		return "ConfigPropagationStat"
+	case ConnectionAgeStat:
+		return "ConnectionAgeStat"
+	case NamespaceFallbackStat:
+		return "NamespaceFallbackStat"
+	case CacheMemoryStat:
+		return "CacheMemoryStat"
+	case ContractMismatchStat:
+		return "ContractMismatchStat"
+	case ConnectionLimitStat:
+		return "ConnectionLimitStat"
+	case RateLimitExemptionStat:
+		return "RateLimitExemptionStat"
+	case ShadowComparisonStat:
+		return "ShadowComparisonStat"
 	default:
 		return "Unknown"
 	}
@@ -256,4 +310,22 @@ func init() {
 	metricTypes.Add(LoadBalanceStat)
 	metricTypes.Add(RateLimitStat)
 	metricTypes.Add(RouteStat)
+	metricTypes.Add(BulkheadStat)
+	metricTypes.Add(ServerEndpointStat)
+	metricTypes.Add(ServerTrafficStat)
+	metricTypes.Add(RegisterFlappingStat)
+	metricTypes.Add(PluginPanicStat)
+	metricTypes.Add(CacheDivergenceStat)
+	metricTypes.Add(RequestAttemptsStat)
+	metricTypes.Add(RateLimitTopKStat)
+	metricTypes.Add(FlowBudgetStat)
+	metricTypes.Add(ConfigOperationStat)
+	metricTypes.Add(ConfigPropagationStat)
+	metricTypes.Add(ConnectionAgeStat)
+	metricTypes.Add(NamespaceFallbackStat)
+	metricTypes.Add(CacheMemoryStat)
+	metricTypes.Add(ContractMismatchStat)
+	metricTypes.Add(ConnectionLimitStat)
+	metricTypes.Add(RateLimitExemptionStat)
+	metricTypes.Add(ShadowComparisonStat)
 }
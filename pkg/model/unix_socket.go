@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// UnixSocketScheme unix domain socket 实例的地址前缀，注册时 Host 填写为 unix:///path/to/sock，Port 可以为0
+	UnixSocketScheme = "unix://"
+	// NetworkUnix unix domain socket 的网络类型
+	NetworkUnix = "unix"
+	// NetworkTCP tcp 的网络类型
+	NetworkTCP = "tcp"
+)
+
+// IsUnixSocketHost 实例地址是否为 unix domain socket
+func IsUnixSocketHost(host string) bool {
+	return strings.HasPrefix(host, UnixSocketScheme)
+}
+
+// UnixSocketPath 获取 unix domain socket 实例的文件路径，非 unix domain socket 地址返回空
+func UnixSocketPath(host string) string {
+	if !IsUnixSocketHost(host) {
+		return ""
+	}
+	return strings.TrimPrefix(host, UnixSocketScheme)
+}
+
+// InstanceDialAddress 获取实例用于拨号的网络类型及地址，可直接传给 net.Dial，
+// unix domain socket 实例返回 ("unix", path)，其他实例返回 ("tcp", host:port)
+func InstanceDialAddress(instance Instance) (string, string) {
+	return DialAddress(instance.GetHost(), int(instance.GetPort()))
+}
+
+// DialAddress 根据实例的 host、port 计算拨号的网络类型及地址
+func DialAddress(host string, port int) (string, string) {
+	if IsUnixSocketHost(host) {
+		return NetworkUnix, UnixSocketPath(host)
+	}
+	return NetworkTCP, net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// isValidPort 校验端口，unix domain socket 实例允许端口为0
+func isValidPort(host string, port int) bool {
+	if IsUnixSocketHost(host) {
+		return len(UnixSocketPath(host)) > 0 && port >= 0 && port < 65536
+	}
+	return port > 0 && port < 65536
+}
@@ -0,0 +1,44 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import "testing"
+
+func TestUnixSocketInstance(t *testing.T) {
+	network, address := DialAddress("unix:///var/run/echo.sock", 0)
+	if network != NetworkUnix || address != "/var/run/echo.sock" {
+		t.Fatalf("unexpected unix dial address %s %s", network, address)
+	}
+	network, address = DialAddress("::1", 8080)
+	if network != NetworkTCP || address != "[::1]:8080" {
+		t.Fatalf("unexpected tcp dial address %s %s", network, address)
+	}
+
+	req := &InstanceRegisterRequest{Namespace: "Test", Service: "echo", Host: "unix:///var/run/echo.sock"}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expect unix socket instance with zero port valid, got %v", err)
+	}
+	req.Host = "unix://"
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect unix socket instance without path invalid")
+	}
+	req.Host = "127.0.0.1"
+	if err := req.Validate(); err == nil {
+		t.Fatal("expect tcp instance with zero port invalid")
+	}
+}
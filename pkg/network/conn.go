@@ -21,6 +21,7 @@ package network
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -64,6 +65,42 @@ type Connection struct {
 	lazyDestroy uint32
 	// to apply for the lock
 	mutex sync.Mutex
+	// 连接创建时间
+	createTime time.Time
+	// 最近一次占用或者释放连接的时间，UnixNano
+	lastActive int64
+	// 连接的最大存活时间，已叠加随机延迟，0表示不限制
+	maxAge time.Duration
+}
+
+// newConnection 创建连接对象
+func newConnection(conn ClosableConn, connID ConnID, maxAge time.Duration) *Connection {
+	now := time.Now()
+	return &Connection{
+		Conn:       conn,
+		ConnID:     connID,
+		createTime: now,
+		lastActive: now.UnixNano(),
+		maxAge:     maxAge,
+	}
+}
+
+// Age 连接的存活时长
+func (c *Connection) Age() time.Duration {
+	return time.Since(c.createTime)
+}
+
+// idleTime 连接的空闲时长，连接被占用时返回0
+func (c *Connection) idleTime(now time.Time) time.Duration {
+	if atomic.LoadInt32(&c.ref) > 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&c.lastActive)))
+}
+
+// expired 连接是否超过最大存活时间
+func (c *Connection) expired(now time.Time) bool {
+	return c.maxAge > 0 && now.Sub(c.createTime) >= c.maxAge
 }
 
 // IsAvailableConnection whether the connection is available
@@ -85,6 +122,7 @@ func (c *Connection) acquire(opKey string) bool {
 		return false
 	}
 	curRef := atomic.AddInt32(&c.ref, 1)
+	atomic.StoreInt64(&c.lastActive, time.Now().UnixNano())
 	log.GetNetworkLogger().Tracef("connection %v: acquired, curRef is %d", c.ConnID, curRef)
 	return true
 }
@@ -122,6 +160,7 @@ func (c *Connection) lazyClose(force bool) {
 // Release the connection release
 func (c *Connection) Release(opKey string) {
 	nextValue := atomic.AddInt32(&c.ref, -1)
+	atomic.StoreInt64(&c.lastActive, time.Now().UnixNano())
 	log.GetNetworkLogger().Tracef(
 		"connection %s: pending to release for op %s, curRef is %d", c.ConnID, opKey, nextValue)
 	var closed bool
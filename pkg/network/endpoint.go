@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// serverEndpoint 带优先级及权重的server地址
+type serverEndpoint struct {
+	address  string
+	priority int
+	weight   int
+	// 平滑加权轮询的当前权重
+	currentWeight int
+	// 连接失败后的隔离截止时间，隔离期间不参与选择
+	isolatedUntil time.Time
+}
+
+// endpointSelector 按照优先级分组选择server地址，组内按照权重进行平滑加权轮询
+// 只有高优先级的地址全部被隔离后，才会选择低优先级（容灾）的地址
+type endpointSelector struct {
+	mutex     sync.Mutex
+	endpoints []*serverEndpoint
+	// 地址连接失败后的隔离时长，隔离结束后才会尝试切回
+	isolation time.Duration
+}
+
+func newEndpointSelector(endpointConfigs []*config.ServerEndpointConfig, isolation time.Duration) *endpointSelector {
+	endpoints := make([]*serverEndpoint, 0, len(endpointConfigs))
+	for _, endpointConfig := range endpointConfigs {
+		endpoints = append(endpoints, &serverEndpoint{
+			address:  endpointConfig.Address,
+			priority: endpointConfig.Priority,
+			weight:   endpointConfig.GetWeight(),
+		})
+	}
+	// 随机打散，避免所有客户端都从同一个地址开始轮询
+	for i := len(endpoints) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		endpoints[i], endpoints[j] = endpoints[j], endpoints[i]
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].priority < endpoints[j].priority
+	})
+	return &endpointSelector{
+		endpoints: endpoints,
+		isolation: isolation,
+	}
+}
+
+// selectEndpoint 选择一个server地址
+func (s *endpointSelector) selectEndpoint() *serverEndpoint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	candidates := s.availableGroup(time.Now())
+	if len(candidates) == 0 {
+		// 所有地址都被隔离，则全部地址参与选择
+		candidates = s.endpoints
+	}
+	var (
+		total int
+		best  *serverEndpoint
+	)
+	for _, endpoint := range candidates {
+		endpoint.currentWeight += endpoint.weight
+		total += endpoint.weight
+		if best == nil || endpoint.currentWeight > best.currentWeight {
+			best = endpoint
+		}
+	}
+	if best != nil {
+		best.currentWeight -= total
+	}
+	return best
+}
+
+// availableGroup 返回优先级最高且未被隔离的地址分组
+func (s *endpointSelector) availableGroup(now time.Time) []*serverEndpoint {
+	var group []*serverEndpoint
+	for _, endpoint := range s.endpoints {
+		if len(group) > 0 && endpoint.priority != group[0].priority {
+			break
+		}
+		if now.Before(endpoint.isolatedUntil) {
+			continue
+		}
+		group = append(group, endpoint)
+	}
+	return group
+}
+
+// markFailed 地址连接失败，进行隔离
+func (s *endpointSelector) markFailed(address string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if endpoint := s.find(address); endpoint != nil {
+		endpoint.isolatedUntil = time.Now().Add(s.isolation)
+	}
+}
+
+// markSuccess 地址连接成功，解除隔离
+func (s *endpointSelector) markSuccess(address string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if endpoint := s.find(address); endpoint != nil {
+		endpoint.isolatedUntil = time.Time{}
+	}
+}
+
+// getPriority 获取地址的优先级
+func (s *endpointSelector) getPriority(address string) (int, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if endpoint := s.find(address); endpoint != nil {
+		return endpoint.priority, true
+	}
+	return 0, false
+}
+
+// needFailback 当前连接的地址是否存在可用的更高优先级地址
+func (s *endpointSelector) needFailback(address string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	current := s.find(address)
+	if current == nil {
+		return false
+	}
+	group := s.availableGroup(time.Now())
+	return len(group) > 0 && group[0].priority < current.priority
+}
+
+func (s *endpointSelector) find(address string) *serverEndpoint {
+	for _, endpoint := range s.endpoints {
+		if endpoint.address == address {
+			return endpoint
+		}
+	}
+	return nil
+}
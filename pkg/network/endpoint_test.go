@@ -0,0 +1,56 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+func TestEndpointSelectorPriorityAndWeight(t *testing.T) {
+	selector := newEndpointSelector([]*config.ServerEndpointConfig{
+		{Address: "primary-1", Priority: 0, Weight: 300},
+		{Address: "primary-2", Priority: 0, Weight: 100},
+		{Address: "dr-1", Priority: 1},
+	}, time.Hour)
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[selector.selectEndpoint().address]++
+	}
+	if counts["primary-1"] != 300 || counts["primary-2"] != 100 || counts["dr-1"] != 0 {
+		t.Fatalf("unexpected selection counts %v", counts)
+	}
+
+	selector.markFailed("primary-1")
+	selector.markFailed("primary-2")
+	if addr := selector.selectEndpoint().address; addr != "dr-1" {
+		t.Fatalf("expect dr endpoint when primary isolated, got %s", addr)
+	}
+	if selector.needFailback("dr-1") {
+		t.Fatal("no failback while primary isolated")
+	}
+	selector.markSuccess("primary-2")
+	if !selector.needFailback("dr-1") {
+		t.Fatal("expect failback when primary recovered")
+	}
+	if addr := selector.selectEndpoint().address; addr != "primary-2" {
+		t.Fatalf("expect recovered primary endpoint, got %s", addr)
+	}
+}
@@ -20,7 +20,6 @@ package network
 import (
 	"context"
 	"fmt"
-	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,10 +27,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/modern-go/reflect2"
 
-	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 var (
@@ -52,19 +51,17 @@ type ServerAddressList struct {
 	useDefault bool
 	// 当前生效连接，存放的是Connection对象
 	curConn atomic.Value
-	// 当前的index，只对预埋地址生效，用于轮询
-	curIndex int
-	// 预埋地址列表
-	addresses []string
+	// 预埋地址选择器，按照优先级及权重选择地址
+	endpoints *endpointSelector
 	// 首次连接控制锁
 	connectMutex sync.Mutex
 	// 全局管理对象指针
 	manager *connectionManager
 }
 
-// getAndConnectServer 获取并进行连接
+// getAndConnectServer 获取并进行连接，reason 为替换当前连接的原因
 func (s *ServerAddressList) getAndConnectServer(
-	force bool, svc config.ClusterService, timeout time.Duration) *Connection {
+	force bool, svc config.ClusterService, timeout time.Duration, reason string) *Connection {
 	s.connectMutex.Lock()
 	defer s.connectMutex.Unlock()
 	address, instance, err := s.getServerAddress(s.manager.GetHashKey())
@@ -72,7 +69,7 @@ func (s *ServerAddressList) getAndConnectServer(
 		log.GetNetworkLogger().Errorf("fail get server address from service %s, error %v", svc, err)
 		return nil
 	}
-	conn, err := s.connectServer(force, address, instance, svc, timeout)
+	conn, err := s.connectServer(force, address, instance, svc, timeout, reason)
 	if err != nil {
 		log.GetNetworkLogger().Errorf("fail get connect %s from service %s, error %v", address, svc, err)
 		return nil
@@ -85,13 +82,11 @@ func (s *ServerAddressList) getServerAddress(hashKey []byte) (string, model.Inst
 	var targetAddress string
 	var instance model.Instance
 	if s.service.ClusterType == config.BuiltinCluster || s.service.ClusterType == config.ConfigCluster {
-		serverCount := len(s.addresses)
-		targetAddress = s.addresses[s.curIndex%serverCount]
-		if s.curIndex == math.MaxInt32 {
-			s.curIndex = 0
-		} else {
-			s.curIndex++
+		endpoint := s.endpoints.selectEndpoint()
+		if endpoint == nil {
+			return "", nil, fmt.Errorf("no server address for %s", s.service)
 		}
+		targetAddress = endpoint.address
 	} else {
 		engineValue, ok := s.manager.valueCtx.GetValue(model.ContextKeyEngine)
 		if !ok {
@@ -134,9 +129,9 @@ func (s *ServerAddressList) loadCurrentConnection() *Connection {
 	return connValue.(*Connection)
 }
 
-// connectServer 根据地址进行连接
+// connectServer 根据地址进行连接，当前连接可用时按 reason 上报被替换连接的存活时长
 func (s *ServerAddressList) connectServer(force bool, addr string, instance model.Instance,
-	service config.ClusterService, timeout time.Duration) (*Connection, error) {
+	service config.ClusterService, timeout time.Duration, reason string) (*Connection, error) {
 	var lastConn = s.loadCurrentConnection()
 	if !force && IsAvailableConnection(lastConn) && lastConn.Address == addr {
 		log.GetNetworkLogger().Debugf("address %s not changed, no need to switch server", addr)
@@ -156,19 +151,31 @@ func (s *ServerAddressList) connectServer(force bool, addr string, instance mode
 		if !reflect2.IsNil(instance) {
 			s.manager.ReportFail(connID, int32(model.ErrCodeConnectError), connectDuration)
 		}
+		if nil != s.endpoints {
+			s.endpoints.markFailed(addr)
+		}
 		return nil, fmt.Errorf("fail to connect to %s, timeout is %v, service is %s, because %s",
 			addr, connectDuration, s.service, err.Error())
 	}
+	if nil != s.endpoints {
+		s.endpoints.markSuccess(addr)
+	}
 
 	if nil != lastConn {
+		if IsAvailableConnection(lastConn) {
+			s.reportConnectionAge(lastConn, reason)
+		}
 		// 延迟释放连接
 		lastConn.lazyClose(false)
+		if lastConn.Address != addr {
+			s.reportEndpointPinned(lastConn.Address, lastConn.instance, false)
+		}
 	}
-
-	conn := &Connection{
-		Conn:   tcpConn,
-		ConnID: connID,
+	if nil == lastConn || lastConn.Address != addr {
+		s.reportEndpointPinned(addr, instance, true)
 	}
+
+	conn := newConnection(tcpConn, connID, s.manager.jitteredMaxAge())
 	if ctrl, ok := DefaultServerServiceToConnectionControl[s.service.ClusterType]; ok && ctrl == ConnectionLong {
 		log.GetNetworkLogger().Infof("long connection %v, target address %s: create", conn.ConnID, addr)
 	} else {
@@ -178,6 +185,40 @@ func (s *ServerAddressList) connectServer(force bool, addr string, instance mode
 	return conn, nil
 }
 
+// reportEndpointPinned 上报连接绑定的server地址
+func (s *ServerAddressList) reportEndpointPinned(addr string, instance model.Instance, pinned bool) {
+	engineValue, ok := s.manager.valueCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	gauge := &model.ServerEndpointGauge{
+		Cluster: string(s.service.ClusterType),
+		Address: addr,
+		Pinned:  pinned,
+	}
+	if nil != s.endpoints {
+		gauge.Priority, _ = s.endpoints.getPriority(addr)
+	} else if !reflect2.IsNil(instance) {
+		gauge.Priority = int(instance.GetPriority())
+	}
+	_ = engineValue.(model.Engine).SyncReportStat(model.ServerEndpointStat, gauge)
+}
+
+// tryFailback 当前连接在低优先级地址上，且高优先级地址已经可用时，尝试切回高优先级地址
+func (s *ServerAddressList) tryFailback(timeout time.Duration) {
+	if nil == s.endpoints {
+		return
+	}
+	curConn := s.loadCurrentConnection()
+	if !IsAvailableConnection(curConn) || !s.endpoints.needFailback(curConn.Address) {
+		return
+	}
+	log.GetNetworkLogger().Infof("start failback for %s, current address %s", s.service, curConn.Address)
+	if conn := s.getAndConnectServer(false, s.service, timeout, model.ConnectionRetireFailback); nil != conn {
+		log.GetNetworkLogger().Infof("server of %s failback to %s", s.service, conn.Address)
+	}
+}
+
 // ConnectServerByAddrOnly 。根据地址进行链接
 func (s *ServerAddressList) ConnectServerByAddrOnly(addr string, timeout time.Duration,
 	clsService config.ClusterService, instance model.Instance) (*Connection, error) {
@@ -194,10 +235,7 @@ func (s *ServerAddressList) ConnectServerByAddrOnly(addr string, timeout time.Du
 		Address:  addr,
 		instance: instance,
 	}
-	conn := &Connection{
-		Conn:   tcpConn,
-		ConnID: connID,
-	}
+	conn := newConnection(tcpConn, connID, 0)
 	conn.acquire(addr)
 	return conn, nil
 }
@@ -219,7 +257,7 @@ func (s *ServerAddressList) tryGetConnection(timeout time.Duration, hashKey []by
 	if err != nil {
 		return nil, err
 	}
-	return s.connectServer(false, address, instance, s.service, timeout)
+	return s.connectServer(false, address, instance, s.service, timeout, model.ConnectionRetireDown)
 }
 
 // closeCurrentConnection 关闭当前连接
@@ -239,8 +277,14 @@ type connectionManager struct {
 	connectTimeout time.Duration
 	// 连接切换周期
 	switchInterval time.Duration
-	ctx            context.Context
-	cancel         context.CancelFunc
+	// 切回高优先级地址的检查周期
+	failbackInterval time.Duration
+	// 连接的最大存活时间、最大空闲时间及重建连接的随机延迟上限
+	maxConnectionAge  time.Duration
+	maxConnectionIdle time.Duration
+	reconnectJitter   time.Duration
+	ctx               context.Context
+	cancel            context.CancelFunc
 	// 发现服务
 	discoverService model.ServiceKey
 	// 配置中心服务
@@ -268,13 +312,19 @@ func NewConnectionManager(
 	switchInterval := cfg.GetGlobal().GetServerConnector().GetServerSwitchInterval()
 	connectTimeout := cfg.GetGlobal().GetServerConnector().GetConnectTimeout()
 	protocol := cfg.GetGlobal().GetServerConnector().GetProtocol()
+	endpoints := cfg.GetGlobal().GetServerConnector().GetEndpoints()
+	failbackInterval := cfg.GetGlobal().GetServerConnector().GetFailbackInterval()
 	manager := &connectionManager{
-		connectTimeout:   connectTimeout,
-		switchInterval:   switchInterval,
-		serverServices:   make(map[config.ClusterType]*ServerAddressList),
-		valueCtx:         valueCtx,
-		protocol:         protocol,
-		discoverEventSet: make(map[model.EventType]bool, 0),
+		connectTimeout:    connectTimeout,
+		switchInterval:    switchInterval,
+		failbackInterval:  failbackInterval,
+		maxConnectionAge:  cfg.GetGlobal().GetServerConnector().GetMaxConnectionAge(),
+		maxConnectionIdle: cfg.GetGlobal().GetServerConnector().GetMaxConnectionIdle(),
+		reconnectJitter:   cfg.GetGlobal().GetServerConnector().GetReconnectJitter(),
+		serverServices:    make(map[config.ClusterType]*ServerAddressList),
+		valueCtx:          valueCtx,
+		protocol:          protocol,
+		discoverEventSet:  make(map[model.EventType]bool, 0),
 	}
 	serverServices := config.GetServerServices(cfg)
 	for _, svc := range serverServices {
@@ -295,8 +345,7 @@ func NewConnectionManager(
 		},
 		useDefault: false,
 		manager:    manager,
-		addresses:  addresses,
-		curIndex:   rand.Intn(len(addresses)),
+		endpoints:  newEndpointSelector(config.MergeServerEndpoints(addresses, endpoints), failbackInterval),
 	}
 	manager.serverServices[config.BuiltinCluster] = builtInAddrList
 	if len(manager.discoverService.Service) == 0 {
@@ -304,7 +353,9 @@ func NewConnectionManager(
 		manager.ready = serviceReadyStatus
 	}
 	manager.ctx, manager.cancel = context.WithCancel(context.Background())
-	go manager.doSwitchRoutine()
+	profiling.Go("network", "switchServer", manager.doSwitchRoutine)
+	profiling.Go("network", "failback", manager.doFailbackRoutine)
+	profiling.Go("network", "reapConnection", manager.doReapRoutine)
 	return manager, nil
 }
 
@@ -313,15 +364,21 @@ func NewConfigConnectionManager(cfg config.Configuration, valueCtx model.ValueCo
 	configSwitchInterval := cfg.GetConfigFile().GetConfigConnectorConfig().GetServerSwitchInterval()
 	configConnectTimeout := cfg.GetConfigFile().GetConfigConnectorConfig().GetConnectTimeout()
 	configProtocol := cfg.GetConfigFile().GetConfigConnectorConfig().GetProtocol()
+	configFailbackInterval := cfg.GetConfigFile().GetConfigConnectorConfig().GetFailbackInterval()
 	configManager := &connectionManager{
-		connectTimeout: configConnectTimeout,
-		switchInterval: configSwitchInterval,
-		serverServices: make(map[config.ClusterType]*ServerAddressList),
-		valueCtx:       valueCtx,
-		protocol:       configProtocol,
+		connectTimeout:    configConnectTimeout,
+		switchInterval:    configSwitchInterval,
+		failbackInterval:  configFailbackInterval,
+		maxConnectionAge:  cfg.GetConfigFile().GetConfigConnectorConfig().GetMaxConnectionAge(),
+		maxConnectionIdle: cfg.GetConfigFile().GetConfigConnectorConfig().GetMaxConnectionIdle(),
+		reconnectJitter:   cfg.GetConfigFile().GetConfigConnectorConfig().GetReconnectJitter(),
+		serverServices:    make(map[config.ClusterType]*ServerAddressList),
+		valueCtx:          valueCtx,
+		protocol:          configProtocol,
 	}
 
 	configAddresses := cfg.GetConfigFile().GetConfigConnectorConfig().GetAddresses()
+	configEndpoints := cfg.GetConfigFile().GetConfigConnectorConfig().GetEndpoints()
 	configAddrList := &ServerAddressList{
 		service: config.ClusterService{
 			ServiceKey:  model.ServiceKey{Namespace: config.ServerNamespace, Service: defaultService},
@@ -329,8 +386,8 @@ func NewConfigConnectionManager(cfg config.Configuration, valueCtx model.ValueCo
 		},
 		useDefault: false,
 		manager:    configManager,
-		addresses:  configAddresses,
-		curIndex:   rand.Intn(len(configAddresses)),
+		endpoints: newEndpointSelector(config.MergeServerEndpoints(configAddresses, configEndpoints),
+			configFailbackInterval),
 	}
 	configManager.serverServices[config.ConfigCluster] = configAddrList
 
@@ -340,9 +397,47 @@ func NewConfigConnectionManager(cfg config.Configuration, valueCtx model.ValueCo
 	}
 
 	configManager.ctx, configManager.cancel = context.WithCancel(context.Background())
+	profiling.Go("network", "failback", configManager.doFailbackRoutine)
+	profiling.Go("network", "reapConnection", configManager.doReapRoutine)
 	return configManager, nil
 }
 
+// NewRegistryConnectionManager 创建只连接指定服务端地址的连接管理器，用于双注册场景下对接旧集群，
+// 服务发现及健康检查集群均使用内置的地址列表
+func NewRegistryConnectionManager(
+	cfg config.Configuration, valueCtx model.ValueContext, addresses []string) (ConnectionManager, error) {
+	failbackInterval := cfg.GetGlobal().GetServerConnector().GetFailbackInterval()
+	manager := &connectionManager{
+		connectTimeout:    cfg.GetGlobal().GetServerConnector().GetConnectTimeout(),
+		switchInterval:    cfg.GetGlobal().GetServerConnector().GetServerSwitchInterval(),
+		failbackInterval:  failbackInterval,
+		maxConnectionAge:  cfg.GetGlobal().GetServerConnector().GetMaxConnectionAge(),
+		maxConnectionIdle: cfg.GetGlobal().GetServerConnector().GetMaxConnectionIdle(),
+		reconnectJitter:   cfg.GetGlobal().GetServerConnector().GetReconnectJitter(),
+		serverServices:    make(map[config.ClusterType]*ServerAddressList),
+		valueCtx:          valueCtx,
+		protocol:          cfg.GetGlobal().GetServerConnector().GetProtocol(),
+		discoverEventSet:  make(map[model.EventType]bool, 0),
+	}
+	builtInAddrList := &ServerAddressList{
+		service: config.ClusterService{
+			ServiceKey:  model.ServiceKey{Namespace: config.ServerNamespace, Service: defaultService},
+			ClusterType: config.BuiltinCluster,
+		},
+		useDefault: false,
+		manager:    manager,
+		endpoints:  newEndpointSelector(config.MergeServerEndpoints(addresses, nil), failbackInterval),
+	}
+	manager.serverServices[config.BuiltinCluster] = builtInAddrList
+	manager.discoverService = builtInAddrList.service.ServiceKey
+	manager.ready = serviceReadyStatus
+	manager.ctx, manager.cancel = context.WithCancel(context.Background())
+	profiling.Go("network", "switchServer", manager.doSwitchRoutine)
+	profiling.Go("network", "failback", manager.doFailbackRoutine)
+	profiling.Go("network", "reapConnection", manager.doReapRoutine)
+	return manager, nil
+}
+
 // SetConnCreator 设置当前协议的连接创建器
 func (c *connectionManager) SetConnCreator(creator ConnCreator) {
 	c.creator = creator
@@ -426,12 +521,16 @@ func (c *connectionManager) ReportConnectionDown(connID ConnID) {
 		return
 	}
 	log.GetNetworkLogger().Infof("connection %s down received from service %s", connID, svc.String())
+	if nil != serverList.endpoints {
+		serverList.endpoints.markFailed(connID.Address)
+	}
 	curConn := serverList.loadCurrentConnection()
 	if nil != curConn && connID.ID != curConn.ConnID.ID {
 		// 已经切换新连接，忽略
 		return
 	}
 	if nil != curConn && IsAvailableConnection(curConn) {
+		serverList.reportConnectionAge(curConn, model.ConnectionRetireDown)
 		curConn.lazyClose(false)
 	}
 }
@@ -471,7 +570,8 @@ func (c *connectionManager) doSwitchRoutine() {
 					if IsAvailableConnection(curConn) {
 						// 只有成功后，才进行切换
 						log.GetNetworkLogger().Infof("start switch for %s", serverList.service.ServiceKey)
-						conn := serverList.getAndConnectServer(false, serverList.service, c.connectTimeout)
+						conn := serverList.getAndConnectServer(false, serverList.service, c.connectTimeout,
+							model.ConnectionRetireSwitch)
 						if nil != conn {
 							log.GetNetworkLogger().Infof("discover server switched to %s", conn.Address)
 						}
@@ -484,6 +584,23 @@ func (c *connectionManager) doSwitchRoutine() {
 	}
 }
 
+// doFailbackRoutine 定期检查连接是否可以切回高优先级的地址
+func (c *connectionManager) doFailbackRoutine() {
+	failbackTicker := time.NewTicker(c.failbackInterval)
+	defer failbackTicker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.GetNetworkLogger().Infof("doFailbackRoutine of connection manager has been terminated")
+			return
+		case <-failbackTicker.C:
+			for _, serverList := range c.serverServices {
+				serverList.tryFailback(c.connectTimeout)
+			}
+		}
+	}
+}
+
 // UpdateServers 更新系统服务
 func (c *connectionManager) UpdateServers(svcEventKey model.ServiceEventKey) {
 	svc := model.ServiceKey{Namespace: svcEventKey.Namespace, Service: svcEventKey.Service}
@@ -0,0 +1,117 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// maxReapInterval 连接存活检查的最大周期
+const maxReapInterval = time.Minute
+
+// jitteredMaxAge 计算新建连接的最大存活时间，叠加随机延迟避免大量客户端同时重建连接
+func (c *connectionManager) jitteredMaxAge() time.Duration {
+	if c.maxConnectionAge <= 0 {
+		return 0
+	}
+	if c.reconnectJitter <= 0 {
+		return c.maxConnectionAge
+	}
+	return c.maxConnectionAge + time.Duration(rand.Int63n(int64(c.reconnectJitter)))
+}
+
+// reapInterval 连接存活检查的周期，未设置最大存活时间及最大空闲时间时返回0
+func (c *connectionManager) reapInterval() time.Duration {
+	shortest := c.maxConnectionAge
+	if c.maxConnectionIdle > 0 && (shortest <= 0 || c.maxConnectionIdle < shortest) {
+		shortest = c.maxConnectionIdle
+	}
+	if shortest <= 0 {
+		return 0
+	}
+	interval := shortest / 10
+	if interval < config.DefaultMinTimingInterval {
+		interval = config.DefaultMinTimingInterval
+	}
+	if interval > maxReapInterval {
+		interval = maxReapInterval
+	}
+	return interval
+}
+
+// doReapRoutine 定期重建超过最大存活时间的连接，释放空闲超时的连接
+func (c *connectionManager) doReapRoutine() {
+	interval := c.reapInterval()
+	if interval <= 0 {
+		return
+	}
+	reapTicker := time.NewTicker(interval)
+	defer reapTicker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.GetNetworkLogger().Infof("doReapRoutine of connection manager has been terminated")
+			return
+		case <-reapTicker.C:
+			for _, serverList := range c.serverServices {
+				serverList.reapConnection(time.Now(), c.connectTimeout)
+			}
+		}
+	}
+}
+
+// reapConnection 检查当前连接，超过最大存活时间时建立新连接后平滑释放旧连接，新连接建立失败时继续使用旧连接；
+// 空闲超时时直接释放，下次请求时重新建立
+func (s *ServerAddressList) reapConnection(now time.Time, timeout time.Duration) {
+	curConn := s.loadCurrentConnection()
+	if !IsAvailableConnection(curConn) {
+		return
+	}
+	if curConn.expired(now) {
+		log.GetNetworkLogger().Infof("connection %v of %s exceeds max age %v, re-establish",
+			curConn.ConnID, s.service, curConn.maxAge)
+		s.getAndConnectServer(true, s.service, timeout, model.ConnectionRetireMaxAge)
+		return
+	}
+	maxIdle := s.manager.maxConnectionIdle
+	if maxIdle > 0 && curConn.idleTime(now) >= maxIdle {
+		log.GetNetworkLogger().Infof("connection %v of %s idle for more than %v, release",
+			curConn.ConnID, s.service, maxIdle)
+		s.reportConnectionAge(curConn, model.ConnectionRetireIdle)
+		curConn.lazyClose(false)
+	}
+}
+
+// reportConnectionAge 上报被替换或者释放的连接的存活时长
+func (s *ServerAddressList) reportConnectionAge(conn *Connection, reason string) {
+	engineValue, ok := s.manager.valueCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	_ = engineValue.(model.Engine).SyncReportStat(model.ConnectionAgeStat, &model.ConnectionAgeGauge{
+		Cluster: string(s.service.ClusterType),
+		Address: conn.Address,
+		Reason:  reason,
+		Age:     conn.Age(),
+	})
+}
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
+)
+
+type fakeConn struct {
+	closed bool
+}
+
+func (f *fakeConn) Close() error {
+	f.closed = true
+	return nil
+}
+
+type fakeCreator struct {
+	conns []*fakeConn
+}
+
+func (f *fakeCreator) Name() string {
+	return "fake"
+}
+
+func (f *fakeCreator) CreateConnection(string, time.Duration, *ClientInfo) (ClosableConn, error) {
+	conn := &fakeConn{}
+	f.conns = append(f.conns, conn)
+	return conn, nil
+}
+
+func TestReapConnection(t *testing.T) {
+	if err := log.ConfigNetworkLogger(log.DefaultLogger, log.CreateDefaultLoggerOptions(
+		filepath.Join(t.TempDir(), log.DefaultNetworkLogRotationPath), log.DefaultBaseLogLevel)); err != nil {
+		t.Fatal(err)
+	}
+	creator := &fakeCreator{}
+	manager := &connectionManager{
+		maxConnectionAge:  time.Minute,
+		maxConnectionIdle: time.Hour,
+		valueCtx:          model.NewValueContext(),
+		creator:           creator,
+	}
+	serverList := &ServerAddressList{
+		service: config.ClusterService{ClusterType: config.BuiltinCluster},
+		manager: manager,
+		endpoints: newEndpointSelector([]*config.ServerEndpointConfig{
+			{Address: "127.0.0.1:8091"}}, time.Hour),
+	}
+	conn, err := serverList.tryGetConnection(time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manager.reapInterval() != 6*time.Second {
+		t.Fatalf("unexpected reap interval %v", manager.reapInterval())
+	}
+
+	// 未超过最大存活时间及最大空闲时间时保留连接
+	serverList.reapConnection(time.Now(), time.Second)
+	if serverList.loadCurrentConnection() != conn || len(creator.conns) != 1 {
+		t.Fatal("expect connection kept")
+	}
+	// 超过最大存活时间时重建连接，旧连接空闲后关闭
+	serverList.reapConnection(time.Now().Add(2*time.Minute), time.Second)
+	if serverList.loadCurrentConnection() == conn || len(creator.conns) != 2 || !creator.conns[0].closed {
+		t.Fatal("expect connection re-established after max age")
+	}
+	// 空闲超时后释放连接，被占用的连接不会被释放
+	current := serverList.loadCurrentConnection()
+	current.maxAge = 0
+	current.acquire("op")
+	serverList.reapConnection(time.Now().Add(2*time.Hour), time.Second)
+	if !IsAvailableConnection(current) {
+		t.Fatal("expect acquired connection not reaped")
+	}
+	// 内置集群为短连接模式，释放时会直接关闭，这里只归还引用计数
+	atomic.AddInt32(&current.ref, -1)
+	serverList.reapConnection(time.Now().Add(2*time.Hour), time.Second)
+	if IsAvailableConnection(current) || !creator.conns[1].closed {
+		t.Fatal("expect idle connection released")
+	}
+}
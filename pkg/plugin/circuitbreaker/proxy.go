@@ -27,18 +27,33 @@ import (
 type Proxy struct {
 	CircuitBreaker
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
-// Stat proxy InstanceCircuitBreaker stat
-func (p *Proxy) CheckResource(res model.Resource) model.CircuitBreakerStatus {
+// Stat proxy InstanceCircuitBreaker stat，插件panic或被禁用时不返回熔断状态，即放通
+func (p *Proxy) CheckResource(res model.Resource) (status model.CircuitBreakerStatus) {
+	if p.guard.Disabled() {
+		return nil
+	}
+	var err error
+	defer p.guard.Recover("CheckResource", &err)
 	return p.CircuitBreaker.CheckResource(res)
 }
 
 // CircuitBreak proxy InstanceCircuitBreaker CircuitBreak
-func (p *Proxy) Report(stat *model.ResourceStat) error {
+func (p *Proxy) Report(stat *model.ResourceStat) (err error) {
+	if p.guard.Disabled() {
+		return nil
+	}
+	defer p.guard.Recover("Report", &err)
 	return p.CircuitBreaker.Report(stat)
 }
 
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
+}
+
 // SetRealPlugin 设置
 func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 	p.CircuitBreaker = plug.(CircuitBreaker)
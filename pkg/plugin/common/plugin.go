@@ -101,6 +101,12 @@ const (
 	OnRateLimitWindowCreated PluginEventType = 0x8008
 	// OnRateLimitWindowDeleted 一个限流规则的限流窗口被删除时触发的事件
 	OnRateLimitWindowDeleted PluginEventType = 0x8009
+	// OnCircuitBreakerStatusChanged 资源的熔断状态变更时触发的事件，事件对象为 *model.CircuitBreakerEvent
+	OnCircuitBreakerStatusChanged PluginEventType = 0x800A
+	// OnCachePreloadProgress 启动时加载持久化缓存的进度变更事件，事件对象为 *model.CachePreloadEvent
+	OnCachePreloadProgress PluginEventType = 0x800B
+	// OnMemoryShedChanged 缓存内存超出预算进入或退出降载模式的事件，事件对象为 *model.MemoryShedEvent
+	OnMemoryShedChanged PluginEventType = 0x800C
 )
 
 // PluginEvent 插件事件
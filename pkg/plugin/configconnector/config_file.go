@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -57,6 +58,10 @@ type ConfigFile struct {
 	Mode model.GetConfigFileRequestMode
 	// 文件持久化配置
 	Persistent model.Persistent
+	// 本次请求使用的鉴权token，为空时使用命名空间级别或者全局配置的token
+	Token string
+	// 配置的发布时间，服务端未返回时为零值
+	ReleaseTime time.Time
 }
 
 func (c *ConfigFile) String() string {
@@ -72,6 +77,21 @@ func (c *ConfigFile) String() string {
 	return bf.String()
 }
 
+// ReleaseTimeLayout 服务端返回的配置发布时间格式
+const ReleaseTimeLayout = "2006-01-02 15:04:05"
+
+// ParseReleaseTime 解析服务端返回的配置发布时间，服务端按本地时区格式化，解析失败时返回零值
+func ParseReleaseTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	releaseTime, err := time.ParseInLocation(ReleaseTimeLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return releaseTime
+}
+
 type ConfigFileTag struct {
 	Key   string
 	Value string
@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// PanicGuarded 需要进行panic隔离的插件proxy实现该接口，由插件管理器在初始化时注入 PanicGuard
+type PanicGuarded interface {
+	SetPanicGuard(guard *PanicGuard)
+}
+
+// PanicGuard 插件调用的panic隔离，将panic转换为 SDKError，按插件统计panic次数，
+// 并在统计窗口内panic次数达到阈值后禁用插件
+type PanicGuard struct {
+	pluginType common.Type
+	pluginName string
+	engine     model.Engine
+	threshold  int
+	window     time.Duration
+
+	mutex      sync.Mutex
+	panicTimes []time.Time
+	total      uint64
+	disabled   uint32
+}
+
+// NewPanicGuard 创建插件的panic隔离
+func NewPanicGuard(plug Plugin, cfg config.Configuration, engine model.Engine) *PanicGuard {
+	guard := &PanicGuard{
+		pluginType: plug.Type(),
+		pluginName: plug.Name(),
+		engine:     engine,
+	}
+	if cfg != nil {
+		guard.threshold = cfg.GetGlobal().GetAPI().GetPluginPanicThreshold()
+		guard.window = cfg.GetGlobal().GetAPI().GetPluginPanicWindow()
+	}
+	return guard
+}
+
+// Recover 在插件调用处通过 defer 直接调用，将panic转换为错误写入 err，guard 为 nil 时同样进行隔离
+func (g *PanicGuard) Recover(method string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	pluginType, pluginName := common.TypePluginBase, ""
+	if g != nil {
+		pluginType, pluginName = g.pluginType, g.pluginName
+	}
+	log.GetBaseLogger().Errorf("[Plugin] plugin %v:%s panic in %s: %v\n%s",
+		pluginType, pluginName, method, r, debug.Stack())
+	if err != nil {
+		*err = model.NewSDKError(model.ErrCodePluginError, nil,
+			"plugin %v:%s panic in %s: %v", pluginType, pluginName, method, r)
+	}
+	if g != nil {
+		g.onPanic(method)
+	}
+}
+
+func (g *PanicGuard) onPanic(method string) {
+	atomic.AddUint64(&g.total, 1)
+	disabled := false
+	if g.threshold > 0 {
+		now := time.Now()
+		g.mutex.Lock()
+		g.panicTimes = append(g.panicTimes, now)
+		expired := 0
+		for expired < len(g.panicTimes) && now.Sub(g.panicTimes[expired]) > g.window {
+			expired++
+		}
+		g.panicTimes = g.panicTimes[expired:]
+		if len(g.panicTimes) >= g.threshold && atomic.CompareAndSwapUint32(&g.disabled, 0, 1) {
+			log.GetBaseLogger().Errorf("[Plugin] plugin %v:%s disabled after %d panics within %v",
+				g.pluginType, g.pluginName, len(g.panicTimes), g.window)
+		}
+		g.mutex.Unlock()
+		disabled = g.Disabled()
+	}
+	// 统计上报插件自身panic时不再上报，避免递归
+	if g.engine == nil || g.pluginType == common.TypeStatReporter {
+		return
+	}
+	_ = g.engine.SyncReportStat(model.PluginPanicStat, &model.PluginPanicGauge{
+		PluginType: g.pluginType.String(),
+		PluginName: g.pluginName,
+		Method:     method,
+		Disabled:   disabled,
+	})
+}
+
+// Disabled 插件是否因为频繁panic已被禁用
+func (g *PanicGuard) Disabled() bool {
+	return g != nil && atomic.LoadUint32(&g.disabled) == 1
+}
+
+// PanicCount 插件累计的panic次数
+func (g *PanicGuard) PanicCount() uint64 {
+	if g == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&g.total)
+}
+
+// DisabledError 插件被禁用时返回的错误
+func (g *PanicGuard) DisabledError() error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"plugin %v:%s disabled after repeated panics", g.pluginType, g.pluginName)
+}
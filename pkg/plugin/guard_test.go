@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+func TestPanicGuard(t *testing.T) {
+	guard := &PanicGuard{pluginType: common.TypeLoadBalancer, pluginName: "test", threshold: 2, window: time.Minute}
+	invoke := func() (err error) {
+		defer guard.Recover("ChooseInstance", &err)
+		panic("boom")
+	}
+	err := invoke()
+	sdkErr, ok := err.(model.SDKError)
+	if !ok || sdkErr.ErrorCode() != model.ErrCodePluginError {
+		t.Fatalf("expect plugin error, got %v", err)
+	}
+	if guard.Disabled() {
+		t.Fatal("expect plugin enabled before reaching threshold")
+	}
+	_ = invoke()
+	if !guard.Disabled() || guard.PanicCount() != 2 {
+		t.Fatalf("expect plugin disabled after 2 panics, count %d", guard.PanicCount())
+	}
+
+	var nilGuard *PanicGuard
+	nilInvoke := func() (err error) {
+		defer nilGuard.Recover("ChooseInstance", &err)
+		panic("boom")
+	}
+	if err := nilInvoke(); err == nil || nilGuard.Disabled() {
+		t.Fatal("expect nil guard recovers panic")
+	}
+}
@@ -29,6 +29,7 @@ import (
 type Proxy struct {
 	HealthChecker
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
 // SetRealPlugin 设置
@@ -38,9 +39,18 @@ func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 }
 
 // DetectInstance proxy HealthChecker DetectInstance
-func (p *Proxy) DetectInstance(inst model.Instance, rule *fault_tolerance.FaultDetectRule) (DetectResult, error) {
-	result, err := p.HealthChecker.DetectInstance(inst, rule)
-	return result, err
+func (p *Proxy) DetectInstance(inst model.Instance,
+	rule *fault_tolerance.FaultDetectRule) (result DetectResult, err error) {
+	if p.guard.Disabled() {
+		return nil, p.guard.DisabledError()
+	}
+	defer p.guard.Recover("DetectInstance", &err)
+	return p.HealthChecker.DetectInstance(inst, rule)
+}
+
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
 }
 
 // Protocol .
@@ -27,6 +27,7 @@ import (
 type Proxy struct {
 	LoadBalancer
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
 // SetRealPlugin 设置
@@ -42,11 +43,19 @@ type SelectStatus struct {
 }
 
 // ChooseInstance proxy LoadBalancer ChooseInstance
-func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error) {
+func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (result model.Instance, err error) {
+	if p.guard.Disabled() {
+		return nil, p.guard.DisabledError()
+	}
+	defer p.guard.Recover("ChooseInstance", &err)
 	// 包括处于半开的实例
 	criteria.Cluster.IncludeHalfOpen = true
-	result, err := p.LoadBalancer.ChooseInstance(criteria, instances)
-	return result, err
+	return p.LoadBalancer.ChooseInstance(criteria, instances)
+}
+
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
 }
 
 // init 注册proxy
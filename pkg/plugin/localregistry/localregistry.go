@@ -20,6 +20,7 @@ package localregistry
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -87,6 +88,9 @@ type InstancesRegistry interface {
 	// 如果已经加载过了，那就直接进行notify
 	// 否则，加载完毕后调用notify函数
 	LoadInstances(svcKey *model.ServiceKey) (*common.Notifier, error)
+	// RefreshInstances 实例缓存超过 maxStaleness 未经服务端确认时，返回等待下一次服务端应答的通知器，
+	// 缓存足够新鲜时返回nil
+	RefreshInstances(svcKey *model.ServiceKey, maxStaleness time.Duration) (*common.Notifier, error)
 	// UpdateInstances 批量更新服务实例状态，properties存放的是状态值，当前支持2个key
 	// 1. ReadyToServe: 故障熔断标识，true or false
 	// 2. DynamicWeight：动态权重值
@@ -95,6 +99,10 @@ type InstancesRegistry interface {
 	PersistMessage(file string, msg proto.Message) error
 	// LoadPersistedMessage 从文件中加载PB缓存
 	LoadPersistedMessage(file string, msg proto.Message) error
+	// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件
+	ExportSnapshot(path string) error
+	// ImportSnapshot 从快照文件导入缓存，本地已存在的资源不会被覆盖
+	ImportSnapshot(path string) error
 }
 
 // InstancesFilter 用于在向缓存获取实例时进行过滤
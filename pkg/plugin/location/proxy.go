@@ -26,6 +26,7 @@ import (
 type Proxy struct {
 	Provider
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
 // SetRealPlugin 设置
@@ -35,10 +36,19 @@ func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 }
 
 // GetLocation 获取实例地理位置信息
-func (p *Proxy) GetLocation() (*model.Location, error) {
+func (p *Proxy) GetLocation() (location *model.Location, err error) {
+	if p.guard.Disabled() {
+		return nil, p.guard.DisabledError()
+	}
+	defer p.guard.Recover("GetLocation", &err)
 	return p.Provider.GetLocation()
 }
 
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeLocationProvider, &Proxy{})
@@ -213,6 +213,9 @@ func (m *manager) InitPlugins(
 			// }
 			proxy := createPluginProxy(typ)
 			proxy.SetRealPlugin(plug, engine)
+			if guarded, ok := proxy.(PanicGuarded); ok {
+				guarded.SetPanicGuard(NewPanicGuard(plug, ctx.Config, engine))
+			}
 			if !plug.IsEnable(ctx.Config) {
 				continue
 			}
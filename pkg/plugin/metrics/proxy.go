@@ -27,6 +27,7 @@ import (
 type Proxy struct {
 	StatReporter
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
 // SetRealPlugin 设置
@@ -35,6 +36,32 @@ func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 	p.engine = engine
 }
 
+// ReportStat proxy StatReporter ReportStat，插件因频繁panic被禁用后丢弃统计数据
+func (p *Proxy) ReportStat(metricType model.MetricType, gauge model.InstanceGauge) (err error) {
+	if p.guard.Disabled() {
+		return nil
+	}
+	defer p.guard.Recover("ReportStat", &err)
+	return p.StatReporter.ReportStat(metricType, gauge)
+}
+
+// Flush 立即上报缓存的统计数据，插件未实现 Flusher 时直接返回
+func (p *Proxy) Flush() (err error) {
+	if p.guard.Disabled() {
+		return nil
+	}
+	defer p.guard.Recover("Flush", &err)
+	if flusher, ok := p.StatReporter.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeStatReporter, &Proxy{})
@@ -40,3 +40,8 @@ type StatReporter interface {
 func init() {
 	plugin.RegisterPluginInterface(common.TypeStatReporter, new(StatReporter))
 }
+
+// Flusher 可选实现，将缓存中尚未上报的统计数据立即上报，在SDK下线排空时调用
+type Flusher interface {
+	Flush() error
+}
@@ -45,14 +45,19 @@ import (
 	_ "github.com/polarismesh/polaris-go/plugin/location"
 	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
 	_ "github.com/polarismesh/polaris-go/plugin/metrics/prometheus"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/leakybucket"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/slidingcounter"
+	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/slidinglog"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/unirate"
 	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/canary"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/dstmeta"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/filteronly"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/graybucket"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/nearbybase"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/rulebase"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/sessionaffinity"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/setdivision"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/zeroprotect"
 	_ "github.com/polarismesh/polaris-go/plugin/weightadjuster/ratedelay"
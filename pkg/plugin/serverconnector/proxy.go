@@ -71,6 +71,18 @@ func (p *Proxy) UpdateServers(key *model.ServiceEventKey) error {
 	return err
 }
 
+// QueryInstances proxy ServerConnector QueryInstances
+func (p *Proxy) QueryInstances(req *model.QueryInstancesRequest) (model.ServiceInstances, error) {
+	result, err := p.ServerConnector.QueryInstances(req)
+	return result, err
+}
+
+// GetServiceContract proxy ServerConnector GetServiceContract
+func (p *Proxy) GetServiceContract(req *model.GetServiceContractRequest) (*model.ServiceContract, error) {
+	result, err := p.ServerConnector.GetServiceContract(req)
+	return result, err
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeServerConnector, &Proxy{})
@@ -88,6 +88,12 @@ type ServerConnector interface {
 	// UpdateServers 更新服务端地址
 	// 异常场景：当地址列表为空，或者地址全部连接失败，则返回error，调用者需进行重试
 	UpdateServers(key *model.ServiceEventKey) error
+	// QueryInstances 直接向服务端查询服务实例，不订阅服务，也不写入本地缓存
+	// 异常场景：当服务端不可用或者查询失败，则返回error
+	QueryInstances(req *model.QueryInstancesRequest) (model.ServiceInstances, error)
+	// GetServiceContract 向服务端查询被调服务上报的服务契约，服务未上报契约时返回nil
+	// 异常场景：当服务端不可用或者查询失败，则返回error
+	GetServiceContract(req *model.GetServiceContractRequest) (*model.ServiceContract, error)
 }
 
 // 初始化
@@ -0,0 +1,193 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package servicerouter
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// IndependentRouter 可选接口，声明路由插件在本次请求中是独立的：过滤结果只依赖服务实例及请求信息，
+// 等价于对输入集群取交集，且不修改RouteInfo。相邻的独立路由插件在开启并行执行时可同时执行后对结果取交集
+type IndependentRouter interface {
+	// IsIndependent 本次请求中路由插件是否独立
+	IsIndependent(routeInfo *RouteInfo) bool
+}
+
+// isIndependentRouter 判断路由插件在本次请求中是否独立
+func isIndependentRouter(router ServiceRouter, routeInfo *RouteInfo) bool {
+	independent, ok := router.(IndependentRouter)
+	return ok && independent.IsIndependent(routeInfo)
+}
+
+// collectIndependentRouters 从start开始收集相邻的已启用的独立路由插件，返回插件列表以及最后处理的下标
+func collectIndependentRouters(routers []ServiceRouter, start int, routeInfo *RouteInfo,
+	svcClusters model.ServiceClusters) ([]ServiceRouter, int) {
+	group := []ServiceRouter{routers[start]}
+	last := start
+	for i := start + 1; i < len(routers); i++ {
+		router := routers[i]
+		if !routeInfo.IsRouterEnable(router.ID()) || !router.Enable(routeInfo, svcClusters) {
+			last = i
+			continue
+		}
+		if !isIndependentRouter(router, routeInfo) {
+			break
+		}
+		group = append(group, router)
+		last = i
+	}
+	return group, last
+}
+
+// processIndependentRouters 并行执行独立路由插件，并对各插件输出的实例取交集，
+// 交集为空时退化为顺序执行，以保持各插件在无可用实例时的降级逻辑
+func processIndependentRouters(group []ServiceRouter, routeInfo *RouteInfo,
+	svcClusters model.ServiceClusters, cluster *model.Cluster) (*RouteResult, error) {
+	// 预先构建输入集群的索引，避免并发构建
+	cluster.GetClusterValue()
+	results := make([]*RouteResult, len(group))
+	errs := make([]error, len(group))
+	wg := &sync.WaitGroup{}
+	for i := range group {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			results[idx], errs[idx] = group[idx].GetFilteredInstances(routeInfo, svcClusters, cluster)
+		}(i)
+	}
+	wg.Wait()
+	for i := range group {
+		if errs[i] != nil {
+			recycleRouteResults(results, cluster, nil)
+			return nil, errs[i]
+		}
+		if results[i] != nil && nil != results[i].RedirectDestService {
+			recycleRouteResults(results, cluster, results[i])
+			return results[i], nil
+		}
+	}
+	output := intersectRouteResults(results, svcClusters, cluster)
+	if output == nil {
+		log.GetBaseLogger().Debugf("[Router] independent routers of %s/%s have no common instances, "+
+			"fall back to sequential", routeInfo.DestService.GetNamespace(), routeInfo.DestService.GetService())
+		recycleRouteResults(results, cluster, nil)
+		return processRoutersSequentially(group, routeInfo, cluster)
+	}
+	result := results[0]
+	for _, r := range results {
+		if r.OutputCluster == output {
+			result = r
+			break
+		}
+	}
+	recycleRouteResults(results, cluster, result)
+	result.OutputCluster = output
+	return result, nil
+}
+
+// intersectRouteResults 对各路由结果的实例取交集，交集为空时返回nil。
+// 交集与某个路由结果一致时直接复用该结果的集群，否则基于交集实例构建新的集群
+func intersectRouteResults(results []*RouteResult, svcClusters model.ServiceClusters,
+	cluster *model.Cluster) *model.Cluster {
+	var ordered []model.Instance
+	hits := make(map[string]int)
+	sizes := make([]int, len(results))
+	hasLimitedInstances := false
+	for i, result := range results {
+		if result.OutputCluster.HasLimitedInstances {
+			hasLimitedInstances = true
+		}
+		instances, _ := result.OutputCluster.GetAllInstances()
+		sizes[i] = len(instances)
+		for _, instance := range instances {
+			if i == 0 {
+				ordered = append(ordered, instance)
+				hits[instance.GetId()] = 1
+				continue
+			}
+			if hit, ok := hits[instance.GetId()]; ok && hit == i {
+				hits[instance.GetId()] = i + 1
+			}
+		}
+	}
+	subset := make([]model.Instance, 0, len(ordered))
+	for _, instance := range ordered {
+		if hits[instance.GetId()] == len(results) {
+			subset = append(subset, instance)
+		}
+	}
+	if len(subset) == 0 {
+		return nil
+	}
+	for i, result := range results {
+		if sizes[i] == len(subset) && result.OutputCluster.HasLimitedInstances == hasLimitedInstances {
+			return result.OutputCluster
+		}
+	}
+	svcInstances := svcClusters.GetServiceInstances()
+	subsetClusters := model.NewServiceClusters(model.NewDefaultServiceInstancesWithRegistryValue(model.ServiceInfo{
+		Service:   svcInstances.GetService(),
+		Namespace: svcInstances.GetNamespace(),
+		Metadata:  svcInstances.GetMetadata(),
+	}, svcInstances, subset))
+	output := model.NewCluster(subsetClusters, cluster)
+	output.HasLimitedInstances = hasLimitedInstances
+	return output
+}
+
+// recycleRouteResults 回收并行执行产生的路由结果及集群，保留的结果及其集群不回收
+func recycleRouteResults(results []*RouteResult, cluster *model.Cluster, keep *RouteResult) {
+	for _, result := range results {
+		if result == nil || result == keep {
+			continue
+		}
+		if result.OutputCluster != nil && result.OutputCluster != cluster &&
+			(keep == nil || result.OutputCluster != keep.OutputCluster) {
+			result.OutputCluster.PoolPut()
+		}
+		GetRouteResultPool().Put(result)
+	}
+}
+
+// processRoutersSequentially 顺序执行路由插件，输入集群由调用方回收
+func processRoutersSequentially(group []ServiceRouter, routeInfo *RouteInfo,
+	cluster *model.Cluster) (*RouteResult, error) {
+	var result *RouteResult
+	current := cluster
+	for _, router := range group {
+		if nil != result {
+			GetRouteResultPool().Put(result)
+		}
+		var err error
+		result, err = router.GetFilteredInstances(routeInfo, current.GetClusters(), current)
+		if result != nil && result.OutputCluster != current && current != cluster {
+			current.PoolPut()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if nil != result.RedirectDestService {
+			return result, nil
+		}
+		current = result.OutputCluster
+	}
+	return result, nil
+}
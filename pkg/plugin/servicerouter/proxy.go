@@ -29,6 +29,7 @@ import (
 type Proxy struct {
 	ServiceRouter
 	engine model.Engine
+	guard  *plugin.PanicGuard
 }
 
 // RouteGauge 路由调用统计数据
@@ -103,15 +104,31 @@ func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 	p.engine = engine
 }
 
-// GetFilteredInstances proxy ServiceRouter GetFilteredInstances
+// GetFilteredInstances proxy ServiceRouter GetFilteredInstances，插件因频繁panic被禁用后直接透传
 func (p *Proxy) GetFilteredInstances(
 	routeInfo *RouteInfo, serviceClusters model.ServiceClusters, withinCluster *model.Cluster) (*RouteResult, error) {
-	result, err := p.ServiceRouter.GetFilteredInstances(routeInfo, serviceClusters, withinCluster)
+	if p.guard.Disabled() {
+		result := PoolGetRouteResult(p.engine.GetContext())
+		result.OutputCluster = withinCluster
+		return result, nil
+	}
+	result, err := p.doGetFilteredInstances(routeInfo, serviceClusters, withinCluster)
 	p.reportRouteStat(routeInfo, model.GetErrorCodeFromError(err),
 		withinCluster.GetClusters().GetServiceInstances(), result)
 	return result, err
 }
 
+func (p *Proxy) doGetFilteredInstances(routeInfo *RouteInfo, serviceClusters model.ServiceClusters,
+	withinCluster *model.Cluster) (result *RouteResult, err error) {
+	defer p.guard.Recover("GetFilteredInstances", &err)
+	return p.ServiceRouter.GetFilteredInstances(routeInfo, serviceClusters, withinCluster)
+}
+
+// SetPanicGuard 设置插件的panic隔离
+func (p *Proxy) SetPanicGuard(guard *plugin.PanicGuard) {
+	p.guard = guard
+}
+
 // 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeServiceRouter, &Proxy{})
@@ -69,6 +69,8 @@ type RouteInfo struct {
 	MatchRuleType RuleType
 	// 规则路由失败降级类型
 	FailOverType *FailOverType
+	// 是否并行执行相邻的独立路由插件
+	EnableParallel bool
 }
 
 // Init 初始化map
@@ -91,6 +93,7 @@ func (r *RouteInfo) ClearValue() {
 	r.FilterOnlyRouter = nil
 	r.MatchRuleType = UnknownRule
 	r.ignoreFilterOnlyOnEndChain = false
+	r.EnableParallel = false
 	for k := range r.chainEnables {
 		r.chainEnables[k] = true
 	}
@@ -156,6 +159,8 @@ const (
 	LimitedNoCanary RouteStatus = 9
 	// DegradeToFilterOnly 降级使用filterOnly
 	DegradeToFilterOnly RouteStatus = 10
+	// DegradeToCampus 机架级别就近降级到园区
+	DegradeToCampus RouteStatus = 11
 )
 
 var routeStatusMap = map[RouteStatus]string{
@@ -170,6 +175,7 @@ var routeStatusMap = map[RouteStatus]string{
 	LimitedCanary:           "LimitedCanary",
 	LimitedNoCanary:         "LimitedNoCanary",
 	DegradeToFilterOnly:     "DegradeToFilterOnly",
+	DegradeToCampus:         "DegradeToCampus",
 }
 
 // String 转换为字符串
@@ -59,15 +59,24 @@ func processServiceRouters(ctx model.ValueContext, routers []ServiceRouter, rout
 	svcClusters model.ServiceClusters, cluster *model.Cluster) (*RouteResult, model.SDKError) {
 	var result *RouteResult
 	var err error
-	for _, router := range routers {
+	for i := 0; i < len(routers); i++ {
+		router := routers[i]
 		if !routeInfo.IsRouterEnable(router.ID()) || !router.Enable(routeInfo, svcClusters) {
 			continue
 		}
+		var group []ServiceRouter
+		if routeInfo.EnableParallel && isIndependentRouter(router, routeInfo) {
+			group, i = collectIndependentRouters(routers, i, routeInfo, svcClusters)
+		}
 		if nil != result {
 			// 回收，下一步即将被新值替换
 			GetRouteResultPool().Put(result)
 		}
-		result, err = router.GetFilteredInstances(routeInfo, svcClusters, cluster)
+		if len(group) > 1 {
+			result, err = processIndependentRouters(group, routeInfo, svcClusters, cluster)
+		} else {
+			result, err = router.GetFilteredInstances(routeInfo, svcClusters, cluster)
+		}
 		// 判断result.OutputCluster是否是同一个地址，如果是同一个地址不要回收
 		if result != nil && result.OutputCluster != cluster {
 			cluster.PoolPut()
@@ -80,6 +89,8 @@ func processServiceRouters(ctx model.ValueContext, routers []ServiceRouter, rout
 			return result, nil
 		}
 		cluster = result.OutputCluster
+		// 路由插件可能基于实例子集构建输出集群，后续插件需要基于该子集继续过滤
+		svcClusters = cluster.GetClusters()
 	}
 	if !routeInfo.ignoreFilterOnlyOnEndChain {
 		// 需要执行一遍全死全活
@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package profiling 为SDK的常驻协程打上pprof标签，并提供SDK专属的profile，
+// 使得CPU等profile可以按插件及任务类型归集SDK的开销
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/pprof"
+	"strconv"
+)
+
+const (
+	// LabelPlugin 协程所属的插件或者模块
+	LabelPlugin = "polaris_plugin"
+	// LabelTask 协程执行的任务类型
+	LabelTask = "polaris_task"
+	// GoroutineProfileName 记录存活的SDK常驻协程及其启动位置的profile
+	GoroutineProfileName = "polaris-go.goroutines"
+	// ProfilePathPrefix SDK专属profile在pprof mux上的路径前缀
+	ProfilePathPrefix = "/debug/pprof/"
+)
+
+var goroutineProfile = pprof.NewProfile(GoroutineProfileName)
+
+// goroutineKey 常驻协程在profile中的唯一标识
+type goroutineKey struct {
+	plugin string
+	task   string
+}
+
+// Go 启动SDK常驻协程，协程带有插件及任务类型的pprof标签，存活期间记录在SDK专属的协程profile中
+func Go(plugin string, task string, fn func()) {
+	key := &goroutineKey{plugin: plugin, task: task}
+	// 记录启动协程的调用栈，跳过Go自身
+	goroutineProfile.Add(key, 1)
+	go func() {
+		defer goroutineProfile.Remove(key)
+		pprof.Do(context.Background(), pprof.Labels(LabelPlugin, plugin, LabelTask, task), func(context.Context) {
+			fn()
+		})
+	}()
+}
+
+// GoroutineCount 存活的SDK常驻协程数
+func GoroutineCount() int {
+	return goroutineProfile.Count()
+}
+
+// RegisterHandlers 将SDK专属的profile注册到已有的pprof mux上
+func RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(ProfilePathPrefix+GoroutineProfileName, serveGoroutineProfile)
+}
+
+// serveGoroutineProfile 输出SDK常驻协程profile，debug参数的含义与net/http/pprof一致
+func serveGoroutineProfile(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	debug, _ := strconv.Atoi(r.FormValue("debug"))
+	if debug != 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, GoroutineProfileName))
+	}
+	if err := goroutineProfile.WriteTo(w, debug); err != nil {
+		http.Error(w, fmt.Sprintf("fail to write profile %s: %v", GoroutineProfileName, err),
+			http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package profiling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoTrackedInProfile(t *testing.T) {
+	base := GoroutineCount()
+	stop := make(chan struct{})
+	started := make(chan struct{})
+	Go("test", "block", func() {
+		close(started)
+		<-stop
+	})
+	<-started
+	if GoroutineCount() != base+1 {
+		t.Fatalf("expect %d goroutines tracked, got %d", base+1, GoroutineCount())
+	}
+	mux := http.NewServeMux()
+	RegisterHandlers(mux)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ProfilePathPrefix+GoroutineProfileName+"?debug=1", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "TestGoTrackedInProfile") {
+		t.Fatalf("expect launch site in profile, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	close(stop)
+	deadline := time.Now().Add(time.Second)
+	for GoroutineCount() != base {
+		if time.Now().After(deadline) {
+			t.Fatalf("expect goroutine removed from profile after exit, got %d", GoroutineCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
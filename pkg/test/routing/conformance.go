@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/plugin/servicerouter/rulebase"
+)
+
+// NewRuleBasedRouter 基于默认配置创建规则路由插件，作为一致性测试的参考实现
+func NewRuleBasedRouter() (servicerouter.ServiceRouter, error) {
+	cfg := config.NewDefaultConfiguration(nil)
+	router := &rulebase.RuleBasedInstancesFilter{}
+	if err := router.Init(&plugin.InitContext{Config: cfg, ValueCtx: model.NewValueContext()}); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// Evaluate 使用路由插件执行测试向量，返回排序后的选中实例ID
+func Evaluate(router servicerouter.ServiceRouter, c *Case) ([]string, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	instancesResp, err := c.buildInstances()
+	if err != nil {
+		return nil, err
+	}
+	svcInstances := pb.NewServiceInstancesInProto(instancesResp, func(string) local.InstanceLocalValue {
+		return local.NewInstanceLocalValue()
+	}, &pb.SvcPluginValues{}, nil)
+	routeInfo := &servicerouter.RouteInfo{
+		DestService: c.Destination.toServiceInfo(),
+	}
+	if c.Source != nil {
+		routeInfo.SourceService = c.Source.toServiceInfo()
+	}
+	if routeInfo.DestRouteRule, err = buildRule(c.Destination, c.DestRouting); err != nil {
+		return nil, err
+	}
+	if routeInfo.SourceRouteRule, err = buildRule(c.Source, c.SourceRouting); err != nil {
+		return nil, err
+	}
+	switch c.FailOver {
+	case FailOverAll:
+		failOver := servicerouter.FailOverAll
+		routeInfo.FailOverType = &failOver
+	case FailOverNone:
+		failOver := servicerouter.FailOverNone
+		routeInfo.FailOverType = &failOver
+	}
+	clusters := svcInstances.GetServiceClusters()
+	result, err := router.GetFilteredInstances(routeInfo, clusters, model.NewCluster(clusters, nil))
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.OutputCluster == nil {
+		return nil, fmt.Errorf("router %s returns empty cluster", router.Name())
+	}
+	instances, _ := result.OutputCluster.GetInstances()
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.GetId())
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// buildRule 构建并校验路由规则，规则为空时返回 nil
+func buildRule(svc *Service, raw []byte) (model.ServiceRule, error) {
+	resp, err := buildRouting(svc, raw)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	rule := pb.NewServiceRuleInProto(resp)
+	if err = rule.ValidateAndBuildCache(); err != nil {
+		return nil, fmt.Errorf("invalid routing of %s/%s: %w", svc.Namespace, svc.Service, err)
+	}
+	return rule, nil
+}
+
+// Run 以子测试的形式执行测试套件中的所有用例
+func Run(t *testing.T, router servicerouter.ServiceRouter, suites ...*Suite) {
+	for _, suite := range suites {
+		suite := suite
+		t.Run(suite.Name, func(t *testing.T) {
+			for _, c := range suite.Cases {
+				c := c
+				t.Run(c.Name, func(t *testing.T) {
+					actual, err := Evaluate(router, c)
+					if err != nil {
+						t.Fatalf("evaluate error: %v", err)
+					}
+					expected := append([]string(nil), c.Expected...)
+					sort.Strings(expected)
+					if strings.Join(actual, ",") != strings.Join(expected, ",") {
+						t.Errorf("selected instances mismatch, expected %v, actual %v", expected, actual)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package routing
+
+import (
+	"testing"
+)
+
+// TestRuleBasedRouterConformance 规则路由插件需要通过所有测试向量
+func TestRuleBasedRouterConformance(t *testing.T) {
+	suites, err := LoadSuites("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suites) == 0 {
+		t.Fatal("no routing suite found")
+	}
+	router, err := NewRuleBasedRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, router, suites...)
+}
@@ -0,0 +1,199 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package routing 提供路由规则一致性测试套件，通过规则以及请求标签的测试向量，
+// 校验路由插件选出的实例集合是否符合预期，便于自定义路由插件以及多语言 SDK 之间保持一致的路由语义
+package routing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/golang/protobuf/jsonpb"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// FailOverAll 规则匹配失败时返回全部实例
+	FailOverAll = "all"
+	// FailOverNone 规则匹配失败时返回空实例
+	FailOverNone = "none"
+)
+
+// Suite 一组路由测试向量
+type Suite struct {
+	// Name 测试套件名，默认为文件名
+	Name string `json:"name"`
+	// Cases 测试用例
+	Cases []*Case `json:"cases"`
+}
+
+// Service 测试向量中的服务描述
+type Service struct {
+	// Namespace 命名空间
+	Namespace string `json:"namespace"`
+	// Service 服务名
+	Service string `json:"service"`
+	// Metadata 服务元数据，对于主调服务即为请求标签
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Case 单个路由测试向量
+type Case struct {
+	// Name 用例名
+	Name string `json:"name"`
+	// Source 主调服务及请求标签，可为空
+	Source *Service `json:"source,omitempty"`
+	// Destination 被调服务
+	Destination *Service `json:"destination"`
+	// Instances 被调服务实例，格式与北极星服务端下发的 Instance 一致
+	Instances []json.RawMessage `json:"instances"`
+	// DestRouting 被调服务的入规则，格式与北极星服务端下发的 Routing 一致
+	DestRouting json.RawMessage `json:"destRouting,omitempty"`
+	// SourceRouting 主调服务的出规则，格式与北极星服务端下发的 Routing 一致
+	SourceRouting json.RawMessage `json:"sourceRouting,omitempty"`
+	// FailOver 规则匹配失败后的降级方式，取值为 all 或 none，为空则使用插件配置
+	FailOver string `json:"failOver,omitempty"`
+	// Expected 期望选中的实例ID
+	Expected []string `json:"expected"`
+}
+
+// LoadSuite 从 JSON 文件中加载测试向量
+func LoadSuite(path string) (*Suite, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	suite := &Suite{}
+	if err = json.Unmarshal(content, suite); err != nil {
+		return nil, fmt.Errorf("fail to unmarshal routing suite %s: %w", path, err)
+	}
+	if len(suite.Name) == 0 {
+		suite.Name = filepath.Base(path)
+	}
+	for i, c := range suite.Cases {
+		if err = c.Validate(); err != nil {
+			return nil, fmt.Errorf("routing suite %s, case %d invalid: %w", path, i, err)
+		}
+	}
+	return suite, nil
+}
+
+// LoadSuites 加载目录下所有 .json 结尾的测试向量，按文件名排序
+func LoadSuites(dir string) ([]*Suite, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	suites := make([]*Suite, 0, len(files))
+	for _, file := range files {
+		suite, err := LoadSuite(file)
+		if err != nil {
+			return nil, err
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+// Validate 校验测试向量
+func (c *Case) Validate() error {
+	if len(c.Name) == 0 {
+		return fmt.Errorf("name is required")
+	}
+	if c.Destination == nil || len(c.Destination.Namespace) == 0 || len(c.Destination.Service) == 0 {
+		return fmt.Errorf("destination namespace and service are required")
+	}
+	switch c.FailOver {
+	case "", FailOverAll, FailOverNone:
+	default:
+		return fmt.Errorf("failOver must be %s or %s", FailOverAll, FailOverNone)
+	}
+	return nil
+}
+
+// buildInstances 构建被调服务的实例应答
+func (c *Case) buildInstances() (*apiservice.DiscoverResponse, error) {
+	resp := &apiservice.DiscoverResponse{
+		Code:    wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type:    apiservice.DiscoverResponse_INSTANCE,
+		Service: c.Destination.toProto(),
+	}
+	for i, raw := range c.Instances {
+		instance := &apiservice.Instance{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(raw), instance); err != nil {
+			return nil, fmt.Errorf("fail to unmarshal instance %d: %w", i, err)
+		}
+		if instance.GetHealthy() == nil {
+			instance.Healthy = wrapperspb.Bool(true)
+		}
+		if instance.GetWeight() == nil {
+			instance.Weight = wrapperspb.UInt32(100)
+		}
+		resp.Instances = append(resp.Instances, instance)
+	}
+	return resp, nil
+}
+
+// buildRouting 构建路由规则应答，规则为空时返回 nil
+func buildRouting(svc *Service, raw json.RawMessage) (*apiservice.DiscoverResponse, error) {
+	if len(raw) == 0 || svc == nil {
+		return nil, nil
+	}
+	routing := &apitraffic.Routing{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(raw), routing); err != nil {
+		return nil, fmt.Errorf("fail to unmarshal routing of %s/%s: %w", svc.Namespace, svc.Service, err)
+	}
+	routing.Namespace = wrapperspb.String(svc.Namespace)
+	routing.Service = wrapperspb.String(svc.Service)
+	return &apiservice.DiscoverResponse{
+		Code:    wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type:    apiservice.DiscoverResponse_ROUTING,
+		Service: svc.toProto(),
+		Routing: routing,
+	}, nil
+}
+
+func (s *Service) toProto() *apiservice.Service {
+	return &apiservice.Service{
+		Namespace: wrapperspb.String(s.Namespace),
+		Name:      wrapperspb.String(s.Service),
+		Metadata:  s.Metadata,
+	}
+}
+
+// toServiceInfo 转换为路由插件使用的服务信息
+func (s *Service) toServiceInfo() *model.ServiceInfo {
+	if s == nil {
+		return nil
+	}
+	return &model.ServiceInfo{
+		Namespace: s.Namespace,
+		Service:   s.Service,
+		Metadata:  s.Metadata,
+	}
+}
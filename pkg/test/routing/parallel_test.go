@@ -0,0 +1,131 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/plugin/servicerouter/dstmeta"
+	"github.com/polarismesh/polaris-go/plugin/servicerouter/filteronly"
+	"github.com/polarismesh/polaris-go/plugin/servicerouter/graybucket"
+)
+
+// TestParallelIndependentRouters 测试并行执行独立路由插件的结果与顺序执行一致
+func TestParallelIndependentRouters(t *testing.T) {
+	cfg := config.NewDefaultConfiguration(nil)
+	rule := &graybucket.BucketRule{Service: "callee", Label: "uid", Percent: 50,
+		Metadata: map[string]string{"version": "v2"}}
+	if err := cfg.GetConsumer().GetServiceRouter().SetPluginConfig(config.DefaultServiceRouterGrayBucket,
+		&graybucket.Config{Rules: []*graybucket.BucketRule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	manager := plugin.NewPluginManager()
+	if err := manager.InitPlugins(plugin.InitContext{Config: cfg}, nil, nil, func() error {
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	valueCtx := model.NewValueContext()
+	valueCtx.SetValue(model.ContextKeyPlugins, manager)
+	initCtx := &plugin.InitContext{Config: cfg, ValueCtx: valueCtx}
+	routers := []servicerouter.ServiceRouter{&dstmeta.InstancesFilter{}, &graybucket.GrayBucketRouter{}}
+	filterOnly := &filteronly.InstancesFilter{}
+	for _, router := range append(routers, filterOnly) {
+		if err := router.Init(initCtx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var grayUser, stableUser string
+	for i := 0; len(grayUser) == 0 || len(stableUser) == 0; i++ {
+		uid := fmt.Sprintf("user-%d", i)
+		if graybucket.InBucket(rule.Service, uid, rule.Percent) {
+			grayUser = uid
+		} else {
+			stableUser = uid
+		}
+	}
+	instances := []json.RawMessage{
+		json.RawMessage(`{"id": "i1", "host": "127.0.0.1", "port": 8001, "metadata": {"version": "v1", "env": "a"}}`),
+		json.RawMessage(`{"id": "i2", "host": "127.0.0.1", "port": 8002, "metadata": {"version": "v2", "env": "a"}}`),
+		json.RawMessage(`{"id": "i3", "host": "127.0.0.1", "port": 8003, "metadata": {"version": "v1", "env": "b"}}`),
+		json.RawMessage(`{"id": "i4", "host": "127.0.0.1", "port": 8004, "metadata": {"version": "v1", "env": "c"}}`),
+	}
+	cases := []*Case{
+		{Name: "gray_env_a", Source: &Service{Service: "caller", Metadata: map[string]string{"uid": grayUser}},
+			Destination: &Service{Service: "callee", Metadata: map[string]string{"env": "a"}}, Expected: []string{"i2"}},
+		{Name: "stable_env_a", Source: &Service{Service: "caller", Metadata: map[string]string{"uid": stableUser}},
+			Destination: &Service{Service: "callee", Metadata: map[string]string{"env": "a"}}, Expected: []string{"i1"}},
+		// 灰度实例不在目标环境中，交集为空，退化为顺序执行由灰度分桶兜底返回目标环境实例
+		{Name: "gray_env_b", Source: &Service{Service: "caller", Metadata: map[string]string{"uid": grayUser}},
+			Destination: &Service{Service: "callee", Metadata: map[string]string{"env": "b"}}, Expected: []string{"i3"}},
+		{Name: "stable_no_meta", Source: &Service{Service: "caller", Metadata: map[string]string{"uid": stableUser}},
+			Destination: &Service{Service: "callee"}, Expected: []string{"i1", "i3", "i4"}},
+	}
+	for _, c := range cases {
+		c.Source.Namespace, c.Destination.Namespace = "Test", "Test"
+		c.Instances = instances
+		for _, parallel := range []bool{false, true} {
+			actual, err := evaluateChain(valueCtx, routers, filterOnly, c, parallel)
+			if err != nil {
+				t.Fatalf("%s parallel=%v: %v", c.Name, parallel, err)
+			}
+			if actual != strings.Join(c.Expected, ",") {
+				t.Fatalf("%s parallel=%v: expect %v, got %s", c.Name, parallel, c.Expected, actual)
+			}
+		}
+	}
+}
+
+// evaluateChain 执行路由链，返回排序后的选中实例ID
+func evaluateChain(valueCtx model.ValueContext, routers []servicerouter.ServiceRouter,
+	filterOnly servicerouter.ServiceRouter, c *Case, parallel bool) (string, error) {
+	instancesResp, err := c.buildInstances()
+	if err != nil {
+		return "", err
+	}
+	svcInstances := pb.NewServiceInstancesInProto(instancesResp, func(string) local.InstanceLocalValue {
+		return local.NewInstanceLocalValue()
+	}, &pb.SvcPluginValues{}, nil)
+	routeInfo := &servicerouter.RouteInfo{
+		SourceService:    c.Source.toServiceInfo(),
+		DestService:      c.Destination.toServiceInfo(),
+		FilterOnlyRouter: filterOnly,
+		EnableParallel:   parallel,
+	}
+	result, sdkErr := servicerouter.GetFilterCluster(valueCtx, routers, routeInfo, svcInstances.GetServiceClusters())
+	if sdkErr != nil {
+		return "", sdkErr
+	}
+	instances, _ := result.OutputCluster.GetInstances()
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.GetId())
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ","), nil
+}
@@ -18,6 +18,7 @@
 package composite
 
 import (
+	"container/list"
 	"context"
 	"sync"
 	"sync/atomic"
@@ -77,6 +78,10 @@ type CompositeCircuitBreaker struct {
 	taskCtx context.Context
 	// executor
 	executor *TaskExecutor
+	// classifier 调用结果分类
+	classifier *errorClassifier
+	// faultDetectIndexes 探测规则索引缓存，model.ServiceKey -> *faultDetectRuleIndex
+	faultDetectIndexes sync.Map
 }
 
 // Init 初始化插件
@@ -109,10 +114,17 @@ func (c *CompositeCircuitBreaker) Start() error {
 	}
 	c.healthCheckInstanceExpireInterval = c.checkPeriod * defaultCheckPeriodMultiple
 	c.engineFlow = c.pluginCtx.ValueCtx.GetEngine()
+	cfgValue, _ := c.pluginCtx.Config.GetConsumer().GetCircuitBreaker().GetPluginConfig(c.Name()).(*circuitbreakConfig)
+	c.classifier = newErrorClassifier(cfgValue)
+	maxMethodResources := defaultMaxMethodResources
+	if cfgValue != nil {
+		maxMethodResources = cfgValue.MaxMethodResources
+	}
 	c.start = 1
 
 	c.countersCache[fault_tolerance.Level_SERVICE] = newCountersBucket()
-	c.countersCache[fault_tolerance.Level_METHOD] = newCountersBucket()
+	// 通配方法的规则会为每个调用到的方法创建独立的统计窗口，需要限制单个服务下的方法数
+	c.countersCache[fault_tolerance.Level_METHOD] = newLRUCountersBucket(maxMethodResources, c.onResourceEvicted)
 	c.countersCache[fault_tolerance.Level_INSTANCE] = newCountersBucket()
 	c.countersCache[fault_tolerance.Level_GROUP] = newCountersBucket()
 
@@ -175,6 +187,9 @@ func (c *CompositeCircuitBreaker) doReport(stat *model.ResourceStat, record bool
 	if retStatus == model.RetReject || retStatus == model.RetFlowControl {
 		return nil
 	}
+	if c.classifier.classify(stat) == classIgnored {
+		return nil
+	}
 	counters, exist := c.getResourceCounters(resource)
 	if !exist {
 		c.containers.LoadOrStore(resource.String(), newRuleContainer(c.taskCtx, resource, c))
@@ -246,6 +261,9 @@ func (c *CompositeCircuitBreaker) OnEvent(event *common.PluginEvent) error {
 	if eventObject.SvcEventKey.Type != model.EventCircuitBreaker && eventObject.SvcEventKey.Type != model.EventFaultDetect {
 		return nil
 	}
+	if eventObject.SvcEventKey.Type == model.EventFaultDetect {
+		c.faultDetectIndexes.Delete(eventObject.SvcEventKey.ServiceKey)
+	}
 	c.doSchedule(eventObject.SvcEventKey)
 	return nil
 }
@@ -306,6 +324,18 @@ func (c *CompositeCircuitBreaker) setResourceHealthChecker(res model.Resource, c
 	c.healthCheckCache.Store(res, checker)
 }
 
+// onResourceEvicted 资源统计被淘汰后，清理对应的规则容器以及探测任务，下次调用时重新创建
+func (c *CompositeCircuitBreaker) onResourceEvicted(res model.Resource) {
+	c.containers.Delete(res.String())
+	if checker, ok := c.delResourceHealthChecker(res); ok {
+		checker.stop()
+	}
+	if checkers, ok := c.loadServiceHealthCheck(*res.GetService()); ok {
+		checkers.remove(res)
+	}
+	c.log.Infof("[CircuitBreaker] resource %s evicted for exceeding max resources per service", res.String())
+}
+
 func (c *CompositeCircuitBreaker) isDestroyed() bool {
 	return atomic.LoadInt32(&c.destroy) == 1
 }
@@ -319,24 +349,82 @@ func newCountersBucket() *CountersBucket {
 	return &CountersBucket{m: make(map[string]*ResourceCounters)}
 }
 
+// newLRUCountersBucket 创建按服务限制资源数的统计桶，超出maxPerService后淘汰最久未访问的资源
+func newLRUCountersBucket(maxPerService int, onEvict func(model.Resource)) *CountersBucket {
+	return &CountersBucket{
+		m:             make(map[string]*ResourceCounters),
+		maxPerService: maxPerService,
+		lruLists:      make(map[model.ServiceKey]*list.List),
+		lruElements:   make(map[string]*list.Element),
+		onEvict:       onEvict,
+	}
+}
+
 type CountersBucket struct {
 	lock sync.RWMutex
 	m    map[string]*ResourceCounters
+	// maxPerService 每个服务下最多保留的资源数，为0则不限制
+	maxPerService int
+	// lruLists 每个服务下资源的访问顺序，队头为最近访问
+	lruLists map[model.ServiceKey]*list.List
+	// lruElements 资源在访问队列中的位置
+	lruElements map[string]*list.Element
+	// onEvict 资源被淘汰后的回调
+	onEvict func(model.Resource)
 }
 
 func (c *CountersBucket) get(key model.Resource) (*ResourceCounters, bool) {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
+	if c.maxPerService > 0 {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if elem, ok := c.lruElements[key.String()]; ok {
+			c.lruLists[*key.GetService()].MoveToFront(elem)
+		}
+	} else {
+		c.lock.RLock()
+		defer c.lock.RUnlock()
+	}
 
 	v, ok := c.m[key.String()]
 	return v, ok
 }
 
 func (c *CountersBucket) put(key model.Resource, counter *ResourceCounters) {
+	var evicted []model.Resource
 	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	c.m[key.String()] = counter
+	if c.maxPerService > 0 {
+		evicted = c.touch(key)
+	}
+	c.lock.Unlock()
+
+	for _, res := range evicted {
+		c.onEvict(res)
+	}
+}
+
+// touch 更新资源的访问顺序，返回因超出上限被淘汰的资源
+func (c *CountersBucket) touch(key model.Resource) []model.Resource {
+	svcKey := *key.GetService()
+	lruList, ok := c.lruLists[svcKey]
+	if !ok {
+		lruList = list.New()
+		c.lruLists[svcKey] = lruList
+	}
+	if elem, ok := c.lruElements[key.String()]; ok {
+		elem.Value = key
+		lruList.MoveToFront(elem)
+		return nil
+	}
+	c.lruElements[key.String()] = lruList.PushFront(key)
+	var evicted []model.Resource
+	for lruList.Len() > c.maxPerService {
+		oldest := lruList.Remove(lruList.Back()).(model.Resource)
+		delete(c.lruElements, oldest.String())
+		delete(c.m, oldest.String())
+		evicted = append(evicted, oldest)
+	}
+	return evicted
 }
 
 func (c *CountersBucket) remove(key model.Resource) (*ResourceCounters, bool) {
@@ -345,6 +433,14 @@ func (c *CountersBucket) remove(key model.Resource) (*ResourceCounters, bool) {
 
 	v, ok := c.m[key.String()]
 	delete(c.m, key.String())
+	if elem, exist := c.lruElements[key.String()]; exist {
+		svcKey := *key.GetService()
+		c.lruLists[svcKey].Remove(elem)
+		delete(c.lruElements, key.String())
+		if c.lruLists[svcKey].Len() == 0 {
+			delete(c.lruLists, svcKey)
+		}
+	}
 	return v, ok
 }
 
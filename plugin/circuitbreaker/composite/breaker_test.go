@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newMethodTestResource(t *testing.T, service string, method string) model.Resource {
+	resource, err := model.NewMethodResource(&model.ServiceKey{Namespace: "Test", Service: service}, nil, method)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resource
+}
+
+// TestLRUCountersBucket 测试单个服务下资源数超出上限后淘汰最久未访问的资源，访问会刷新资源的顺序
+func TestLRUCountersBucket(t *testing.T) {
+	var evicted []string
+	bucket := newLRUCountersBucket(2, func(res model.Resource) {
+		evicted = append(evicted, res.String())
+	})
+	m1 := newMethodTestResource(t, "echo", "m1")
+	m2 := newMethodTestResource(t, "echo", "m2")
+	m3 := newMethodTestResource(t, "echo", "m3")
+	other := newMethodTestResource(t, "other", "m1")
+
+	bucket.put(m1, &ResourceCounters{})
+	bucket.put(m2, &ResourceCounters{})
+	bucket.put(other, &ResourceCounters{})
+	if len(evicted) != 0 {
+		t.Fatalf("resources of different services should not evict each other, got %v", evicted)
+	}
+	// 访问m1后，m2成为最久未访问的资源
+	if _, ok := bucket.get(m1); !ok {
+		t.Fatal("m1 should exist")
+	}
+	bucket.put(m3, &ResourceCounters{})
+	if len(evicted) != 1 || evicted[0] != m2.String() {
+		t.Fatalf("expect m2 evicted, got %v", evicted)
+	}
+	if _, ok := bucket.get(m2); ok {
+		t.Fatal("evicted m2 should be dropped")
+	}
+
+	// 重新写入m1只刷新顺序，不触发淘汰，之后m3成为最久未访问的资源
+	evicted = nil
+	bucket.put(m1, &ResourceCounters{})
+	if len(evicted) != 0 {
+		t.Fatalf("updating existing resource should not evict, got %v", evicted)
+	}
+	bucket.put(m2, &ResourceCounters{})
+	if len(evicted) != 1 || evicted[0] != m3.String() {
+		t.Fatalf("expect m3 evicted, got %v", evicted)
+	}
+	for _, res := range []model.Resource{m1, m2, other} {
+		if _, ok := bucket.get(res); !ok {
+			t.Fatalf("%s should exist", res.String())
+		}
+	}
+}
+
+// TestResourceEvicted 测试资源被淘汰后统计、熔断状态及探测任务被清理，再次创建时从关闭状态开始
+func TestResourceEvicted(t *testing.T) {
+	breaker := &CompositeCircuitBreaker{
+		containers:              &sync.Map{},
+		healthCheckCache:        &sync.Map{},
+		serviceHealthCheckCache: &sync.Map{},
+		log:                     log.GetBaseLogger(),
+	}
+	breaker.countersCache = map[fault_tolerance.Level]*CountersBucket{
+		fault_tolerance.Level_METHOD: newLRUCountersBucket(1, breaker.onResourceEvicted),
+	}
+	rule := &fault_tolerance.CircuitBreakerRule{Name: "rule"}
+	m1 := newMethodTestResource(t, "echo", "m1")
+	m2 := newMethodTestResource(t, "echo", "m2")
+
+	counters, err := newResourceCounters(m1, rule, breaker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counters.updateCircuitBreakerStatus(model.NewCircuitBreakerStatus(rule.Name, model.Open, time.Now()))
+	breaker.getLevelResourceCounters(fault_tolerance.Level_METHOD).put(m1, counters)
+	breaker.containers.Store(m1.String(), &RuleContainer{})
+	checker := &ResourceHealthChecker{resource: m1, log: log.GetBaseLogger()}
+	breaker.setResourceHealthChecker(m1, checker)
+	breaker.loadOrStoreServiceHealthCheck(*m1.GetService()).put(m1, checker)
+	if status := breaker.CheckResource(m1); status == nil || status.GetStatus() != model.Open {
+		t.Fatalf("m1 should be open, got %v", status)
+	}
+
+	counters2, err := newResourceCounters(m2, rule, breaker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaker.getLevelResourceCounters(fault_tolerance.Level_METHOD).put(m2, counters2)
+	if status := breaker.CheckResource(m1); status != nil {
+		t.Fatalf("evicted m1 should have no status, got %v", status)
+	}
+	if _, ok := breaker.containers.Load(m1.String()); ok {
+		t.Fatal("rule container of evicted m1 should be removed")
+	}
+	if _, ok := breaker.getResourceHealthChecker(m1); ok || !checker.isStopped() {
+		t.Fatal("health checker of evicted m1 should be stopped and removed")
+	}
+	if checkers, _ := breaker.loadServiceHealthCheck(*m1.GetService()); checkers != nil {
+		if _, ok := checkers.get(m1); ok {
+			t.Fatal("evicted m1 should be removed from service health checkers")
+		}
+	}
+
+	// 再次调用时重新创建统计，从关闭状态开始
+	rebuilt, err := newResourceCounters(m1, rule, breaker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaker.getLevelResourceCounters(fault_tolerance.Level_METHOD).put(m1, rebuilt)
+	if status := breaker.CheckResource(m1); status == nil || status.GetStatus() != model.Close {
+		t.Fatalf("rebuilt m1 should be closed, got %v", status)
+	}
+	if status := breaker.CheckResource(m2); status != nil {
+		t.Fatalf("m2 should be evicted by rebuilt m1, got %v", status)
+	}
+}
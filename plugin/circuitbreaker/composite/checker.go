@@ -226,7 +226,12 @@ func (c *ResourceHealthChecker) addInstance(res *model.InstanceResource, record
 
 func (c *ResourceHealthChecker) selectFaultDetectRules(res model.Resource,
 	faultDetector *fault_tolerance.FaultDetector) map[string]*fault_tolerance.FaultDetectRule {
-	sortedRules := sortFaultDetectRules(faultDetector.GetRules())
+	var sortedRules []*fault_tolerance.FaultDetectRule
+	if svcKey := res.GetService(); svcKey != nil && c.circuitBreaker != nil {
+		sortedRules = c.circuitBreaker.getFaultDetectRuleIndex(*svcKey, faultDetector).candidates(svcKey)
+	} else {
+		sortedRules = sortFaultDetectRules(faultDetector.GetRules())
+	}
 	matchRule := map[string]*fault_tolerance.FaultDetectRule{}
 
 	for i := range sortedRules {
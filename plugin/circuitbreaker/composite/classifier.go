@@ -0,0 +1,117 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// errorClass 调用结果的分类
+type errorClass int
+
+const (
+	// classUnmatched 没有匹配的分类规则，按服务端规则判断
+	classUnmatched errorClass = iota
+	// classSuccess 视为成功
+	classSuccess
+	// classFailure 视为失败
+	classFailure
+	// classSlow 时延超过阈值，视为超时
+	classSlow
+	// classIgnored 不参与熔断统计
+	classIgnored
+)
+
+const matchAll = "*"
+
+// compiledClassification 预处理后的分类规则
+type compiledClassification struct {
+	namespace    string
+	service      string
+	failureCodes map[string]struct{}
+	successCodes map[string]struct{}
+	ignoredCodes map[string]struct{}
+	maxDelay     int64
+}
+
+func (c *compiledClassification) matchService(svcKey *model.ServiceKey) bool {
+	if len(c.namespace) > 0 && c.namespace != matchAll && c.namespace != svcKey.Namespace {
+		return false
+	}
+	if len(c.service) > 0 && c.service != matchAll && c.service != svcKey.Service {
+		return false
+	}
+	return true
+}
+
+// errorClassifier 在统计数据进入熔断窗口前，根据本地配置对调用结果进行分类
+type errorClassifier struct {
+	classifications []*compiledClassification
+}
+
+func newErrorClassifier(cfg *circuitbreakConfig) *errorClassifier {
+	classifier := &errorClassifier{}
+	if cfg == nil {
+		return classifier
+	}
+	for _, classification := range cfg.ErrorClassifications {
+		classifier.classifications = append(classifier.classifications, &compiledClassification{
+			namespace:    classification.Namespace,
+			service:      classification.Service,
+			failureCodes: toCodeSet(classification.FailureCodes),
+			successCodes: toCodeSet(classification.SuccessCodes),
+			ignoredCodes: toCodeSet(classification.IgnoredCodes),
+			maxDelay:     int64(classification.MaxDelay),
+		})
+	}
+	return classifier
+}
+
+func toCodeSet(codes []string) map[string]struct{} {
+	codeSet := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		codeSet[code] = struct{}{}
+	}
+	return codeSet
+}
+
+// classify 对调用结果进行分类，优先级为：忽略 > 成功 > 失败 > 时延
+func (e *errorClassifier) classify(stat *model.ResourceStat) errorClass {
+	if e == nil || len(e.classifications) == 0 {
+		return classUnmatched
+	}
+	svcKey := stat.Resource.GetService()
+	for _, classification := range e.classifications {
+		if !classification.matchService(svcKey) {
+			continue
+		}
+		if _, ok := classification.ignoredCodes[stat.RetCode]; ok {
+			return classIgnored
+		}
+		if _, ok := classification.successCodes[stat.RetCode]; ok {
+			return classSuccess
+		}
+		if _, ok := classification.failureCodes[stat.RetCode]; ok {
+			return classFailure
+		}
+		if classification.maxDelay > 0 && int64(stat.Delay) > classification.maxDelay {
+			return classSlow
+		}
+	}
+	return classUnmatched
+}
@@ -0,0 +1,92 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newClassifierTestStat(t *testing.T, service string, retCode string, delay time.Duration) *model.ResourceStat {
+	resource, err := model.NewServiceResource(&model.ServiceKey{Namespace: "Test", Service: service}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &model.ResourceStat{Resource: resource, RetCode: retCode, Delay: delay, RetStatus: model.RetSuccess}
+}
+
+// TestErrorClassifierClassify 测试按服务匹配分类规则以及忽略 > 成功 > 失败 > 时延的优先级
+func TestErrorClassifierClassify(t *testing.T) {
+	classifier := newErrorClassifier(&circuitbreakConfig{ErrorClassifications: []*ErrorClassification{
+		{
+			Service:      "echo",
+			FailureCodes: []string{"429", "503"},
+			SuccessCodes: []string{"404", "503"},
+			IgnoredCodes: []string{"499", "404"},
+			MaxDelay:     time.Second,
+		},
+	}})
+	cases := []struct {
+		service string
+		retCode string
+		delay   time.Duration
+		expect  errorClass
+	}{
+		{"echo", "404", 0, classIgnored},
+		{"echo", "503", 0, classSuccess},
+		{"echo", "429", 0, classFailure},
+		{"echo", "200", 2 * time.Second, classSlow},
+		{"echo", "200", 0, classUnmatched},
+		{"other", "429", 0, classUnmatched},
+	}
+	for _, c := range cases {
+		if class := classifier.classify(newClassifierTestStat(t, c.service, c.retCode, c.delay)); class != c.expect {
+			t.Fatalf("service %s, code %s, delay %v: expect class %d, got %d",
+				c.service, c.retCode, c.delay, c.expect, class)
+		}
+	}
+}
+
+// TestParseRetStatusWithClassifier 测试本地分类规则优先于服务端规则的错误条件，未匹配时仍按服务端规则判断
+func TestParseRetStatusWithClassifier(t *testing.T) {
+	breaker := &CompositeCircuitBreaker{classifier: newErrorClassifier(&circuitbreakConfig{
+		ErrorClassifications: []*ErrorClassification{{Service: "echo", SuccessCodes: []string{"500"}}},
+	})}
+	counters := &ResourceCounters{
+		circuitBreaker: breaker,
+		activeRule: &fault_tolerance.CircuitBreakerRule{ErrorConditions: []*fault_tolerance.ErrorCondition{{
+			InputType: fault_tolerance.ErrorCondition_RET_CODE,
+			Condition: &apimodel.MatchString{
+				Type:  apimodel.MatchString_EXACT,
+				Value: wrapperspb.String("500"),
+			},
+		}}},
+	}
+	if status := counters.parseRetStatus(newClassifierTestStat(t, "echo", "500", 0)); status != model.RetSuccess {
+		t.Fatalf("expect local classification applied, got %v", status)
+	}
+	if status := counters.parseRetStatus(newClassifierTestStat(t, "other", "500", 0)); status != model.RetFail {
+		t.Fatalf("expect server error condition applied, got %v", status)
+	}
+}
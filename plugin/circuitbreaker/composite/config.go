@@ -17,15 +17,58 @@
 
 package composite
 
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultMaxMethodResources = 1000
+)
+
 type circuitbreakConfig struct {
+	// MaxMethodResources 每个服务最多保留熔断统计的方法数，超出后淘汰最久未调用的方法
+	MaxMethodResources int `yaml:"maxMethodResources" json:"maxMethodResources"`
+	// ErrorClassifications 按服务配置的调用结果分类规则，优先于服务端熔断规则中的错误条件生效，未匹配时按服务端规则判定。
+	// 服务端规则的错误条件（返回码、时延）只能判定失败，没有成功及忽略的分类，因此成功及忽略的分类只支持本地配置
+	ErrorClassifications []*ErrorClassification `yaml:"errorClassifications" json:"errorClassifications"`
+}
+
+// ErrorClassification 调用结果分类规则，决定一次调用在熔断统计中被视为成功、失败还是忽略
+type ErrorClassification struct {
+	// Namespace 命名空间，为空或者*代表全部命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// Service 服务名，为空或者*代表全部服务
+	Service string `yaml:"service" json:"service"`
+	// FailureCodes 视为失败的返回码
+	FailureCodes []string `yaml:"failureCodes" json:"failureCodes"`
+	// SuccessCodes 视为成功的返回码
+	SuccessCodes []string `yaml:"successCodes" json:"successCodes"`
+	// IgnoredCodes 不参与熔断统计的返回码
+	IgnoredCodes []string `yaml:"ignoredCodes" json:"ignoredCodes"`
+	// MaxDelay 时延超过该值的调用视为失败，为0则不按时延判断
+	MaxDelay time.Duration `yaml:"maxDelay" json:"maxDelay"`
 }
 
 // Verify 校验配置是否OK
 func (c *circuitbreakConfig) Verify() error {
+	if c.MaxMethodResources < 0 {
+		return fmt.Errorf("maxMethodResources must not be negative")
+	}
+	for i, classification := range c.ErrorClassifications {
+		if classification == nil {
+			return fmt.Errorf("errorClassifications[%d] is nil", i)
+		}
+		if classification.MaxDelay < 0 {
+			return fmt.Errorf("errorClassifications[%d].maxDelay must not be negative", i)
+		}
+	}
 	return nil
 }
 
 // SetDefault 对关键值设置默认值
 func (c *circuitbreakConfig) SetDefault() {
-
+	if c.MaxMethodResources == 0 {
+		c.MaxMethodResources = defaultMaxMethodResources
+	}
 }
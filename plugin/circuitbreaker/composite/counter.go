@@ -29,6 +29,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/composite/trigger"
 )
@@ -234,6 +235,16 @@ func (rc *ResourceCounters) Report(stat *model.ResourceStat) {
 }
 
 func (rc *ResourceCounters) parseRetStatus(stat *model.ResourceStat) model.RetStatus {
+	if rc.circuitBreaker != nil {
+		switch rc.circuitBreaker.classifier.classify(stat) {
+		case classSuccess:
+			return model.RetSuccess
+		case classFailure:
+			return model.RetFail
+		case classSlow:
+			return model.RetTimeout
+		}
+	}
 	errConditions := rc.activeRule.GetErrorConditions()
 	if len(errConditions) == 0 {
 		return stat.RetStatus
@@ -260,6 +271,7 @@ func (rc *ResourceCounters) parseRetStatus(stat *model.ResourceStat) model.RetSt
 }
 
 func (rc *ResourceCounters) reportCircuitStatus(newStatus model.CircuitBreakerStatus) {
+	rc.fireStatusChanged(newStatus)
 	if !rc.isInsRes {
 		return
 	}
@@ -283,6 +295,27 @@ func (rc *ResourceCounters) reportCircuitStatus(newStatus model.CircuitBreakerSt
 	}
 }
 
+// fireStatusChanged 通知熔断状态变更事件的订阅者
+func (rc *ResourceCounters) fireStatusChanged(newStatus model.CircuitBreakerStatus) {
+	if rc.circuitBreaker == nil || rc.circuitBreaker.pluginCtx == nil {
+		return
+	}
+	handlers := rc.circuitBreaker.pluginCtx.Plugins.GetEventSubscribers(common.OnCircuitBreakerStatusChanged)
+	if len(handlers) == 0 {
+		return
+	}
+	eventObj := &common.PluginEvent{
+		EventType: common.OnCircuitBreakerStatusChanged,
+		EventObject: &model.CircuitBreakerEvent{
+			Resource: rc.resource,
+			Status:   newStatus,
+		},
+	}
+	for _, h := range handlers {
+		_ = h.Callback(eventObj)
+	}
+}
+
 func buildFallbackInfo(rule *fault_tolerance.CircuitBreakerRule) *model.FallbackInfo {
 	if rule == nil {
 		return nil
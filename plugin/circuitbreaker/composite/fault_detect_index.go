@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"strings"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// faultDetectRuleIndex 按规则版本缓存的探测规则索引，规则排序后按被调服务分组，
+// 单个资源选择规则时只需要遍历本服务以及通配服务的规则
+type faultDetectRuleIndex struct {
+	revision string
+	// exact 命名空间以及服务名都精确指定的规则
+	exact map[model.ServiceKey][]indexedFaultDetectRule
+	// wildcard 命名空间或者服务名为通配的规则
+	wildcard []indexedFaultDetectRule
+}
+
+// indexedFaultDetectRule 带排序序号的探测规则
+type indexedFaultDetectRule struct {
+	order int
+	rule  *fault_tolerance.FaultDetectRule
+}
+
+func newFaultDetectRuleIndex(faultDetector *fault_tolerance.FaultDetector) *faultDetectRuleIndex {
+	index := &faultDetectRuleIndex{
+		revision: faultDetector.GetRevision(),
+		exact:    map[model.ServiceKey][]indexedFaultDetectRule{},
+	}
+	for i, rule := range sortFaultDetectRules(faultDetector.GetRules()) {
+		item := indexedFaultDetectRule{order: i, rule: rule}
+		namespace := strings.TrimSpace(rule.GetTargetService().GetNamespace())
+		service := strings.TrimSpace(rule.GetTargetService().GetService())
+		if match.IsMatchAll(namespace) || match.IsMatchAll(service) {
+			index.wildcard = append(index.wildcard, item)
+			continue
+		}
+		key := model.ServiceKey{Namespace: namespace, Service: service}
+		index.exact[key] = append(index.exact[key], item)
+	}
+	return index
+}
+
+// candidates 返回可能匹配该服务的规则，保持规则的排序
+func (f *faultDetectRuleIndex) candidates(svcKey *model.ServiceKey) []*fault_tolerance.FaultDetectRule {
+	var exact []indexedFaultDetectRule
+	if svcKey != nil {
+		exact = f.exact[*svcKey]
+	}
+	rules := make([]*fault_tolerance.FaultDetectRule, 0, len(exact)+len(f.wildcard))
+	i, j := 0, 0
+	for i < len(exact) || j < len(f.wildcard) {
+		if j >= len(f.wildcard) || (i < len(exact) && exact[i].order < f.wildcard[j].order) {
+			rules = append(rules, exact[i].rule)
+			i++
+			continue
+		}
+		rules = append(rules, f.wildcard[j].rule)
+		j++
+	}
+	return rules
+}
+
+// getFaultDetectRuleIndex 获取服务探测规则的索引，规则版本变化时重新构建
+func (c *CompositeCircuitBreaker) getFaultDetectRuleIndex(svcKey model.ServiceKey,
+	faultDetector *fault_tolerance.FaultDetector) *faultDetectRuleIndex {
+	if value, ok := c.faultDetectIndexes.Load(svcKey); ok {
+		if index := value.(*faultDetectRuleIndex); index.revision == faultDetector.GetRevision() {
+			return index
+		}
+	}
+	index := newFaultDetectRuleIndex(faultDetector)
+	c.faultDetectIndexes.Store(svcKey, index)
+	return index
+}
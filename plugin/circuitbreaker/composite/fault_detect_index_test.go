@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	_ "github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+)
+
+func newTestFaultDetectRule(name, namespace, service string) *fault_tolerance.FaultDetectRule {
+	return &fault_tolerance.FaultDetectRule{
+		Name: name,
+		TargetService: &fault_tolerance.FaultDetectRule_DestinationService{
+			Namespace: namespace,
+			Service:   service,
+		},
+	}
+}
+
+func TestFaultDetectRuleIndex(t *testing.T) {
+	breaker := &CompositeCircuitBreaker{}
+	detector := &fault_tolerance.FaultDetector{
+		Revision: "v1",
+		Rules: []*fault_tolerance.FaultDetectRule{
+			newTestFaultDetectRule("all", "*", "*"),
+			newTestFaultDetectRule("other", "default", "other"),
+			newTestFaultDetectRule("ns", "default", "*"),
+			newTestFaultDetectRule("svc", "default", "echo"),
+		},
+	}
+	svcKey := model.ServiceKey{Namespace: "default", Service: "echo"}
+	index := breaker.getFaultDetectRuleIndex(svcKey, detector)
+	rules := index.candidates(&svcKey)
+	if len(rules) != 3 || rules[0].Name != "svc" || rules[1].Name != "ns" || rules[2].Name != "all" {
+		t.Fatalf("expect rules sorted by specificity without other services, got %v", rules)
+	}
+	if breaker.getFaultDetectRuleIndex(svcKey, detector) != index {
+		t.Fatal("expect index cached for the same revision")
+	}
+	detector = &fault_tolerance.FaultDetector{Revision: "v2"}
+	if rules = breaker.getFaultDetectRuleIndex(svcKey, detector).candidates(&svcKey); len(rules) != 0 {
+		t.Fatalf("expect index rebuilt on new revision, got %v", rules)
+	}
+}
@@ -250,7 +250,7 @@ func selectFaultDetector(res model.Resource, object *model.ServiceRuleResponse,
 }
 
 func sortFaultDetectRules(srcRules []*fault_tolerance.FaultDetectRule) []*fault_tolerance.FaultDetectRule {
-	rules := make([]*fault_tolerance.FaultDetectRule, 0, len(srcRules))
+	rules := make([]*fault_tolerance.FaultDetectRule, len(srcRules))
 	copy(rules, srcRules)
 	sort.Slice(rules, func(i, j int) bool {
 		rule1 := rules[i]
@@ -18,41 +18,15 @@
 package trigger
 
 import (
-	"math"
 	"sync/atomic"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
-	"github.com/polarismesh/polaris-go/pkg/metric"
-	"github.com/polarismesh/polaris-go/pkg/model"
-)
-
-const (
-	bucketCount = 10
-)
-
-const (
-	// 错误率统计窗口下标
-	metricIdxErrRate = iota
-	// 半开统计窗口下标
-	metricIdxHalfOpen
-	// 最大窗口下标
-	metricIdxMax
-)
-
-// 统计维度
-const (
-	// 总请求数
-	keyRequestCount = iota
-	// 错误数
-	keyFailCount
-	// 总统计维度
-	maxDimension
 )
 
 type ErrRateCounter struct {
 	*baseCounter
-	sliceWindow    *metric.SliceWindow
+	window         *ringWindow
 	metricWindow   time.Duration
 	minimumRequest int32
 	errorPercent   int
@@ -72,7 +46,7 @@ func (c *ErrRateCounter) init() {
 	c.metricWindow = time.Duration(c.triggerCondition.Interval) * time.Second
 	c.errorPercent = int(c.triggerCondition.ErrorPercent)
 	c.minimumRequest = int32(c.triggerCondition.MinimumRequest)
-	c.sliceWindow = metric.NewSliceWindow(c.res.String(), bucketCount, getBucketInterval(c.metricWindow), maxDimension, clock.GetClock().Now().UnixNano())
+	c.window = newRingWindow(c.metricWindow, ringBucketInterval)
 }
 
 func (c *ErrRateCounter) Report(success bool) {
@@ -80,40 +54,17 @@ func (c *ErrRateCounter) Report(success bool) {
 		c.log.Debugf("[CircuitBreaker][Counter] errRateCounter(%s) suspended, skip report", c.ruleName)
 		return
 	}
-	c.log.Debugf("[CircuitBreaker][Counter] errRateCounter(%s): add requestCount 1, success(%+v)", c.ruleName, success)
-
-	retStatus := model.RetSuccess
-	if !success {
-		retStatus = model.RetFail
-	}
-
-	c.sliceWindow.AddGauge(&model.ServiceCallResult{
-		RetStatus: retStatus,
-	}, func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
-		ret := gauge.GetRetStatus()
-		if ret == model.RetFail {
-			bucket.AddMetric(keyFailCount, 1)
-		}
-		bucket.AddMetric(keyRequestCount, 1)
-		return 0
-	})
+	c.window.add(clock.GetClock().Now().UnixNano(), !success)
 	if !success && atomic.CompareAndSwapInt32(&c.scheduled, 0, 1) {
 		c.log.Infof("[CircuitBreaker][Counter] errRateCounter: trigger error rate callback on failure, name(%s)", c.ruleName)
 		c.delayExecutor(c.metricWindow, func() {
-			currentTime := time.Now()
-			timeRange := &metric.TimeRange{
-				Start: currentTime.Add(-1 * c.metricWindow),
-				End:   currentTime,
-			}
-			reqCount := c.sliceWindow.CalcMetrics(keyRequestCount, timeRange)
-			reqFailCount := c.sliceWindow.CalcMetrics(keyFailCount, timeRange)
+			reqCount, failCount := c.window.sum(clock.GetClock().Now().UnixNano())
 			c.log.Infof("[CircuitBreaker][Counter] errRateCounter: requestCount(%d) failCount(%d), minimumRequest(%d), name(%s)",
-				reqCount, reqFailCount, c.minimumRequest, c.ruleName)
+				reqCount, failCount, c.minimumRequest, c.ruleName)
 			if reqCount < int64(c.minimumRequest) {
 				atomic.StoreInt32(&c.scheduled, 0)
 				return
 			}
-			failCount := c.sliceWindow.CalcMetrics(keyFailCount, timeRange)
 			failRatio := (float64(failCount) / float64(reqCount)) * 100
 			if failRatio >= float64(c.errorPercent) {
 				c.suspend()
@@ -130,11 +81,6 @@ func (c *ErrRateCounter) Resume() {
 	}
 }
 
-func getBucketInterval(interval time.Duration) time.Duration {
-	bucketSize := math.Ceil(float64(interval) / float64(bucketCount))
-	return time.Duration(bucketSize)
-}
-
 // ToErrorRateThreshold 转换成熔断错误率阈值
 func ToErrorRateThreshold(errorRatePercent int) float64 {
 	return float64(errorRatePercent) / 100
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package trigger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// ringBucketInterval 环形滑窗每个桶的时长
+	ringBucketInterval = time.Second
+)
+
+// ringBucket 滑窗中的单个桶，start为桶的起始时间(ns)
+type ringBucket struct {
+	start    int64
+	requests int64
+	fails    int64
+}
+
+// ringWindow 基于定长环形数组的滑动窗口，每个桶为固定时长的原子计数器，
+// 上报过程无锁且不分配内存，桶过期时通过CAS重置，重置瞬间并发写入的少量计数可能丢失，对错误率判断可以忽略
+type ringWindow struct {
+	bucketNanos int64
+	buckets     []ringBucket
+}
+
+// newRingWindow 创建覆盖window时长的环形滑窗，桶个数向上取整
+func newRingWindow(window, bucketInterval time.Duration) *ringWindow {
+	if bucketInterval <= 0 {
+		bucketInterval = ringBucketInterval
+	}
+	count := int((window + bucketInterval - 1) / bucketInterval)
+	if count < 1 {
+		count = 1
+	}
+	return &ringWindow{
+		bucketNanos: int64(bucketInterval),
+		buckets:     make([]ringBucket, count),
+	}
+}
+
+// add 在now(ns)所在的桶中增加一次请求
+func (w *ringWindow) add(now int64, fail bool) {
+	start := now - now%w.bucketNanos
+	bucket := &w.buckets[(now/w.bucketNanos)%int64(len(w.buckets))]
+	for {
+		bucketStart := atomic.LoadInt64(&bucket.start)
+		if bucketStart == start {
+			break
+		}
+		if bucketStart > start {
+			// 时钟回退导致落到已经被复用的桶，直接丢弃
+			return
+		}
+		if atomic.CompareAndSwapInt64(&bucket.start, bucketStart, start) {
+			atomic.StoreInt64(&bucket.requests, 0)
+			atomic.StoreInt64(&bucket.fails, 0)
+			break
+		}
+	}
+	atomic.AddInt64(&bucket.requests, 1)
+	if fail {
+		atomic.AddInt64(&bucket.fails, 1)
+	}
+}
+
+// sum 统计截止到now(ns)窗口内的请求数以及失败数
+func (w *ringWindow) sum(now int64) (requests int64, fails int64) {
+	minStart := now - now%w.bucketNanos - int64(len(w.buckets)-1)*w.bucketNanos
+	for i := range w.buckets {
+		bucket := &w.buckets[i]
+		start := atomic.LoadInt64(&bucket.start)
+		if start < minStart || start > now {
+			continue
+		}
+		requests += atomic.LoadInt64(&bucket.requests)
+		fails += atomic.LoadInt64(&bucket.fails)
+	}
+	return requests, fails
+}
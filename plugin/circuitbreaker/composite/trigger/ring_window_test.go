@@ -0,0 +1,82 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestRingWindow(t *testing.T) {
+	window := newRingWindow(3*time.Second, time.Second)
+	base := int64(100 * time.Second)
+	window.add(base, true)
+	window.add(base+int64(500*time.Millisecond), false)
+	window.add(base+int64(time.Second), false)
+	window.add(base+int64(2*time.Second), true)
+	if requests, fails := window.sum(base + int64(2*time.Second)); requests != 4 || fails != 2 {
+		t.Fatalf("expect 4 requests 2 fails, got %d %d", requests, fails)
+	}
+	// 第一个桶滑出窗口后被复用
+	window.add(base+int64(3*time.Second), false)
+	if requests, fails := window.sum(base + int64(3*time.Second)); requests != 3 || fails != 1 {
+		t.Fatalf("expect 3 requests 1 fail, got %d %d", requests, fails)
+	}
+	if requests, _ := window.sum(base + int64(10*time.Second)); requests != 0 {
+		t.Fatalf("expect expired window empty, got %d", requests)
+	}
+}
+
+// BenchmarkRingWindowAdd 滑窗并发上报的开销
+func BenchmarkRingWindowAdd(b *testing.B) {
+	window := newRingWindow(10*time.Second, ringBucketInterval)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int64
+		for pb.Next() {
+			i++
+			window.add(time.Now().UnixNano(), i%10 == 0)
+		}
+	})
+}
+
+// BenchmarkErrRateCounterReport 错误率计数器并发上报成功请求的开销
+func BenchmarkErrRateCounterReport(b *testing.B) {
+	res, err := model.NewServiceResource(&model.ServiceKey{Namespace: "default", Service: "svc"}, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	counter := NewErrRateCounter("bench", &Options{
+		Resource:      res,
+		Condition:     &fault_tolerance.TriggerCondition{Interval: 10, ErrorPercent: 50, MinimumRequest: 10},
+		Log:           log.GetBaseLogger(),
+		DelayExecutor: func(time.Duration, func()) {},
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Report(true)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// tokenOf 获取配置文件请求使用的鉴权token，优先使用请求指定的token，其次是命名空间级别的token
+func (c *Connector) tokenOf(configFile *configconnector.ConfigFile) string {
+	if len(configFile.Token) > 0 {
+		return configFile.Token
+	}
+	return c.namespaceToken(configFile.Namespace)
+}
+
+// namespaceToken 获取命名空间使用的鉴权token，未单独配置时使用全局token
+func (c *Connector) namespaceToken(namespace string) string {
+	return c.connectorConfig.GetNamespaceToken(namespace)
+}
+
+// isAuthFailure 是否为鉴权失败的返回码
+func isAuthFailure(code uint32) bool {
+	switch apimodel.Code(code) {
+	case apimodel.Code_NotAllowedAccess, apimodel.Code_AuthTokenForbidden, apimodel.Code_OperationRoleForbidden:
+		return true
+	}
+	return false
+}
+
+// authError 构造鉴权失败的错误，提示检查命名空间级别的token配置
+func authError(opKey string, request string, code uint32, info string) error {
+	return model.NewSDKErrorWithServerInfo(model.ErrCodeUnauthorized, nil, code, info,
+		"fail to %s, token has no permission, please check config.configConnector.token, "+
+			"config.configConnector.namespaceTokens or the token passed to the call. request %s, server code %d, reason %s",
+		opKey, request, code, info)
+}
@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+func TestTokenOf(t *testing.T) {
+	cfg := &config.ConfigConnectorConfigImpl{}
+	cfg.SetToken("global")
+	cfg.SetNamespaceTokens(map[string]string{"ns1": "ns1-token"})
+	c := &Connector{token: cfg.GetToken(), connectorConfig: cfg}
+
+	if token := c.tokenOf(&configconnector.ConfigFile{Namespace: "ns1", Token: "call"}); token != "call" {
+		t.Fatalf("expect call token, got %s", token)
+	}
+	if token := c.tokenOf(&configconnector.ConfigFile{Namespace: "ns1"}); token != "ns1-token" {
+		t.Fatalf("expect namespace token, got %s", token)
+	}
+	if token := c.tokenOf(&configconnector.ConfigFile{Namespace: "ns2"}); token != "global" {
+		t.Fatalf("expect global token, got %s", token)
+	}
+}
+
+func TestAuthError(t *testing.T) {
+	if !isAuthFailure(uint32(apimodel.Code_AuthTokenForbidden)) {
+		t.Fatal("expect auth failure")
+	}
+	if isAuthFailure(uint32(apimodel.Code_ExecuteSuccess)) {
+		t.Fatal("expect not auth failure")
+	}
+	err := authError("CreateConfigFile", "ns1/group/file", uint32(apimodel.Code_AuthTokenForbidden), "forbidden")
+	sdkErr, ok := err.(model.SDKError)
+	if !ok || sdkErr.ErrorCode() != model.ErrCodeUnauthorized {
+		t.Fatalf("expect unauthorized error, got %v", err)
+	}
+}
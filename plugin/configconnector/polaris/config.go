@@ -20,6 +20,7 @@ package polaris
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -29,11 +30,28 @@ const (
 	DefaultMaxCallRecvMsgSize = 50 * 1024 * 1024
 	// MaxMaxCallRecvMsgSize GRPC链路包接收大小的设置上限.
 	MaxMaxCallRecvMsgSize = 500 * 1024 * 1024
+	// DefaultPollInterval 轮询模式下默认的配置拉取间隔.
+	DefaultPollInterval = 5 * time.Second
+	// MinPollInterval 轮询模式下配置拉取间隔的下限.
+	MinPollInterval = 1 * time.Second
+)
+
+const (
+	// WatchModeAuto 优先使用watch长连接，服务端不支持时自动降级为轮询.
+	WatchModeAuto = "auto"
+	// WatchModeWatch 只使用watch长连接.
+	WatchModeWatch = "watch"
+	// WatchModePoll 只使用按版本号的条件轮询.
+	WatchModePoll = "poll"
 )
 
 // GRPC插件级别配置.
 type networkConfig struct {
 	MaxCallRecvMsgSize int `yaml:"maxCallRecvMsgSize"`
+	// 配置变更的监听方式，auto|watch|poll
+	WatchMode string `yaml:"watchMode"`
+	// 轮询模式下的配置拉取间隔
+	PollInterval time.Duration `yaml:"pollInterval"`
 }
 
 // Verify 校验GRPC配置值.
@@ -42,6 +60,14 @@ func (r *networkConfig) Verify() error {
 	if r.MaxCallRecvMsgSize <= 0 || r.MaxCallRecvMsgSize > MaxMaxCallRecvMsgSize {
 		errs = multierror.Append(errs, fmt.Errorf("grpc.maxCallRecvMsgSize must be int (0, 524288000]"))
 	}
+	switch r.WatchMode {
+	case WatchModeAuto, WatchModeWatch, WatchModePoll:
+	default:
+		errs = multierror.Append(errs, fmt.Errorf("grpc.watchMode must be one of auto, watch, poll"))
+	}
+	if r.PollInterval < MinPollInterval {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.pollInterval must be greater than %v", MinPollInterval))
+	}
 	return errs
 }
 
@@ -50,4 +76,10 @@ func (r *networkConfig) SetDefault() {
 	if r.MaxCallRecvMsgSize <= 0 {
 		r.MaxCallRecvMsgSize = DefaultMaxCallRecvMsgSize
 	}
+	if r.WatchMode == "" {
+		r.WatchMode = WatchModeAuto
+	}
+	if r.PollInterval == 0 {
+		r.PollInterval = DefaultPollInterval
+	}
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
@@ -58,6 +59,12 @@ type Connector struct {
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 	token           string
+	// 配置中心连接器配置，用于获取命名空间级别的鉴权token
+	connectorConfig config.ConfigConnectorConfig
+	// watch接口不可用时，降级为轮询的截止时间(UnixNano)
+	watchFallbackUntil int64
+	// 每次调用附加的客户端身份信息，未启用时为nil
+	identity metadata.MD
 }
 
 // Type 插件类型.
@@ -79,6 +86,8 @@ func (c *Connector) Init(ctx *plugin.InitContext) error {
 		c.cfg = cfgValue.(*networkConfig)
 	}
 	c.token = ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetToken()
+	c.connectorConfig = ctx.Config.GetConfigFile().GetConfigConnectorConfig()
+	c.identity = connector.BuildIdentityMetadata(ctx.Config.GetGlobal().GetClient())
 	connManager, err := network.NewConfigConnectionManager(ctx.Config, ctx.ValueCtx)
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create config connectionManager")
@@ -123,7 +132,7 @@ func (c *Connector) GetConfigFile(configFile *configconnector.ConfigFile) (*conf
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextRegisterInstanceReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenOf(configFile)),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -144,6 +153,9 @@ func (c *Connector) WatchConfigFiles(configFileList []*configconnector.ConfigFil
 	if err = c.waitDiscoverReady(); err != nil {
 		return nil, err
 	}
+	if c.isPolling() {
+		return c.pollConfigFiles(configFileList)
+	}
 	opKey := connector.OpKeyWatchConfigFiles
 	startTime := clock.GetClock().Now()
 	// 获取server连接
@@ -173,6 +185,11 @@ func (c *Connector) WatchConfigFiles(configFileList []*configconnector.ConfigFil
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
 	pbResp, err := configClient.WatchConfigFiles(ctx, request)
+	if err != nil && c.getWatchMode() == WatchModeAuto && isWatchUnsupported(err) {
+		// 服务端或者网关不支持watch，非服务端故障，不上报连接失败，直接降级为轮询
+		c.fallbackToPolling(err, conn.ConnID.String())
+		return c.pollConfigFiles(configFileList)
+	}
 	return c.handleResponse(request.String(), reqID, opKey, pbResp, err, conn, startTime)
 }
 
@@ -194,7 +211,7 @@ func (c *Connector) CreateConfigFile(configFile *configconnector.ConfigFile) (*c
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextCreateConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenOf(configFile)),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -227,7 +244,7 @@ func (c *Connector) UpdateConfigFile(configFile *configconnector.ConfigFile) (*c
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextUpdateConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenOf(configFile)),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -260,7 +277,7 @@ func (c *Connector) PublishConfigFile(configFile *configconnector.ConfigFile) (*
 	defer conn.Release(opKey)
 	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
 	reqID := connector.NextPublishConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.tokenOf(configFile)),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -290,7 +307,7 @@ func (c *Connector) GetConfigGroup(req *configconnector.ConfigGroup) (*configcon
 	}
 
 	reqID := connector.NextPublishConfigFileReqID()
-	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.token),
+	ctx, cancel := connector.CreateHeadersContext(0, connector.AppendAuthHeader(c.namespaceToken(req.Namespace)),
 		connector.AppendHeaderWithReqId(reqID))
 	if cancel != nil {
 		defer cancel()
@@ -323,6 +340,11 @@ func (c *Connector) GetConfigGroup(req *configconnector.ConfigGroup) (*configcon
 		}
 		return groupResp, nil
 	}
+	if isAuthFailure(response.GetCode().GetValue()) {
+		// 鉴权失败不是服务端故障，不上报连接失败
+		c.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		return nil, authError(opKey, request.String(), response.GetCode().GetValue(), response.GetInfo().GetValue())
+	}
 	// 当server发生了内部错误时，上报调用服务失败
 	errMsg := fmt.Sprintf(
 		"fail to %s, request %s, server code %d, reason %s, server %s", opKey,
@@ -384,6 +406,11 @@ func (c *Connector) handleResponse(request string, reqID string, opKey string, r
 			ConfigFile: transferFromClientConfigFileInfo(response.GetConfigFile()),
 		}, nil
 	}
+	if isAuthFailure(response.GetCode().GetValue()) {
+		// 鉴权失败不是服务端故障，不上报连接失败
+		c.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		return nil, authError(opKey, request, response.GetCode().GetValue(), response.GetInfo().GetValue())
+	}
 	// 当server发生了内部错误时，上报调用服务失败
 	errMsg := fmt.Sprintf(
 		"fail to %s, request %s, server code %d, reason %s, server %s", opKey,
@@ -428,6 +455,7 @@ func transferFromClientConfigFileInfo(configFileInfo *config_manage.ClientConfig
 		Md5:           configFileInfo.GetMd5().GetValue(),
 		Encrypted:     configFileInfo.GetEncrypted().GetValue(),
 		Tags:          tags,
+		ReleaseTime:   configconnector.ParseReleaseTime(configFileInfo.GetReleaseTime().GetValue()),
 		Persistent: model.Persistent{
 			Encoding: configFileInfo.GetPersistent().GetEncoding(),
 			Path:     configFileInfo.GetPersistent().GetPath(),
@@ -29,6 +29,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/network"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
 
 // CreateConnection 创建连接.
@@ -44,6 +45,7 @@ func (c *Connector) CreateConnection(
 	}
 	log.GetBaseLogger().Debugf("create connection with maxCallRecvSize %d", c.cfg.MaxCallRecvMsgSize)
 	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(c.cfg.MaxCallRecvMsgSize)))
+	opts = append(opts, connector.IdentityDialOptions(c.identity)...)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	conn, err := grpc.DialContext(ctx, address, opts...)
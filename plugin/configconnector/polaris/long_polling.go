@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"sync/atomic"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+const (
+	// 自动降级为轮询后，重新探测watch接口是否可用的间隔
+	watchReprobeInterval = 10 * time.Minute
+)
+
+// getWatchMode 获取配置变更的监听方式
+func (c *Connector) getWatchMode() string {
+	if c.cfg == nil || c.cfg.WatchMode == "" {
+		return WatchModeAuto
+	}
+	return c.cfg.WatchMode
+}
+
+// getPollInterval 获取轮询模式下的拉取间隔
+func (c *Connector) getPollInterval() time.Duration {
+	if c.cfg == nil || c.cfg.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+	return c.cfg.PollInterval
+}
+
+// isPolling 当前是否使用轮询代替watch
+func (c *Connector) isPolling() bool {
+	switch c.getWatchMode() {
+	case WatchModePoll:
+		return true
+	case WatchModeWatch:
+		return false
+	}
+	return clock.GetClock().Now().UnixNano() < atomic.LoadInt64(&c.watchFallbackUntil)
+}
+
+// fallbackToPolling 服务端不支持watch时降级为轮询，并在一段时间后重新探测
+func (c *Connector) fallbackToPolling(err error, server string) {
+	until := clock.GetClock().Now().Add(watchReprobeInterval)
+	atomic.StoreInt64(&c.watchFallbackUntil, until.UnixNano())
+	log.GetBaseLogger().Warnf("[Config] watch config files is not supported by server %s, fallback to polling "+
+		"with interval %v until %v, reason: %v", server, c.getPollInterval(), until, err)
+}
+
+// isWatchUnsupported 判断watch失败是否由于服务端(或中间的网关)不支持该接口
+func isWatchUnsupported(err error) bool {
+	return status.Code(err) == codes.Unimplemented
+}
+
+// pollConfigFiles 按版本号对订阅的配置文件逐个发起条件查询，间隔 pollInterval 执行一次，
+// 语义与 WatchConfigFiles 一致：存在变更时返回首个版本号更大的配置文件，否则返回 DataNoChange
+func (c *Connector) pollConfigFiles(configFileList []*configconnector.ConfigFile) (
+	*configconnector.ConfigFileResponse, error) {
+	timer := time.NewTimer(c.getPollInterval())
+	defer timer.Stop()
+	select {
+	case <-c.RunContext.Done():
+		return nil, model.NewSDKError(model.ErrCodeInvalidStateError, nil, "SDK context has destroyed")
+	case <-timer.C:
+	}
+	var lastErr error
+	var failCount int
+	for _, configFile := range configFileList {
+		resp, err := c.GetConfigFile(configFile)
+		if err != nil {
+			lastErr = err
+			failCount++
+			continue
+		}
+		if resp.GetCode() != uint32(apimodel.Code_ExecuteSuccess) || resp.GetConfigFile() == nil {
+			continue
+		}
+		if resp.GetConfigFile().GetVersion() > configFile.GetVersion() {
+			return resp, nil
+		}
+	}
+	if failCount > 0 && failCount == len(configFileList) {
+		return nil, lastErr
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:    uint32(apimodel.Code_DataNoChange),
+		Message: "config files polled without change",
+	}, nil
+}
@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatchFallbackToPolling(t *testing.T) {
+	cfg := &networkConfig{}
+	cfg.SetDefault()
+	if err := cfg.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	c := &Connector{cfg: cfg}
+	if c.isPolling() {
+		t.Fatal("expect watch before fallback")
+	}
+	if isWatchUnsupported(errors.New("connection refused")) ||
+		isWatchUnsupported(status.Error(codes.Unavailable, "unavailable")) {
+		t.Fatal("expect network error not to trigger fallback")
+	}
+	err := status.Error(codes.Unimplemented, "unknown method WatchConfigFiles")
+	if !isWatchUnsupported(err) {
+		t.Fatal("expect unimplemented to trigger fallback")
+	}
+	c.fallbackToPolling(err, "127.0.0.1:8093")
+	if !c.isPolling() {
+		t.Fatal("expect polling after fallback")
+	}
+	cfg.WatchMode = WatchModeWatch
+	if c.isPolling() {
+		t.Fatal("expect watch mode never to poll")
+	}
+}
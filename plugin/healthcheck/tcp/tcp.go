@@ -72,11 +72,12 @@ func (g *Detector) Init(ctx *plugin.InitContext) (err error) {
 // DetectInstance 探测服务实例健康
 func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.FaultDetectRule) (result healthcheck.DetectResult, err error) {
 	start := time.Now()
-	address := fmt.Sprintf("%s:%d", ins.GetHost(), ins.GetPort())
-	if rule != nil && rule.GetPort() > 0 {
+	// unix domain socket 实例直接探测 socket 文件
+	network, address := model.InstanceDialAddress(ins)
+	if network == model.NetworkTCP && rule != nil && rule.GetPort() > 0 {
 		address = fmt.Sprintf("%s:%d", ins.GetHost(), rule.GetPort())
 	}
-	success := g.doTCPDetect(address, rule)
+	success := g.doTCPDetect(network, address, rule)
 	result = &healthcheck.DetectResultImp{
 		Success:        success,
 		DetectTime:     start,
@@ -92,13 +93,13 @@ func (g *Detector) DetectInstance(ins model.Instance, rule *fault_tolerance.Faul
 }
 
 // doTCPDetect 执行一次探测逻辑
-func (g *Detector) doTCPDetect(address string, rule *fault_tolerance.FaultDetectRule) bool {
+func (g *Detector) doTCPDetect(network, address string, rule *fault_tolerance.FaultDetectRule) bool {
 	timeout := g.timeout
 	if rule != nil {
 		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
 	}
 	// 建立连接
-	conn, err := net.DialTimeout("tcp", address, timeout)
+	conn, err := net.DialTimeout(network, address, timeout)
 	if err != nil {
 		log.GetDetectLogger().Errorf("[HealthCheck][tcp] fail to check %s, err is %v", address, err)
 		return false
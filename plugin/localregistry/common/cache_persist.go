@@ -18,18 +18,16 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
 	"net/url"
-	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-multierror"
-	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -53,18 +51,29 @@ type CachePersistHandler struct {
 	maxReadRetry  int
 	retryInterval time.Duration
 	marshaler     *jsonpb.Marshaler
+	backendName   string
+	backend       PersistBackend
 }
 
-// CacheFileInfo 文件信息
+// CacheFileInfo 缓存信息
 type CacheFileInfo struct {
-	Msg      proto.Message
-	FileInfo os.FileInfo
+	Msg proto.Message
+	// ModTime 缓存最近一次写入的时间
+	ModTime time.Time
 }
 
 // NewCachePersistHandler create persistence handler
 func NewCachePersistHandler(persistEnable bool, persistDir string, maxWriteRetry int,
+	maxReadRetry int, retryInterval time.Duration) (*CachePersistHandler, error) {
+	return NewCachePersistHandlerWithBackend(PersistBackendFile, persistEnable, persistDir, maxWriteRetry,
+		maxReadRetry, retryInterval)
+}
+
+// NewCachePersistHandlerWithBackend create persistence handler with the specified backend
+func NewCachePersistHandlerWithBackend(backend string, persistEnable bool, persistDir string, maxWriteRetry int,
 	maxReadRetry int, retryInterval time.Duration) (*CachePersistHandler, error) {
 	handler := &CachePersistHandler{}
+	handler.backendName = backend
 	handler.persistEnable = persistEnable
 	handler.persistDir = persistDir
 	handler.maxReadRetry = maxReadRetry
@@ -81,94 +90,113 @@ func (cph *CachePersistHandler) init() error {
 	if nil == cph.marshaler {
 		cph.marshaler = &jsonpb.Marshaler{}
 	}
-	if cph.persistEnable {
-		return model.EnsureAndVerifyDir(cph.persistDir)
+	if !cph.persistEnable {
+		// 未开启持久化时不创建目录及存储文件，只支持读取已有的缓存文件
+		cph.backend, _ = newFileBackend(cph.persistDir)
+		return nil
+	}
+	if err := model.EnsureAndVerifyDir(cph.persistDir); err != nil {
+		return err
+	}
+	backend, err := NewPersistBackend(cph.backendName, cph.persistDir)
+	if err != nil {
+		return err
+	}
+	cph.backend = backend
+	if cph.backendName != PersistBackendFile {
+		// 切换存储后端后，将原有的缓存文件迁移到新的存储后端中
+		fileBackend, _ := newFileBackend(cph.persistDir)
+		migrated, err := migrateKeys(fileBackend, backend)
+		if err != nil {
+			log.GetBaseLogger().Warnf("fail to migrate cache files to %s backend: %v", cph.backendName, err)
+		} else if migrated > 0 {
+			log.GetBaseLogger().Infof("%d cache files migrated to %s backend", migrated, cph.backendName)
+		}
 	}
 	return nil
 }
 
-// LoadPersistedServices 加载目录中所有的缓存文件
+// Close 关闭持久化存储后端
+func (cph *CachePersistHandler) Close() error {
+	return cph.backend.Close()
+}
+
+// LoadPersistedServices 加载所有已持久化的服务缓存
 func (cph *CachePersistHandler) LoadPersistedServices() map[model.ServiceEventKey]CacheFileInfo {
-	cacheFiles, _ := filepath.Glob(filepath.Join(cph.persistDir, PatternGlob+CacheSuffix))
-	if len(cacheFiles) == 0 {
+	keys, err := cph.backend.Keys()
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to list persisted cache in %s, error is %v", cph.persistDir, err)
 		return nil
 	}
-	values := make(map[model.ServiceEventKey]CacheFileInfo, len(cacheFiles))
-	for _, cacheFile := range cacheFiles {
-		msg := &apiservice.DiscoverResponse{}
-		svcValueKey, fileInfo, err := cph.loadCacheFromFile(cacheFile, msg)
-		if err != nil {
-			log.GetBaseLogger().Errorf("fail to load cache from file %s, error is %v", cacheFile, err)
+	values := make(map[model.ServiceEventKey]CacheFileInfo, len(keys))
+	for _, key := range keys {
+		if !isCacheKey(key) {
 			continue
 		}
-		// 加载缓存时，也要将实例进行排序
-		sort.Sort(pb.InstSlice(msg.Instances))
-		info := CacheFileInfo{
-			Msg:      msg,
-			FileInfo: fileInfo,
+		svcValueKey, info, err := cph.loadPersistedService(key)
+		if err != nil {
+			log.GetBaseLogger().Errorf("fail to load cache from %s, error is %v", key, err)
+			continue
 		}
 		values[*svcValueKey] = info
 	}
+	if len(values) == 0 {
+		return nil
+	}
 	return values
 }
 
-// 从文件中加载服务缓存
-func (cph *CachePersistHandler) loadCacheFromFile(
-	cacheFile string, message proto.Message) (*model.ServiceEventKey, os.FileInfo, error) {
-	svcValueKey, err := cph.fileNameToServiceEventKey(cacheFile)
+// 从存储后端中加载服务缓存
+func (cph *CachePersistHandler) loadCache(key string, message proto.Message) (*model.ServiceEventKey, time.Time, error) {
+	svcValueKey, err := cph.fileNameToServiceEventKey(key)
 	if err != nil {
-		return nil, nil, multierror.Prefix(err, fmt.Sprintf("Fail to decode the cache file name %s: ", cacheFile))
+		return nil, time.Time{}, multierror.Prefix(err, fmt.Sprintf("Fail to decode the cache file name %s: ", key))
 	}
-	fileInfo, err := os.Stat(cacheFile)
+	modTime, err := cph.loadMessage(key, message, 0)
 	if err != nil {
-		return svcValueKey, nil, multierror.Prefix(err, fmt.Sprintf("Fail to Stat the cache file name %s: ",
-			cacheFile))
-	}
-	if err = cph.loadMessageFromAbsoluteFile(cacheFile, message, 0); err != nil {
-		return svcValueKey, nil, err
+		return svcValueKey, time.Time{}, err
 	}
 	if err = pb.ValidateMessage(svcValueKey, message); err != nil {
-		return svcValueKey, nil, multierror.Prefix(err, "Fail to validate file cache: ")
+		return svcValueKey, time.Time{}, multierror.Prefix(err, "Fail to validate file cache: ")
 	}
-	return svcValueKey, fileInfo, nil
+	return svcValueKey, modTime, nil
 }
 
 // LoadMessageFromFile 从相对文件中加载缓存
 func (cph *CachePersistHandler) LoadMessageFromFile(relativeFile string, message proto.Message) error {
-	absFile := filepath.Join(cph.persistDir, relativeFile)
-	return cph.loadMessageFromAbsoluteFile(absFile, message, cph.maxReadRetry)
+	_, err := cph.loadMessage(relativeFile, message, cph.maxReadRetry)
+	return err
 }
 
-// 从绝对文件中加载缓存
-func (cph *CachePersistHandler) loadMessageFromAbsoluteFile(cacheFile string, message proto.Message,
-	maxRetry int) error {
-	log.GetBaseLogger().Infof("Start to load cache from %s", cacheFile)
+// 从存储后端中加载缓存
+func (cph *CachePersistHandler) loadMessage(key string, message proto.Message, maxRetry int) (time.Time, error) {
+	log.GetBaseLogger().Infof("Start to load cache from %s", key)
 	var lastErr error
 	var retryTimes int
 	for retryTimes = 0; retryTimes <= maxRetry; retryTimes++ {
-		cacheJson, err := os.OpenFile(cacheFile, os.O_RDONLY, 0600)
+		data, modTime, err := cph.backend.Read(key)
 		if err != nil {
 			lastErr = model.NewSDKError(model.ErrCodeDiskError, err, "fail to read file cache")
-			// 文件打开失败的话，重试没有意义，直接失败
+			// 读取失败的话，重试没有意义，直接失败
 			break
 		}
-		err = jsonpb.Unmarshal(cacheJson, message)
-		_ = cacheJson.Close()
+		err = jsonpb.Unmarshal(bytes.NewReader(data), message)
 		if err != nil {
 			lastErr = multierror.Prefix(err, "Fail to unmarshal file cache: ")
 			time.Sleep(cph.retryInterval)
 			// 解码失败可能是读到了部分数据，所以这里可以重试
 			continue
 		}
-		return nil
+		return modTime, nil
 	}
-	return multierror.Prefix(lastErr,
-		fmt.Sprintf("load message from %s failed after retry %d times", cacheFile, retryTimes))
+	return time.Time{}, multierror.Prefix(lastErr,
+		fmt.Sprintf("load message from %s failed after retry %d times", key, retryTimes))
 }
 
 // 从文件名转化为serviceKey
 func (cph *CachePersistHandler) fileNameToServiceEventKey(fileName string) (*model.ServiceEventKey, error) {
-	svcKeyFile := fileName[0 : len(fileName)-len(CacheSuffix)]
+	svcKeyFile := filepath.Base(fileName)
+	svcKeyFile = svcKeyFile[0 : len(svcKeyFile)-len(CacheSuffix)]
 	pieces := strings.Split(svcKeyFile, "#")
 	namespace, err := url.QueryUnescape(pieces[1])
 	if err != nil {
@@ -191,21 +219,13 @@ func (cph *CachePersistHandler) fileNameToServiceEventKey(fileName string) (*mod
 
 // DeleteCacheFromFile 删除缓存文件
 func (cph *CachePersistHandler) DeleteCacheFromFile(fileName string) {
-	fileToDelete := filepath.Join(cph.persistDir, fileName)
-	log.GetBaseLogger().Infof("Start to delete cache for %s", fileToDelete)
+	log.GetBaseLogger().Infof("Start to delete cache for %s", fileName)
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
-		err := os.Remove(fileToDelete)
-		if err != nil {
-			if !os.IsNotExist(err) {
-				log.GetBaseLogger().Warnf("Fail to delete cache file %s,"+
-					" because %s, next retrytimes %d", fileToDelete, err.Error(), retryTimes)
-			} else {
-				log.GetBaseLogger().Warnf("Fail to delete cache file %s,"+
-					" error %s is not retryable, stop retrying", fileToDelete, err.Error())
-				return
-			}
+		if err := cph.backend.Delete(fileName); err != nil {
+			log.GetBaseLogger().Warnf("Fail to delete cache file %s,"+
+				" because %s, next retrytimes %d", fileName, err.Error(), retryTimes)
 		} else {
-			log.GetBaseLogger().Infof("Success to delete cache file %s", fileToDelete)
+			log.GetBaseLogger().Infof("Success to delete cache file %s", fileName)
 			return
 		}
 		time.Sleep(cph.retryInterval)
@@ -214,67 +234,27 @@ func (cph *CachePersistHandler) DeleteCacheFromFile(fileName string) {
 
 // SaveMessageToFile 按服务来进行缓存存储
 func (cph *CachePersistHandler) SaveMessageToFile(fileName string, svcResp proto.Message) {
-	fileToAdd := filepath.Join(cph.persistDir, fileName)
-	log.GetBaseLogger().Infof("Start to save cache to file %s", fileToAdd)
+	log.GetBaseLogger().Infof("Start to save cache to file %s", fileName)
 	msg, err := cph.marshaler.MarshalToString(svcResp)
 	if err != nil {
-		log.GetBaseLogger().Warnf("Fail to marshal the service response for %s", fileToAdd)
+		log.GetBaseLogger().Warnf("Fail to marshal the service response for %s", fileName)
 		return
 	}
 	for retryTimes := 0; retryTimes <= cph.maxWriteRetry; retryTimes++ {
-		err = cph.doWriteFile(fileToAdd, []byte(msg))
+		err = cph.backend.Write(fileName, []byte(msg))
 		if err != nil {
 			if retryTimes > 0 {
 				log.GetBaseLogger().Warnf("Fail to write cache file %s, error: %s,"+
-					" retry times: %v", fileToAdd, err.Error(), retryTimes)
+					" retry times: %v", fileName, err.Error(), retryTimes)
 			}
 		} else {
-			log.GetBaseLogger().Infof("Success to write cache file %s", fileToAdd)
+			log.GetBaseLogger().Infof("Success to write cache file %s", fileName)
 			return
 		}
 		time.Sleep(cph.retryInterval)
 	}
 }
 
-// 实际写文件
-func (cph *CachePersistHandler) doWriteFile(cacheFile string, msg []byte) error {
-	tempFileName := cacheFile + ".tmp"
-	tmpFile, err := os.OpenFile(tempFileName, os.O_WRONLY|os.O_CREATE, 0600)
-	if err != nil {
-		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to open file %s to write", tempFileName)
-	}
-	n, err := tmpFile.Write(msg)
-	if err == nil && n < len(msg) {
-		return model.NewSDKError(model.ErrCodeDiskError, nil, "unable to write all bytes to file %s", tempFileName)
-	}
-	if err = cph.closeTmpFile(tmpFile, cacheFile); err != nil {
-		_ = os.Remove(tempFileName)
-		return err
-	}
-	return nil
-}
-
-// 关闭文件
-func (cph *CachePersistHandler) closeTmpFile(tmpFile *os.File, cacheFile string) error {
-	if err := tmpFile.Sync(); err != nil {
-		_ = tmpFile.Close()
-		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to sync file %s", tmpFile.Name())
-	}
-	if err := tmpFile.Close(); err != nil {
-		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to close file %s", tmpFile.Name())
-	}
-	if model.PathExist(cacheFile) {
-		if err := os.Chmod(cacheFile, 0600); err != nil {
-			return model.NewSDKError(model.ErrCodeDiskError, err, "fail to chmod file %s", cacheFile)
-		}
-	}
-	err := os.Rename(tmpFile.Name(), cacheFile)
-	if err != nil {
-		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to rename file %s to %s", tmpFile.Name(), cacheFile)
-	}
-	return nil
-}
-
 // ServiceEventKeyToFileName 服务名转化为文件名
 func ServiceEventKeyToFileName(svcKey model.ServiceEventKey) string {
 	svcKey.Namespace = url.QueryEscape(svcKey.Namespace)
@@ -0,0 +1,219 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+const (
+	// preloadProgressSteps 加载过程中最多上报的进度次数，避免缓存数量较多时频繁上报
+	preloadProgressSteps = 10
+)
+
+// PreloadOptions 持久化缓存并行加载选项
+type PreloadOptions struct {
+	// Parallelism 并行加载的协程数，不大于0时串行加载
+	Parallelism int
+	// Deadline 最长等待时间，超时后返回已加载的结果，剩余的缓存转为后台加载，不大于0时等待全部加载完成
+	Deadline time.Duration
+	// Stop 关闭后停止后台加载
+	Stop <-chan struct{}
+	// OnLoaded 单个缓存加载成功后的回调，会在多个协程中并发调用
+	OnLoaded func(svcKey model.ServiceEventKey, info CacheFileInfo)
+	// OnProgress 加载进度回调，回调是串行的
+	OnProgress func(event *model.CachePreloadEvent)
+}
+
+// PreloadPersistedServices 并行加载所有已持久化的服务缓存，全部加载完成或者超过等待时间后返回当前的加载进度，
+// 超时后剩余的缓存在后台继续加载，加载结果同样通过 OnLoaded 回调
+func (cph *CachePersistHandler) PreloadPersistedServices(opts PreloadOptions) model.CachePreloadEvent {
+	keys, err := cph.backend.Keys()
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to list persisted cache in %s, error is %v", cph.persistDir, err)
+	}
+	cacheKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if isCacheKey(key) {
+			cacheKeys = append(cacheKeys, key)
+		}
+	}
+	progress := newPreloadProgress(len(cacheKeys), opts.OnProgress)
+	if len(cacheKeys) == 0 {
+		return progress.snapshot()
+	}
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	if parallelism > len(cacheKeys) {
+		parallelism = len(cacheKeys)
+	}
+	keyChan := make(chan string, len(cacheKeys))
+	for _, key := range cacheKeys {
+		keyChan <- key
+	}
+	close(keyChan)
+	done := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keyChan {
+				select {
+				case <-opts.Stop:
+					return
+				default:
+				}
+				svcKey, info, err := cph.loadPersistedService(key)
+				if err != nil {
+					log.GetBaseLogger().Errorf("fail to load cache from %s, error is %v", key, err)
+					progress.record(false)
+					continue
+				}
+				if opts.OnLoaded != nil {
+					opts.OnLoaded(*svcKey, info)
+				}
+				progress.record(true)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	if opts.Deadline <= 0 {
+		<-done
+		return progress.snapshot()
+	}
+	timer := time.NewTimer(opts.Deadline)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		progress.toBackground()
+	}
+	return progress.snapshot()
+}
+
+// 加载单个已持久化的服务缓存
+func (cph *CachePersistHandler) loadPersistedService(key string) (*model.ServiceEventKey, CacheFileInfo, error) {
+	msg := &apiservice.DiscoverResponse{}
+	svcValueKey, modTime, err := cph.loadCache(key, msg)
+	if err != nil {
+		return nil, CacheFileInfo{}, err
+	}
+	// 加载缓存时，也要将实例进行排序
+	sort.Sort(pb.InstSlice(msg.Instances))
+	return svcValueKey, CacheFileInfo{Msg: msg, ModTime: modTime}, nil
+}
+
+// preloadProgress 持久化缓存加载进度
+type preloadProgress struct {
+	mutex      sync.Mutex
+	event      model.CachePreloadEvent
+	start      time.Time
+	step       int
+	nextReport int
+	onProgress func(event *model.CachePreloadEvent)
+}
+
+func newPreloadProgress(total int, onProgress func(event *model.CachePreloadEvent)) *preloadProgress {
+	p := &preloadProgress{
+		start:      time.Now(),
+		onProgress: onProgress,
+	}
+	p.event.Total = total
+	p.step = (total + preloadProgressSteps - 1) / preloadProgressSteps
+	if p.step < 1 {
+		p.step = 1
+	}
+	p.nextReport = p.step
+	if total == 0 {
+		p.event.Done = true
+		p.report()
+	}
+	return p
+}
+
+// record 记录单个缓存的加载结果，每加载完一定比例的缓存以及全部加载完成时上报进度
+func (p *preloadProgress) record(success bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if success {
+		p.event.Loaded++
+	} else {
+		p.event.Failed++
+	}
+	finished := p.event.Loaded + p.event.Failed
+	if finished == p.event.Total {
+		p.event.Done = true
+		p.report()
+		return
+	}
+	if finished >= p.nextReport {
+		p.nextReport += p.step
+		p.report()
+	}
+}
+
+// toBackground 启动等待超时，剩余的缓存转为后台加载
+func (p *preloadProgress) toBackground() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.event.Done {
+		return
+	}
+	p.event.Background = true
+	p.event.Elapsed = time.Since(p.start)
+	log.GetBaseLogger().Warnf("[CachePreload] deadline exceeded after %v, %d of %d persisted caches"+
+		" will be loaded in background", p.event.Elapsed, p.event.Total-p.event.Loaded-p.event.Failed, p.event.Total)
+	p.report()
+}
+
+func (p *preloadProgress) report() {
+	p.event.Elapsed = time.Since(p.start)
+	event := p.event
+	if event.Done {
+		log.GetBaseLogger().Infof("[CachePreload] persisted caches loaded, loaded %d, failed %d, total %d,"+
+			" elapsed %v, background %v", event.Loaded, event.Failed, event.Total, event.Elapsed, event.Background)
+	} else {
+		log.GetBaseLogger().Infof("[CachePreload] loading persisted caches, loaded %d, failed %d, total %d,"+
+			" elapsed %v", event.Loaded, event.Failed, event.Total, event.Elapsed)
+	}
+	if p.onProgress != nil {
+		p.onProgress(&event)
+	}
+}
+
+func (p *preloadProgress) snapshot() model.CachePreloadEvent {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	event := p.event
+	event.Elapsed = time.Since(p.start)
+	return event
+}
@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func persistTestServices(t *testing.T, handler *CachePersistHandler, count int) {
+	for i := 0; i < count; i++ {
+		svcKey := model.ServiceEventKey{
+			ServiceKey: model.ServiceKey{Namespace: "Test", Service: fmt.Sprintf("echo-%d", i)},
+			Type:       model.EventInstances,
+		}
+		handler.SaveMessageToFile(ServiceEventKeyToFileName(svcKey), &apiservice.DiscoverResponse{
+			Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+			Type: apiservice.DiscoverResponse_INSTANCE,
+			Service: &apiservice.Service{
+				Namespace: wrapperspb.String(svcKey.Namespace),
+				Name:      wrapperspb.String(svcKey.Service),
+				Revision:  wrapperspb.String("r1"),
+			},
+		})
+	}
+}
+
+// TestPreloadPersistedServices 测试并行加载持久化缓存的进度上报，以及超时后转为后台加载
+func TestPreloadPersistedServices(t *testing.T) {
+	handler, err := NewCachePersistHandler(true, t.TempDir(), 1, 1, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistTestServices(t, handler, 25)
+
+	loaded := &sync.Map{}
+	var events []model.CachePreloadEvent
+	result := handler.PreloadPersistedServices(PreloadOptions{
+		Parallelism: 4,
+		OnLoaded: func(svcKey model.ServiceEventKey, info CacheFileInfo) {
+			loaded.Store(svcKey, info)
+		},
+		OnProgress: func(event *model.CachePreloadEvent) {
+			events = append(events, *event)
+		},
+	})
+	if !result.Done || result.Loaded != 25 || result.Total != 25 || result.Background {
+		t.Fatalf("expect all caches loaded, got %+v", result)
+	}
+	count := 0
+	loaded.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	if count != 25 {
+		t.Fatalf("expect 25 caches loaded, got %d", count)
+	}
+	if len(events) == 0 || len(events) > preloadProgressSteps+1 || !events[len(events)-1].Done {
+		t.Fatalf("unexpected progress events %+v", events)
+	}
+
+	// 加载被阻塞时，超过等待时间后返回，剩余的缓存在后台继续加载
+	release := make(chan struct{})
+	finished := make(chan model.CachePreloadEvent, 1)
+	result = handler.PreloadPersistedServices(PreloadOptions{
+		Parallelism: 2,
+		Deadline:    10 * time.Millisecond,
+		OnLoaded: func(svcKey model.ServiceEventKey, info CacheFileInfo) {
+			<-release
+		},
+		OnProgress: func(event *model.CachePreloadEvent) {
+			if event.Done {
+				finished <- *event
+			}
+		},
+	})
+	if result.Done || !result.Background {
+		t.Fatalf("expect preload continue in background, got %+v", result)
+	}
+	close(release)
+	select {
+	case event := <-finished:
+		if event.Loaded != 25 || !event.Background {
+			t.Fatalf("unexpected background result %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("background preload not finished")
+	}
+}
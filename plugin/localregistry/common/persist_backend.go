@@ -0,0 +1,234 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// PersistBackendFile 每个资源一个文件的持久化存储，为默认的存储格式
+	PersistBackendFile = "file"
+	// PersistBackendKV 所有资源存放在单个文件中的嵌入式KV存储，适用于订阅大量服务的场景
+	PersistBackendKV = "kv"
+)
+
+// PersistBackend 本地缓存持久化存储后端，key为缓存文件名，如 svc#namespace#service#instance.json
+type PersistBackend interface {
+	// Name 存储后端名称
+	Name() string
+	// Keys 返回已持久化的全部key
+	Keys() ([]string, error)
+	// Read 读取key对应的内容以及最近一次写入的时间
+	Read(key string) ([]byte, time.Time, error)
+	// Write 写入key对应的内容
+	Write(key string, data []byte) error
+	// Delete 删除key，key不存在时不返回错误
+	Delete(key string) error
+	// Close 关闭存储后端
+	Close() error
+}
+
+// PersistBackendFactory 存储后端的构造函数，persistDir为持久化目录
+type PersistBackendFactory func(persistDir string) (PersistBackend, error)
+
+var (
+	persistBackendMutex     sync.RWMutex
+	persistBackendFactories = map[string]PersistBackendFactory{
+		PersistBackendFile: newFileBackend,
+		PersistBackendKV:   newKVBackend,
+	}
+)
+
+// RegisterPersistBackend 注册自定义的持久化存储后端
+func RegisterPersistBackend(name string, factory PersistBackendFactory) {
+	persistBackendMutex.Lock()
+	defer persistBackendMutex.Unlock()
+	persistBackendFactories[name] = factory
+}
+
+// NewPersistBackend 根据名称创建持久化存储后端
+func NewPersistBackend(name string, persistDir string) (PersistBackend, error) {
+	persistBackendMutex.RLock()
+	factory, ok := persistBackendFactories[name]
+	persistBackendMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("persist backend %s not registered", name)
+	}
+	return factory(persistDir)
+}
+
+// MigratePersistBackend 将持久化目录中的缓存从一种存储后端迁移到另一种存储后端，
+// 迁移成功的缓存会从源存储后端中删除，返回迁移的缓存数量
+func MigratePersistBackend(persistDir string, from string, to string) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	src, err := NewPersistBackend(from, persistDir)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	dst, err := NewPersistBackend(to, persistDir)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+	return migrateKeys(src, dst)
+}
+
+func migrateKeys(src PersistBackend, dst PersistBackend) (int, error) {
+	keys, err := src.Keys()
+	if err != nil {
+		return 0, err
+	}
+	var migrated int
+	for _, key := range keys {
+		if !isCacheKey(key) {
+			continue
+		}
+		data, _, err := src.Read(key)
+		if err != nil {
+			return migrated, err
+		}
+		if err = dst.Write(key, data); err != nil {
+			return migrated, err
+		}
+		if err = src.Delete(key); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// isCacheKey 是否为服务缓存的key
+func isCacheKey(key string) bool {
+	matched, _ := filepath.Match(PatternGlob+CacheSuffix, key)
+	return matched
+}
+
+// fileBackend 每个key对应持久化目录中的一个文件
+type fileBackend struct {
+	persistDir string
+}
+
+func newFileBackend(persistDir string) (PersistBackend, error) {
+	return &fileBackend{persistDir: persistDir}, nil
+}
+
+// Name 存储后端名称
+func (f *fileBackend) Name() string {
+	return PersistBackendFile
+}
+
+// Keys 返回目录中全部的缓存文件名
+func (f *fileBackend) Keys() ([]string, error) {
+	files, err := ioutil.ReadDir(f.persistDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, model.NewSDKError(model.ErrCodeDiskError, err, "fail to read dir %s", f.persistDir)
+	}
+	keys := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasSuffix(file.Name(), ".tmp") {
+			continue
+		}
+		keys = append(keys, file.Name())
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Read 读取缓存文件
+func (f *fileBackend) Read(key string) ([]byte, time.Time, error) {
+	cacheFile := filepath.Join(f.persistDir, key)
+	fileInfo, err := os.Stat(cacheFile)
+	if err != nil {
+		return nil, time.Time{}, model.NewSDKError(model.ErrCodeDiskError, err, "fail to stat file %s", cacheFile)
+	}
+	data, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, time.Time{}, model.NewSDKError(model.ErrCodeDiskError, err, "fail to read file %s", cacheFile)
+	}
+	return data, fileInfo.ModTime(), nil
+}
+
+// Write 先写临时文件再重命名，避免读到写了一半的文件
+func (f *fileBackend) Write(key string, data []byte) error {
+	cacheFile := filepath.Join(f.persistDir, key)
+	tempFileName := cacheFile + ".tmp"
+	tmpFile, err := os.OpenFile(tempFileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to open file %s to write", tempFileName)
+	}
+	n, err := tmpFile.Write(data)
+	if err == nil && n < len(data) {
+		_ = tmpFile.Close()
+		return model.NewSDKError(model.ErrCodeDiskError, nil, "unable to write all bytes to file %s", tempFileName)
+	}
+	if err = closeTmpFile(tmpFile, cacheFile); err != nil {
+		_ = os.Remove(tempFileName)
+		return err
+	}
+	return nil
+}
+
+// Delete 删除缓存文件
+func (f *fileBackend) Delete(key string) error {
+	if err := os.Remove(filepath.Join(f.persistDir, key)); err != nil && !os.IsNotExist(err) {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to delete file %s", key)
+	}
+	return nil
+}
+
+// Close 文件存储无需关闭
+func (f *fileBackend) Close() error {
+	return nil
+}
+
+// closeTmpFile 同步并关闭临时文件，然后重命名为目标文件
+func closeTmpFile(tmpFile *os.File, cacheFile string) error {
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to sync file %s", tmpFile.Name())
+	}
+	if err := tmpFile.Close(); err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to close file %s", tmpFile.Name())
+	}
+	if model.PathExist(cacheFile) {
+		if err := os.Chmod(cacheFile, 0600); err != nil {
+			return model.NewSDKError(model.ErrCodeDiskError, err, "fail to chmod file %s", cacheFile)
+		}
+	}
+	if err := os.Rename(tmpFile.Name(), cacheFile); err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to rename file %s to %s", tmpFile.Name(), cacheFile)
+	}
+	return nil
+}
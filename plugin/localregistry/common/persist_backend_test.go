@@ -0,0 +1,198 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestKVBackendRecovery 测试KV存储的读写、重新打开后的索引重建以及尾部损坏记录的截断
+func TestKVBackendRecovery(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Write("a", []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Write("b", []byte("v2")); err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Write("a", []byte("v3")); err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 模拟写了一半的记录
+	file, err := os.OpenFile(filepath.Join(dir, KVFileName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte{1, 2, 3})
+	_ = file.Close()
+
+	backend, err = NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	keys, _ := backend.Keys()
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	data, modTime, err := backend.Read("a")
+	if err != nil || !bytes.Equal(data, []byte("v3")) || time.Since(modTime) > time.Minute {
+		t.Fatalf("unexpected value %s, %v, %v", data, modTime, err)
+	}
+	if _, _, err = backend.Read("b"); err == nil {
+		t.Fatal("expect deleted key not found")
+	}
+}
+
+// TestKVBackendCompact 测试无效数据过多时的压缩
+func TestKVBackendCompact(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	value := bytes.Repeat([]byte("x"), 64*1024)
+	for i := 0; i < 40; i++ {
+		if err = backend.Write("key", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info, err := os.Stat(filepath.Join(dir, KVFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() >= kvCompactMinSize {
+		t.Fatalf("expect kv file compacted, size %d", info.Size())
+	}
+	data, _, err := backend.Read("key")
+	if err != nil || !bytes.Equal(data, value) {
+		t.Fatal("expect value kept after compaction")
+	}
+}
+
+// TestKVBackendCorruptedLength 测试记录头中的长度字段损坏时截断而不按其分配内存
+func TestKVBackendCorruptedLength(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = backend.Write("a", []byte("v1")); err != nil {
+		t.Fatal(err)
+	}
+	_ = backend.Close()
+	header := make([]byte, kvHeaderSize)
+	binary.BigEndian.PutUint32(header[13:17], 1)
+	binary.BigEndian.PutUint32(header[17:21], 0xffffffff)
+	file, err := os.OpenFile(filepath.Join(dir, KVFileName), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write(header)
+	_ = file.Close()
+
+	backend, err = NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	if data, _, err := backend.Read("a"); err != nil || string(data) != "v1" {
+		t.Fatalf("expect record before corruption kept, got %s, %v", data, err)
+	}
+}
+
+// TestKVBackendExclusive 测试持久化目录不能被多个KV存储同时打开
+func TestKVBackendExclusive(t *testing.T) {
+	dir := t.TempDir()
+	backend, err := NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewPersistBackend(PersistBackendKV, dir); err == nil {
+		t.Fatal("expect shared persistDir refused")
+	}
+	_ = backend.Close()
+	backend, err = NewPersistBackend(PersistBackendKV, dir)
+	if err != nil {
+		t.Fatalf("expect lock released after close, got %v", err)
+	}
+	_ = backend.Close()
+}
+
+// TestMigratePersistBackend 测试缓存文件迁移到KV存储后仍可加载
+func TestMigratePersistBackend(t *testing.T) {
+	dir := t.TempDir()
+	svcKey := model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "Test", Service: "echo"},
+		Type:       model.EventInstances,
+	}
+	msg := &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String(svcKey.Namespace),
+			Name:      wrapperspb.String(svcKey.Service),
+			Revision:  wrapperspb.String("r1"),
+		},
+	}
+	fileHandler, err := NewCachePersistHandler(true, dir, 1, 1, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileHandler.SaveMessageToFile(ServiceEventKeyToFileName(svcKey), msg)
+
+	migrated, err := MigratePersistBackend(dir, PersistBackendFile, PersistBackendKV)
+	if err != nil || migrated != 1 {
+		t.Fatalf("expect 1 cache migrated, got %d, %v", migrated, err)
+	}
+	if model.PathExist(filepath.Join(dir, ServiceEventKeyToFileName(svcKey))) {
+		t.Fatal("expect cache file removed after migration")
+	}
+	kvHandler, err := NewCachePersistHandlerWithBackend(PersistBackendKV, true, dir, 1, 1, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kvHandler.Close()
+	values := kvHandler.LoadPersistedServices()
+	value, ok := values[svcKey]
+	if !ok || value.Msg.(*apiservice.DiscoverResponse).GetService().GetRevision().GetValue() != "r1" {
+		t.Fatalf("expect migrated cache loaded, got %v", values)
+	}
+}
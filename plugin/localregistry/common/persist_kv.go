@@ -0,0 +1,297 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// KVFileName 单文件KV存储的文件名
+	KVFileName = "polaris-cache.kv"
+	// 进程独占KV存储的锁文件，压缩时存储文件会被替换，因此锁加在单独的文件上
+	kvLockFileName = KVFileName + ".lock"
+	// 记录头：crc32(4) + 操作类型(1) + 写入时间(8) + key长度(4) + value长度(4)
+	kvHeaderSize = 21
+	kvOpPut      = byte(0)
+	kvOpDelete   = byte(1)
+	// 文件超过该大小且无效数据超过一半时进行压缩
+	kvCompactMinSize = 1 << 20
+)
+
+var errKVClosed = errors.New("kv backend is closed")
+
+// kvEntry 索引项，记录value在文件中的位置
+type kvEntry struct {
+	offset  int64
+	size    int64
+	modTime time.Time
+}
+
+// kvBackend 追加写的单文件KV存储，启动时顺序扫描文件建立内存索引，无效数据过多时重写文件进行压缩
+type kvBackend struct {
+	mutex     sync.Mutex
+	path      string
+	lockFile  *os.File
+	file      *os.File
+	index     map[string]*kvEntry
+	fileSize  int64
+	liveBytes int64
+}
+
+func newKVBackend(persistDir string) (PersistBackend, error) {
+	if err := model.EnsureAndVerifyDir(persistDir); err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(persistDir, kvLockFileName)
+	lockFile, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeDiskError, err, "fail to open kv lock file %s", lockPath)
+	}
+	// 多个进程同时追加写同一个文件会损坏记录，持久化目录不允许多个进程共享
+	if err = lockKVFile(lockFile); err != nil {
+		_ = lockFile.Close()
+		return nil, model.NewSDKError(model.ErrCodeDiskError, err,
+			"kv file in %s is used by another process, persistDir can not be shared", persistDir)
+	}
+	kv := &kvBackend{path: filepath.Join(persistDir, KVFileName), lockFile: lockFile}
+	if err = kv.open(); err != nil {
+		_ = lockFile.Close()
+		return nil, err
+	}
+	return kv, nil
+}
+
+// open 打开存储文件并重建索引，文件尾部不完整的记录会被截断，打开失败时 kv.file 置为nil
+func (kv *kvBackend) open() error {
+	kv.file = nil
+	file, err := os.OpenFile(kv.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to open kv file %s", kv.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to stat kv file %s", kv.path)
+	}
+	kv.index = make(map[string]*kvEntry)
+	kv.liveBytes = 0
+	var offset int64
+	header := make([]byte, kvHeaderSize)
+	for {
+		if _, err = file.ReadAt(header, offset); err != nil {
+			break
+		}
+		op := header[4]
+		modTime := time.Unix(0, int64(binary.BigEndian.Uint64(header[5:13])))
+		keyLen := int64(binary.BigEndian.Uint32(header[13:17]))
+		valueLen := int64(binary.BigEndian.Uint32(header[17:21]))
+		// 长度字段损坏时不按其分配内存
+		if offset+kvHeaderSize+keyLen+valueLen > info.Size() {
+			err = io.ErrUnexpectedEOF
+			break
+		}
+		body := make([]byte, keyLen+valueLen)
+		if _, err = file.ReadAt(body, offset+kvHeaderSize); err != nil {
+			break
+		}
+		crc := crc32.NewIEEE()
+		_, _ = crc.Write(header[4:])
+		_, _ = crc.Write(body)
+		if crc.Sum32() != binary.BigEndian.Uint32(header[0:4]) {
+			err = io.ErrUnexpectedEOF
+			break
+		}
+		key := string(body[:keyLen])
+		kv.removeIndex(key)
+		if op == kvOpPut {
+			kv.index[key] = &kvEntry{offset: offset + kvHeaderSize + keyLen, size: valueLen, modTime: modTime}
+			kv.liveBytes += kvHeaderSize + keyLen + valueLen
+		}
+		offset += kvHeaderSize + keyLen + valueLen
+	}
+	if err != nil && err != io.EOF {
+		log.GetBaseLogger().Warnf("kv file %s is corrupted at offset %d, truncated: %v", kv.path, offset, err)
+	}
+	if err = file.Truncate(offset); err != nil {
+		_ = file.Close()
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to truncate kv file %s", kv.path)
+	}
+	kv.file = file
+	kv.fileSize = offset
+	return nil
+}
+
+func (kv *kvBackend) removeIndex(key string) {
+	if entry, ok := kv.index[key]; ok {
+		kv.liveBytes -= kvHeaderSize + int64(len(key)) + entry.size
+		delete(kv.index, key)
+	}
+}
+
+// Name 存储后端名称
+func (kv *kvBackend) Name() string {
+	return PersistBackendKV
+}
+
+// Keys 返回全部key
+func (kv *kvBackend) Keys() ([]string, error) {
+	kv.mutex.Lock()
+	defer kv.mutex.Unlock()
+	keys := make([]string, 0, len(kv.index))
+	for key := range kv.index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Read 读取key对应的内容
+func (kv *kvBackend) Read(key string) ([]byte, time.Time, error) {
+	kv.mutex.Lock()
+	defer kv.mutex.Unlock()
+	if kv.file == nil {
+		return nil, time.Time{}, errKVClosed
+	}
+	entry, ok := kv.index[key]
+	if !ok {
+		return nil, time.Time{}, model.NewSDKError(model.ErrCodeDiskError, os.ErrNotExist,
+			"key %s not found in kv file %s", key, kv.path)
+	}
+	data := make([]byte, entry.size)
+	if _, err := kv.file.ReadAt(data, entry.offset); err != nil {
+		return nil, time.Time{}, model.NewSDKError(model.ErrCodeDiskError, err, "fail to read kv file %s", kv.path)
+	}
+	return data, entry.modTime, nil
+}
+
+// Write 追加写入一条记录
+func (kv *kvBackend) Write(key string, data []byte) error {
+	kv.mutex.Lock()
+	defer kv.mutex.Unlock()
+	if kv.file == nil {
+		return errKVClosed
+	}
+	modTime := time.Now()
+	offset, err := kv.append(kv.file, kv.fileSize, kvOpPut, key, data, modTime)
+	if err != nil {
+		return err
+	}
+	kv.removeIndex(key)
+	kv.index[key] = &kvEntry{offset: offset - int64(len(data)), size: int64(len(data)), modTime: modTime}
+	kv.liveBytes += kvHeaderSize + int64(len(key)) + int64(len(data))
+	kv.fileSize = offset
+	return kv.compactIfNeeded()
+}
+
+// Delete 追加写入一条删除记录
+func (kv *kvBackend) Delete(key string) error {
+	kv.mutex.Lock()
+	defer kv.mutex.Unlock()
+	if kv.file == nil {
+		return errKVClosed
+	}
+	if _, ok := kv.index[key]; !ok {
+		return nil
+	}
+	offset, err := kv.append(kv.file, kv.fileSize, kvOpDelete, key, nil, time.Now())
+	if err != nil {
+		return err
+	}
+	kv.removeIndex(key)
+	kv.fileSize = offset
+	return kv.compactIfNeeded()
+}
+
+// Close 同步并关闭存储文件，释放锁文件
+func (kv *kvBackend) Close() error {
+	kv.mutex.Lock()
+	defer kv.mutex.Unlock()
+	var err error
+	if kv.file != nil {
+		_ = kv.file.Sync()
+		err = kv.file.Close()
+		kv.file = nil
+	}
+	if kv.lockFile != nil {
+		_ = kv.lockFile.Close()
+		kv.lockFile = nil
+	}
+	return err
+}
+
+// append 在offset处写入一条记录，返回写入后的文件末尾位置
+func (kv *kvBackend) append(file *os.File, offset int64, op byte, key string, value []byte,
+	modTime time.Time) (int64, error) {
+	record := make([]byte, kvHeaderSize+len(key)+len(value))
+	record[4] = op
+	binary.BigEndian.PutUint64(record[5:13], uint64(modTime.UnixNano()))
+	binary.BigEndian.PutUint32(record[13:17], uint32(len(key)))
+	binary.BigEndian.PutUint32(record[17:21], uint32(len(value)))
+	copy(record[kvHeaderSize:], key)
+	copy(record[kvHeaderSize+len(key):], value)
+	binary.BigEndian.PutUint32(record[0:4], crc32.ChecksumIEEE(record[4:]))
+	if _, err := file.WriteAt(record, offset); err != nil {
+		return offset, model.NewSDKError(model.ErrCodeDiskError, err, "fail to write kv file %s", file.Name())
+	}
+	return offset + int64(len(record)), nil
+}
+
+// compactIfNeeded 无效数据超过一半时，将有效记录重写到新文件并替换原文件
+func (kv *kvBackend) compactIfNeeded() error {
+	if kv.fileSize < kvCompactMinSize || kv.liveBytes*2 > kv.fileSize {
+		return nil
+	}
+	tmpFile, err := os.OpenFile(kv.path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to create compact file for %s", kv.path)
+	}
+	var offset int64
+	for key, entry := range kv.index {
+		data := make([]byte, entry.size)
+		if _, err = kv.file.ReadAt(data, entry.offset); err != nil {
+			break
+		}
+		if offset, err = kv.append(tmpFile, offset, kvOpPut, key, data, entry.modTime); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to compact kv file %s", kv.path)
+	}
+	if err = closeTmpFile(tmpFile, kv.path); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	_ = kv.file.Close()
+	log.GetBaseLogger().Infof("kv file %s compacted from %d to %d bytes", kv.path, kv.fileSize, offset)
+	return kv.open()
+}
@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// SnapshotVersion 快照文件格式版本
+const SnapshotVersion = 1
+
+// Snapshot 本地缓存快照，用于将一个单元的拓扑预先注入到另一个单元的备用进程中
+type Snapshot struct {
+	Version    int              `json:"version"`
+	CreateTime time.Time        `json:"createTime"`
+	Resources  []*SnapshotEntry `json:"resources"`
+}
+
+// SnapshotEntry 快照中的单个缓存资源
+type SnapshotEntry struct {
+	Namespace string          `json:"namespace"`
+	Service   string          `json:"service"`
+	Type      string          `json:"type"`
+	Message   json.RawMessage `json:"message"`
+}
+
+// SaveSnapshot 将缓存消息序列化到快照文件
+func SaveSnapshot(path string, messages map[model.ServiceEventKey]proto.Message) error {
+	marshaler := &jsonpb.Marshaler{}
+	snapshot := &Snapshot{
+		Version:    SnapshotVersion,
+		CreateTime: time.Now(),
+		Resources:  make([]*SnapshotEntry, 0, len(messages)),
+	}
+	for svcKey, msg := range messages {
+		buf := &bytes.Buffer{}
+		if err := marshaler.Marshal(buf, msg); err != nil {
+			return model.NewSDKError(model.ErrCodeInternalError, err, "fail to marshal snapshot resource %s", svcKey)
+		}
+		snapshot.Resources = append(snapshot.Resources, &SnapshotEntry{
+			Namespace: svcKey.Namespace,
+			Service:   svcKey.Service,
+			Type:      svcKey.Type.String(),
+			Message:   buf.Bytes(),
+		})
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeInternalError, err, "fail to marshal snapshot")
+	}
+	if err = ioutil.WriteFile(path, data, 0600); err != nil {
+		return model.NewSDKError(model.ErrCodeDiskError, err, "fail to write snapshot file %s", path)
+	}
+	return nil
+}
+
+// LoadSnapshot 从快照文件中加载缓存消息，无法识别或者校验失败的资源会被跳过
+func LoadSnapshot(path string) (map[model.ServiceEventKey]proto.Message, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeDiskError, err, "fail to read snapshot file %s", path)
+	}
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to unmarshal snapshot file %s", path)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"unsupported snapshot version %d", snapshot.Version)
+	}
+	messages := make(map[model.ServiceEventKey]proto.Message, len(snapshot.Resources))
+	for _, entry := range snapshot.Resources {
+		svcKey := model.ServiceEventKey{
+			ServiceKey: model.ServiceKey{Namespace: entry.Namespace, Service: entry.Service},
+			Type:       model.ToEventType(entry.Type),
+		}
+		if svcKey.Type == model.EventUnknown {
+			log.GetBaseLogger().Warnf("[Snapshot] skip resource %s/%s with unknown type %s",
+				entry.Namespace, entry.Service, entry.Type)
+			continue
+		}
+		msg := &apiservice.DiscoverResponse{}
+		if err = jsonpb.Unmarshal(bytes.NewReader(entry.Message), msg); err != nil {
+			log.GetBaseLogger().Warnf("[Snapshot] skip resource %s: %v", svcKey, err)
+			continue
+		}
+		if err = pb.ValidateMessage(&svcKey, msg); err != nil {
+			log.GetBaseLogger().Warnf("[Snapshot] skip invalid resource %s: %v", svcKey, err)
+			continue
+		}
+		sort.Sort(pb.InstSlice(msg.Instances))
+		messages[svcKey] = msg
+	}
+	return messages, nil
+}
@@ -0,0 +1,66 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	svcKey := model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "Test", Service: "svc#1"},
+		Type:       model.EventInstances,
+	}
+	msg := &apiservice.DiscoverResponse{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)),
+		Type: apiservice.DiscoverResponse_INSTANCE,
+		Service: &apiservice.Service{
+			Namespace: wrapperspb.String(svcKey.Namespace),
+			Name:      wrapperspb.String(svcKey.Service),
+			Revision:  wrapperspb.String("r1"),
+		},
+		Instances: []*apiservice.Instance{{
+			Id:   wrapperspb.String("inst-1"),
+			Host: wrapperspb.String("127.0.0.1"),
+			Port: wrapperspb.UInt32(8080),
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := SaveSnapshot(path, map[model.ServiceEventKey]proto.Message{svcKey: msg}); err != nil {
+		t.Fatalf("save snapshot: %v", err)
+	}
+	messages, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("load snapshot: %v", err)
+	}
+	loaded, ok := messages[svcKey]
+	if !ok {
+		t.Fatalf("resource %s not found in snapshot", svcKey)
+	}
+	if !proto.Equal(loaded, msg) {
+		t.Fatalf("expect %v, got %v", msg, loaded)
+	}
+}
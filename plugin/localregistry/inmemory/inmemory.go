@@ -39,6 +39,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 	lrplug "github.com/polarismesh/polaris-go/plugin/localregistry/common"
 )
 
@@ -92,8 +93,18 @@ type LocalCache struct {
 	startUseFileCache bool
 	// pushEmptyProtection 实例推空保护开关
 	pushEmptyProtection bool
+	// healthSmoothingThreshold 实例健康状态平滑阈值，大于1时启用平滑
+	healthSmoothingThreshold int
 	// 缓存文件的有效时间
 	cacheFromPersistAvailableInterval time.Duration
+	// 启动时并行加载持久化缓存的协程数
+	preloadParallelism int
+	// 启动时加载持久化缓存的最长等待时间
+	preloadDeadline time.Duration
+	// 缓存内存预算
+	memoryBudget config.MemoryBudgetConfig
+	// 是否处于降载模式
+	shedding uint32
 }
 
 // 系统服务集群及刷新间隔信息
@@ -122,6 +133,9 @@ func (g *LocalCache) Destroy() error {
 	if err != nil {
 		return err
 	}
+	if g.cachePersistHandler != nil {
+		return g.cachePersistHandler.Close()
+	}
 	return nil
 }
 
@@ -147,6 +161,7 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 	}
 	g.globalConfig = ctx.Config
 	g.pushEmptyProtection = ctx.Config.GetConsumer().GetLocalCache().GetPushEmptyProtection()
+	g.healthSmoothingThreshold = ctx.Config.GetConsumer().GetLocalCache().GetHealthSmoothingThreshold()
 	g.servicesMutex = &sync.RWMutex{}
 	g.serviceWatchers = make(map[model.ServiceEventKey]int32, 0)
 	g.serviceRefreshInterval = ctx.Config.GetConsumer().GetLocalCache().GetServiceRefreshInterval()
@@ -167,7 +182,11 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 	// 批量服务
 	g.eventToCacheHandlers[model.EventServices] = g.newServicesHandler()
 	g.cacheFromPersistAvailableInterval = ctx.Config.GetConsumer().GetLocalCache().GetPersistAvailableInterval()
-	g.cachePersistHandler, err = lrplug.NewCachePersistHandler(
+	g.preloadParallelism = ctx.Config.GetConsumer().GetLocalCache().GetPreloadParallelism()
+	g.preloadDeadline = ctx.Config.GetConsumer().GetLocalCache().GetPreloadDeadline()
+	g.memoryBudget = ctx.Config.GetConsumer().GetLocalCache().GetMemoryBudget()
+	g.cachePersistHandler, err = lrplug.NewCachePersistHandlerWithBackend(
+		ctx.Config.GetConsumer().GetLocalCache().GetPersistBackend(),
 		g.persistEnable,
 		g.persistDir,
 		ctx.Config.GetConsumer().GetLocalCache().GetPersistMaxWriteRetry(),
@@ -218,9 +237,12 @@ func (g *LocalCache) logServiceMap() {
 func (g *LocalCache) Start() error {
 	g.loadCacheFromFiles()
 	if g.persistEnable {
-		go g.eliminateExpiredCache()
+		profiling.Go(g.Name(), "eliminateExpiredCache", g.eliminateExpiredCache)
+	}
+	profiling.Go(g.Name(), "logServiceMap", g.logServiceMap)
+	if g.memoryBudgetEnabled() {
+		profiling.Go(g.Name(), "memoryBudgetCheck", g.doMemoryBudgetCheck)
 	}
-	go g.logServiceMap()
 	return nil
 }
 
@@ -449,12 +471,24 @@ func (g *LocalCache) messageToServiceInstances(cachedValue interface{}, value pr
 		}
 	}
 	svcInstances := pb.NewServiceInstancesInProto(respInProto, createLocalValueFunc, pluginValues, svcLocalValue)
+	g.observeInstancesHealth(svcInstances)
 	if cacheLoaded {
 		svcInstances.CacheLoaded = 1
 	}
 	return svcInstances
 }
 
+// observeInstancesHealth 启用健康状态平滑时，记录一次服务端下发的实例健康状态
+func (g *LocalCache) observeInstancesHealth(svcInstances *pb.ServiceInstancesInProto) {
+	if g.healthSmoothingThreshold <= 1 {
+		return
+	}
+	if svcInstances.ObserveHealth(g.healthSmoothingThreshold) {
+		log.GetBaseLogger().Infof("smoothed health status changed for service %s::%s",
+			svcInstances.GetNamespace(), svcInstances.GetService())
+	}
+}
+
 // 转换为北极星命名空间下的插件链
 func (g *LocalCache) toNamespacePluginValues() *pb.SvcPluginValues {
 	values := &pb.SvcPluginValues{}
@@ -516,6 +550,27 @@ func (g *LocalCache) LoadInstances(svcKey *model.ServiceKey) (*common.Notifier,
 	return g.loadRemoteValue(svcEvKey, g.eventToCacheHandlers[svcEvKey.Type])
 }
 
+// RefreshInstances 缓存的实例超过 maxStaleness 未经服务端确认时，返回等待下一次服务端应答的通知器，
+// 缓存足够新鲜时返回nil，缓存尚未加载时发起加载
+func (g *LocalCache) RefreshInstances(svcKey *model.ServiceKey, maxStaleness time.Duration) (*common.Notifier, error) {
+	svcEvKey := model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Service: svcKey.Service, Namespace: svcKey.Namespace},
+		Type:       model.EventInstances}
+	value, ok := g.serviceMap.Load(svcEvKey)
+	if !ok {
+		return g.LoadInstances(svcKey)
+	}
+	cacheObject := value.(*CacheObject)
+	lastUpdate := cacheObject.GetLastRemoteUpdateTime()
+	if !lastUpdate.IsZero() && g.globalCtx.Since(lastUpdate) <= maxStaleness {
+		return nil, nil
+	}
+	if atomic.LoadUint32(&cacheObject.hasRegistered) == 0 {
+		return g.LoadInstances(svcKey)
+	}
+	return cacheObject.GetRefreshNotifier(), nil
+}
+
 // loadRemoteValue 通用远程查询逻辑
 func (g *LocalCache) loadRemoteValue(svcKey *model.ServiceEventKey, handler CacheHandlers) (*common.Notifier, error) {
 	if g.IsDestroyed() {
@@ -1031,21 +1086,37 @@ func (g *LocalCache) LoadServiceRule(svcEventKey *model.ServiceEventKey) (*commo
 // 从持久化文件中读取缓存
 func (g *LocalCache) loadCacheFromFiles() {
 	timeNow := time.Now()
-	persistedServices := g.cachePersistHandler.LoadPersistedServices()
-	for svcKey, message := range persistedServices {
-		newSvcKey := &model.ServiceEventKey{
-			ServiceKey: svcKey.ServiceKey,
-			Type:       svcKey.Type,
-		}
-		newSvcObj := NewCacheObjectWithInitValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message.Msg)
-		if timeNow.Sub(message.FileInfo.ModTime()) <= g.cacheFromPersistAvailableInterval {
-			newSvcObj.cachePersistentAvailable = 1
-		} else {
-			newSvcObj.cachePersistentAvailable = 0
-		}
-		g.serviceMap.Store(*newSvcKey, newSvcObj)
-		log.GetBaseLogger().Infof("cache loaded from files, key: %v, cacheObject: %v",
-			newSvcKey, newSvcObj.serviceValueKey)
+	g.cachePersistHandler.PreloadPersistedServices(lrplug.PreloadOptions{
+		Parallelism: g.preloadParallelism,
+		Deadline:    g.preloadDeadline,
+		Stop:        g.Done(),
+		OnLoaded: func(svcKey model.ServiceEventKey, message lrplug.CacheFileInfo) {
+			newSvcKey := &model.ServiceEventKey{
+				ServiceKey: svcKey.ServiceKey,
+				Type:       svcKey.Type,
+			}
+			newSvcObj := NewCacheObjectWithInitValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message.Msg)
+			if timeNow.Sub(message.ModTime) <= g.cacheFromPersistAvailableInterval {
+				newSvcObj.cachePersistentAvailable = 1
+			} else {
+				newSvcObj.cachePersistentAvailable = 0
+			}
+			// 超时转为后台加载后，服务端数据可能已经先于缓存文件到达，此时不能使用缓存文件覆盖
+			if _, loaded := g.serviceMap.LoadOrStore(*newSvcKey, newSvcObj); loaded {
+				return
+			}
+			log.GetBaseLogger().Infof("cache loaded from files, key: %v, cacheObject: %v",
+				newSvcKey, newSvcObj.serviceValueKey)
+		},
+		OnProgress: g.onCachePreloadProgress,
+	})
+}
+
+// onCachePreloadProgress 持久化缓存加载进度回调，通知订阅了加载进度事件的插件
+func (g *LocalCache) onCachePreloadProgress(progress *model.CachePreloadEvent) {
+	event := &common.PluginEvent{EventType: common.OnCachePreloadProgress, EventObject: progress}
+	for _, handler := range g.plugins.GetEventSubscribers(common.OnCachePreloadProgress) {
+		_ = handler.Callback(event)
 	}
 }
 
@@ -1059,11 +1130,44 @@ func (g *LocalCache) enhanceServiceEventHandler(svcEventHandler *serverconnector
 			svcEventHandler.TargetCluster = config.DiscoverCluster
 		}
 	} else {
-		svcEventHandler.RefreshInterval = g.serviceRefreshInterval
+		svcEventHandler.RefreshInterval = g.getServiceRefreshInterval(svcEventHandler.ServiceKey.Namespace)
+		if g.isShedding() {
+			// 降载模式下放大新订阅服务的刷新周期，减少缓存更新的开销
+			svcEventHandler.RefreshInterval *= time.Duration(g.memoryBudget.GetShedRefreshMultiplier())
+		}
 		svcEventHandler.TargetCluster = config.DiscoverCluster
 	}
 }
 
+// getServiceRefreshInterval 获取服务刷新周期，优先使用命名空间级配置
+func (g *LocalCache) getServiceRefreshInterval(namespace string) time.Duration {
+	if nsCfg := g.globalConfig.GetConsumer().GetNamespaceSpecific(namespace); nsCfg != nil &&
+		nsCfg.GetServiceRefreshInterval() > 0 {
+		return nsCfg.GetServiceRefreshInterval()
+	}
+	return g.serviceRefreshInterval
+}
+
+// getServiceExpireTime 获取服务缓存淘汰时间，优先使用命名空间级配置
+func (g *LocalCache) getServiceExpireTime(namespace string) time.Duration {
+	if nsCfg := g.globalConfig.GetConsumer().GetNamespaceSpecific(namespace); nsCfg != nil &&
+		nsCfg.GetServiceExpireTime() > 0 {
+		return nsCfg.GetServiceExpireTime()
+	}
+	return g.serviceExpireTime
+}
+
+// minServiceExpireTime 全局及各命名空间中最短的服务缓存淘汰时间，用于计算淘汰检查周期
+func (g *LocalCache) minServiceExpireTime() time.Duration {
+	expireTime := g.serviceExpireTime
+	for _, nsCfg := range g.globalConfig.GetConsumer().GetNamespacesSpecific() {
+		if nsExpireTime := nsCfg.GetServiceExpireTime(); nsExpireTime > 0 && nsExpireTime < expireTime {
+			expireTime = nsExpireTime
+		}
+	}
+	return expireTime
+}
+
 func (g *LocalCache) checkResourceWatched(resKey model.ServiceEventKey) bool {
 	g.servicesMutex.Lock()
 	defer g.servicesMutex.Unlock()
@@ -1074,7 +1178,7 @@ func (g *LocalCache) checkResourceWatched(resKey model.ServiceEventKey) bool {
 // 淘汰过时缓存
 func (g *LocalCache) eliminateExpiredCache() {
 	// 用于检测服务是否过期的定时器，周期为服务过期时间一半
-	checkTime := g.serviceExpireTime / 2
+	checkTime := g.minServiceExpireTime() / 2
 	if checkTime > config.DefaultMaxServiceExpireCheckTime {
 		checkTime = config.DefaultMaxServiceExpireCheckTime
 	}
@@ -1111,13 +1215,14 @@ func (g *LocalCache) eliminateExpiredCache() {
 					log.GetBaseLogger().Debugf("%s serviceIsWatched, can not expire", svcKey.String())
 					return true
 				}
-				if time.Duration(diffTime) < g.serviceExpireTime {
+				serviceExpireTime := g.getServiceExpireTime(svcKey.Namespace)
+				if time.Duration(diffTime) < serviceExpireTime {
 					return true
 				}
 				svcEvKey := k.(model.ServiceEventKey)
 				log.GetBaseLogger().Infof("%s expired, lastVisited: %v, serviceExpireTime：%v",
 					cacheObjectValue.serviceValueKey, time.Unix(0, lastVisitTime),
-					g.serviceExpireTime)
+					serviceExpireTime)
 				oldValue := cacheObjectValue.LoadValue(false)
 				g.eventToCacheHandlers[svcEvKey.Type].OnEventDeleted(&svcEvKey, oldValue)
 				return true
@@ -1154,6 +1259,51 @@ func (g *LocalCache) LoadPersistedMessage(file string, msg proto.Message) error
 	return g.cachePersistHandler.LoadMessageFromFile(file, msg)
 }
 
+// ExportSnapshot 将本地缓存的服务、实例及规则导出到快照文件
+func (g *LocalCache) ExportSnapshot(path string) error {
+	messages := make(map[model.ServiceEventKey]proto.Message)
+	g.serviceMap.Range(func(k, v interface{}) bool {
+		svcKey := k.(model.ServiceEventKey)
+		if _, ok := g.serverServicesSet[svcKey.ServiceKey]; ok {
+			// 系统服务与单元相关，不导出
+			return true
+		}
+		if msg := v.(*CacheObject).loadMessage(); msg != nil {
+			messages[svcKey] = msg
+		}
+		return true
+	})
+	if err := lrplug.SaveSnapshot(path, messages); err != nil {
+		return err
+	}
+	log.GetBaseLogger().Infof("[Snapshot] %d resources exported to %s", len(messages), path)
+	return nil
+}
+
+// ImportSnapshot 从快照文件导入缓存，本地已存在的资源不会被覆盖
+func (g *LocalCache) ImportSnapshot(path string) error {
+	messages, err := lrplug.LoadSnapshot(path)
+	if err != nil {
+		return err
+	}
+	var imported int
+	for svcKey, message := range messages {
+		newSvcKey := &model.ServiceEventKey{
+			ServiceKey: svcKey.ServiceKey,
+			Type:       svcKey.Type,
+		}
+		newSvcObj := NewCacheObjectWithInitValue(g.eventToCacheHandlers[newSvcKey.Type], g, newSvcKey, message)
+		// 快照数据在远程更新前直接可用，保证备用进程切换后可以立即提供服务
+		newSvcObj.cachePersistentAvailable = 1
+		if _, loaded := g.serviceMap.LoadOrStore(*newSvcKey, newSvcObj); loaded {
+			continue
+		}
+		imported++
+	}
+	log.GetBaseLogger().Infof("[Snapshot] %d of %d resources imported from %s", imported, len(messages), path)
+	return nil
+}
+
 // WatchService 服务订阅
 func (g *LocalCache) WatchService(svcEventKey model.ServiceEventKey) {
 	g.servicesMutex.Lock()
@@ -0,0 +1,163 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+const (
+	// cacheSizeAmplification 解析后的缓存对象（实例索引、集群缓存等）相对原始消息大小的估算倍数
+	cacheSizeAmplification = 2
+	// memoryShedRecoverRatio 降载模式下，估算内存回落到预算的该比例以下时退出降载模式，淘汰也以此为目标
+	memoryShedRecoverRatio = 0.8
+)
+
+// estimateCacheSize 估算缓存对象占用的内存
+func estimateCacheSize(message proto.Message) int64 {
+	return int64(proto.Size(message)) * cacheSizeAmplification
+}
+
+// memoryBudgetEnabled 是否配置了缓存内存预算
+func (g *LocalCache) memoryBudgetEnabled() bool {
+	return g.memoryBudget != nil && g.memoryBudget.GetMaxBytes() > 0
+}
+
+// isShedding 是否处于降载模式
+func (g *LocalCache) isShedding() bool {
+	return atomic.LoadUint32(&g.shedding) == 1
+}
+
+// cacheMemoryUsage 本地缓存的估算内存
+func (g *LocalCache) cacheMemoryUsage() int64 {
+	var used int64
+	g.serviceMap.Range(func(k, v interface{}) bool {
+		used += atomic.LoadInt64(&v.(*CacheObject).memorySize)
+		return true
+	})
+	return used
+}
+
+// doMemoryBudgetCheck 定期检查本地缓存的估算内存是否超出预算
+func (g *LocalCache) doMemoryBudgetCheck() {
+	ticker := time.NewTicker(g.memoryBudget.GetCheckInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.Done():
+			log.GetBaseLogger().Infof("doMemoryBudgetCheck of inmemory localRegistry has been terminated")
+			return
+		case <-ticker.C:
+			g.checkMemoryBudget()
+		}
+	}
+}
+
+// checkMemoryBudget 超出预算时进入降载模式并淘汰最近最少使用的缓存，回落到预算的恢复比例以下时退出降载模式
+func (g *LocalCache) checkMemoryBudget() {
+	budget := g.memoryBudget.GetMaxBytes()
+	used := g.cacheMemoryUsage()
+	recoverBytes := int64(float64(budget) * memoryShedRecoverRatio)
+	wasShedding := g.isShedding()
+	var evicted int
+	if used > budget {
+		if !wasShedding {
+			log.GetBaseLogger().Errorf("[MemoryBudget] local cache memory %d bytes exceeds budget %d bytes, "+
+				"enter shed mode", used, budget)
+		}
+		atomic.StoreUint32(&g.shedding, 1)
+		evicted, used = g.shedCache(used, recoverBytes)
+	} else if wasShedding && used < recoverBytes {
+		log.GetBaseLogger().Warnf("[MemoryBudget] local cache memory %d bytes is back under budget %d bytes, "+
+			"exit shed mode", used, budget)
+		atomic.StoreUint32(&g.shedding, 0)
+	}
+	shedding := g.isShedding()
+	g.reportCacheMemory(used, budget, shedding, evicted)
+	if shedding != wasShedding || evicted > 0 {
+		g.onMemoryShedChanged(&model.MemoryShedEvent{
+			Shedding:    shedding,
+			UsedBytes:   used,
+			BudgetBytes: budget,
+			Evicted:     evicted,
+		})
+	}
+}
+
+// shedCache 按最近访问时间从旧到新淘汰缓存，直到估算内存不超过target，系统服务及被订阅的服务不淘汰
+func (g *LocalCache) shedCache(used int64, target int64) (int, int64) {
+	candidates := make([]*CacheObject, 0)
+	g.serviceMap.Range(func(k, v interface{}) bool {
+		cacheObject := v.(*CacheObject)
+		if _, ok := g.serverServicesSet[cacheObject.serviceValueKey.ServiceKey]; ok {
+			return true
+		}
+		if atomic.LoadInt64(&cacheObject.memorySize) == 0 || g.checkResourceWatched(*cacheObject.serviceValueKey) {
+			return true
+		}
+		candidates = append(candidates, cacheObject)
+		return true
+	})
+	sort.Slice(candidates, func(i, j int) bool {
+		return atomic.LoadInt64(&candidates[i].lastVisitTime) < atomic.LoadInt64(&candidates[j].lastVisitTime)
+	})
+	var evicted int
+	for _, cacheObject := range candidates {
+		if used <= target {
+			break
+		}
+		svcEvKey := *cacheObject.serviceValueKey
+		log.GetBaseLogger().Warnf("[MemoryBudget] evict %s in shed mode, lastVisited: %v", svcEvKey,
+			time.Unix(0, atomic.LoadInt64(&cacheObject.lastVisitTime)))
+		used -= atomic.LoadInt64(&cacheObject.memorySize)
+		oldValue := cacheObject.LoadValue(false)
+		g.eventToCacheHandlers[svcEvKey.Type].OnEventDeleted(&svcEvKey, oldValue)
+		evicted++
+	}
+	return evicted, used
+}
+
+// reportCacheMemory 上报本地缓存的估算内存及降载状态
+func (g *LocalCache) reportCacheMemory(used int64, budget int64, shedding bool, evicted int) {
+	engineValue, ok := g.globalCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	_ = engineValue.(model.Engine).SyncReportStat(model.CacheMemoryStat, &model.CacheMemoryGauge{
+		UsedBytes:   used,
+		BudgetBytes: budget,
+		Shedding:    shedding,
+		Evicted:     evicted,
+	})
+}
+
+// onMemoryShedChanged 通知订阅了降载模式变更事件的插件
+func (g *LocalCache) onMemoryShedChanged(shedEvent *model.MemoryShedEvent) {
+	event := &common.PluginEvent{EventType: common.OnMemoryShedChanged, EventObject: shedEvent}
+	for _, handler := range g.plugins.GetEventSubscribers(common.OnMemoryShedChanged) {
+		_ = handler.Callback(event)
+	}
+}
@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func TestShedCacheEvictLeastRecentlyVisited(t *testing.T) {
+	var evicted []string
+	g := &LocalCache{
+		servicesMutex:     &sync.RWMutex{},
+		serviceWatchers:   make(map[model.ServiceEventKey]int32),
+		serviceMap:        &sync.Map{},
+		serverServicesSet: make(map[model.ServiceKey]clusterAndInterval),
+		eventToCacheHandlers: map[model.EventType]CacheHandlers{
+			model.EventInstances: {OnEventDeleted: func(key *model.ServiceEventKey, cacheValue interface{}) {
+				evicted = append(evicted, key.Service)
+			}},
+		},
+	}
+	// watched为最久未访问的服务，但由于被订阅而不会被淘汰
+	for svc, visitTime := range map[string]int64{"a": 3, "b": 1, "c": 2, "watched": 0} {
+		svcKey := &model.ServiceEventKey{
+			ServiceKey: model.ServiceKey{Namespace: "Test", Service: svc}, Type: model.EventInstances}
+		cacheObject := NewCacheObject(CacheHandlers{}, nil, svcKey)
+		cacheObject.lastVisitTime = visitTime
+		cacheObject.memorySize = 100
+		g.serviceMap.Store(*svcKey, cacheObject)
+	}
+	g.serviceWatchers[model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "Test", Service: "watched"}, Type: model.EventInstances}] = 1
+	if g.cacheMemoryUsage() != 400 {
+		t.Fatalf("expect memory usage 400, got %d", g.cacheMemoryUsage())
+	}
+	count, used := g.shedCache(400, 250)
+	if count != 2 || used != 200 {
+		t.Fatalf("expect 2 evicted with 200 bytes left, got %d evicted with %d bytes left", count, used)
+	}
+	if len(evicted) != 2 || evicted[0] != "b" || evicted[1] != "c" {
+		t.Fatalf("expect b and c evicted in visit order, got %v", evicted)
+	}
+}
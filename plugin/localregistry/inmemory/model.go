@@ -18,6 +18,7 @@
 package inmemory
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -118,8 +119,10 @@ type CacheHandlers struct {
 // CacheObject 缓存值的管理基类
 type CacheObject struct {
 	// 最后一次访问的时间，初始化时为加入轮询队列的时间
-	lastVisitTime   int64
-	value           atomic.Value
+	lastVisitTime int64
+	value         atomic.Value
+	// 最近一次生效的原始消息，用于导出快照
+	message         atomic.Value
 	serviceValueKey *model.ServiceEventKey
 	Handler         CacheHandlers
 	registry        *LocalCache
@@ -139,6 +142,13 @@ type CacheObject struct {
 	cachePersistentAvailable uint32
 	// 是否为远程服务端出现错误无法获取数据
 	hasRemoteError uint32
+	// 最近一次成功收到服务端应答的时间，无论数据是否变更
+	lastRemoteUpdateTime int64
+	// 等待下一次服务端应答的通知器
+	refreshMutex    sync.Mutex
+	refreshNotifier *common.Notifier
+	// 缓存的估算内存
+	memorySize int64
 }
 
 // NewCacheObject 创建缓存对象
@@ -174,6 +184,7 @@ func NewCacheObjectWithInitValue(handler CacheHandlers, registry *LocalCache,
 	}
 	cacheValue := handler.MessageToCacheValue(nil, message, cacheObject.svcLocalValue, true)
 	cacheObject.SetValue(cacheValue)
+	cacheObject.storeMessage(message)
 	cacheObject.notifier = common.NewNotifier()
 	cacheObject.createTime = clock.GetClock().Now()
 	return cacheObject
@@ -271,6 +282,7 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			atomic.StoreUint32(&s.hasRemoteError, 1)
 		}
 	} else {
+		atomic.StoreInt64(&s.lastRemoteUpdateTime, clock.GetClock().Now().UnixNano())
 		message := event.Value
 		cachedValue := s.LoadValue(false)
 		cachedStatus := s.Handler.CompareMessage(cachedValue, message)
@@ -282,6 +294,7 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			_ = s.registry.PersistMessage(svcCacheFile, message)
 			cacheValue := s.Handler.MessageToCacheValue(cachedValue, message, s.svcLocalValue, false)
 			s.SetValue(cacheValue)
+			s.storeMessage(message)
 			eventObject := &common.ServiceEventObject{SvcEventKey: *svcEventKey,
 				OldValue: cachedValue, NewValue: cacheValue}
 			s.notifyEventHandlers(eventObject, cachedStatus)
@@ -289,12 +302,45 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			switch event.Type {
 			case model.EventInstances:
 				atomic.StoreInt32(&cachedValue.(*pb.ServiceInstancesInProto).CacheLoaded, 0)
+				// 服务端确认数据未变更，同样作为一次健康状态的观察
+				s.registry.observeInstancesHealth(cachedValue.(*pb.ServiceInstancesInProto))
 			case model.EventRouting:
 				atomic.StoreInt32(&cachedValue.(*pb.ServiceRuleInProto).CacheLoaded, 0)
 			}
 		}
 	}
 	s.notifier.Notify(err)
+	s.notifyRefreshed(err)
+}
+
+// GetLastRemoteUpdateTime 获取最近一次成功收到服务端应答的时间，未收到过时返回零值
+func (s *CacheObject) GetLastRemoteUpdateTime() time.Time {
+	lastUpdate := atomic.LoadInt64(&s.lastRemoteUpdateTime)
+	if lastUpdate == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, lastUpdate)
+}
+
+// GetRefreshNotifier 获取等待下一次服务端应答的通知器
+func (s *CacheObject) GetRefreshNotifier() *common.Notifier {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+	if s.refreshNotifier == nil {
+		s.refreshNotifier = common.NewNotifier()
+	}
+	return s.refreshNotifier
+}
+
+// notifyRefreshed 收到服务端应答后唤醒等待刷新的请求
+func (s *CacheObject) notifyRefreshed(err model.SDKError) {
+	s.refreshMutex.Lock()
+	notifier := s.refreshNotifier
+	s.refreshNotifier = nil
+	s.refreshMutex.Unlock()
+	if notifier != nil {
+		notifier.Notify(err)
+	}
 }
 
 // GetRevision 获取服务对象的版本号
@@ -314,6 +360,26 @@ func (s *CacheObject) SetValue(cacheValue model.RegistryValue) {
 		"CacheObject: value for %s is updated, revision %s", *s.serviceValueKey, cacheValue.GetRevision())
 }
 
+// 记录原始消息
+func (s *CacheObject) storeMessage(message proto.Message) {
+	if reflect2.IsNil(message) {
+		return
+	}
+	s.message.Store(message)
+	if s.registry != nil && s.registry.memoryBudgetEnabled() {
+		atomic.StoreInt64(&s.memorySize, estimateCacheSize(message))
+	}
+}
+
+// loadMessage 获取最近一次生效的原始消息
+func (s *CacheObject) loadMessage() proto.Message {
+	value := s.message.Load()
+	if reflect2.IsNil(value) {
+		return nil
+	}
+	return value.(proto.Message)
+}
+
 // GetBusiness 获取业务类型
 func (s *CacheObject) GetBusiness() string {
 	if s.serviceValueKey.Type == model.EventServices {
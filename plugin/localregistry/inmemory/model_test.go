@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+func TestCacheObjectRefreshNotifier(t *testing.T) {
+	svcKey := &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: "Test", Service: "echo"}, Type: model.EventInstances}
+	cacheObject := NewCacheObject(CacheHandlers{}, nil, svcKey)
+	notifier := cacheObject.GetRefreshNotifier()
+	if cacheObject.GetRefreshNotifier() != notifier {
+		t.Fatal("expect pending refresh notifier shared")
+	}
+	sdkErr := model.NewSDKError(model.ErrCodeServerUserError, nil, "mock error")
+	cacheObject.OnServiceUpdate(&serverconnector.ServiceEvent{ServiceEventKey: *svcKey, Error: sdkErr})
+	select {
+	case <-notifier.GetContext().Done():
+	default:
+		t.Fatal("expect refresh notifier triggered by server response")
+	}
+	if notifier.GetError() == nil {
+		t.Fatal("expect server error passed to refresh waiter")
+	}
+	if !cacheObject.GetLastRemoteUpdateTime().IsZero() {
+		t.Fatal("expect failed response not counted as remote update")
+	}
+	if cacheObject.GetRefreshNotifier() == notifier {
+		t.Fatal("expect new notifier created after refresh")
+	}
+}
@@ -63,11 +63,13 @@ func (p *LocationProviderImpl) GetLocation() (*model.Location, error) {
 	region, _ := options["region"].(string)
 	zone, _ := options["zone"].(string)
 	campus, _ := options["campus"].(string)
+	rack, _ := options["rack"].(string)
 
 	p.locCache = &model.Location{
 		Region: region,
 		Zone:   zone,
 		Campus: campus,
+		Rack:   rack,
 	}
 	return p.locCache, nil
 }
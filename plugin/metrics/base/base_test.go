@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLatencySketchQuantile(t *testing.T) {
+	sketch := NewLatencySketch(0.01)
+	other := NewLatencySketch(0.01)
+	for i := 1; i <= 1000; i++ {
+		if i%2 == 0 {
+			sketch.Record(float64(i))
+		} else {
+			other.Record(float64(i))
+		}
+	}
+	sketch.Merge(other)
+	if sketch.Count() != 1000 || sketch.Min() != 1 || sketch.Max() != 1000 {
+		t.Fatalf("unexpected count %d, min %v, max %v", sketch.Count(), sketch.Min(), sketch.Max())
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		expect := q * 999
+		actual := sketch.Quantile(q)
+		if math.Abs(actual-expect)/expect > 0.02 {
+			t.Fatalf("quantile %v expect about %v, actual %v", q, expect, actual)
+		}
+	}
+	sketch.Reset()
+	if sketch.Count() != 0 || sketch.Quantile(0.99) != 0 {
+		t.Fatal("expect empty sketch after reset")
+	}
+}
+
+func TestWindowRollup(t *testing.T) {
+	rollup := NewWindowRollup(DefaultRelativeAccuracy)
+	labels := map[string]string{"service": "svc", "method": "get"}
+	rollup.Observe(labels, 10*time.Millisecond, true)
+	rollup.Observe(map[string]string{"method": "get", "service": "svc"}, 30*time.Millisecond, false)
+	rollup.Observe(map[string]string{"service": "other"}, time.Millisecond, true)
+
+	_, rollups := rollup.Rotate()
+	if len(rollups) != 2 {
+		t.Fatalf("expect 2 dimensions, got %d", len(rollups))
+	}
+	for _, r := range rollups {
+		if r.Labels["service"] != "svc" {
+			continue
+		}
+		if r.Total != 2 || r.Success != 1 || r.Failure != 1 || r.Latency.Max() != 30 {
+			t.Fatalf("unexpected rollup %+v", r)
+		}
+	}
+	if _, rollups = rollup.Rotate(); len(rollups) != 0 {
+		t.Fatal("expect empty window after rotate")
+	}
+
+	counter := NewDimensionCounter()
+	counter.Add("limit", labels, 1)
+	counter.Add("limit", labels, 2)
+	if samples := counter.Collect(true); len(samples) != 1 || samples[0].Value != 3 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if samples := counter.Collect(false); len(samples) != 0 {
+		t.Fatal("expect counter reset after collect")
+	}
+}
@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultReportInterval 默认的批量上报周期
+	DefaultReportInterval = 30 * time.Second
+)
+
+// Config 基于本库实现的统计上报插件的通用配置，插件可直接注册该配置或将其内嵌到自己的配置中
+type Config struct {
+	// Interval 批量上报周期
+	Interval time.Duration `yaml:"interval"`
+	// RelativeAccuracy 时延分位值的相对误差，默认 0.01
+	RelativeAccuracy float64 `yaml:"relativeAccuracy"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.Interval == 0 {
+		c.Interval = DefaultReportInterval
+	}
+	if c.RelativeAccuracy == 0 {
+		c.RelativeAccuracy = DefaultRelativeAccuracy
+	}
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	if c.Interval <= 0 {
+		return errors.New("stat reporter interval must be greater than 0")
+	}
+	if c.RelativeAccuracy <= 0 || c.RelativeAccuracy >= 1 {
+		return errors.New("stat reporter relativeAccuracy must be in (0, 1)")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LabelsKey 将维度标签按key排序后拼接为唯一的字符串，用作聚合的key
+func LabelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key)
+		builder.WriteByte('=')
+		builder.WriteString(labels[key])
+		builder.WriteByte('|')
+	}
+	return builder.String()
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	ret := make(map[string]string, len(labels))
+	for k, v := range labels {
+		ret[k] = v
+	}
+	return ret
+}
+
+// CounterSample 某个维度组合下的计数值
+type CounterSample struct {
+	// Name 指标名
+	Name string
+	// Labels 维度标签
+	Labels map[string]string
+	// Value 计数值
+	Value int64
+}
+
+// DimensionCounter 按指标名及维度标签聚合的计数器
+type DimensionCounter struct {
+	mutex  sync.Mutex
+	values map[string]*CounterSample
+}
+
+// NewDimensionCounter 创建维度计数器
+func NewDimensionCounter() *DimensionCounter {
+	return &DimensionCounter{values: map[string]*CounterSample{}}
+}
+
+// Add 对指定维度的计数加上 delta
+func (c *DimensionCounter) Add(name string, labels map[string]string, delta int64) {
+	key := name + "#" + LabelsKey(labels)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	sample, ok := c.values[key]
+	if !ok {
+		sample = &CounterSample{Name: name, Labels: copyLabels(labels)}
+		c.values[key] = sample
+	}
+	sample.Value += delta
+}
+
+// Collect 获取全部维度的计数，reset 为 true 时同时清空，用于按周期上报增量
+func (c *DimensionCounter) Collect(reset bool) []CounterSample {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	samples := make([]CounterSample, 0, len(c.values))
+	for _, sample := range c.values {
+		samples = append(samples, *sample)
+	}
+	if reset {
+		c.values = map[string]*CounterSample{}
+	}
+	return samples
+}
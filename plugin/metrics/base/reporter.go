@@ -0,0 +1,211 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+// Batch 一个上报周期内预聚合的统计数据
+type Batch struct {
+	// Start 周期起始时间
+	Start time.Time
+	// End 周期结束时间
+	End time.Time
+	// ServiceCalls 服务调用按被调/主调维度的汇总，包含时延分布
+	ServiceCalls []*Rollup
+	// Counters 限流、熔断等事件按指标名及维度的计数增量
+	Counters []CounterSample
+}
+
+// BatchHandler 【简化的插件接口】按周期接收预聚合后的统计数据，只需实现数据的发送逻辑
+type BatchHandler interface {
+	// HandleBatch 处理一个周期的统计数据，在上报协程中串行调用
+	HandleBatch(batch *Batch) error
+}
+
+// Reporter 统计上报插件的通用实现，负责接收 SDK 的统计数据、按维度预聚合，并按周期回调 BatchHandler。
+// 自定义插件内嵌 *Reporter，实现 Name 及 Init，并在 Init 中通过 NewReporter 创建即可
+type Reporter struct {
+	*plugin.PluginBase
+	*common.RunContext
+	handler      BatchHandler
+	cfg          *Config
+	bindIP       string
+	serviceCalls *WindowRollup
+	counters     *DimensionCounter
+	// 保证同一时间只有一个批次在回调，并串行化 Flush 与周期上报
+	flushMutex sync.Mutex
+	startOnce  sync.Once
+}
+
+// NewReporter 创建通用统计上报实现，cfg 为空时使用默认配置
+func NewReporter(ctx *plugin.InitContext, handler BatchHandler, cfg *Config) *Reporter {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	cfg.SetDefault()
+	return &Reporter{
+		PluginBase:   plugin.NewPluginBase(ctx),
+		RunContext:   common.NewRunContext(),
+		handler:      handler,
+		cfg:          cfg,
+		bindIP:       ctx.Config.GetGlobal().GetAPI().GetBindIP(),
+		serviceCalls: NewWindowRollup(cfg.RelativeAccuracy),
+		counters:     NewDimensionCounter(),
+	}
+}
+
+// Type 插件类型
+func (r *Reporter) Type() common.Type {
+	return common.TypeStatReporter
+}
+
+// Start 启动周期上报协程
+func (r *Reporter) Start() error {
+	r.startOnce.Do(func() {
+		profiling.Go("statReporter", "report", r.run)
+	})
+	return nil
+}
+
+func (r *Reporter) run() {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Done():
+			return
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				log.GetStatLogger().Errorf("[StatReporter] fail to handle stat batch, err: %v", err)
+			}
+		}
+	}
+}
+
+// ReportStat 接收统计数据并预聚合
+func (r *Reporter) ReportStat(metricType model.MetricType, gauge model.InstanceGauge) error {
+	switch metricType {
+	case model.ServiceStat:
+		val, ok := gauge.(*model.ServiceCallResult)
+		if !ok || val == nil {
+			return nil
+		}
+		var delay time.Duration
+		if val.GetDelay() != nil {
+			delay = *val.GetDelay()
+		}
+		r.serviceCalls.Observe(statcommon.ConvertInsGaugeToLabels(val, r.bindIP), delay,
+			val.GetRetStatus() == model.RetSuccess)
+	case model.RateLimitStat:
+		val, ok := gauge.(*model.RateLimitGauge)
+		if !ok || val == nil {
+			return nil
+		}
+		labels := statcommon.ConvertRateLimitGaugeToLabels(val)
+		r.counters.Add(statcommon.MetricsNameRateLimitRequestTotal, labels, 1)
+		if val.Result == model.QuotaResultLimited {
+			r.counters.Add(statcommon.MetricsNameRateLimitRequestLimit, labels, 1)
+		} else {
+			r.counters.Add(statcommon.MetricsNameRateLimitRequestPass, labels, 1)
+		}
+	case model.RateLimitTopKStat:
+		val, ok := gauge.(*model.RateLimitTopKGauge)
+		if !ok || val == nil {
+			return nil
+		}
+		for _, entry := range val.Entries {
+			labels := statcommon.ConvertRateLimitTopKEntryToLabels(val, entry)
+			if entry.Passed > 0 {
+				r.counters.Add(statcommon.MetricsNameRateLimitTopKPass, labels, entry.Passed)
+			}
+			if entry.Limited > 0 {
+				r.counters.Add(statcommon.MetricsNameRateLimitTopKLimit, labels, entry.Limited)
+			}
+		}
+	case model.CircuitBreakStat:
+		val, ok := gauge.(*model.CircuitBreakGauge)
+		if !ok || val == nil {
+			return nil
+		}
+		labels := statcommon.ConvertCircuitBreakGaugeToLabels(val)
+		status := val.GetCircuitBreakerStatus()
+		if status == nil {
+			return nil
+		}
+		switch status.GetStatus() {
+		case model.Open:
+			r.counters.Add(statcommon.MetricsNameCircuitBreakerOpen, labels, 1)
+		case model.HalfOpen:
+			r.counters.Add(statcommon.MetricsNameCircuitBreakerHalfOpen, labels, 1)
+		}
+	case model.BulkheadStat:
+		val, ok := gauge.(*model.BulkheadGauge)
+		if !ok || val == nil {
+			return nil
+		}
+		labels := statcommon.ConvertBulkheadGaugeToLabels(val)
+		switch val.Result {
+		case model.BulkheadRejected:
+			r.counters.Add(statcommon.MetricsNameBulkheadRequestReject, labels, 1)
+		case model.BulkheadQueued:
+			r.counters.Add(statcommon.MetricsNameBulkheadRequestQueued, labels, 1)
+		}
+	}
+	return nil
+}
+
+// Flush 立即结束当前周期并回调 BatchHandler，SDK 下线排空时也会调用
+func (r *Reporter) Flush() error {
+	r.flushMutex.Lock()
+	defer r.flushMutex.Unlock()
+	start, rollups := r.serviceCalls.Rotate()
+	counters := r.counters.Collect(true)
+	if len(rollups) == 0 && len(counters) == 0 {
+		return nil
+	}
+	return r.handler.HandleBatch(&Batch{
+		Start:        start,
+		End:          time.Now(),
+		ServiceCalls: rollups,
+		Counters:     counters,
+	})
+}
+
+// Info 插件信息，批量上报插件不对外暴露抓取地址
+func (r *Reporter) Info() model.StatInfo {
+	return model.StatInfo{}
+}
+
+// Destroy 停止上报协程，并上报剩余的数据
+func (r *Reporter) Destroy() error {
+	if r.IsDestroyed() {
+		return nil
+	}
+	_ = r.RunContext.Destroy()
+	return r.Flush()
+}
@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"sync"
+	"time"
+)
+
+// Rollup 一个统计周期内某个维度组合的调用汇总
+type Rollup struct {
+	// Labels 维度标签
+	Labels map[string]string
+	// Total 调用总数
+	Total int64
+	// Success 成功数
+	Success int64
+	// Failure 失败数
+	Failure int64
+	// Latency 时延分布，单位毫秒
+	Latency *LatencySketch
+}
+
+// WindowRollup 按维度标签汇总调用结果及时延分布，每次 Rotate 返回上一个周期的汇总并开始新周期
+type WindowRollup struct {
+	mutex            sync.Mutex
+	relativeAccuracy float64
+	windowStart      time.Time
+	rollups          map[string]*Rollup
+}
+
+// NewWindowRollup 创建周期汇总，relativeAccuracy 为时延分位值的相对误差
+func NewWindowRollup(relativeAccuracy float64) *WindowRollup {
+	return &WindowRollup{
+		relativeAccuracy: relativeAccuracy,
+		windowStart:      time.Now(),
+		rollups:          map[string]*Rollup{},
+	}
+}
+
+// Observe 记录一次调用
+func (w *WindowRollup) Observe(labels map[string]string, delay time.Duration, success bool) {
+	key := LabelsKey(labels)
+	w.mutex.Lock()
+	rollup, ok := w.rollups[key]
+	if !ok {
+		rollup = &Rollup{Labels: copyLabels(labels), Latency: NewLatencySketch(w.relativeAccuracy)}
+		w.rollups[key] = rollup
+	}
+	rollup.Total++
+	if success {
+		rollup.Success++
+	} else {
+		rollup.Failure++
+	}
+	rollup.Latency.Record(float64(delay) / float64(time.Millisecond))
+	w.mutex.Unlock()
+}
+
+// Rotate 结束当前周期，返回周期起始时间及周期内的汇总
+func (w *WindowRollup) Rotate() (time.Time, []*Rollup) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	start := w.windowStart
+	rollups := make([]*Rollup, 0, len(w.rollups))
+	for _, rollup := range w.rollups {
+		rollups = append(rollups, rollup)
+	}
+	w.rollups = map[string]*Rollup{}
+	w.windowStart = time.Now()
+	return start, rollups
+}
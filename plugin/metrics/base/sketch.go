@@ -0,0 +1,242 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package base
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	// DefaultRelativeAccuracy 默认的分位值相对误差
+	DefaultRelativeAccuracy = 0.01
+	// minTrackableValue 小于该值的样本统一计入零值桶
+	minTrackableValue = 1e-9
+)
+
+// LatencySketch 相对误差可控的分位数草图，按对数划分桶，任意分位值的相对误差不超过 relativeAccuracy，
+// 内存占用只与数据的取值跨度有关，可跨实例合并，适合按服务/方法维度统计时延分布
+type LatencySketch struct {
+	mutex            sync.Mutex
+	relativeAccuracy float64
+	gamma            float64
+	logGamma         float64
+	buckets          map[int]uint64
+	zeroCount        uint64
+	count            uint64
+	sum              float64
+	min              float64
+	max              float64
+}
+
+// NewLatencySketch 创建分位数草图，relativeAccuracy 取值范围 (0, 1)，非法时使用默认值
+func NewLatencySketch(relativeAccuracy float64) *LatencySketch {
+	if relativeAccuracy <= 0 || relativeAccuracy >= 1 {
+		relativeAccuracy = DefaultRelativeAccuracy
+	}
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return &LatencySketch{
+		relativeAccuracy: relativeAccuracy,
+		gamma:            gamma,
+		logGamma:         math.Log(gamma),
+		buckets:          map[int]uint64{},
+	}
+}
+
+// RelativeAccuracy 返回分位值的相对误差
+func (s *LatencySketch) RelativeAccuracy() float64 {
+	return s.relativeAccuracy
+}
+
+// Record 记录一个样本，负数按0处理
+func (s *LatencySketch) Record(value float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.recordN(value, 1)
+}
+
+func (s *LatencySketch) recordN(value float64, n uint64) {
+	if value < 0 || math.IsNaN(value) {
+		value = 0
+	}
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.count += n
+	s.sum += value * float64(n)
+	if value < minTrackableValue {
+		s.zeroCount += n
+		return
+	}
+	s.buckets[int(math.Ceil(math.Log(value)/s.logGamma))] += n
+}
+
+// Quantile 计算分位值，q 取值范围 [0, 1]，无样本时返回0
+func (s *LatencySketch) Quantile(q float64) float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.quantile(q)
+}
+
+func (s *LatencySketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+	rank := uint64(q * float64(s.count-1))
+	if rank < s.zeroCount {
+		return 0
+	}
+	indexes := make([]int, 0, len(s.buckets))
+	for index := range s.buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	cumulative := s.zeroCount
+	for _, index := range indexes {
+		cumulative += s.buckets[index]
+		if cumulative > rank {
+			value := 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+			// 桶的代表值可能略超出实际的最值，收敛到真实范围内
+			return math.Max(s.min, math.Min(s.max, value))
+		}
+	}
+	return s.max
+}
+
+// Quantiles 批量计算分位值，只加锁及排序一次
+func (s *LatencySketch) Quantiles(qs []float64) []float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	values := make([]float64, len(qs))
+	for i, q := range qs {
+		values[i] = s.quantile(q)
+	}
+	return values
+}
+
+// Count 样本数
+func (s *LatencySketch) Count() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
+
+// Sum 样本总和
+func (s *LatencySketch) Sum() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sum
+}
+
+// Min 最小值
+func (s *LatencySketch) Min() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.min
+}
+
+// Max 最大值
+func (s *LatencySketch) Max() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.max
+}
+
+// Merge 合并另一个草图的数据，两者的相对误差需一致，否则按当前草图的桶重新划分
+func (s *LatencySketch) Merge(other *LatencySketch) {
+	if other == nil || other == s {
+		return
+	}
+	other.mutex.Lock()
+	snapshot := other.copyLocked()
+	other.mutex.Unlock()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if snapshot.count == 0 {
+		return
+	}
+	if snapshot.gamma == s.gamma {
+		for index, n := range snapshot.buckets {
+			s.buckets[index] += n
+		}
+		s.zeroCount += snapshot.zeroCount
+		if s.count == 0 || snapshot.min < s.min {
+			s.min = snapshot.min
+		}
+		if s.count == 0 || snapshot.max > s.max {
+			s.max = snapshot.max
+		}
+		s.count += snapshot.count
+		s.sum += snapshot.sum
+		return
+	}
+	for index, n := range snapshot.buckets {
+		s.recordN(2*math.Pow(snapshot.gamma, float64(index))/(snapshot.gamma+1), n)
+	}
+	if snapshot.zeroCount > 0 {
+		s.recordN(0, snapshot.zeroCount)
+	}
+}
+
+// Copy 复制当前草图
+func (s *LatencySketch) Copy() *LatencySketch {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.copyLocked()
+}
+
+func (s *LatencySketch) copyLocked() *LatencySketch {
+	buckets := make(map[int]uint64, len(s.buckets))
+	for index, n := range s.buckets {
+		buckets[index] = n
+	}
+	return &LatencySketch{
+		relativeAccuracy: s.relativeAccuracy,
+		gamma:            s.gamma,
+		logGamma:         s.logGamma,
+		buckets:          buckets,
+		zeroCount:        s.zeroCount,
+		count:            s.count,
+		sum:              s.sum,
+		min:              s.min,
+		max:              s.max,
+	}
+}
+
+// Reset 清空数据
+func (s *LatencySketch) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.buckets = map[int]uint64{}
+	s.zeroCount = 0
+	s.count = 0
+	s.sum = 0
+	s.min = 0
+	s.max = 0
+}
@@ -19,8 +19,10 @@ package common
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -59,6 +61,13 @@ const (
 	CallerLabels    = "caller_labels"
 	MetricNameLabel = "metric_name"
 	RuleName        = "rule_name"
+	ServerCluster   = "server_cluster"
+	ServerAddress   = "server_address"
+	ServerPriority  = "server_priority"
+	ServerMethod    = "server_method"
+	Direction       = "direction"
+	Compression     = "compression"
+	RateLimitLabels = "ratelimit_labels"
 
 	// MetricsNameUpstreamRequestTotal 与路由、请求相关的指标信息.
 	MetricsNameUpstreamRequestTotal      = "upstream_rq_total"
@@ -71,11 +80,26 @@ const (
 	MetricsNameRateLimitRequestTotal = "ratelimit_rq_total"
 	MetricsNameRateLimitRequestPass  = "ratelimit_rq_pass"
 	MetricsNameRateLimitRequestLimit = "ratelimit_rq_limit"
+	// 限流规则下消耗配额最多的标签值，仅上报每个周期的前K个.
+	MetricsNameRateLimitTopKPass  = "ratelimit_topk_pass"
+	MetricsNameRateLimitTopKLimit = "ratelimit_topk_limit"
 
 	// 熔断相关指标信息.
 	MetricsNameCircuitBreakerOpen     = "circuitbreaker_open"
 	MetricsNameCircuitBreakerHalfOpen = "circuitbreaker_halfopen"
 
+	// 舱壁隔离相关指标信息.
+	MetricsNameBulkheadRequestQueued = "bulkhead_rq_queued"
+	MetricsNameBulkheadRequestReject = "bulkhead_rq_reject"
+
+	// 与server连接相关指标信息.
+	MetricsNameServerEndpointPinned = "server_endpoint_pinned"
+	MetricsNameServerTrafficRaw     = "server_traffic_raw_bytes"
+	MetricsNameServerTrafficWire    = "server_traffic_wire_bytes"
+
+	// 实例注册相关指标信息.
+	MetricsNameRegisterFlapping = "register_flapping_total"
+
 	// SystemMetricValue.
 	NilValue = "__NULL__"
 )
@@ -186,6 +210,75 @@ var (
 		},
 	}
 
+	BulkheadGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		CalleeNamespace: func(args interface{}) string {
+			val := args.(*model.BulkheadGauge)
+			return val.Namespace
+		},
+		CalleeService: func(args interface{}) string {
+			val := args.(*model.BulkheadGauge)
+			return val.Service
+		},
+		CalleeMethod: func(args interface{}) string {
+			val := args.(*model.BulkheadGauge)
+			if val.Method != "" {
+				return val.Method
+			}
+			return NilValue
+		},
+		RuleName: func(args interface{}) string {
+			return model.BulkheadRuleName
+		},
+	}
+
+	ServerEndpointGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		ServerCluster: func(args interface{}) string {
+			val := args.(*model.ServerEndpointGauge)
+			return val.Cluster
+		},
+		ServerAddress: func(args interface{}) string {
+			val := args.(*model.ServerEndpointGauge)
+			return val.Address
+		},
+		ServerPriority: func(args interface{}) string {
+			val := args.(*model.ServerEndpointGauge)
+			return strconv.Itoa(val.Priority)
+		},
+	}
+
+	ServerTrafficGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		ServerMethod: func(args interface{}) string {
+			val := args.(*model.ServerTrafficGauge)
+			return val.Method
+		},
+		Direction: func(args interface{}) string {
+			val := args.(*model.ServerTrafficGauge)
+			return val.Direction
+		},
+		Compression: func(args interface{}) string {
+			val := args.(*model.ServerTrafficGauge)
+			if val.Compression != "" {
+				return val.Compression
+			}
+			return NilValue
+		},
+	}
+
+	RegisterFlappingGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
+		CalleeNamespace: func(args interface{}) string {
+			val := args.(*model.RegisterFlappingGauge)
+			return val.Namespace
+		},
+		CalleeService: func(args interface{}) string {
+			val := args.(*model.RegisterFlappingGauge)
+			return val.Service
+		},
+		CalleeInstance: func(args interface{}) string {
+			val := args.(*model.RegisterFlappingGauge)
+			return fmt.Sprintf("%s:%d", val.Host, val.Port)
+		},
+	}
+
 	CircuitBreakerGaugeLabelOrder map[string]LabelValueSupplier = map[string]LabelValueSupplier{
 		CalleeNamespace: func(args interface{}) string {
 			val := args.(*model.CircuitBreakGauge)
@@ -249,6 +342,16 @@ func ConvertRateLimitGaugeToLabels(val *model.RateLimitGauge) map[string]string
 	return labels
 }
 
+// ConvertRateLimitTopKEntryToLabels 热点标签值的维度，标签值作为单独的维度上报
+func ConvertRateLimitTopKEntryToLabels(val *model.RateLimitTopKGauge, entry model.RateLimitTopKEntry) map[string]string {
+	return map[string]string{
+		CalleeNamespace: val.Namespace,
+		CalleeService:   val.Service,
+		RuleName:        val.RuleName,
+		RateLimitLabels: entry.Labels,
+	}
+}
+
 func ConvertCircuitBreakGaugeToLabels(val *model.CircuitBreakGauge) map[string]string {
 	labels := make(map[string]string)
 	for label, supplier := range CircuitBreakerGaugeLabelOrder {
@@ -256,3 +359,100 @@ func ConvertCircuitBreakGaugeToLabels(val *model.CircuitBreakGauge) map[string]s
 	}
 	return labels
 }
+
+func ConvertBulkheadGaugeToLabels(val *model.BulkheadGauge) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range BulkheadGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	return labels
+}
+
+func ConvertServerEndpointGaugeToLabels(val *model.ServerEndpointGauge) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range ServerEndpointGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	return labels
+}
+
+func ConvertServerTrafficGaugeToLabels(val *model.ServerTrafficGauge) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range ServerTrafficGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	return labels
+}
+
+func ConvertRegisterFlappingGaugeToLabels(val *model.RegisterFlappingGauge) map[string]string {
+	labels := make(map[string]string)
+	for label, supplier := range RegisterFlappingGaugeLabelOrder {
+		labels[label] = supplier(val)
+	}
+	return labels
+}
+
+// AppendCostLabels 将成本归属标签按照key的字典序追加到指标的label顺序中，返回新的label顺序以及追加的key
+func AppendCostLabels(order []string, costLabels map[string]string) ([]string, []string, error) {
+	if len(costLabels) == 0 {
+		return order, nil, nil
+	}
+	exists := make(map[string]struct{}, len(order))
+	for _, label := range order {
+		exists[label] = struct{}{}
+	}
+	keys := make([]string, 0, len(costLabels))
+	for key := range costLabels {
+		if _, ok := exists[key]; ok {
+			return nil, nil, fmt.Errorf("cost label %s conflicts with builtin metric label", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	newOrder := make([]string, 0, len(order)+len(keys))
+	newOrder = append(newOrder, order...)
+	newOrder = append(newOrder, keys...)
+	return newOrder, keys, nil
+}
+
+// RemoveCallerLabels 从标签顺序中剔除主调命名空间以及主调服务标签，用于关闭主调服务标签的场景
+func RemoveCallerLabels(order []string) []string {
+	newOrder := make([]string, 0, len(order))
+	for _, label := range order {
+		if label == CallerNamespace || label == CallerService {
+			continue
+		}
+		newOrder = append(newOrder, label)
+	}
+	return newOrder
+}
+
+// FillCallerLabels 处理服务调用指标的主调服务标签，关闭时移除对应标签，
+// 开启时对调用请求未携带的主调信息使用配置的默认主调服务填充
+func FillCallerLabels(labels map[string]string, cfg config.CallerLabelsConfig) {
+	if cfg == nil {
+		return
+	}
+	if !cfg.IsEnable() {
+		delete(labels, CallerNamespace)
+		delete(labels, CallerService)
+		return
+	}
+	if labels[CallerNamespace] == NilValue && cfg.GetNamespace() != "" {
+		labels[CallerNamespace] = cfg.GetNamespace()
+	}
+	if labels[CallerService] == NilValue && cfg.GetService() != "" {
+		labels[CallerService] = cfg.GetService()
+	}
+}
+
+// FillCostLabels 填充成本归属标签的值，未设置的标签使用 NilValue
+func FillCostLabels(labels map[string]string, keys []string, values map[string]string) {
+	for _, key := range keys {
+		if value, ok := values[key]; ok && value != "" {
+			labels[key] = value
+			continue
+		}
+		labels[key] = NilValue
+	}
+}
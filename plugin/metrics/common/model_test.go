@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAppendCostLabels 测试成本归属标签按key排序追加到指标标签后，与内置标签冲突时报错
+func TestAppendCostLabels(t *testing.T) {
+	order := []string{CalleeNamespace, CalleeService}
+	newOrder, keys, err := AppendCostLabels(order, map[string]string{"team": "infra", "env": "prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"env", "team"}) {
+		t.Fatalf("expect sorted cost label keys, got %v", keys)
+	}
+	if !reflect.DeepEqual(newOrder, []string{CalleeNamespace, CalleeService, "env", "team"}) {
+		t.Fatalf("unexpected label order %v", newOrder)
+	}
+	if len(order) != 2 {
+		t.Fatalf("builtin label order should not be modified, got %v", order)
+	}
+	if same, keys, err := AppendCostLabels(order, nil); err != nil || keys != nil || !reflect.DeepEqual(same, order) {
+		t.Fatalf("expect label order unchanged without cost labels, got %v %v %v", same, keys, err)
+	}
+	if _, _, err = AppendCostLabels(order, map[string]string{CalleeService: "x"}); err == nil {
+		t.Fatal("expect conflict with builtin label")
+	}
+
+	labels := map[string]string{}
+	FillCostLabels(labels, []string{"env", "team"}, map[string]string{"team": "search", "env": ""})
+	if labels["team"] != "search" || labels["env"] != NilValue {
+		t.Fatalf("unexpected cost label values %v", labels)
+	}
+}
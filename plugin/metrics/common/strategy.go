@@ -57,6 +57,49 @@ var (
 		MetricNameLabel,
 	}
 
+	BulkheadStrategy = []MetricValueAggregationStrategy{
+		&BulkheadRequestQueuedStrategy{},
+		&BulkheadRequestRejectStrategy{},
+	}
+	BulkheadLabelOrder = []string{
+		CalleeNamespace,
+		CalleeService,
+		CalleeMethod,
+		RuleName,
+		MetricNameLabel,
+	}
+
+	ServerEndpointStrategy = []MetricValueAggregationStrategy{
+		&ServerEndpointPinnedStrategy{},
+	}
+	ServerEndpointLabelOrder = []string{
+		ServerCluster,
+		ServerAddress,
+		ServerPriority,
+		MetricNameLabel,
+	}
+
+	ServerTrafficStrategy = []MetricValueAggregationStrategy{
+		&ServerTrafficRawBytesStrategy{},
+		&ServerTrafficWireBytesStrategy{},
+	}
+	ServerTrafficLabelOrder = []string{
+		ServerMethod,
+		Direction,
+		Compression,
+		MetricNameLabel,
+	}
+
+	RegisterFlappingStrategy = []MetricValueAggregationStrategy{
+		&RegisterFlappingTotalStrategy{},
+	}
+	RegisterFlappingLabelOrder = []string{
+		CalleeNamespace,
+		CalleeService,
+		CalleeInstance,
+		MetricNameLabel,
+	}
+
 	CircuitBreakerStrategy = []MetricValueAggregationStrategy{
 		&CircuitBreakerHalfOpenStrategy{},
 		&CircuitBreakerOpenStrategy{},
@@ -438,3 +481,198 @@ func (us *RateLimitRequestLimitStrategy) UpdateMetricValue(targetValue StatMetri
 		targetValue.Inc()
 	}
 }
+
+type BulkheadRequestQueuedStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *BulkheadRequestQueuedStrategy) GetStrategyDescription() string {
+	return "total of queued request caused by bulkhead saturation per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *BulkheadRequestQueuedStrategy) GetStrategyName() string {
+	return MetricsNameBulkheadRequestQueued
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *BulkheadRequestQueuedStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.BulkheadGauge)
+	if !ok {
+		return 0
+	}
+	if gauge.Result == model.BulkheadQueued {
+		return 1.0
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *BulkheadRequestQueuedStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.BulkheadGauge)
+	if !ok {
+		return
+	}
+	if gauge.Result == model.BulkheadQueued {
+		targetValue.Inc()
+	}
+}
+
+type BulkheadRequestRejectStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *BulkheadRequestRejectStrategy) GetStrategyDescription() string {
+	return "total of rejected request caused by bulkhead saturation per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *BulkheadRequestRejectStrategy) GetStrategyName() string {
+	return MetricsNameBulkheadRequestReject
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *BulkheadRequestRejectStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.BulkheadGauge)
+	if !ok {
+		return 0
+	}
+	if gauge.Result == model.BulkheadRejected {
+		return 1.0
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *BulkheadRequestRejectStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.BulkheadGauge)
+	if !ok {
+		return
+	}
+	if gauge.Result == model.BulkheadRejected {
+		targetValue.Inc()
+	}
+}
+
+type ServerEndpointPinnedStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *ServerEndpointPinnedStrategy) GetStrategyDescription() string {
+	return "whether the connection to server is pinned to the endpoint"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *ServerEndpointPinnedStrategy) GetStrategyName() string {
+	return MetricsNameServerEndpointPinned
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *ServerEndpointPinnedStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.ServerEndpointGauge)
+	if !ok {
+		return 0
+	}
+	if gauge.Pinned {
+		return 1.0
+	}
+	return 0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *ServerEndpointPinnedStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.ServerEndpointGauge)
+	if !ok {
+		return
+	}
+	if gauge.Pinned {
+		targetValue.Set(1)
+		return
+	}
+	targetValue.Set(0)
+}
+
+type ServerTrafficRawBytesStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *ServerTrafficRawBytesStrategy) GetStrategyDescription() string {
+	return "bytes of messages to/from server before compression per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *ServerTrafficRawBytesStrategy) GetStrategyName() string {
+	return MetricsNameServerTrafficRaw
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *ServerTrafficRawBytesStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.ServerTrafficGauge)
+	if !ok {
+		return 0
+	}
+	return float64(gauge.RawBytes)
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *ServerTrafficRawBytesStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.ServerTrafficGauge)
+	if !ok {
+		return
+	}
+	targetValue.Add(int64(gauge.RawBytes))
+}
+
+type ServerTrafficWireBytesStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *ServerTrafficWireBytesStrategy) GetStrategyDescription() string {
+	return "bytes of messages to/from server on the wire per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *ServerTrafficWireBytesStrategy) GetStrategyName() string {
+	return MetricsNameServerTrafficWire
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *ServerTrafficWireBytesStrategy) InitMetricValue(dataSource interface{}) float64 {
+	gauge, ok := dataSource.(*model.ServerTrafficGauge)
+	if !ok {
+		return 0
+	}
+	return float64(gauge.WireBytes)
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *ServerTrafficWireBytesStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	gauge, ok := dataSource.(*model.ServerTrafficGauge)
+	if !ok {
+		return
+	}
+	targetValue.Add(int64(gauge.WireBytes))
+}
+
+type RegisterFlappingTotalStrategy struct {
+}
+
+// 返回策略的描述信息
+func (us *RegisterFlappingTotalStrategy) GetStrategyDescription() string {
+	return "total of register flapping detected per period"
+}
+
+// 返回策略名称，通常该名称用作metricName
+func (us *RegisterFlappingTotalStrategy) GetStrategyName() string {
+	return MetricsNameRegisterFlapping
+}
+
+// 根据数据源的内容获取第一次创建metric的时候的初始值
+func (us *RegisterFlappingTotalStrategy) InitMetricValue(dataSource interface{}) float64 {
+	return 1.0
+}
+
+// 根据metric自身的value值和聚合数据源T的值来更新metric的value
+func (us *RegisterFlappingTotalStrategy) UpdateMetricValue(targetValue StatMetric, dataSource interface{}) {
+	targetValue.Inc()
+}
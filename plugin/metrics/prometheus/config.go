@@ -17,10 +17,12 @@
 package prometheus
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/plugin/metrics/base"
 )
 
 func init() {
@@ -30,8 +32,13 @@ func init() {
 const (
 	defaultReportInterval = 1 * time.Minute
 	defaultMetricPort     = 28080
+	// defaultLatencyRotation 时延分布默认的轮转周期
+	defaultLatencyRotation = time.Minute
 )
 
+// defaultExemplarBuckets 时延直方图默认的桶边界，单位毫秒
+var defaultExemplarBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
 // Config prometheus 的配置
 type Config struct {
 	Type     string        `yaml:"type"`
@@ -40,10 +47,90 @@ type Config struct {
 	port     int           `yaml:"-"`
 	Interval time.Duration `yaml:"interval"`
 	Address  string        `yaml:"address"`
+	// LocalhostOnly 仅监听在 127.0.0.1 上，用于同 pod 内 sidecar 抓取，开启后忽略 metricHost
+	LocalhostOnly bool `yaml:"localhostOnly"`
+	// Auth 拉取指标的鉴权配置，不配置时不鉴权
+	Auth *AuthConfig `yaml:"auth"`
+	// TLS 指标 http-server 的证书配置，不配置时使用明文 http
+	TLS *TLSConfig `yaml:"tls"`
+	// Latency 服务调用时延分位值的统计配置
+	Latency *LatencyConfig `yaml:"latency"`
+	// Exemplar 时延直方图的 exemplar 配置，需要抓取端开启 OpenMetrics 格式才能采集
+	Exemplar *ExemplarConfig `yaml:"exemplar"`
+}
+
+// ExemplarConfig 开启后导出 upstream_rq_delay_histogram 时延直方图，
+// 并将 ServiceCallResult 中的 trace id 作为 exemplar 附加到样本上，便于从时延毛刺跳转到调用链
+type ExemplarConfig struct {
+	// Enable 是否开启，默认关闭
+	Enable bool `yaml:"enable"`
+	// Buckets 直方图的桶边界，单位毫秒
+	Buckets []float64 `yaml:"buckets"`
+}
+
+// LatencyConfig 按服务/方法统计时延分布，以 summary 的分位值导出
+type LatencyConfig struct {
+	// Enable 是否开启，默认开启
+	Enable *bool `yaml:"enable"`
+	// RelativeAccuracy 分位值的相对误差，越小越精确但占用内存越多，默认 0.01
+	RelativeAccuracy float64 `yaml:"relativeAccuracy"`
+	// Quantiles 导出的分位值，默认 0.5,0.9,0.99
+	Quantiles []float64 `yaml:"quantiles"`
+	// Rotation 时延分布的轮转周期，导出的分位值覆盖最近一到两个周期，默认 1m
+	Rotation time.Duration `yaml:"rotation"`
+}
+
+// IsEnable 是否开启时延分位值统计
+func (l *LatencyConfig) IsEnable() bool {
+	return l.Enable == nil || *l.Enable
+}
+
+// AuthConfig 拉取指标的鉴权配置，basic-auth 与 bearer token 可同时配置，满足其一即可
+type AuthConfig struct {
+	Username    string `yaml:"username"`
+	Password    string `yaml:"password"`
+	BearerToken string `yaml:"bearerToken"`
+}
+
+// TLSConfig 指标 http-server 的证书配置
+type TLSConfig struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
 }
 
 // Verify verify config
 func (c *Config) Verify() error {
+	if c.Auth != nil {
+		if (len(c.Auth.Username) == 0) != (len(c.Auth.Password) == 0) {
+			return errors.New("prometheus auth username and password must be set together")
+		}
+		if len(c.Auth.Username) == 0 && len(c.Auth.BearerToken) == 0 {
+			return errors.New("prometheus auth requires basic-auth or bearerToken")
+		}
+	}
+	if c.TLS != nil && (len(c.TLS.CertFile) == 0 || len(c.TLS.KeyFile) == 0) {
+		return errors.New("prometheus tls requires both certFile and keyFile")
+	}
+	if c.Latency != nil {
+		if c.Latency.RelativeAccuracy < 0 || c.Latency.RelativeAccuracy >= 1 {
+			return errors.New("prometheus latency relativeAccuracy must be in (0, 1)")
+		}
+		for _, q := range c.Latency.Quantiles {
+			if q <= 0 || q >= 1 {
+				return errors.New("prometheus latency quantiles must be in (0, 1)")
+			}
+		}
+		if c.Latency.Rotation < 0 {
+			return errors.New("prometheus latency rotation must not be negative")
+		}
+	}
+	if c.Exemplar != nil {
+		for i := 1; i < len(c.Exemplar.Buckets); i++ {
+			if c.Exemplar.Buckets[i] <= c.Exemplar.Buckets[i-1] {
+				return errors.New("prometheus exemplar buckets must be in increasing order")
+			}
+		}
+	}
 	return nil
 }
 
@@ -58,6 +145,24 @@ func (c *Config) SetDefault() {
 	if c.Interval == 0 {
 		c.Interval = 15 * time.Second
 	}
+	if c.Latency == nil {
+		c.Latency = &LatencyConfig{}
+	}
+	if c.Latency.RelativeAccuracy == 0 {
+		c.Latency.RelativeAccuracy = base.DefaultRelativeAccuracy
+	}
+	if len(c.Latency.Quantiles) == 0 {
+		c.Latency.Quantiles = []float64{0.5, 0.9, 0.99}
+	}
+	if c.Latency.Rotation == 0 {
+		c.Latency.Rotation = defaultLatencyRotation
+	}
+	if c.Exemplar == nil {
+		c.Exemplar = &ExemplarConfig{}
+	}
+	if len(c.Exemplar.Buckets) == 0 {
+		c.Exemplar.Buckets = defaultExemplarBuckets
+	}
 	port, _ := strconv.ParseInt(c.PortStr, 10, 64)
 	c.port = int(port)
 }
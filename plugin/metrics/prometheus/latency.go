@@ -0,0 +1,171 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/plugin/metrics/base"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+const (
+	// MetricsNameUpstreamRequestLatency 服务调用时延分布，单位毫秒
+	MetricsNameUpstreamRequestLatency = "upstream_rq_latency"
+	// MetricsNameUpstreamRequestDelayHistogram 携带 exemplar 的服务调用时延直方图，单位毫秒
+	MetricsNameUpstreamRequestDelayHistogram = "upstream_rq_delay_histogram"
+	// exemplarTraceIDLabel exemplar 中 trace id 的标签名，与 Grafana 的默认配置一致
+	exemplarTraceIDLabel = "trace_id"
+)
+
+// latencyLabelOrder 时延分布的维度，只按服务/方法聚合，控制时序数量
+var latencyLabelOrder = []string{statcommon.CalleeNamespace, statcommon.CalleeService, statcommon.CalleeMethod}
+
+type latencySeries struct {
+	labelValues []string
+	sketch      *base.LatencySketch
+}
+
+// latencyCollector 基于分位数草图的时延分布采集器，保留当前及上一个轮转周期的数据，
+// 导出时合并两个周期，避免轮转后分位值出现断崖
+type latencyCollector struct {
+	desc      *prometheus.Desc
+	cfg       *LatencyConfig
+	mutex     sync.Mutex
+	current   map[string]*latencySeries
+	previous  map[string]*latencySeries
+	rotatedAt time.Time
+}
+
+func newLatencyCollector(cfg *LatencyConfig) *latencyCollector {
+	return &latencyCollector{
+		desc: prometheus.NewDesc(MetricsNameUpstreamRequestLatency,
+			"quantiles of request delay in milliseconds", latencyLabelOrder, nil),
+		cfg:       cfg,
+		current:   map[string]*latencySeries{},
+		previous:  map[string]*latencySeries{},
+		rotatedAt: time.Now(),
+	}
+}
+
+// observe 记录一次服务调用的时延
+func (c *latencyCollector) observe(val *model.ServiceCallResult) {
+	delay := val.GetDelay()
+	if delay == nil {
+		return
+	}
+	labelValues := []string{
+		val.GetCalledInstance().GetNamespace(),
+		val.GetCalledInstance().GetService(),
+		val.GetMethod(),
+	}
+	key := strings.Join(labelValues, "|")
+
+	c.mutex.Lock()
+	c.rotateIfNeed()
+	series, ok := c.current[key]
+	if !ok {
+		series = &latencySeries{labelValues: labelValues, sketch: base.NewLatencySketch(c.cfg.RelativeAccuracy)}
+		c.current[key] = series
+	}
+	c.mutex.Unlock()
+	series.sketch.Record(float64(*delay) / float64(time.Millisecond))
+}
+
+// rotateIfNeed 到达轮转周期时丢弃上一个周期的数据，调用方需持有锁
+func (c *latencyCollector) rotateIfNeed() {
+	elapsed := time.Since(c.rotatedAt)
+	if elapsed < c.cfg.Rotation {
+		return
+	}
+	if elapsed >= 2*c.cfg.Rotation {
+		// 超过两个周期没有轮转，上一个周期的数据同样过期
+		c.previous = map[string]*latencySeries{}
+	} else {
+		c.previous = c.current
+	}
+	c.current = map[string]*latencySeries{}
+	c.rotatedAt = time.Now()
+}
+
+// Describe 实现 prometheus.Collector
+func (c *latencyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect 实现 prometheus.Collector
+func (c *latencyCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	c.rotateIfNeed()
+	merged := make(map[string]*latencySeries, len(c.current)+len(c.previous))
+	for _, window := range []map[string]*latencySeries{c.previous, c.current} {
+		for key, series := range window {
+			if exist, ok := merged[key]; ok {
+				exist.sketch.Merge(series.sketch)
+				continue
+			}
+			merged[key] = &latencySeries{labelValues: series.labelValues, sketch: series.sketch.Copy()}
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, series := range merged {
+		values := series.sketch.Quantiles(c.cfg.Quantiles)
+		quantiles := make(map[float64]float64, len(values))
+		for i, q := range c.cfg.Quantiles {
+			quantiles[q] = values[i]
+		}
+		metric, err := prometheus.NewConstSummary(c.desc, series.sketch.Count(), series.sketch.Sum(),
+			quantiles, series.labelValues...)
+		if err != nil {
+			continue
+		}
+		ch <- metric
+	}
+}
+
+// newExemplarHistogram 创建时延直方图，维度与时延分位值一致
+func newExemplarHistogram(cfg *ExemplarConfig) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MetricsNameUpstreamRequestDelayHistogram,
+		Help:    "histogram of request delay in milliseconds with trace exemplars",
+		Buckets: cfg.Buckets,
+	}, latencyLabelOrder)
+}
+
+// observeWithExemplar 记录时延，有 trace id 时附加 exemplar
+func observeWithExemplar(histogram *prometheus.HistogramVec, val *model.ServiceCallResult) {
+	delay := val.GetDelay()
+	if delay == nil {
+		return
+	}
+	observer := histogram.WithLabelValues(val.GetCalledInstance().GetNamespace(),
+		val.GetCalledInstance().GetService(), val.GetMethod())
+	delayMs := float64(*delay) / float64(time.Millisecond)
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if !ok || len(val.TraceID) == 0 {
+		observer.Observe(delayMs)
+		return
+	}
+	exemplarObserver.ObserveWithExemplar(delayMs, prometheus.Labels{exemplarTraceIDLabel: val.TraceID})
+}
@@ -19,10 +19,12 @@ package prometheus
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,14 +33,89 @@ import (
 	"github.com/prometheus/client_golang/prometheus/push"
 	"go.uber.org/zap"
 
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
 )
 
+const (
+	// MetricsNamePluginPanicTotal 插件调用panic的次数
+	MetricsNamePluginPanicTotal = "plugin_panic_total"
+	labelPluginType             = "plugin_type"
+	labelPluginName             = "plugin_name"
+	labelPluginMethod           = "plugin_method"
+	// MetricsNameCacheDivergenceTotal 缓存版本巡检发现缓存与服务端不一致的次数
+	MetricsNameCacheDivergenceTotal = "cache_revision_divergence_total"
+	labelEventType                  = "event_type"
+	// MetricsNameRequestAttemptsTotal 逻辑请求发起的尝试总数，包括重试及对冲请求
+	MetricsNameRequestAttemptsTotal = "request_attempts_total"
+	// MetricsNameLogicalRequestTotal 使用尝试预算的逻辑请求总数
+	MetricsNameLogicalRequestTotal = "logical_request_total"
+	requestResultSuccess           = "success"
+	requestResultFail              = "fail"
+	requestResultExhausted         = "exhausted"
+	// MetricsNameFlowBudgetExhaustedTotal 服务发现流程预算耗尽的次数，按耗尽预算的步骤区分
+	MetricsNameFlowBudgetExhaustedTotal = "flow_budget_exhausted_total"
+	labelFlowAPI                        = "api"
+	labelFlowStep                       = "flow_step"
+	// MetricsNameConfigOperationDelay 配置中心操作的耗时分布，单位毫秒
+	MetricsNameConfigOperationDelay = "config_operation_delay"
+	// MetricsNameConfigPropagationDelay 配置变更从发布到通知监听器的时延分布，单位毫秒
+	MetricsNameConfigPropagationDelay = "config_propagation_delay"
+	labelConfigNamespace              = "namespace"
+	labelConfigGroup                  = "group"
+	labelConfigOperation              = "operation"
+	labelConfigResult                 = "result"
+	// MetricsNameServerConnectionAge 与server的连接被替换或者释放时的存活时长分布，单位秒
+	MetricsNameServerConnectionAge = "server_connection_age_seconds"
+	labelConnectionCluster         = "cluster"
+	labelConnectionReason          = "reason"
+	// MetricsNameNamespaceFallbackTotal 服务在请求的命名空间中不存在而回退到其他命名空间查找的次数
+	MetricsNameNamespaceFallbackTotal = "namespace_fallback_total"
+	labelFallbackNamespace            = "fallback_namespace"
+	fallbackResultFound               = "found"
+	fallbackResultNotFound            = "not_found"
+	// MetricsNameCacheMemoryBytes 本地缓存估算占用的内存字节数
+	MetricsNameCacheMemoryBytes = "cache_memory_bytes"
+	// MetricsNameCacheMemoryBudgetBytes 本地缓存的内存预算字节数
+	MetricsNameCacheMemoryBudgetBytes = "cache_memory_budget_bytes"
+	// MetricsNameCacheShedMode 本地缓存是否处于降载模式，1表示降载中
+	MetricsNameCacheShedMode = "cache_shed_mode"
+	// MetricsNameCacheShedEvictionsTotal 降载模式下淘汰的缓存条目数
+	MetricsNameCacheShedEvictionsTotal = "cache_shed_evictions_total"
+	// MetricsNameContractMismatchTotal 请求与被调服务契约不一致的次数
+	MetricsNameContractMismatchTotal = "contract_mismatch_total"
+	labelContractProtocol            = "protocol"
+	labelContractMethod              = "method"
+	labelContractReason              = "reason"
+	// MetricsNameClientConnections 客户端到被调服务的连接数
+	MetricsNameClientConnections = "client_connections"
+	// MetricsNameClientConnectionLimitedTotal 建立连接时因连接数限制排队或被拒绝的次数
+	MetricsNameClientConnectionLimitedTotal = "client_connection_limited_total"
+	labelConnectionLimitResult              = "result"
+	connectionLimitResultQueued             = "queued"
+	connectionLimitResultRejected           = "rejected"
+	// MetricsNameRateLimitExemptedTotal 匹配限流豁免、绕过限流的请求数
+	MetricsNameRateLimitExemptedTotal = "ratelimit_exempted_total"
+	labelRateLimitExemption           = "exemption"
+	// MetricsNameShadowComparisonTotal 影子请求的对比次数，按对比结果区分，不一致率为 mismatch 占全部结果的比例
+	MetricsNameShadowComparisonTotal = "shadow_comparison_total"
+	labelShadowNamespace             = "shadow_namespace"
+	labelShadowService               = "shadow_service"
+	labelShadowResult                = "result"
+)
+
+// connectionAgeBuckets 连接存活时长直方图的桶边界，单位秒
+var connectionAgeBuckets = []float64{1, 10, 60, 300, 600, 1800, 3600, 7200, 21600, 86400}
+
+// configDelayBuckets 配置中心时延直方图的桶边界，单位毫秒，长轮询及变更传播的时延可达分钟级
+var configDelayBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000, 120000}
+
 const (
 	// PluginName is the name of the plugin.
 	PluginName          = "prometheus"
@@ -89,7 +166,54 @@ type PrometheusReporter struct {
 
 	insCollector            *statcommon.StatInfoRevisionCollector
 	rateLimitCollector      *statcommon.StatInfoRevisionCollector
+	bulkheadCollector       *statcommon.StatInfoRevisionCollector
+	serverTrafficCollector  *statcommon.StatInfoRevisionCollector
+	flappingCollector       *statcommon.StatInfoRevisionCollector
 	circuitBreakerCollector *statcommon.StatInfoStatefulCollector
+	serverEndpointCollector *statcommon.StatInfoStatefulCollector
+	// 时延分位值采集器，未开启时为nil
+	latencyCollector *latencyCollector
+	// 携带 exemplar 的时延直方图，未开启时为nil
+	delayHistogram *prometheus.HistogramVec
+	// 插件panic次数
+	pluginPanicCounter *prometheus.CounterVec
+	// 缓存版本不一致次数
+	cacheDivergenceCounter *prometheus.CounterVec
+	// 逻辑请求的尝试次数及请求数
+	requestAttemptsCounter *prometheus.CounterVec
+	logicalRequestCounter  *prometheus.CounterVec
+	// 流程预算耗尽次数
+	flowBudgetCounter *prometheus.CounterVec
+	// 配置中心操作耗时及变更传播时延
+	configOperationHistogram   *prometheus.HistogramVec
+	configPropagationHistogram *prometheus.HistogramVec
+	// 连接存活时长
+	connectionAgeHistogram *prometheus.HistogramVec
+	// 命名空间回退次数
+	namespaceFallbackCounter *prometheus.CounterVec
+	// 本地缓存内存占用
+	cacheMemoryGauge       prometheus.Gauge
+	cacheMemoryBudgetGauge prometheus.Gauge
+	cacheShedModeGauge     prometheus.Gauge
+	cacheShedEvictCounter  prometheus.Counter
+	// 契约不一致次数
+	contractMismatchCounter *prometheus.CounterVec
+	// 客户端连接数及连接数限制
+	clientConnectionsGauge       *prometheus.GaugeVec
+	clientConnectionLimitCounter *prometheus.CounterVec
+	// 限流豁免的请求数
+	rateLimitExemptedCounter *prometheus.CounterVec
+	// 影子请求对比结果
+	shadowComparisonCounter *prometheus.CounterVec
+	// 限流热点标签值
+	topKCollector *topKCollector
+
+	// 成本归属标签的key，以及追加了成本归属标签后的label顺序
+	costLabelKeys         []string
+	serviceCallLabelOrder []string
+	rateLimitLabelOrder   []string
+	// 主调服务标签配置
+	callerLabels config.CallerLabelsConfig
 
 	cancel context.CancelFunc
 }
@@ -121,16 +245,186 @@ func (s *PrometheusReporter) Init(ctx *plugin.InitContext) error {
 	s.registry = prometheus.NewRegistry()
 	s.insCollector = statcommon.NewStatInfoRevisionCollector()
 	s.rateLimitCollector = statcommon.NewStatInfoRevisionCollector()
+	s.bulkheadCollector = statcommon.NewStatInfoRevisionCollector()
+	s.serverTrafficCollector = statcommon.NewStatInfoRevisionCollector()
+	s.flappingCollector = statcommon.NewStatInfoRevisionCollector()
 	s.circuitBreakerCollector = statcommon.NewStatInfoStatefulCollector()
-	if err := s.initSampleMapping(statcommon.ServiceCallStrategy, statcommon.ServiceCallLabelOrder); err != nil {
+	s.serverEndpointCollector = statcommon.NewStatInfoStatefulCollector()
+	costLabels := ctx.Config.GetGlobal().GetStatReporter().GetCostLabels()
+	s.callerLabels = ctx.Config.GetGlobal().GetStatReporter().GetCallerLabels()
+	serviceCallLabelOrder := statcommon.ServiceCallLabelOrder
+	if s.callerLabels != nil && !s.callerLabels.IsEnable() {
+		serviceCallLabelOrder = statcommon.RemoveCallerLabels(serviceCallLabelOrder)
+	}
+	var err error
+	if s.serviceCallLabelOrder, s.costLabelKeys, err = statcommon.AppendCostLabels(
+		serviceCallLabelOrder, costLabels); err != nil {
+		return err
+	}
+	if s.rateLimitLabelOrder, _, err = statcommon.AppendCostLabels(
+		statcommon.RateLimitLabelOrder, costLabels); err != nil {
 		return err
 	}
-	if err := s.initSampleMapping(statcommon.RateLimitStrategy, statcommon.RateLimitLabelOrder); err != nil {
+	if err := s.initSampleMapping(statcommon.ServiceCallStrategy, s.serviceCallLabelOrder); err != nil {
+		return err
+	}
+	if err := s.initSampleMapping(statcommon.RateLimitStrategy, s.rateLimitLabelOrder); err != nil {
 		return err
 	}
 	if err := s.initSampleMapping(statcommon.CircuitBreakerStrategy, statcommon.CircuitBreakerLabelOrder); err != nil {
 		return err
 	}
+	if err := s.initSampleMapping(statcommon.BulkheadStrategy, statcommon.BulkheadLabelOrder); err != nil {
+		return err
+	}
+	if err := s.initSampleMapping(statcommon.ServerEndpointStrategy, statcommon.ServerEndpointLabelOrder); err != nil {
+		return err
+	}
+	if err := s.initSampleMapping(statcommon.ServerTrafficStrategy, statcommon.ServerTrafficLabelOrder); err != nil {
+		return err
+	}
+	if err := s.initSampleMapping(statcommon.RegisterFlappingStrategy, statcommon.RegisterFlappingLabelOrder); err != nil {
+		return err
+	}
+	if s.cfg != nil && s.cfg.Latency != nil && s.cfg.Latency.IsEnable() {
+		s.latencyCollector = newLatencyCollector(s.cfg.Latency)
+		if err := s.registry.Register(s.latencyCollector); err != nil {
+			return err
+		}
+	}
+	s.pluginPanicCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNamePluginPanicTotal,
+		Help: "total of panics recovered in plugin invocations",
+	}, []string{labelPluginType, labelPluginName, labelPluginMethod})
+	if err := s.registry.Register(s.pluginPanicCounter); err != nil {
+		return err
+	}
+	s.cacheDivergenceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameCacheDivergenceTotal,
+		Help: "total of local cache revisions diverged from server found by revision audit",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, labelEventType})
+	if err := s.registry.Register(s.cacheDivergenceCounter); err != nil {
+		return err
+	}
+	requestLabels := []string{statcommon.CalleeNamespace, statcommon.CalleeService, statcommon.CalleeMethod,
+		statcommon.CalleeResult}
+	s.requestAttemptsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameRequestAttemptsTotal,
+		Help: "total of attempts issued by logical requests, including retries and hedges",
+	}, requestLabels)
+	if err := s.registry.Register(s.requestAttemptsCounter); err != nil {
+		return err
+	}
+	s.logicalRequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameLogicalRequestTotal,
+		Help: "total of logical requests accounted by request budget",
+	}, requestLabels)
+	if err := s.registry.Register(s.logicalRequestCounter); err != nil {
+		return err
+	}
+	s.flowBudgetCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameFlowBudgetExhaustedTotal,
+		Help: "total of discovery flows exhausting flow budget, by the step exhausting it",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, labelFlowAPI, labelFlowStep})
+	if err := s.registry.Register(s.flowBudgetCounter); err != nil {
+		return err
+	}
+	s.configOperationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MetricsNameConfigOperationDelay,
+		Help:    "delay of config center operations in milliseconds",
+		Buckets: configDelayBuckets,
+	}, []string{labelConfigNamespace, labelConfigGroup, labelConfigOperation, labelConfigResult})
+	if err := s.registry.Register(s.configOperationHistogram); err != nil {
+		return err
+	}
+	s.configPropagationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MetricsNameConfigPropagationDelay,
+		Help:    "delay from config release to listener notification in milliseconds",
+		Buckets: configDelayBuckets,
+	}, []string{labelConfigNamespace, labelConfigGroup})
+	if err := s.registry.Register(s.configPropagationHistogram); err != nil {
+		return err
+	}
+	s.connectionAgeHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MetricsNameServerConnectionAge,
+		Help:    "age of server connections in seconds when replaced or released",
+		Buckets: connectionAgeBuckets,
+	}, []string{labelConnectionCluster, labelConnectionReason})
+	if err := s.registry.Register(s.connectionAgeHistogram); err != nil {
+		return err
+	}
+	s.namespaceFallbackCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameNamespaceFallbackTotal,
+		Help: "total of discovery requests falling back to other namespaces for services not found",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, labelFallbackNamespace,
+		statcommon.CalleeResult})
+	if err := s.registry.Register(s.namespaceFallbackCounter); err != nil {
+		return err
+	}
+	s.cacheMemoryGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: MetricsNameCacheMemoryBytes,
+		Help: "estimated memory bytes used by local cache",
+	})
+	s.cacheMemoryBudgetGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: MetricsNameCacheMemoryBudgetBytes,
+		Help: "memory budget bytes of local cache",
+	})
+	s.cacheShedModeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: MetricsNameCacheShedMode,
+		Help: "whether local cache is in shed mode, 1 for shedding",
+	})
+	s.cacheShedEvictCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: MetricsNameCacheShedEvictionsTotal,
+		Help: "total of local cache entries evicted in shed mode",
+	})
+	for _, collector := range []prometheus.Collector{s.cacheMemoryGauge, s.cacheMemoryBudgetGauge,
+		s.cacheShedModeGauge, s.cacheShedEvictCounter} {
+		if err := s.registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	s.contractMismatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameContractMismatchTotal,
+		Help: "total of requests mismatching the callee service contract",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, labelContractProtocol, labelContractMethod,
+		labelContractReason})
+	if err := s.registry.Register(s.contractMismatchCounter); err != nil {
+		return err
+	}
+	s.clientConnectionsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: MetricsNameClientConnections,
+		Help: "client connections to callee service under connection limit",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService})
+	s.clientConnectionLimitCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameClientConnectionLimitedTotal,
+		Help: "total of client connections queued or rejected by connection limit",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, labelConnectionLimitResult})
+	s.rateLimitExemptedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameRateLimitExemptedTotal,
+		Help: "total of requests bypassing rate limit by exemption",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, statcommon.CalleeMethod,
+		labelRateLimitExemption})
+	s.shadowComparisonCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsNameShadowComparisonTotal,
+		Help: "total of shadow requests compared with primary responses",
+	}, []string{statcommon.CalleeNamespace, statcommon.CalleeService, statcommon.CalleeMethod,
+		labelShadowNamespace, labelShadowService, labelShadowResult})
+	for _, collector := range []prometheus.Collector{s.clientConnectionsGauge, s.clientConnectionLimitCounter,
+		s.rateLimitExemptedCounter, s.shadowComparisonCounter} {
+		if err := s.registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	s.topKCollector = newTopKCollector()
+	if err := s.registry.Register(s.topKCollector); err != nil {
+		return err
+	}
+	if s.cfg != nil && s.cfg.Exemplar != nil && s.cfg.Exemplar.Enable {
+		s.delayHistogram = newExemplarHistogram(s.cfg.Exemplar)
+		if err := s.registry.Register(s.delayHistogram); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -145,8 +439,16 @@ func (s *PrometheusReporter) ReportStat(metricsType model.MetricType, metricsVal
 				return nil
 			}
 			labels := statcommon.ConvertInsGaugeToLabels(val, s.clientIP)
+			statcommon.FillCallerLabels(labels, s.callerLabels)
+			statcommon.FillCostLabels(labels, s.costLabelKeys, val.CostLabels)
 			s.insCollector.CollectStatInfo(val, labels, statcommon.ServiceCallStrategy,
-				statcommon.ServiceCallLabelOrder)
+				s.serviceCallLabelOrder)
+			if s.latencyCollector != nil {
+				s.latencyCollector.observe(val)
+			}
+			if s.delayHistogram != nil {
+				observeWithExemplar(s.delayHistogram, val)
+			}
 		}
 	case model.RateLimitStat:
 		val, ok := metricsVal.(*model.RateLimitGauge)
@@ -155,8 +457,14 @@ func (s *PrometheusReporter) ReportStat(metricsType model.MetricType, metricsVal
 				return nil
 			}
 			labels := statcommon.ConvertRateLimitGaugeToLabels(val)
+			statcommon.FillCostLabels(labels, s.costLabelKeys, val.CostLabels)
 			s.rateLimitCollector.CollectStatInfo(val, labels, statcommon.RateLimitStrategy,
-				statcommon.RateLimitLabelOrder)
+				s.rateLimitLabelOrder)
+		}
+	case model.RateLimitTopKStat:
+		val, ok := metricsVal.(*model.RateLimitTopKGauge)
+		if ok && val != nil && s.topKCollector != nil {
+			s.topKCollector.update(val)
 		}
 	case model.CircuitBreakStat:
 		val, ok := metricsVal.(*model.CircuitBreakGauge)
@@ -168,6 +476,147 @@ func (s *PrometheusReporter) ReportStat(metricsType model.MetricType, metricsVal
 			s.circuitBreakerCollector.CollectStatInfo(val, labels, statcommon.CircuitBreakerStrategy,
 				statcommon.CircuitBreakerLabelOrder)
 		}
+	case model.BulkheadStat:
+		val, ok := metricsVal.(*model.BulkheadGauge)
+		if ok {
+			if s.bulkheadCollector == nil || val == nil {
+				return nil
+			}
+			labels := statcommon.ConvertBulkheadGaugeToLabels(val)
+			s.bulkheadCollector.CollectStatInfo(val, labels, statcommon.BulkheadStrategy,
+				statcommon.BulkheadLabelOrder)
+		}
+	case model.ServerEndpointStat:
+		val, ok := metricsVal.(*model.ServerEndpointGauge)
+		if ok {
+			if s.serverEndpointCollector == nil || val == nil {
+				return nil
+			}
+			labels := statcommon.ConvertServerEndpointGaugeToLabels(val)
+			s.serverEndpointCollector.CollectStatInfo(val, labels, statcommon.ServerEndpointStrategy,
+				statcommon.ServerEndpointLabelOrder)
+		}
+	case model.ServerTrafficStat:
+		val, ok := metricsVal.(*model.ServerTrafficGauge)
+		if ok {
+			if s.serverTrafficCollector == nil || val == nil {
+				return nil
+			}
+			labels := statcommon.ConvertServerTrafficGaugeToLabels(val)
+			s.serverTrafficCollector.CollectStatInfo(val, labels, statcommon.ServerTrafficStrategy,
+				statcommon.ServerTrafficLabelOrder)
+		}
+	case model.RegisterFlappingStat:
+		val, ok := metricsVal.(*model.RegisterFlappingGauge)
+		if ok {
+			if s.flappingCollector == nil || val == nil {
+				return nil
+			}
+			labels := statcommon.ConvertRegisterFlappingGaugeToLabels(val)
+			s.flappingCollector.CollectStatInfo(val, labels, statcommon.RegisterFlappingStrategy,
+				statcommon.RegisterFlappingLabelOrder)
+		}
+	case model.PluginPanicStat:
+		val, ok := metricsVal.(*model.PluginPanicGauge)
+		if ok && val != nil && s.pluginPanicCounter != nil {
+			s.pluginPanicCounter.WithLabelValues(val.PluginType, val.PluginName, val.Method).Inc()
+		}
+	case model.CacheDivergenceStat:
+		val, ok := metricsVal.(*model.CacheDivergenceGauge)
+		if ok && val != nil && s.cacheDivergenceCounter != nil {
+			s.cacheDivergenceCounter.WithLabelValues(val.Namespace, val.Service, val.EventType).Inc()
+		}
+	case model.RequestAttemptsStat:
+		val, ok := metricsVal.(*model.RequestAttemptsGauge)
+		if ok && val != nil && s.requestAttemptsCounter != nil {
+			result := requestResultFail
+			if val.Success {
+				result = requestResultSuccess
+			} else if val.Exhausted {
+				result = requestResultExhausted
+			}
+			s.requestAttemptsCounter.WithLabelValues(val.Namespace, val.Service, val.Method, result).
+				Add(float64(val.Attempts))
+			s.logicalRequestCounter.WithLabelValues(val.Namespace, val.Service, val.Method, result).Inc()
+		}
+	case model.FlowBudgetStat:
+		val, ok := metricsVal.(*model.FlowBudgetGauge)
+		if ok && val != nil && s.flowBudgetCounter != nil {
+			s.flowBudgetCounter.WithLabelValues(val.Namespace, val.Service, val.API.String(), string(val.Step)).Inc()
+		}
+	case model.ConfigOperationStat:
+		val, ok := metricsVal.(*model.ConfigOperationGauge)
+		if ok && val != nil && s.configOperationHistogram != nil {
+			result := requestResultFail
+			if val.Success {
+				result = requestResultSuccess
+			}
+			s.configOperationHistogram.WithLabelValues(val.Namespace, val.FileGroup, string(val.Operation), result).
+				Observe(float64(val.Delay.Milliseconds()))
+		}
+	case model.ConfigPropagationStat:
+		val, ok := metricsVal.(*model.ConfigPropagationGauge)
+		if ok && val != nil && s.configPropagationHistogram != nil {
+			s.configPropagationHistogram.WithLabelValues(val.Namespace, val.FileGroup).
+				Observe(float64(val.Delay.Milliseconds()))
+		}
+	case model.ConnectionAgeStat:
+		val, ok := metricsVal.(*model.ConnectionAgeGauge)
+		if ok && val != nil && s.connectionAgeHistogram != nil {
+			s.connectionAgeHistogram.WithLabelValues(val.Cluster, val.Reason).Observe(val.Age.Seconds())
+		}
+	case model.NamespaceFallbackStat:
+		val, ok := metricsVal.(*model.NamespaceFallbackGauge)
+		if ok && val != nil && s.namespaceFallbackCounter != nil {
+			result := fallbackResultNotFound
+			if val.Found {
+				result = fallbackResultFound
+			}
+			s.namespaceFallbackCounter.WithLabelValues(val.Namespace, val.Service, val.FallbackNamespace, result).Inc()
+		}
+	case model.CacheMemoryStat:
+		val, ok := metricsVal.(*model.CacheMemoryGauge)
+		if ok && val != nil && s.cacheMemoryGauge != nil {
+			s.cacheMemoryGauge.Set(float64(val.UsedBytes))
+			s.cacheMemoryBudgetGauge.Set(float64(val.BudgetBytes))
+			shedMode := 0.0
+			if val.Shedding {
+				shedMode = 1
+			}
+			s.cacheShedModeGauge.Set(shedMode)
+			s.cacheShedEvictCounter.Add(float64(val.Evicted))
+		}
+	case model.ContractMismatchStat:
+		val, ok := metricsVal.(*model.ContractMismatchGauge)
+		if ok && val != nil && s.contractMismatchCounter != nil {
+			// 请求路径可能带有参数，为避免指标维度膨胀不作为标签，具体路径见告警日志
+			s.contractMismatchCounter.WithLabelValues(val.Namespace, val.Service, val.Protocol, val.Method,
+				string(val.Reason)).Inc()
+		}
+	case model.ConnectionLimitStat:
+		val, ok := metricsVal.(*model.ConnectionLimitGauge)
+		if ok && val != nil && s.clientConnectionsGauge != nil {
+			s.clientConnectionsGauge.WithLabelValues(val.Namespace, val.Service).Set(float64(val.Connections))
+			switch val.Result {
+			case model.BulkheadQueued:
+				s.clientConnectionLimitCounter.WithLabelValues(val.Namespace, val.Service,
+					connectionLimitResultQueued).Inc()
+			case model.BulkheadRejected:
+				s.clientConnectionLimitCounter.WithLabelValues(val.Namespace, val.Service,
+					connectionLimitResultRejected).Inc()
+			}
+		}
+	case model.RateLimitExemptionStat:
+		val, ok := metricsVal.(*model.RateLimitExemptionGauge)
+		if ok && val != nil && s.rateLimitExemptedCounter != nil {
+			s.rateLimitExemptedCounter.WithLabelValues(val.Namespace, val.Service, val.Method, val.Exemption).Inc()
+		}
+	case model.ShadowComparisonStat:
+		val, ok := metricsVal.(*model.ShadowComparisonGauge)
+		if ok && val != nil && s.shadowComparisonCounter != nil {
+			s.shadowComparisonCounter.WithLabelValues(val.Namespace, val.Service, val.Method, val.ShadowNamespace,
+				val.ShadowService, string(val.Result)).Inc()
+		}
 	}
 	return nil
 }
@@ -218,6 +667,14 @@ func (s *PrometheusReporter) Info() model.StatInfo {
 	return s.action.Info()
 }
 
+// Flush 推送模式下立即推送一次统计数据，拉取模式无需处理.
+func (s *PrometheusReporter) Flush() error {
+	if pushAction, ok := s.action.(*PushAction); ok && pushAction.pusher != nil {
+		return pushAction.push()
+	}
+	return nil
+}
+
 // Destroy .销毁插件.
 func (s *PrometheusReporter) Destroy() error {
 	if s.PluginBase != nil {
@@ -241,16 +698,47 @@ func (s *PrometheusReporter) Destroy() error {
 
 type metricsHttpHandler struct {
 	handler http.Handler
+	auth    *AuthConfig
 	lock    sync.RWMutex
 }
 
 // ServeHTTP 提供 prometheus http 服务.
 func (p *metricsHttpHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !p.authorized(request) {
+		writer.Header().Set("WWW-Authenticate", `Basic realm="polaris-metrics"`)
+		http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 	p.handler.ServeHTTP(writer, request)
 }
 
+// authorized 校验 basic-auth 或 bearer token，未配置鉴权时直接通过
+func (p *metricsHttpHandler) authorized(request *http.Request) bool {
+	if p.auth == nil {
+		return true
+	}
+	if len(p.auth.BearerToken) > 0 {
+		authorization := request.Header.Get("Authorization")
+		if strings.HasPrefix(authorization, "Bearer ") &&
+			secureEqual(strings.TrimPrefix(authorization, "Bearer "), p.auth.BearerToken) {
+			return true
+		}
+	}
+	if len(p.auth.Username) > 0 {
+		username, password, ok := request.BasicAuth()
+		if ok && secureEqual(username, p.auth.Username) && secureEqual(password, p.auth.Password) {
+			return true
+		}
+	}
+	return false
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
 type ReportAction interface {
 	Init(initCtx *plugin.InitContext, reporter *PrometheusReporter)
 	Run(ctx context.Context)
@@ -279,6 +767,9 @@ func (pa *PullAction) Init(initCtx *plugin.InitContext, reporter *PrometheusRepo
 	if len(pa.cfg.IP) != 0 {
 		pa.bindIP = pa.cfg.IP
 	}
+	if pa.cfg.LocalhostOnly {
+		pa.bindIP = "127.0.0.1"
+	}
 	pa.bindPort = int32(pa.cfg.port)
 }
 
@@ -299,11 +790,21 @@ func (pa *PullAction) doAggregation(ctx context.Context) {
 		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.insCollector,
 			pa.reporter.insCollector.GetCurrentRevision())
 		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.circuitBreakerCollector, 0)
+		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.serverEndpointCollector, 0)
 		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.rateLimitCollector,
 			pa.reporter.rateLimitCollector.GetCurrentRevision())
+		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.bulkheadCollector,
+			pa.reporter.bulkheadCollector.GetCurrentRevision())
+		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.serverTrafficCollector,
+			pa.reporter.serverTrafficCollector.GetCurrentRevision())
+		statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.flappingCollector,
+			pa.reporter.flappingCollector.GetCurrentRevision())
 
 		log.GetBaseLogger().Debugf("[metrics][push] revision collector inc current revision to %d", pa.reporter.insCollector.IncRevision())
 		log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.rateLimitCollector.IncRevision())
+		log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.bulkheadCollector.IncRevision())
+		log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.serverTrafficCollector.IncRevision())
+		log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.flappingCollector.IncRevision())
 	}
 
 	for {
@@ -320,7 +821,9 @@ func (pa *PullAction) Run(ctx context.Context) {
 	if pa.bindPort < 0 {
 		return
 	}
-	go pa.doAggregation(ctx)
+	profiling.Go(PluginName, "aggregation", func() {
+		pa.doAggregation(ctx)
+	})
 	go func() {
 		ln, err := net.Listen("tcp", fmt.Sprintf("%s:%d", pa.bindIP, pa.bindPort))
 		if err != nil {
@@ -330,12 +833,21 @@ func (pa *PullAction) Run(ctx context.Context) {
 		}
 		pa.ln = ln
 		pa.bindPort = int32(ln.Addr().(*net.TCPAddr).Port)
-		handler := metricsHttpHandler{
-			handler: promhttp.HandlerFor(pa.reporter.registry, promhttp.HandlerOpts{}),
+		handler := &metricsHttpHandler{
+			handler: promhttp.HandlerFor(pa.reporter.registry, promhttp.HandlerOpts{
+				// exemplar 只能通过 OpenMetrics 格式导出，由抓取端通过 Accept 头协商
+				EnableOpenMetrics: pa.cfg.Exemplar != nil && pa.cfg.Exemplar.Enable,
+			}),
+			auth: pa.cfg.Auth,
 		}
 
 		log.GetBaseLogger().Infof("[metrics][push] start metrics http-server address : %s", fmt.Sprintf("%s:%d", pa.bindIP, pa.bindPort))
-		if err := http.Serve(ln, &handler); err != nil {
+		if pa.cfg.TLS != nil {
+			err = http.ServeTLS(ln, handler, pa.cfg.TLS.CertFile, pa.cfg.TLS.KeyFile)
+		} else {
+			err = http.Serve(ln, handler)
+		}
+		if err != nil {
 			log.GetBaseLogger().Errorf("[metrics][push] start metrics http-server fail : %s", err)
 			return
 		}
@@ -351,15 +863,23 @@ func (pa *PullAction) Info() model.StatInfo {
 		Target:   PluginName,
 		Port:     uint32(pa.bindPort),
 		Path:     "/metrics",
-		Protocol: "http",
+		Protocol: pa.protocol(),
 	}
 }
 
+func (pa *PullAction) protocol() string {
+	if pa.cfg != nil && pa.cfg.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
 type PushAction struct {
 	initCtx  *plugin.InitContext
 	reporter *PrometheusReporter
 	cfg      *Config
 	pusher   *push.Pusher
+	mutex    sync.Mutex
 }
 
 func (pa *PushAction) Init(initCtx *plugin.InitContext, reporter *PrometheusReporter) {
@@ -380,39 +900,54 @@ func (pa *PushAction) Close() {
 	}
 }
 
-func (pa *PushAction) Run(ctx context.Context) {
-	go func() {
-		pushTicker := time.NewTicker(pa.cfg.Interval)
-
-		action := func() {
-			defer func() {
-				if err := recover(); err != nil {
-					log.GetBaseLogger().Errorf("[metrics][push] stat metrics to pushgateway panic", zap.Any("error", err))
-				}
-			}()
-
-			log.GetBaseLogger().Infof("[metrics][push] start push stat metrics to pushgateway")
+// push 聚合统计数据并推送到 pushgateway
+func (pa *PushAction) push() (err error) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	defer func() {
+		if e := recover(); e != nil {
+			log.GetBaseLogger().Errorf("[metrics][push] stat metrics to pushgateway panic", zap.Any("error", e))
+			err = fmt.Errorf("push metrics panic: %v", e)
+		}
+	}()
 
-			statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.insCollector,
-				pa.reporter.insCollector.GetCurrentRevision())
-			statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.circuitBreakerCollector, 0)
-			statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.rateLimitCollector,
-				pa.reporter.rateLimitCollector.GetCurrentRevision())
+	log.GetBaseLogger().Infof("[metrics][push] start push stat metrics to pushgateway")
+
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.insCollector,
+		pa.reporter.insCollector.GetCurrentRevision())
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.circuitBreakerCollector, 0)
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.serverEndpointCollector, 0)
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.rateLimitCollector,
+		pa.reporter.rateLimitCollector.GetCurrentRevision())
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.bulkheadCollector,
+		pa.reporter.bulkheadCollector.GetCurrentRevision())
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.serverTrafficCollector,
+		pa.reporter.serverTrafficCollector.GetCurrentRevision())
+	statcommon.PutDataFromContainerInOrder(pa.reporter.metricVecCaches, pa.reporter.flappingCollector,
+		pa.reporter.flappingCollector.GetCurrentRevision())
+
+	if err := pa.pusher.
+		Push(); err != nil {
+		log.GetBaseLogger().Errorf("push metrics to pushgateway fail: %s", err.Error())
+		return err
+	}
 
-			if err := pa.pusher.
-				Push(); err != nil {
-				log.GetBaseLogger().Errorf("push metrics to pushgateway fail: %s", err.Error())
-				return
-			}
+	log.GetBaseLogger().Debugf("[metrics][push] revision collector inc current revision to %d", pa.reporter.insCollector.IncRevision())
+	log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.rateLimitCollector.IncRevision())
+	log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.bulkheadCollector.IncRevision())
+	log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.serverTrafficCollector.IncRevision())
+	log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.flappingCollector.IncRevision())
+	return nil
+}
 
-			log.GetBaseLogger().Debugf("[metrics][push] revision collector inc current revision to %d", pa.reporter.insCollector.IncRevision())
-			log.GetBaseLogger().Debugf("[metrics][push] collector inc current revision to %d", pa.reporter.rateLimitCollector.IncRevision())
-		}
+func (pa *PushAction) Run(ctx context.Context) {
+	go func() {
+		pushTicker := time.NewTicker(pa.cfg.Interval)
 
 		for {
 			select {
 			case <-pushTicker.C:
-				action()
+				_ = pa.push()
 			case <-ctx.Done():
 				pushTicker.Stop()
 				return
@@ -0,0 +1,150 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+func TestMetricsHandlerAuth(t *testing.T) {
+	handler := &metricsHttpHandler{
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		auth:    &AuthConfig{Username: "polaris", Password: "secret", BearerToken: "token"},
+	}
+	serve := func(setup func(r *http.Request)) int {
+		request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		setup(request)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	if code := serve(func(r *http.Request) {}); code != http.StatusUnauthorized {
+		t.Fatalf("expect 401 without credential, got %d", code)
+	}
+	if code := serve(func(r *http.Request) { r.SetBasicAuth("polaris", "wrong") }); code != http.StatusUnauthorized {
+		t.Fatalf("expect 401 with wrong password, got %d", code)
+	}
+	if code := serve(func(r *http.Request) { r.SetBasicAuth("polaris", "secret") }); code != http.StatusOK {
+		t.Fatalf("expect 200 with basic auth, got %d", code)
+	}
+	if code := serve(func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") }); code != http.StatusOK {
+		t.Fatalf("expect 200 with bearer token, got %d", code)
+	}
+}
+
+type testInstance struct {
+	model.Instance
+}
+
+func (i *testInstance) GetNamespace() string {
+	return "default"
+}
+
+func (i *testInstance) GetService() string {
+	return "svc"
+}
+
+func TestLatencyCollector(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	collector := newLatencyCollector(cfg.Latency)
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 100; i++ {
+		delay := time.Duration(i) * time.Millisecond
+		collector.observe(&model.ServiceCallResult{CalledInstance: &testInstance{}, Method: "get", Delay: &delay})
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 1 || len(families[0].GetMetric()) != 1 {
+		t.Fatalf("expect one latency series, got %v", families)
+	}
+	summary := families[0].GetMetric()[0].GetSummary()
+	if summary.GetSampleCount() != 100 {
+		t.Fatalf("expect 100 samples, got %d", summary.GetSampleCount())
+	}
+	for _, quantile := range summary.GetQuantile() {
+		if quantile.GetQuantile() == 0.99 && (quantile.GetValue() < 97 || quantile.GetValue() > 100) {
+			t.Fatalf("unexpected p99 %v", quantile.GetValue())
+		}
+	}
+}
+
+func TestExemplarHistogram(t *testing.T) {
+	cfg := &Config{Exemplar: &ExemplarConfig{Enable: true}}
+	cfg.SetDefault()
+	histogram := newExemplarHistogram(cfg.Exemplar)
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(histogram); err != nil {
+		t.Fatal(err)
+	}
+	delay := 30 * time.Millisecond
+	result := &model.ServiceCallResult{CalledInstance: &testInstance{}, Method: "get", Delay: &delay}
+	observeWithExemplar(histogram, result.SetTraceID("4bf92f3577b34da6a3ce929d0e0e4736"))
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var traceID string
+	for _, bucket := range families[0].GetMetric()[0].GetHistogram().GetBucket() {
+		for _, label := range bucket.GetExemplar().GetLabel() {
+			if label.GetName() == exemplarTraceIDLabel {
+				traceID = label.GetValue()
+			}
+		}
+	}
+	if traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expect exemplar with trace id, got %q", traceID)
+	}
+}
+
+func TestCallerLabels(t *testing.T) {
+	cfg := &config.CallerLabelsConfigImpl{Namespace: "Production", Service: "gateway"}
+	cfg.SetDefault()
+	labels := map[string]string{
+		statcommon.CalleeService:   "svc",
+		statcommon.CallerNamespace: statcommon.NilValue,
+		statcommon.CallerService:   "order",
+	}
+	statcommon.FillCallerLabels(labels, cfg)
+	if labels[statcommon.CallerNamespace] != "Production" || labels[statcommon.CallerService] != "order" {
+		t.Fatalf("expect default caller namespace and propagated caller service, got %v", labels)
+	}
+
+	cfg.SetEnable(false)
+	statcommon.FillCallerLabels(labels, cfg)
+	order := statcommon.RemoveCallerLabels(
+		[]string{statcommon.CalleeService, statcommon.CallerNamespace, statcommon.CallerService})
+	gaugeVec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_caller_labels"}, order)
+	if _, err := gaugeVec.GetMetricWith(labels); err != nil {
+		t.Fatalf("labels %v mismatch label order %v: %v", labels, order, err)
+	}
+}
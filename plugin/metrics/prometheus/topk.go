@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	statcommon "github.com/polarismesh/polaris-go/plugin/metrics/common"
+)
+
+// topKLabelOrder 热点标签值的维度
+var topKLabelOrder = []string{statcommon.CalleeNamespace, statcommon.CalleeService, statcommon.RuleName,
+	statcommon.RateLimitLabels}
+
+type topKSnapshot struct {
+	gauge      *model.RateLimitTopKGauge
+	reportedAt time.Time
+}
+
+// topKCollector 导出各限流规则最近一个周期的热点标签值，每次上报整体替换该规则的数据，
+// 超过两个周期未更新的规则不再导出，避免已经冷却的标签值一直残留
+type topKCollector struct {
+	passDesc  *prometheus.Desc
+	limitDesc *prometheus.Desc
+	mutex     sync.Mutex
+	snapshots map[string]*topKSnapshot
+}
+
+func newTopKCollector() *topKCollector {
+	return &topKCollector{
+		passDesc: prometheus.NewDesc(statcommon.MetricsNameRateLimitTopKPass,
+			"passed requests of the top label values per rate limit rule in the last interval", topKLabelOrder, nil),
+		limitDesc: prometheus.NewDesc(statcommon.MetricsNameRateLimitTopKLimit,
+			"limited requests of the top label values per rate limit rule in the last interval", topKLabelOrder, nil),
+		snapshots: map[string]*topKSnapshot{},
+	}
+}
+
+func (c *topKCollector) update(val *model.RateLimitTopKGauge) {
+	key := val.Namespace + "#" + val.Service + "#" + val.RuleName
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.snapshots[key] = &topKSnapshot{gauge: val, reportedAt: time.Now()}
+}
+
+// Describe 实现 prometheus.Collector
+func (c *topKCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.passDesc
+	ch <- c.limitDesc
+}
+
+// Collect 实现 prometheus.Collector
+func (c *topKCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	for key, snapshot := range c.snapshots {
+		val := snapshot.gauge
+		if now.Sub(snapshot.reportedAt) > 2*val.Interval {
+			delete(c.snapshots, key)
+			continue
+		}
+		for _, entry := range val.Entries {
+			labelValues := []string{val.Namespace, val.Service, val.RuleName, entry.Labels}
+			ch <- prometheus.MustNewConstMetric(c.passDesc, prometheus.GaugeValue, float64(entry.Passed), labelValues...)
+			ch <- prometheus.MustNewConstMetric(c.limitDesc, prometheus.GaugeValue, float64(entry.Limited),
+				labelValues...)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 每个计数槽位占用的字节数
+	sharedSlotSize = 8
+	// 计数槽位中，低32位存放已使用配额，高32位存放周期序号
+	sharedUsedMask = 0xffffffff
+)
+
+var (
+	sharedCounters      = make(map[string]*SharedQuotaCounter)
+	sharedCountersMutex sync.Mutex
+)
+
+// SharedQuotaCounter 基于内存映射文件的跨进程配额计数器
+// 同一主机上映射同一文件的进程共享计数，每个槽位对应一个限流周期的配额；
+// 不支持内存映射的平台（windows）上退化为进程内共享的计数
+type SharedQuotaCounter struct {
+	path string
+	// 映射的计数文件，持有共享文件锁表示本进程正在使用该文件
+	file  *os.File
+	data  []byte
+	slots []*uint64
+	// 引用计数，由sharedCountersMutex保护，归零时解除映射
+	refs int
+	// 解除映射后不再访问映射内存，保护仍在进行中的配额分配
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// GetSharedQuotaCounter 获取共享配额计数器，相同key的计数器在进程内只映射一次，使用完毕后需调用Close
+func GetSharedQuotaCounter(dir string, key string, slotCount int) (*SharedQuotaCounter, error) {
+	hashValue, err := model.HashStr(key)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%016x.quota", hashValue))
+	sharedCountersMutex.Lock()
+	defer sharedCountersMutex.Unlock()
+	if counter, ok := sharedCounters[path]; ok && len(counter.slots) >= slotCount {
+		counter.refs++
+		return counter, nil
+	}
+	counter, err := openSharedQuotaCounter(path, slotCount)
+	if err != nil {
+		return nil, err
+	}
+	counter.refs++
+	sharedCounters[path] = counter
+	return counter, nil
+}
+
+// Close 释放计数器的一次引用，最后一个引用释放时解除内存映射，没有其他进程使用时同时删除计数文件，
+// 之后计数器不再分配配额
+func (s *SharedQuotaCounter) Close() error {
+	sharedCountersMutex.Lock()
+	defer sharedCountersMutex.Unlock()
+	if s.refs <= 0 {
+		return nil
+	}
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+	if sharedCounters[s.path] == s {
+		delete(sharedCounters, s.path)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	return releaseSharedQuota(s)
+}
+
+// TryAcquire 在周期stage内尝试分配token个配额，返回分配后的剩余配额，小于0则代表配额不足且未进行分配；
+// 计数器已经释放时返回false，由调用方改为进程内分配
+func (s *SharedQuotaCounter) TryAcquire(slot int, stage int64, token uint32, maxAmount uint32) (int64, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return 0, false
+	}
+	addr := s.slots[slot]
+	stageMark := uint64(uint32(stage)) << 32
+	for {
+		value := atomic.LoadUint64(addr)
+		var used uint64
+		if value&^sharedUsedMask == stageMark {
+			used = value & sharedUsedMask
+		}
+		left := int64(maxAmount) - int64(used) - int64(token)
+		if left < 0 {
+			return left, true
+		}
+		if atomic.CompareAndSwapUint64(addr, value, stageMark|(used+uint64(token))) {
+			return left, true
+		}
+	}
+}
+
+// GiveBack 归还周期stage内分配的配额，周期已经切换或者计数器已经释放则忽略
+func (s *SharedQuotaCounter) GiveBack(slot int, stage int64, token uint32) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return
+	}
+	addr := s.slots[slot]
+	stageMark := uint64(uint32(stage)) << 32
+	for {
+		value := atomic.LoadUint64(addr)
+		if value&^sharedUsedMask != stageMark {
+			return
+		}
+		used := value & sharedUsedMask
+		if used < uint64(token) {
+			used = uint64(token)
+		}
+		if atomic.CompareAndSwapUint64(addr, value, stageMark|(used-uint64(token))) {
+			return
+		}
+	}
+}
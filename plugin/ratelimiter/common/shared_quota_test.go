@@ -0,0 +1,55 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+)
+
+// TestSharedQuotaCounterClose 测试共享配额计数器按引用计数释放
+func TestSharedQuotaCounterClose(t *testing.T) {
+	dir := t.TempDir()
+	first, err := GetSharedQuotaCounter(dir, "rule#window", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GetSharedQuotaCounter(dir, "rule#window", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("expect counter mapped once in process")
+	}
+	if err = first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if left, ok := second.TryAcquire(1, 1, 1, 2); !ok || left != 1 {
+		t.Fatalf("expect counter usable before last close, got left %d", left)
+	}
+	if err = second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	third, err := GetSharedQuotaCounter(dir, "rule#window", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer third.Close()
+	if third == first {
+		t.Fatal("expect counter remapped after last close")
+	}
+}
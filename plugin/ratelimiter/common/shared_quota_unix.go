@@ -0,0 +1,107 @@
+//go:build !windows
+// +build !windows
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// openSharedQuotaCounter 打开并映射共享计数文件，映射期间持有文件的共享锁
+func openSharedQuotaCounter(path string, slotCount int) (*SharedQuotaCounter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	file, err := openLockedQuotaFile(path)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(slotCount * sharedSlotSize)
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if info.Size() < size {
+		if err = file.Truncate(size); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	counter := &SharedQuotaCounter{
+		path:  path,
+		file:  file,
+		data:  data,
+		slots: make([]*uint64, slotCount),
+	}
+	for i := 0; i < slotCount; i++ {
+		counter.slots[i] = (*uint64)(unsafe.Pointer(&data[i*sharedSlotSize]))
+	}
+	return counter, nil
+}
+
+// openLockedQuotaFile 打开计数文件并加共享锁，加锁期间文件被其他进程删除时重新创建
+func openLockedQuotaFile(path string) (*os.File, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+		if err != nil {
+			return nil, err
+		}
+		if err = syscall.Flock(int(file.Fd()), syscall.LOCK_SH); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		opened, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		current, err := os.Stat(path)
+		if err == nil && os.SameFile(opened, current) {
+			return file, nil
+		}
+		_ = file.Close()
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+}
+
+// releaseSharedQuota 解除共享计数文件的内存映射，没有其他进程持有文件锁时删除计数文件
+func releaseSharedQuota(counter *SharedQuotaCounter) error {
+	err := syscall.Munmap(counter.data)
+	if syscall.Flock(int(counter.file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) == nil {
+		if removeErr := os.Remove(counter.path); removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+			err = removeErr
+		}
+	}
+	if closeErr := counter.file.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+// +build !windows
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestSharedQuotaCounterAcrossMappings 测试映射同一文件的两个计数器共享配额
+func TestSharedQuotaCounterAcrossMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.quota")
+	first, err := openSharedQuotaCounter(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := openSharedQuotaCounter(path, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := first.TryAcquire(0, 1, 2, 3); left != 1 {
+		t.Fatalf("expect left 1, got %d", left)
+	}
+	if left, _ := second.TryAcquire(0, 1, 2, 3); left >= 0 {
+		t.Fatalf("expect quota exhausted, got left %d", left)
+	}
+	second.GiveBack(0, 1, 1)
+	if left, _ := second.TryAcquire(0, 1, 2, 3); left != 0 {
+		t.Fatalf("expect left 0 after give back, got %d", left)
+	}
+	// 切换到新的周期后配额重置
+	if left, _ := first.TryAcquire(0, 2, 1, 3); left != 2 {
+		t.Fatalf("expect left 2 in new stage, got %d", left)
+	}
+}
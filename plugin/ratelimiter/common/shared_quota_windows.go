@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+// openSharedQuotaCounter windows 不支持 syscall.Mmap，计数只在进程内共享，
+// 同一进程内的多个SDK实例仍共享配额，不同进程之间各自计数
+func openSharedQuotaCounter(path string, slotCount int) (*SharedQuotaCounter, error) {
+	values := make([]uint64, slotCount)
+	counter := &SharedQuotaCounter{
+		path:  path,
+		slots: make([]*uint64, slotCount),
+	}
+	for i := range values {
+		counter.slots[i] = &values[i]
+	}
+	return counter, nil
+}
+
+// releaseSharedQuota 进程内计数无需解除映射
+func releaseSharedQuota(counter *SharedQuotaCounter) error {
+	return nil
+}
@@ -0,0 +1,129 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leakybucket
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// LeakyBucket 带排队队列的漏桶
+type LeakyBucket struct {
+	mutex sync.Mutex
+	// 每个请求流出的时间间隔，取所有amount中最严格的速率
+	leakIntervalMilli float64
+	// 下一个请求可以流出的时间点
+	nextLeakMilli float64
+	// 桶容量
+	maxQueueSize int
+	// 最大排队时间
+	maxQueuingMilli int64
+	// 是不是有amount为0
+	rejectAll bool
+}
+
+func createLeakyBucket(criteria *ratelimiter.InitCriteria, cfg *Config) *LeakyBucket {
+	bucket := &LeakyBucket{
+		maxQueueSize:    cfg.MaxQueueSize,
+		maxQueuingMilli: cfg.MaxQueuingTime.Milliseconds(),
+	}
+	rule := criteria.DstRule
+	if rule.GetMaxQueueDelay().GetValue() > 0 {
+		bucket.maxQueuingMilli = int64(rule.GetMaxQueueDelay().GetValue()) * 1000
+	}
+	for _, amount := range rule.GetAmounts() {
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if maxAmount == 0 {
+			bucket.rejectAll = true
+			return bucket
+		}
+		duration, _ := pb.ConvertDuration(amount.GetValidDuration())
+		interval := float64(model.ToMilliSeconds(duration)) / float64(maxAmount)
+		bucket.leakIntervalMilli = math.Max(bucket.leakIntervalMilli, interval)
+	}
+	return bucket
+}
+
+// GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
+func (l *LeakyBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if l.rejectAll {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: "leakyBucket RateLimiter: reject for zero rule amount",
+		}
+	}
+	if token == 0 {
+		token = 1
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := float64(curTimeMs)
+	if l.nextLeakMilli < now {
+		l.nextLeakMilli = now
+	}
+	waitMilli := int64(math.Ceil(l.nextLeakMilli - now))
+	var queueDepth int
+	if l.leakIntervalMilli > 0 {
+		queueDepth = int((l.nextLeakMilli - now) / l.leakIntervalMilli)
+		if queueDepth >= l.maxQueueSize {
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: fmt.Sprintf("leakyBucket RateLimiter: queue size %d exceed maxQueueSize %d",
+					queueDepth, l.maxQueueSize),
+			}
+		}
+	}
+	if waitMilli > l.maxQueuingMilli {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: fmt.Sprintf("leakyBucket RateLimiter: queueing time %dms exceed maxQueuingTime %dms",
+				waitMilli, l.maxQueuingMilli),
+		}
+	}
+	l.nextLeakMilli += l.leakIntervalMilli * float64(token)
+	return &model.QuotaResponse{
+		Code:       model.QuotaResultOk,
+		WaitMs:     waitMilli,
+		QueueDepth: queueDepth,
+	}
+}
+
+// Release 释放配额（仅对于并发数限流有用）
+func (l *LeakyBucket) Release() {
+
+}
+
+// OnRemoteUpdate 远程配额更新
+func (l *LeakyBucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+
+}
+
+// GetQuotaUsed 拉取本地使用配额情况以供上报
+func (l *LeakyBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	return ratelimiter.UsageInfo{CurTimeMilli: curTimeMilli}
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (l *LeakyBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	return nil
+}
@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leakybucket
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func newTestBucket(maxAmount uint32, maxQueueSize int, maxQueuingTime time.Duration) *LeakyBucket {
+	rule := &apitraffic.Rule{
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: &durationpb.Duration{Seconds: 1},
+			},
+		},
+	}
+	return createLeakyBucket(&ratelimiter.InitCriteria{DstRule: rule},
+		&Config{MaxQueueSize: maxQueueSize, MaxQueuingTime: model.ToDurationPtr(maxQueuingTime)})
+}
+
+// TestLeakyBucketQueuing 测试请求按固定速率流出，排队时间超过上限时被限流
+func TestLeakyBucketQueuing(t *testing.T) {
+	bucket := newTestBucket(10, 100, 250*time.Millisecond)
+	start := int64(1_000_000)
+	for i, expectWait := range []int64{0, 100, 200} {
+		resp := bucket.GetQuota(start, 1)
+		if resp.Code != model.QuotaResultOk || resp.WaitMs != expectWait || resp.QueueDepth != i {
+			t.Fatalf("request %d: expect wait %dms, got %+v", i, expectWait, resp)
+		}
+	}
+	if resp := bucket.GetQuota(start, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatal("expect limited when queuing time exceeds maxQueuingTime")
+	}
+	// 桶中的请求流出后不再需要排队
+	if resp := bucket.GetQuota(start+1000, 1); resp.Code != model.QuotaResultOk || resp.WaitMs != 0 {
+		t.Fatalf("expect no wait after queue drained, got %+v", resp)
+	}
+}
+
+// TestLeakyBucketQueueSize 测试排队请求数达到桶容量时被限流
+func TestLeakyBucketQueueSize(t *testing.T) {
+	bucket := newTestBucket(10, 2, time.Minute)
+	start := int64(1_000_000)
+	for i := 0; i < 2; i++ {
+		if resp := bucket.GetQuota(start, 1); resp.Code != model.QuotaResultOk {
+			t.Fatalf("request %d should be queued, info %s", i, resp.Info)
+		}
+	}
+	if resp := bucket.GetQuota(start, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatal("expect limited when queue is full")
+	}
+	if resp := newTestBucket(0, 2, time.Minute).GetQuota(start, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatal("zero amount should reject all")
+	}
+}
@@ -23,7 +23,13 @@ import (
 )
 
 type QuotaBucketReject struct {
-	bucket *RemoteAwareQpsBucket
+	bucket  *RemoteAwareQpsBucket
+	limiter *RateLimiterReject
+}
+
+// Close 窗口过期或者规则变更时释放窗口占用的进程间共享配额计数器
+func (q *QuotaBucketReject) Close() error {
+	return q.limiter.releaseBucket(q.bucket)
 }
 
 // GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
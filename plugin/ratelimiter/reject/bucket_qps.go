@@ -27,6 +27,7 @@ import (
 	"github.com/modern-go/reflect2"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 
+	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -34,13 +35,17 @@ import (
 	"github.com/polarismesh/polaris-go/plugin/ratelimiter/common"
 )
 
-// NewRemoteAwareQpsBucket 创建QPS远程限流窗口
-func NewRemoteAwareQpsBucket(criteria *ratelimiter.InitCriteria) *RemoteAwareQpsBucket {
+// NewRemoteAwareQpsBucket 创建QPS远程限流窗口，shareDir不为空时单机配额在同主机进程间共享
+func NewRemoteAwareQpsBucket(criteria *ratelimiter.InitCriteria, shareDir string) *RemoteAwareQpsBucket {
 	raqb := &RemoteAwareQpsBucket{
 		uniqueKey:      criteria.WindowKey,
 		identifierPool: &sync.Pool{},
 	}
 	raqb.tokenBuckets = initTokenBuckets(criteria.DstRule, criteria.WindowKey)
+	if len(shareDir) > 0 && criteria.DstRule.GetType() == apitraffic.Rule_LOCAL {
+		raqb.sharedCounter = raqb.tokenBuckets.attachSharedCounter(
+			shareDir, criteria.DstRule.GetId().GetValue(), criteria.WindowKey)
+	}
 	raqb.tokenBucketMap = make(map[int64]*TokenBucket, len(raqb.tokenBuckets))
 	for _, tokenBucket := range raqb.tokenBuckets {
 		raqb.tokenBucketMap[tokenBucket.validDurationMilli] = tokenBucket
@@ -58,6 +63,20 @@ type RemoteAwareQpsBucket struct {
 	tokenBucketMap map[int64]*TokenBucket
 	// 存放[]UpdateIdentifier数据
 	identifierPool *sync.Pool
+	// 进程间共享的配额计数器，未共享时为nil
+	sharedCounter *common.SharedQuotaCounter
+	closeOnce     sync.Once
+}
+
+// Close 释放进程间共享的配额计数器，释放后单机配额改为进程内分配
+func (r *RemoteAwareQpsBucket) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		if nil != r.sharedCounter {
+			err = r.sharedCounter.Close()
+		}
+	})
+	return err
 }
 
 const (
@@ -239,6 +258,10 @@ type TokenBucket struct {
 	sliceWindow *common.SlidingWindow
 	// 共享的规则数据
 	shareInfo *BucketShareInfo
+	// 进程间共享的配额计数器，不为空时本地配额从共享计数器中分配
+	sharedCounter *common.SharedQuotaCounter
+	// 在共享计数器中的槽位
+	sharedSlot int
 }
 
 // NewTokenBucket 创建令牌桶
@@ -277,6 +300,10 @@ func (t *TokenBucket) GiveBackToken(identifier *UpdateIdentifier, token int64, m
 			atomic.AddInt64(&t.tokenLeft, token)
 		}
 	case Local:
+		if nil != t.sharedCounter {
+			t.sharedCounter.GiveBack(t.sharedSlot, identifier.stageStartMilli/t.validDurationMilli, uint32(token))
+			return
+		}
 		if atomic.LoadInt64(&t.stageStartMilli) == identifier.stageStartMilli {
 			atomic.AddInt64(&t.tokenLeft, token)
 		}
@@ -359,6 +386,14 @@ func (t *TokenBucket) calculateStageStart(curTimeMs int64) int64 {
 // tryAllocateLocal 本地分配
 func (t *TokenBucket) tryAllocateLocal(
 	token uint32, nowMilli int64, identifier *UpdateIdentifier) (int64, TokenBucketMode) {
+	if nil != t.sharedCounter {
+		stageStartMilli := t.calculateStageStart(nowMilli)
+		identifier.stageStartMilli = stageStartMilli
+		if left, ok := t.sharedCounter.TryAcquire(
+			t.sharedSlot, stageStartMilli/t.validDurationMilli, token, t.ruleTokenAmount); ok {
+			return left, Local
+		}
+	}
 	t.initLocalStageOnLocalConfig(nowMilli)
 	t.mutex.RLock()
 	defer t.mutex.RUnlock()
@@ -505,6 +540,22 @@ func (tbs TokenBuckets) Swap(i, j int) {
 	tbs[i], tbs[j] = tbs[j], tbs[i]
 }
 
+// attachSharedCounter 为令牌桶绑定进程间共享的配额计数器，返回绑定的计数器，失败则退化为进程内配额并返回nil
+func (tbs TokenBuckets) attachSharedCounter(
+	shareDir string, ruleId string, windowKey string) *common.SharedQuotaCounter {
+	counter, err := common.GetSharedQuotaCounter(shareDir, ruleId+config.DefaultNamesSeparator+windowKey, len(tbs))
+	if err != nil {
+		log.GetBaseLogger().Warnf("[RateLimit]fail to share local quota in %s, windowKey %s, err: %v",
+			shareDir, windowKey, err)
+		return nil
+	}
+	for i, tokenBucket := range tbs {
+		tokenBucket.sharedCounter = counter
+		tokenBucket.sharedSlot = i
+	}
+	return counter
+}
+
 // initTokenBuckets 初始化令牌桶
 func initTokenBuckets(rule *apitraffic.Rule, windowKey string) TokenBuckets {
 	shareInfo := &BucketShareInfo{}
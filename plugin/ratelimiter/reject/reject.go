@@ -18,6 +18,8 @@
 package reject
 
 import (
+	"sync"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
@@ -28,6 +30,11 @@ import (
 // RateLimiterReject 基于直接拒绝策略的限流控制器
 type RateLimiterReject struct {
 	*plugin.PluginBase
+	// 进程间共享单机配额的目录，为空则不共享
+	shareDir string
+	// 绑定了共享配额计数器的窗口，窗口过期、规则变更或者插件销毁时释放
+	mutex         sync.Mutex
+	sharedBuckets map[*RemoteAwareQpsBucket]struct{}
 }
 
 // Type 插件类型
@@ -43,12 +50,34 @@ func (g *RateLimiterReject) Name() string {
 // Init 初始化插件
 func (g *RateLimiterReject) Init(ctx *plugin.InitContext) error {
 	g.PluginBase = plugin.NewPluginBase(ctx)
+	rateLimitCfg := ctx.Config.GetProvider().GetRateLimit()
+	if rateLimitCfg.IsShareLocalQuota() {
+		g.shareDir = rateLimitCfg.GetShareDir()
+	}
 	return nil
 }
 
-// Destroy 销毁插件，可用于释放资源
+// Destroy 销毁插件，释放共享配额计数器的内存映射
 func (g *RateLimiterReject) Destroy() error {
-	return nil
+	g.mutex.Lock()
+	buckets := g.sharedBuckets
+	g.sharedBuckets = nil
+	g.mutex.Unlock()
+	var closeErr error
+	for bucket := range buckets {
+		if err := bucket.Close(); err != nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// releaseBucket 释放窗口绑定的共享配额计数器
+func (g *RateLimiterReject) releaseBucket(bucket *RemoteAwareQpsBucket) error {
+	g.mutex.Lock()
+	delete(g.sharedBuckets, bucket)
+	g.mutex.Unlock()
+	return bucket.Close()
 }
 
 // IsEnable enable
@@ -59,8 +88,18 @@ func (g *RateLimiterReject) IsEnable(cfg config.Configuration) bool {
 // InitQuota 初始化并创建限流窗口
 // 主流程会在首次调用，以及规则对象变更的时候，调用该方法
 func (g *RateLimiterReject) InitQuota(criteria *ratelimiter.InitCriteria) ratelimiter.QuotaBucket {
+	bucket := NewRemoteAwareQpsBucket(criteria, g.shareDir)
+	if nil != bucket.sharedCounter {
+		g.mutex.Lock()
+		if nil == g.sharedBuckets {
+			g.sharedBuckets = make(map[*RemoteAwareQpsBucket]struct{})
+		}
+		g.sharedBuckets[bucket] = struct{}{}
+		g.mutex.Unlock()
+	}
 	return &QuotaBucketReject{
-		bucket: NewRemoteAwareQpsBucket(criteria),
+		bucket:  bucket,
+		limiter: g,
 	}
 }
 
@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package reject
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// TestReleaseSharedCounter 测试窗口释放后共享配额计数器及计数文件被回收，窗口仍可在进程内分配配额
+func TestReleaseSharedCounter(t *testing.T) {
+	dir := t.TempDir()
+	limiter := &RateLimiterReject{shareDir: dir}
+	rule := &apitraffic.Rule{
+		Id:   wrapperspb.String("rule"),
+		Type: apitraffic.Rule_LOCAL,
+		Amounts: []*apitraffic.Amount{
+			{MaxAmount: wrapperspb.UInt32(2), ValidDuration: &durationpb.Duration{Seconds: 1}},
+		},
+	}
+	bucket := limiter.InitQuota(&ratelimiter.InitCriteria{DstRule: rule, WindowKey: "uid:1"})
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 || len(limiter.sharedBuckets) != 1 {
+		t.Fatalf("expect one shared counter, got %d files, %d buckets", len(files), len(limiter.sharedBuckets))
+	}
+	if resp := bucket.GetQuota(model.CurrentMillisecond(), 1); resp.Code != model.QuotaResultOk {
+		t.Fatalf("expect quota allocated, got %+v", resp)
+	}
+
+	if err := bucket.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 || len(limiter.sharedBuckets) != 0 {
+		t.Fatalf("expect shared counter released, got %d files, %d buckets", len(files), len(limiter.sharedBuckets))
+	}
+	if resp := bucket.GetQuota(model.CurrentMillisecond(), 1); resp.Code != model.QuotaResultOk {
+		t.Fatalf("expect quota allocated in process after release, got %+v", resp)
+	}
+	if err := limiter.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,146 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidingcounter
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// minValidDurationMilli 限流区间的最小值，避免区间为0时无法划分周期
+const minValidDurationMilli int64 = 1
+
+// windowCounter 单个amount对应的计数器，记录当前周期以及上一个周期通过的请求数
+type windowCounter struct {
+	// 限流区间 单位毫秒
+	validDurationMilli int64
+	// 最大配额数
+	maxAmount uint32
+	// 当前周期起始时间
+	curStartMilli int64
+	// 当前周期通过数
+	curCount uint32
+	// 上一个周期通过数
+	prevCount uint32
+}
+
+// roll 根据当前时间滚动周期
+func (w *windowCounter) roll(curTimeMs int64) {
+	startMilli := curTimeMs - curTimeMs%w.validDurationMilli
+	if startMilli == w.curStartMilli {
+		return
+	}
+	if startMilli == w.curStartMilli+w.validDurationMilli {
+		w.prevCount = w.curCount
+	} else {
+		w.prevCount = 0
+	}
+	w.curCount = 0
+	w.curStartMilli = startMilli
+}
+
+// estimate 估算最近一个validDuration内通过的请求数
+func (w *windowCounter) estimate(curTimeMs int64) float64 {
+	elapsed := curTimeMs - w.curStartMilli
+	prevWeight := float64(w.validDurationMilli-elapsed) / float64(w.validDurationMilli)
+	return float64(w.prevCount)*prevWeight + float64(w.curCount)
+}
+
+// SlidingCounterBucket 滑动窗口计数算法桶
+type SlidingCounterBucket struct {
+	mutex    sync.Mutex
+	counters []*windowCounter
+	// 是不是有amount为0
+	rejectAll bool
+}
+
+func createSlidingCounterBucket(criteria *ratelimiter.InitCriteria) *SlidingCounterBucket {
+	bucket := &SlidingCounterBucket{}
+	for _, amount := range criteria.DstRule.GetAmounts() {
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if maxAmount == 0 {
+			bucket.rejectAll = true
+			return bucket
+		}
+		duration, _ := pb.ConvertDuration(amount.GetValidDuration())
+		validDurationMilli := model.ToMilliSeconds(duration)
+		if validDurationMilli < minValidDurationMilli {
+			validDurationMilli = minValidDurationMilli
+		}
+		bucket.counters = append(bucket.counters, &windowCounter{
+			validDurationMilli: validDurationMilli,
+			maxAmount:          maxAmount,
+		})
+	}
+	return bucket
+}
+
+// GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
+func (s *SlidingCounterBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if s.rejectAll {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: "slidingCounter RateLimiter: reject for zero rule amount",
+		}
+	}
+	if token == 0 {
+		token = 1
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, counter := range s.counters {
+		counter.roll(curTimeMs)
+		if counter.estimate(curTimeMs)+float64(token) > float64(counter.maxAmount) {
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: fmt.Sprintf("slidingCounter RateLimiter: exceed %d requests in %dms",
+					counter.maxAmount, counter.validDurationMilli),
+			}
+		}
+	}
+	for _, counter := range s.counters {
+		counter.curCount += token
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 释放配额（仅对于并发数限流有用）
+func (s *SlidingCounterBucket) Release() {
+
+}
+
+// OnRemoteUpdate 远程配额更新，滑动窗口计数仅支持单机限流
+func (s *SlidingCounterBucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+
+}
+
+// GetQuotaUsed 拉取本地使用配额情况以供上报
+func (s *SlidingCounterBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	return ratelimiter.UsageInfo{CurTimeMilli: curTimeMilli}
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (s *SlidingCounterBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	return nil
+}
@@ -0,0 +1,86 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidingcounter
+
+import (
+	"testing"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func newTestBucket(maxAmount uint32, durationSecond int64) *SlidingCounterBucket {
+	rule := &apitraffic.Rule{
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: &durationpb.Duration{Seconds: durationSecond},
+			},
+		},
+	}
+	return createSlidingCounterBucket(&ratelimiter.InitCriteria{DstRule: rule})
+}
+
+// passed 在同一时间点连续申请配额，返回通过的请求数
+func passed(bucket *SlidingCounterBucket, curTimeMs int64, times int) int {
+	var count int
+	for i := 0; i < times; i++ {
+		if bucket.GetQuota(curTimeMs, 1).Code == model.QuotaResultOk {
+			count++
+		}
+	}
+	return count
+}
+
+// TestSlidingCounterWeightedWindow 测试上一个周期的通过数按剩余比例计入当前窗口
+func TestSlidingCounterWeightedWindow(t *testing.T) {
+	bucket := newTestBucket(10, 1)
+	if count := passed(bucket, 1_000_000, 20); count != 10 {
+		t.Fatalf("expect 10 requests passed in first window, got %d", count)
+	}
+	// 当前周期过半，上一个周期的10个请求按一半计入
+	if count := passed(bucket, 1_001_500, 20); count != 5 {
+		t.Fatalf("expect 5 requests passed in half window, got %d", count)
+	}
+	// 跳过一个完整周期后上一个周期的计数清零
+	if count := passed(bucket, 1_003_000, 20); count != 10 {
+		t.Fatalf("expect 10 requests passed after idle window, got %d", count)
+	}
+}
+
+// TestSlidingCounterZeroAmount 测试配额为0时全部拒绝
+func TestSlidingCounterZeroAmount(t *testing.T) {
+	if resp := newTestBucket(0, 1).GetQuota(1000, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatal("zero amount should reject all")
+	}
+}
+
+// TestSlidingCounterZeroDuration 测试限流区间为0时按最小区间计数，不会出现除零
+func TestSlidingCounterZeroDuration(t *testing.T) {
+	bucket := newTestBucket(2, 0)
+	if count := passed(bucket, 1000, 5); count != 2 {
+		t.Fatalf("expect 2 requests passed in minimal window, got %d", count)
+	}
+	if count := passed(bucket, 1002, 5); count != 2 {
+		t.Fatalf("expect 2 requests passed in next minimal window, got %d", count)
+	}
+}
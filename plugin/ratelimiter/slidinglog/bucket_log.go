@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// logEntry 同一毫秒内通过的请求数
+type logEntry struct {
+	timeMs int64
+	count  uint32
+}
+
+// requestLog 单个amount对应的请求时间日志，按时间顺序记录窗口内每毫秒通过的请求数，
+// 滑出窗口的记录随即淘汰，占用的内存随实际流量变化，最多为 min(maxAmount, 窗口毫秒数) 条
+type requestLog struct {
+	// 限流区间 单位毫秒
+	validDurationMilli int64
+	// 最大配额数
+	maxAmount uint32
+	// 窗口内的记录，按时间升序
+	entries []logEntry
+	// 窗口内通过的请求总数
+	used uint32
+}
+
+// expire 淘汰已经滑出窗口的记录
+func (r *requestLog) expire(curTimeMs int64) {
+	var i int
+	for i < len(r.entries) && curTimeMs-r.entries[i].timeMs >= r.validDurationMilli {
+		r.used -= r.entries[i].count
+		i++
+	}
+	if i == len(r.entries) {
+		// 复用底层数组，避免空闲后重新分配
+		r.entries = r.entries[:0]
+		return
+	}
+	r.entries = r.entries[i:]
+}
+
+// acquirable 判断在当前时间下是否能够再通过token个请求
+func (r *requestLog) acquirable(curTimeMs int64, token uint32) bool {
+	r.expire(curTimeMs)
+	return uint64(r.used)+uint64(token) <= uint64(r.maxAmount)
+}
+
+// record 记录本次通过的请求
+func (r *requestLog) record(curTimeMs int64, token uint32) {
+	r.used += token
+	if last := len(r.entries) - 1; last >= 0 && r.entries[last].timeMs == curTimeMs {
+		r.entries[last].count += token
+		return
+	}
+	r.entries = append(r.entries, logEntry{timeMs: curTimeMs, count: token})
+}
+
+// SlidingLogBucket 滑动窗口日志算法桶，任意长度为validDuration的时间段内通过的请求数都不会超过maxAmount
+type SlidingLogBucket struct {
+	mutex sync.Mutex
+	logs  []*requestLog
+	// 是不是有amount为0
+	rejectAll bool
+}
+
+func createSlidingLogBucket(criteria *ratelimiter.InitCriteria) *SlidingLogBucket {
+	bucket := &SlidingLogBucket{}
+	for _, amount := range criteria.DstRule.GetAmounts() {
+		maxAmount := amount.GetMaxAmount().GetValue()
+		if maxAmount == 0 {
+			bucket.rejectAll = true
+			return bucket
+		}
+		duration, _ := pb.ConvertDuration(amount.GetValidDuration())
+		bucket.logs = append(bucket.logs, &requestLog{
+			validDurationMilli: model.ToMilliSeconds(duration),
+			maxAmount:          maxAmount,
+		})
+	}
+	return bucket
+}
+
+// GetQuota 在令牌桶/漏桶中进行单个配额的划扣，并返回本次分配的结果
+func (s *SlidingLogBucket) GetQuota(curTimeMs int64, token uint32) *model.QuotaResponse {
+	if s.rejectAll {
+		return &model.QuotaResponse{
+			Code: model.QuotaResultLimited,
+			Info: "slidingLog RateLimiter: reject for zero rule amount",
+		}
+	}
+	if token == 0 {
+		token = 1
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, reqLog := range s.logs {
+		if !reqLog.acquirable(curTimeMs, token) {
+			return &model.QuotaResponse{
+				Code: model.QuotaResultLimited,
+				Info: fmt.Sprintf("slidingLog RateLimiter: exceed %d requests in %dms",
+					reqLog.maxAmount, reqLog.validDurationMilli),
+			}
+		}
+	}
+	for _, reqLog := range s.logs {
+		reqLog.record(curTimeMs, token)
+	}
+	return &model.QuotaResponse{
+		Code: model.QuotaResultOk,
+	}
+}
+
+// Release 释放配额（仅对于并发数限流有用）
+func (s *SlidingLogBucket) Release() {
+
+}
+
+// OnRemoteUpdate 远程配额更新，滑动窗口日志仅支持单机精确限流
+func (s *SlidingLogBucket) OnRemoteUpdate(remoteQuota ratelimiter.RemoteQuotaResult) {
+
+}
+
+// GetQuotaUsed 拉取本地使用配额情况以供上报
+func (s *SlidingLogBucket) GetQuotaUsed(curTimeMilli int64) ratelimiter.UsageInfo {
+	return ratelimiter.UsageInfo{CurTimeMilli: curTimeMilli}
+}
+
+// GetAmountInfos 获取规则的限流阈值信息
+func (s *SlidingLogBucket) GetAmountInfos() []ratelimiter.AmountInfo {
+	return nil
+}
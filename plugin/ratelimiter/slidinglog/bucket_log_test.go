@@ -0,0 +1,85 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slidinglog
+
+import (
+	"testing"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+func newTestBucket(maxAmount uint32, durationSecond int64) *SlidingLogBucket {
+	rule := &apitraffic.Rule{
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(maxAmount),
+				ValidDuration: &durationpb.Duration{Seconds: durationSecond},
+			},
+		},
+	}
+	return createSlidingLogBucket(&ratelimiter.InitCriteria{DstRule: rule})
+}
+
+// TestSlidingLogNoBoundaryBurst 测试窗口边界不会出现突发流量
+func TestSlidingLogNoBoundaryBurst(t *testing.T) {
+	bucket := newTestBucket(5, 1)
+	start := int64(1_000_900)
+	for i := 0; i < 5; i++ {
+		if resp := bucket.GetQuota(start, 1); resp.Code != model.QuotaResultOk {
+			t.Fatalf("request %d should pass, info %s", i, resp.Info)
+		}
+	}
+	// 跨过固定窗口的边界，但还未过滑动窗口，应当被限流
+	if resp := bucket.GetQuota(start+200, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatalf("request across fixed window boundary should be limited")
+	}
+	if resp := bucket.GetQuota(start+1000, 1); resp.Code != model.QuotaResultOk {
+		t.Fatalf("request after sliding window should pass, info %s", resp.Info)
+	}
+}
+
+// TestSlidingLogZeroAmount 测试配额为0时全部拒绝
+func TestSlidingLogZeroAmount(t *testing.T) {
+	bucket := newTestBucket(0, 1)
+	if resp := bucket.GetQuota(1000, 1); resp.Code != model.QuotaResultLimited {
+		t.Fatalf("zero amount should reject all")
+	}
+}
+
+// TestSlidingLogMemoryByUsage 测试日志占用随实际流量变化，不按配额上限预先分配
+func TestSlidingLogMemoryByUsage(t *testing.T) {
+	bucket := newTestBucket(1_000_000_000, 1)
+	reqLog := bucket.logs[0]
+	if cap(reqLog.entries) != 0 {
+		t.Fatalf("expect no preallocation, got cap %d", cap(reqLog.entries))
+	}
+	start := int64(1_000_000)
+	for i := int64(0); i < 3000; i++ {
+		// 每毫秒两次请求合并为一条记录
+		bucket.GetQuota(start+i, 1)
+		bucket.GetQuota(start+i, 1)
+	}
+	if len(reqLog.entries) != 1000 || reqLog.used != 2000 {
+		t.Fatalf("expect only entries in window kept, got %d entries, used %d", len(reqLog.entries), reqLog.used)
+	}
+}
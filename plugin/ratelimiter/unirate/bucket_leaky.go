@@ -134,9 +134,14 @@ func (l *LeakyBucket) allocateQuota() *model.QuotaResponse {
 	// 如果等待时间在上限之内，那么放通
 	if waitDuration <= l.maxQueuingDuration {
 		// log.Printf("grant quota, waitDuration %v", waitDuration)
+		var queueDepth int
+		if costDuration > 0 {
+			queueDepth = int(waitDuration / costDuration)
+		}
 		return &model.QuotaResponse{
-			Code:   model.QuotaResultOk,
-			WaitMs: waitDuration,
+			Code:       model.QuotaResultOk,
+			WaitMs:     waitDuration,
+			QueueDepth: queueDepth,
 		}
 	}
 	// 如果等待时间超过配置的上限，那么拒绝
@@ -0,0 +1,59 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package unirate
+
+import (
+	"testing"
+	"time"
+
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/ratelimiter"
+)
+
+// TestLeakyBucketQueueDepth 测试匀速排队时返回的等待时间和排队深度随排队请求递增
+func TestLeakyBucketQueueDepth(t *testing.T) {
+	rule := &apitraffic.Rule{
+		Amounts: []*apitraffic.Amount{
+			{
+				MaxAmount:     wrapperspb.UInt32(1),
+				ValidDuration: &durationpb.Duration{Seconds: 1},
+			},
+		},
+	}
+	bucket := createLeakyBucket(&ratelimiter.InitCriteria{DstRule: rule},
+		&Config{MaxQueuingTime: model.ToDurationPtr(10 * time.Second)})
+	if resp := bucket.GetQuota(0, 1); resp.Code != model.QuotaResultOk || resp.WaitMs != 0 || resp.QueueDepth != 0 {
+		t.Fatalf("first request should pass without queuing, got %+v", resp)
+	}
+	var lastWait int64
+	lastDepth := -1
+	for i := 0; i < 4; i++ {
+		resp := bucket.GetQuota(0, 1)
+		if resp.Code != model.QuotaResultOk || resp.WaitMs <= lastWait || resp.QueueDepth <= lastDepth {
+			t.Fatalf("request %d: expect longer wait and deeper queue, got %+v", i, resp)
+		}
+		lastWait, lastDepth = resp.WaitMs, resp.QueueDepth
+	}
+	if lastDepth < 3 {
+		t.Fatalf("expect at least 3 requests ahead, got %d", lastDepth)
+	}
+}
@@ -41,8 +41,8 @@ func RegisterRequestToProto(request *model.InstanceRegisterRequest) (pbInstance
 	if nil != request.Version {
 		pbInstance.Version = &wrappers.StringValue{Value: *request.Version}
 	}
-	if nil != request.Metadata {
-		pbInstance.Metadata = request.Metadata
+	if metadata := model.WithTopologyMetadata(request.Metadata, request.Location); nil != metadata {
+		pbInstance.Metadata = metadata
 	}
 	if nil != request.Healthy {
 		pbInstance.Healthy = &wrappers.BoolValue{Value: *request.Healthy}
@@ -39,6 +39,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	"github.com/polarismesh/polaris-go/pkg/profiling"
 )
 
 const (
@@ -93,6 +94,8 @@ type DiscoverConnector struct {
 	// 创建具体调度客户端的逻辑
 	createClient DiscoverClientCreator
 	scalableRand *rand.ScalableRand
+	// 缓存版本巡检配置
+	revisionAudit config.RevisionAuditConfig
 }
 
 // 任务对象，用于在connector协程中做轮转处理
@@ -115,6 +118,7 @@ func (g *DiscoverConnector) Init(ctx *plugin.InitContext, createClient DiscoverC
 	g.messageTimeout = ctxConfig.GetGlobal().GetServerConnector().GetMessageTimeout()
 	g.connManager = ctx.ConnManager
 	g.createClient = createClient
+	g.revisionAudit = ctxConfig.GetConsumer().GetLocalCache().GetRevisionAudit()
 	for _, cachedSvc := range g.cachedServerServices {
 		g.connManager.UpdateServers(cachedSvc)
 	}
@@ -125,9 +129,12 @@ func (g *DiscoverConnector) StartUpdateRoutines() {
 	g.updateTaskSet = &sync.Map{}
 	g.taskChannel = make(chan *clientTask, g.queueSize)
 	g.retryPriorityTaskChannel = make(chan model.ServiceEventKey, g.queueSize)
-	go g.doSend()
-	go g.doRetry()
-	go g.doLog()
+	profiling.Go("serverConnector", "discoverSend", g.doSend)
+	profiling.Go("serverConnector", "discoverRetry", g.doRetry)
+	profiling.Go("serverConnector", "discoverLog", g.doLog)
+	if g.revisionAudit != nil && g.revisionAudit.IsEnable() {
+		profiling.Go("serverConnector", "revisionAudit", g.doAudit)
+	}
 }
 
 // 将存储在原子变量里面的时间转化为string
@@ -613,7 +620,7 @@ func (g *DiscoverConnector) newStream(task *serviceUpdateTask) (streamingClient
 	streamingClient.pendingTasks[task.ServiceEventKey] = task
 	streamingClient.lastRecvTime.Store(time.Now())
 	// 启动streamingClient的接收协程
-	go streamingClient.receiveAndNotify()
+	profiling.Go("serverConnector", "discoverReceive", streamingClient.receiveAndNotify)
 finally:
 	if err != nil {
 		if nil != streamingClient.connection {
@@ -954,11 +961,26 @@ func (g *DiscoverConnector) UpdateServers(key *model.ServiceEventKey) error {
 
 // 同步进行服务或规则发现
 func (g *DiscoverConnector) syncUpdateTask(task *serviceUpdateTask) error {
-	var curTime = time.Now()
+	var request = task.toDiscoverRequest()
+	task.msgSendTime.Store(time.Now())
+	atomic.AddUint64(&task.totalRequests, 1)
+	resp, connection, err := g.discoverOnce(task, request)
+	if err != nil {
+		return err
+	}
+	svcEvent, _ := discoverResponseToEvent(resp, task.ServiceEventKey, connection)
+	atomic.AddUint64(&task.successUpdates, 1)
+	task.handler.OnServiceUpdate(svcEvent)
+	return nil
+}
+
+// discoverOnce 使用独立的stream发送一次服务发现请求并等待应答
+func (g *DiscoverConnector) discoverOnce(task *serviceUpdateTask,
+	request *apiservice.DiscoverRequest) (*apiservice.DiscoverResponse, *network.Connection, error) {
 	// 获取服务发现server连接
 	connection, err := g.connManager.GetConnection(OpKeyDiscover, task.targetCluster)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer connection.Release(OpKeyDiscover)
 	reqID := NextDiscoverReqID()
@@ -972,18 +994,15 @@ func (g *DiscoverConnector) syncUpdateTask(task *serviceUpdateTask) error {
 		defer cancel()
 	}
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	log.GetNetworkLogger().Debugf("sync stream %s created, connection %s, timeout %v",
 		reqID, connection.ConnID, g.connectionIdleTimeout)
-	var request = task.toDiscoverRequest()
-	task.msgSendTime.Store(curTime)
-	atomic.AddUint64(&task.totalRequests, 1)
 	err = discoverClient.Send(request)
 	if err != nil {
 		log.GetNetworkLogger().Errorf(
 			"fail to send request for service %v, error is %+v", task.ServiceEventKey, err)
-		return err
+		return nil, nil, err
 	}
 	resp, err := discoverClient.Recv()
 	var sdkErr model.SDKError
@@ -1000,12 +1019,9 @@ func (g *DiscoverConnector) syncUpdateTask(task *serviceUpdateTask) error {
 		}
 	}
 	if nil != sdkErr {
-		return sdkErr
+		return nil, nil, sdkErr
 	}
 	// 打印应答报文
 	logDiscoverResponse(resp, connection)
-	svcEvent, _ := discoverResponseToEvent(resp, task.ServiceEventKey, connection)
-	atomic.AddUint64(&task.successUpdates, 1)
-	task.handler.OnServiceUpdate(svcEvent)
-	return nil
+	return resp, connection, nil
 }
@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/version"
+)
+
+const (
+	// HeaderSDKVersion SDK版本
+	HeaderSDKVersion = "polaris-sdk-version"
+	// HeaderSDKLanguage SDK语言
+	HeaderSDKLanguage = "polaris-sdk-language"
+	// HeaderClientPID 客户端进程号
+	HeaderClientPID = "polaris-client-pid"
+	// HeaderClientHost 客户端主机名
+	HeaderClientHost = "polaris-client-host"
+	// HeaderClientID 客户端ID
+	HeaderClientID = "polaris-client-id"
+	// HeaderClientLabelPrefix 客户端标签的前缀，标签名转为小写，非法字符替换为'-'
+	HeaderClientLabelPrefix = "polaris-client-label-"
+)
+
+// BuildIdentityMetadata 构建访问服务端时携带的客户端身份信息，未启用时返回nil
+func BuildIdentityMetadata(cfg config.ClientConfig) metadata.MD {
+	if cfg == nil || !cfg.IsIdentityMetadataEnable() {
+		return nil
+	}
+	md := metadata.MD{}
+	md.Set(HeaderSDKVersion, version.Version)
+	md.Set(HeaderSDKLanguage, "go")
+	md.Set(HeaderClientPID, strconv.Itoa(os.Getpid()))
+	if hostname, err := os.Hostname(); err == nil {
+		md.Set(HeaderClientHost, hostname)
+	}
+	if len(cfg.GetId()) > 0 {
+		md.Set(HeaderClientID, metadataValue(cfg.GetId()))
+	}
+	for key, value := range cfg.GetLabels() {
+		md.Set(HeaderClientLabelPrefix+metadataKey(key), metadataValue(value))
+	}
+	return md
+}
+
+// IdentityDialOptions 返回在每次调用时附加客户端身份信息的连接选项
+func IdentityDialOptions(md metadata.MD) []grpc.DialOption {
+	if len(md) == 0 {
+		return nil
+	}
+	pairs := make([]string, 0, 2*len(md))
+	for key, values := range md {
+		for _, value := range values {
+			pairs = append(pairs, key, value)
+		}
+	}
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+			method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(metadata.AppendToOutgoingContext(ctx, pairs...), desc, cc, method, opts...)
+		}),
+	}
+}
+
+// metadataKey 将标签名转换为合法的gRPC头部名称
+func metadataKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, key)
+}
+
+// metadataValue gRPC头部的值只允许可打印的ASCII字符，其他情况进行转义
+func metadataValue(value string) string {
+	for _, r := range value {
+		if r < 0x20 || r > 0x7E {
+			return url.QueryEscape(value)
+		}
+	}
+	return value
+}
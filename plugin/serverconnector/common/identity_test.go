@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/version"
+)
+
+func TestBuildIdentityMetadata(t *testing.T) {
+	cfg := &config.ClientConfigImpl{}
+	cfg.SetDefault()
+	cfg.SetId("client-1")
+	cfg.SetLabels(map[string]string{"Team Name": "支付"})
+	md := BuildIdentityMetadata(cfg)
+	if got := md.Get(HeaderSDKVersion); len(got) != 1 || got[0] != version.Version {
+		t.Fatalf("unexpected sdk version %v", got)
+	}
+	if got := md.Get(HeaderClientID); len(got) != 1 || got[0] != "client-1" {
+		t.Fatalf("unexpected client id %v", got)
+	}
+	if len(md.Get(HeaderClientPID)) != 1 {
+		t.Fatalf("pid is absent")
+	}
+	got := md.Get(HeaderClientLabelPrefix + "team-name")
+	if len(got) != 1 || got[0] != "%E6%94%AF%E4%BB%98" {
+		t.Fatalf("unexpected label %v", got)
+	}
+	if len(IdentityDialOptions(md)) != 2 {
+		t.Fatalf("interceptors are absent")
+	}
+
+	cfg.SetIdentityMetadataEnable(false)
+	if md := BuildIdentityMetadata(cfg); md != nil {
+		t.Fatalf("metadata should be nil when disabled, got %v", md)
+	}
+	if opts := IdentityDialOptions(nil); opts != nil {
+		t.Fatalf("dial options should be nil without metadata")
+	}
+}
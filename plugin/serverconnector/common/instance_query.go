@@ -0,0 +1,57 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// QueryInstances 使用独立的stream直接向服务端查询服务实例，不订阅服务，也不写入本地缓存
+func (g *DiscoverConnector) QueryInstances(req *model.QueryInstancesRequest) (model.ServiceInstances, error) {
+	task := &serviceUpdateTask{targetCluster: config.DiscoverCluster}
+	task.Namespace = req.Namespace
+	task.Service = req.Service
+	task.Type = model.EventInstances
+	request := &apiservice.DiscoverRequest{
+		Type: pb.GetProtoRequestType(model.EventInstances),
+		Service: &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: req.Service},
+			Namespace: &wrappers.StringValue{Value: req.Namespace},
+		},
+		Filter: &apiservice.DiscoverFilter{OnlyHealthyInstance: req.HealthyOnly},
+	}
+	resp, connection, err := g.discoverOnce(task, request)
+	if err != nil {
+		return nil, err
+	}
+	retCode := resp.GetCode().GetValue()
+	if !model.IsSuccessResultCode(retCode) {
+		errInfo := resp.GetInfo().GetValue()
+		return nil, model.NewServerSDKError(retCode, errInfo, nil,
+			"server error from %s: %s", connection.ConnID.Address, errInfo)
+	}
+	return pb.NewServiceInstancesInProto(resp, func(string) local.InstanceLocalValue {
+		return local.NewInstanceLocalValue()
+	}, nil, nil), nil
+}
@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// doAudit 定期抽样已订阅的资源，使用独立的stream与服务端核对缓存版本
+func (g *DiscoverConnector) doAudit() {
+	ticker := time.NewTicker(g.revisionAudit.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.Done():
+			log.GetBaseLogger().Infof("%s, doAudit of discover connector has been terminated",
+				g.ServiceConnector.GetSDKContextID())
+			return
+		case <-ticker.C:
+			for _, task := range g.sampleAuditTasks(g.revisionAudit.GetSampleSize()) {
+				if err := g.auditTask(task); err != nil {
+					log.GetBaseLogger().Warnf("[RevisionAudit] fail to audit %s, err %v", task, err)
+				}
+			}
+		}
+	}
+}
+
+// sampleAuditTasks 从长稳运行的用户资源更新任务中随机抽样，系统服务不参与巡检
+func (g *DiscoverConnector) sampleAuditTasks(size int) []*serviceUpdateTask {
+	samples := make([]*serviceUpdateTask, 0, size)
+	seen := 0
+	g.updateTaskSet.Range(func(k, v interface{}) bool {
+		task := v.(*serviceUpdateTask)
+		if atomic.LoadUint32(&task.longRun) != longRunning || task.targetCluster == config.BuiltinCluster {
+			return true
+		}
+		seen++
+		if len(samples) < size {
+			samples = append(samples, task)
+			return true
+		}
+		// 蓄水池抽样，保证每个任务被抽中的概率相同
+		mu.Lock()
+		idx := g.scalableRand.Intn(seen)
+		mu.Unlock()
+		if idx < size {
+			samples[idx] = task
+		}
+		return true
+	})
+	return samples
+}
+
+// auditTask 携带本地缓存版本向服务端查询，服务端返回了不同版本的数据时判定为缓存不一致，上报指标并强制刷新缓存
+func (g *DiscoverConnector) auditTask(task *serviceUpdateTask) error {
+	localRevision := task.handler.GetRevision()
+	if len(localRevision) == 0 {
+		// 缓存尚未加载
+		return nil
+	}
+	request := &apiservice.DiscoverRequest{
+		Type: pb.GetProtoRequestType(task.Type),
+		Service: &apiservice.Service{
+			Name:      &wrappers.StringValue{Value: task.Service},
+			Namespace: &wrappers.StringValue{Value: task.Namespace},
+			Revision:  &wrappers.StringValue{Value: localRevision},
+			Business:  &wrappers.StringValue{Value: task.handler.GetBusiness()},
+		},
+	}
+	resp, connection, err := g.discoverOnce(task, request)
+	if err != nil {
+		return err
+	}
+	retCode := resp.GetCode().GetValue()
+	if retCode == uint32(apimodel.Code_DataNoChange) || !model.IsSuccessResultCode(retCode) {
+		return nil
+	}
+	serverRevision := resp.GetService().GetRevision().GetValue()
+	if serverRevision == localRevision || serverRevision == task.handler.GetRevision() {
+		// 巡检期间缓存已经通过正常的更新流程追平
+		return nil
+	}
+	log.GetBaseLogger().Warnf("[RevisionAudit] cache of %s diverged, local revision %s, server revision %s, "+
+		"force refresh", task, localRevision, serverRevision)
+	g.reportDivergence(task, localRevision, serverRevision)
+	svcEvent, _ := discoverResponseToEvent(resp, task.ServiceEventKey, connection)
+	task.handler.OnServiceUpdate(svcEvent)
+	return nil
+}
+
+// reportDivergence 上报缓存不一致指标
+func (g *DiscoverConnector) reportDivergence(task *serviceUpdateTask, localRevision string, serverRevision string) {
+	if g.valueCtx == nil {
+		return
+	}
+	engineValue, ok := g.valueCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	_ = engineValue.(model.Engine).SyncReportStat(model.CacheDivergenceStat, &model.CacheDivergenceGauge{
+		Namespace:      task.Namespace,
+		Service:        task.Service,
+		EventType:      task.Type.String(),
+		LocalRevision:  localRevision,
+		ServerRevision: serverRevision,
+	})
+}
@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestSampleAuditTasks 测试巡检只抽样长稳运行的用户资源
+func TestSampleAuditTasks(t *testing.T) {
+	g := &DiscoverConnector{updateTaskSet: &sync.Map{}, scalableRand: rand.NewScalableRand()}
+	addTask := func(name string, longRun uint32, cluster config.ClusterType) {
+		task := &serviceUpdateTask{longRun: longRun, targetCluster: cluster}
+		task.ServiceEventKey = model.ServiceEventKey{
+			ServiceKey: model.ServiceKey{Namespace: "Test", Service: name}, Type: model.EventInstances}
+		g.updateTaskSet.Store(task.ServiceEventKey, task)
+	}
+	for i := 0; i < 20; i++ {
+		addTask(fmt.Sprintf("svc-%d", i), longRunning, config.DiscoverCluster)
+	}
+	addTask("first", firstTask, config.DiscoverCluster)
+	addTask("polaris.discover", longRunning, config.BuiltinCluster)
+
+	samples := g.sampleAuditTasks(5)
+	if len(samples) != 5 {
+		t.Fatalf("expect 5 samples, got %d", len(samples))
+	}
+	seen := make(map[string]bool)
+	for _, task := range samples {
+		if task.Service == "first" || task.Service == "polaris.discover" {
+			t.Fatalf("unexpected sampled task %s", task)
+		}
+		if seen[task.Service] {
+			t.Fatalf("duplicate sampled task %s", task)
+		}
+		seen[task.Service] = true
+	}
+	if len(g.sampleAuditTasks(100)) != 20 {
+		t.Fatal("expect all long running user tasks sampled")
+	}
+}
@@ -0,0 +1,173 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// 单个UDP心跳应答的最大长度
+const maxUDPHeartbeatAckSize = 1024
+
+// udpHeartbeatPacket UDP心跳报文，JSON编码
+type udpHeartbeatPacket struct {
+	ID           string `json:"id"`
+	Token        string `json:"token,omitempty"`
+	ServiceToken string `json:"serviceToken,omitempty"`
+	Namespace    string `json:"namespace"`
+	Service      string `json:"service"`
+	InstanceID   string `json:"instanceId,omitempty"`
+	Host         string `json:"host"`
+	Port         int    `json:"port"`
+}
+
+// udpHeartbeatAck UDP心跳应答报文，JSON编码
+type udpHeartbeatAck struct {
+	ID   string `json:"id"`
+	Code uint32 `json:"code"`
+	Info string `json:"info"`
+}
+
+// UDPHeartbeater 基于UDP的轻量心跳上报，每次心跳需要服务端回复应答，
+// 连续丢包达到上限后在一段时间内回退到GRPC，之后再重新尝试UDP
+type UDPHeartbeater struct {
+	cfg   config.HeartbeatConfig
+	mutex sync.Mutex
+	// 当前使用的服务端地址下标，丢包时切换到下一个地址
+	addrIndex int
+	// 连续丢包次数
+	losses int
+	// 回退到GRPC的截止时间
+	fallbackUntil time.Time
+}
+
+// NewUDPHeartbeater 创建UDP心跳上报器，心跳协议不是udp时返回nil
+func NewUDPHeartbeater(cfg config.HeartbeatConfig) *UDPHeartbeater {
+	if cfg == nil || cfg.GetProtocol() != config.HeartbeatProtocolUDP {
+		return nil
+	}
+	return &UDPHeartbeater{cfg: cfg}
+}
+
+// Available 当前是否可以使用UDP上报心跳
+func (u *UDPHeartbeater) Available() bool {
+	if u == nil {
+		return false
+	}
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return !clock.GetClock().Now().Before(u.fallbackUntil)
+}
+
+// Heartbeat 通过UDP上报心跳，handled为false表示报文丢失，调用方需要通过GRPC重新上报
+func (u *UDPHeartbeater) Heartbeat(req *model.InstanceHeartbeatRequest, token string) (handled bool, err error) {
+	reqID := NextHeartbeatReqID()
+	buf, err := json.Marshal(&udpHeartbeatPacket{
+		ID:           reqID,
+		Token:        token,
+		ServiceToken: req.ServiceToken,
+		Namespace:    req.Namespace,
+		Service:      req.Service,
+		InstanceID:   req.InstanceID,
+		Host:         req.Host,
+		Port:         req.Port,
+	})
+	if err != nil {
+		return false, err
+	}
+	address := u.currentAddress()
+	ack, err := u.sendAndWait(address, reqID, buf)
+	if err != nil {
+		u.onLoss(address, err)
+		return false, err
+	}
+	u.onAck()
+	if ack.Code != uint32(apimodel.Code_ExecuteSuccess) {
+		return true, model.NewSDKErrorWithServerInfo(model.ErrCodeServerUserError, nil, ack.Code, ack.Info,
+			"fail to heartbeat by udp, request %s, server error code is %d, error is %s, server %s",
+			*req, ack.Code, ack.Info, address)
+	}
+	return true, nil
+}
+
+// sendAndWait 发送心跳报文并等待对应的应答，超时视为丢包
+func (u *UDPHeartbeater) sendAndWait(address string, reqID string, buf []byte) (*udpHeartbeatAck, error) {
+	conn, err := net.DialTimeout("udp", address, u.cfg.GetUDPAckTimeout())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(u.cfg.GetUDPAckTimeout())); err != nil {
+		return nil, err
+	}
+	if _, err = conn.Write(buf); err != nil {
+		return nil, err
+	}
+	ackBuf := make([]byte, maxUDPHeartbeatAckSize)
+	for {
+		n, err := conn.Read(ackBuf)
+		if err != nil {
+			return nil, err
+		}
+		ack := &udpHeartbeatAck{}
+		if err = json.Unmarshal(ackBuf[:n], ack); err != nil || ack.ID != reqID {
+			// 忽略无法解析或者属于之前请求的迟到应答
+			continue
+		}
+		return ack, nil
+	}
+}
+
+func (u *UDPHeartbeater) currentAddress() string {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	addresses := u.cfg.GetUDPAddresses()
+	return addresses[u.addrIndex%len(addresses)]
+}
+
+func (u *UDPHeartbeater) onAck() {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.losses = 0
+}
+
+// onLoss 记录丢包，切换服务端地址，连续丢包达到上限后回退到GRPC
+func (u *UDPHeartbeater) onLoss(address string, err error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.losses++
+	u.addrIndex++
+	if u.losses < u.cfg.GetUDPMaxLoss() {
+		log.GetBaseLogger().Warnf("[Heartbeat] udp heartbeat to %s lost, losses %d, err %v", address, u.losses, err)
+		return
+	}
+	u.losses = 0
+	u.fallbackUntil = clock.GetClock().Now().Add(u.cfg.GetUDPFallbackInterval())
+	log.GetBaseLogger().Warnf("[Heartbeat] udp heartbeat lost %d times continuously, fallback to grpc until %v",
+		u.cfg.GetUDPMaxLoss(), u.fallbackUntil)
+}
@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// startAckServer 启动应答UDP心跳的服务端，ack为false时只接收不应答
+func startAckServer(t *testing.T, ack bool) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if !ack {
+				continue
+			}
+			packet := &udpHeartbeatPacket{}
+			if err = json.Unmarshal(buf[:n], packet); err != nil {
+				continue
+			}
+			resp, _ := json.Marshal(&udpHeartbeatAck{ID: packet.ID, Code: uint32(apimodel.Code_ExecuteSuccess)})
+			_, _ = conn.WriteToUDP(resp, addr)
+		}
+	}()
+	return conn
+}
+
+// TestUDPHeartbeater 测试UDP心跳的应答及丢包回退
+func TestUDPHeartbeater(t *testing.T) {
+	server := startAckServer(t, true)
+	defer server.Close()
+	silent := startAckServer(t, false)
+	defer silent.Close()
+
+	cfg := &config.HeartbeatConfigImpl{Protocol: config.HeartbeatProtocolUDP,
+		UDPAddresses: []string{server.LocalAddr().String()}, UDPAckTimeout: 100 * time.Millisecond}
+	cfg.SetDefault()
+	req := &model.InstanceHeartbeatRequest{Namespace: "Test", Service: "svc", Host: "127.0.0.1", Port: 8080}
+	heartbeater := NewUDPHeartbeater(cfg)
+	if handled, err := heartbeater.Heartbeat(req, ""); !handled || err != nil {
+		t.Fatalf("expect heartbeat acked, handled %v, err %v", handled, err)
+	}
+
+	cfg.SetUDPAddresses([]string{silent.LocalAddr().String()})
+	for i := 0; i < cfg.GetUDPMaxLoss(); i++ {
+		if !heartbeater.Available() {
+			t.Fatalf("expect udp available before %d losses", cfg.GetUDPMaxLoss())
+		}
+		if handled, _ := heartbeater.Heartbeat(req, ""); handled {
+			t.Fatal("expect heartbeat lost")
+		}
+	}
+	if heartbeater.Available() {
+		t.Fatal("expect fallback to grpc after continuous losses")
+	}
+	if NewUDPHeartbeater(&config.HeartbeatConfigImpl{Protocol: config.HeartbeatProtocolGRPC}).Available() {
+		t.Fatal("expect udp heartbeat disabled for grpc protocol")
+	}
+}
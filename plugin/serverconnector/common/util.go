@@ -52,6 +52,7 @@ const (
 	reqIDPrefixCreateConfigFile
 	reqIDPrefixUpdateConfigFile
 	reqIDPrefixPublishConfigFile
+	reqIDPrefixGetServiceContract
 )
 
 const (
@@ -70,6 +71,7 @@ const (
 	OpKeyUpdateConfigFile      = "UpdateConfigFile"
 	OpKeyPublishConfigFile     = "PublishConfigFile"
 	OpKeyGetConfigGroup        = "GetConfigGroup"
+	OpKeyGetServiceContract    = "GetServiceContract"
 )
 
 // NextDiscoverReqID 生成GetInstances调用的请求Id
@@ -132,6 +134,11 @@ func NextPublishConfigFileReqID() string {
 	return fmt.Sprintf("%d%d", reqIDPrefixPublishConfigFile, uuid.New().ID())
 }
 
+// NextGetServiceContractReqID 生成GetServiceContract调用的请求Id
+func NextGetServiceContractReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixGetServiceContract, uuid.New().ID())
+}
+
 // GetConnErrorCode 获取连接错误码
 func GetConnErrorCode(err error) int32 {
 	code, ok := status.FromError(err)
@@ -175,6 +182,12 @@ func GetUpdateTaskRequestTime(updateTask *serviceUpdateTask) time.Duration {
 // }
 
 func CreateHeadersContext(timeout time.Duration, options ...func(map[string]string)) (context.Context, context.CancelFunc) {
+	return CreateHeadersContextWithParent(context.Background(), timeout, options...)
+}
+
+// CreateHeadersContextWithParent 基于调用方上下文创建携带请求头的上下文，调用方上下文取消时请求随之取消
+func CreateHeadersContextWithParent(parent context.Context, timeout time.Duration,
+	options ...func(map[string]string)) (context.Context, context.CancelFunc) {
 	headers := map[string]string{}
 	for _, option := range options {
 		option(headers)
@@ -184,9 +197,9 @@ func CreateHeadersContext(timeout time.Duration, options ...func(map[string]stri
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(parent, timeout)
 	} else {
-		ctx = context.Background()
+		ctx = parent
 		cancel = nil
 	}
 	return metadata.NewOutgoingContext(ctx, md), cancel
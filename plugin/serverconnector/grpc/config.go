@@ -21,6 +21,9 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
+	"google.golang.org/grpc/encoding"
+	// 注册gzip压缩器
+	_ "google.golang.org/grpc/encoding/gzip"
 )
 
 const (
@@ -33,6 +36,27 @@ const (
 // GRPC插件级别配置
 type networkConfig struct {
 	MaxCallRecvMsgSize int `yaml:"maxCallRecvMsgSize"`
+	// Compression 请求压缩算法，为空则不压缩，server会使用相同的算法压缩应答
+	// 内置支持gzip，其他算法（如zstd）需要先通过encoding.RegisterCompressor注册
+	Compression string `yaml:"compression"`
+	// CompressionCalls 启用压缩的调用，取值为Discover、RegisterInstance等操作名，为空则全部调用都启用压缩
+	CompressionCalls []string `yaml:"compressionCalls"`
+}
+
+// compressEnabled 指定操作的调用是否启用压缩
+func (r *networkConfig) compressEnabled(opKey string) bool {
+	if len(r.Compression) == 0 {
+		return false
+	}
+	if len(r.CompressionCalls) == 0 {
+		return true
+	}
+	for _, call := range r.CompressionCalls {
+		if call == opKey {
+			return true
+		}
+	}
+	return false
 }
 
 // Verify 校验GRPC配置值
@@ -41,6 +65,9 @@ func (r *networkConfig) Verify() error {
 	if r.MaxCallRecvMsgSize <= 0 || r.MaxCallRecvMsgSize > MaxMaxCallRecvMsgSize {
 		errs = multierror.Append(errs, fmt.Errorf("grpc.maxCallRecvMsgSize must be int (0, 524288000]"))
 	}
+	if len(r.Compression) > 0 && encoding.GetCompressor(r.Compression) == nil {
+		errs = multierror.Append(errs, fmt.Errorf("grpc.compression %s is not registered", r.Compression))
+	}
 	return errs
 }
 
@@ -20,6 +20,7 @@ package grpc
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -29,6 +30,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/network"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
 )
 
 // CreateConnection 创建连接
@@ -38,11 +40,14 @@ func (g *Connector) CreateConnection(
 	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	opts = append(opts, grpc.WithBlock())
 	localIPValue := clientInfo.GetIPString()
-	if len(localIPValue) == 0 {
-		opts = append(opts, grpc.WithStatsHandler(&statHandler{clientInfo: clientInfo}))
-	}
+	opts = append(opts, grpc.WithStatsHandler(&statHandler{
+		clientInfo: clientInfo,
+		storeIP:    len(localIPValue) == 0,
+		valueCtx:   g.valueCtx,
+	}))
 	log.GetBaseLogger().Debugf("create connection with maxCallRecvSize %d", g.cfg.MaxCallRecvMsgSize)
 	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(g.cfg.MaxCallRecvMsgSize)))
+	opts = append(opts, connector.IdentityDialOptions(g.identity)...)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	conn, err := grpc.DialContext(ctx, address, opts...)
@@ -52,22 +57,108 @@ func (g *Connector) CreateConnection(
 	return conn, nil
 }
 
+// callOptions 获取指定操作的调用选项
+func (g *Connector) callOptions(opKey string) []grpc.CallOption {
+	if !g.cfg.compressEnabled(opKey) {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(g.cfg.Compression)}
+}
+
+// 长连接的流在调用结束前按该间隔上报累计的流量
+const trafficReportInterval = 30 * time.Second
+
+type rpcTrafficKey struct{}
+
+// rpcTraffic 单次调用累计的流量，调用结束或者流式调用超过上报间隔时合并上报
+type rpcTraffic struct {
+	mutex      sync.Mutex
+	method     string
+	lastReport time.Time
+	// 下标为流量方向，0为入流量，1为出流量
+	compression [2]string
+	rawBytes    [2]int
+	wireBytes   [2]int
+}
+
+var trafficDirections = [2]string{model.TrafficInbound, model.TrafficOutbound}
+
+// collect 累计流量，需要上报时返回待上报的数据并清零
+func (t *rpcTraffic) collect(rpcStats stats.RPCStats, now time.Time) []*model.ServerTrafficGauge {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	switch value := rpcStats.(type) {
+	case *stats.InHeader:
+		// 应答使用的压缩算法，由服务端决定
+		t.compression[0] = value.Compression
+	case *stats.OutHeader:
+		// 本次调用实际使用的压缩算法，未通过 callOptions 开启压缩的调用为空
+		t.compression[1] = value.Compression
+	case *stats.InPayload:
+		t.rawBytes[0] += value.Length
+		t.wireBytes[0] += value.WireLength
+	case *stats.OutPayload:
+		t.rawBytes[1] += value.Length
+		t.wireBytes[1] += value.WireLength
+	case *stats.End:
+		return t.drain(now)
+	default:
+		return nil
+	}
+	if now.Sub(t.lastReport) < trafficReportInterval {
+		return nil
+	}
+	return t.drain(now)
+}
+
+func (t *rpcTraffic) drain(now time.Time) []*model.ServerTrafficGauge {
+	t.lastReport = now
+	var gauges []*model.ServerTrafficGauge
+	for i, direction := range trafficDirections {
+		if t.rawBytes[i] == 0 && t.wireBytes[i] == 0 {
+			continue
+		}
+		gauges = append(gauges, &model.ServerTrafficGauge{Method: t.method, Direction: direction,
+			Compression: t.compression[i], RawBytes: t.rawBytes[i], WireBytes: t.wireBytes[i]})
+		t.rawBytes[i], t.wireBytes[i] = 0, 0
+	}
+	return gauges
+}
+
 // Handler defines the interface for the related stats handling (e.g., RPCs, connections).
 type statHandler struct {
 	// 全局上下文
 	clientInfo *network.ClientInfo
+	// 是否需要从连接中获取本地IP
+	storeIP bool
+	// 全局上下文，用于获取流程引擎上报流量统计
+	valueCtx model.ValueContext
 }
 
 // TagRPC can attach some information to the given context.
 // The context used for the rest lifetime of the RPC will be derived from
 // the returned context.
 func (s *statHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
-	return ctx
+	return context.WithValue(ctx, rpcTrafficKey{}, &rpcTraffic{method: info.FullMethodName, lastReport: time.Now()})
 }
 
 // HandleRPC processes the RPC stats.
-func (s *statHandler) HandleRPC(context.Context, stats.RPCStats) {
-
+func (s *statHandler) HandleRPC(ctx context.Context, rpcStats stats.RPCStats) {
+	traffic, ok := ctx.Value(rpcTrafficKey{}).(*rpcTraffic)
+	if !ok {
+		return
+	}
+	gauges := traffic.collect(rpcStats, time.Now())
+	if len(gauges) == 0 || s.valueCtx == nil {
+		return
+	}
+	engineValue, ok := s.valueCtx.GetValue(model.ContextKeyEngine)
+	if !ok {
+		return
+	}
+	for _, gauge := range gauges {
+		_ = engineValue.(model.Engine).SyncReportStat(model.ServerTrafficStat, gauge)
+	}
 }
 
 // TagConn can attach some information to the given context.
@@ -78,6 +169,9 @@ func (s *statHandler) HandleRPC(context.Context, stats.RPCStats) {
 // HandleRPC for all RPCs on this connection will be derived from the context returned.
 // On client side, the context is not derived from the context returned.
 func (s *statHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	if !s.storeIP {
+		return ctx
+	}
 	localAddr := info.LocalAddr.String()
 	localIP := strings.Split(localAddr, ":")[0]
 	hashValue, _ := model.HashStr(localIP)
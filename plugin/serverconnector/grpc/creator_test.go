@@ -0,0 +1,95 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/stats"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+type trafficEngine struct {
+	model.Engine
+	gauges []*model.ServerTrafficGauge
+}
+
+func (e *trafficEngine) SyncReportStat(typ model.MetricType, stat model.InstanceGauge) error {
+	e.gauges = append(e.gauges, stat.(*model.ServerTrafficGauge))
+	return nil
+}
+
+// TestStatHandlerTraffic 测试按调用实际使用的压缩算法打标签，并在调用结束时合并上报
+func TestStatHandlerTraffic(t *testing.T) {
+	engine := &trafficEngine{}
+	valueCtx := model.NewValueContext()
+	valueCtx.SetValue(model.ContextKeyEngine, engine)
+	handler := &statHandler{valueCtx: valueCtx}
+
+	// 开启压缩的调用
+	ctx := handler.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/v1.PolarisGRPC/RegisterInstance"})
+	handler.HandleRPC(ctx, &stats.OutHeader{Client: true, Compression: "gzip"})
+	handler.HandleRPC(ctx, &stats.OutPayload{Client: true, Length: 100, WireLength: 40})
+	handler.HandleRPC(ctx, &stats.InHeader{Client: true})
+	handler.HandleRPC(ctx, &stats.InPayload{Client: true, Length: 30, WireLength: 35})
+	handler.HandleRPC(ctx, &stats.InPayload{Client: true, Length: 20, WireLength: 25})
+	if len(engine.gauges) != 0 {
+		t.Fatalf("expect traffic reported when rpc ends, got %d gauges", len(engine.gauges))
+	}
+	handler.HandleRPC(ctx, &stats.End{Client: true})
+	if len(engine.gauges) != 2 {
+		t.Fatalf("expect one gauge per direction, got %d", len(engine.gauges))
+	}
+	in, out := engine.gauges[0], engine.gauges[1]
+	if in.Direction != model.TrafficInbound || in.Compression != "" || in.RawBytes != 50 || in.WireBytes != 60 {
+		t.Fatalf("unexpected inbound gauge %+v", in)
+	}
+	if out.Direction != model.TrafficOutbound || out.Compression != "gzip" || out.RawBytes != 100 ||
+		out.WireBytes != 40 || out.Method != "/v1.PolarisGRPC/RegisterInstance" {
+		t.Fatalf("unexpected outbound gauge %+v", out)
+	}
+
+	// 未开启压缩的调用不使用配置的压缩算法作为标签
+	engine.gauges = nil
+	ctx = handler.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/v1.PolarisGRPC/Heartbeat"})
+	handler.HandleRPC(ctx, &stats.OutHeader{Client: true})
+	handler.HandleRPC(ctx, &stats.OutPayload{Client: true, Length: 10, WireLength: 15})
+	handler.HandleRPC(ctx, &stats.End{Client: true})
+	if len(engine.gauges) != 1 || engine.gauges[0].Compression != "" {
+		t.Fatalf("expect uncompressed outbound gauge, got %+v", engine.gauges)
+	}
+}
+
+// TestRPCTrafficStreamReport 测试流式调用超过上报间隔时上报累计的流量
+func TestRPCTrafficStreamReport(t *testing.T) {
+	start := time.Now()
+	traffic := &rpcTraffic{method: "/v1.PolarisGRPC/Discover", lastReport: start}
+	if gauges := traffic.collect(&stats.InPayload{Length: 10, WireLength: 10}, start.Add(time.Second)); gauges != nil {
+		t.Fatal("expect traffic accumulated within report interval")
+	}
+	gauges := traffic.collect(&stats.InPayload{Length: 5, WireLength: 5}, start.Add(trafficReportInterval))
+	if len(gauges) != 1 || gauges[0].RawBytes != 15 {
+		t.Fatalf("expect accumulated traffic reported, got %+v", gauges)
+	}
+	if gauges = traffic.collect(&stats.End{}, start.Add(trafficReportInterval+time.Second)); gauges != nil {
+		t.Fatalf("expect nothing left to report, got %+v", gauges)
+	}
+}
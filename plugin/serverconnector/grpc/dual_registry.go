@@ -0,0 +1,118 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+// registryTarget 注册操作的目标集群
+type registryTarget struct {
+	connManager network.ConnectionManager
+	token       string
+	// 是否为双注册配置的旧集群
+	legacy bool
+}
+
+// dualRegistry 双注册状态，注册、心跳及反注册同时发往主备两个集群
+type dualRegistry struct {
+	primary   *registryTarget
+	secondary *registryTarget
+	// 进行中的备集群调用，销毁时最多等待 closeTimeout 后释放连接
+	inflight     sync.WaitGroup
+	closeTimeout time.Duration
+}
+
+// initDualRegistry 根据双注册配置创建旧集群的连接管理器
+func (g *Connector) initDualRegistry(ctx *plugin.InitContext) error {
+	dualCfg := ctx.Config.GetProvider().GetDualRegistration()
+	if !dualCfg.IsEnable() {
+		return nil
+	}
+	legacyManager, err := network.NewRegistryConnectionManager(ctx.Config, ctx.ValueCtx, dualCfg.GetAddresses())
+	if err != nil {
+		return err
+	}
+	legacyManager.SetConnCreator(g)
+	token := dualCfg.GetToken()
+	if len(token) == 0 {
+		token = g.token
+	}
+	current := &registryTarget{connManager: g.connManager, token: g.token}
+	legacy := &registryTarget{connManager: legacyManager, token: token, legacy: true}
+	g.dualRegistry = &dualRegistry{primary: current, secondary: legacy,
+		closeTimeout: ctx.Config.GetGlobal().GetAPI().GetTimeout()}
+	if dualCfg.IsReverse() {
+		g.dualRegistry.primary, g.dualRegistry.secondary = legacy, current
+	}
+	log.GetBaseLogger().Infof("%s, dual registration enabled, legacy addresses %v, reverse %v",
+		g.GetSDKContextID(), dualCfg.GetAddresses(), dualCfg.IsReverse())
+	return nil
+}
+
+// registryTargets 获取注册操作的主备集群，未启用双注册时备集群为nil
+func (g *Connector) registryTargets() (*registryTarget, *registryTarget) {
+	if nil == g.dualRegistry {
+		return &registryTarget{connManager: g.connManager, token: g.token}, nil
+	}
+	return g.dualRegistry.primary, g.dualRegistry.secondary
+}
+
+// callSecondary 异步向备集群发起调用，不等待调用结束，
+// 备集群变慢或者失败都不影响主集群的调用耗时及结果，失败只记录日志
+func (g *Connector) callSecondary(opKey string, target *registryTarget, call func(target *registryTarget) error) {
+	if nil == target {
+		return
+	}
+	g.dualRegistry.inflight.Add(1)
+	go func() {
+		defer g.dualRegistry.inflight.Done()
+		if err := call(target); err != nil {
+			log.GetBaseLogger().Warnf("%s, dual registration: fail to %s on secondary cluster (legacy %v), err %v",
+				g.GetSDKContextID(), opKey, target.legacy, err)
+		}
+	}()
+}
+
+// destroyDualRegistry 等待进行中的备集群调用结束后释放旧集群的连接管理器，需要在主连接管理器销毁前调用
+func (g *Connector) destroyDualRegistry() {
+	if nil == g.dualRegistry {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		g.dualRegistry.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(g.dualRegistry.closeTimeout):
+		log.GetBaseLogger().Warnf("%s, dual registration: secondary calls not finished in %v",
+			g.GetSDKContextID(), g.dualRegistry.closeTimeout)
+	}
+	for _, target := range []*registryTarget{g.dualRegistry.primary, g.dualRegistry.secondary} {
+		if target.legacy {
+			target.connManager.Destroy()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+)
+
+func TestDualRegistry(t *testing.T) {
+	newConnector := func(reverse bool) *Connector {
+		cfg := config.NewDefaultConfiguration([]string{"127.0.0.1:8091"})
+		dualCfg := cfg.GetProvider().GetDualRegistration()
+		dualCfg.SetEnable(true)
+		dualCfg.SetAddresses([]string{"127.0.0.1:18091"})
+		dualCfg.SetToken("legacy-token")
+		dualCfg.SetReverse(reverse)
+		if err := cfg.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		ctx := &plugin.InitContext{Config: cfg, ValueCtx: model.NewValueContext()}
+		g := &Connector{PluginBase: plugin.NewPluginBase(ctx), token: "current-token"}
+		if err := g.initDualRegistry(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	g := newConnector(false)
+	primary, secondary := g.registryTargets()
+	if primary.legacy || primary.token != "current-token" {
+		t.Fatalf("unexpected primary %+v", primary)
+	}
+	if secondary == nil || !secondary.legacy || secondary.token != "legacy-token" {
+		t.Fatalf("unexpected secondary %+v", secondary)
+	}
+
+	reversed := newConnector(true)
+	defer reversed.destroyDualRegistry()
+	primary, secondary = reversed.registryTargets()
+	if !primary.legacy || secondary.legacy {
+		t.Fatalf("roles should be reversed, primary %+v, secondary %+v", primary, secondary)
+	}
+
+	// 备集群的调用不阻塞调用方，销毁时等待进行中的备集群调用结束
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	g.callSecondary("test", secondary, func(target *registryTarget) error {
+		<-release
+		close(finished)
+		return errors.New("legacy unavailable")
+	})
+	g.callSecondary("test", nil, func(target *registryTarget) error {
+		t.Fatal("nil secondary should not be called")
+		return nil
+	})
+	close(release)
+	g.destroyDualRegistry()
+	select {
+	case <-finished:
+	default:
+		t.Fatal("secondary call should be finished before destroy returns")
+	}
+}
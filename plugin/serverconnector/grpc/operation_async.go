@@ -22,6 +22,7 @@ import (
 	"time"
 
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -53,6 +54,12 @@ type Connector struct {
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 	token           string
+	// UDP心跳上报器，未启用UDP心跳时为nil
+	udpHeartbeater *connector.UDPHeartbeater
+	// 双注册状态，未启用双注册时为nil
+	dualRegistry *dualRegistry
+	// 每次调用附加的客户端身份信息，未启用时为nil
+	identity metadata.MD
 }
 
 // Type 插件类型
@@ -74,6 +81,8 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 		g.cfg = cfgValue.(*networkConfig)
 	}
 	g.token = ctx.Config.GetGlobal().GetServerConnector().GetToken()
+	g.identity = connector.BuildIdentityMetadata(ctx.Config.GetGlobal().GetClient())
+	g.udpHeartbeater = connector.NewUDPHeartbeater(ctx.Config.GetProvider().GetHeartbeat())
 	g.connManager = ctx.ConnManager
 	g.connectionIdleTimeout = ctx.Config.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	g.valueCtx = ctx.ValueCtx
@@ -82,6 +91,9 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 		log.GetBaseLogger().Infof("set %s plugin as connectionCreator", g.Name())
 		g.connManager.SetConnCreator(g)
 	}
+	if err := g.initDualRegistry(ctx); err != nil {
+		return err
+	}
 	g.discoverConnector = &connector.DiscoverConnector{}
 	g.discoverConnector.ServiceConnector = g.PluginBase
 	g.discoverConnector.Init(ctx, g.createDiscoverClient)
@@ -107,7 +119,7 @@ func (g *Connector) createDiscoverClient(args *connector.DiscoverClientCreatorAr
 		connector.AppendAuthHeader(args.AuthToken),
 		connector.AppendHeaderWithReqId(args.ReqId))
 
-	discoverClient, err := client.Discover(outgoingCtx)
+	discoverClient, err := client.Discover(outgoingCtx, g.callOptions(connector.OpKeyDiscover)...)
 	return discoverClient, cancel, err
 }
 
@@ -115,6 +127,7 @@ func (g *Connector) createDiscoverClient(args *connector.DiscoverClientCreatorAr
 func (g *Connector) Destroy() error {
 	_ = g.RunContext.Destroy()
 	_ = g.discoverConnector.Destroy()
+	g.destroyDualRegistry()
 	g.connManager.Destroy()
 	return nil
 }
@@ -142,6 +155,15 @@ func (g *Connector) UpdateServers(key *model.ServiceEventKey) error {
 	return g.discoverConnector.UpdateServers(key)
 }
 
+// QueryInstances 直接向服务端查询服务实例，不订阅服务，也不写入本地缓存
+// 异常场景：当服务端不可用或者查询失败，则返回error
+func (g *Connector) QueryInstances(req *model.QueryInstancesRequest) (model.ServiceInstances, error) {
+	if err := g.waitDiscoverReady(); err != nil {
+		return nil, err
+	}
+	return g.discoverConnector.QueryInstances(req)
+}
+
 // init 注册插件信息
 func init() {
 	plugin.RegisterConfigurablePlugin(&Connector{}, &networkConfig{})
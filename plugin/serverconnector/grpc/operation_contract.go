@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/jsonpb"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+// GetServiceContract 查询被调服务上报的服务契约
+// 异常场景：当sdk已经退出过程中，则返回error
+// 异常场景：当服务端不可用或者查询失败，则返回error
+func (g *Connector) GetServiceContract(req *model.GetServiceContractRequest) (*model.ServiceContract, error) {
+	if err := g.waitDiscoverReady(); err != nil {
+		return nil, err
+	}
+	var (
+		opKey     = connector.OpKeyGetServiceContract
+		startTime = clock.GetClock().Now()
+		// 获取server连接
+		conn, err = g.connManager.GetConnection(opKey, config.DiscoverCluster)
+	)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeNetworkError, err, fmt.Sprintf("fail to get connection, opKey %s", opKey))
+	}
+	// 释放server连接
+	defer conn.Release(opKey)
+	var (
+		contractClient = apiservice.NewPolarisServiceContractGRPCClient(network.ToGRPCConn(conn.Conn))
+		reqID          = connector.NextGetServiceContractReqID()
+		ctx, cancel    = connector.CreateHeadersContext(req.Timeout,
+			connector.AppendAuthHeader(g.token),
+			connector.AppendHeaderWithReqId(reqID))
+	)
+	if cancel != nil {
+		defer cancel()
+	}
+	reqProto := &apiservice.ServiceContract{
+		Namespace: req.Namespace,
+		Service:   req.Service,
+		Protocol:  req.Protocol,
+		Version:   req.Version,
+	}
+	// 打印请求报文
+	if log.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
+		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
+	}
+	pbResp, err := contractClient.GetServiceContract(ctx, reqProto, g.callOptions(opKey)...)
+	endTime := g.valueCtx.Now()
+	if err != nil {
+		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
+			fmt.Sprintf("fail to send request, opKey %s, reqID %s, connID %s", opKey, reqID, conn.ConnID))
+	}
+	// 打印应答报文
+	if log.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		respJson, _ := (&jsonpb.Marshaler{}).MarshalToString(pbResp)
+		log.GetBaseLogger().Debugf("response recv is %s, opKey %s, connID %s", respJson, opKey, conn.ConnID)
+	}
+	serverCodeType := pb.ConvertServerErrorToRpcError(pbResp.GetCode().GetValue())
+	switch pbResp.GetCode().GetValue() {
+	case uint32(apimodel.Code_ExecuteSuccess):
+		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		return serviceContractFromProto(pbResp.GetServiceContract()), nil
+	case uint32(apimodel.Code_NotFoundResource), uint32(apimodel.Code_NotFoundService):
+		// 服务未上报契约
+		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		return nil, nil
+	}
+	errMsg := fmt.Sprintf("fail to getServiceContract, server error code is %d, error is %s, connID %s",
+		pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), conn.ConnID)
+	if serverCodeType == model.ErrCodeServerError {
+		// 当server发生内部错误时，上报调用服务失败
+		g.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
+		return nil, model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
+	}
+	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+	return nil, model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
+}
+
+// serviceContractFromProto 将服务端返回的契约转换为SDK的契约对象
+func serviceContractFromProto(contract *apiservice.ServiceContract) *model.ServiceContract {
+	if nil == contract {
+		return nil
+	}
+	result := &model.ServiceContract{
+		Namespace:  contract.GetNamespace(),
+		Service:    contract.GetService(),
+		Protocol:   contract.GetProtocol(),
+		Version:    contract.GetVersion(),
+		Revision:   contract.GetRevision(),
+		Status:     contract.GetStatus(),
+		Interfaces: make([]*model.ContractInterface, 0, len(contract.GetInterfaces())),
+	}
+	for _, itf := range contract.GetInterfaces() {
+		result.Interfaces = append(result.Interfaces, &model.ContractInterface{
+			Method: itf.GetMethod(),
+			Path:   itf.GetPath(),
+		})
+	}
+	return result
+}
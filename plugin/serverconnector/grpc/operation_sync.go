@@ -41,14 +41,25 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	if err := g.waitDiscoverReady(); err != nil {
 		return nil, err
 	}
+	primary, secondary := g.registryTargets()
+	g.callSecondary(connector.OpKeyRegisterInstance, secondary, func(target *registryTarget) error {
+		_, err := g.registerInstance(target, req, header)
+		return err
+	})
+	return g.registerInstance(primary, req, header)
+}
+
+// registerInstance 向指定集群注册服务
+func (g *Connector) registerInstance(target *registryTarget, req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
 	var (
 		opKey     = connector.OpKeyRegisterInstance
 		startTime = clock.GetClock().Now()
 		// 获取server连接
-		conn, err = g.connManager.GetConnection(opKey, config.DiscoverCluster)
+		conn, err = target.connManager.GetConnection(opKey, config.DiscoverCluster)
 	)
 	if err != nil {
-		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrCodeConnectError), err, startTime,
+		return nil, connector.NetworkError(target.connManager, conn, int32(model.ErrCodeConnectError), err, startTime,
 			fmt.Sprintf("fail to get connection, opKey %s", opKey))
 	}
 	// 释放server连接
@@ -56,8 +67,8 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.Context(), *req.Timeout,
+			connector.AppendAuthHeader(target.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 
@@ -70,10 +81,10 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.RegisterInstance(ctx, reqProto)
+	pbResp, err := namingClient.RegisterInstance(ctx, reqProto, g.callOptions(opKey)...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
-		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
+		return nil, connector.NetworkError(target.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to registerInstance, request %s, "+
 				"reason is fail to send request, reqID %s, server %s", *req, reqID, conn.ConnID))
 	}
@@ -91,13 +102,13 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 			*req, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), conn.ConnID)
 		if serverCodeType == model.ErrCodeServerError {
 			// 当server发生了内部错误时，上报调用服务失败
-			g.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
+			target.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
 			return nil, model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 		}
-		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 		return nil, model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
 	}
-	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+	target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	resp := &model.InstanceRegisterResponse{InstanceID: pbResp.GetInstance().GetId().GetValue(),
 		Existed: uint32(apimodel.Code_ExistedResource) == pbResp.GetCode().GetValue()}
 	return resp, nil
@@ -108,11 +119,20 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	if err := g.waitDiscoverReady(); err != nil {
 		return err
 	}
+	primary, secondary := g.registryTargets()
+	g.callSecondary(connector.OpKeyDeregisterInstance, secondary, func(target *registryTarget) error {
+		return g.deregisterInstance(target, req)
+	})
+	return g.deregisterInstance(primary, req)
+}
+
+// deregisterInstance 向指定集群反注册服务
+func (g *Connector) deregisterInstance(target *registryTarget, req *model.InstanceDeRegisterRequest) error {
 	var (
 		opKey     = connector.OpKeyDeregisterInstance
 		startTime = clock.GetClock().Now()
 		// 获取server连接
-		conn, err = g.connManager.GetConnection(opKey, config.DiscoverCluster)
+		conn, err = target.connManager.GetConnection(opKey, config.DiscoverCluster)
 	)
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeNetworkError, err, "fail to get connection, opKey %s", opKey)
@@ -122,8 +142,8 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextDeRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.Context(), *req.Timeout,
+			connector.AppendAuthHeader(target.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 	if cancel != nil {
@@ -135,10 +155,10 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.DeregisterInstance(ctx, reqProto)
+	pbResp, err := namingClient.DeregisterInstance(ctx, reqProto, g.callOptions(opKey)...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
-		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
+		return connector.NetworkError(target.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to deregisterInstance, request %s, "+
 				"reason is fail to send request, reqID %s, server %s", *req, reqID, conn.ConnID))
 	}
@@ -156,18 +176,33 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 			*req, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), conn.ConnID)
 		if serverCodeType == model.ErrCodeServerError {
 			// 当server发生了内部错误时，上报调用服务失败
-			g.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
+			target.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
 			return model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 		}
-		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 		return model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
 	}
-	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+	target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	return nil
 }
 
 // Heartbeat 心跳上报
 func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	primary, secondary := g.registryTargets()
+	g.callSecondary(connector.OpKeyInstanceHeartbeat, secondary, func(target *registryTarget) error {
+		return g.heartbeat(target, req)
+	})
+	return g.heartbeat(primary, req)
+}
+
+// heartbeat 向指定集群上报心跳
+func (g *Connector) heartbeat(target *registryTarget, req *model.InstanceHeartbeatRequest) error {
+	if !target.legacy && g.udpHeartbeater.Available() {
+		// 优先使用UDP上报，报文丢失时本次心跳再通过GRPC上报，UDP心跳只对接serverConnector配置的集群
+		if handled, err := g.udpHeartbeater.Heartbeat(req, target.token); handled {
+			return err
+		}
+	}
 	if err := g.waitDiscoverReady(); err != nil {
 		return err
 	}
@@ -175,7 +210,7 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 		opKey     = connector.OpKeyInstanceHeartbeat
 		startTime = clock.GetClock().Now()
 		// 获取心跳server连接
-		conn, err = g.connManager.GetConnection(opKey, config.HealthCheckCluster)
+		conn, err = target.connManager.GetConnection(opKey, config.HealthCheckCluster)
 	)
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeNetworkError, err, "fail to get connection, opKey %s", opKey)
@@ -185,8 +220,8 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextHeartbeatReqID()
-		ctx, cancel  = connector.CreateHeadersContext(*req.Timeout,
-			connector.AppendAuthHeader(g.token),
+		ctx, cancel  = connector.CreateHeadersContextWithParent(req.Context(), *req.Timeout,
+			connector.AppendAuthHeader(target.token),
 			connector.AppendHeaderWithReqId(reqID))
 	)
 	if cancel != nil {
@@ -198,10 +233,10 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.Heartbeat(ctx, reqProto)
+	pbResp, err := namingClient.Heartbeat(ctx, reqProto, g.callOptions(opKey)...)
 	endTime := clock.GetClock().Now()
 	if err != nil {
-		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
+		return connector.NetworkError(target.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to heartbeat, request %s, reason is fail to send request, reqID %s, server %s",
 				*req, reqID, conn.ConnID))
 	}
@@ -219,13 +254,13 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 		log.GetBaseLogger().Errorf(errMsg)
 		if serverCodeType == model.ErrCodeServerError {
 			// 当server发生内部错误时，上报调用服务失败
-			g.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
+			target.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
 			return model.NewSDKErrorWithServerInfo(model.ErrCodeServerException, nil, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), errMsg)
 		}
-		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 		return model.NewSDKErrorWithServerInfo(model.ErrCodeServerUserError, nil, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), errMsg)
 	}
-	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+	target.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	return nil
 }
 
@@ -292,7 +327,7 @@ func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportC
 		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(reqProto)
 		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
 	}
-	pbResp, err := namingClient.ReportClient(ctx, reqProto)
+	pbResp, err := namingClient.ReportClient(ctx, reqProto, g.callOptions(opKey)...)
 	endTime := g.valueCtx.Now()
 	if err != nil {
 		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
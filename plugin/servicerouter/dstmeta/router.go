@@ -197,3 +197,8 @@ func init() {
 func (g *InstancesFilter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
 	return len(routeInfo.DestService.GetMetadata()) != 0
 }
+
+// IsIndependent 未开启元数据匹配降级时只按目标元数据过滤实例，可与其他独立路由并行执行
+func (g *InstancesFilter) IsIndependent(routeInfo *servicerouter.RouteInfo) bool {
+	return !routeInfo.EnableFailOverDefaultMeta
+}
@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package graybucket
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// matchAll 通配所有命名空间
+const matchAll = "*"
+
+// Config 灰度分桶路由的配置
+type Config struct {
+	// 灰度规则，按顺序匹配被调服务
+	Rules []*BucketRule `yaml:"rules" json:"rules"`
+}
+
+// BucketRule 灰度分桶规则，主调标签值的哈希落在百分比内的请求会被路由到灰度实例
+type BucketRule struct {
+	// 被调命名空间，为空或者*代表所有命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 必选，被调服务名
+	Service string `yaml:"service" json:"service"`
+	// 必选，用于分桶的主调请求标签，例如 uid
+	Label string `yaml:"label" json:"label"`
+	// 必选，灰度流量的百分比，范围 [0, 100]，支持两位小数
+	Percent float64 `yaml:"percent" json:"percent"`
+	// 可选，哈希盐值，默认为被调服务名，修改盐值会重新划分用户群
+	Salt string `yaml:"salt" json:"salt"`
+	// 必选，灰度实例的元数据
+	Metadata map[string]string `yaml:"metadata" json:"metadata"`
+}
+
+// match 规则是否适用于被调服务
+func (r *BucketRule) match(namespace, service string) bool {
+	if r.Service != service {
+		return false
+	}
+	return len(r.Namespace) == 0 || r.Namespace == matchAll || r.Namespace == namespace
+}
+
+// getSalt 获取哈希盐值
+func (r *BucketRule) getSalt() string {
+	if len(r.Salt) > 0 {
+		return r.Salt
+	}
+	return r.Service
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	for i, rule := range c.Rules {
+		if rule == nil {
+			errs = multierror.Append(errs, fmt.Errorf("grayBucketRouter: rule %d is nil", i))
+			continue
+		}
+		if len(rule.Service) == 0 || len(rule.Label) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("grayBucketRouter: rule %d service and label are required", i))
+		}
+		if rule.Percent < 0 || rule.Percent > 100 {
+			errs = multierror.Append(errs, fmt.Errorf("grayBucketRouter: rule %d percent should be in [0, 100]", i))
+		}
+		if len(rule.Metadata) == 0 {
+			errs = multierror.Append(errs, errors.New("grayBucketRouter: gray instance metadata is required"))
+		}
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+}
@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package graybucket 按主调标签一致性分桶的灰度路由：对标签值（例如用户ID）做哈希后落入固定的百分比桶，
+// 保证所有客户端进程对同一用户的灰度判定一致，区别于按请求随机的百分比路由
+package graybucket
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&GrayBucketRouter{}, &Config{})
+}
+
+// GrayBucketRouter 按主调标签一致性分桶的灰度路由
+type GrayBucketRouter struct {
+	*plugin.PluginBase
+	valueCtx model.ValueContext
+	cfg      *Config
+}
+
+// Type 插件类型
+func (g *GrayBucketRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *GrayBucketRouter) Name() string {
+	return config.DefaultServiceRouterGrayBucket
+}
+
+// Init 初始化插件
+func (g *GrayBucketRouter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.cfg = &Config{}
+	if cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name()); cfgValue != nil {
+		g.cfg = cfgValue.(*Config)
+	}
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *GrayBucketRouter) Destroy() error {
+	return nil
+}
+
+// Enable 被调服务存在灰度规则时启用
+func (g *GrayBucketRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return g.findRule(routeInfo.DestService) != nil
+}
+
+// IsIndependent 灰度分桶只按主调标签过滤实例，可与其他独立路由并行执行
+func (g *GrayBucketRouter) IsIndependent(routeInfo *servicerouter.RouteInfo) bool {
+	return true
+}
+
+// GetFilteredInstances 命中灰度桶的请求路由到灰度实例，其余请求路由到非灰度实例，目标实例为空时不做过滤
+func (g *GrayBucketRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	rule := g.findRule(routeInfo.DestService)
+	if rule == nil {
+		result.OutputCluster = model.NewCluster(clusters, withinCluster)
+		return result, nil
+	}
+	var labelValue string
+	if routeInfo.SourceService != nil {
+		labelValue = routeInfo.SourceService.GetMetadata()[rule.Label]
+	}
+	gray := len(labelValue) > 0 && InBucket(rule.getSalt(), labelValue, rule.Percent)
+	var targetCluster *model.Cluster
+	if gray {
+		targetCluster = model.NewCluster(clusters, withinCluster)
+		for k, v := range rule.Metadata {
+			targetCluster.AddMetadata(k, v)
+		}
+		targetCluster.ReloadComposeMetaValue()
+	} else {
+		targetCluster = excludeGrayInstances(clusters, withinCluster, rule.Metadata)
+	}
+	if targetCluster.GetClusterValue().GetInstancesSet(true, true).Count() == 0 {
+		log.GetBaseLogger().Debugf("[Router][GrayBucket] %s/%s no instances for gray=%v, skip filter",
+			routeInfo.DestService.GetNamespace(), routeInfo.DestService.GetService(), gray)
+		targetCluster.PoolPut()
+		targetCluster = model.NewCluster(clusters, withinCluster)
+	}
+	result.OutputCluster = targetCluster
+	return result, nil
+}
+
+// findRule 查找被调服务的灰度规则
+func (g *GrayBucketRouter) findRule(dest model.ServiceMetadata) *BucketRule {
+	if dest == nil || g.cfg == nil {
+		return nil
+	}
+	for _, rule := range g.cfg.Rules {
+		if rule.match(dest.GetNamespace(), dest.GetService()) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// excludeGrayInstances 构建不包含灰度实例的集群
+func excludeGrayInstances(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	grayMetadata map[string]string) *model.Cluster {
+	allCluster := model.NewCluster(clusters, withinCluster)
+	defer allCluster.PoolPut()
+	all, _ := allCluster.GetAllInstances()
+	stable := make([]model.Instance, 0, len(all))
+	for _, instance := range all {
+		if !matchMetadata(instance.GetMetadata(), grayMetadata) {
+			stable = append(stable, instance)
+		}
+	}
+	svcInstances := clusters.GetServiceInstances()
+	stableClusters := model.NewServiceClusters(model.NewDefaultServiceInstancesWithRegistryValue(model.ServiceInfo{
+		Service:   svcInstances.GetService(),
+		Namespace: svcInstances.GetNamespace(),
+		Metadata:  svcInstances.GetMetadata(),
+	}, svcInstances, stable))
+	return model.NewCluster(stableClusters, withinCluster)
+}
+
+func matchMetadata(instanceMeta, expect map[string]string) bool {
+	for k, v := range expect {
+		if instanceMeta[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// InBucket 判断标签值是否落在灰度百分比内，使用 FNV-1a 哈希，保证跨进程、跨语言的结果一致
+func InBucket(salt, value string, percent float64) bool {
+	return hash.InBucket(salt, value, percent)
+}
+
+// Bucket 计算标签值所在的桶，范围 [0, 10000)
+func Bucket(salt, value string) uint32 {
+	return hash.Bucket(salt, value)
+}
@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package graybucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/test/routing"
+)
+
+// TestInBucket 测试分桶的一致性及比例
+func TestInBucket(t *testing.T) {
+	if Bucket("svc", "user-1") != Bucket("svc", "user-1") {
+		t.Fatal("bucket should be deterministic")
+	}
+	total, hit := 100000, 0
+	for i := 0; i < total; i++ {
+		if InBucket("svc", fmt.Sprintf("user-%d", i), 5) {
+			hit++
+		}
+	}
+	if hit < total*4/100 || hit > total*6/100 {
+		t.Fatalf("expect about 5%% users in bucket, got %d/%d", hit, total)
+	}
+	if InBucket("svc", "user-1", 0) || !InBucket("svc", "user-1", 100) {
+		t.Fatal("0% and 100% should be exact")
+	}
+}
+
+// TestGrayBucketRouter 使用路由一致性测试套件验证灰度分桶路由
+func TestGrayBucketRouter(t *testing.T) {
+	cfg := config.NewDefaultConfiguration(nil)
+	rule := &BucketRule{Service: "callee", Label: "uid", Percent: 50, Metadata: map[string]string{"version": "v2"}}
+	if err := cfg.GetConsumer().GetServiceRouter().SetPluginConfig(config.DefaultServiceRouterGrayBucket,
+		&Config{Rules: []*BucketRule{rule}}); err != nil {
+		t.Fatal(err)
+	}
+	router := &GrayBucketRouter{}
+	if err := router.Init(&plugin.InitContext{Config: cfg, ValueCtx: model.NewValueContext()}); err != nil {
+		t.Fatal(err)
+	}
+	var grayUser, stableUser string
+	for i := 0; len(grayUser) == 0 || len(stableUser) == 0; i++ {
+		uid := fmt.Sprintf("user-%d", i)
+		if InBucket(rule.getSalt(), uid, rule.Percent) {
+			grayUser = uid
+		} else {
+			stableUser = uid
+		}
+	}
+	instances := []json.RawMessage{
+		json.RawMessage(`{"id": "i1", "host": "127.0.0.1", "port": 8001, "metadata": {"version": "v1"}}`),
+		json.RawMessage(`{"id": "i2", "host": "127.0.0.1", "port": 8002, "metadata": {"version": "v2"}}`),
+	}
+	suite := &routing.Suite{Name: "grayBucket", Cases: []*routing.Case{
+		{
+			Name:        "gray_user",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller", Metadata: map[string]string{"uid": grayUser}},
+			Destination: &routing.Service{Namespace: "Test", Service: "callee"},
+			Instances:   instances,
+			Expected:    []string{"i2"},
+		},
+		{
+			Name:        "stable_user",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller", Metadata: map[string]string{"uid": stableUser}},
+			Destination: &routing.Service{Namespace: "Test", Service: "callee"},
+			Instances:   instances,
+			Expected:    []string{"i1"},
+		},
+		{
+			Name:        "without_label",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller"},
+			Destination: &routing.Service{Namespace: "Test", Service: "callee"},
+			Instances:   instances,
+			Expected:    []string{"i1"},
+		},
+	}}
+	routing.Run(t, router, suite)
+}
@@ -115,18 +115,21 @@ var nearbyLevels = map[string]int{
 	config.RegionLevel: priorityLevelRegion,
 	config.ZoneLevel:   priorityLevelZone,
 	config.CampusLevel: priorityLevelCampus,
+	config.RackLevel:   priorityLevelRack,
 }
 
 // Verify 校验
 func (n *nearbyConfig) Verify() error {
-	if config.RegionLevel != n.MatchLevel && config.ZoneLevel != n.MatchLevel && config.CampusLevel != n.MatchLevel {
-		return fmt.Errorf("invalud match level for nearby router: %s, it must be one of %s, %s and %s",
-			n.MatchLevel, config.RegionLevel, config.ZoneLevel, config.CampusLevel)
+	if config.RegionLevel != n.MatchLevel && config.ZoneLevel != n.MatchLevel &&
+		config.CampusLevel != n.MatchLevel && config.RackLevel != n.MatchLevel {
+		return fmt.Errorf("invalud match level for nearby router: %s, it must be one of %s, %s, %s and %s",
+			n.MatchLevel, config.RegionLevel, config.ZoneLevel, config.CampusLevel, config.RackLevel)
 	}
 	if config.RegionLevel != n.MaxMatchLevel && config.ZoneLevel != n.MaxMatchLevel &&
-		config.CampusLevel != n.MaxMatchLevel && config.AllLevel != n.MaxMatchLevel {
-		return fmt.Errorf("invalud highest match level for nearby router: %s, it must be one of %s, %s and %s",
-			n.MaxMatchLevel, config.RegionLevel, config.ZoneLevel, config.CampusLevel)
+		config.CampusLevel != n.MaxMatchLevel && config.RackLevel != n.MaxMatchLevel &&
+		config.AllLevel != n.MaxMatchLevel {
+		return fmt.Errorf("invalud highest match level for nearby router: %s, it must be one of %s, %s, %s and %s",
+			n.MaxMatchLevel, config.RegionLevel, config.ZoneLevel, config.CampusLevel, config.RackLevel)
 	}
 	if nearbyLevels[n.MaxMatchLevel] > nearbyLevels[n.MatchLevel] {
 		return fmt.Errorf("maxMatchLevel \"%s\" is less than matchLevel \"%s\"",
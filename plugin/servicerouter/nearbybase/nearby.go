@@ -89,6 +89,9 @@ const (
 	priorityLevelRegion
 	priorityLevelZone
 	priorityLevelCampus
+	priorityLevelRack
+	// nearbyLevelCount 就近匹配级别的数量
+	nearbyLevelCount
 )
 
 // Enable 当前是否需要启动该服务路由插件
@@ -114,9 +117,10 @@ func getClusterInstanceCount(c *model.Cluster, clear bool, count *nearbyLevelIns
 	count.unHealthCount = count.allCount - count.healthCount
 }
 
-// 获取从priorityLevelAll到priorityLevelCampus四个级别匹配的实例数量（全部实例和健康实例）
+// 获取从priorityLevelAll到priorityLevelCampus四个级别匹配的实例数量（全部实例和健康实例），
+// 匹配级别为机架时同时获取机架级别的实例数量
 func (g *NearbyBasedInstancesFilter) checkAllLevelInstCounts(outCluster *model.Cluster, location *model.Location,
-	allLevelsCount *[4]nearbyLevelInstanceCount) {
+	matchLevel int, allLevelsCount *[nearbyLevelCount]nearbyLevelInstanceCount) {
 	// priorityLevelAll的实例数
 	getClusterInstanceCount(outCluster, false, &allLevelsCount[priorityLevelAll])
 	// priorityLevelRegion的实例数
@@ -128,28 +132,35 @@ func (g *NearbyBasedInstancesFilter) checkAllLevelInstCounts(outCluster *model.C
 	// priorityLevelCampus的实例数
 	outCluster.Location.Campus = location.Campus
 	getClusterInstanceCount(outCluster, true, &allLevelsCount[priorityLevelCampus])
+	// priorityLevelRack的实例数
+	if matchLevel >= priorityLevelRack {
+		outCluster.Location.Rack = location.Rack
+		getClusterInstanceCount(outCluster, true, &allLevelsCount[priorityLevelRack])
+	}
 }
 
-// 进行降级检测匹配
-func (g *NearbyBasedInstancesFilter) modifyOutClusterLevel(outCluster *model.Cluster, finalLevel int) {
-	if finalLevel != priorityLevelCampus {
-		switch finalLevel {
-		case priorityLevelZone:
-			outCluster.Location.Campus = ""
-		case priorityLevelRegion:
-			outCluster.Location.Campus = ""
-			outCluster.Location.Zone = ""
-		case priorityLevelAll:
-			outCluster.Location.Campus = ""
-			outCluster.Location.Zone = ""
-			outCluster.Location.Region = ""
-		}
-		outCluster.ClearClusterValue()
+// 进行降级检测匹配，deepestLevel 为统计实例数量时匹配到的最细级别
+func (g *NearbyBasedInstancesFilter) modifyOutClusterLevel(outCluster *model.Cluster, finalLevel, deepestLevel int) {
+	if finalLevel == deepestLevel {
+		return
+	}
+	if finalLevel < priorityLevelRack {
+		outCluster.Location.Rack = ""
+	}
+	if finalLevel < priorityLevelCampus {
+		outCluster.Location.Campus = ""
 	}
+	if finalLevel < priorityLevelZone {
+		outCluster.Location.Zone = ""
+	}
+	if finalLevel < priorityLevelRegion {
+		outCluster.Location.Region = ""
+	}
+	outCluster.ClearClusterValue()
 }
 
 // 检查某个level的实例数量是否满足要求，实例数量是否大于0
-func (g *NearbyBasedInstancesFilter) checkLevelCount(allLevelsCount *[4]nearbyLevelInstanceCount,
+func (g *NearbyBasedInstancesFilter) checkLevelCount(allLevelsCount *[nearbyLevelCount]nearbyLevelInstanceCount,
 	level int) (satisfied bool, notZero bool) {
 	notZero = allLevelsCount[level].allCount > 0
 	satisfied = notZero
@@ -161,7 +172,17 @@ func (g *NearbyBasedInstancesFilter) checkLevelCount(allLevelsCount *[4]nearbyLe
 }
 
 // 将allLevelsCount转化为字符串
-func allLevelsCountToString(allLevelsCount *[4]nearbyLevelInstanceCount) string {
+func allLevelsCountToString(allLevelsCount *[nearbyLevelCount]nearbyLevelInstanceCount, matchLevel int) string {
+	if matchLevel >= priorityLevelRack {
+		return fmt.Sprintf("location matched status：[ all Level:{health: %d, unhealth: %d},"+
+			" region Level:{health: %d, unhealth: %d}, zone Level:{health: %d, unhealth: %d},"+
+			" campus Level:{health: %d, unhealth: %d}, rack Level:{health: %d, unhealth: %d} ]",
+			allLevelsCount[0].healthCount, allLevelsCount[0].unHealthCount,
+			allLevelsCount[1].healthCount, allLevelsCount[1].unHealthCount,
+			allLevelsCount[2].healthCount, allLevelsCount[2].unHealthCount,
+			allLevelsCount[3].healthCount, allLevelsCount[3].unHealthCount,
+			allLevelsCount[4].healthCount, allLevelsCount[4].unHealthCount)
+	}
 	return fmt.Sprintf("location matched status：[ all Level:{health: %d, unhealth: %d},"+
 		" region Level:{health: %d, unhealth: %d}, zone Level:{health: %d, unhealth: %d},"+
 		" campus Level:{health: %d, unhealth: %d} ]", allLevelsCount[0].healthCount, allLevelsCount[0].unHealthCount,
@@ -176,6 +197,8 @@ func checkNearbyStatus(matchLevel, finalLevel int) servicerouter.RouteStatus {
 		return servicerouter.Normal
 	}
 	switch finalLevel {
+	case priorityLevelCampus:
+		return servicerouter.DegradeToCampus
 	case priorityLevelZone:
 		return servicerouter.DegradeToCity
 	case priorityLevelRegion:
@@ -220,13 +243,17 @@ func (g *NearbyBasedInstancesFilter) GetLevel(clusters model.ServiceClusters) (i
 func (g *NearbyBasedInstancesFilter) GetFilteredInstances(rInfo *servicerouter.RouteInfo,
 	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
 	// 记录各个匹配级别的实例数量
-	var allLevelsCount [4]nearbyLevelInstanceCount
+	var allLevelsCount [nearbyLevelCount]nearbyLevelInstanceCount
 	var outCluster *model.Cluster
 	// var enableNearby bool
 	var setNearbyCluster = true
 	location := g.valueCtx.GetCurrentLocation().GetLocation()
 	var finalLevel, notZeroLevel int
 	matchLevel, maxMatchLevel := g.GetLevel(clusters)
+	deepestLevel := priorityLevelCampus
+	if matchLevel >= priorityLevelRack {
+		deepestLevel = priorityLevelRack
+	}
 
 	if len(withinCluster.ComposeMetaValue) == 0 {
 		var nearCluster *model.Cluster
@@ -252,11 +279,11 @@ func (g *NearbyBasedInstancesFilter) GetFilteredInstances(rInfo *servicerouter.R
 	// }
 
 	outCluster = model.NewCluster(clusters, withinCluster)
-	g.checkAllLevelInstCounts(outCluster, location, &allLevelsCount)
+	g.checkAllLevelInstCounts(outCluster, location, matchLevel, &allLevelsCount)
 	// 如果priorityLevelAll级别的实例数量为0，说明没有实例，直接报错
 	if allLevelsCount[priorityLevelAll].allCount == 0 {
 		outCluster.MissLocationInstances = true
-		outCluster.LocationMatchInfo = allLevelsCountToString(&allLevelsCount)
+		outCluster.LocationMatchInfo = allLevelsCountToString(&allLevelsCount, matchLevel)
 		goto finally
 	}
 	finalLevel = -1
@@ -277,12 +304,12 @@ func (g *NearbyBasedInstancesFilter) GetFilteredInstances(rInfo *servicerouter.R
 	}
 	if finalLevel < priorityLevelAll {
 		outCluster.MissLocationInstances = true
-		outCluster.LocationMatchInfo = allLevelsCountToString(&allLevelsCount)
+		outCluster.LocationMatchInfo = allLevelsCountToString(&allLevelsCount, matchLevel)
 		goto finally
 	}
 
 	// 如果进行降级，修改outcluster的地域信息以对齐最终匹配级别，否则直接使用已经匹配到的实例
-	g.modifyOutClusterLevel(outCluster, finalLevel)
+	g.modifyOutClusterLevel(outCluster, finalLevel, deepestLevel)
 
 finally:
 	if len(withinCluster.ComposeMetaValue) == 0 && setNearbyCluster {
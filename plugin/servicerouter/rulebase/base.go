@@ -20,6 +20,8 @@ package rulebase
 import (
 	"os"
 	"sort"
+	"strconv"
+	"time"
 
 	regexp "github.com/dlclark/regexp2"
 	"github.com/modern-go/reflect2"
@@ -27,6 +29,7 @@ import (
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/expr"
 	"github.com/polarismesh/polaris-go/pkg/algorithm/match"
 	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -143,14 +146,39 @@ func (g *RuleBasedInstancesFilter) matchSourceMetadata(ruleMeta map[string]*apim
 	if routeInfo.SourceService != nil {
 		srcMeta = routeInfo.SourceService.GetMetadata()
 	}
+	// 时间窗口不依赖请求标签，优先进行匹配
+	labelCount := len(ruleMeta)
+	if timeWindow, ok := ruleMeta[TimeWindowKey]; ok {
+		if !g.matchTimeWindow(timeWindow.GetValue().GetValue(), routeInfo) {
+			return false, "", nil
+		}
+		labelCount--
+	}
+	// 表达式作用于全部请求标签，不参与逐个key的匹配
+	if exprValue, ok := ruleMeta[expr.RuleLabelKey]; ok {
+		exprText := exprValue.GetValue().GetValue()
+		program, err := expr.CompileCached(exprText)
+		if err != nil {
+			return false, exprText, err
+		}
+		matched, err := program.Match(expr.NewLabelsActivation(srcMeta))
+		if err != nil {
+			log.GetBaseLogger().Debugf("[Router][RuleBase] fail to eval expression %s, err: %v", exprText, err)
+			return false, "", nil
+		}
+		if !matched {
+			return false, "", nil
+		}
+		labelCount--
+	}
 	// 如果规则metadata不为空, 待匹配规则为空, 直接返回失败
-	if len(srcMeta) == 0 {
+	if len(srcMeta) == 0 && labelCount > 0 {
 		return false, "", nil
 	}
 	// metadata是否全部匹配
 	allMetaMatched := true
 	for ruleMetaKey, ruleMetaValue := range ruleMeta {
-		if ruleMetaKey == matchAll {
+		if ruleMetaKey == matchAll || ruleMetaKey == TimeWindowKey || ruleMetaKey == expr.RuleLabelKey {
 			continue
 		}
 		if srcMetaValue, ok := srcMeta[ruleMetaKey]; ok {
@@ -194,6 +222,30 @@ func (g *RuleBasedInstancesFilter) getVariable(envKey string) (string, bool) {
 	return value, exist
 }
 
+// matchTimeWindow 判断规则的时间窗口是否生效，并将判定结果记录到routeInfo中
+func (g *RuleBasedInstancesFilter) matchTimeWindow(text string, routeInfo *servicerouter.RouteInfo) bool {
+	windows, err := g.timeWindows.load(text)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[RuleRouter] invalid time window %s: %v", text, err)
+		return false
+	}
+	now := time.Now()
+	if g.valueCtx != nil {
+		now = g.valueCtx.Now()
+	}
+	active := windows.isActive(now, g.timeWindowTolerance())
+	addRouteInfoVariable(TimeWindowKey+":"+text, strconv.FormatBool(active), routeInfo)
+	return active
+}
+
+// timeWindowTolerance 时间窗口的时钟偏差容忍度
+func (g *RuleBasedInstancesFilter) timeWindowTolerance() time.Duration {
+	if g.routerConf == nil || g.routerConf.TimeWindowTolerance == nil {
+		return DefaultTimeWindowTolerance
+	}
+	return *g.routerConf.TimeWindowTolerance
+}
+
 // 往routeInfo中添加匹配到的环境变量
 func addRouteInfoVariable(key, value string, routeInfo *servicerouter.RouteInfo) {
 	if routeInfo.EnvironmentVariables == nil {
@@ -19,7 +19,9 @@ package rulebase
 
 import (
 	"encoding/json"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/modern-go/reflect2"
@@ -48,6 +50,7 @@ type RuleBasedInstancesFilter struct {
 	prioritySubsetPool    *sync.Pool
 	systemCfg             config.SystemConfig
 	routerConf            *RuleRouterConfig
+	timeWindows           timeWindowCache
 }
 
 // Type 插件类型
@@ -237,10 +240,15 @@ func checkRouteRule(routeRule model.ServiceRule) error {
 type RuleRouterConfig struct {
 	failoverType servicerouter.FailOverType
 	FailoverType string `yaml:"failoverType"`
+	// 时间窗口的时钟偏差容忍度，窗口两端各放宽该时长
+	TimeWindowTolerance *time.Duration `yaml:"timeWindowTolerance"`
 }
 
 // Verify 校验配置是否OK
 func (rc *RuleRouterConfig) Verify() error {
+	if rc.TimeWindowTolerance != nil && *rc.TimeWindowTolerance < 0 {
+		return errors.New("ruleBasedRouter: timeWindowTolerance should not be negative")
+	}
 	return nil
 }
 
@@ -250,4 +258,8 @@ func (rc *RuleRouterConfig) SetDefault() {
 	if rc.FailoverType == "none" {
 		rc.failoverType = servicerouter.FailOverNone
 	}
+	if rc.TimeWindowTolerance == nil {
+		tolerance := DefaultTimeWindowTolerance
+		rc.TimeWindowTolerance = &tolerance
+	}
 }
@@ -0,0 +1,206 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebase
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TimeWindowKey 路由规则 source 中的保留标签，值为生效的时间窗口，仅在时间窗口内该 source 才能匹配成功，
+	// 格式为 [星期] HH:MM-HH:MM [时区]，多个窗口使用分号分隔，例如 "Mon-Fri 22:00-06:00 Asia/Shanghai; Sat,Sun 00:00-24:00"
+	TimeWindowKey = "$time_window"
+	// DefaultTimeWindowTolerance 时间窗口默认的时钟偏差容忍度
+	DefaultTimeWindowTolerance = 30 * time.Second
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// timeWindow 单个时间窗口
+type timeWindow struct {
+	// 生效的星期，为空代表每天生效
+	days map[time.Weekday]bool
+	// 开始时间，距离零点的偏移
+	start time.Duration
+	// 持续时长，结束时间小于开始时间代表跨天
+	duration time.Duration
+	location *time.Location
+}
+
+// timeWindows 时间窗口集合，任意一个窗口生效即为生效
+type timeWindows []*timeWindow
+
+// isActive 判断时间窗口是否生效，窗口两端各放宽 tolerance，保证时钟存在偏差的客户端在窗口内都能生效
+func (tws timeWindows) isActive(now time.Time, tolerance time.Duration) bool {
+	for _, tw := range tws {
+		if tw.isActive(now, tolerance) {
+			return true
+		}
+	}
+	return false
+}
+
+func (tw *timeWindow) isActive(now time.Time, tolerance time.Duration) bool {
+	local := now.In(tw.location)
+	year, month, day := local.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, tw.location)
+	// 跨天的窗口可能从前一天开始，放宽后的窗口也可能从后一天开始
+	for _, offset := range []int{-1, 0, 1} {
+		dayStart := today.AddDate(0, 0, offset)
+		if len(tw.days) > 0 && !tw.days[dayStart.Weekday()] {
+			continue
+		}
+		start := dayStart.Add(tw.start)
+		end := start.Add(tw.duration)
+		if !local.Before(start.Add(-tolerance)) && local.Before(end.Add(tolerance)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeWindows 解析时间窗口表达式
+func parseTimeWindows(text string) (timeWindows, error) {
+	var windows timeWindows
+	for _, item := range strings.Split(text, ";") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		window, err := parseTimeWindow(item)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("time window %q is empty", text)
+	}
+	return windows, nil
+}
+
+func parseTimeWindow(text string) (*timeWindow, error) {
+	fields := strings.Fields(text)
+	rangeIdx := -1
+	for i, field := range fields {
+		if strings.Contains(field, ":") {
+			rangeIdx = i
+			break
+		}
+	}
+	if rangeIdx < 0 || rangeIdx > 1 || len(fields)-rangeIdx > 2 {
+		return nil, fmt.Errorf("time window %q should be [days] HH:MM-HH:MM [timezone]", text)
+	}
+	window := &timeWindow{location: time.Local}
+	if rangeIdx == 1 {
+		days, err := parseWeekdays(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		window.days = days
+	}
+	bounds := strings.Split(fields[rangeIdx], "-")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("time range %q should be HH:MM-HH:MM", fields[rangeIdx])
+	}
+	start, err := parseClock(bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClock(bounds[1])
+	if err != nil {
+		return nil, err
+	}
+	window.start = start
+	window.duration = end - start
+	if window.duration <= 0 {
+		window.duration += 24 * time.Hour
+	}
+	if len(fields) > rangeIdx+1 {
+		if window.location, err = time.LoadLocation(fields[rangeIdx+1]); err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", fields[rangeIdx+1], err)
+		}
+	}
+	return window, nil
+}
+
+// parseWeekdays 解析星期，支持 Mon-Fri 以及 Sat,Sun 的写法
+func parseWeekdays(text string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, item := range strings.Split(text, ",") {
+		bounds := strings.Split(item, "-")
+		first, ok := weekdays[strings.ToLower(bounds[0])]
+		if !ok || len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdays[strings.ToLower(bounds[1])]; !ok {
+				return nil, fmt.Errorf("invalid weekday %q", item)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseClock 解析 HH:MM，允许 24:00 表示当天结束
+func parseClock(text string) (time.Duration, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(text, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid clock %q: %w", text, err)
+	}
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute > 0) {
+		return 0, fmt.Errorf("invalid clock %q", text)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// timeWindowCache 缓存解析后的时间窗口，避免每次路由都重新解析及加载时区
+type timeWindowCache struct {
+	windows sync.Map
+}
+
+// load 获取解析后的时间窗口
+func (c *timeWindowCache) load(text string) (timeWindows, error) {
+	if value, ok := c.windows.Load(text); ok {
+		return value.(timeWindows), nil
+	}
+	windows, err := parseTimeWindows(text)
+	if err != nil {
+		return nil, err
+	}
+	c.windows.Store(text, windows)
+	return windows, nil
+}
@@ -0,0 +1,63 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebase
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimeWindowActive 测试时间窗口的解析与判定
+func TestTimeWindowActive(t *testing.T) {
+	// 2023-06-05 为周一
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2023, 6, 5, hour, minute, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		spec      string
+		now       time.Time
+		tolerance time.Duration
+		active    bool
+	}{
+		{"09:00-18:00 UTC", monday(10, 0), 0, true},
+		{"09:00-18:00 UTC", monday(18, 0), 0, false},
+		{"09:00-18:00 UTC", monday(8, 59), 2 * time.Minute, true},
+		{"22:00-06:00 UTC", monday(23, 30), 0, true},
+		{"22:00-06:00 UTC", monday(5, 30), 0, true},
+		{"22:00-06:00 UTC", monday(12, 0), 0, false},
+		// 周日晚开始的跨天窗口，在周一凌晨仍然生效
+		{"Sun 22:00-06:00 UTC", monday(1, 0), 0, true},
+		{"Sat,Sun 00:00-24:00 UTC", monday(12, 0), 0, false},
+		{"Mon-Fri 09:00-10:00 UTC; Sat 00:00-24:00 UTC", monday(9, 30), 0, true},
+		{"Tue-Fri 09:00-10:00 UTC", monday(9, 30), 0, false},
+	}
+	for _, c := range cases {
+		windows, err := parseTimeWindows(c.spec)
+		if err != nil {
+			t.Fatalf("parse %s: %v", c.spec, err)
+		}
+		if active := windows.isActive(c.now, c.tolerance); active != c.active {
+			t.Errorf("spec %s at %v, expect %v, got %v", c.spec, c.now, c.active, active)
+		}
+	}
+	for _, spec := range []string{"", "9-18", "Foo 09:00-10:00", "09:00-25:00", "09:00-10:00 Mars/Base"} {
+		if _, err := parseTimeWindows(spec); err == nil {
+			t.Errorf("spec %q should be invalid", spec)
+		}
+	}
+}
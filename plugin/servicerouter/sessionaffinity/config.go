@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sessionaffinity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// matchAll 通配所有命名空间
+	matchAll = "*"
+	// DefaultSessionTTL 会话的默认存活时间，每次命中会话后重新计时
+	DefaultSessionTTL = 30 * time.Minute
+	// DefaultMaxSessions 默认的会话表容量
+	DefaultMaxSessions = 10000
+	// DefaultSubsetSize 按哈希选择实例时，会话固定的默认实例数
+	DefaultSubsetSize = 1
+)
+
+const (
+	// MetadataLabel 服务端下发规则的服务元数据键，值为会话标签，例如 x-session-affinity
+	MetadataLabel = "internal-session-affinity-label"
+	// MetadataSubsetKey 服务端下发规则的服务元数据键，值为实例元数据键
+	MetadataSubsetKey = "internal-session-affinity-subset-key"
+	// MetadataSubsetSize 服务端下发规则的服务元数据键，值为会话固定的实例数
+	MetadataSubsetSize = "internal-session-affinity-subset-size"
+)
+
+// Config 会话亲和路由的配置
+type Config struct {
+	// 本地会话规则，被调服务未通过服务元数据下发规则时生效，按顺序匹配
+	Rules []*AffinityRule `yaml:"rules" json:"rules"`
+	// 会话存活时间，每次命中会话后重新计时
+	SessionTTL time.Duration `yaml:"sessionTTL" json:"sessionTTL"`
+	// 会话表最大容量，超出时淘汰最久未使用的会话
+	MaxSessions int `yaml:"maxSessions" json:"maxSessions"`
+}
+
+// AffinityRule 会话亲和规则，携带相同标签值的请求在会话存活期间固定路由到同一个实例子集
+type AffinityRule struct {
+	// 被调命名空间，为空或者*代表所有命名空间
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// 被调服务名，服务端下发的规则无需填写
+	Service string `yaml:"service" json:"service"`
+	// 必选，会话标签，例如 x-session-affinity
+	Label string `yaml:"label" json:"label"`
+	// 可选，实例元数据键，设置后会话固定到该元数据值与标签值相等的实例，例如标签值 blue 对应 color=blue 的实例
+	SubsetKey string `yaml:"subsetKey" json:"subsetKey"`
+	// 可选，未设置 SubsetKey 时按标签值哈希选择的实例数，默认为1
+	SubsetSize int `yaml:"subsetSize" json:"subsetSize"`
+}
+
+// match 规则是否适用于被调服务
+func (r *AffinityRule) match(namespace, service string) bool {
+	if r.Service != service {
+		return false
+	}
+	return len(r.Namespace) == 0 || r.Namespace == matchAll || r.Namespace == namespace
+}
+
+// getSubsetSize 获取会话固定的实例数
+func (r *AffinityRule) getSubsetSize() int {
+	if r.SubsetSize > 0 {
+		return r.SubsetSize
+	}
+	return DefaultSubsetSize
+}
+
+// parseServerRule 从被调服务元数据中解析服务端下发的会话规则，未下发时返回nil
+func parseServerRule(metadata map[string]string) *AffinityRule {
+	label := metadata[MetadataLabel]
+	if len(label) == 0 {
+		return nil
+	}
+	rule := &AffinityRule{
+		Label:     label,
+		SubsetKey: metadata[MetadataSubsetKey],
+	}
+	if size, err := strconv.Atoi(metadata[MetadataSubsetSize]); err == nil && size > 0 {
+		rule.SubsetSize = size
+	}
+	return rule
+}
+
+// Verify 校验配置
+func (c *Config) Verify() error {
+	var errs error
+	for i, rule := range c.Rules {
+		if rule == nil {
+			errs = multierror.Append(errs, fmt.Errorf("sessionAffinityRouter: rule %d is nil", i))
+			continue
+		}
+		if len(rule.Service) == 0 || len(rule.Label) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("sessionAffinityRouter: rule %d service and label are required", i))
+		}
+		if rule.SubsetSize < 0 {
+			errs = multierror.Append(errs, fmt.Errorf("sessionAffinityRouter: rule %d subsetSize should be positive", i))
+		}
+	}
+	if c.SessionTTL <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("sessionAffinityRouter: sessionTTL should be positive"))
+	}
+	if c.MaxSessions <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("sessionAffinityRouter: maxSessions should be positive"))
+	}
+	return errs
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if c.SessionTTL == 0 {
+		c.SessionTTL = DefaultSessionTTL
+	}
+	if c.MaxSessions == 0 {
+		c.MaxSessions = DefaultMaxSessions
+	}
+}
@@ -0,0 +1,217 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package sessionaffinity 基于请求标签的会话亲和路由：携带相同标签值（例如 x-session-affinity: blue）的请求，
+// 在会话存活期间固定路由到同一个实例子集。负载均衡只在固定的子集内进行，
+// 因此一致性哈希等负载均衡的亲和性在子集内依然有效，不会与规则驱动的亲和性冲突
+package sessionaffinity
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&SessionAffinityRouter{}, &Config{})
+}
+
+// SessionAffinityRouter 基于请求标签的会话亲和路由
+type SessionAffinityRouter struct {
+	*plugin.PluginBase
+	valueCtx model.ValueContext
+	cfg      *Config
+	sessions *sessionTable
+	// 排空中实例承接已有会话的宽限期
+	drainingGracePeriod time.Duration
+}
+
+// Type 插件类型
+func (s *SessionAffinityRouter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (s *SessionAffinityRouter) Name() string {
+	return config.DefaultServiceRouterSessionAffinity
+}
+
+// Init 初始化插件
+func (s *SessionAffinityRouter) Init(ctx *plugin.InitContext) error {
+	s.PluginBase = plugin.NewPluginBase(ctx)
+	s.valueCtx = ctx.ValueCtx
+	s.cfg = &Config{}
+	if cfgValue := ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(s.Name()); cfgValue != nil {
+		s.cfg = cfgValue.(*Config)
+	}
+	s.cfg.SetDefault()
+	s.sessions = newSessionTable(s.cfg.SessionTTL, s.cfg.MaxSessions)
+	s.drainingGracePeriod = ctx.Config.GetConsumer().GetServiceRouter().GetDrainingGracePeriod()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (s *SessionAffinityRouter) Destroy() error {
+	return nil
+}
+
+// Enable 被调服务存在会话规则时启用
+func (s *SessionAffinityRouter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return s.findRule(routeInfo.DestService, clusters) != nil
+}
+
+// GetFilteredInstances 请求携带会话标签时路由到会话固定的实例子集，未携带标签或者子集为空时不做过滤
+func (s *SessionAffinityRouter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	result := servicerouter.PoolGetRouteResult(s.valueCtx)
+	rule := s.findRule(routeInfo.DestService, clusters)
+	var labelValue string
+	if rule != nil && routeInfo.SourceService != nil {
+		labelValue = routeInfo.SourceService.GetMetadata()[rule.Label]
+	}
+	if len(labelValue) == 0 {
+		result.OutputCluster = model.NewCluster(clusters, withinCluster)
+		return result, nil
+	}
+	all, _ := model.NewCluster(clusters, withinCluster).GetAllInstances()
+	now := time.Now()
+	key := sessionKey(routeInfo.DestService, rule.Label, labelValue)
+	subset := pinnedInstances(all, s.sessions.get(key, now), now, s.drainingGracePeriod)
+	if len(subset) == 0 {
+		subset = selectSubset(all, rule, labelValue, now, s.drainingGracePeriod)
+		if len(subset) == 0 {
+			log.GetBaseLogger().Debugf("[Router][SessionAffinity] %s no instances for session %s=%s, skip filter",
+				key, rule.Label, labelValue)
+			result.OutputCluster = model.NewCluster(clusters, withinCluster)
+			return result, nil
+		}
+		s.sessions.put(key, instanceIDs(subset), now)
+	}
+	svcInstances := clusters.GetServiceInstances()
+	subsetClusters := model.NewServiceClusters(model.NewDefaultServiceInstancesWithRegistryValue(model.ServiceInfo{
+		Service:   svcInstances.GetService(),
+		Namespace: svcInstances.GetNamespace(),
+		Metadata:  svcInstances.GetMetadata(),
+	}, svcInstances, subset))
+	result.OutputCluster = model.NewCluster(subsetClusters, withinCluster)
+	return result, nil
+}
+
+// findRule 查找被调服务的会话规则，服务端通过服务元数据下发的规则优先于本地规则
+func (s *SessionAffinityRouter) findRule(dest model.ServiceMetadata, clusters model.ServiceClusters) *AffinityRule {
+	if dest == nil {
+		return nil
+	}
+	if clusters != nil && clusters.GetServiceInstances() != nil {
+		if rule := parseServerRule(clusters.GetServiceInstances().GetMetadata()); rule != nil {
+			return rule
+		}
+	}
+	if s.cfg == nil {
+		return nil
+	}
+	for _, rule := range s.cfg.Rules {
+		if rule.match(dest.GetNamespace(), dest.GetService()) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func sessionKey(dest model.ServiceMetadata, label, value string) string {
+	return strings.Join([]string{dest.GetNamespace(), dest.GetService(), label, value}, "/")
+}
+
+func isAvailable(instance model.Instance) bool {
+	return instance.IsHealthy() && !instance.IsIsolated() && instance.GetWeight() > 0
+}
+
+// pinnedInstances 会话已固定的实例中仍然可用的实例，排空中的实例在宽限期内继续承接已有会话
+func pinnedInstances(all []model.Instance, ids []string, now time.Time,
+	gracePeriod time.Duration) []model.Instance {
+	if len(ids) == 0 {
+		return nil
+	}
+	pinned := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		pinned[id] = struct{}{}
+	}
+	var subset []model.Instance
+	for _, instance := range all {
+		if _, ok := pinned[instance.GetId()]; ok && isAvailable(instance) &&
+			model.GetDrainingState(instance, now, gracePeriod) != model.Drained {
+			subset = append(subset, instance)
+		}
+	}
+	return subset
+}
+
+// selectSubset 为新会话选择实例子集：设置了 SubsetKey 时选择元数据值与标签值相等的实例，
+// 否则按标签值做最高随机权重哈希（rendezvous hashing）选择，保证不同进程对同一会话的选择一致；新会话不会选择排空中的实例
+func selectSubset(all []model.Instance, rule *AffinityRule, value string, now time.Time,
+	gracePeriod time.Duration) []model.Instance {
+	var candidates []model.Instance
+	for _, instance := range all {
+		if !isAvailable(instance) || model.GetDrainingState(instance, now, gracePeriod) != model.NotDraining {
+			continue
+		}
+		if len(rule.SubsetKey) > 0 && instance.GetMetadata()[rule.SubsetKey] != value {
+			continue
+		}
+		candidates = append(candidates, instance)
+	}
+	if len(rule.SubsetKey) > 0 {
+		return candidates
+	}
+	size := rule.getSubsetSize()
+	if len(candidates) <= size {
+		return candidates
+	}
+	scores := make(map[string]uint64, len(candidates))
+	for _, instance := range candidates {
+		scores[instance.GetId()] = score(value, instance.GetId())
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return scores[candidates[i].GetId()] > scores[candidates[j].GetId()]
+	})
+	return candidates[:size]
+}
+
+func score(value, instanceID string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(instanceID))
+	return h.Sum64()
+}
+
+func instanceIDs(instances []model.Instance) []string {
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.GetId())
+	}
+	return ids
+}
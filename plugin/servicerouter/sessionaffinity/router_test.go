@@ -0,0 +1,106 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sessionaffinity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/test/routing"
+)
+
+// TestSessionTable 测试会话表的过期续期及容量淘汰
+func TestSessionTable(t *testing.T) {
+	table := newSessionTable(time.Minute, 2)
+	now := time.Now()
+	table.put("a", []string{"i1"}, now)
+	table.put("b", []string{"i2"}, now)
+	if ids := table.get("a", now.Add(50*time.Second)); len(ids) != 1 || ids[0] != "i1" {
+		t.Fatalf("expect session a, got %v", ids)
+	}
+	// a 最近被访问，淘汰 b
+	table.put("c", []string{"i3"}, now)
+	if table.size() != 2 || table.get("b", now) != nil {
+		t.Fatal("expect least recently used session evicted")
+	}
+	// a 在访问时续期
+	if ids := table.get("a", now.Add(100*time.Second)); len(ids) != 1 {
+		t.Fatal("expect session renewed on access")
+	}
+	if ids := table.get("c", now.Add(2*time.Minute)); ids != nil {
+		t.Fatal("expect session expired")
+	}
+}
+
+// TestSessionAffinityRouter 使用路由一致性测试套件验证会话亲和路由
+func TestSessionAffinityRouter(t *testing.T) {
+	cfg := config.NewDefaultConfiguration(nil)
+	localRule := &AffinityRule{Service: "callee", Label: "session"}
+	if err := cfg.GetConsumer().GetServiceRouter().SetPluginConfig(config.DefaultServiceRouterSessionAffinity,
+		&Config{Rules: []*AffinityRule{localRule}}); err != nil {
+		t.Fatal(err)
+	}
+	router := &SessionAffinityRouter{}
+	if err := router.Init(&plugin.InitContext{Config: cfg, ValueCtx: model.NewValueContext()}); err != nil {
+		t.Fatal(err)
+	}
+	instances := []json.RawMessage{
+		json.RawMessage(`{"id": "i1", "host": "127.0.0.1", "port": 8001, "metadata": {"color": "blue"}}`),
+		json.RawMessage(`{"id": "i2", "host": "127.0.0.1", "port": 8002, "metadata": {"color": "green"}}`),
+		json.RawMessage(`{"id": "i3", "host": "127.0.0.1", "port": 8003, "metadata": {"color": "blue"}}`),
+	}
+	// 按哈希选择时固定到得分最高的实例
+	pinned := "i1"
+	for _, id := range []string{"i2", "i3"} {
+		if score("s-1", id) > score("s-1", pinned) {
+			pinned = id
+		}
+	}
+	serverRule := map[string]string{MetadataLabel: "x-session-affinity", MetadataSubsetKey: "color"}
+	suite := &routing.Suite{Name: "sessionAffinity", Cases: []*routing.Case{
+		{
+			Name:        "server_rule",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller", Metadata: map[string]string{"x-session-affinity": "blue"}},
+			Destination: &routing.Service{Namespace: "Test", Service: "other", Metadata: serverRule},
+			Instances:   instances,
+			Expected:    []string{"i1", "i3"},
+		},
+		{
+			Name:        "local_rule",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller", Metadata: map[string]string{"session": "s-1"}},
+			Destination: &routing.Service{Namespace: "Test", Service: "callee"},
+			Instances:   instances,
+			Expected:    []string{pinned},
+		},
+		{
+			Name:        "without_label",
+			Source:      &routing.Service{Namespace: "Test", Service: "caller"},
+			Destination: &routing.Service{Namespace: "Test", Service: "callee"},
+			Instances:   instances,
+			Expected:    []string{"i1", "i2", "i3"},
+		},
+	}}
+	routing.Run(t, router, suite)
+	if router.sessions.size() != 2 {
+		t.Fatalf("expect 2 sessions recorded, got %d", router.sessions.size())
+	}
+}
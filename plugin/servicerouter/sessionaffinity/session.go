@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sessionaffinity
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// session 会话固定的实例子集
+type session struct {
+	key         string
+	instanceIDs []string
+	expireTime  time.Time
+}
+
+// sessionTable 会话表，按最近使用排序，超出容量时淘汰最久未使用的会话
+type sessionTable struct {
+	mutex    sync.Mutex
+	ttl      time.Duration
+	capacity int
+	sessions map[string]*list.Element
+	lru      *list.List
+}
+
+func newSessionTable(ttl time.Duration, capacity int) *sessionTable {
+	return &sessionTable{
+		ttl:      ttl,
+		capacity: capacity,
+		sessions: make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// get 获取未过期的会话并续期，不存在或者已过期时返回nil
+func (t *sessionTable) get(key string, now time.Time) []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	elem, ok := t.sessions[key]
+	if !ok {
+		return nil
+	}
+	s := elem.Value.(*session)
+	if now.After(s.expireTime) {
+		t.lru.Remove(elem)
+		delete(t.sessions, key)
+		return nil
+	}
+	s.expireTime = now.Add(t.ttl)
+	t.lru.MoveToFront(elem)
+	return s.instanceIDs
+}
+
+// put 记录会话固定的实例子集
+func (t *sessionTable) put(key string, instanceIDs []string, now time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if elem, ok := t.sessions[key]; ok {
+		s := elem.Value.(*session)
+		s.instanceIDs = instanceIDs
+		s.expireTime = now.Add(t.ttl)
+		t.lru.MoveToFront(elem)
+		return
+	}
+	t.sessions[key] = t.lru.PushFront(&session{key: key, instanceIDs: instanceIDs, expireTime: now.Add(t.ttl)})
+	for t.lru.Len() > t.capacity {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.sessions, oldest.Value.(*session).key)
+	}
+}
+
+// size 会话数量
+func (t *sessionTable) size() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.lru.Len()
+}